@@ -17,6 +17,17 @@ func (hs *HTTPServer) AdminRotateDataEncryptionKeys(c *contextmodel.ReqContext)
 	return response.Respond(http.StatusNoContent, "")
 }
 
+// AdminGetDataKeysUsage returns the data keys with the number of payloads encrypted with each of them,
+// along with the state of the scheduled data keys rotation.
+func (hs *HTTPServer) AdminGetDataKeysUsage(c *contextmodel.ReqContext) response.Response {
+	report, err := hs.secretsRotation.Usage(c.Req.Context())
+	if err != nil {
+		return response.Error(http.StatusInternalServerError, "Failed to get data keys usage", err)
+	}
+
+	return response.JSON(http.StatusOK, report)
+}
+
 func (hs *HTTPServer) AdminReEncryptEncryptionKeys(c *contextmodel.ReqContext) response.Response {
 	if err := hs.SecretsService.ReEncryptDataKeys(c.Req.Context()); err != nil {
 		return response.Error(http.StatusInternalServerError, "Failed to re-encrypt data keys", err)
@@ -74,6 +74,7 @@ func (hs *HTTPServer) RenderToPng(c *contextmodel.ReqContext) {
 		Timezone:          queryReader.Get("tz", ""),
 		Encoding:          queryReader.Get("encoding", ""),
 		ConcurrentLimit:   hs.Cfg.RendererConcurrentRequestLimit,
+		Priority:          rendering.PriorityHigh,
 		DeviceScaleFactor: scale,
 		Headers:           headers,
 		Theme:             models.ThemeDark,
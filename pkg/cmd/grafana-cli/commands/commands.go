@@ -7,6 +7,7 @@ import (
 	"github.com/urfave/cli/v2"
 
 	"github.com/grafana/grafana/pkg/cmd/grafana-cli/commands/datamigrations"
+	"github.com/grafana/grafana/pkg/cmd/grafana-cli/commands/provisioningvalidation"
 	"github.com/grafana/grafana/pkg/cmd/grafana-cli/commands/secretsmigrations"
 	"github.com/grafana/grafana/pkg/cmd/grafana-cli/logger"
 	"github.com/grafana/grafana/pkg/cmd/grafana-cli/utils"
@@ -81,6 +82,13 @@ func runPluginCommand(command func(commandLine utils.CommandLine) error) func(co
 	}
 }
 
+// runCommand runs a command that neither manages plugins nor needs the Grafana configuration.
+func runCommand(command func(commandLine utils.CommandLine) error) func(context *cli.Context) error {
+	return func(context *cli.Context) error {
+		return command(&utils.ContextCommandLine{Context: context})
+	}
+}
+
 var pluginCommands = []*cli.Command{
 	{
 		Name:   "install",
@@ -225,6 +233,26 @@ grafana-cli user-manager conflicts ingest-file <filepath>
 	},
 }
 
+var provisioningCommands = []*cli.Command{
+	{
+		Name:      "validate",
+		Usage:     "validate <provisioning dir>",
+		ArgsUsage: "<provisioning dir>",
+		Description: "Validates the data source, dashboard, plugin and alerting provisioning files in a directory " +
+			"with the same layout as Grafana's provisioning directory, without connecting to a database. " +
+			"Returns an error if any problem is found. References to unknown data sources are reported as warnings, " +
+			"as the data sources might have been created outside of provisioning.",
+		Action: runCommand(provisioningvalidation.ValidateCommand),
+		Flags: []cli.Flag{
+			&cli.IntFlag{
+				Name:  "base-interval-seconds",
+				Usage: "The scheduler interval of unified alerting, rule group intervals must be a multiple of it",
+				Value: int(setting.SchedulerBaseInterval.Seconds()),
+			},
+		},
+	},
+}
+
 var Commands = []*cli.Command{
 	{
 		Name:        "plugins",
@@ -236,4 +264,9 @@ var Commands = []*cli.Command{
 		Usage:       "Grafana admin commands",
 		Subcommands: adminCommands,
 	},
+	{
+		Name:        "provisioning",
+		Usage:       "Provisioning commands",
+		Subcommands: provisioningCommands,
+	},
 }
@@ -0,0 +1,102 @@
+package provisioningvalidation
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/fatih/color"
+
+	"github.com/grafana/grafana/pkg/cmd/grafana-cli/logger"
+	"github.com/grafana/grafana/pkg/cmd/grafana-cli/utils"
+	"github.com/grafana/grafana/pkg/services/provisioning/alerting"
+	"github.com/grafana/grafana/pkg/services/provisioning/dashboards"
+	"github.com/grafana/grafana/pkg/services/provisioning/datasources"
+	"github.com/grafana/grafana/pkg/services/provisioning/plugins"
+	provisioningutils "github.com/grafana/grafana/pkg/services/provisioning/utils"
+)
+
+var errMissingDirectory = errors.New("missing provisioning directory argument")
+
+// Report contains the problems found in a provisioning directory.
+type Report struct {
+	Errors   []error
+	Warnings []string
+}
+
+// Validate runs the config readers of data source, dashboard, plugin and alerting provisioning
+// against dir, which is expected to have the same layout as the provisioning directory of Grafana.
+// References to data sources that are not provisioned with an explicit UID are reported as warnings,
+// as the data sources might have been created outside of provisioning.
+func Validate(ctx context.Context, dir string, baseInterval time.Duration) Report {
+	report := Report{}
+
+	dsResult := datasources.Validate(ctx, filepath.Join(dir, "datasources"))
+	report.Errors = append(report.Errors, dsResult.Errors...)
+
+	dashResult := dashboards.Validate(ctx, filepath.Join(dir, "dashboards"))
+	report.Errors = append(report.Errors, dashResult.Errors...)
+	report.Warnings = append(report.Warnings, dashResult.Warnings...)
+
+	report.Errors = append(report.Errors, plugins.Validate(ctx, filepath.Join(dir, "plugins"))...)
+
+	alertingResult := alerting.Validate(ctx, filepath.Join(dir, "alerting"), baseInterval)
+	report.Errors = append(report.Errors, alertingResult.Errors...)
+
+	report.Warnings = append(report.Warnings, checkDatasourceReferences(dashResult.DatasourceReferences, dsResult.UIDs)...)
+	report.Warnings = append(report.Warnings, checkDatasourceReferences(alertingResult.DatasourceReferences, dsResult.UIDs)...)
+
+	return report
+}
+
+func checkDatasourceReferences(refs []provisioningutils.DatasourceReference, uids map[int64]map[string]struct{}) []string {
+	var warnings []string
+	reported := map[provisioningutils.DatasourceReference]struct{}{}
+	for _, ref := range refs {
+		if _, ok := uids[ref.OrgID][ref.UID]; ok {
+			continue
+		}
+		if _, ok := reported[ref]; ok {
+			continue
+		}
+		reported[ref] = struct{}{}
+		warnings = append(warnings, fmt.Sprintf("%s: unknown data source uid %q in organization %d", ref.Location, ref.UID, ref.OrgID))
+	}
+	return warnings
+}
+
+// ValidateCommand validates the provisioning directory given as first argument and
+// returns an error if any problem was found.
+func ValidateCommand(c utils.CommandLine) error {
+	dir := c.Args().First()
+	if dir == "" {
+		return errMissingDirectory
+	}
+
+	fi, err := os.Stat(dir)
+	if err != nil {
+		return err
+	}
+	if !fi.IsDir() {
+		return fmt.Errorf("%s is not a directory", dir)
+	}
+
+	report := Validate(context.Background(), dir, time.Duration(c.Int("base-interval-seconds"))*time.Second)
+
+	for _, warning := range report.Warnings {
+		logger.Warnf("%s %s\n", color.YellowString("warning:"), warning)
+	}
+	for _, err := range report.Errors {
+		logger.Errorf("%s %s\n", color.RedString("error:"), err)
+	}
+
+	if len(report.Errors) > 0 {
+		return fmt.Errorf("found %d provisioning error(s)", len(report.Errors))
+	}
+
+	logger.Infof("%s provisioning directory %s is valid\n", color.GreenString("✔"), dir)
+	return nil
+}
@@ -0,0 +1,47 @@
+package provisioningvalidation
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidate(t *testing.T) {
+	t.Run("valid provisioning directory", func(t *testing.T) {
+		report := Validate(context.Background(), "testdata/valid", 10*time.Second)
+		require.Empty(t, report.Errors)
+		require.Empty(t, report.Warnings)
+	})
+
+	t.Run("invalid provisioning directory reports the errors of each provisioning type", func(t *testing.T) {
+		report := Validate(context.Background(), "testdata/invalid", 10*time.Second)
+
+		var messages []string
+		for _, err := range report.Errors {
+			messages = append(messages, err.Error())
+		}
+
+		expected := []string{
+			`failure to parse file logs.yaml: yaml: line 6: mapping values are not allowed in this context`,
+			`datasource.yaml config is invalid. Only one datasource per organization can be marked as default`,
+			`testdata/invalid/dashboard-files/second.json: uid "capacity" is already used by testdata/invalid/dashboard-files/first.json`,
+			`failure to parse file clock.yaml: yaml: line 4: did not find expected ',' or ']'`,
+			`app item 1 in configuration doesn't contain required field type`,
+			`rules.yaml: rule group "my_group": invalid alert rule: interval (15s) should be non-zero and divided exactly by scheduler interval: 10`,
+			`rules.yaml: rule "high_load": condition "C" does not match any refId`,
+		}
+		require.Equal(t, expected, messages)
+	})
+
+	t.Run("unknown data source uids are reported as warnings", func(t *testing.T) {
+		report := Validate(context.Background(), "testdata/invalid", 10*time.Second)
+
+		expected := []string{
+			`testdata/invalid/dashboard-files/first.json: unknown data source uid "graphite" in organization 1`,
+			`rules.yaml: rule "high_load": unknown data source uid "influx" in organization 1`,
+		}
+		require.Equal(t, expected, report.Warnings)
+	})
+}
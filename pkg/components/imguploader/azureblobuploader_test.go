@@ -15,6 +15,7 @@ func TestUploadToAzureBlob(t *testing.T) {
 
 		err := cfg.Load(setting.CommandLineArgs{
 			HomePath: "../../../",
+			Args:     []string{"cfg:paths.data=" + t.TempDir(), "cfg:paths.logs=" + t.TempDir()},
 		})
 		require.NoError(t, err)
 
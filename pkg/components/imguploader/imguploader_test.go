@@ -14,6 +14,7 @@ func TestImageUploaderFactory(t *testing.T) {
 			cfg := setting.NewCfg()
 			err := cfg.Load(setting.CommandLineArgs{
 				HomePath: "../../../",
+				Args:     []string{"cfg:paths.data=" + t.TempDir(), "cfg:paths.logs=" + t.TempDir()},
 			})
 			require.NoError(t, err)
 
@@ -87,6 +88,7 @@ func TestImageUploaderFactory(t *testing.T) {
 			cfg := setting.NewCfg()
 			err := cfg.Load(setting.CommandLineArgs{
 				HomePath: "../../../",
+				Args:     []string{"cfg:paths.data=" + t.TempDir(), "cfg:paths.logs=" + t.TempDir()},
 			})
 			require.NoError(t, err)
 
@@ -115,6 +117,7 @@ func TestImageUploaderFactory(t *testing.T) {
 			cfg := setting.NewCfg()
 			err := cfg.Load(setting.CommandLineArgs{
 				HomePath: "../../../",
+				Args:     []string{"cfg:paths.data=" + t.TempDir(), "cfg:paths.logs=" + t.TempDir()},
 			})
 			require.NoError(t, err)
 
@@ -140,6 +143,7 @@ func TestImageUploaderFactory(t *testing.T) {
 			cfg := setting.NewCfg()
 			err := cfg.Load(setting.CommandLineArgs{
 				HomePath: "../../../",
+				Args:     []string{"cfg:paths.data=" + t.TempDir(), "cfg:paths.logs=" + t.TempDir()},
 			})
 			require.NoError(t, err)
 
@@ -173,6 +177,7 @@ func TestImageUploaderFactory(t *testing.T) {
 			cfg := setting.NewCfg()
 			err := cfg.Load(setting.CommandLineArgs{
 				HomePath: "../../../",
+				Args:     []string{"cfg:paths.data=" + t.TempDir(), "cfg:paths.logs=" + t.TempDir()},
 			})
 			require.NoError(t, err)
 
@@ -14,6 +14,7 @@ func TestUploadToS3(t *testing.T) {
 		cfg := setting.NewCfg()
 		err := cfg.Load(setting.CommandLineArgs{
 			HomePath: "../../../",
+			Args:     []string{"cfg:paths.data=" + t.TempDir(), "cfg:paths.logs=" + t.TempDir()},
 		})
 		require.NoError(t, err)
 
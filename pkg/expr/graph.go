@@ -24,6 +24,8 @@ const (
 	TypeDatasourceNode
 	// TypeMLNode is a NodeType for Machine Learning queries.
 	TypeMLNode
+	// TypeInputNode is a NodeType for results provided with the request.
+	TypeInputNode
 )
 
 func (nt NodeType) String() string {
@@ -34,6 +36,8 @@ func (nt NodeType) String() string {
 		return "Datasource"
 	case TypeMLNode:
 		return "Machine Learning"
+	case TypeInputNode:
+		return "Input"
 	default:
 		return "Unknown"
 	}
@@ -245,6 +249,16 @@ func (s *Service) buildGraph(req *Request) (*simple.DirectedGraph, error) {
 
 		dp.AddNode(node)
 	}
+
+	for i, input := range req.Inputs {
+		dp.AddNode(&InputNode{
+			baseNode: baseNode{
+				id:    int64(len(req.Queries) + i),
+				refID: input.RefID,
+			},
+			results: input.Results,
+		})
+	}
 	return dp, nil
 }
 
@@ -230,6 +230,32 @@ func TestServicebuildPipeLine(t *testing.T) {
 			},
 			expectedOrder: []string{"B", "A"},
 		},
+		{
+			name: "expressions can reference inputs",
+			req: &Request{
+				Queries: []Query{
+					{
+						RefID:      "A",
+						DataSource: dataSourceModel(),
+						JSON: json.RawMessage(`{
+							"expression": "$B * $I",
+							"type": "math"
+						}`),
+					},
+					{
+						RefID: "B",
+						DataSource: &datasources.DataSource{
+							UID: "Fake",
+						},
+						TimeRange: AbsoluteTimeRange{},
+					},
+				},
+				Inputs: []Input{
+					{RefID: "I"},
+				},
+			},
+			expectedOrder: []string{"B", "I", "A"},
+		},
 	}
 	s := Service{}
 	for _, tt := range tests {
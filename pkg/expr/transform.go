@@ -8,6 +8,7 @@ import (
 
 	"github.com/grafana/grafana-plugin-sdk-go/backend"
 
+	"github.com/grafana/grafana/pkg/expr/mathexp"
 	"github.com/grafana/grafana/pkg/services/auth/identity"
 	"github.com/grafana/grafana/pkg/services/datasources"
 )
@@ -18,7 +19,16 @@ type Request struct {
 	Debug   bool
 	OrgId   int64
 	Queries []Query
-	User    identity.Requester
+	// Inputs are results provided with the request that queries and expressions can reference by RefID.
+	Inputs []Input
+	User   identity.Requester
+}
+
+// Input is a result that is provided with the request instead of being queried,
+// for example the results of another alert rule.
+type Input struct {
+	RefID   string
+	Results mathexp.Results
 }
 
 // Query is like plugins.DataSubQuery, but with a a time range, and only the UID
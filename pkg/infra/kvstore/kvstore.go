@@ -2,6 +2,8 @@ package kvstore
 
 import (
 	"context"
+	"errors"
+	"time"
 
 	"github.com/grafana/grafana/pkg/infra/db"
 	"github.com/grafana/grafana/pkg/infra/log"
@@ -12,6 +14,9 @@ const (
 	AllOrganizations = -1
 )
 
+// ErrVersionMismatch is returned when a compare-and-swap operation finds a version other than the expected one.
+var ErrVersionMismatch = errors.New("kvstore: version mismatch")
+
 func ProvideService(sqlStore db.DB) KVStore {
 	return &kvStoreSQL{
 		sqlStore: sqlStore,
@@ -26,6 +31,17 @@ type KVStore interface {
 	Del(ctx context.Context, orgId int64, namespace string, key string) error
 	Keys(ctx context.Context, orgId int64, namespace string, keyPrefix string) ([]Key, error)
 	GetAll(ctx context.Context, orgId int64, namespace string) (map[int64]map[string]string, error)
+
+	// GetEntry returns the value of a key along with its version.
+	GetEntry(ctx context.Context, orgId int64, namespace string, key string) (Entry, bool, error)
+	// GetEntries returns all the values of a namespace along with their version.
+	GetEntries(ctx context.Context, orgId int64, namespace string) ([]Entry, error)
+	// CompareAndSwap sets the value of a key only if its current version is version, or if the key
+	// doesn't exist when version is 0. The value expires after ttl, unless ttl is 0.
+	// It returns the new version, or ErrVersionMismatch.
+	CompareAndSwap(ctx context.Context, orgId int64, namespace string, key string, version int64, value string, ttl time.Duration) (int64, error)
+	// CompareAndDelete deletes a key only if its current version is version. It returns ErrVersionMismatch otherwise.
+	CompareAndDelete(ctx context.Context, orgId int64, namespace string, key string, version int64) error
 }
 
 // WithNamespace returns a kvstore wrapper with fixed orgId and namespace.
@@ -64,3 +80,24 @@ func (kv *NamespacedKVStore) Keys(ctx context.Context, keyPrefix string) ([]Key,
 func (kv *NamespacedKVStore) GetAll(ctx context.Context) (map[int64]map[string]string, error) {
 	return kv.kvStore.GetAll(ctx, kv.orgId, kv.namespace)
 }
+
+func (kv *NamespacedKVStore) GetEntry(ctx context.Context, key string) (Entry, bool, error) {
+	return kv.kvStore.GetEntry(ctx, kv.orgId, kv.namespace, key)
+}
+
+func (kv *NamespacedKVStore) GetEntries(ctx context.Context) ([]Entry, error) {
+	return kv.kvStore.GetEntries(ctx, kv.orgId, kv.namespace)
+}
+
+func (kv *NamespacedKVStore) CompareAndSwap(ctx context.Context, key string, version int64, value string, ttl time.Duration) (int64, error) {
+	return kv.kvStore.CompareAndSwap(ctx, kv.orgId, kv.namespace, key, version, value, ttl)
+}
+
+func (kv *NamespacedKVStore) CompareAndDelete(ctx context.Context, key string, version int64) error {
+	return kv.kvStore.CompareAndDelete(ctx, kv.orgId, kv.namespace, key, version)
+}
+
+// Watch notifies about the changes of the namespace, see Watch.
+func (kv *NamespacedKVStore) Watch(ctx context.Context, interval time.Duration) <-chan []Change {
+	return Watch(ctx, kv.kvStore, kv.orgId, kv.namespace, interval)
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -287,3 +288,77 @@ func TestGetItems(t *testing.T) {
 		}
 	})
 }
+
+func TestIntegrationKVStore_CompareAndSwap(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+	kv := createTestableKVStore(t)
+	ctx := context.Background()
+
+	t.Run("create, update and delete with versions", func(t *testing.T) {
+		_, err := kv.CompareAndSwap(ctx, 1, "cas", "key", 1, "v1", 0)
+		require.ErrorIs(t, err, ErrVersionMismatch, "a key that doesn't exist can only be created with version 0")
+
+		version, err := kv.CompareAndSwap(ctx, 1, "cas", "key", 0, "v1", 0)
+		require.NoError(t, err)
+		require.Equal(t, int64(1), version)
+
+		_, err = kv.CompareAndSwap(ctx, 1, "cas", "key", 0, "v1", 0)
+		require.ErrorIs(t, err, ErrVersionMismatch)
+
+		version, err = kv.CompareAndSwap(ctx, 1, "cas", "key", version, "v2", 0)
+		require.NoError(t, err)
+		require.Equal(t, int64(2), version)
+
+		entry, ok, err := kv.GetEntry(ctx, 1, "cas", "key")
+		require.NoError(t, err)
+		require.True(t, ok)
+		require.Equal(t, "v2", entry.Value)
+		require.Equal(t, int64(2), entry.Version)
+		require.True(t, entry.ExpiresAt.IsZero())
+
+		// Set bumps the version so that concurrent compare-and-swap fail
+		require.NoError(t, kv.Set(ctx, 1, "cas", "key", "v3"))
+		_, err = kv.CompareAndSwap(ctx, 1, "cas", "key", version, "v4", 0)
+		require.ErrorIs(t, err, ErrVersionMismatch)
+
+		require.ErrorIs(t, kv.CompareAndDelete(ctx, 1, "cas", "key", version), ErrVersionMismatch)
+		require.NoError(t, kv.CompareAndDelete(ctx, 1, "cas", "key", version+1))
+
+		_, ok, err = kv.Get(ctx, 1, "cas", "key")
+		require.NoError(t, err)
+		require.False(t, ok)
+	})
+
+	t.Run("expired entries are ignored and deleted", func(t *testing.T) {
+		version, err := kv.CompareAndSwap(ctx, 1, "ttl", "short", 0, "value", time.Millisecond)
+		require.NoError(t, err)
+		_, err = kv.CompareAndSwap(ctx, 1, "ttl", "long", 0, "value", time.Hour)
+		require.NoError(t, err)
+		time.Sleep(10 * time.Millisecond)
+
+		_, ok, err := kv.Get(ctx, 1, "ttl", "short")
+		require.NoError(t, err)
+		require.False(t, ok)
+
+		keys, err := kv.Keys(ctx, 1, "ttl", "")
+		require.NoError(t, err)
+		require.Equal(t, []Key{{OrgId: 1, Namespace: "ttl", Key: "long"}}, keys)
+
+		entries, err := kv.GetEntries(ctx, 1, "ttl")
+		require.NoError(t, err)
+		require.Len(t, entries, 1)
+		require.False(t, entries[0].ExpiresAt.IsZero())
+
+		// an expired entry can be created again, with a version higher than before
+		newVersion, err := kv.CompareAndSwap(ctx, 1, "ttl", "short", 0, "value", time.Millisecond)
+		require.NoError(t, err)
+		require.Greater(t, newVersion, version)
+		time.Sleep(10 * time.Millisecond)
+
+		deleted, err := DeleteExpired(ctx, kv.(*kvStoreSQL).sqlStore)
+		require.NoError(t, err)
+		require.Equal(t, int64(1), deleted)
+	})
+}
@@ -11,11 +11,19 @@ type Item struct {
 	Namespace *string
 	Key       *string
 	Value     string
+	// Version is incremented every time the value is written.
+	Version int64
+	// ExpiresAt is the Unix time in milliseconds when the item expires, 0 if it never expires.
+	ExpiresAt int64
 
 	Created time.Time
 	Updated time.Time
 }
 
+func (i *Item) expired(now time.Time) bool {
+	return i.ExpiresAt > 0 && i.ExpiresAt <= now.UnixMilli()
+}
+
 func (i *Item) TableName() string {
 	return "kv_store"
 }
@@ -29,3 +37,29 @@ type Key struct {
 func (i *Key) TableName() string {
 	return "kv_store"
 }
+
+// Entry is a value of the store along with its version.
+type Entry struct {
+	OrgId     int64
+	Namespace string
+	Key       string
+	Value     string
+	// Version is incremented every time the value is written, it starts at 1.
+	Version int64
+	// ExpiresAt is when the entry expires, zero if it never expires.
+	ExpiresAt time.Time
+}
+
+func (i *Item) entry() Entry {
+	e := Entry{
+		OrgId:     *i.OrgId,
+		Namespace: *i.Namespace,
+		Key:       *i.Key,
+		Value:     i.Value,
+		Version:   i.Version,
+	}
+	if i.ExpiresAt > 0 {
+		e.ExpiresAt = time.UnixMilli(i.ExpiresAt)
+	}
+	return e
+}
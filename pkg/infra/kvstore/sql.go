@@ -30,7 +30,7 @@ func (kv *kvStoreSQL) Get(ctx context.Context, orgId int64, namespace string, ke
 			kv.log.Debug("error getting kvstore value", "orgId", orgId, "namespace", namespace, "key", key, "err", err)
 			return err
 		}
-		if !has {
+		if !has || item.expired(time.Now()) {
 			kv.log.Debug("kvstore value not found", "orgId", orgId, "namespace", namespace, "key", key)
 			return nil
 		}
@@ -57,7 +57,7 @@ func (kv *kvStoreSQL) Set(ctx context.Context, orgId int64, namespace string, ke
 			return err
 		}
 
-		if has && item.Value == value {
+		if has && item.Value == value && item.ExpiresAt == 0 {
 			kv.log.Debug("kvstore value not changed", "orgId", orgId, "namespace", namespace, "key", key, "value", value)
 			return nil
 		}
@@ -66,7 +66,7 @@ func (kv *kvStoreSQL) Set(ctx context.Context, orgId int64, namespace string, ke
 		item.Updated = time.Now()
 
 		if has {
-			_, err = dbSession.Exec("UPDATE kv_store SET value = ?, updated = ? WHERE id = ?", item.Value, item.Updated, item.Id)
+			_, err = dbSession.Exec("UPDATE kv_store SET value = ?, updated = ?, version = version + 1, expires_at = 0 WHERE id = ?", item.Value, item.Updated, item.Id)
 			if err != nil {
 				kv.log.Debug("error updating kvstore value", "orgId", orgId, "namespace", namespace, "key", key, "value", value, "err", err)
 			} else {
@@ -76,6 +76,7 @@ func (kv *kvStoreSQL) Set(ctx context.Context, orgId int64, namespace string, ke
 		}
 
 		item.Created = item.Updated
+		item.Version = 1
 		_, err = dbSession.Insert(&item)
 		if err != nil {
 			kv.log.Debug("error inserting kvstore value", "orgId", orgId, "namespace", namespace, "key", key, "value", value, "err", err)
@@ -101,7 +102,8 @@ func (kv *kvStoreSQL) Del(ctx context.Context, orgId int64, namespace string, ke
 func (kv *kvStoreSQL) Keys(ctx context.Context, orgId int64, namespace string, keyPrefix string) ([]Key, error) {
 	var keys []Key
 	err := kv.sqlStore.WithDbSession(ctx, func(dbSession *db.Session) error {
-		query := dbSession.Where("namespace = ?", namespace).And(fmt.Sprintf("%s LIKE ?", kv.sqlStore.GetDialect().Quote("key")), keyPrefix+"%")
+		query := dbSession.Where("namespace = ?", namespace).And(fmt.Sprintf("%s LIKE ?", kv.sqlStore.GetDialect().Quote("key")), keyPrefix+"%").
+			And("(expires_at = 0 OR expires_at > ?)", time.Now().UnixMilli())
 		if orgId != AllOrganizations {
 			query.And("org_id = ?", orgId)
 		}
@@ -114,15 +116,7 @@ func (kv *kvStoreSQL) Keys(ctx context.Context, orgId int64, namespace string, k
 // organizations the constant 'kvstore.AllOrganizations' can be passed as orgId.
 // The map result is like map[orgId]map[key]value
 func (kv *kvStoreSQL) GetAll(ctx context.Context, orgId int64, namespace string) (map[int64]map[string]string, error) {
-	var results []Item
-	err := kv.sqlStore.WithDbSession(ctx, func(dbSession *db.Session) error {
-		query := dbSession.Where("namespace = ?", namespace)
-		if orgId != AllOrganizations {
-			query.And("org_id = ?", orgId)
-		}
-
-		return query.Find(&results)
-	})
+	results, err := kv.findItems(ctx, orgId, namespace)
 
 	items := map[int64]map[string]string{}
 	for _, r := range results {
@@ -134,3 +128,156 @@ func (kv *kvStoreSQL) GetAll(ctx context.Context, orgId int64, namespace string)
 
 	return items, err
 }
+
+// GetEntry gets an item from the store along with its version.
+func (kv *kvStoreSQL) GetEntry(ctx context.Context, orgId int64, namespace string, key string) (Entry, bool, error) {
+	item := Item{
+		OrgId:     &orgId,
+		Namespace: &namespace,
+		Key:       &key,
+	}
+	var itemFound bool
+
+	err := kv.sqlStore.WithDbSession(ctx, func(dbSession *db.Session) error {
+		has, err := dbSession.Get(&item)
+		if err != nil {
+			return err
+		}
+		itemFound = has && !item.expired(time.Now())
+		return nil
+	})
+	if err != nil || !itemFound {
+		return Entry{}, false, err
+	}
+
+	return item.entry(), true, nil
+}
+
+// GetEntries gets all items of a given namespace and org along with their version. To query for all
+// organizations the constant 'kvstore.AllOrganizations' can be passed as orgId.
+func (kv *kvStoreSQL) GetEntries(ctx context.Context, orgId int64, namespace string) ([]Entry, error) {
+	results, err := kv.findItems(ctx, orgId, namespace)
+	if err != nil {
+		return nil, err
+	}
+
+	entries := make([]Entry, 0, len(results))
+	for _, r := range results {
+		entries = append(entries, r.entry())
+	}
+	return entries, nil
+}
+
+// CompareAndSwap sets an item in the store only if its current version is the expected one, or if it doesn't
+// exist when the expected version is 0. The item expires after ttl, unless ttl is 0.
+// It returns the new version of the item, or ErrVersionMismatch.
+func (kv *kvStoreSQL) CompareAndSwap(ctx context.Context, orgId int64, namespace string, key string, version int64, value string, ttl time.Duration) (int64, error) {
+	var newVersion int64
+	err := kv.sqlStore.WithTransactionalDbSession(ctx, func(dbSession *db.Session) error {
+		item := Item{
+			OrgId:     &orgId,
+			Namespace: &namespace,
+			Key:       &key,
+		}
+
+		has, err := dbSession.Get(&item)
+		if err != nil {
+			return err
+		}
+
+		now := time.Now()
+		var current int64
+		if has && !item.expired(now) {
+			current = item.Version
+		}
+		if current != version {
+			return ErrVersionMismatch
+		}
+
+		var expiresAt int64
+		if ttl > 0 {
+			expiresAt = now.Add(ttl).UnixMilli()
+		}
+
+		if has {
+			// an expired item is overwritten, its version keeps increasing so that
+			// writers that saw the item before it expired can't overwrite it.
+			newVersion = item.Version + 1
+			res, err := dbSession.Exec("UPDATE kv_store SET value = ?, updated = ?, version = ?, expires_at = ? WHERE id = ? AND version = ?",
+				value, now, newVersion, expiresAt, item.Id, item.Version)
+			if err != nil {
+				return err
+			}
+			if affected, err := res.RowsAffected(); err != nil || affected != 1 {
+				return ErrVersionMismatch
+			}
+			return nil
+		}
+
+		newVersion = 1
+		item.Value = value
+		item.Version = newVersion
+		item.ExpiresAt = expiresAt
+		item.Created = now
+		item.Updated = now
+		if _, err := dbSession.Insert(&item); err != nil {
+			if kv.sqlStore.GetDialect().IsUniqueConstraintViolation(err) {
+				return ErrVersionMismatch
+			}
+			return err
+		}
+		return nil
+	})
+	if err != nil {
+		kv.log.Debug("kvstore compare and swap failed", "orgId", orgId, "namespace", namespace, "key", key, "version", version, "err", err)
+		return 0, err
+	}
+
+	kv.log.Debug("kvstore value swapped", "orgId", orgId, "namespace", namespace, "key", key, "version", newVersion)
+	return newVersion, nil
+}
+
+// CompareAndDelete deletes an item from the store only if its current version is the expected one.
+// It returns ErrVersionMismatch otherwise.
+func (kv *kvStoreSQL) CompareAndDelete(ctx context.Context, orgId int64, namespace string, key string, version int64) error {
+	return kv.sqlStore.WithDbSession(ctx, func(dbSession *db.Session) error {
+		query := fmt.Sprintf("DELETE FROM kv_store WHERE org_id=? and namespace=? and %s=? and version=? and (expires_at = 0 OR expires_at > ?)",
+			kv.sqlStore.GetDialect().Quote("key"))
+		res, err := dbSession.Exec(query, orgId, namespace, key, version, time.Now().UnixMilli())
+		if err != nil {
+			return err
+		}
+		if affected, err := res.RowsAffected(); err != nil || affected != 1 {
+			return ErrVersionMismatch
+		}
+		return nil
+	})
+}
+
+// findItems finds all the items of a namespace that haven't expired.
+func (kv *kvStoreSQL) findItems(ctx context.Context, orgId int64, namespace string) ([]Item, error) {
+	var results []Item
+	err := kv.sqlStore.WithDbSession(ctx, func(dbSession *db.Session) error {
+		query := dbSession.Where("namespace = ?", namespace).And("(expires_at = 0 OR expires_at > ?)", time.Now().UnixMilli())
+		if orgId != AllOrganizations {
+			query.And("org_id = ?", orgId)
+		}
+
+		return query.Find(&results)
+	})
+	return results, err
+}
+
+// DeleteExpired removes the expired items from the store and returns how many were removed.
+func DeleteExpired(ctx context.Context, sqlStore db.DB) (int64, error) {
+	var affected int64
+	err := sqlStore.WithDbSession(ctx, func(dbSession *db.Session) error {
+		res, err := dbSession.Exec("DELETE FROM kv_store WHERE expires_at > 0 AND expires_at <= ?", time.Now().UnixMilli())
+		if err != nil {
+			return err
+		}
+		affected, err = res.RowsAffected()
+		return err
+	})
+	return affected, err
+}
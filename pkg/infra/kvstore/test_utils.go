@@ -4,16 +4,19 @@ import (
 	"context"
 	"errors"
 	"strings"
+	"time"
 )
 
 // In memory kv store used for testing
 type FakeKVStore struct {
 	store    map[Key]string
+	versions map[Key]int64
+	expiry   map[Key]time.Time
 	delError bool
 }
 
 func NewFakeKVStore() *FakeKVStore {
-	return &FakeKVStore{store: make(map[Key]string)}
+	return &FakeKVStore{store: make(map[Key]string), versions: make(map[Key]int64), expiry: make(map[Key]time.Time)}
 }
 
 func (f *FakeKVStore) DeletionError(shouldErr bool) {
@@ -27,7 +30,10 @@ func (f *FakeKVStore) Get(ctx context.Context, orgId int64, namespace string, ke
 }
 
 func (f *FakeKVStore) Set(ctx context.Context, orgId int64, namespace string, key string, value string) error {
-	f.store[buildKey(orgId, namespace, key)] = value
+	k := buildKey(orgId, namespace, key)
+	f.store[k] = value
+	f.versions[k]++
+	delete(f.expiry, k)
 	return nil
 }
 
@@ -68,6 +74,70 @@ func (f *FakeKVStore) GetAll(ctx context.Context, orgId int64, namespace string)
 	return items, nil
 }
 
+func (f *FakeKVStore) GetEntry(ctx context.Context, orgId int64, namespace string, key string) (Entry, bool, error) {
+	k := buildKey(orgId, namespace, key)
+	value, found := f.store[k]
+	if !found || f.expired(k) {
+		return Entry{}, false, nil
+	}
+	return f.entry(k, value), true, nil
+}
+
+func (f *FakeKVStore) GetEntries(ctx context.Context, orgId int64, namespace string) ([]Entry, error) {
+	entries := make([]Entry, 0)
+	for k, value := range f.store {
+		if (orgId == AllOrganizations || k.OrgId == orgId) && k.Namespace == namespace && !f.expired(k) {
+			entries = append(entries, f.entry(k, value))
+		}
+	}
+	return entries, nil
+}
+
+func (f *FakeKVStore) CompareAndSwap(ctx context.Context, orgId int64, namespace string, key string, version int64, value string, ttl time.Duration) (int64, error) {
+	k := buildKey(orgId, namespace, key)
+	var current int64
+	if _, found := f.store[k]; found && !f.expired(k) {
+		current = f.versions[k]
+	}
+	if current != version {
+		return 0, ErrVersionMismatch
+	}
+
+	f.store[k] = value
+	f.versions[k]++
+	delete(f.expiry, k)
+	if ttl > 0 {
+		f.expiry[k] = time.Now().Add(ttl)
+	}
+	return f.versions[k], nil
+}
+
+func (f *FakeKVStore) CompareAndDelete(ctx context.Context, orgId int64, namespace string, key string, version int64) error {
+	k := buildKey(orgId, namespace, key)
+	if _, found := f.store[k]; !found || f.expired(k) || f.versions[k] != version {
+		return ErrVersionMismatch
+	}
+	delete(f.store, k)
+	delete(f.expiry, k)
+	return nil
+}
+
+func (f *FakeKVStore) expired(k Key) bool {
+	expiresAt, ok := f.expiry[k]
+	return ok && !time.Now().Before(expiresAt)
+}
+
+func (f *FakeKVStore) entry(k Key, value string) Entry {
+	return Entry{
+		OrgId:     k.OrgId,
+		Namespace: k.Namespace,
+		Key:       k.Key,
+		Value:     value,
+		Version:   f.versions[k],
+		ExpiresAt: f.expiry[k],
+	}
+}
+
 func buildKey(orgId int64, namespace string, key string) Key {
 	return Key{
 		OrgId:     orgId,
@@ -0,0 +1,81 @@
+package kvstore
+
+import (
+	"context"
+	"time"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+)
+
+var watchLogger = log.New("infra.kvstore.watch")
+
+// Change is a change of an entry observed by Watch.
+type Change struct {
+	Entry
+	// Deleted is true when the entry was deleted or has expired, Entry then holds its last known value.
+	Deleted bool
+}
+
+// Watch polls the entries of a namespace every interval and sends the changes found since the previous poll.
+// The entries existing when Watch is called are not reported. To watch all organizations the constant
+// 'kvstore.AllOrganizations' can be passed as orgId. The channel is closed when the context is done.
+// As changes are found by polling, several changes of an entry within an interval are reported as one.
+func Watch(ctx context.Context, kv KVStore, orgId int64, namespace string, interval time.Duration) <-chan []Change {
+	ch := make(chan []Change)
+
+	go func() {
+		defer close(ch)
+
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		var previous map[Key]Entry
+		for {
+			entries, err := kv.GetEntries(ctx, orgId, namespace)
+			if err != nil {
+				if ctx.Err() == nil {
+					watchLogger.Warn("Failed to poll namespace", "orgId", orgId, "namespace", namespace, "error", err)
+				}
+			} else {
+				current := make(map[Key]Entry, len(entries))
+				for _, e := range entries {
+					current[Key{OrgId: e.OrgId, Namespace: e.Namespace, Key: e.Key}] = e
+				}
+
+				if previous != nil {
+					if changes := diffEntries(previous, current); len(changes) > 0 {
+						select {
+						case ch <- changes:
+						case <-ctx.Done():
+							return
+						}
+					}
+				}
+				previous = current
+			}
+
+			select {
+			case <-ticker.C:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	return ch
+}
+
+func diffEntries(previous, current map[Key]Entry) []Change {
+	var changes []Change
+	for k, e := range current {
+		if p, ok := previous[k]; !ok || p.Version != e.Version || p.Value != e.Value {
+			changes = append(changes, Change{Entry: e})
+		}
+	}
+	for k, p := range previous {
+		if _, ok := current[k]; !ok {
+			changes = append(changes, Change{Entry: p, Deleted: true})
+		}
+	}
+	return changes
+}
@@ -0,0 +1,59 @@
+package kvstore
+
+import (
+	"context"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestIntegrationWatch(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+	kv := createTestableKVStore(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	require.NoError(t, kv.Set(ctx, 1, "watched", "existing", "value"))
+	require.NoError(t, kv.Set(ctx, 1, "watched", "deleted", "value"))
+
+	ch := Watch(ctx, kv, 1, "watched", 10*time.Millisecond)
+	// let the first poll take place, existing entries are not reported
+	time.Sleep(50 * time.Millisecond)
+
+	require.NoError(t, kv.Set(ctx, 1, "watched", "existing", "updated"))
+	require.NoError(t, kv.Set(ctx, 1, "watched", "created", "value"))
+	require.NoError(t, kv.Del(ctx, 1, "watched", "deleted"))
+	require.NoError(t, kv.Set(ctx, 1, "other", "key", "value"))
+
+	// the changes may be spread over several polls
+	var changes []Change
+	timeout := time.After(time.Second)
+	for len(changes) < 3 {
+		select {
+		case c := <-ch:
+			changes = append(changes, c...)
+		case <-timeout:
+			t.Fatalf("expected 3 changes, received %d", len(changes))
+		}
+	}
+
+	sort.Slice(changes, func(i, j int) bool { return changes[i].Key < changes[j].Key })
+	require.Len(t, changes, 3)
+	require.Equal(t, "created", changes[0].Key)
+	require.False(t, changes[0].Deleted)
+	require.Equal(t, "deleted", changes[1].Key)
+	require.True(t, changes[1].Deleted)
+	require.Equal(t, "existing", changes[2].Key)
+	require.Equal(t, "updated", changes[2].Value)
+	require.False(t, changes[2].Deleted)
+
+	cancel()
+	require.Eventually(t, func() bool {
+		_, ok := <-ch
+		return !ok
+	}, time.Second, 10*time.Millisecond)
+}
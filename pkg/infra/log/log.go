@@ -493,6 +493,11 @@ func ReadLoggingConfig(modes []string, logsPath string, cfg *ini.File) error {
 		handler.maxLevel = leveloption
 		configLoggers = append(configLoggers, handler)
 	}
+
+	// Error records are also kept in memory so that they can be included in support bundles.
+	recentErrors.resize(cfg.Section("log").Key("recent_errors_buffer_size").MustInt(defaultRecentErrorsSize))
+	configLoggers = append(configLoggers, logWithFilters{val: recentErrors, maxLevel: level.AllowError()})
+
 	if len(configLoggers) > 0 {
 		root.initialize(configLoggers)
 	}
@@ -0,0 +1,73 @@
+package log
+
+import (
+	"fmt"
+	"sync"
+)
+
+// defaultRecentErrorsSize is the number of error records kept in memory when
+// [log] recent_errors_buffer_size is not set.
+const defaultRecentErrorsSize = 100
+
+var recentErrors = newRingBufferLogger(defaultRecentErrorsSize)
+
+// RecentErrors returns the error records most recently logged by any logger,
+// oldest first. Each record is the list of key/value pairs that was logged.
+func RecentErrors() [][]any {
+	return recentErrors.entries()
+}
+
+// ringBufferLogger is a gokit logger keeping the last records in memory.
+type ringBufferLogger struct {
+	mu      sync.Mutex
+	records [][]any
+	next    int
+	full    bool
+}
+
+func newRingBufferLogger(size int) *ringBufferLogger {
+	return &ringBufferLogger{records: make([][]any, size)}
+}
+
+func (r *ringBufferLogger) Log(keyvals ...any) error {
+	// values are formatted right away so that the buffer doesn't keep
+	// references to objects that would otherwise be garbage collected.
+	record := make([]any, len(keyvals))
+	for i, v := range keyvals {
+		record[i] = fmt.Sprint(v)
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if len(r.records) == 0 {
+		return nil
+	}
+
+	r.records[r.next] = record
+	r.next = (r.next + 1) % len(r.records)
+	if r.next == 0 {
+		r.full = true
+	}
+	return nil
+}
+
+func (r *ringBufferLogger) entries() [][]any {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if !r.full {
+		return append([][]any{}, r.records[:r.next]...)
+	}
+	return append(append([][]any{}, r.records[r.next:]...), r.records[:r.next]...)
+}
+
+// resize drops all records and changes the number of records kept.
+func (r *ringBufferLogger) resize(size int) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.records = make([][]any, size)
+	r.next = 0
+	r.full = false
+}
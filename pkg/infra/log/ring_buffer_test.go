@@ -0,0 +1,40 @@
+package log
+
+import (
+	"testing"
+
+	"github.com/go-kit/log/level"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRingBufferLogger(t *testing.T) {
+	t.Run("should return records oldest first", func(t *testing.T) {
+		r := newRingBufferLogger(3)
+		require.Empty(t, r.entries())
+
+		require.NoError(t, r.Log("msg", "one"))
+		require.NoError(t, r.Log("msg", "two"))
+		require.Equal(t, [][]any{{"msg", "one"}, {"msg", "two"}}, r.entries())
+	})
+
+	t.Run("should overwrite the oldest records when full", func(t *testing.T) {
+		r := newRingBufferLogger(2)
+		require.NoError(t, r.Log("msg", "one"))
+		require.NoError(t, r.Log("msg", "two"))
+		require.NoError(t, r.Log("msg", "three"))
+		require.Equal(t, [][]any{{"msg", "two"}, {"msg", "three"}}, r.entries())
+	})
+
+	t.Run("should format values", func(t *testing.T) {
+		r := newRingBufferLogger(1)
+		require.NoError(t, r.Log(level.Key(), level.ErrorValue(), "count", 3))
+		require.Equal(t, [][]any{{"level", "error", "count", "3"}}, r.entries())
+	})
+
+	t.Run("should keep nothing when size is zero", func(t *testing.T) {
+		r := newRingBufferLogger(5)
+		r.resize(0)
+		require.NoError(t, r.Log("msg", "one"))
+		require.Empty(t, r.entries())
+	})
+}
@@ -101,6 +101,18 @@ var (
 	// MRenderingQueue is a metric gauge for image rendering queue size
 	MRenderingQueue prometheus.Gauge
 
+	// MRenderingQueueWaiting is a metric gauge for render requests waiting for a slot, labelled by priority
+	MRenderingQueueWaiting *prometheus.GaugeVec
+
+	// MRenderingQueueWaitDuration is a metric histogram for the time render requests wait for a slot
+	MRenderingQueueWaitDuration *prometheus.HistogramVec
+
+	// MRenderingQueueRejectedTotal is a metric counter for render requests rejected by the queue
+	MRenderingQueueRejectedTotal *prometheus.CounterVec
+
+	// MRenderingDeduplicatedTotal is a metric counter for render requests sharing an identical render in progress
+	MRenderingDeduplicatedTotal prometheus.Counter
+
 	// MAccessEvaluationCount is a metric gauge for total number of evaluation requests
 	MAccessEvaluationCount prometheus.Counter
 
@@ -414,6 +426,31 @@ func init() {
 		Namespace: ExporterName,
 	})
 
+	MRenderingQueueWaiting = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name:      "rendering_queue_waiting",
+		Help:      "number of render requests waiting for a slot",
+		Namespace: ExporterName,
+	}, []string{"priority"})
+
+	MRenderingQueueWaitDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Name:      "rendering_queue_wait_duration_seconds",
+		Help:      "histogram of the time render requests wait for a slot",
+		Buckets:   []float64{.01, .05, .1, .5, 1, 2.5, 5, 10, 30, 60},
+		Namespace: ExporterName,
+	}, []string{"priority"})
+
+	MRenderingQueueRejectedTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name:      "rendering_queue_rejected_total",
+		Help:      "counter for render requests rejected by the queue",
+		Namespace: ExporterName,
+	}, []string{"reason"})
+
+	MRenderingDeduplicatedTotal = prometheus.NewCounter(prometheus.CounterOpts{
+		Name:      "rendering_deduplicated_total",
+		Help:      "counter for render requests sharing an identical render in progress",
+		Namespace: ExporterName,
+	})
+
 	MDataSourceProxyReqTimer = prometheus.NewSummary(prometheus.SummaryOpts{
 		Name:       "api_dataproxy_request_all_milliseconds",
 		Help:       "summary for dataproxy request duration",
@@ -687,6 +724,10 @@ func initMetricVars() {
 		MRenderingSummary,
 		MRenderingUserLookupSummary,
 		MRenderingQueue,
+		MRenderingQueueWaiting,
+		MRenderingQueueWaitDuration,
+		MRenderingQueueRejectedTotal,
+		MRenderingDeduplicatedTotal,
 		MAccessPermissionsSummary,
 		MAccessEvaluationsSummary,
 		MAlertingActiveAlerts,
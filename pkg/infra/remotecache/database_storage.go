@@ -53,7 +53,13 @@ func (dc *databaseCache) internalRunGC() {
 }
 
 func (dc *databaseCache) Get(ctx context.Context, key string) ([]byte, error) {
+	data, _, _, err := dc.GetWithTTL(ctx, key)
+	return data, err
+}
+
+func (dc *databaseCache) GetWithTTL(ctx context.Context, key string) ([]byte, time.Duration, bool, error) {
 	cacheHit := CacheData{}
+	var ttl time.Duration
 
 	err := dc.SQLStore.WithDbSession(ctx, func(session *db.Session) error {
 		exist, err := session.Where("cache_key= ?", key).Get(&cacheHit)
@@ -75,12 +81,13 @@ func (dc *databaseCache) Get(ctx context.Context, key string) ([]byte, error) {
 				}
 				return ErrCacheItemNotFound
 			}
+			ttl = time.Duration(cacheHit.CreatedAt+cacheHit.Expires-getTime().Unix()) * time.Second
 		}
 
 		return nil
 	})
 
-	return cacheHit.Data, err
+	return cacheHit.Data, ttl, err == nil, err
 }
 
 func (dc *databaseCache) Set(ctx context.Context, key string, data []byte, expire time.Duration) error {
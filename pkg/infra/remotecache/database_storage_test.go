@@ -115,3 +115,24 @@ func TestDatabaseStorageCount(t *testing.T) {
 	require.NoError(t, errC)
 	assert.Equal(t, int64(2), n)
 }
+
+func TestDatabaseStorageGetWithTTL(t *testing.T) {
+	db := &databaseCache{
+		SQLStore: db.InitTestDB(t),
+		log:      log.New("remotecache.database"),
+	}
+	ctx := context.Background()
+
+	require.NoError(t, db.Set(ctx, "expiring", []byte("v"), time.Minute))
+	require.NoError(t, db.Set(ctx, "lasting", []byte("v"), 0))
+
+	_, ttl, ok, err := db.GetWithTTL(ctx, "expiring")
+	require.NoError(t, err)
+	require.True(t, ok)
+	require.InDelta(t, time.Minute, ttl, float64(2*time.Second))
+
+	_, ttl, ok, err = db.GetWithTTL(ctx, "lasting")
+	require.NoError(t, err)
+	require.True(t, ok)
+	require.Zero(t, ttl)
+}
@@ -0,0 +1,201 @@
+package remotecache
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+
+	"github.com/grafana/grafana/pkg/infra/db"
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/setting"
+	"github.com/grafana/grafana/pkg/util"
+)
+
+const (
+	redisInvalidation    = "redis"
+	databaseInvalidation = "database"
+	noInvalidation       = "none"
+
+	invalidationChannel = "remote_cache_invalidation"
+)
+
+// invalidator broadcasts the keys changed by an instance to the other instances sharing the remote cache.
+type invalidator interface {
+	// Invalidate tells the other instances to drop key from their local cache.
+	Invalidate(ctx context.Context, key string) error
+	// Run calls onInvalidate with the keys invalidated by the other instances until the context is cancelled.
+	Run(ctx context.Context, onInvalidate func(key string)) error
+}
+
+// newInvalidator returns the invalidator configured in opts, or nil when invalidation is disabled.
+// By default, the redis remote cache uses redis pub/sub and the other ones use the database.
+func newInvalidator(opts *setting.RemoteCacheOptions, remote CacheStorage, sqlstore db.DB) (invalidator, error) {
+	mode := opts.Invalidation
+	redisRemote, isRedis := remote.(*redisStorage)
+	if mode == "" {
+		mode = databaseInvalidation
+		if isRedis {
+			mode = redisInvalidation
+		}
+	}
+
+	source := util.GenerateShortUID()
+	switch mode {
+	case redisInvalidation:
+		if !isRedis {
+			return nil, fmt.Errorf("redis invalidation requires the redis remote cache, got %q", opts.Name)
+		}
+		return &redisInvalidator{
+			c:       redisRemote.c,
+			channel: opts.Prefix + invalidationChannel,
+			source:  source,
+			log:     log.New("remotecache.invalidation"),
+		}, nil
+	case databaseInvalidation:
+		if opts.InvalidationRetention <= opts.InvalidationPollInterval {
+			return nil, fmt.Errorf("invalidation_retention %s must be longer than invalidation_poll_interval %s",
+				opts.InvalidationRetention, opts.InvalidationPollInterval)
+		}
+		return &databaseInvalidator{
+			SQLStore:     sqlstore,
+			prefix:       opts.Prefix,
+			source:       source,
+			pollInterval: opts.InvalidationPollInterval,
+			retention:    opts.InvalidationRetention,
+			started:      getTime().Unix(),
+			log:          log.New("remotecache.invalidation"),
+		}, nil
+	case noInvalidation:
+		return nil, nil
+	default:
+		return nil, fmt.Errorf("invalid remote cache invalidation %q", mode)
+	}
+}
+
+// redisInvalidator publishes the invalidated keys on a redis pub/sub channel.
+// The messages are prefixed with the source instance so that it ignores its own.
+type redisInvalidator struct {
+	c       *redis.Client
+	channel string
+	source  string
+	log     log.Logger
+}
+
+func (i *redisInvalidator) Invalidate(ctx context.Context, key string) error {
+	return i.c.Publish(ctx, i.channel, i.source+":"+key).Err()
+}
+
+func (i *redisInvalidator) Run(ctx context.Context, onInvalidate func(key string)) error {
+	// the subscription is re-established by the client when the connection is lost
+	pubsub := i.c.Subscribe(ctx, i.channel)
+	defer func() {
+		if err := pubsub.Close(); err != nil {
+			i.log.Debug("Failed to close subscription", "error", err)
+		}
+	}()
+
+	ch := pubsub.Channel()
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case msg, ok := <-ch:
+			if !ok {
+				<-ctx.Done()
+				return ctx.Err()
+			}
+			source, key, found := strings.Cut(msg.Payload, ":")
+			if !found || source == i.source {
+				continue
+			}
+			onInvalidate(key)
+		}
+	}
+}
+
+// databaseInvalidator records the invalidated keys in the cache_invalidation table,
+// which every instance polls. The rows older than the retention are removed.
+type databaseInvalidator struct {
+	SQLStore     db.DB
+	prefix       string
+	source       string
+	pollInterval time.Duration
+	retention    time.Duration
+	started      int64
+	log          log.Logger
+}
+
+type cacheInvalidation struct {
+	Id       int64
+	CacheKey string
+	Source   string
+}
+
+func (i *databaseInvalidator) Invalidate(ctx context.Context, key string) error {
+	return i.SQLStore.WithDbSession(ctx, func(session *db.Session) error {
+		sql := `INSERT INTO cache_invalidation (cache_key,source,created_at) VALUES(?,?,?)`
+		_, err := session.Exec(sql, i.prefix+key, i.source, getTime().Unix())
+		return err
+	})
+}
+
+func (i *databaseInvalidator) Run(ctx context.Context, onInvalidate func(key string)) error {
+	pollTicker := time.NewTicker(i.pollInterval)
+	defer pollTicker.Stop()
+	pruneTicker := time.NewTicker(i.retention / 2)
+	defer pruneTicker.Stop()
+
+	var lastID int64
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-pollTicker.C:
+			lastID = i.poll(ctx, lastID, onInvalidate)
+		case <-pruneTicker.C:
+			i.prune(ctx)
+		}
+	}
+}
+
+// poll calls onInvalidate with the keys invalidated by the other instances since lastID and returns
+// the ID of the last invalidation seen. Until an invalidation is seen, the ones recorded since the
+// instance started are read. Rows committed out of order can be missed, which only leaves an item
+// stale until the local TTL.
+func (i *databaseInvalidator) poll(ctx context.Context, lastID int64, onInvalidate func(key string)) int64 {
+	var rows []cacheInvalidation
+	err := i.SQLStore.WithDbSession(ctx, func(session *db.Session) error {
+		if lastID == 0 {
+			return session.SQL(`SELECT id, cache_key, source FROM cache_invalidation WHERE created_at >= ? ORDER BY id`, i.started).Find(&rows)
+		}
+		return session.SQL(`SELECT id, cache_key, source FROM cache_invalidation WHERE id > ? ORDER BY id`, lastID).Find(&rows)
+	})
+	if err != nil {
+		if ctx.Err() == nil {
+			i.log.Warn("Failed to poll cache invalidations", "error", err)
+		}
+		return lastID
+	}
+
+	for _, row := range rows {
+		lastID = row.Id
+		if row.Source == i.source || !strings.HasPrefix(row.CacheKey, i.prefix) {
+			continue
+		}
+		onInvalidate(strings.TrimPrefix(row.CacheKey, i.prefix))
+	}
+	return lastID
+}
+
+func (i *databaseInvalidator) prune(ctx context.Context) {
+	err := i.SQLStore.WithDbSession(ctx, func(session *db.Session) error {
+		_, err := session.Exec(`DELETE FROM cache_invalidation WHERE created_at < ?`, getTime().Add(-i.retention).Unix())
+		return err
+	})
+	if err != nil && ctx.Err() == nil {
+		i.log.Warn("Failed to remove old cache invalidations", "error", err)
+	}
+}
@@ -0,0 +1,177 @@
+package remotecache
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"time"
+
+	lru "github.com/hashicorp/golang-lru/v2"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"golang.org/x/sync/errgroup"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/registry"
+	"github.com/grafana/grafana/pkg/setting"
+)
+
+var localCacheRequests = promauto.NewCounterVec(
+	prometheus.CounterOpts{
+		Namespace: "grafana",
+		Subsystem: "remote_cache",
+		Name:      "local_requests_total",
+		Help:      "A counter for the requests to the local tier of the remote cache, by result",
+	},
+	[]string{"result"},
+)
+
+type localCacheItem struct {
+	value   []byte
+	expires time.Time
+}
+
+// expiringCacheStorage is implemented by the cache storages that can tell when their items expire.
+type expiringCacheStorage interface {
+	// GetWithTTL returns the value of key and how long it remains in the cache, 0 if it never expires.
+	// ok is false if the storage can't tell when the item expires.
+	GetWithTTL(ctx context.Context, key string) (value []byte, ttl time.Duration, ok bool, err error)
+}
+
+// getWithTTL returns the value of key in cache and how long it remains in the cache, if cache can tell.
+func getWithTTL(ctx context.Context, cache CacheStorage, key string) ([]byte, time.Duration, bool, error) {
+	if expiring, ok := cache.(expiringCacheStorage); ok {
+		return expiring.GetWithTTL(ctx, key)
+	}
+	value, err := cache.Get(ctx, key)
+	return value, 0, false, err
+}
+
+// localCacheStorage keeps the recently used items of the remote cache in memory, so that hot keys
+// don't hit the remote cache on every Get. The keys changed by this instance are dropped from the
+// local cache of the other instances through the invalidator. Items are fetched again from the
+// remote cache after the local TTL, which bounds how long a missed invalidation leaves an item stale.
+// Items read from the remote cache are kept no longer than they remain in the remote cache, and aren't
+// kept at all when the remote cache can't tell when they expire, like memcached.
+type localCacheStorage struct {
+	remote      CacheStorage
+	items       *lru.Cache[string, localCacheItem]
+	ttl         time.Duration
+	invalidator invalidator
+	log         log.Logger
+}
+
+func newLocalCacheStorage(remote CacheStorage, opts *setting.RemoteCacheOptions, inv invalidator) (*localCacheStorage, error) {
+	if opts.LocalCacheMaxItems <= 0 {
+		return nil, fmt.Errorf("local_cache_max_items must be positive, got %d", opts.LocalCacheMaxItems)
+	}
+	if opts.LocalCacheTTL <= 0 {
+		return nil, fmt.Errorf("local_cache_ttl must be positive, got %s", opts.LocalCacheTTL)
+	}
+
+	items, err := lru.New[string, localCacheItem](opts.LocalCacheMaxItems)
+	if err != nil {
+		return nil, err
+	}
+
+	return &localCacheStorage{
+		remote:      remote,
+		items:       items,
+		ttl:         opts.LocalCacheTTL,
+		invalidator: inv,
+		log:         log.New("remotecache.local"),
+	}, nil
+}
+
+func (s *localCacheStorage) Get(ctx context.Context, key string) ([]byte, error) {
+	if item, ok := s.items.Get(key); ok {
+		if getTime().Before(item.expires) {
+			localCacheRequests.WithLabelValues("hit").Inc()
+			return bytes.Clone(item.value), nil
+		}
+		s.items.Remove(key)
+	}
+	localCacheRequests.WithLabelValues("miss").Inc()
+
+	value, remoteTTL, ok, err := getWithTTL(ctx, s.remote, key)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return value, nil
+	}
+
+	ttl := s.ttl
+	if remoteTTL > 0 && remoteTTL < ttl {
+		ttl = remoteTTL
+	}
+	s.add(key, value, ttl)
+	return value, nil
+}
+
+func (s *localCacheStorage) Set(ctx context.Context, key string, value []byte, expire time.Duration) error {
+	if err := s.remote.Set(ctx, key, value, expire); err != nil {
+		s.items.Remove(key)
+		return err
+	}
+
+	ttl := s.ttl
+	if expire > 0 && expire < ttl {
+		ttl = expire
+	}
+	s.add(key, value, ttl)
+	s.invalidate(ctx, key)
+	return nil
+}
+
+func (s *localCacheStorage) Delete(ctx context.Context, key string) error {
+	s.items.Remove(key)
+	if err := s.remote.Delete(ctx, key); err != nil {
+		return err
+	}
+
+	s.invalidate(ctx, key)
+	return nil
+}
+
+func (s *localCacheStorage) Count(ctx context.Context, prefix string) (int64, error) {
+	return s.remote.Count(ctx, prefix)
+}
+
+// Run runs the background processes of the remote cache and listens to the
+// invalidations of the other instances until the context is cancelled.
+func (s *localCacheStorage) Run(ctx context.Context) error {
+	g, ctx := errgroup.WithContext(ctx)
+
+	if backgroundjob, ok := s.remote.(registry.BackgroundService); ok {
+		g.Go(func() error { return backgroundjob.Run(ctx) })
+	}
+	if s.invalidator != nil {
+		g.Go(func() error {
+			return s.invalidator.Run(ctx, func(key string) {
+				s.items.Remove(key)
+			})
+		})
+	}
+	g.Go(func() error {
+		<-ctx.Done()
+		return ctx.Err()
+	})
+
+	return g.Wait()
+}
+
+func (s *localCacheStorage) add(key string, value []byte, ttl time.Duration) {
+	s.items.Add(key, localCacheItem{value: bytes.Clone(value), expires: getTime().Add(ttl)})
+}
+
+// invalidate tells the other instances to drop key from their local cache. The change is already
+// stored in the remote cache, a failure only leaves the other instances stale until the local TTL.
+func (s *localCacheStorage) invalidate(ctx context.Context, key string) {
+	if s.invalidator == nil {
+		return
+	}
+	if err := s.invalidator.Invalidate(ctx, key); err != nil {
+		s.log.Warn("Failed to broadcast cache invalidation", "key", key, "error", err)
+	}
+}
@@ -0,0 +1,159 @@
+package remotecache
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/infra/db"
+	"github.com/grafana/grafana/pkg/setting"
+)
+
+func TestLocalCacheStorage(t *testing.T) {
+	ctx := context.Background()
+	remote := NewFakeCacheStorage()
+	local, err := newLocalCacheStorage(remote, &setting.RemoteCacheOptions{LocalCacheMaxItems: 2, LocalCacheTTL: time.Minute}, nil)
+	require.NoError(t, err)
+
+	require.NoError(t, local.Set(ctx, "foo", []byte("bar"), time.Hour))
+	require.Equal(t, "bar", string(remote.Storage["foo"]))
+
+	// the value is served from the local cache
+	remote.Storage["foo"] = []byte("changed")
+	v, err := local.Get(ctx, "foo")
+	require.NoError(t, err)
+	require.Equal(t, "bar", string(v))
+
+	// until the local TTL expires
+	getTime = func() time.Time { return time.Now().Add(2 * time.Minute) }
+	v, err = local.Get(ctx, "foo")
+	getTime = time.Now
+	require.NoError(t, err)
+	require.Equal(t, "changed", string(v))
+
+	// the least recently used keys are evicted
+	require.NoError(t, local.Set(ctx, "a", []byte("a"), time.Hour))
+	require.NoError(t, local.Set(ctx, "b", []byte("b"), time.Hour))
+	require.False(t, local.items.Contains("foo"))
+
+	require.NoError(t, local.Delete(ctx, "a"))
+	_, err = local.Get(ctx, "a")
+	require.ErrorIs(t, err, ErrCacheItemNotFound)
+}
+
+func TestLocalCacheStorageRemoteExpiry(t *testing.T) {
+	ctx := context.Background()
+	opts := &setting.RemoteCacheOptions{LocalCacheMaxItems: 10, LocalCacheTTL: time.Hour}
+
+	t.Run("items are kept no longer than they remain in the remote cache", func(t *testing.T) {
+		mr := miniredis.RunT(t)
+		remote, err := newRedisStorage(&setting.RemoteCacheOptions{ConnStr: "addr=" + mr.Addr()})
+		require.NoError(t, err)
+		local, err := newLocalCacheStorage(remote, opts, nil)
+		require.NoError(t, err)
+
+		require.NoError(t, remote.Set(ctx, "expiring", []byte("v"), time.Minute))
+		require.NoError(t, remote.Set(ctx, "lasting", []byte("v"), 0))
+		for _, key := range []string{"expiring", "lasting"} {
+			_, err := local.Get(ctx, key)
+			require.NoError(t, err)
+		}
+
+		expiring, ok := local.items.Get("expiring")
+		require.True(t, ok)
+		require.WithinDuration(t, time.Now().Add(time.Minute), expiring.expires, 5*time.Second)
+		lasting, ok := local.items.Get("lasting")
+		require.True(t, ok)
+		require.WithinDuration(t, time.Now().Add(time.Hour), lasting.expires, 5*time.Second)
+	})
+
+	t.Run("items are not kept when the remote cache can't tell when they expire", func(t *testing.T) {
+		remote := NewFakeCacheStorage()
+		local, err := newLocalCacheStorage(remote, opts, nil)
+		require.NoError(t, err)
+
+		remote.Storage["foo"] = []byte("bar")
+		v, err := local.Get(ctx, "foo")
+		require.NoError(t, err)
+		require.Equal(t, "bar", string(v))
+		require.False(t, local.items.Contains("foo"))
+	})
+}
+
+func TestLocalCacheRedisInvalidation(t *testing.T) {
+	mr := miniredis.RunT(t)
+	opts := &setting.RemoteCacheOptions{
+		Name:               redisCacheType,
+		ConnStr:            "addr=" + mr.Addr(),
+		LocalCacheEnabled:  true,
+		LocalCacheMaxItems: 10,
+		LocalCacheTTL:      time.Hour,
+	}
+	first, second := runLocalCacheClient(t, opts, nil), runLocalCacheClient(t, opts, nil)
+	testLocalCacheInvalidation(t, first, second)
+}
+
+func TestIntegrationLocalCacheDatabaseInvalidation(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+
+	sqlStore := db.InitTestDB(t)
+	opts := &setting.RemoteCacheOptions{
+		Name:                     databaseCacheType,
+		LocalCacheEnabled:        true,
+		LocalCacheMaxItems:       10,
+		LocalCacheTTL:            time.Hour,
+		InvalidationPollInterval: 10 * time.Millisecond,
+		InvalidationRetention:    time.Minute,
+	}
+	first, second := runLocalCacheClient(t, opts, sqlStore), runLocalCacheClient(t, opts, sqlStore)
+	testLocalCacheInvalidation(t, first, second)
+}
+
+func runLocalCacheClient(t *testing.T, opts *setting.RemoteCacheOptions, sqlStore db.DB) *localCacheStorage {
+	t.Helper()
+
+	client, err := createClient(opts, sqlStore, nil)
+	require.NoError(t, err)
+	local, ok := client.(*localCacheStorage)
+	require.True(t, ok)
+	require.NotNil(t, local.invalidator)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		_ = local.Run(ctx)
+		close(done)
+	}()
+	t.Cleanup(func() {
+		cancel()
+		<-done
+	})
+	return local
+}
+
+func testLocalCacheInvalidation(t *testing.T, first, second *localCacheStorage) {
+	t.Helper()
+	ctx := context.Background()
+
+	require.NoError(t, first.Set(ctx, "key", []byte("v1"), time.Hour))
+	v, err := second.Get(ctx, "key")
+	require.NoError(t, err)
+	require.Equal(t, "v1", string(v))
+	require.True(t, second.items.Contains("key"))
+
+	require.NoError(t, first.Set(ctx, "key", []byte("v2"), time.Hour))
+	require.Eventually(t, func() bool {
+		v, err := second.Get(ctx, "key")
+		return err == nil && string(v) == "v2"
+	}, 5*time.Second, 10*time.Millisecond)
+
+	require.NoError(t, second.Delete(ctx, "key"))
+	require.Eventually(t, func() bool {
+		return !first.items.Contains("key")
+	}, 5*time.Second, 10*time.Millisecond)
+}
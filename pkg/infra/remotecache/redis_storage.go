@@ -96,6 +96,27 @@ func (s *redisStorage) Get(ctx context.Context, key string) ([]byte, error) {
 	return s.c.Get(ctx, key).Bytes()
 }
 
+// GetWithTTL returns the value as byte array and how long it remains in the cache
+func (s *redisStorage) GetWithTTL(ctx context.Context, key string) ([]byte, time.Duration, bool, error) {
+	pipe := s.c.Pipeline()
+	get := pipe.Get(ctx, key)
+	pttl := pipe.PTTL(ctx, key)
+	if _, err := pipe.Exec(ctx); err != nil {
+		return nil, 0, false, err
+	}
+
+	value, err := get.Bytes()
+	if err != nil {
+		return nil, 0, false, err
+	}
+	// a negative TTL means that the key has no expiry, or that it expired since it was read
+	ttl := pttl.Val()
+	if ttl < 0 {
+		return value, 0, ttl == -1, nil
+	}
+	return value, ttl, true, nil
+}
+
 // Delete delete a key from session.
 func (s *redisStorage) Delete(ctx context.Context, key string) error {
 	cmd := s.c.Del(ctx, key)
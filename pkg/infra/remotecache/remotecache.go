@@ -53,6 +53,13 @@ func (ds *RemoteCache) getUsageStats(ctx context.Context) (map[string]any, error
 
 	stats["stats.remote_cache.encrypt_enabled.count"] = encryptVal
 
+	localCacheVal := 0
+	if ds.Cfg.RemoteCacheOptions.LocalCacheEnabled {
+		localCacheVal = 1
+	}
+
+	stats["stats.remote_cache.local_cache_enabled.count"] = localCacheVal
+
 	return stats, nil
 }
 
@@ -133,6 +140,7 @@ func createClient(opts *setting.RemoteCacheOptions, sqlstore db.DB, secretsServi
 	if err != nil {
 		return cache, err
 	}
+	remote := cache
 	if opts.Prefix != "" {
 		cache = &prefixCacheStorage{cache: cache, prefix: opts.Prefix}
 	}
@@ -140,6 +148,19 @@ func createClient(opts *setting.RemoteCacheOptions, sqlstore db.DB, secretsServi
 	if opts.Encryption {
 		cache = &encryptedCacheStorage{cache: cache, secretsService: secretsService}
 	}
+
+	// the local cache holds decrypted values so that hits skip the decryption as well
+	if opts.LocalCacheEnabled {
+		inv, err := newInvalidator(opts, remote, sqlstore)
+		if err != nil {
+			return nil, err
+		}
+		local, err := newLocalCacheStorage(cache, opts, inv)
+		if err != nil {
+			return nil, err
+		}
+		return local, nil
+	}
 	return cache, nil
 }
 
@@ -161,6 +182,17 @@ func (pcs *encryptedCacheStorage) Get(ctx context.Context, key string) ([]byte,
 
 	return pcs.secretsService.Decrypt(ctx, data)
 }
+
+func (pcs *encryptedCacheStorage) GetWithTTL(ctx context.Context, key string) ([]byte, time.Duration, bool, error) {
+	data, ttl, ok, err := getWithTTL(ctx, pcs.cache, key)
+	if err != nil {
+		return nil, 0, false, err
+	}
+
+	decrypted, err := pcs.secretsService.Decrypt(ctx, data)
+	return decrypted, ttl, ok, err
+}
+
 func (pcs *encryptedCacheStorage) Set(ctx context.Context, key string, value []byte, expire time.Duration) error {
 	encrypted, err := pcs.secretsService.Encrypt(ctx, value, secrets.WithoutScope())
 	if err != nil {
@@ -185,6 +217,9 @@ type prefixCacheStorage struct {
 func (pcs *prefixCacheStorage) Get(ctx context.Context, key string) ([]byte, error) {
 	return pcs.cache.Get(ctx, pcs.prefix+key)
 }
+func (pcs *prefixCacheStorage) GetWithTTL(ctx context.Context, key string) ([]byte, time.Duration, bool, error) {
+	return getWithTTL(ctx, pcs.cache, pcs.prefix+key)
+}
 func (pcs *prefixCacheStorage) Set(ctx context.Context, key string, value []byte, expire time.Duration) error {
 	return pcs.cache.Set(ctx, pcs.prefix+key, value, expire)
 }
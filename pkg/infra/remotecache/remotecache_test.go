@@ -31,6 +31,7 @@ func TestCachedBasedOnConfig(t *testing.T) {
 	cfg := setting.NewCfg()
 	err := cfg.Load(setting.CommandLineArgs{
 		HomePath: "../../../",
+		Args:     []string{"cfg:paths.data=" + t.TempDir(), "cfg:paths.logs=" + t.TempDir()},
 	})
 	require.Nil(t, err, "Failed to load config")
 
@@ -116,8 +117,9 @@ func canNotFetchExpiredItems(t *testing.T, client CacheStorage) {
 
 func TestCollectUsageStats(t *testing.T) {
 	wantMap := map[string]any{
-		"stats.remote_cache.redis.count":           1,
-		"stats.remote_cache.encrypt_enabled.count": 1,
+		"stats.remote_cache.redis.count":               1,
+		"stats.remote_cache.encrypt_enabled.count":     1,
+		"stats.remote_cache.local_cache_enabled.count": 0,
 	}
 	cfg := setting.NewCfg()
 	cfg.RemoteCacheOptions = &setting.RemoteCacheOptions{Name: redisCacheType, Encryption: true}
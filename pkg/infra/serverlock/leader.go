@@ -0,0 +1,267 @@
+package serverlock
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/util"
+)
+
+const (
+	DefaultLeaseDuration = 30 * time.Second
+	DefaultRenewInterval = 10 * time.Second
+)
+
+// LeaderElectionConfig configures a LeaderElection.
+type LeaderElectionConfig struct {
+	// LeaseDuration is how long the leader keeps the leadership without renewing its lease.
+	// Other instances take over when the lease has not been renewed for this long.
+	LeaseDuration time.Duration
+	// RenewInterval is how often the leader renews its lease and how often the
+	// other instances check whether they can take over. It must be shorter than LeaseDuration.
+	RenewInterval time.Duration
+
+	// OnStartedLeading is called in its own goroutine when this instance becomes the leader.
+	// The context is cancelled when the leadership is lost, the function is expected to return then.
+	// When it returns while this instance is still the leader, the instance resigns.
+	OnStartedLeading func(ctx context.Context)
+	// OnStoppedLeading is called when this instance stops being the leader, after OnStartedLeading returned.
+	OnStoppedLeading func()
+	// OnNewLeader is called when the leader observed by this instance changes, including when
+	// this instance becomes the leader. The identity is empty when there is no leader.
+	OnNewLeader func(identity string)
+}
+
+// LeaderElection elects a single leader among the Grafana instances sharing the database, using
+// a lease stored in the server_lock table. The leader renews its lease every RenewInterval,
+// and any other instance takes over when the lease expires.
+type LeaderElection struct {
+	sl         *ServerLockService
+	actionName string
+	identity   string
+	cfg        LeaderElectionConfig
+	log        log.Logger
+
+	// electionMtx serializes the changes of leadership
+	electionMtx sync.Mutex
+
+	mtx         sync.Mutex
+	leader      string
+	stopLeading context.CancelFunc
+	leadingDone chan struct{}
+	resigned    time.Time
+}
+
+// NewLeaderElection creates a leader election for actionName. The identity of this instance
+// is derived from the host name and is unique to each LeaderElection.
+func (sl *ServerLockService) NewLeaderElection(actionName string, cfg LeaderElectionConfig) (*LeaderElection, error) {
+	if cfg.LeaseDuration == 0 {
+		cfg.LeaseDuration = DefaultLeaseDuration
+	}
+	if cfg.RenewInterval == 0 {
+		cfg.RenewInterval = DefaultRenewInterval
+	}
+	if cfg.RenewInterval >= cfg.LeaseDuration {
+		return nil, fmt.Errorf("renew interval %s must be shorter than lease duration %s", cfg.RenewInterval, cfg.LeaseDuration)
+	}
+	// leases are stored with a precision of a second
+	if cfg.LeaseDuration < 2*time.Second {
+		return nil, fmt.Errorf("lease duration %s must be at least 2s", cfg.LeaseDuration)
+	}
+
+	hostname, err := os.Hostname()
+	if err != nil {
+		hostname = "unknown"
+	}
+
+	return &LeaderElection{
+		sl:         sl,
+		actionName: actionName,
+		identity:   hostname + "-" + util.GenerateShortUID(),
+		cfg:        cfg,
+		log:        sl.log.New("actionName", actionName),
+	}, nil
+}
+
+// Identity returns the identity of this instance in the election.
+func (le *LeaderElection) Identity() string {
+	return le.identity
+}
+
+// IsLeader returns true if this instance is the leader.
+func (le *LeaderElection) IsLeader() bool {
+	le.mtx.Lock()
+	defer le.mtx.Unlock()
+	return le.leader == le.identity
+}
+
+// Leader returns the identity of the leader as last observed by this instance.
+func (le *LeaderElection) Leader() string {
+	le.mtx.Lock()
+	defer le.mtx.Unlock()
+	return le.leader
+}
+
+// Run takes part in the election until the context is cancelled. When this instance is the leader
+// at that moment, it stops leading and releases the lease so that another instance takes over
+// without waiting for the lease to expire.
+func (le *LeaderElection) Run(ctx context.Context) error {
+	ticker := time.NewTicker(le.cfg.RenewInterval)
+	defer ticker.Stop()
+
+	for {
+		le.tick(ctx)
+
+		select {
+		case <-ticker.C:
+		case <-ctx.Done():
+			// the lease is released with a fresh context as ctx is already cancelled
+			releaseCtx, cancel := context.WithTimeout(context.Background(), le.cfg.RenewInterval)
+			defer cancel()
+			if err := le.Resign(releaseCtx); err != nil {
+				le.log.Warn("Failed to release lease", "error", err)
+			}
+			return ctx.Err()
+		}
+	}
+}
+
+// Resign stops leading and releases the lease, handing the leadership over to another instance.
+// It waits for OnStartedLeading to return, it must therefore not be called from it.
+// This instance doesn't try to become the leader again for a lease duration.
+func (le *LeaderElection) Resign(ctx context.Context) error {
+	le.electionMtx.Lock()
+	defer le.electionMtx.Unlock()
+
+	le.mtx.Lock()
+	wasLeader := le.leader == le.identity
+	if wasLeader {
+		le.resigned = time.Now()
+	}
+	le.mtx.Unlock()
+
+	if !wasLeader {
+		return nil
+	}
+
+	le.setLeader("")
+	return le.sl.ReleaseLease(ctx, le.actionName, le.identity)
+}
+
+func (le *LeaderElection) tick(ctx context.Context) {
+	le.electionMtx.Lock()
+	defer le.electionMtx.Unlock()
+
+	ctx, cancel := context.WithTimeout(ctx, le.cfg.RenewInterval)
+	defer cancel()
+
+	le.mtx.Lock()
+	backingOff := time.Since(le.resigned) < le.cfg.LeaseDuration
+	le.mtx.Unlock()
+
+	if backingOff {
+		le.observe(ctx)
+		return
+	}
+
+	isLeader, leader, err := le.sl.TryAcquireLease(ctx, le.actionName, le.identity, le.cfg.LeaseDuration)
+	if err != nil {
+		if errors.Is(err, context.Canceled) {
+			return
+		}
+		le.log.Error("Failed to acquire or renew lease", "error", err)
+		// the leader can't tell whether it still holds the lease, it steps down
+		// before the lease expires so that two instances never lead at once.
+		if le.IsLeader() {
+			le.setLeader("")
+		}
+		return
+	}
+
+	if isLeader && !le.IsLeader() {
+		le.log.Info("Became the leader", "identity", le.identity)
+	} else if !isLeader && le.IsLeader() {
+		le.log.Warn("Lost leadership", "identity", le.identity, "leader", leader)
+	}
+	le.setLeader(leader)
+}
+
+// observe reads the current leader without trying to take over.
+func (le *LeaderElection) observe(ctx context.Context) {
+	leader, err := le.sl.GetLeaseHolder(ctx, le.actionName, le.cfg.LeaseDuration)
+	if err != nil {
+		le.log.Debug("Failed to read lease", "error", err)
+		return
+	}
+	le.setLeader(leader)
+}
+
+// setLeader records the observed leader and starts or stops leading accordingly.
+func (le *LeaderElection) setLeader(leader string) {
+	le.mtx.Lock()
+	previous := le.leader
+	le.leader = leader
+	le.mtx.Unlock()
+
+	if previous == leader {
+		return
+	}
+
+	if previous == le.identity {
+		le.stopLeadingAndWait()
+	}
+	if le.cfg.OnNewLeader != nil {
+		le.cfg.OnNewLeader(leader)
+	}
+	if leader == le.identity {
+		le.startLeading()
+	}
+}
+
+func (le *LeaderElection) startLeading() {
+	if le.cfg.OnStartedLeading == nil {
+		return
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+
+	le.mtx.Lock()
+	le.stopLeading = cancel
+	le.leadingDone = done
+	le.mtx.Unlock()
+
+	go func() {
+		le.cfg.OnStartedLeading(ctx)
+		close(done)
+
+		// the work is over while still leading, another instance can take over
+		if ctx.Err() == nil {
+			releaseCtx, cancel := context.WithTimeout(context.Background(), le.cfg.RenewInterval)
+			defer cancel()
+			if err := le.Resign(releaseCtx); err != nil {
+				le.log.Warn("Failed to release lease", "error", err)
+			}
+		}
+	}()
+}
+
+func (le *LeaderElection) stopLeadingAndWait() {
+	le.mtx.Lock()
+	cancel, done := le.stopLeading, le.leadingDone
+	le.stopLeading, le.leadingDone = nil, nil
+	le.mtx.Unlock()
+
+	if cancel != nil {
+		cancel()
+		<-done
+	}
+	if le.cfg.OnStoppedLeading != nil {
+		le.cfg.OnStoppedLeading()
+	}
+}
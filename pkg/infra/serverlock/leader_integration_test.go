@@ -0,0 +1,107 @@
+package serverlock
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestIntegrationServerLock_Lease(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+	sl := createTestableServerLock(t)
+	ctx := context.Background()
+
+	acquired, holder, err := sl.TryAcquireLease(ctx, "test-lease", "a", time.Hour)
+	require.NoError(t, err)
+	require.True(t, acquired)
+	require.Equal(t, "a", holder)
+
+	// the lease is held by a
+	acquired, holder, err = sl.TryAcquireLease(ctx, "test-lease", "b", time.Hour)
+	require.NoError(t, err)
+	require.False(t, acquired)
+	require.Equal(t, "a", holder)
+
+	// a can renew its lease
+	acquired, _, err = sl.TryAcquireLease(ctx, "test-lease", "a", time.Hour)
+	require.NoError(t, err)
+	require.True(t, acquired)
+
+	holder, err = sl.GetLeaseHolder(ctx, "test-lease", time.Hour)
+	require.NoError(t, err)
+	require.Equal(t, "a", holder)
+
+	// releasing a lease held by someone else does nothing
+	require.NoError(t, sl.ReleaseLease(ctx, "test-lease", "b"))
+	holder, err = sl.GetLeaseHolder(ctx, "test-lease", time.Hour)
+	require.NoError(t, err)
+	require.Equal(t, "a", holder)
+
+	require.NoError(t, sl.ReleaseLease(ctx, "test-lease", "a"))
+	holder, err = sl.GetLeaseHolder(ctx, "test-lease", time.Hour)
+	require.NoError(t, err)
+	require.Empty(t, holder)
+
+	acquired, holder, err = sl.TryAcquireLease(ctx, "test-lease", "b", time.Hour)
+	require.NoError(t, err)
+	require.True(t, acquired)
+	require.Equal(t, "b", holder)
+
+	// an expired lease can be taken over
+	acquired, holder, err = sl.TryAcquireLease(ctx, "test-lease", "a", time.Nanosecond)
+	require.NoError(t, err)
+	require.True(t, acquired)
+	require.Equal(t, "a", holder)
+}
+
+func TestIntegrationServerLock_LeaderElection(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+	sl := createTestableServerLock(t)
+
+	_, err := sl.NewLeaderElection("test-election", LeaderElectionConfig{LeaseDuration: 2 * time.Second, RenewInterval: 2 * time.Second})
+	require.Error(t, err)
+
+	var leading atomic.Int32
+	newElection := func() *LeaderElection {
+		le, err := sl.NewLeaderElection("test-election", LeaderElectionConfig{
+			LeaseDuration: 2 * time.Second,
+			RenewInterval: 50 * time.Millisecond,
+			OnStartedLeading: func(ctx context.Context) {
+				leading.Add(1)
+				<-ctx.Done()
+				leading.Add(-1)
+			},
+		})
+		require.NoError(t, err)
+		return le
+	}
+
+	first, second := newElection(), newElection()
+	require.NotEqual(t, first.Identity(), second.Identity())
+
+	firstCtx, stopFirst := context.WithCancel(context.Background())
+	firstDone := make(chan error)
+	go func() { firstDone <- first.Run(firstCtx) }()
+	require.Eventually(t, first.IsLeader, time.Second, 10*time.Millisecond)
+
+	secondCtx, stopSecond := context.WithCancel(context.Background())
+	defer stopSecond()
+	go func() { _ = second.Run(secondCtx) }()
+	require.Eventually(t, func() bool { return second.Leader() == first.Identity() }, time.Second, 10*time.Millisecond)
+	require.False(t, second.IsLeader())
+	require.Equal(t, int32(1), leading.Load())
+
+	// stopping the leader hands the leadership over without waiting for the lease to expire
+	stopFirst()
+	require.ErrorIs(t, <-firstDone, context.Canceled)
+	require.False(t, first.IsLeader())
+	require.Eventually(t, second.IsLeader, time.Second, 10*time.Millisecond)
+	require.Eventually(t, func() bool { return leading.Load() == 1 }, time.Second, 10*time.Millisecond)
+}
@@ -0,0 +1,124 @@
+package serverlock
+
+import (
+	"context"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+
+	"github.com/grafana/grafana/pkg/infra/db"
+)
+
+// TryAcquireLease acquires the lease named actionName for holder, or renews it when holder already holds it.
+// The lease is granted when nobody holds it or when its holder has not renewed it for leaseDuration.
+// It returns whether holder holds the lease and the holder of the lease as last seen, which is
+// empty when nobody holds it. Leases are stored in the server_lock table and must not share their
+// actionName with LockAndExecute or LockExecuteAndRelease.
+func (sl *ServerLockService) TryAcquireLease(ctx context.Context, actionName, holder string, leaseDuration time.Duration) (bool, string, error) {
+	ctx, span := sl.tracer.Start(ctx, "ServerLockService.TryAcquireLease")
+	span.SetAttributes(attribute.String("serverlock.actionName", actionName))
+	defer span.End()
+
+	rowLock, err := sl.getOrCreate(ctx, actionName)
+	if err != nil {
+		span.RecordError(err)
+		return false, "", err
+	}
+
+	// someone else holds a valid lease
+	if rowLock.Holder != "" && rowLock.Holder != holder && sl.isLockWithinInterval(rowLock, leaseDuration) {
+		return false, rowLock.Holder, nil
+	}
+
+	acquired, err := sl.updateLease(ctx, rowLock, holder, time.Now().Unix())
+	if err != nil {
+		span.RecordError(err)
+		return false, "", err
+	}
+	if !acquired {
+		// another instance updated the lease since it was read, it is the holder now
+		sl.log.FromContext(ctx).Debug("Lost race to acquire lease", "actionName", actionName)
+		return false, rowLock.Holder, nil
+	}
+
+	return true, holder, nil
+}
+
+// GetLeaseHolder returns the holder of the lease named actionName, or an empty string
+// when nobody holds it or its holder has not renewed it for leaseDuration.
+func (sl *ServerLockService) GetLeaseHolder(ctx context.Context, actionName string, leaseDuration time.Duration) (string, error) {
+	ctx, span := sl.tracer.Start(ctx, "ServerLockService.GetLeaseHolder")
+	span.SetAttributes(attribute.String("serverlock.actionName", actionName))
+	defer span.End()
+
+	var holder string
+	err := sl.SQLStore.WithDbSession(ctx, func(dbSession *db.Session) error {
+		lockRows := []*serverLock{}
+		if err := dbSession.Where("operation_uid = ?", actionName).Find(&lockRows); err != nil {
+			return err
+		}
+
+		if len(lockRows) > 0 && sl.isLockWithinInterval(lockRows[0], leaseDuration) {
+			holder = lockRows[0].Holder
+		}
+		return nil
+	})
+	if err != nil {
+		span.RecordError(err)
+	}
+
+	return holder, err
+}
+
+// ReleaseLease gives up the lease named actionName if it is held by holder, so that
+// another instance can acquire it without waiting for the lease to expire.
+func (sl *ServerLockService) ReleaseLease(ctx context.Context, actionName, holder string) error {
+	ctx, span := sl.tracer.Start(ctx, "ServerLockService.ReleaseLease")
+	span.SetAttributes(attribute.String("serverlock.actionName", actionName))
+	defer span.End()
+
+	err := sl.SQLStore.WithDbSession(ctx, func(dbSession *db.Session) error {
+		sql := `UPDATE server_lock SET
+			version = version + 1,
+			last_execution = 0,
+			holder = ''
+		WHERE
+			operation_uid = ? AND holder = ?`
+
+		_, err := dbSession.Exec(sql, actionName, holder)
+		return err
+	})
+	if err != nil {
+		span.RecordError(err)
+	}
+
+	return err
+}
+
+// updateLease sets the holder of the lease based on rowLock version.
+func (sl *ServerLockService) updateLease(ctx context.Context, serverLock *serverLock, holder string, now int64) (bool, error) {
+	ctx, span := sl.tracer.Start(ctx, "ServerLockService.updateLease")
+	defer span.End()
+	var result bool
+
+	err := sl.SQLStore.WithDbSession(ctx, func(dbSession *db.Session) error {
+		sql := `UPDATE server_lock SET
+			version = ?,
+			last_execution = ?,
+			holder = ?
+		WHERE
+			id = ? AND version = ?`
+
+		res, err := dbSession.Exec(sql, serverLock.Version+1, now, holder, serverLock.Id, serverLock.Version)
+		if err != nil {
+			return err
+		}
+
+		affected, err := res.RowsAffected()
+		result = affected == 1
+
+		return err
+	})
+
+	return result, err
+}
@@ -6,4 +6,6 @@ type serverLock struct {
 	OperationUID  string `xorm:"operation_uid"`
 	LastExecution int64
 	Version       int64
+	// Holder is the identity of the instance holding the lease, only used by leader election.
+	Holder string
 }
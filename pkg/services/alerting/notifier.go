@@ -218,6 +218,7 @@ func (n *notificationService) renderAndUploadImage(evalCtx *EvalContext, timeout
 		Width:           1000,
 		Height:          500,
 		ConcurrentLimit: setting.AlertingRenderLimit,
+		Priority:        rendering.PriorityLow,
 		Theme:           models.ThemeDark,
 	}
 
@@ -0,0 +1,21 @@
+package cleanup
+
+import (
+	"net/http"
+
+	"github.com/grafana/grafana/pkg/api/response"
+	"github.com/grafana/grafana/pkg/api/routing"
+	"github.com/grafana/grafana/pkg/middleware"
+	contextmodel "github.com/grafana/grafana/pkg/services/contexthandler/model"
+)
+
+func (srv *CleanUpService) registerAPIEndpoints() {
+	srv.routeRegister.Group("/api/admin/cleanup", func(cleanupRoute routing.RouteRegister) {
+		cleanupRoute.Get("/retention", middleware.ReqGrafanaAdmin, routing.Wrap(srv.getRetentionStatusHandler))
+	})
+}
+
+// getRetentionStatusHandler returns the last run of the retention policies on this instance.
+func (srv *CleanUpService) getRetentionStatusHandler(c *contextmodel.ReqContext) response.Response {
+	return response.JSON(http.StatusOK, srv.RetentionStatus())
+}
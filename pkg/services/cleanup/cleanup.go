@@ -8,15 +8,19 @@ import (
 	"os"
 	"path"
 	"strconv"
+	"sync"
 	"time"
 
 	"go.opentelemetry.io/otel/attribute"
 
+	"github.com/grafana/grafana/pkg/api/routing"
 	"github.com/grafana/grafana/pkg/infra/db"
+	"github.com/grafana/grafana/pkg/infra/kvstore"
 	"github.com/grafana/grafana/pkg/infra/log"
 	"github.com/grafana/grafana/pkg/infra/serverlock"
 	"github.com/grafana/grafana/pkg/infra/tracing"
 	"github.com/grafana/grafana/pkg/services/annotations"
+	"github.com/grafana/grafana/pkg/services/cleanup/retention"
 	"github.com/grafana/grafana/pkg/services/dashboardsnapshots"
 	dashver "github.com/grafana/grafana/pkg/services/dashboardversion"
 	"github.com/grafana/grafana/pkg/services/ngalert/image"
@@ -29,7 +33,8 @@ import (
 func ProvideService(cfg *setting.Cfg, serverLockService *serverlock.ServerLockService,
 	shortURLService shorturls.Service, sqlstore db.DB, queryHistoryService queryhistory.Service,
 	dashboardVersionService dashver.Service, dashSnapSvc dashboardsnapshots.Service, deleteExpiredImageService *image.DeleteExpiredService,
-	tempUserService tempuser.Service, tracer tracing.Tracer, annotationCleaner annotations.Cleaner) *CleanUpService {
+	tempUserService tempuser.Service, tracer tracing.Tracer, annotationCleaner annotations.Cleaner,
+	routeRegister routing.RouteRegister) *CleanUpService {
 	s := &CleanUpService{
 		Cfg:                       cfg,
 		ServerLockService:         serverLockService,
@@ -43,7 +48,26 @@ func ProvideService(cfg *setting.Cfg, serverLockService *serverlock.ServerLockSe
 		tempUserService:           tempUserService,
 		tracer:                    tracer,
 		annotationCleaner:         annotationCleaner,
+		routeRegister:             routeRegister,
+		retentionStatus:           map[string]RetentionStatus{},
 	}
+
+	s.RegisterRetentionJob(retention.Job{
+		DataType:  "short_urls",
+		OrgScoped: true,
+		Default:   retention.Policy{MaxAge: time.Hour * 24 * 7},
+		// only short URLs that have never been visited are removed
+		Apply: retention.Table{Store: sqlstore, Table: "short_url", TimeColumn: "created_at", TimeUnit: time.Second, OrgColumn: "org_id", Where: "(last_seen_at IS NULL OR last_seen_at = 0)"}.Apply,
+	})
+	s.RegisterRetentionJob(retention.Job{
+		DataType:  "query_history",
+		OrgScoped: true,
+		Default:   retention.Policy{MaxAge: time.Hour * 24 * 14},
+		// starred queries are never removed
+		Apply: retention.Table{Store: sqlstore, Table: "query_history", TimeColumn: "created_at", TimeUnit: time.Second, OrgColumn: "org_id", Where: "uid NOT IN (SELECT query_uid FROM query_history_star)"}.Apply,
+	})
+
+	s.registerAPIEndpoints()
 	return s
 }
 
@@ -60,6 +84,11 @@ type CleanUpService struct {
 	deleteExpiredImageService *image.DeleteExpiredService
 	tempUserService           tempuser.Service
 	annotationCleaner         annotations.Cleaner
+	routeRegister             routing.RouteRegister
+
+	retentionMtx    sync.Mutex
+	retentionJobs   []retention.Job
+	retentionStatus map[string]RetentionStatus
 }
 
 type cleanUpJob struct {
@@ -100,8 +129,9 @@ func (srv *CleanUpService) clean(ctx context.Context) {
 		{"delete expired images", srv.deleteExpiredImages},
 		{"cleanup old annotations", srv.cleanUpOldAnnotations},
 		{"expire old user invites", srv.expireOldUserInvites},
-		{"delete stale short URLs", srv.deleteStaleShortURLs},
-		{"delete stale query history", srv.deleteStaleQueryHistory},
+		{"delete expired kv store entries", srv.deleteExpiredKVStoreEntries},
+		{"enforce query history row limits", srv.enforceQueryHistoryRowLimits},
+		{"apply retention policies", srv.applyRetentionPoliciesOnce},
 	}
 
 	logger := srv.log.FromContext(ctx)
@@ -237,33 +267,21 @@ func (srv *CleanUpService) expireOldUserInvites(ctx context.Context) {
 	}
 }
 
-func (srv *CleanUpService) deleteStaleShortURLs(ctx context.Context) {
+func (srv *CleanUpService) deleteExpiredKVStoreEntries(ctx context.Context) {
 	logger := srv.log.FromContext(ctx)
-	cmd := shorturls.DeleteShortUrlCommand{
-		OlderThan: time.Now().Add(-time.Hour * 24 * 7),
-	}
-	if err := srv.ShortURLService.DeleteStaleShortURLs(ctx, &cmd); err != nil {
-		logger.Error("Problem deleting stale short urls", "error", err.Error())
+	if rowsAffected, err := kvstore.DeleteExpired(ctx, srv.store); err != nil {
+		logger.Error("Failed to delete expired kv store entries", "error", err.Error())
 	} else {
-		logger.Debug("Deleted short urls", "rows affected", cmd.NumDeleted)
+		logger.Debug("Deleted expired kv store entries", "rows affected", rowsAffected)
 	}
 }
 
-func (srv *CleanUpService) deleteStaleQueryHistory(ctx context.Context) {
+func (srv *CleanUpService) enforceQueryHistoryRowLimits(ctx context.Context) {
 	logger := srv.log.FromContext(ctx)
-	// Delete query history from 14+ days ago with exception of starred queries
-	maxQueryHistoryLifetime := time.Hour * 24 * 14
-	olderThan := time.Now().Add(-maxQueryHistoryLifetime).Unix()
-	rowsCount, err := srv.QueryHistoryService.DeleteStaleQueriesInQueryHistory(ctx, olderThan)
-	if err != nil {
-		logger.Error("Problem deleting stale query history", "error", err.Error())
-	} else {
-		logger.Debug("Deleted stale query history", "rows affected", rowsCount)
-	}
 
 	// Enforce 200k limit for query_history table
 	queryHistoryLimit := 200000
-	rowsCount, err = srv.QueryHistoryService.EnforceRowLimitInQueryHistory(ctx, queryHistoryLimit, false)
+	rowsCount, err := srv.QueryHistoryService.EnforceRowLimitInQueryHistory(ctx, queryHistoryLimit, false)
 	if err != nil {
 		logger.Error("Problem with enforcing row limit for query_history", "error", err.Error())
 	} else {
@@ -0,0 +1,166 @@
+package cleanup
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend/gtime"
+	"gopkg.in/ini.v1"
+
+	"github.com/grafana/grafana/pkg/services/cleanup/retention"
+	"github.com/grafana/grafana/pkg/setting"
+)
+
+const (
+	retentionSection = "retention"
+
+	retentionLockActionName = "apply retention policies"
+	// retentionLockInterval is the minimum interval between two runs of the retention policies, shorter
+	// than the interval of the cleanup jobs so that the policies are applied once per cleanup run.
+	retentionLockInterval = 9 * time.Minute
+)
+
+// RetentionStatus is the result of the last run of a retention policy.
+type RetentionStatus struct {
+	DataType string `json:"dataType"`
+	OrgID    int64  `json:"orgId"`
+	MaxAge   string `json:"maxAge,omitempty"`
+	MaxCount int64  `json:"maxCount,omitempty"`
+	DryRun   bool   `json:"dryRun"`
+
+	LastRun  time.Time `json:"lastRun"`
+	Duration string    `json:"duration"`
+	// RowsRemoved is the number of rows removed, or that would have been removed in dry-run mode.
+	RowsRemoved int64  `json:"rowsRemoved"`
+	Error       string `json:"error,omitempty"`
+}
+
+func (srv *CleanUpService) RegisterRetentionJob(job retention.Job) {
+	srv.retentionMtx.Lock()
+	defer srv.retentionMtx.Unlock()
+	srv.retentionJobs = append(srv.retentionJobs, job)
+}
+
+// RetentionStatus returns the result of the last run of every retention policy.
+func (srv *CleanUpService) RetentionStatus() []RetentionStatus {
+	srv.retentionMtx.Lock()
+	defer srv.retentionMtx.Unlock()
+
+	statuses := make([]RetentionStatus, 0, len(srv.retentionStatus))
+	for _, status := range srv.retentionStatus {
+		statuses = append(statuses, status)
+	}
+	sort.Slice(statuses, func(i, j int) bool {
+		if statuses[i].DataType != statuses[j].DataType {
+			return statuses[i].DataType < statuses[j].DataType
+		}
+		return statuses[i].OrgID < statuses[j].OrgID
+	})
+	return statuses
+}
+
+// applyRetentionPoliciesOnce applies the retention policies on a single instance
+// at a time, as all the instances share the database.
+func (srv *CleanUpService) applyRetentionPoliciesOnce(ctx context.Context) {
+	err := srv.ServerLockService.LockAndExecute(ctx, retentionLockActionName, retentionLockInterval, srv.applyRetentionPolicies)
+	if err != nil {
+		srv.log.FromContext(ctx).Error("Failed to lock and apply retention policies", "error", err)
+	}
+}
+
+func (srv *CleanUpService) applyRetentionPolicies(ctx context.Context) {
+	logger := srv.log.FromContext(ctx)
+
+	srv.retentionMtx.Lock()
+	jobs := make([]retention.Job, len(srv.retentionJobs))
+	copy(jobs, srv.retentionJobs)
+	srv.retentionMtx.Unlock()
+
+	for _, job := range jobs {
+		for _, policy := range retentionPolicies(srv.Cfg, job) {
+			if ctx.Err() != nil {
+				return
+			}
+			if policy.IsZero() {
+				continue
+			}
+
+			start := time.Now()
+			rows, err := job.Apply(ctx, policy)
+			status := RetentionStatus{
+				DataType:    job.DataType,
+				OrgID:       policy.OrgID,
+				MaxCount:    policy.MaxCount,
+				DryRun:      policy.DryRun,
+				LastRun:     start,
+				Duration:    time.Since(start).String(),
+				RowsRemoved: rows,
+			}
+			if policy.MaxAge > 0 {
+				status.MaxAge = policy.MaxAge.String()
+			}
+
+			switch {
+			case err != nil && !errors.Is(err, context.DeadlineExceeded):
+				status.Error = err.Error()
+				logger.Error("Failed to apply retention policy", "dataType", job.DataType, "orgId", policy.OrgID, "error", err)
+			case policy.DryRun:
+				logger.Info("Retention policy dry run", "dataType", job.DataType, "orgId", policy.OrgID, "rows to remove", rows)
+			default:
+				logger.Debug("Applied retention policy", "dataType", job.DataType, "orgId", policy.OrgID, "rows affected", rows)
+			}
+
+			srv.retentionMtx.Lock()
+			srv.retentionStatus[fmt.Sprintf("%s/%d", job.DataType, policy.OrgID)] = status
+			srv.retentionMtx.Unlock()
+		}
+	}
+}
+
+// retentionPolicies returns the policies configured for a job: the policy of the data type,
+// which falls back to the default policy of the job, followed by the organization policies.
+func retentionPolicies(cfg *setting.Cfg, job retention.Job) []retention.Policy {
+	policy := job.Default
+	policy.DryRun = cfg.SectionWithEnvOverrides(retentionSection).Key("dry_run").MustBool(false)
+
+	prefix := retentionSection + "." + job.DataType
+	if section, err := cfg.Raw.GetSection(prefix); err == nil {
+		policy = readRetentionPolicy(section, policy)
+	}
+
+	var orgPolicies []retention.Policy
+	for _, section := range cfg.Raw.Sections() {
+		orgID, ok := strings.CutPrefix(section.Name(), prefix+".org_")
+		if !ok {
+			continue
+		}
+		id, err := strconv.ParseInt(orgID, 10, 64)
+		if err != nil || !job.OrgScoped {
+			continue
+		}
+
+		orgPolicy := readRetentionPolicy(section, policy)
+		orgPolicy.OrgID = id
+		orgPolicies = append(orgPolicies, orgPolicy)
+		policy.ExcludedOrgIDs = append(policy.ExcludedOrgIDs, id)
+	}
+
+	return append([]retention.Policy{policy}, orgPolicies...)
+}
+
+func readRetentionPolicy(section *ini.Section, fallback retention.Policy) retention.Policy {
+	policy := fallback
+	if maxAge := section.Key("max_age").String(); maxAge != "" {
+		if d, err := gtime.ParseDuration(maxAge); err == nil {
+			policy.MaxAge = d
+		}
+	}
+	policy.MaxCount = section.Key("max_count").MustInt64(fallback.MaxCount)
+	policy.DryRun = section.Key("dry_run").MustBool(fallback.DryRun)
+	return policy
+}
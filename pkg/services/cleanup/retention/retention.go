@@ -0,0 +1,48 @@
+// Package retention defines the retention jobs that services register with the cleanup service
+// to remove their old data, without depending on the cleanup service itself.
+package retention
+
+import (
+	"context"
+	"time"
+)
+
+// Policy limits how long and how many rows of a data type are kept.
+type Policy struct {
+	// OrgID is the organization the policy applies to, or 0 for every organization.
+	OrgID int64
+	// ExcludedOrgIDs are the organizations with a policy of their own, which
+	// are left alone by a policy applying to every organization.
+	ExcludedOrgIDs []int64
+	// MaxAge is the age after which rows are removed, 0 to keep rows regardless of their age.
+	MaxAge time.Duration
+	// MaxCount is the number of most recent rows kept per organization, 0 for no limit.
+	MaxCount int64
+	// DryRun counts the rows the policy would remove without removing them.
+	DryRun bool
+}
+
+// IsZero returns true if the policy doesn't remove anything.
+func (p Policy) IsZero() bool {
+	return p.MaxAge <= 0 && p.MaxCount <= 0
+}
+
+// Job removes the rows of a data type that aren't retained by its policies.
+type Job struct {
+	// DataType names the data type in the [retention.<data type>] config sections and in the status.
+	DataType string
+	// OrgScoped is true if policies can be configured per organization
+	// in [retention.<data type>.org_<org id>] config sections.
+	OrgScoped bool
+	// Default is the policy applied when none is configured for the data type.
+	Default Policy
+	// Apply removes the rows not retained by policy, or only counts them in dry-run mode,
+	// and returns their number.
+	Apply func(ctx context.Context, policy Policy) (int64, error)
+}
+
+// JobRegistry lets services register the retention jobs of their own data,
+// which are run by the cleanup service along with the built-in ones.
+type JobRegistry interface {
+	RegisterRetentionJob(job Job)
+}
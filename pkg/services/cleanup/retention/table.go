@@ -0,0 +1,171 @@
+package retention
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/grafana/grafana/pkg/infra/db"
+)
+
+// batchSize is the number of rows removed by each statement of a Table.
+const batchSize = 1000
+
+// Table applies retention policies to the rows of a table with an id column,
+// a creation time column and, optionally, an organization column.
+type Table struct {
+	Store db.DB
+	Table string
+	// TimeColumn holds the creation time of the rows as a unix timestamp in TimeUnit.
+	TimeColumn string
+	TimeUnit   time.Duration
+	// OrgColumn holds the organization of the rows, empty if the table isn't scoped to an organization.
+	OrgColumn string
+	// Where restricts the rows subject to the policies, for example to leave starred rows alone.
+	Where string
+	// AfterDelete is executed once rows have been removed, for example to remove the rows referencing them.
+	AfterDelete string
+}
+
+// Apply removes the rows older than the maximum age of policy, then the oldest rows exceeding its
+// maximum count in each organization. In dry-run mode, it only counts the rows it would remove.
+func (r Table) Apply(ctx context.Context, policy Policy) (int64, error) {
+	var removed int64
+	err := r.Store.WithDbSession(ctx, func(sess *db.Session) error {
+		orgIDs := []int64{policy.OrgID}
+		if r.OrgColumn == "" {
+			orgIDs = []int64{0}
+		} else if policy.OrgID == 0 && policy.MaxCount > 0 {
+			where, args := r.where(policy, 0)
+			orgIDs = nil
+			if err := sess.SQL(fmt.Sprintf("SELECT DISTINCT %s FROM %s WHERE %s", r.OrgColumn, r.Table, where), args...).Find(&orgIDs); err != nil {
+				return err
+			}
+		}
+
+		for _, orgID := range orgIDs {
+			n, err := r.apply(ctx, sess, policy, orgID)
+			removed += n
+			if err != nil {
+				return err
+			}
+		}
+
+		if removed > 0 && !policy.DryRun && r.AfterDelete != "" {
+			_, err := sess.Exec(r.AfterDelete)
+			return err
+		}
+		return nil
+	})
+	return removed, err
+}
+
+func (r Table) apply(ctx context.Context, sess *db.Session, policy Policy, orgID int64) (int64, error) {
+	where, args := r.where(policy, orgID)
+
+	var expired int64
+	ageWhere, ageArgs := where, args
+	if policy.MaxAge > 0 {
+		ageWhere = where + fmt.Sprintf(" AND %s < ?", r.TimeColumn)
+		ageArgs = append(append([]any{}, args...), time.Now().Add(-policy.MaxAge).UnixNano()/int64(r.TimeUnit))
+
+		n, err := r.removeBatches(ctx, sess, policy.DryRun, ageWhere, ageArgs, 0)
+		if err != nil {
+			return n, err
+		}
+		expired = n
+	}
+
+	if policy.MaxCount <= 0 {
+		return expired, nil
+	}
+
+	if policy.DryRun {
+		var total int64
+		if _, err := sess.SQL(fmt.Sprintf("SELECT COUNT(*) FROM %s WHERE %s", r.Table, where), args...).Get(&total); err != nil {
+			return expired, err
+		}
+		if excess := total - expired - policy.MaxCount; excess > 0 {
+			return expired + excess, nil
+		}
+		return expired, nil
+	}
+
+	n, err := r.removeBatches(ctx, sess, false, where, args, policy.MaxCount)
+	return expired + n, err
+}
+
+// removeBatches removes, batch by batch, the rows matching where after skipping the
+// offset most recent ones. In dry-run mode, it only counts them.
+func (r Table) removeBatches(ctx context.Context, sess *db.Session, dryRun bool, where string, args []any, offset int64) (int64, error) {
+	if dryRun {
+		var count int64
+		_, err := sess.SQL(fmt.Sprintf("SELECT COUNT(*) FROM %s WHERE %s", r.Table, where), args...).Get(&count)
+		return count, err
+	}
+
+	var removed int64
+	for {
+		if ctx.Err() != nil {
+			return removed, ctx.Err()
+		}
+
+		var ids []int64
+		query := fmt.Sprintf("SELECT id FROM %s WHERE %s ORDER BY %s DESC, id DESC LIMIT %d OFFSET %d",
+			r.Table, where, r.TimeColumn, batchSize, offset)
+		if err := sess.SQL(query, args...).Find(&ids); err != nil {
+			return removed, err
+		}
+		if len(ids) == 0 {
+			return removed, nil
+		}
+
+		placeholders := strings.TrimSuffix(strings.Repeat("?,", len(ids)), ",")
+		deleteArgs := make([]any, 0, len(ids)+1)
+		deleteArgs = append(deleteArgs, fmt.Sprintf("DELETE FROM %s WHERE id IN (%s)", r.Table, placeholders))
+		for _, id := range ids {
+			deleteArgs = append(deleteArgs, id)
+		}
+		res, err := sess.Exec(deleteArgs...)
+		if err != nil {
+			return removed, err
+		}
+		affected, err := res.RowsAffected()
+		if err != nil {
+			return removed, err
+		}
+		removed += affected
+
+		if len(ids) < batchSize {
+			return removed, nil
+		}
+	}
+}
+
+// where returns the condition matching the rows subject to policy in the organization orgID,
+// or in every organization not excluded by policy if orgID is 0.
+func (r Table) where(policy Policy, orgID int64) (string, []any) {
+	conditions := []string{"1 = 1"}
+	var args []any
+
+	if r.Where != "" {
+		conditions = append(conditions, "("+r.Where+")")
+	}
+
+	if r.OrgColumn != "" {
+		switch {
+		case orgID != 0:
+			conditions = append(conditions, r.OrgColumn+" = ?")
+			args = append(args, orgID)
+		case len(policy.ExcludedOrgIDs) > 0:
+			conditions = append(conditions, fmt.Sprintf("%s NOT IN (%s)", r.OrgColumn,
+				strings.TrimSuffix(strings.Repeat("?,", len(policy.ExcludedOrgIDs)), ",")))
+			for _, id := range policy.ExcludedOrgIDs {
+				args = append(args, id)
+			}
+		}
+	}
+
+	return strings.Join(conditions, " AND "), args
+}
@@ -0,0 +1,100 @@
+package retention
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/infra/db"
+	"github.com/grafana/grafana/pkg/services/shorturls"
+)
+
+func TestIntegrationTable(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+
+	store := db.InitTestDB(t)
+	ctx := context.Background()
+	table := Table{Store: store, Table: "short_url", TimeColumn: "created_at", TimeUnit: time.Second, OrgColumn: "org_id", Where: "last_seen_at = 0"}
+
+	// setup inserts, in orgs 1 and 2, 5 short URLs created 1 to 5 days ago and one visited 10 days ago
+	setup := func(t *testing.T) {
+		t.Helper()
+		require.NoError(t, store.WithDbSession(ctx, func(sess *db.Session) error {
+			if _, err := sess.Exec("DELETE FROM short_url"); err != nil {
+				return err
+			}
+			for _, orgID := range []int64{1, 2} {
+				for days := 1; days <= 5; days++ {
+					if _, err := sess.Insert(&shorturls.ShortUrl{
+						OrgId:     orgID,
+						Uid:       fmt.Sprintf("org%d-%dd", orgID, days),
+						CreatedAt: time.Now().Add(-time.Duration(days) * 24 * time.Hour).Unix(),
+					}); err != nil {
+						return err
+					}
+				}
+				if _, err := sess.Insert(&shorturls.ShortUrl{
+					OrgId:      orgID,
+					Uid:        fmt.Sprintf("org%d-visited", orgID),
+					CreatedAt:  time.Now().Add(-10 * 24 * time.Hour).Unix(),
+					LastSeenAt: time.Now().Unix(),
+				}); err != nil {
+					return err
+				}
+			}
+			return nil
+		}))
+	}
+
+	remaining := func(t *testing.T) []string {
+		t.Helper()
+		var uids []string
+		require.NoError(t, store.WithDbSession(ctx, func(sess *db.Session) error {
+			return sess.Table("short_url").Cols("uid").Asc("uid").Find(&uids)
+		}))
+		return uids
+	}
+
+	t.Run("removes the rows older than the max age", func(t *testing.T) {
+		setup(t)
+		removed, err := table.Apply(ctx, Policy{MaxAge: 60 * time.Hour})
+		require.NoError(t, err)
+		assert.Equal(t, int64(6), removed)
+		assert.Equal(t, []string{"org1-1d", "org1-2d", "org1-visited", "org2-1d", "org2-2d", "org2-visited"}, remaining(t))
+	})
+
+	t.Run("keeps the most recent rows of each organization", func(t *testing.T) {
+		setup(t)
+		removed, err := table.Apply(ctx, Policy{MaxCount: 1})
+		require.NoError(t, err)
+		assert.Equal(t, int64(8), removed)
+		assert.Equal(t, []string{"org1-1d", "org1-visited", "org2-1d", "org2-visited"}, remaining(t))
+	})
+
+	t.Run("applies organization policies", func(t *testing.T) {
+		setup(t)
+		removed, err := table.Apply(ctx, Policy{OrgID: 2, MaxAge: 36 * time.Hour, MaxCount: 3})
+		require.NoError(t, err)
+		assert.Equal(t, int64(4), removed)
+
+		removed, err = table.Apply(ctx, Policy{ExcludedOrgIDs: []int64{2}, MaxCount: 3})
+		require.NoError(t, err)
+		assert.Equal(t, int64(2), removed)
+		assert.Equal(t, []string{"org1-1d", "org1-2d", "org1-3d", "org1-visited", "org2-1d", "org2-visited"}, remaining(t))
+	})
+
+	t.Run("only counts the rows in dry-run mode", func(t *testing.T) {
+		setup(t)
+		before := remaining(t)
+		removed, err := table.Apply(ctx, Policy{MaxAge: 84 * time.Hour, MaxCount: 1, DryRun: true})
+		require.NoError(t, err)
+		assert.Equal(t, int64(8), removed)
+		assert.Equal(t, before, remaining(t))
+	})
+}
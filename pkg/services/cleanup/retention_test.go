@@ -0,0 +1,119 @@
+package cleanup
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/ini.v1"
+
+	"github.com/grafana/grafana/pkg/infra/db"
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/infra/serverlock"
+	"github.com/grafana/grafana/pkg/infra/tracing"
+	"github.com/grafana/grafana/pkg/services/cleanup/retention"
+	"github.com/grafana/grafana/pkg/setting"
+)
+
+func TestRetentionPolicies(t *testing.T) {
+	raw, err := ini.Load([]byte(`
+[retention]
+dry_run = true
+
+[retention.short_urls]
+max_age = 30d
+
+[retention.short_urls.org_2]
+max_count = 100
+dry_run = false
+
+[retention.login_attempts.org_2]
+max_age = 1d
+`))
+	require.NoError(t, err)
+	cfg := setting.NewCfg()
+	cfg.Raw = raw
+
+	t.Run("org scoped jobs", func(t *testing.T) {
+		policies := retentionPolicies(cfg, retention.Job{DataType: "short_urls", OrgScoped: true, Default: retention.Policy{MaxAge: time.Hour}})
+		assert.Equal(t, []retention.Policy{
+			{MaxAge: 30 * 24 * time.Hour, DryRun: true, ExcludedOrgIDs: []int64{2}},
+			{OrgID: 2, MaxAge: 30 * 24 * time.Hour, MaxCount: 100},
+		}, policies)
+	})
+
+	t.Run("jobs not scoped to organizations ignore organization policies", func(t *testing.T) {
+		policies := retentionPolicies(cfg, retention.Job{DataType: "login_attempts", Default: retention.Policy{MaxAge: time.Hour}})
+		assert.Equal(t, []retention.Policy{{MaxAge: time.Hour, DryRun: true}}, policies)
+	})
+}
+
+func TestApplyRetentionPolicies(t *testing.T) {
+	cfg := setting.NewCfg()
+	cfg.Raw.Section("retention.failing").Key("max_count").SetValue("10")
+	srv := &CleanUpService{Cfg: cfg, log: log.New("cleanup"), retentionStatus: map[string]RetentionStatus{}}
+
+	var applied []retention.Policy
+	srv.RegisterRetentionJob(retention.Job{
+		DataType: "things",
+		Default:  retention.Policy{MaxAge: time.Hour},
+		Apply: func(ctx context.Context, policy retention.Policy) (int64, error) {
+			applied = append(applied, policy)
+			return 3, nil
+		},
+	})
+	srv.RegisterRetentionJob(retention.Job{
+		DataType: "failing",
+		Apply: func(ctx context.Context, policy retention.Policy) (int64, error) {
+			return 0, fmt.Errorf("table is locked")
+		},
+	})
+	srv.RegisterRetentionJob(retention.Job{
+		DataType: "unconfigured",
+		Apply: func(ctx context.Context, policy retention.Policy) (int64, error) {
+			t.Fatal("a job without policy should not be applied")
+			return 0, nil
+		},
+	})
+
+	srv.applyRetentionPolicies(context.Background())
+
+	assert.Equal(t, []retention.Policy{{MaxAge: time.Hour}}, applied)
+	status := srv.RetentionStatus()
+	require.Len(t, status, 2)
+	assert.Equal(t, "failing", status[0].DataType)
+	assert.Equal(t, "table is locked", status[0].Error)
+	assert.Equal(t, int64(10), status[0].MaxCount)
+	assert.Equal(t, "things", status[1].DataType)
+	assert.Equal(t, int64(3), status[1].RowsRemoved)
+	assert.Equal(t, "1h0m0s", status[1].MaxAge)
+}
+
+func TestIntegrationApplyRetentionPoliciesOnce(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+
+	store := db.InitTestDB(t)
+	lock := serverlock.ProvideService(store, tracing.InitializeTracerForTest())
+
+	applied := 0
+	for i := 0; i < 2; i++ {
+		// the instances share the database and the server lock
+		srv := &CleanUpService{Cfg: setting.NewCfg(), ServerLockService: lock, log: log.New("cleanup"), retentionStatus: map[string]RetentionStatus{}}
+		srv.RegisterRetentionJob(retention.Job{
+			DataType: "things",
+			Default:  retention.Policy{MaxAge: time.Hour},
+			Apply: func(ctx context.Context, policy retention.Policy) (int64, error) {
+				applied++
+				return 0, nil
+			},
+		})
+		srv.applyRetentionPoliciesOnce(context.Background())
+	}
+
+	assert.Equal(t, 1, applied)
+}
@@ -1,8 +1,11 @@
 package encryption
 
 import (
+	"bytes"
 	"context"
 	"crypto/sha256"
+	"encoding/base64"
+	"errors"
 
 	"golang.org/x/crypto/pbkdf2"
 )
@@ -12,6 +15,12 @@ const (
 
 	AesCfb = "aes-cfb"
 	AesGcm = "aes-gcm"
+
+	// DefaultAlgorithm is the algorithm used to encrypt secrets unless
+	// another one is configured in [security.encryption].
+	DefaultAlgorithm = AesGcm
+
+	algorithmDelimiter = '*'
 )
 
 // Internal must not be used for general purpose encryption.
@@ -46,3 +55,33 @@ type Provider interface {
 func KeyToBytes(secret, salt string) ([]byte, error) {
 	return pbkdf2.Key([]byte(secret), []byte(salt), 10000, 32, sha256.New), nil
 }
+
+// DeriveAlgorithm returns the algorithm a payload produced by Internal.Encrypt was encrypted
+// with, and the ciphertext without the algorithm metadata. Payloads without metadata were
+// encrypted with AES-CFB. The algorithm is empty if the metadata is present but empty, which
+// was caused by a bug in the past and must be treated as AES-CFB too.
+func DeriveAlgorithm(payload []byte) (string, []byte, error) {
+	if len(payload) == 0 {
+		return "", nil, errors.New("unable to derive encryption algorithm")
+	}
+
+	if payload[0] != algorithmDelimiter {
+		return AesCfb, payload, nil // backwards compatibility
+	}
+
+	payload = payload[1:]
+	algorithmDelimiterIdx := bytes.Index(payload, []byte{algorithmDelimiter})
+	if algorithmDelimiterIdx == -1 {
+		return AesCfb, payload, nil // backwards compatibility
+	}
+
+	algorithmB64 := payload[:algorithmDelimiterIdx]
+	payload = payload[algorithmDelimiterIdx+1:]
+
+	algorithm := make([]byte, base64.RawStdEncoding.DecodedLen(len(algorithmB64)))
+	if _, err := base64.RawStdEncoding.Decode(algorithm, algorithmB64); err != nil {
+		return "", nil, err
+	}
+
+	return string(algorithm), payload, nil
+}
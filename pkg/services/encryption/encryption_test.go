@@ -20,3 +20,24 @@ func Test_KeyToBytes(t *testing.T) {
 		assert.Len(t, key, 32)
 	})
 }
+
+func Test_DeriveAlgorithm(t *testing.T) {
+	t.Run("with algorithm metadata", func(t *testing.T) {
+		algorithm, payload, err := DeriveAlgorithm([]byte("*YWVzLWdjbQ*ciphertext"))
+		require.NoError(t, err)
+		assert.Equal(t, AesGcm, algorithm)
+		assert.Equal(t, []byte("ciphertext"), payload)
+	})
+
+	t.Run("without algorithm metadata", func(t *testing.T) {
+		algorithm, payload, err := DeriveAlgorithm([]byte("ciphertext"))
+		require.NoError(t, err)
+		assert.Equal(t, AesCfb, algorithm)
+		assert.Equal(t, []byte("ciphertext"), payload)
+	})
+
+	t.Run("with empty payload", func(t *testing.T) {
+		_, _, err := DeriveAlgorithm(nil)
+		require.Error(t, err)
+	})
+}
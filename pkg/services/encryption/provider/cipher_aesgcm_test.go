@@ -0,0 +1,38 @@
+package provider
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/services/encryption"
+)
+
+func Test_aesGcmCipher(t *testing.T) {
+	cipher := aesGcmCipher{}
+	decipher := aesDecipher{algorithm: encryption.AesGcm}
+	ctx := context.Background()
+
+	encrypted, err := cipher.Encrypt(ctx, []byte("grafana"), "1234")
+	require.NoError(t, err)
+	assert.NotEmpty(t, encrypted)
+
+	decrypted, err := decipher.Decrypt(ctx, encrypted, "1234")
+	require.NoError(t, err)
+	assert.Equal(t, []byte("grafana"), decrypted)
+
+	t.Run("tampered payload cannot be decrypted", func(t *testing.T) {
+		tampered := append([]byte{}, encrypted...)
+		tampered[len(tampered)-1] ^= 0xff
+
+		_, err := decipher.Decrypt(ctx, tampered, "1234")
+		require.Error(t, err)
+	})
+
+	t.Run("wrong secret cannot decrypt", func(t *testing.T) {
+		_, err := decipher.Decrypt(ctx, encrypted, "4321")
+		require.Error(t, err)
+	})
+}
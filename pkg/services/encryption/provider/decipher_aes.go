@@ -43,6 +43,10 @@ func decryptGCM(block cipher.Block, payload []byte) ([]byte, error) {
 		return nil, err
 	}
 
+	if len(payload) < encryption.SaltLength+gcm.NonceSize() {
+		return nil, errors.New("payload too short")
+	}
+
 	nonce := payload[encryption.SaltLength : encryption.SaltLength+gcm.NonceSize()]
 	ciphertext := payload[encryption.SaltLength+gcm.NonceSize():]
 	return gcm.Open(nil, nonce, ciphertext, nil)
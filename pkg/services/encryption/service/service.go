@@ -1,7 +1,6 @@
 package service
 
 import (
-	"bytes"
 	"context"
 	"encoding/base64"
 	"errors"
@@ -18,7 +17,7 @@ const (
 
 	securitySection            = "security.encryption"
 	encryptionAlgorithmKey     = "algorithm"
-	defaultEncryptionAlgorithm = encryption.AesCfb
+	defaultEncryptionAlgorithm = encryption.DefaultAlgorithm
 )
 
 // Service must not be used for encryption.
@@ -122,26 +121,7 @@ func (s *Service) Decrypt(ctx context.Context, payload []byte, secret string) ([
 }
 
 func (s *Service) deriveEncryptionAlgorithm(payload []byte) (string, []byte, error) {
-	if len(payload) == 0 {
-		return "", nil, fmt.Errorf("unable to derive encryption algorithm")
-	}
-
-	if payload[0] != encryptionAlgorithmDelimiter {
-		return encryption.AesCfb, payload, nil // backwards compatibility
-	}
-
-	payload = payload[1:]
-	algorithmDelimiterIdx := bytes.Index(payload, []byte{encryptionAlgorithmDelimiter})
-	if algorithmDelimiterIdx == -1 {
-		return encryption.AesCfb, payload, nil // backwards compatibility
-	}
-
-	algorithmB64 := payload[:algorithmDelimiterIdx]
-	payload = payload[algorithmDelimiterIdx+1:]
-
-	algorithm := make([]byte, base64.RawStdEncoding.DecodedLen(len(algorithmB64)))
-
-	_, err := base64.RawStdEncoding.Decode(algorithm, algorithmB64)
+	algorithm, payload, err := encryption.DeriveAlgorithm(payload)
 	if err != nil {
 		return "", nil, err
 	}
@@ -154,12 +134,12 @@ func (s *Service) deriveEncryptionAlgorithm(payload []byte) (string, []byte, err
 	//
 	// Therefore, we want to keep doing the same to be able to
 	// decrypt legacy secrets.
-	if string(algorithm) == "" {
-		s.log.Warn("Encryption algorithm derivation found an empty string", "error", err)
+	if algorithm == "" {
+		s.log.Warn("Encryption algorithm derivation found an empty string")
 		return encryption.AesCfb, payload, nil
 	}
 
-	return string(algorithm), payload, nil
+	return algorithm, payload, nil
 }
 
 func (s *Service) Encrypt(ctx context.Context, payload []byte, secret string) ([]byte, error) {
@@ -0,0 +1,103 @@
+package live
+
+import (
+	"context"
+	"encoding/json"
+	"sort"
+
+	"github.com/grafana/grafana/pkg/services/supportbundles"
+)
+
+// maxSupportBundleChannels is the number of busiest channels listed in the support bundle.
+const maxSupportBundleChannels = 50
+
+func (g *GrafanaLive) supportBundleCollector() supportbundles.Collector {
+	collectorFn := func(ctx context.Context) (*supportbundles.SupportItem, error) {
+		type nodeInfo struct {
+			Name        string `json:"name"`
+			Version     string `json:"version"`
+			NumClients  uint32 `json:"numClients"`
+			NumUsers    uint32 `json:"numUsers"`
+			NumSubs     uint32 `json:"numSubscriptions"`
+			NumChannels uint32 `json:"numChannels"`
+			Uptime      uint32 `json:"uptimeSeconds"`
+		}
+
+		type channelInfo struct {
+			Channel        string `json:"channel"`
+			NumSubscribers int    `json:"numSubscribers"`
+		}
+
+		type liveInfo struct {
+			HAEngine         string        `json:"haEngine,omitempty"`
+			MaxConnections   int           `json:"maxConnections"`
+			NumClients       int           `json:"numClients"`
+			NumUsers         int           `json:"numUsers"`
+			NumSubscriptions int           `json:"numSubscriptions"`
+			NumChannels      int           `json:"numChannels"`
+			Nodes            []nodeInfo    `json:"nodes,omitempty"`
+			NodesError       string        `json:"nodesError,omitempty"`
+			BusiestChannels  []channelInfo `json:"busiestChannels,omitempty"`
+		}
+
+		hub := g.node.Hub()
+		info := liveInfo{
+			HAEngine:         g.Cfg.LiveHAEngine,
+			MaxConnections:   g.Cfg.LiveMaxConnections,
+			NumClients:       hub.NumClients(),
+			NumUsers:         hub.NumUsers(),
+			NumSubscriptions: hub.NumSubscriptions(),
+			NumChannels:      hub.NumChannels(),
+		}
+
+		// the HA engine address and password are left out on purpose, only
+		// the node statistics are relevant here.
+		if nodes, err := g.node.Info(); err != nil {
+			info.NodesError = err.Error()
+		} else {
+			for _, n := range nodes.Nodes {
+				info.Nodes = append(info.Nodes, nodeInfo{
+					Name:        n.Name,
+					Version:     n.Version,
+					NumClients:  n.NumClients,
+					NumUsers:    n.NumUsers,
+					NumSubs:     n.NumSubs,
+					NumChannels: n.NumChannels,
+					Uptime:      n.Uptime,
+				})
+			}
+		}
+
+		for _, ch := range hub.Channels() {
+			info.BusiestChannels = append(info.BusiestChannels, channelInfo{
+				Channel:        ch,
+				NumSubscribers: hub.NumSubscribers(ch),
+			})
+		}
+		sort.Slice(info.BusiestChannels, func(i, j int) bool {
+			return info.BusiestChannels[i].NumSubscribers > info.BusiestChannels[j].NumSubscribers
+		})
+		if len(info.BusiestChannels) > maxSupportBundleChannels {
+			info.BusiestChannels = info.BusiestChannels[:maxSupportBundleChannels]
+		}
+
+		data, err := json.MarshalIndent(info, "", " ")
+		if err != nil {
+			return nil, err
+		}
+
+		return &supportbundles.SupportItem{
+			Filename:  "live.json",
+			FileBytes: data,
+		}, nil
+	}
+
+	return supportbundles.Collector{
+		UID:               "live",
+		DisplayName:       "Grafana Live",
+		Description:       "Grafana Live connection and channel counts",
+		IncludedByDefault: false,
+		Default:           true,
+		Fn:                collectorFn,
+	}
+}
@@ -131,6 +131,21 @@ var (
 		},
 	}
 
+	receiversSecretsReaderRole = accesscontrol.RoleRegistration{
+		Role: accesscontrol.RoleDTO{
+			Name:        accesscontrol.FixedRolePrefix + "alerting.receivers.secrets:reader",
+			DisplayName: "Contact Point Secrets Reader",
+			Description: "Read the payloads of the notifications sent by contact points, which can include their secrets",
+			Group:       AlertRolesGroup,
+			Permissions: []accesscontrol.Permission{
+				{
+					Action: accesscontrol.ActionAlertingReceiversReadSecrets, // organization scope
+				},
+			},
+		},
+		Grants: []string{string(org.RoleAdmin)},
+	}
+
 	alertingReaderRole = accesscontrol.RoleRegistration{
 		Role: accesscontrol.RoleDTO{
 			Name:        accesscontrol.FixedRolePrefix + "alerting:reader",
@@ -194,7 +209,7 @@ func DeclareFixedRoles(service accesscontrol.Service) error {
 	return service.DeclareFixedRoles(
 		rulesReaderRole, rulesWriterRole,
 		instancesReaderRole, instancesWriterRole,
-		notificationsReaderRole, notificationsWriterRole,
+		notificationsReaderRole, notificationsWriterRole, receiversSecretsReaderRole,
 		alertingReaderRole, alertingWriterRole, alertingProvisionerRole, alertingProvisioningReaderWithSecretsRole,
 	)
 }
@@ -28,10 +28,12 @@ const (
 )
 
 type AlertmanagerSrv struct {
-	log    log.Logger
-	ac     accesscontrol.AccessControl
-	mam    *notifier.MultiOrgAlertmanager
-	crypto notifier.Crypto
+	log                 log.Logger
+	ac                  accesscontrol.AccessControl
+	mam                 *notifier.MultiOrgAlertmanager
+	crypto              notifier.Crypto
+	recurringSilences   *notifier.RecurringSilenceService
+	notificationHistory *notifier.NotificationHistorian
 }
 
 type UnknownReceiverError struct {
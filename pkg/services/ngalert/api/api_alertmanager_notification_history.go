@@ -0,0 +1,60 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/grafana/grafana/pkg/api/response"
+	"github.com/grafana/grafana/pkg/services/accesscontrol"
+	contextmodel "github.com/grafana/grafana/pkg/services/contexthandler/model"
+	apimodels "github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+	ngmodels "github.com/grafana/grafana/pkg/services/ngalert/models"
+)
+
+const defaultNotificationHistoryLimit = 100
+
+func (srv AlertmanagerSrv) RouteGetNotificationHistory(c *contextmodel.ReqContext) response.Response {
+	if srv.notificationHistory == nil {
+		return ErrResp(http.StatusNotFound, errors.New("notification history is disabled"), "")
+	}
+
+	query := ngmodels.NotificationHistoryQuery{
+		OrgID:       c.SignedInUser.GetOrgID(),
+		Receiver:    c.Query("receiver"),
+		Integration: c.Query("integration"),
+		Status:      ngmodels.NotificationStatus(c.Query("status")),
+		GroupKey:    c.Query("groupKey"),
+		Limit:       c.QueryInt("limit"),
+	}
+	switch query.Status {
+	case "", ngmodels.NotificationStatusSuccess, ngmodels.NotificationStatusFailure:
+	default:
+		return ErrResp(http.StatusBadRequest, fmt.Errorf("invalid status %q, must be one of %q or %q", query.Status, ngmodels.NotificationStatusSuccess, ngmodels.NotificationStatusFailure), "")
+	}
+	if from := c.QueryInt64("from"); from > 0 {
+		query.From = time.Unix(from, 0)
+	}
+	if to := c.QueryInt64("to"); to > 0 {
+		query.To = time.Unix(to, 0)
+	}
+	if query.Limit <= 0 {
+		query.Limit = defaultNotificationHistoryLimit
+	}
+
+	entries, err := srv.notificationHistory.GetNotificationHistory(c.Req.Context(), query)
+	if err != nil {
+		return ErrResp(http.StatusInternalServerError, err, "failed to get notification history")
+	}
+	// the payloads can include secrets of the contact points that are not redacted, such as tokens in URLs
+	canReadPayloads := accesscontrol.HasAccess(srv.ac, c)(accesscontrol.EvalPermission(accesscontrol.ActionAlertingReceiversReadSecrets))
+	result := make(apimodels.NotificationHistory, 0, len(entries))
+	for _, e := range entries {
+		if !canReadPayloads {
+			e.Payload = ""
+		}
+		result = append(result, ApiNotificationHistoryEntryFromNotificationHistoryEntry(e))
+	}
+	return response.JSON(http.StatusOK, result)
+}
@@ -0,0 +1,75 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/benbjohnson/clock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/services/accesscontrol"
+	contextmodel "github.com/grafana/grafana/pkg/services/contexthandler/model"
+	apimodels "github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+	ngmodels "github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/services/ngalert/notifier"
+	"github.com/grafana/grafana/pkg/services/user"
+	"github.com/grafana/grafana/pkg/web"
+)
+
+type fakeNotificationHistoryStore struct {
+	entries []ngmodels.NotificationHistoryEntry
+}
+
+func (f *fakeNotificationHistoryStore) SaveNotificationHistory(_ context.Context, entries []ngmodels.NotificationHistoryEntry) error {
+	f.entries = append(f.entries, entries...)
+	return nil
+}
+
+func (f *fakeNotificationHistoryStore) GetNotificationHistory(_ context.Context, _ ngmodels.NotificationHistoryQuery) ([]ngmodels.NotificationHistoryEntry, error) {
+	return f.entries, nil
+}
+
+func TestRouteGetNotificationHistory(t *testing.T) {
+	store := &fakeNotificationHistoryStore{entries: []ngmodels.NotificationHistoryEntry{
+		{OrgID: 1, Receiver: "team-a", Integration: "webhook", Status: ngmodels.NotificationStatusSuccess, Payload: `{"status":"firing"}`},
+	}}
+	sut := createSut(t)
+	sut.notificationHistory = notifier.NewNotificationHistorian(store, 1024, clock.NewMock(), log.NewNopLogger())
+
+	getHistory := func(t *testing.T, permissions map[string][]string) apimodels.NotificationHistory {
+		t.Helper()
+		rc := &contextmodel.ReqContext{
+			Context: &web.Context{
+				Req: httptest.NewRequest(http.MethodGet, "/api/alertmanager/grafana/api/v2/notifications/history", nil),
+			},
+			SignedInUser: &user.SignedInUser{
+				OrgID:       1,
+				Permissions: map[int64]map[string][]string{1: permissions},
+			},
+		}
+		response := sut.RouteGetNotificationHistory(rc)
+		require.Equal(t, http.StatusOK, response.Status())
+		var result apimodels.NotificationHistory
+		require.NoError(t, json.Unmarshal(response.Body(), &result))
+		require.Len(t, result, 1)
+		return result
+	}
+
+	t.Run("should not return the payloads without the permission to read the secrets of receivers", func(t *testing.T) {
+		result := getHistory(t, map[string][]string{accesscontrol.ActionAlertingNotificationsRead: nil})
+		require.Equal(t, "team-a", result[0].Receiver)
+		require.Empty(t, result[0].Payload)
+	})
+
+	t.Run("should return the payloads with the permission to read the secrets of receivers", func(t *testing.T) {
+		result := getHistory(t, map[string][]string{
+			accesscontrol.ActionAlertingNotificationsRead:    nil,
+			accesscontrol.ActionAlertingReceiversReadSecrets: nil,
+		})
+		require.Equal(t, `{"status":"firing"}`, result[0].Payload)
+	})
+}
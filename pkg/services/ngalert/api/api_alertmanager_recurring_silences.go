@@ -0,0 +1,74 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/grafana/grafana/pkg/api/response"
+	contextmodel "github.com/grafana/grafana/pkg/services/contexthandler/model"
+	apimodels "github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+	ngmodels "github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/services/ngalert/store"
+	"github.com/grafana/grafana/pkg/util"
+)
+
+func (srv AlertmanagerSrv) RouteGetRecurringSilences(c *contextmodel.ReqContext) response.Response {
+	silences, err := srv.recurringSilences.GetRecurringSilences(c.Req.Context(), c.SignedInUser.GetOrgID())
+	if err != nil {
+		return ErrResp(http.StatusInternalServerError, err, "failed to get recurring silences")
+	}
+	result := make(apimodels.RecurringSilences, 0, len(silences))
+	for _, s := range silences {
+		result = append(result, ApiRecurringSilenceFromRecurringSilence(s))
+	}
+	return response.JSON(http.StatusOK, result)
+}
+
+func (srv AlertmanagerSrv) RouteGetRecurringSilence(c *contextmodel.ReqContext, uid string) response.Response {
+	silence, err := srv.recurringSilences.GetRecurringSilence(c.Req.Context(), c.SignedInUser.GetOrgID(), uid)
+	if err != nil {
+		return recurringSilenceErrorResponse(err)
+	}
+	return response.JSON(http.StatusOK, ApiRecurringSilenceFromRecurringSilence(silence))
+}
+
+func (srv AlertmanagerSrv) RouteCreateRecurringSilence(c *contextmodel.ReqContext, body apimodels.RecurringSilence) response.Response {
+	silence := RecurringSilenceFromApiRecurringSilence(c.SignedInUser.GetOrgID(), body)
+	if silence.CreatedBy == "" {
+		silence.CreatedBy = c.SignedInUser.GetLogin()
+	}
+	created, err := srv.recurringSilences.CreateRecurringSilence(c.Req.Context(), silence)
+	if err != nil {
+		return recurringSilenceErrorResponse(err)
+	}
+	return response.JSON(http.StatusCreated, ApiRecurringSilenceFromRecurringSilence(created))
+}
+
+func (srv AlertmanagerSrv) RoutePutRecurringSilence(c *contextmodel.ReqContext, body apimodels.RecurringSilence, uid string) response.Response {
+	body.UID = uid
+	updated, err := srv.recurringSilences.UpdateRecurringSilence(c.Req.Context(), RecurringSilenceFromApiRecurringSilence(c.SignedInUser.GetOrgID(), body))
+	if err != nil {
+		return recurringSilenceErrorResponse(err)
+	}
+	return response.JSON(http.StatusOK, ApiRecurringSilenceFromRecurringSilence(updated))
+}
+
+func (srv AlertmanagerSrv) RouteDeleteRecurringSilence(c *contextmodel.ReqContext, uid string) response.Response {
+	if err := srv.recurringSilences.DeleteRecurringSilence(c.Req.Context(), c.SignedInUser.GetOrgID(), uid); err != nil {
+		return recurringSilenceErrorResponse(err)
+	}
+	return response.JSON(http.StatusOK, util.DynMap{"message": "recurring silence deleted"})
+}
+
+func recurringSilenceErrorResponse(err error) response.Response {
+	if errors.Is(err, ngmodels.ErrRecurringSilenceNotFound) {
+		return ErrResp(http.StatusNotFound, err, "")
+	}
+	if errors.Is(err, ngmodels.ErrRecurringSilenceFailedValidation) {
+		return ErrResp(http.StatusBadRequest, err, "")
+	}
+	if errors.Is(err, store.ErrOptimisticLock) {
+		return ErrResp(http.StatusConflict, err, "")
+	}
+	return ErrResp(http.StatusInternalServerError, err, "")
+}
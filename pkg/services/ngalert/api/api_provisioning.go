@@ -28,6 +28,7 @@ type ProvisioningSrv struct {
 	templates           TemplateService
 	muteTimings         MuteTimingService
 	alertRules          AlertRuleService
+	alertRuleTemplates  AlertRuleTemplateService
 }
 
 type ContactPointService interface {
@@ -56,6 +57,14 @@ type MuteTimingService interface {
 	DeleteMuteTiming(ctx context.Context, name string, orgID int64) error
 }
 
+type AlertRuleTemplateService interface {
+	GetTemplates(ctx context.Context, orgID int64) ([]*alerting_models.AlertRuleTemplate, map[string]alerting_models.Provenance, error)
+	GetTemplate(ctx context.Context, orgID int64, uid string) (alerting_models.AlertRuleTemplate, alerting_models.Provenance, error)
+	CreateTemplate(ctx context.Context, template alerting_models.AlertRuleTemplate, provenance alerting_models.Provenance) (alerting_models.AlertRuleTemplate, error)
+	UpdateTemplate(ctx context.Context, template alerting_models.AlertRuleTemplate, provenance alerting_models.Provenance, u *user.SignedInUser) (alerting_models.AlertRuleTemplate, error)
+	DeleteTemplate(ctx context.Context, orgID int64, uid string, provenance alerting_models.Provenance) error
+}
+
 type AlertRuleService interface {
 	GetAlertRules(ctx context.Context, orgID int64) ([]*alerting_models.AlertRule, map[string]alerting_models.Provenance, error)
 	GetAlertRule(ctx context.Context, orgID int64, ruleUID string) (alerting_models.AlertRule, alerting_models.Provenance, error)
@@ -494,6 +503,97 @@ func (srv *ProvisioningSrv) RoutePutAlertRuleGroup(c *contextmodel.ReqContext, a
 	return response.JSON(http.StatusOK, ag)
 }
 
+func (srv *ProvisioningSrv) RouteGetAlertRuleTemplates(c *contextmodel.ReqContext) response.Response {
+	templates, provenances, err := srv.alertRuleTemplates.GetTemplates(c.Req.Context(), c.SignedInUser.GetOrgID())
+	if err != nil {
+		return ErrResp(http.StatusInternalServerError, err, "")
+	}
+	result := make(definitions.AlertRuleTemplates, 0, len(templates))
+	for _, template := range templates {
+		provenance := alerting_models.ProvenanceNone
+		if prov, exists := provenances[template.ResourceID()]; exists {
+			provenance = prov
+		}
+		result = append(result, ApiAlertRuleTemplateFromAlertRuleTemplate(*template, provenance))
+	}
+	return response.JSON(http.StatusOK, result)
+}
+
+func (srv *ProvisioningSrv) RouteGetAlertRuleTemplate(c *contextmodel.ReqContext, UID string) response.Response {
+	template, provenance, err := srv.alertRuleTemplates.GetTemplate(c.Req.Context(), c.SignedInUser.GetOrgID(), UID)
+	if err != nil {
+		if errors.Is(err, alerting_models.ErrAlertRuleTemplateNotFound) {
+			return response.Empty(http.StatusNotFound)
+		}
+		return ErrResp(http.StatusInternalServerError, err, "")
+	}
+	return response.JSON(http.StatusOK, ApiAlertRuleTemplateFromAlertRuleTemplate(template, provenance))
+}
+
+// RouteGetAlertRuleTemplatesExport retrieves all alert rule templates in a format compatible with file provisioning.
+func (srv *ProvisioningSrv) RouteGetAlertRuleTemplatesExport(c *contextmodel.ReqContext) response.Response {
+	if extractExportRequest(c).Format == "hcl" {
+		return ErrResp(http.StatusBadRequest, errors.New("alert rule templates cannot be exported to HCL"), "")
+	}
+	templates, _, err := srv.alertRuleTemplates.GetTemplates(c.Req.Context(), c.SignedInUser.GetOrgID())
+	if err != nil {
+		return ErrResp(http.StatusInternalServerError, err, "failed to get alert rule templates")
+	}
+	e, err := AlertingFileExportFromAlertRuleTemplates(templates)
+	if err != nil {
+		return ErrResp(http.StatusInternalServerError, err, "failed to create alerting file export")
+	}
+	return exportResponse(c, e)
+}
+
+func (srv *ProvisioningSrv) RoutePostAlertRuleTemplate(c *contextmodel.ReqContext, t definitions.AlertRuleTemplate) response.Response {
+	template := AlertRuleTemplateFromApiAlertRuleTemplate(t)
+	template.OrgID = c.SignedInUser.GetOrgID()
+	provenance := determineProvenance(c)
+	created, err := srv.alertRuleTemplates.CreateTemplate(c.Req.Context(), template, alerting_models.Provenance(provenance))
+	if err != nil {
+		return alertRuleTemplateErrorResponse(err)
+	}
+	return response.JSON(http.StatusCreated, ApiAlertRuleTemplateFromAlertRuleTemplate(created, alerting_models.Provenance(provenance)))
+}
+
+func (srv *ProvisioningSrv) RoutePutAlertRuleTemplate(c *contextmodel.ReqContext, t definitions.AlertRuleTemplate, UID string) response.Response {
+	template := AlertRuleTemplateFromApiAlertRuleTemplate(t)
+	template.OrgID = c.SignedInUser.GetOrgID()
+	template.UID = UID
+	provenance := determineProvenance(c)
+	updated, err := srv.alertRuleTemplates.UpdateTemplate(c.Req.Context(), template, alerting_models.Provenance(provenance), c.SignedInUser)
+	if err != nil {
+		return alertRuleTemplateErrorResponse(err)
+	}
+	return response.JSON(http.StatusOK, ApiAlertRuleTemplateFromAlertRuleTemplate(updated, alerting_models.Provenance(provenance)))
+}
+
+func (srv *ProvisioningSrv) RouteDeleteAlertRuleTemplate(c *contextmodel.ReqContext, UID string) response.Response {
+	provenance := determineProvenance(c)
+	err := srv.alertRuleTemplates.DeleteTemplate(c.Req.Context(), c.SignedInUser.GetOrgID(), UID, alerting_models.Provenance(provenance))
+	if err != nil {
+		return alertRuleTemplateErrorResponse(err)
+	}
+	return response.JSON(http.StatusNoContent, "")
+}
+
+func alertRuleTemplateErrorResponse(err error) response.Response {
+	switch {
+	case errors.Is(err, alerting_models.ErrAlertRuleTemplateNotFound):
+		return response.Empty(http.StatusNotFound)
+	case errors.Is(err, alerting_models.ErrAlertRuleTemplateFailedValidation),
+		errors.Is(err, alerting_models.ErrAlertRuleFailedValidation):
+		return ErrResp(http.StatusBadRequest, err, "")
+	case errors.Is(err, alerting_models.ErrAlertRuleTemplateInUse),
+		errors.Is(err, store.ErrOptimisticLock):
+		return ErrResp(http.StatusConflict, err, "")
+	case errors.Is(err, provisioning.ErrPermissionDenied):
+		return ErrResp(http.StatusForbidden, err, "")
+	}
+	return ErrResp(http.StatusInternalServerError, err, "")
+}
+
 func determineProvenance(ctx *contextmodel.ReqContext) definitions.Provenance {
 	if _, disabled := ctx.Req.Header[disableProvenanceHeaderName]; disabled {
 		return definitions.Provenance(alerting_models.ProvenanceNone)
@@ -19,6 +19,7 @@ import (
 	apimodels "github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
 	"github.com/grafana/grafana/pkg/services/ngalert/eval"
 	ngmodels "github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/services/ngalert/notifier"
 	"github.com/grafana/grafana/pkg/services/ngalert/provisioning"
 	"github.com/grafana/grafana/pkg/services/ngalert/store"
 	"github.com/grafana/grafana/pkg/services/quota"
@@ -41,6 +42,7 @@ type RulerSrv struct {
 	cfg                *setting.UnifiedAlertingSettings
 	ac                 accesscontrol.AccessControl
 	conditionValidator ConditionValidator
+	amConfigStore      AlertingStore
 }
 
 var (
@@ -267,6 +269,9 @@ func (srv RulerSrv) updateAlertRulesInGroup(c *contextmodel.ReqContext, groupKey
 	hasAccess := accesscontrol.HasAccess(srv.ac, c)
 	err := srv.xactManager.InTransaction(c.Req.Context(), func(tranCtx context.Context) error {
 		logger := srv.log.New("namespace_uid", groupKey.NamespaceUID, "group", groupKey.RuleGroup, "org_id", groupKey.OrgID, "user_id", c.UserID)
+		if err := applyAlertRuleTemplates(tranCtx, srv.store, groupKey.OrgID, rules); err != nil {
+			return err
+		}
 		groupChanges, err := store.CalculateChanges(tranCtx, srv.store, groupKey, rules)
 		if err != nil {
 			return err
@@ -289,6 +294,10 @@ func (srv RulerSrv) updateAlertRulesInGroup(c *contextmodel.ReqContext, groupKey
 			return err
 		}
 
+		if err := srv.validateNotificationSettings(c.Req.Context(), c.SignedInUser.GetOrgID(), groupChanges); err != nil {
+			return err
+		}
+
 		if err := verifyProvisionedRulesNotAffected(c.Req.Context(), srv.provenanceStore, c.SignedInUser.GetOrgID(), groupChanges); err != nil {
 			return err
 		}
@@ -401,16 +410,19 @@ func toGettableRuleGroupConfig(groupName string, rules ngmodels.RulesGroup, name
 	rules.SortByGroupIndex()
 	ruleNodes := make([]apimodels.GettableExtendedRuleNode, 0, len(rules))
 	var interval time.Duration
+	var sequential bool
 	if len(rules) > 0 {
 		interval = time.Duration(rules[0].IntervalSeconds) * time.Second
+		sequential = rules[0].Sequential
 	}
 	for _, r := range rules {
 		ruleNodes = append(ruleNodes, toGettableExtendedRuleNode(*r, namespaceID, provenanceRecords))
 	}
 	return apimodels.GettableRuleGroupConfig{
-		Name:     groupName,
-		Interval: model.Duration(interval),
-		Rules:    ruleNodes,
+		Name:       groupName,
+		Interval:   model.Duration(interval),
+		Sequential: sequential,
+		Rules:      ruleNodes,
 	}
 }
 
@@ -421,22 +433,25 @@ func toGettableExtendedRuleNode(r ngmodels.AlertRule, namespaceID int64, provena
 	}
 	gettableExtendedRuleNode := apimodels.GettableExtendedRuleNode{
 		GrafanaManagedAlert: &apimodels.GettableGrafanaRule{
-			ID:              r.ID,
-			OrgID:           r.OrgID,
-			Title:           r.Title,
-			Condition:       r.Condition,
-			Data:            ApiAlertQueriesFromAlertQueries(r.Data),
-			Updated:         r.Updated,
-			IntervalSeconds: r.IntervalSeconds,
-			Version:         r.Version,
-			UID:             r.UID,
-			NamespaceUID:    r.NamespaceUID,
-			NamespaceID:     namespaceID,
-			RuleGroup:       r.RuleGroup,
-			NoDataState:     apimodels.NoDataState(r.NoDataState),
-			ExecErrState:    apimodels.ExecutionErrorState(r.ExecErrState),
-			Provenance:      apimodels.Provenance(provenance),
-			IsPaused:        r.IsPaused,
+			ID:                   r.ID,
+			OrgID:                r.OrgID,
+			Title:                r.Title,
+			Condition:            r.Condition,
+			Data:                 ApiAlertQueriesFromAlertQueries(r.Data),
+			Updated:              r.Updated,
+			IntervalSeconds:      r.IntervalSeconds,
+			Version:              r.Version,
+			UID:                  r.UID,
+			NamespaceUID:         r.NamespaceUID,
+			NamespaceID:          namespaceID,
+			RuleGroup:            r.RuleGroup,
+			NoDataState:          apimodels.NoDataState(r.NoDataState),
+			ExecErrState:         apimodels.ExecutionErrorState(r.ExecErrState),
+			Provenance:           apimodels.Provenance(provenance),
+			IsPaused:             r.IsPaused,
+			Record:               ApiRecordFromModelRecord(r.Record),
+			NotificationSettings: ApiNotificationSettingsFromModelNotificationSettings(r.NotificationSettings),
+			Template:             ApiAlertRuleTemplateRefFromModelAlertRuleTemplateRef(r.Template),
 		},
 	}
 	forDuration := model.Duration(r.For)
@@ -445,6 +460,10 @@ func toGettableExtendedRuleNode(r ngmodels.AlertRule, namespaceID int64, provena
 		Annotations: r.Annotations,
 		Labels:      r.Labels,
 	}
+	if r.KeepFiringFor > 0 {
+		keepFiringFor := model.Duration(r.KeepFiringFor)
+		gettableExtendedRuleNode.ApiRuleNode.KeepFiringFor = &keepFiringFor
+	}
 	return gettableExtendedRuleNode
 }
 
@@ -501,6 +520,50 @@ func validateQueries(ctx context.Context, groupChanges *store.GroupDelta, valida
 	return nil
 }
 
+// applyAlertRuleTemplates sets the condition, the queries and the annotations of the rules instantiated from a template.
+func applyAlertRuleTemplates(ctx context.Context, templates provisioning.AlertRuleTemplateGetter, orgID int64, rules []*ngmodels.AlertRuleWithOptionals) error {
+	toApply := make([]*ngmodels.AlertRule, 0, len(rules))
+	for _, rule := range rules {
+		toApply = append(toApply, &rule.AlertRule)
+	}
+	return provisioning.ApplyAlertRuleTemplates(ctx, templates, orgID, toApply...)
+}
+
+// validateNotificationSettings checks that the notification settings of the new and updated rules refer to
+// contact points and mute timings of the Alertmanager configuration of the organization.
+func (srv RulerSrv) validateNotificationSettings(ctx context.Context, orgID int64, groupChanges *store.GroupDelta) error {
+	rules := make([]*ngmodels.AlertRule, 0, len(groupChanges.New)+len(groupChanges.Update))
+	for _, rule := range groupChanges.New {
+		if rule.NotificationSettings != nil {
+			rules = append(rules, rule)
+		}
+	}
+	for _, upd := range groupChanges.Update {
+		if upd.New.NotificationSettings != nil {
+			rules = append(rules, upd.New)
+		}
+	}
+	if len(rules) == 0 {
+		return nil
+	}
+
+	amConfig, err := srv.amConfigStore.GetLatestAlertmanagerConfiguration(ctx, &ngmodels.GetLatestAlertmanagerConfigurationQuery{OrgID: orgID})
+	if err != nil {
+		return fmt.Errorf("failed to get the Alertmanager configuration: %w", err)
+	}
+	cfg, err := notifier.Load([]byte(amConfig.AlertmanagerConfiguration))
+	if err != nil {
+		return fmt.Errorf("failed to parse the Alertmanager configuration: %w", err)
+	}
+	validator := provisioning.NewNotificationSettingsValidator(&cfg.AlertmanagerConfig)
+	for _, rule := range rules {
+		if err := validator.Validate(*rule.NotificationSettings); err != nil {
+			return fmt.Errorf("invalid alert rule '%s': %w", rule.Title, err)
+		}
+	}
+	return nil
+}
+
 // getAuthorizedRuleByUid fetches all rules in group to which the specified rule belongs, and checks whether the user is authorized to access the group.
 // A user is authorized to access a group of rules only when it has permission to query all data sources used by all rules in this group.
 // Returns rule identified by provided UID or ErrAuthorization if user is not authorized to access the rule.
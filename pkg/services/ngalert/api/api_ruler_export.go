@@ -28,6 +28,13 @@ func (srv RulerSrv) ExportFromPayload(c *contextmodel.ReqContext, ruleGroupConfi
 		return ErrResp(http.StatusBadRequest, err, "")
 	}
 
+	if err := applyAlertRuleTemplates(c.Req.Context(), srv.store, c.SignedInUser.OrgID, rulesWithOptionals); err != nil {
+		if errors.Is(err, ngmodels.ErrAlertRuleFailedValidation) {
+			return ErrResp(http.StatusBadRequest, err, "")
+		}
+		return ErrResp(http.StatusInternalServerError, err, "failed to apply alert rule templates")
+	}
+
 	rules := make([]ngmodels.AlertRule, 0, len(rulesWithOptionals))
 	for _, optional := range rulesWithOptionals {
 		rules = append(rules, optional.AlertRule)
@@ -23,6 +23,7 @@ import (
 	"github.com/grafana/grafana/pkg/services/folder"
 	apimodels "github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
 	"github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/services/ngalert/notifier"
 	"github.com/grafana/grafana/pkg/services/ngalert/provisioning"
 	"github.com/grafana/grafana/pkg/services/ngalert/store"
 	"github.com/grafana/grafana/pkg/services/ngalert/tests/fakes"
@@ -670,3 +671,99 @@ func withGroupKey(groupKey models.AlertRuleGroupKey) func(rule *models.AlertRule
 		rule.NamespaceUID = groupKey.NamespaceUID
 	}
 }
+
+func TestValidateNotificationSettings(t *testing.T) {
+	amConfig := `{
+		"alertmanager_config": {
+			"route": {"receiver": "default"},
+			"mute_time_intervals": [{"name": "weekends"}],
+			"receivers": [
+				{"name": "default", "grafana_managed_receiver_configs": []},
+				{"name": "slack", "grafana_managed_receiver_configs": []}
+			]
+		}
+	}`
+	configStore := notifier.NewFakeConfigStore(t, map[int64]*models.AlertConfiguration{
+		1: {AlertmanagerConfiguration: amConfig, OrgID: 1},
+	})
+	svc := createService(fakes.NewRuleStore(t))
+	svc.amConfigStore = configStore
+
+	deltaWithSettings := func(settings models.NotificationSettings) *store.GroupDelta {
+		return &store.GroupDelta{
+			New: []*models.AlertRule{models.AlertRuleGen(models.WithOrgID(1), models.WithNotificationSettings(settings))()},
+		}
+	}
+
+	t.Run("should accept settings that refer to existing contact points and mute timings", func(t *testing.T) {
+		err := svc.validateNotificationSettings(context.Background(), 1, deltaWithSettings(models.NotificationSettings{
+			Receiver:          "slack",
+			MuteTimeIntervals: []string{"weekends"},
+		}))
+		require.NoError(t, err)
+	})
+
+	t.Run("should fail if the contact point does not exist", func(t *testing.T) {
+		err := svc.validateNotificationSettings(context.Background(), 1, deltaWithSettings(models.NotificationSettings{Receiver: "email"}))
+		require.ErrorIs(t, err, models.ErrAlertRuleFailedValidation)
+		require.ErrorContains(t, err, "email")
+	})
+
+	t.Run("should fail if the mute timing does not exist", func(t *testing.T) {
+		err := svc.validateNotificationSettings(context.Background(), 1, deltaWithSettings(models.NotificationSettings{
+			Receiver:          "slack",
+			MuteTimeIntervals: []string{"nights"},
+		}))
+		require.ErrorIs(t, err, models.ErrAlertRuleFailedValidation)
+		require.ErrorContains(t, err, "nights")
+	})
+
+	t.Run("should not read the configuration if no rule has notification settings", func(t *testing.T) {
+		rule := models.AlertRuleGen(models.WithOrgID(2))()
+		rule.NotificationSettings = nil
+		err := svc.validateNotificationSettings(context.Background(), 2, &store.GroupDelta{New: []*models.AlertRule{rule}})
+		require.NoError(t, err)
+	})
+}
+
+func TestApplyAlertRuleTemplates(t *testing.T) {
+	ruleStore := fakes.NewRuleStore(t)
+	ruleStore.Templates[1] = []*models.AlertRuleTemplate{{
+		OrgID:     1,
+		UID:       "high-cpu",
+		Title:     "High CPU",
+		Condition: "A",
+		Data: []models.AlertQuery{
+			{RefID: "A", DatasourceUID: "prometheus", Model: json.RawMessage(`{"expr":"cpu{service=\"${service}\"}"}`)},
+		},
+		Annotations: map[string]string{"summary": "CPU of ${service}"},
+		Parameters:  []models.AlertRuleTemplateParameter{{Name: "service", Type: models.AlertRuleTemplateParameterTypeString}},
+	}}
+
+	t.Run("should instantiate the rules that refer to a template", func(t *testing.T) {
+		instance := models.AlertRuleGen(models.WithOrgID(1), models.WithTemplate("high-cpu", map[string]string{"service": "api"}))()
+		other := models.AlertRuleGen(models.WithOrgID(1))()
+		otherData := other.Data
+
+		rules := []*models.AlertRuleWithOptionals{{AlertRule: *instance}, {AlertRule: *other}}
+		require.NoError(t, applyAlertRuleTemplates(context.Background(), ruleStore, 1, rules))
+
+		require.Equal(t, "A", rules[0].Condition)
+		require.Len(t, rules[0].Data, 1)
+		require.JSONEq(t, `{"expr":"cpu{service=\"api\"}"}`, string(rules[0].Data[0].Model))
+		require.Equal(t, "CPU of api", rules[0].Annotations["summary"])
+		require.Equal(t, otherData, rules[1].Data)
+	})
+
+	t.Run("should fail validation if the template does not exist", func(t *testing.T) {
+		instance := models.AlertRuleGen(models.WithOrgID(1), models.WithTemplate("unknown", nil))()
+		err := applyAlertRuleTemplates(context.Background(), ruleStore, 1, []*models.AlertRuleWithOptionals{{AlertRule: *instance}})
+		require.ErrorIs(t, err, models.ErrAlertRuleFailedValidation)
+	})
+
+	t.Run("should fail validation if a required parameter is missing", func(t *testing.T) {
+		instance := models.AlertRuleGen(models.WithOrgID(1), models.WithTemplate("high-cpu", nil))()
+		err := applyAlertRuleTemplates(context.Background(), ruleStore, 1, []*models.AlertRuleWithOptionals{{AlertRule: *instance}})
+		require.ErrorIs(t, err, models.ErrAlertRuleFailedValidation)
+	})
+}
@@ -67,7 +67,31 @@ func validateRuleNode(
 		}
 	}
 
-	if len(ruleNode.GrafanaManagedAlert.Data) == 0 {
+	condition := ruleNode.GrafanaManagedAlert.Condition
+	record := ModelRecordFromApiRecord(ruleNode.GrafanaManagedAlert.Record)
+	if record != nil {
+		if !cfg.RecordingRules.Enabled {
+			return nil, fmt.Errorf("%w: recording rules are not enabled", ngmodels.ErrAlertRuleFailedValidation)
+		}
+		// the condition of a recording rule is the query or expression it writes
+		if condition == "" {
+			condition = record.From
+		}
+	}
+
+	data := ruleNode.GrafanaManagedAlert.Data
+	template := ModelAlertRuleTemplateRefFromApiAlertRuleTemplateRef(ruleNode.GrafanaManagedAlert.Template)
+	if template != nil {
+		if template.UID == "" {
+			return nil, fmt.Errorf("%w: template UID is not specified", ngmodels.ErrAlertRuleFailedValidation)
+		}
+		if record != nil {
+			return nil, fmt.Errorf("%w: recording rules cannot be instantiated from a template", ngmodels.ErrAlertRuleFailedValidation)
+		}
+		// the condition and the queries are replaced by the ones of the template when the rule group is saved
+		condition = ""
+		data = nil
+	} else if len(data) == 0 {
 		if canPatch {
 			if ruleNode.GrafanaManagedAlert.Condition != "" {
 				return nil, fmt.Errorf("%w: query is not specified by condition is. You must specify both query and condition to update existing alert rule", ngmodels.ErrAlertRuleFailedValidation)
@@ -76,18 +100,23 @@ func validateRuleNode(
 			return nil, fmt.Errorf("%w: no queries or expressions are found", ngmodels.ErrAlertRuleFailedValidation)
 		}
 	} else {
-		err = validateCondition(ruleNode.GrafanaManagedAlert.Condition, ruleNode.GrafanaManagedAlert.Data)
+		err = validateCondition(condition, data)
 		if err != nil {
 			return nil, fmt.Errorf("%w: %s", ngmodels.ErrAlertRuleFailedValidation, err.Error())
 		}
 	}
 
-	queries := AlertQueriesFromApiAlertQueries(ruleNode.GrafanaManagedAlert.Data)
+	queries := AlertQueriesFromApiAlertQueries(data)
+	if record != nil && len(queries) > 0 {
+		if err := record.Validate(queries); err != nil {
+			return nil, err
+		}
+	}
 
 	newAlertRule := ngmodels.AlertRule{
 		OrgID:           orgId,
 		Title:           ruleNode.GrafanaManagedAlert.Title,
-		Condition:       ruleNode.GrafanaManagedAlert.Condition,
+		Condition:       condition,
 		Data:            queries,
 		UID:             ruleNode.GrafanaManagedAlert.UID,
 		IntervalSeconds: intervalSeconds,
@@ -95,6 +124,8 @@ func validateRuleNode(
 		RuleGroup:       groupName,
 		NoDataState:     noDataState,
 		ExecErrState:    errorState,
+		Record:          record,
+		Template:        template,
 	}
 
 	newAlertRule.For, err = validateForInterval(ruleNode)
@@ -102,6 +133,32 @@ func validateRuleNode(
 		return nil, err
 	}
 
+	newAlertRule.KeepFiringFor, err = validateKeepFiringForInterval(ruleNode)
+	if err != nil {
+		return nil, err
+	}
+
+	if record != nil {
+		if newAlertRule.For > 0 {
+			return nil, fmt.Errorf("%w: recording rules cannot have field `for`", ngmodels.ErrAlertRuleFailedValidation)
+		}
+		if newAlertRule.KeepFiringFor > 0 {
+			return nil, fmt.Errorf("%w: recording rules cannot have field `keep_firing_for`", ngmodels.ErrAlertRuleFailedValidation)
+		}
+		newAlertRule.For = 0
+		newAlertRule.KeepFiringFor = 0
+	}
+
+	if ruleNode.GrafanaManagedAlert.NotificationSettings != nil {
+		if record != nil {
+			return nil, fmt.Errorf("%w: recording rules cannot have notification settings", ngmodels.ErrAlertRuleFailedValidation)
+		}
+		newAlertRule.NotificationSettings = ModelNotificationSettingsFromApiNotificationSettings(ruleNode.GrafanaManagedAlert.NotificationSettings)
+		if err := newAlertRule.NotificationSettings.Validate(); err != nil {
+			return nil, err
+		}
+	}
+
 	if ruleNode.ApiRuleNode != nil {
 		newAlertRule.Annotations = ruleNode.ApiRuleNode.Annotations
 		newAlertRule.Labels = ruleNode.ApiRuleNode.Labels
@@ -173,6 +230,21 @@ func validateForInterval(ruleNode *apimodels.PostableExtendedRuleNode) (time.Dur
 	return duration, nil
 }
 
+// validateKeepFiringForInterval validates ApiRuleNode.KeepFiringFor and converts it to time.Duration. If the field is not specified returns 0 if GrafanaManagedAlert.UID is empty and -1 if it is not.
+func validateKeepFiringForInterval(ruleNode *apimodels.PostableExtendedRuleNode) (time.Duration, error) {
+	if ruleNode.ApiRuleNode == nil || ruleNode.ApiRuleNode.KeepFiringFor == nil {
+		if ruleNode.GrafanaManagedAlert.UID != "" {
+			return -1, nil // will be patched later with the real value of the current version of the rule
+		}
+		return 0, nil
+	}
+	duration := time.Duration(*ruleNode.ApiRuleNode.KeepFiringFor)
+	if duration < 0 {
+		return 0, fmt.Errorf("field `keep_firing_for` cannot be negative [%v]. 0 or any positive duration are allowed", *ruleNode.ApiRuleNode.KeepFiringFor)
+	}
+	return duration, nil
+}
+
 // validateRuleGroup validates API model (definitions.PostableRuleGroupConfig) and converts it to a collection of models.AlertRule.
 // Returns a slice that contains all rules described by API model or error if either group specification or an alert definition is not valid.
 // It also returns a map containing current existing alerts that don't contain the is_paused field in the body of the call.
@@ -228,10 +300,19 @@ func validateRuleGroup(
 		ruleWithOptionals := ngmodels.AlertRuleWithOptionals{}
 		rule.IsPaused = isPaused
 		rule.RuleGroupIndex = idx + 1
+		rule.Sequential = ruleGroupConfig.Sequential
 		ruleWithOptionals.AlertRule = *rule
 		ruleWithOptionals.HasPause = hasPause
 
 		result = append(result, &ruleWithOptionals)
 	}
+
+	rules := make([]*ngmodels.AlertRule, 0, len(result))
+	for _, r := range result {
+		rules = append(rules, &r.AlertRule)
+	}
+	if err := ngmodels.ValidateRuleResultQueries(rules); err != nil {
+		return nil, err
+	}
 	return result, nil
 }
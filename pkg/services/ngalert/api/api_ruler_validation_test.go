@@ -761,6 +761,82 @@ func TestValidateRuleNodeFailures_UID(t *testing.T) {
 	}
 }
 
+func TestValidateRuleNode_Record(t *testing.T) {
+	orgId := rand.Int63()
+	folder := randFolder()
+	cfg := config(t)
+	cfg.RecordingRules.Enabled = true
+
+	recordingRule := func() *apimodels.PostableExtendedRuleNode {
+		r := validRule()
+		r.ApiRuleNode.For = nil
+		r.GrafanaManagedAlert.Condition = ""
+		r.GrafanaManagedAlert.Record = &apimodels.Record{Metric: "test_metric", From: "A"}
+		return &r
+	}
+
+	t.Run("accepts a recording rule", func(t *testing.T) {
+		r := recordingRule()
+		alert, err := validateRuleNode(r, "", cfg.BaseInterval, orgId, folder, cfg)
+		require.NoError(t, err)
+		require.Equal(t, &models.Record{Metric: "test_metric", From: "A"}, alert.Record)
+		require.Equal(t, "A", alert.Condition)
+		require.Zero(t, alert.For)
+		require.Equal(t, models.RuleTypeRecording, alert.Type())
+	})
+
+	testCases := []struct {
+		name string
+		rule func() *apimodels.PostableExtendedRuleNode
+		cfg  func(cfg setting.UnifiedAlertingSettings) *setting.UnifiedAlertingSettings
+	}{
+		{
+			name: "fail if recording rules are disabled",
+			rule: recordingRule,
+			cfg: func(cfg setting.UnifiedAlertingSettings) *setting.UnifiedAlertingSettings {
+				cfg.RecordingRules.Enabled = false
+				return &cfg
+			},
+		},
+		{
+			name: "fail if metric name is invalid",
+			rule: func() *apimodels.PostableExtendedRuleNode {
+				r := recordingRule()
+				r.GrafanaManagedAlert.Record.Metric = "test metric"
+				return r
+			},
+		},
+		{
+			name: "fail if from does not refer to a query",
+			rule: func() *apimodels.PostableExtendedRuleNode {
+				r := recordingRule()
+				r.GrafanaManagedAlert.Record.From = "B"
+				return r
+			},
+		},
+		{
+			name: "fail if for is set",
+			rule: func() *apimodels.PostableExtendedRuleNode {
+				r := recordingRule()
+				forDuration := model.Duration(time.Minute)
+				r.ApiRuleNode.For = &forDuration
+				return r
+			},
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			c := cfg
+			if testCase.cfg != nil {
+				c = testCase.cfg(*cfg)
+			}
+			_, err := validateRuleNode(testCase.rule(), "", c.BaseInterval, orgId, folder, c)
+			require.ErrorIs(t, err, models.ErrAlertRuleFailedValidation)
+		})
+	}
+}
+
 func TestValidateRuleNodeIntervalFailures(t *testing.T) {
 	cfg := config(t)
 
@@ -106,6 +106,16 @@ func (api *API) authorize(method, path string) web.Handler {
 		// additional authorization is done in the request handler
 		eval = ac.EvalAny(ac.EvalPermission(ac.ActionAlertingInstanceCreate), ac.EvalPermission(ac.ActionAlertingInstanceUpdate))
 
+	// Recurring silences. Grafana Paths
+	case http.MethodGet + "/api/alertmanager/grafana/api/v2/recurring-silences",
+		http.MethodGet + "/api/alertmanager/grafana/api/v2/recurring-silence/{RecurringSilenceUID}":
+		eval = ac.EvalPermission(ac.ActionAlertingInstanceRead)
+	case http.MethodPost + "/api/alertmanager/grafana/api/v2/recurring-silences":
+		eval = ac.EvalPermission(ac.ActionAlertingInstanceCreate)
+	case http.MethodPut + "/api/alertmanager/grafana/api/v2/recurring-silence/{RecurringSilenceUID}",
+		http.MethodDelete + "/api/alertmanager/grafana/api/v2/recurring-silence/{RecurringSilenceUID}":
+		eval = ac.EvalPermission(ac.ActionAlertingInstanceUpdate)
+
 	// Alert Instances. Grafana Paths
 	case http.MethodGet + "/api/alertmanager/grafana/api/v2/alerts/groups":
 		eval = ac.EvalPermission(ac.ActionAlertingInstanceRead)
@@ -156,6 +166,8 @@ func (api *API) authorize(method, path string) web.Handler {
 		eval = ac.EvalAny(ac.EvalPermission(ac.ActionAlertingNotificationsWrite))
 	case http.MethodGet + "/api/alertmanager/grafana/config/api/v1/receivers":
 		eval = ac.EvalPermission(ac.ActionAlertingNotificationsRead)
+	case http.MethodGet + "/api/alertmanager/grafana/api/v2/notifications/history":
+		eval = ac.EvalPermission(ac.ActionAlertingNotificationsRead)
 	case http.MethodPost + "/api/alertmanager/grafana/config/api/v1/receivers/test":
 		eval = ac.EvalPermission(ac.ActionAlertingNotificationsWrite)
 	case http.MethodPost + "/api/alertmanager/grafana/config/api/v1/templates/test":
@@ -208,7 +220,10 @@ func (api *API) authorize(method, path string) web.Handler {
 		http.MethodGet + "/api/v1/provisioning/alert-rules/export",
 		http.MethodGet + "/api/v1/provisioning/alert-rules/{UID}/export",
 		http.MethodGet + "/api/v1/provisioning/folder/{FolderUID}/rule-groups/{Group}",
-		http.MethodGet + "/api/v1/provisioning/folder/{FolderUID}/rule-groups/{Group}/export":
+		http.MethodGet + "/api/v1/provisioning/folder/{FolderUID}/rule-groups/{Group}/export",
+		http.MethodGet + "/api/v1/provisioning/alert-rule-templates",
+		http.MethodGet + "/api/v1/provisioning/alert-rule-templates/export",
+		http.MethodGet + "/api/v1/provisioning/alert-rule-templates/{UID}":
 		eval = ac.EvalAny(ac.EvalPermission(ac.ActionAlertingProvisioningRead), ac.EvalPermission(ac.ActionAlertingProvisioningReadSecrets)) // organization scope
 
 	case http.MethodPut + "/api/v1/provisioning/policies",
@@ -224,7 +239,10 @@ func (api *API) authorize(method, path string) web.Handler {
 		http.MethodPost + "/api/v1/provisioning/alert-rules",
 		http.MethodPut + "/api/v1/provisioning/alert-rules/{UID}",
 		http.MethodDelete + "/api/v1/provisioning/alert-rules/{UID}",
-		http.MethodPut + "/api/v1/provisioning/folder/{FolderUID}/rule-groups/{Group}":
+		http.MethodPut + "/api/v1/provisioning/folder/{FolderUID}/rule-groups/{Group}",
+		http.MethodPost + "/api/v1/provisioning/alert-rule-templates",
+		http.MethodPut + "/api/v1/provisioning/alert-rule-templates/{UID}",
+		http.MethodDelete + "/api/v1/provisioning/alert-rule-templates/{UID}":
 		eval = ac.EvalPermission(ac.ActionAlertingProvisioningWrite) // organization scope
 	}
 
@@ -243,6 +261,10 @@ func authorizeDatasourceAccessForRule(rule *ngmodels.AlertRule, evaluator func(e
 			OldDatasourceUID {
 			continue
 		}
+		// the query reads the results of another rule of the group instead of a data source
+		if query.IsRuleResult() {
+			continue
+		}
 		if !evaluator(ac.EvalPermission(datasources.ActionQuery, datasources.ScopeProvider.GetResourceScopeUID(query.DatasourceUID))) {
 			return false
 		}
@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"time"
 
+	amv2 "github.com/prometheus/alertmanager/api/v2/models"
 	"github.com/prometheus/common/model"
 
 	"github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
@@ -14,43 +15,51 @@ import (
 // AlertRuleFromProvisionedAlertRule converts definitions.ProvisionedAlertRule to models.AlertRule
 func AlertRuleFromProvisionedAlertRule(a definitions.ProvisionedAlertRule) (models.AlertRule, error) {
 	return models.AlertRule{
-		ID:           a.ID,
-		UID:          a.UID,
-		OrgID:        a.OrgID,
-		NamespaceUID: a.FolderUID,
-		RuleGroup:    a.RuleGroup,
-		Title:        a.Title,
-		Condition:    a.Condition,
-		Data:         AlertQueriesFromApiAlertQueries(a.Data),
-		Updated:      a.Updated,
-		NoDataState:  models.NoDataState(a.NoDataState),          // TODO there must be a validation
-		ExecErrState: models.ExecutionErrorState(a.ExecErrState), // TODO there must be a validation
-		For:          time.Duration(a.For),
-		Annotations:  a.Annotations,
-		Labels:       a.Labels,
-		IsPaused:     a.IsPaused,
+		ID:                   a.ID,
+		UID:                  a.UID,
+		OrgID:                a.OrgID,
+		NamespaceUID:         a.FolderUID,
+		RuleGroup:            a.RuleGroup,
+		Title:                a.Title,
+		Condition:            a.Condition,
+		Data:                 AlertQueriesFromApiAlertQueries(a.Data),
+		Updated:              a.Updated,
+		NoDataState:          models.NoDataState(a.NoDataState),          // TODO there must be a validation
+		ExecErrState:         models.ExecutionErrorState(a.ExecErrState), // TODO there must be a validation
+		For:                  time.Duration(a.For),
+		KeepFiringFor:        time.Duration(a.KeepFiringFor),
+		Annotations:          a.Annotations,
+		Labels:               a.Labels,
+		IsPaused:             a.IsPaused,
+		Record:               ModelRecordFromApiRecord(a.Record),
+		NotificationSettings: ModelNotificationSettingsFromApiNotificationSettings(a.NotificationSettings),
+		Template:             ModelAlertRuleTemplateRefFromApiAlertRuleTemplateRef(a.Template),
 	}, nil
 }
 
 // ProvisionedAlertRuleFromAlertRule converts models.AlertRule to definitions.ProvisionedAlertRule and sets provided provenance status
 func ProvisionedAlertRuleFromAlertRule(rule models.AlertRule, provenance models.Provenance) definitions.ProvisionedAlertRule {
 	return definitions.ProvisionedAlertRule{
-		ID:           rule.ID,
-		UID:          rule.UID,
-		OrgID:        rule.OrgID,
-		FolderUID:    rule.NamespaceUID,
-		RuleGroup:    rule.RuleGroup,
-		Title:        rule.Title,
-		For:          model.Duration(rule.For),
-		Condition:    rule.Condition,
-		Data:         ApiAlertQueriesFromAlertQueries(rule.Data),
-		Updated:      rule.Updated,
-		NoDataState:  definitions.NoDataState(rule.NoDataState),          // TODO there may be a validation
-		ExecErrState: definitions.ExecutionErrorState(rule.ExecErrState), // TODO there may be a validation
-		Annotations:  rule.Annotations,
-		Labels:       rule.Labels,
-		Provenance:   definitions.Provenance(provenance), // TODO validate enum conversion?
-		IsPaused:     rule.IsPaused,
+		ID:                   rule.ID,
+		UID:                  rule.UID,
+		OrgID:                rule.OrgID,
+		FolderUID:            rule.NamespaceUID,
+		RuleGroup:            rule.RuleGroup,
+		Title:                rule.Title,
+		For:                  model.Duration(rule.For),
+		KeepFiringFor:        model.Duration(rule.KeepFiringFor),
+		Condition:            rule.Condition,
+		Data:                 ApiAlertQueriesFromAlertQueries(rule.Data),
+		Updated:              rule.Updated,
+		NoDataState:          definitions.NoDataState(rule.NoDataState),          // TODO there may be a validation
+		ExecErrState:         definitions.ExecutionErrorState(rule.ExecErrState), // TODO there may be a validation
+		Annotations:          rule.Annotations,
+		Labels:               rule.Labels,
+		Provenance:           definitions.Provenance(provenance), // TODO validate enum conversion?
+		IsPaused:             rule.IsPaused,
+		Record:               ApiRecordFromModelRecord(rule.Record),
+		NotificationSettings: ApiNotificationSettingsFromModelNotificationSettings(rule.NotificationSettings),
+		Template:             ApiAlertRuleTemplateRefFromModelAlertRuleTemplateRef(rule.Template),
 	}
 }
 
@@ -63,6 +72,124 @@ func ProvisionedAlertRuleFromAlertRules(rules []*models.AlertRule, provenances m
 	return result
 }
 
+// ModelRecordFromApiRecord converts definitions.Record to models.Record
+func ModelRecordFromApiRecord(r *definitions.Record) *models.Record {
+	if r == nil {
+		return nil
+	}
+	return &models.Record{
+		Metric: r.Metric,
+		From:   r.From,
+	}
+}
+
+// ApiRecordFromModelRecord converts models.Record to definitions.Record
+func ApiRecordFromModelRecord(r *models.Record) *definitions.Record {
+	if r == nil {
+		return nil
+	}
+	return &definitions.Record{
+		Metric: r.Metric,
+		From:   r.From,
+	}
+}
+
+// ModelNotificationSettingsFromApiNotificationSettings converts definitions.AlertRuleNotificationSettings to models.NotificationSettings
+func ModelNotificationSettingsFromApiNotificationSettings(s *definitions.AlertRuleNotificationSettings) *models.NotificationSettings {
+	if s == nil {
+		return nil
+	}
+	return &models.NotificationSettings{
+		Receiver:          s.Receiver,
+		GroupBy:           s.GroupBy,
+		GroupWait:         s.GroupWait,
+		GroupInterval:     s.GroupInterval,
+		RepeatInterval:    s.RepeatInterval,
+		MuteTimeIntervals: s.MuteTimeIntervals,
+	}
+}
+
+// ApiNotificationSettingsFromModelNotificationSettings converts models.NotificationSettings to definitions.AlertRuleNotificationSettings
+func ApiNotificationSettingsFromModelNotificationSettings(s *models.NotificationSettings) *definitions.AlertRuleNotificationSettings {
+	if s == nil {
+		return nil
+	}
+	return &definitions.AlertRuleNotificationSettings{
+		Receiver:          s.Receiver,
+		GroupBy:           s.GroupBy,
+		GroupWait:         s.GroupWait,
+		GroupInterval:     s.GroupInterval,
+		RepeatInterval:    s.RepeatInterval,
+		MuteTimeIntervals: s.MuteTimeIntervals,
+	}
+}
+
+// ModelAlertRuleTemplateRefFromApiAlertRuleTemplateRef converts definitions.AlertRuleTemplateRef to models.AlertRuleTemplateRef
+func ModelAlertRuleTemplateRefFromApiAlertRuleTemplateRef(r *definitions.AlertRuleTemplateRef) *models.AlertRuleTemplateRef {
+	if r == nil {
+		return nil
+	}
+	return &models.AlertRuleTemplateRef{
+		UID:        r.UID,
+		Parameters: r.Parameters,
+	}
+}
+
+// ApiAlertRuleTemplateRefFromModelAlertRuleTemplateRef converts models.AlertRuleTemplateRef to definitions.AlertRuleTemplateRef
+func ApiAlertRuleTemplateRefFromModelAlertRuleTemplateRef(r *models.AlertRuleTemplateRef) *definitions.AlertRuleTemplateRef {
+	if r == nil {
+		return nil
+	}
+	return &definitions.AlertRuleTemplateRef{
+		UID:        r.UID,
+		Parameters: r.Parameters,
+	}
+}
+
+// AlertRuleTemplateFromApiAlertRuleTemplate converts definitions.AlertRuleTemplate to models.AlertRuleTemplate
+func AlertRuleTemplateFromApiAlertRuleTemplate(t definitions.AlertRuleTemplate) models.AlertRuleTemplate {
+	params := make([]models.AlertRuleTemplateParameter, 0, len(t.Parameters))
+	for _, p := range t.Parameters {
+		params = append(params, models.AlertRuleTemplateParameter{
+			Name:        p.Name,
+			Type:        models.AlertRuleTemplateParameterType(p.Type),
+			Description: p.Description,
+			Default:     p.Default,
+		})
+	}
+	return models.AlertRuleTemplate{
+		UID:         t.UID,
+		Title:       t.Title,
+		Condition:   t.Condition,
+		Data:        AlertQueriesFromApiAlertQueries(t.Data),
+		Annotations: t.Annotations,
+		Parameters:  params,
+	}
+}
+
+// ApiAlertRuleTemplateFromAlertRuleTemplate converts models.AlertRuleTemplate to definitions.AlertRuleTemplate and sets provided provenance status
+func ApiAlertRuleTemplateFromAlertRuleTemplate(t models.AlertRuleTemplate, provenance models.Provenance) definitions.AlertRuleTemplate {
+	params := make([]definitions.AlertRuleTemplateParameter, 0, len(t.Parameters))
+	for _, p := range t.Parameters {
+		params = append(params, definitions.AlertRuleTemplateParameter{
+			Name:        p.Name,
+			Type:        string(p.Type),
+			Description: p.Description,
+			Default:     p.Default,
+		})
+	}
+	return definitions.AlertRuleTemplate{
+		UID:         t.UID,
+		Title:       t.Title,
+		Condition:   t.Condition,
+		Data:        ApiAlertQueriesFromAlertQueries(t.Data),
+		Annotations: t.Annotations,
+		Parameters:  params,
+		Updated:     t.Updated,
+		Provenance:  definitions.Provenance(provenance),
+	}
+}
+
 // AlertQueriesFromApiAlertQueries converts a collection of definitions.AlertQuery to collection of models.AlertQuery
 func AlertQueriesFromApiAlertQueries(queries []definitions.AlertQuery) []models.AlertQuery {
 	result := make([]models.AlertQuery, 0, len(queries))
@@ -101,9 +228,10 @@ func ApiAlertQueriesFromAlertQueries(queries []models.AlertQuery) []definitions.
 
 func AlertRuleGroupFromApiAlertRuleGroup(a definitions.AlertRuleGroup) (models.AlertRuleGroup, error) {
 	ruleGroup := models.AlertRuleGroup{
-		Title:     a.Title,
-		FolderUID: a.FolderUID,
-		Interval:  a.Interval,
+		Title:      a.Title,
+		FolderUID:  a.FolderUID,
+		Interval:   a.Interval,
+		Sequential: a.Sequential,
 	}
 	for i := range a.Rules {
 		converted, err := AlertRuleFromProvisionedAlertRule(a.Rules[i])
@@ -121,10 +249,11 @@ func ApiAlertRuleGroupFromAlertRuleGroup(d models.AlertRuleGroup) definitions.Al
 		rules = append(rules, ProvisionedAlertRuleFromAlertRule(d.Rules[i], d.Provenance))
 	}
 	return definitions.AlertRuleGroup{
-		Title:     d.Title,
-		FolderUID: d.FolderUID,
-		Interval:  d.Interval,
-		Rules:     rules,
+		Title:      d.Title,
+		FolderUID:  d.FolderUID,
+		Interval:   d.Interval,
+		Sequential: d.Sequential,
+		Rules:      rules,
 	}
 }
 
@@ -158,6 +287,7 @@ func AlertRuleGroupExportFromAlertRuleGroupWithFolderTitle(d models.AlertRuleGro
 		FolderUID:       d.FolderUID,
 		Interval:        model.Duration(time.Duration(d.Interval) * time.Second),
 		IntervalSeconds: d.Interval,
+		Sequential:      d.Sequential,
 		Rules:           rules,
 	}, nil
 }
@@ -184,19 +314,89 @@ func AlertRuleExportFromAlertRule(rule models.AlertRule) (definitions.AlertRuleE
 		NoDataState:  definitions.NoDataState(rule.NoDataState),
 		ExecErrState: definitions.ExecutionErrorState(rule.ExecErrState),
 		IsPaused:     rule.IsPaused,
+		Record:       ApiRecordFromModelRecord(rule.Record),
 	}
 	if rule.For.Seconds() > 0 {
 		result.ForString = util.Pointer(model.Duration(rule.For).String())
 	}
+	if rule.KeepFiringFor.Seconds() > 0 {
+		result.KeepFiringFor = model.Duration(rule.KeepFiringFor)
+		result.KeepFiringForString = util.Pointer(model.Duration(rule.KeepFiringFor).String())
+	}
 	if rule.Annotations != nil {
 		result.Annotations = &rule.Annotations
 	}
 	if rule.Labels != nil {
 		result.Labels = &rule.Labels
 	}
+	if rule.NotificationSettings != nil {
+		result.NotificationSettings = AlertRuleNotificationSettingsExportFromNotificationSettings(*rule.NotificationSettings)
+	}
+	result.Template = ApiAlertRuleTemplateRefFromModelAlertRuleTemplateRef(rule.Template)
 	return result, nil
 }
 
+// AlertRuleNotificationSettingsExportFromNotificationSettings creates a definitions.AlertRuleNotificationSettingsExport DTO from models.NotificationSettings.
+func AlertRuleNotificationSettingsExportFromNotificationSettings(s models.NotificationSettings) *definitions.AlertRuleNotificationSettingsExport {
+	durationString := func(d *model.Duration) *string {
+		if d == nil {
+			return nil
+		}
+		return util.Pointer(d.String())
+	}
+	return &definitions.AlertRuleNotificationSettingsExport{
+		Receiver:          s.Receiver,
+		GroupBy:           s.GroupBy,
+		GroupWait:         durationString(s.GroupWait),
+		GroupInterval:     durationString(s.GroupInterval),
+		RepeatInterval:    durationString(s.RepeatInterval),
+		MuteTimeIntervals: s.MuteTimeIntervals,
+	}
+}
+
+// AlertingFileExportFromAlertRuleTemplates creates a definitions.AlertingFileExport DTO from []models.AlertRuleTemplate.
+func AlertingFileExportFromAlertRuleTemplates(templates []*models.AlertRuleTemplate) (definitions.AlertingFileExport, error) {
+	f := definitions.AlertingFileExport{APIVersion: 1}
+	for _, template := range templates {
+		export, err := AlertRuleTemplateExportFromAlertRuleTemplate(*template)
+		if err != nil {
+			return definitions.AlertingFileExport{}, err
+		}
+		f.RuleTemplates = append(f.RuleTemplates, export)
+	}
+	return f, nil
+}
+
+// AlertRuleTemplateExportFromAlertRuleTemplate creates a definitions.AlertRuleTemplateExport DTO from models.AlertRuleTemplate.
+func AlertRuleTemplateExportFromAlertRuleTemplate(t models.AlertRuleTemplate) (definitions.AlertRuleTemplateExport, error) {
+	data := make([]definitions.AlertQueryExport, 0, len(t.Data))
+	for i := range t.Data {
+		query, err := AlertQueryExportFromAlertQuery(t.Data[i])
+		if err != nil {
+			return definitions.AlertRuleTemplateExport{}, err
+		}
+		data = append(data, query)
+	}
+	params := make([]definitions.AlertRuleTemplateParameterExport, 0, len(t.Parameters))
+	for _, p := range t.Parameters {
+		params = append(params, definitions.AlertRuleTemplateParameterExport{
+			Name:        p.Name,
+			Type:        string(p.Type),
+			Description: p.Description,
+			Default:     p.Default,
+		})
+	}
+	return definitions.AlertRuleTemplateExport{
+		OrgID:       t.OrgID,
+		UID:         t.UID,
+		Title:       t.Title,
+		Condition:   t.Condition,
+		Data:        data,
+		Annotations: t.Annotations,
+		Parameters:  params,
+	}, nil
+}
+
 // AlertQueryExportFromAlertQuery creates a definitions.AlertQueryExport DTO from models.AlertQuery.
 func AlertQueryExportFromAlertQuery(query models.AlertQuery) (definitions.AlertQueryExport, error) {
 	// We unmarshal the json.RawMessage model into a map in order to facilitate yaml marshalling.
@@ -323,6 +523,101 @@ func RouteExportFromRoute(route *definitions.Route) *definitions.RouteExport {
 	return &export
 }
 
+// RecurringSilenceFromApiRecurringSilence converts definitions.RecurringSilence to models.RecurringSilence.
+func RecurringSilenceFromApiRecurringSilence(orgID int64, s definitions.RecurringSilence) models.RecurringSilence {
+	matchers := make([]models.RecurringSilenceMatcher, 0, len(s.Matchers))
+	for _, m := range s.Matchers {
+		if m == nil {
+			continue
+		}
+		matcher := models.RecurringSilenceMatcher{IsEqual: true}
+		if m.Name != nil {
+			matcher.Name = *m.Name
+		}
+		if m.Value != nil {
+			matcher.Value = *m.Value
+		}
+		if m.IsRegex != nil {
+			matcher.IsRegex = *m.IsRegex
+		}
+		if m.IsEqual != nil {
+			matcher.IsEqual = *m.IsEqual
+		}
+		matchers = append(matchers, matcher)
+	}
+	interval := s.Interval
+	if interval == 0 && s.Frequency != "" {
+		interval = 1
+	}
+	return models.RecurringSilence{
+		OrgID:     orgID,
+		UID:       s.UID,
+		Matchers:  matchers,
+		Comment:   s.Comment,
+		CreatedBy: s.CreatedBy,
+		StartsAt:  s.StartsAt,
+		Duration:  time.Duration(s.Duration),
+		Frequency: models.RecurringSilenceFrequency(s.Frequency),
+		Interval:  interval,
+		Until:     s.Until,
+		Timezone:  s.Timezone,
+	}
+}
+
+// ApiRecurringSilenceFromRecurringSilence converts models.RecurringSilence to definitions.RecurringSilence.
+func ApiRecurringSilenceFromRecurringSilence(s *models.RecurringSilence) definitions.RecurringSilence {
+	matchers := make(amv2.Matchers, 0, len(s.Matchers))
+	for _, m := range s.Matchers {
+		m := m
+		matchers = append(matchers, &amv2.Matcher{Name: &m.Name, Value: &m.Value, IsRegex: &m.IsRegex, IsEqual: &m.IsEqual})
+	}
+	silenceIDs := make([]string, 0, len(s.Silences))
+	for _, o := range s.Silences {
+		silenceIDs = append(silenceIDs, o.SilenceID)
+	}
+	return definitions.RecurringSilence{
+		UID:        s.UID,
+		Matchers:   matchers,
+		Comment:    s.Comment,
+		CreatedBy:  s.CreatedBy,
+		StartsAt:   s.StartsAt,
+		Duration:   model.Duration(s.Duration),
+		Frequency:  string(s.Frequency),
+		Interval:   s.Interval,
+		Until:      s.Until,
+		Timezone:   s.Timezone,
+		SilenceIDs: silenceIDs,
+		Updated:    s.Updated,
+	}
+}
+
+// ApiNotificationHistoryEntryFromNotificationHistoryEntry converts models.NotificationHistoryEntry to definitions.NotificationHistoryEntry.
+func ApiNotificationHistoryEntryFromNotificationHistoryEntry(e models.NotificationHistoryEntry) definitions.NotificationHistoryEntry {
+	alerts := make([]definitions.NotificationHistoryAlert, 0, len(e.Alerts))
+	for _, a := range e.Alerts {
+		alerts = append(alerts, definitions.NotificationHistoryAlert{
+			Labels:      a.Labels,
+			Annotations: a.Annotations,
+			Status:      string(a.Status),
+			StartsAt:    a.StartsAt,
+			EndsAt:      a.EndsAt,
+		})
+	}
+	return definitions.NotificationHistoryEntry{
+		Receiver:         e.Receiver,
+		Integration:      e.Integration,
+		IntegrationIndex: e.IntegrationIndex,
+		GroupKey:         e.GroupKey,
+		Alerts:           alerts,
+		Status:           string(e.Status),
+		Error:            e.Error,
+		Retry:            e.Retry,
+		Payload:          e.Payload,
+		SentAt:           e.SentAt,
+		Duration:         model.Duration(e.Duration),
+	}
+}
+
 // OmitDefault returns nil if the value is the default.
 func OmitDefault[T comparable](v *T) *T {
 	var def T
@@ -26,4 +26,6 @@ type RuleStore interface {
 	IncreaseVersionForAllRulesInNamespace(ctx context.Context, orgID int64, namespaceUID string) ([]ngmodels.AlertRuleKeyWithVersionAndPauseStatus, error)
 
 	Count(ctx context.Context, orgID int64) (int64, error)
+
+	GetAlertRuleTemplate(ctx context.Context, orgID int64, uid string) (*ngmodels.AlertRuleTemplate, error)
 }
@@ -127,3 +127,27 @@ func (f *ProvisioningApiHandler) handleRouteGetAlertRuleGroupExport(ctx *context
 func (f *ProvisioningApiHandler) handleRoutePutAlertRuleGroup(ctx *contextmodel.ReqContext, ag apimodels.AlertRuleGroup, folder, group string) response.Response {
 	return f.svc.RoutePutAlertRuleGroup(ctx, ag, folder, group)
 }
+
+func (f *ProvisioningApiHandler) handleRouteGetAlertRuleTemplates(ctx *contextmodel.ReqContext) response.Response {
+	return f.svc.RouteGetAlertRuleTemplates(ctx)
+}
+
+func (f *ProvisioningApiHandler) handleRouteGetAlertRuleTemplate(ctx *contextmodel.ReqContext, UID string) response.Response {
+	return f.svc.RouteGetAlertRuleTemplate(ctx, UID)
+}
+
+func (f *ProvisioningApiHandler) handleRouteGetAlertRuleTemplatesExport(ctx *contextmodel.ReqContext) response.Response {
+	return f.svc.RouteGetAlertRuleTemplatesExport(ctx)
+}
+
+func (f *ProvisioningApiHandler) handleRoutePostAlertRuleTemplate(ctx *contextmodel.ReqContext, template apimodels.AlertRuleTemplate) response.Response {
+	return f.svc.RoutePostAlertRuleTemplate(ctx, template)
+}
+
+func (f *ProvisioningApiHandler) handleRoutePutAlertRuleTemplate(ctx *contextmodel.ReqContext, template apimodels.AlertRuleTemplate, UID string) response.Response {
+	return f.svc.RoutePutAlertRuleTemplate(ctx, template, UID)
+}
+
+func (f *ProvisioningApiHandler) handleRouteDeleteAlertRuleTemplate(ctx *contextmodel.ReqContext, UID string) response.Response {
+	return f.svc.RouteDeleteAlertRuleTemplate(ctx, UID)
+}
@@ -0,0 +1,84 @@
+package definitions
+
+import (
+	"time"
+
+	"github.com/prometheus/common/model"
+)
+
+// swagger:route GET /api/alertmanager/grafana/api/v2/notifications/history alertmanager RouteGetGrafanaNotificationHistory
+//
+// get the attempts of the integrations to send notifications, the most recent first
+//
+//     Responses:
+//       200: NotificationHistory
+//       400: ValidationError
+
+// swagger:parameters RouteGetGrafanaNotificationHistory
+type NotificationHistoryParams struct {
+	// Name of the receiver.
+	// in: query
+	// required: false
+	Receiver string `json:"receiver"`
+	// Type of the integration, e.g. email or slack.
+	// in: query
+	// required: false
+	Integration string `json:"integration"`
+	// in: query
+	// required: false
+	// enum: success,failure
+	Status string `json:"status"`
+	// in: query
+	// required: false
+	GroupKey string `json:"groupKey"`
+	// Unix timestamp in seconds of the earliest attempt.
+	// in: query
+	// required: false
+	From int64 `json:"from"`
+	// Unix timestamp in seconds of the latest attempt.
+	// in: query
+	// required: false
+	To int64 `json:"to"`
+	// Maximum number of attempts returned. Defaults to 100.
+	// in: query
+	// required: false
+	Limit int `json:"limit"`
+}
+
+// swagger:model
+type NotificationHistory []NotificationHistoryEntry
+
+// NotificationHistoryEntry is an attempt of an integration of a receiver to send a notification.
+// swagger:model
+type NotificationHistoryEntry struct {
+	// example: team-a-email
+	Receiver string `json:"receiver"`
+	// example: email
+	Integration string `json:"integration"`
+	// Position of the integration in the receiver.
+	IntegrationIndex int                        `json:"integrationIndex"`
+	GroupKey         string                     `json:"groupKey"`
+	Alerts           []NotificationHistoryAlert `json:"alerts"`
+	// enum: success,failure
+	Status string `json:"status"`
+	Error  string `json:"error,omitempty"`
+	// Whether the failed attempt is retried.
+	Retry bool `json:"retry,omitempty"`
+	// Payload sent by the integration if it is sent through Grafana: the body of webhook requests and
+	// the subject of emails. It is truncated to max_payload_size, and only returned to users allowed to read
+	// the secrets of contact points.
+	Payload  string         `json:"payload,omitempty"`
+	SentAt   time.Time      `json:"sentAt"`
+	Duration model.Duration `json:"duration"`
+}
+
+// NotificationHistoryAlert is an alert sent in a notification.
+// swagger:model
+type NotificationHistoryAlert struct {
+	Labels      map[string]string `json:"labels"`
+	Annotations map[string]string `json:"annotations,omitempty"`
+	// enum: firing,resolved
+	Status   string    `json:"status"`
+	StartsAt time.Time `json:"startsAt"`
+	EndsAt   time.Time `json:"endsAt"`
+}
@@ -0,0 +1,101 @@
+package definitions
+
+import (
+	"time"
+
+	amv2 "github.com/prometheus/alertmanager/api/v2/models"
+	"github.com/prometheus/common/model"
+)
+
+// swagger:route GET /api/alertmanager/grafana/api/v2/recurring-silences alertmanager RouteGetGrafanaRecurringSilences
+//
+// get recurring silences
+//
+//     Responses:
+//       200: RecurringSilences
+
+// swagger:route POST /api/alertmanager/grafana/api/v2/recurring-silences alertmanager RouteCreateGrafanaRecurringSilence
+//
+// create a recurring silence, the occurrences that start within a day are expanded into silences immediately
+//
+//     Responses:
+//       201: RecurringSilence
+//       400: ValidationError
+
+// swagger:route GET /api/alertmanager/grafana/api/v2/recurring-silence/{RecurringSilenceUID} alertmanager RouteGetGrafanaRecurringSilence
+//
+// get recurring silence
+//
+//     Responses:
+//       200: RecurringSilence
+//       404: NotFound
+
+// swagger:route PUT /api/alertmanager/grafana/api/v2/recurring-silence/{RecurringSilenceUID} alertmanager RoutePutGrafanaRecurringSilence
+//
+// replace a recurring silence, the silences created for its previous schedule are expired
+//
+//     Responses:
+//       200: RecurringSilence
+//       400: ValidationError
+//       404: NotFound
+
+// swagger:route DELETE /api/alertmanager/grafana/api/v2/recurring-silence/{RecurringSilenceUID} alertmanager RouteDeleteGrafanaRecurringSilence
+//
+// delete a recurring silence and expire the silences created for it
+//
+//     Responses:
+//       200: Ack
+//       404: NotFound
+
+// swagger:parameters RouteGetGrafanaRecurringSilence RoutePutGrafanaRecurringSilence RouteDeleteGrafanaRecurringSilence
+type RecurringSilenceUIDReference struct {
+	// in:path
+	RecurringSilenceUID string
+}
+
+// swagger:parameters RouteCreateGrafanaRecurringSilence RoutePutGrafanaRecurringSilence
+type RecurringSilencePayload struct {
+	// in:body
+	Body RecurringSilence
+}
+
+// swagger:model
+type RecurringSilences []RecurringSilence
+
+// RecurringSilence is a silence that is scheduled ahead of time and, optionally, repeats. The occurrences of the
+// silence are expanded into silences of the Alertmanager one day before they start.
+// swagger:model
+type RecurringSilence struct {
+	// example: weekly-maintenance
+	UID string `json:"uid,omitempty"`
+	// required: true
+	Matchers amv2.Matchers `json:"matchers"`
+	// required: true
+	// example: Weekly database maintenance
+	Comment string `json:"comment"`
+	// Author of the silences. It defaults to the login of the user creating the recurring silence.
+	CreatedBy string `json:"createdBy,omitempty"`
+	// Start of the first occurrence. The following occurrences start at the same wall clock time in the time zone.
+	// required: true
+	// example: 2023-10-02T22:00:00+02:00
+	StartsAt time.Time `json:"startsAt"`
+	// Duration of each occurrence.
+	// required: true
+	// example: 2h
+	Duration model.Duration `json:"duration"`
+	// Unit of the period of the occurrences. The silence does not repeat if it is empty.
+	// enum: ,daily,weekly,monthly
+	Frequency string `json:"frequency,omitempty"`
+	// Number of units between two occurrences, e.g. 2 with the weekly frequency repeats every other week. Defaults to 1.
+	Interval int64 `json:"interval,omitempty"`
+	// No occurrence starts after this time. The silence repeats forever if it is not set.
+	Until *time.Time `json:"until,omitempty"`
+	// IANA time zone of the occurrences. Defaults to UTC.
+	// example: Europe/Berlin
+	Timezone string `json:"timezone,omitempty"`
+	// IDs of the silences created for the occurrences that have not ended.
+	// readonly: true
+	SilenceIDs []string `json:"silenceIds,omitempty"`
+	// readonly: true
+	Updated time.Time `json:"updated,omitempty"`
+}
@@ -24,6 +24,25 @@ type AlertmanagerIntegration struct {
 	Password *Secret `json:"basicAuthPassword,omitempty" yaml:"basicAuthPassword,omitempty" hcl:"basic_auth_password"`
 }
 
+type AmqpIntegration struct {
+	DisableResolveMessage *bool `json:"-" yaml:"-" hcl:"disable_resolve_message"`
+
+	URL        string `json:"url" yaml:"url" hcl:"url"`
+	RoutingKey string `json:"routingKey" yaml:"routingKey" hcl:"routing_key"`
+
+	Exchange             *string `json:"exchange,omitempty" yaml:"exchange,omitempty" hcl:"exchange"`
+	Persistent           *bool   `json:"persistent,omitempty" yaml:"persistent,omitempty" hcl:"persistent"`
+	MessageFormat        *string `json:"messageFormat,omitempty" yaml:"messageFormat,omitempty" hcl:"message_format"`
+	Title                *string `json:"title,omitempty" yaml:"title,omitempty" hcl:"title"`
+	Message              *string `json:"message,omitempty" yaml:"message,omitempty" hcl:"message"`
+	Username             *string `json:"username,omitempty" yaml:"username,omitempty" hcl:"username"`
+	Password             *Secret `json:"password,omitempty" yaml:"password,omitempty" hcl:"password"`
+	InsecureSkipVerify   *bool   `json:"insecureSkipVerify,omitempty" yaml:"insecureSkipVerify,omitempty" hcl:"insecure_skip_verify"`
+	TLSCACertificate     *string `json:"tlsCACertificate,omitempty" yaml:"tlsCACertificate,omitempty" hcl:"tls_ca_certificate"`
+	TLSClientCertificate *string `json:"tlsClientCertificate,omitempty" yaml:"tlsClientCertificate,omitempty" hcl:"tls_client_certificate"`
+	TLSClientKey         *Secret `json:"tlsClientKey,omitempty" yaml:"tlsClientKey,omitempty" hcl:"tls_client_key"`
+}
+
 type DingdingIntegration struct {
 	DisableResolveMessage *bool `json:"-" yaml:"-" hcl:"disable_resolve_message"`
 
@@ -85,6 +104,26 @@ type LineIntegration struct {
 	Description *string `json:"description,omitempty" yaml:"description,omitempty" hcl:"description"`
 }
 
+type MqttIntegration struct {
+	DisableResolveMessage *bool `json:"-" yaml:"-" hcl:"disable_resolve_message"`
+
+	BrokerURL string `json:"brokerUrl" yaml:"brokerUrl" hcl:"broker_url"`
+	Topic     string `json:"topic" yaml:"topic" hcl:"topic"`
+
+	ClientID             *string `json:"clientId,omitempty" yaml:"clientId,omitempty" hcl:"client_id"`
+	QoS                  *int64  `json:"qos,omitempty" yaml:"qos,omitempty" hcl:"qos"`
+	Retain               *bool   `json:"retain,omitempty" yaml:"retain,omitempty" hcl:"retain"`
+	MessageFormat        *string `json:"messageFormat,omitempty" yaml:"messageFormat,omitempty" hcl:"message_format"`
+	Title                *string `json:"title,omitempty" yaml:"title,omitempty" hcl:"title"`
+	Message              *string `json:"message,omitempty" yaml:"message,omitempty" hcl:"message"`
+	Username             *string `json:"username,omitempty" yaml:"username,omitempty" hcl:"username"`
+	Password             *Secret `json:"password,omitempty" yaml:"password,omitempty" hcl:"password"`
+	InsecureSkipVerify   *bool   `json:"insecureSkipVerify,omitempty" yaml:"insecureSkipVerify,omitempty" hcl:"insecure_skip_verify"`
+	TLSCACertificate     *string `json:"tlsCACertificate,omitempty" yaml:"tlsCACertificate,omitempty" hcl:"tls_ca_certificate"`
+	TLSClientCertificate *string `json:"tlsClientCertificate,omitempty" yaml:"tlsClientCertificate,omitempty" hcl:"tls_client_certificate"`
+	TLSClientKey         *Secret `json:"tlsClientKey,omitempty" yaml:"tlsClientKey,omitempty" hcl:"tls_client_key"`
+}
+
 type OnCallIntegration struct {
 	DisableResolveMessage *bool `json:"-" yaml:"-" hcl:"disable_resolve_message"`
 
@@ -270,12 +309,14 @@ type WecomIntegration struct {
 type ContactPoint struct {
 	Name         string                    `json:"name" yaml:"name" hcl:"name"`
 	Alertmanager []AlertmanagerIntegration `json:"alertmanager" yaml:"alertmanager" hcl:"alertmanager,block"`
+	Amqp         []AmqpIntegration         `json:"amqp" yaml:"amqp" hcl:"amqp,block"`
 	Dingding     []DingdingIntegration     `json:"dingding" yaml:"dingding" hcl:"dingding,block"`
 	Discord      []DiscordIntegration      `json:"discord" yaml:"discord" hcl:"discord,block"`
 	Email        []EmailIntegration        `json:"email" yaml:"email" hcl:"email,block"`
 	Googlechat   []GooglechatIntegration   `json:"googlechat" yaml:"googlechat" hcl:"googlechat,block"`
 	Kafka        []KafkaIntegration        `json:"kafka" yaml:"kafka" hcl:"kafka,block"`
 	Line         []LineIntegration         `json:"line" yaml:"line" hcl:"line,block"`
+	Mqtt         []MqttIntegration         `json:"mqtt" yaml:"mqtt" hcl:"mqtt,block"`
 	Opsgenie     []OpsgenieIntegration     `json:"opsgenie" yaml:"opsgenie" hcl:"opsgenie,block"`
 	Pagerduty    []PagerdutyIntegration    `json:"pagerduty" yaml:"pagerduty" hcl:"pagerduty,block"`
 	OnCall       []OnCallIntegration       `json:"oncall" yaml:"oncall" hcl:"oncall,block"`
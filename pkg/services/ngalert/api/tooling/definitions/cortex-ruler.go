@@ -190,9 +190,10 @@ type NamespaceConfigResponse map[string][]GettableRuleGroupConfig
 
 // swagger:model
 type PostableRuleGroupConfig struct {
-	Name     string                     `yaml:"name" json:"name"`
-	Interval model.Duration             `yaml:"interval,omitempty" json:"interval,omitempty"`
-	Rules    []PostableExtendedRuleNode `yaml:"rules" json:"rules"`
+	Name       string                     `yaml:"name" json:"name"`
+	Interval   model.Duration             `yaml:"interval,omitempty" json:"interval,omitempty"`
+	Sequential bool                       `yaml:"sequential,omitempty" json:"sequential,omitempty"`
+	Rules      []PostableExtendedRuleNode `yaml:"rules" json:"rules"`
 }
 
 func (c *PostableRuleGroupConfig) UnmarshalJSON(b []byte) error {
@@ -239,6 +240,7 @@ type GettableRuleGroupConfig struct {
 	Name          string                     `yaml:"name" json:"name"`
 	Interval      model.Duration             `yaml:"interval,omitempty" json:"interval,omitempty"`
 	SourceTenants []string                   `yaml:"source_tenants,omitempty" json:"source_tenants,omitempty"`
+	Sequential    bool                       `yaml:"sequential,omitempty" json:"sequential,omitempty"`
 	Rules         []GettableExtendedRuleNode `yaml:"rules" json:"rules"`
 }
 
@@ -398,6 +400,11 @@ type PostableGrafanaRule struct {
 	NoDataState  NoDataState         `json:"no_data_state" yaml:"no_data_state"`
 	ExecErrState ExecutionErrorState `json:"exec_err_state" yaml:"exec_err_state"`
 	IsPaused     *bool               `json:"is_paused" yaml:"is_paused"`
+	Record       *Record             `json:"record,omitempty" yaml:"record,omitempty"`
+	// NotificationSettings sends the alerts of the rule directly to a contact point.
+	NotificationSettings *AlertRuleNotificationSettings `json:"notification_settings,omitempty" yaml:"notification_settings,omitempty"`
+	// Template instantiates the condition, the queries and the annotations of the rule from an alert rule template.
+	Template *AlertRuleTemplateRef `json:"template,omitempty" yaml:"template,omitempty"`
 }
 
 // swagger:model
@@ -418,6 +425,47 @@ type GettableGrafanaRule struct {
 	ExecErrState    ExecutionErrorState `json:"exec_err_state" yaml:"exec_err_state"`
 	Provenance      Provenance          `json:"provenance,omitempty" yaml:"provenance,omitempty"`
 	IsPaused        bool                `json:"is_paused" yaml:"is_paused"`
+	Record          *Record             `json:"record,omitempty" yaml:"record,omitempty"`
+	// NotificationSettings sends the alerts of the rule directly to a contact point.
+	NotificationSettings *AlertRuleNotificationSettings `json:"notification_settings,omitempty" yaml:"notification_settings,omitempty"`
+	// Template instantiates the condition, the queries and the annotations of the rule from an alert rule template.
+	Template *AlertRuleTemplateRef `json:"template,omitempty" yaml:"template,omitempty"`
+}
+
+// Record defines how a recording rule writes the result of its queries.
+// swagger:model
+type Record struct {
+	// Name of the metric the result is written to.
+	// required: true
+	// example: grafana_alerts_ratio
+	Metric string `json:"metric" yaml:"metric" hcl:"metric"`
+	// RefID of the query or expression whose result is written.
+	// required: true
+	// example: A
+	From string `json:"from" yaml:"from" hcl:"from"`
+}
+
+// AlertRuleNotificationSettings sends the alerts of a rule directly to a contact point
+// instead of routing them with the notification policies.
+// swagger:model
+type AlertRuleNotificationSettings struct {
+	// Name of the contact point the alerts are sent to.
+	// required: true
+	// example: grafana-default-email
+	Receiver string `json:"receiver" yaml:"receiver"`
+	// Labels the alerts are grouped by, in addition to alertname and grafana_folder.
+	// Use the special label `...` to group alerts by all labels.
+	// example: ["team"]
+	GroupBy []string `json:"group_by,omitempty" yaml:"group_by,omitempty"`
+	// example: 30s
+	GroupWait *model.Duration `json:"group_wait,omitempty" yaml:"group_wait,omitempty"`
+	// example: 5m
+	GroupInterval *model.Duration `json:"group_interval,omitempty" yaml:"group_interval,omitempty"`
+	// example: 4h
+	RepeatInterval *model.Duration `json:"repeat_interval,omitempty" yaml:"repeat_interval,omitempty"`
+	// Names of the mute timings that mute the alerts.
+	// example: ["weekends"]
+	MuteTimeIntervals []string `json:"mute_time_intervals,omitempty" yaml:"mute_time_intervals,omitempty"`
 }
 
 // AlertQuery represents a single query associated with an alert definition.
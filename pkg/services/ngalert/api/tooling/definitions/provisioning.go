@@ -7,9 +7,10 @@ type AlertingFileExport struct {
 	Groups        []AlertRuleGroupExport     `json:"groups,omitempty" yaml:"groups,omitempty"`
 	ContactPoints []ContactPointExport       `json:"contactPoints,omitempty" yaml:"contactPoints,omitempty"`
 	Policies      []NotificationPolicyExport `json:"policies,omitempty" yaml:"policies,omitempty"`
+	RuleTemplates []AlertRuleTemplateExport  `json:"ruleTemplates,omitempty" yaml:"ruleTemplates,omitempty"`
 }
 
-// swagger:parameters RouteGetAlertRuleGroupExport RouteGetAlertRuleExport RouteGetContactpointsExport RouteGetContactpointExport RoutePostRulesGroupForExport
+// swagger:parameters RouteGetAlertRuleGroupExport RouteGetAlertRuleExport RouteGetContactpointsExport RouteGetContactpointExport RoutePostRulesGroupForExport RouteGetAlertRuleTemplatesExport
 type ExportQueryParams struct {
 	// Whether to initiate a download of the file or not.
 	// in: query
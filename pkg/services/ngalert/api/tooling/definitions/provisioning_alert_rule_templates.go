@@ -0,0 +1,160 @@
+package definitions
+
+import (
+	"time"
+)
+
+// swagger:route GET /api/v1/provisioning/alert-rule-templates provisioning stable RouteGetAlertRuleTemplates
+//
+// Get all the alert rule templates.
+//
+//     Responses:
+//       200: AlertRuleTemplates
+
+// swagger:route GET /api/v1/provisioning/alert-rule-templates/export provisioning stable RouteGetAlertRuleTemplatesExport
+//
+// Export all alert rule templates in provisioning file format.
+//
+//     Produces:
+//     - application/json
+//     - application/yaml
+//     - text/yaml
+//
+//     Responses:
+//       200: AlertingFileExport
+//       400: ValidationError
+
+// swagger:route GET /api/v1/provisioning/alert-rule-templates/{UID} provisioning stable RouteGetAlertRuleTemplate
+//
+// Get an alert rule template.
+//
+//     Responses:
+//       200: AlertRuleTemplate
+//       404: description: Not found.
+
+// swagger:route POST /api/v1/provisioning/alert-rule-templates provisioning stable RoutePostAlertRuleTemplate
+//
+// Create a new alert rule template.
+//
+//     Consumes:
+//     - application/json
+//
+//     Responses:
+//       201: AlertRuleTemplate
+//       400: ValidationError
+
+// swagger:route PUT /api/v1/provisioning/alert-rule-templates/{UID} provisioning stable RoutePutAlertRuleTemplate
+//
+// Replace an existing alert rule template and update the alert rules instantiated from it.
+//
+//     Consumes:
+//     - application/json
+//
+//     Responses:
+//       200: AlertRuleTemplate
+//       400: ValidationError
+//       404: description: Not found.
+
+// swagger:route DELETE /api/v1/provisioning/alert-rule-templates/{UID} provisioning stable RouteDeleteAlertRuleTemplate
+//
+// Delete an alert rule template that no alert rule is instantiated from.
+//
+//     Responses:
+//       204: description: The alert rule template was deleted successfully.
+//       409: description: Alert rules are instantiated from the alert rule template.
+
+// swagger:parameters RouteGetAlertRuleTemplate RoutePutAlertRuleTemplate RouteDeleteAlertRuleTemplate
+type AlertRuleTemplateUIDReference struct {
+	// Alert rule template UID
+	// in:path
+	UID string
+}
+
+// swagger:parameters RoutePostAlertRuleTemplate RoutePutAlertRuleTemplate
+type AlertRuleTemplatePayload struct {
+	// in:body
+	Body AlertRuleTemplate
+}
+
+// swagger:parameters RoutePostAlertRuleTemplate RoutePutAlertRuleTemplate RouteDeleteAlertRuleTemplate
+type AlertRuleTemplateHeaders struct {
+	// in:header
+	XDisableProvenance string `json:"X-Disable-Provenance"`
+}
+
+// swagger:model
+type AlertRuleTemplates []AlertRuleTemplate
+
+// AlertRuleTemplate holds the queries, expressions and annotations that alert rules are instantiated from.
+// The queries, expressions and annotations refer to the parameters of the template with the placeholder ${name}.
+// swagger:model
+type AlertRuleTemplate struct {
+	// example: cpu-usage
+	UID string `json:"uid"`
+	// required: true
+	// minLength: 1
+	// maxLength: 190
+	// example: High CPU usage
+	Title string `json:"title"`
+	// required: true
+	// example: A
+	Condition string `json:"condition"`
+	// required: true
+	// example: [{"refId":"A","queryType":"","relativeTimeRange":{"from":600,"to":0},"datasourceUid":"PD8C576611E62080A","model":{"expr":"cpu_usage{service=\"${service}\"} > ${threshold}","refId":"A"}}]
+	Data []AlertQuery `json:"data"`
+	// example: {"summary": "CPU usage of ${service} is above ${threshold}"}
+	Annotations map[string]string            `json:"annotations,omitempty"`
+	Parameters  []AlertRuleTemplateParameter `json:"parameters,omitempty"`
+	// readonly: true
+	Updated    time.Time  `json:"updated,omitempty"`
+	Provenance Provenance `json:"provenance,omitempty"`
+}
+
+// AlertRuleTemplateParameter is a parameter of an alert rule template.
+// swagger:model
+type AlertRuleTemplateParameter struct {
+	// required: true
+	// pattern: ^[a-zA-Z_][a-zA-Z0-9_]*$
+	// example: threshold
+	Name string `json:"name"`
+	// Type of the values of the parameter. The placeholder of a number parameter that is a whole string
+	// of the model of a query is replaced by the number.
+	// required: true
+	// enum: string,number
+	Type        string `json:"type"`
+	Description string `json:"description,omitempty"`
+	// Value of the parameter for the rules that do not set it. Parameters without a default are required.
+	// example: 80
+	Default *string `json:"default,omitempty"`
+}
+
+// AlertRuleTemplateRef is the alert rule template an alert rule is instantiated from. The condition, the queries
+// and the annotations of the template are applied to the rule and updated when the template changes.
+// swagger:model
+type AlertRuleTemplateRef struct {
+	// required: true
+	// example: cpu-usage
+	UID string `json:"uid" yaml:"uid"`
+	// Values of the parameters of the template.
+	// example: {"service": "api", "threshold": "90"}
+	Parameters map[string]string `json:"parameters,omitempty" yaml:"parameters,omitempty"`
+}
+
+// AlertRuleTemplateExport is the provisioned file export of models.AlertRuleTemplate.
+type AlertRuleTemplateExport struct {
+	OrgID       int64                              `json:"orgId" yaml:"orgId"`
+	UID         string                             `json:"uid" yaml:"uid"`
+	Title       string                             `json:"title" yaml:"title"`
+	Condition   string                             `json:"condition" yaml:"condition"`
+	Data        []AlertQueryExport                 `json:"data" yaml:"data"`
+	Annotations map[string]string                  `json:"annotations,omitempty" yaml:"annotations,omitempty"`
+	Parameters  []AlertRuleTemplateParameterExport `json:"parameters,omitempty" yaml:"parameters,omitempty"`
+}
+
+// AlertRuleTemplateParameterExport is the provisioned file export of models.AlertRuleTemplateParameter.
+type AlertRuleTemplateParameterExport struct {
+	Name        string  `json:"name" yaml:"name"`
+	Type        string  `json:"type" yaml:"type"`
+	Description string  `json:"description,omitempty" yaml:"description,omitempty"`
+	Default     *string `json:"default,omitempty" yaml:"default,omitempty"`
+}
@@ -148,6 +148,8 @@ type ProvisionedAlertRule struct {
 	ExecErrState ExecutionErrorState `json:"execErrState"`
 	// required: true
 	For model.Duration `json:"for"`
+	// example: 5m
+	KeepFiringFor model.Duration `json:"keepFiringFor,omitempty"`
 	// example: {"runbook_url": "https://supercoolrunbook.com/page/13"}
 	Annotations map[string]string `json:"annotations,omitempty"`
 	// example: {"team": "sre-team-1"}
@@ -156,6 +158,12 @@ type ProvisionedAlertRule struct {
 	Provenance Provenance `json:"provenance,omitempty"`
 	// example: false
 	IsPaused bool `json:"isPaused"`
+	// Record makes the rule a recording rule.
+	Record *Record `json:"record,omitempty"`
+	// NotificationSettings sends the alerts of the rule directly to a contact point.
+	NotificationSettings *AlertRuleNotificationSettings `json:"notification_settings,omitempty"`
+	// Template instantiates the condition, the queries and the annotations of the rule from an alert rule template.
+	Template *AlertRuleTemplateRef `json:"template,omitempty"`
 }
 
 // swagger:route GET /api/v1/provisioning/folder/{FolderUID}/rule-groups/{Group} provisioning stable RouteGetAlertRuleGroup
@@ -215,10 +223,11 @@ type AlertRuleGroupMetadata struct {
 
 // swagger:model
 type AlertRuleGroup struct {
-	Title     string                 `json:"title"`
-	FolderUID string                 `json:"folderUid"`
-	Interval  int64                  `json:"interval"`
-	Rules     []ProvisionedAlertRule `json:"rules"`
+	Title      string                 `json:"title"`
+	FolderUID  string                 `json:"folderUid"`
+	Interval   int64                  `json:"interval"`
+	Sequential bool                   `json:"sequential,omitempty"`
+	Rules      []ProvisionedAlertRule `json:"rules"`
 }
 
 // AlertRuleGroupExport is the provisioned file export of AlertRuleGroupV1.
@@ -229,6 +238,7 @@ type AlertRuleGroupExport struct {
 	FolderUID       string            `json:"-" yaml:"-" hcl:"folder_uid"`
 	Interval        model.Duration    `json:"interval" yaml:"interval"`
 	IntervalSeconds int64             `json:"-" yaml:"-" hcl:"interval_seconds"`
+	Sequential      bool              `json:"sequential,omitempty" yaml:"sequential,omitempty"`
 	Rules           []AlertRuleExport `json:"rules" yaml:"rules" hcl:"rule,block"`
 }
 
@@ -246,10 +256,28 @@ type AlertRuleExport struct {
 	// ForString is used to:
 	// - Only export the for field for HCL if it is non-zero.
 	// - Format the Prometheus model.Duration type properly for HCL.
-	ForString   *string            `json:"-" yaml:"-" hcl:"for"`
-	Annotations *map[string]string `json:"annotations,omitempty" yaml:"annotations,omitempty" hcl:"annotations"`
-	Labels      *map[string]string `json:"labels,omitempty" yaml:"labels,omitempty" hcl:"labels"`
-	IsPaused    bool               `json:"isPaused" yaml:"isPaused" hcl:"is_paused"`
+	ForString     *string        `json:"-" yaml:"-" hcl:"for"`
+	KeepFiringFor model.Duration `json:"keepFiringFor,omitempty" yaml:"keepFiringFor,omitempty"`
+	// KeepFiringForString is used to only export the keep_firing_for field for HCL if it is non-zero.
+	KeepFiringForString *string            `json:"-" yaml:"-" hcl:"keep_firing_for"`
+	Annotations         *map[string]string `json:"annotations,omitempty" yaml:"annotations,omitempty" hcl:"annotations"`
+	Labels              *map[string]string `json:"labels,omitempty" yaml:"labels,omitempty" hcl:"labels"`
+	IsPaused            bool               `json:"isPaused" yaml:"isPaused" hcl:"is_paused"`
+	Record              *Record            `json:"record,omitempty" yaml:"record,omitempty" hcl:"record,block"`
+	// NotificationSettings sends the alerts of the rule directly to a contact point.
+	NotificationSettings *AlertRuleNotificationSettingsExport `json:"notification_settings,omitempty" yaml:"notification_settings,omitempty" hcl:"notification_settings,block"`
+	// Template is exported for YAML and JSON only, the rule is exported with the queries instantiated from the template.
+	Template *AlertRuleTemplateRef `json:"template,omitempty" yaml:"template,omitempty"`
+}
+
+// AlertRuleNotificationSettingsExport is the provisioned export of models.NotificationSettings.
+type AlertRuleNotificationSettingsExport struct {
+	Receiver          string   `json:"receiver" yaml:"receiver" hcl:"contact_point"`
+	GroupBy           []string `json:"group_by,omitempty" yaml:"group_by,omitempty" hcl:"group_by"`
+	GroupWait         *string  `json:"group_wait,omitempty" yaml:"group_wait,omitempty" hcl:"group_wait,optional"`
+	GroupInterval     *string  `json:"group_interval,omitempty" yaml:"group_interval,omitempty" hcl:"group_interval,optional"`
+	RepeatInterval    *string  `json:"repeat_interval,omitempty" yaml:"repeat_interval,omitempty" hcl:"repeat_interval,optional"`
+	MuteTimeIntervals []string `json:"mute_time_intervals,omitempty" yaml:"mute_time_intervals,omitempty" hcl:"mute_timings"`
 }
 
 // AlertQueryExport is the provisioned export of models.AlertQuery.
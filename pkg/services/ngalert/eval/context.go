@@ -3,6 +3,8 @@ package eval
 import (
 	"context"
 
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+
 	"github.com/grafana/grafana/pkg/services/user"
 )
 
@@ -10,6 +12,22 @@ import (
 type EvaluationContext struct {
 	Ctx  context.Context
 	User *user.SignedInUser
+	// RuleResults reads the results of the alert rules that the condition reads the results of.
+	// If it is nil, these queries return no data.
+	RuleResults RuleResultsReader
+}
+
+// AlertResult is the current state and values of an alert of an alert rule.
+type AlertResult struct {
+	Labels data.Labels
+	State  State
+	Values map[string]float64
+}
+
+// RuleResultsReader reads the current results of alert rules.
+type RuleResultsReader interface {
+	// Read returns the results of the alerts of the rule.
+	Read(orgID int64, ruleUID string) []AlertResult
 }
 
 func NewContext(ctx context.Context, user *user.SignedInUser) EvaluationContext {
@@ -18,3 +36,12 @@ func NewContext(ctx context.Context, user *user.SignedInUser) EvaluationContext
 		User: user,
 	}
 }
+
+// NewContextWithRuleResults returns a context in which the condition can read the results of other alert rules.
+func NewContextWithRuleResults(ctx context.Context, user *user.SignedInUser, reader RuleResultsReader) EvaluationContext {
+	return EvaluationContext{
+		Ctx:         ctx,
+		User:        user,
+		RuleResults: reader,
+	}
+}
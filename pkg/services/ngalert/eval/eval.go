@@ -17,6 +17,7 @@ import (
 
 	"github.com/grafana/grafana/pkg/expr"
 	"github.com/grafana/grafana/pkg/expr/classic"
+	"github.com/grafana/grafana/pkg/expr/mathexp"
 	"github.com/grafana/grafana/pkg/infra/log"
 	"github.com/grafana/grafana/pkg/plugins"
 	"github.com/grafana/grafana/pkg/services/datasources"
@@ -279,6 +280,15 @@ func getExprRequest(ctx EvaluationContext, data []models.AlertQuery, dsCacheServ
 	datasources := make(map[string]*datasources.DataSource, len(data))
 
 	for _, q := range data {
+		if q.IsRuleResult() {
+			input, err := getRuleResultInput(ctx, q)
+			if err != nil {
+				return nil, err
+			}
+			req.Inputs = append(req.Inputs, input)
+			continue
+		}
+
 		model, err := q.GetModel()
 		if err != nil {
 			return nil, fmt.Errorf("failed to get query model from '%s': %w", q.RefID, err)
@@ -320,6 +330,43 @@ func getExprRequest(ctx EvaluationContext, data []models.AlertQuery, dsCacheServ
 	return req, nil
 }
 
+// getRuleResultInput reads the results of the alert rule that the query reads the results of. Each alert
+// of the rule is a number, which is either the value of the expression of the query, or 1 if the alert
+// is firing and 0 otherwise. The alerts without the value of the expression are skipped.
+func getRuleResultInput(ctx EvaluationContext, q models.AlertQuery) (expr.Input, error) {
+	resultQuery, err := q.GetRuleResultQuery()
+	if err != nil {
+		return expr.Input{}, err
+	}
+	var alerts []AlertResult
+	if ctx.RuleResults != nil {
+		alerts = ctx.RuleResults.Read(ctx.User.OrgID, resultQuery.RuleUID)
+	}
+
+	input := expr.Input{RefID: q.RefID}
+	for _, alert := range alerts {
+		var value float64
+		if resultQuery.Expression == "" {
+			if alert.State == Alerting {
+				value = 1
+			}
+		} else {
+			v, ok := alert.Values[resultQuery.Expression]
+			if !ok {
+				continue
+			}
+			value = v
+		}
+		n := mathexp.NewNumber(q.RefID, alert.Labels)
+		n.SetValue(&value)
+		input.Results.Values = append(input.Results.Values, n)
+	}
+	if len(input.Results.Values) == 0 {
+		input.Results.Values = mathexp.Values{mathexp.NewNoData()}
+	}
+	return input, nil
+}
+
 type NumberValueCapture struct {
 	Var    string // RefID
 	Labels data.Labels
@@ -382,7 +429,7 @@ func queryDataResponseToExecutionResults(c models.Condition, execResp *backend.Q
 			hasNoFrames := len(res.Frames) == 0
 			hasNoFields := len(res.Frames) == 1 && len(res.Frames[0].Fields) == 0
 			if hasNoFrames || hasNoFields {
-				if s, ok := datasourceUIDsForRefIDs[refID]; ok && expr.NodeTypeFromDatasourceUID(s) == expr.TypeDatasourceNode { // TODO perhaps extract datasource UID from ML expression too.
+				if s, ok := datasourceUIDsForRefIDs[refID]; ok && expr.NodeTypeFromDatasourceUID(s) == expr.TypeDatasourceNode && s != models.RuleResultDatasourceUID { // TODO perhaps extract datasource UID from ML expression too.
 					result.NoData[refID] = s
 				}
 			}
@@ -13,6 +13,7 @@ import (
 	"github.com/stretchr/testify/require"
 
 	"github.com/grafana/grafana/pkg/expr"
+	"github.com/grafana/grafana/pkg/expr/mathexp"
 	"github.com/grafana/grafana/pkg/infra/tracing"
 	"github.com/grafana/grafana/pkg/plugins"
 	"github.com/grafana/grafana/pkg/services/datasources"
@@ -742,6 +743,67 @@ func TestEvaluate(t *testing.T) {
 	}
 }
 
+type fakeRuleResultsReader map[string][]AlertResult
+
+func (f fakeRuleResultsReader) Read(_ int64, ruleUID string) []AlertResult {
+	return f[ruleUID]
+}
+
+func TestGetExprRequestRuleResults(t *testing.T) {
+	reader := fakeRuleResultsReader{
+		"upstream": {
+			{Labels: data.Labels{"instance": "a"}, State: Alerting, Values: map[string]float64{"B": 10}},
+			{Labels: data.Labels{"instance": "b"}, State: Normal, Values: map[string]float64{"B": 2}},
+			{Labels: data.Labels{"instance": "c"}, State: Pending},
+		},
+	}
+	ruleResultQuery := func(refID, model string) models.AlertQuery {
+		return models.AlertQuery{RefID: refID, DatasourceUID: models.RuleResultDatasourceUID, Model: []byte(model)}
+	}
+	values := func(t *testing.T, input expr.Input) map[string]*float64 {
+		t.Helper()
+		result := make(map[string]*float64, len(input.Results.Values))
+		for _, v := range input.Results.Values {
+			n, ok := v.(mathexp.Number)
+			require.Truef(t, ok, "expected a number, got %T", v)
+			result[n.GetLabels()["instance"]] = n.GetFloat64Value()
+		}
+		return result
+	}
+
+	t.Run("reads the state of the alerts of the rule", func(t *testing.T) {
+		ctx := NewContextWithRuleResults(context.Background(), &user.SignedInUser{OrgID: 1}, reader)
+		req, err := getExprRequest(ctx, []models.AlertQuery{ruleResultQuery("UP", `{"ruleUid": "upstream"}`)}, nil)
+		require.NoError(t, err)
+		require.Empty(t, req.Queries)
+		require.Len(t, req.Inputs, 1)
+		assert.Equal(t, "UP", req.Inputs[0].RefID)
+		assert.Equal(t, map[string]*float64{"a": util.Pointer(1.0), "b": util.Pointer(0.0), "c": util.Pointer(0.0)}, values(t, req.Inputs[0]))
+	})
+
+	t.Run("reads the values of an expression of the rule", func(t *testing.T) {
+		ctx := NewContextWithRuleResults(context.Background(), &user.SignedInUser{OrgID: 1}, reader)
+		req, err := getExprRequest(ctx, []models.AlertQuery{ruleResultQuery("UP", `{"ruleUid": "upstream", "expression": "B"}`)}, nil)
+		require.NoError(t, err)
+		require.Len(t, req.Inputs, 1)
+		assert.Equal(t, map[string]*float64{"a": util.Pointer(10.0), "b": util.Pointer(2.0)}, values(t, req.Inputs[0]))
+	})
+
+	t.Run("is no data without results", func(t *testing.T) {
+		ctx := NewContext(context.Background(), &user.SignedInUser{OrgID: 1})
+		req, err := getExprRequest(ctx, []models.AlertQuery{ruleResultQuery("UP", `{"ruleUid": "upstream"}`)}, nil)
+		require.NoError(t, err)
+		require.Len(t, req.Inputs, 1)
+		assert.True(t, req.Inputs[0].Results.IsNoData())
+	})
+
+	t.Run("fails without the UID of the rule", func(t *testing.T) {
+		ctx := NewContextWithRuleResults(context.Background(), &user.SignedInUser{OrgID: 1}, reader)
+		_, err := getExprRequest(ctx, []models.AlertQuery{ruleResultQuery("UP", `{}`)}, nil)
+		require.Error(t, err)
+	})
+}
+
 func TestEvaluateRaw(t *testing.T) {
 	t.Run("should timeout if request takes too long", func(t *testing.T) {
 		unexpectedResponse := &backend.QueryDataResponse{}
@@ -57,6 +57,19 @@ func (d *Duration) UnmarshalYAML(unmarshal func(any) error) error {
 	}
 }
 
+// RuleResultDatasourceUID is the datasource UID of the queries that read the current results of
+// another alert rule of a sequential rule group instead of querying a datasource.
+const RuleResultDatasourceUID = "__alert_rule__"
+
+// RuleResultQuery is the model of a query that reads the current results of another alert rule.
+type RuleResultQuery struct {
+	// RuleUID is the UID of the alert rule to read the results of.
+	RuleUID string `json:"ruleUid"`
+	// Expression is the RefID of the query or expression of the rule to read the values of. If it is
+	// empty, the query reads the state of the alerts of the rule: 1 if an alert is firing, otherwise 0.
+	Expression string `json:"expression,omitempty"`
+}
+
 // RelativeTimeRange is the per query start and end time
 // for requests.
 type RelativeTimeRange struct {
@@ -116,6 +129,23 @@ func (aq *AlertQuery) IsExpression() (bool, error) {
 	return expr.NodeTypeFromDatasourceUID(aq.DatasourceUID) == expr.TypeCMDNode, nil
 }
 
+// IsRuleResult returns true if the alert query reads the results of another alert rule.
+func (aq *AlertQuery) IsRuleResult() bool {
+	return aq.DatasourceUID == RuleResultDatasourceUID
+}
+
+// GetRuleResultQuery returns the model of a query that reads the results of another alert rule.
+func (aq *AlertQuery) GetRuleResultQuery() (RuleResultQuery, error) {
+	var q RuleResultQuery
+	if err := json.Unmarshal(aq.Model, &q); err != nil {
+		return q, fmt.Errorf("failed to parse the model of query %s: %w", aq.RefID, err)
+	}
+	if q.RuleUID == "" {
+		return q, fmt.Errorf("query %s must specify the UID of the alert rule to read the results of", aq.RefID)
+	}
+	return q, nil
+}
+
 // setMaxDatapoints sets the model maxDataPoints if it's missing or invalid
 func (aq *AlertQuery) setMaxDatapoints() error {
 	if aq.modelProps == nil {
@@ -278,7 +308,7 @@ func (aq *AlertQuery) PreSave() error {
 		return err
 	}
 
-	if ok := isExpression || aq.RelativeTimeRange.isValid(); !ok {
+	if ok := isExpression || aq.IsRuleResult() || aq.RelativeTimeRange.isValid(); !ok {
 		return fmt.Errorf("invalid relative time range: %+v", aq.RelativeTimeRange)
 	}
 	return nil
@@ -12,6 +12,7 @@ import (
 	"github.com/google/go-cmp/cmp"
 	"github.com/google/go-cmp/cmp/cmpopts"
 	alertingModels "github.com/grafana/alerting/models"
+	prommodel "github.com/prometheus/common/model"
 
 	"github.com/grafana/grafana/pkg/services/quota"
 	"github.com/grafana/grafana/pkg/util/cmputil"
@@ -113,6 +114,7 @@ const (
 	StateReasonPaused        = "Paused"
 	StateReasonUpdated       = "Updated"
 	StateReasonRuleDeleted   = "RuleDeleted"
+	StateReasonKeepFiring    = "KeepFiring"
 )
 
 var (
@@ -133,6 +135,7 @@ type AlertRuleGroup struct {
 	Title      string
 	FolderUID  string
 	Interval   int64
+	Sequential bool
 	Provenance Provenance
 	Rules      []AlertRule
 }
@@ -147,15 +150,18 @@ type AlertRuleGroupWithFolderTitle struct {
 func NewAlertRuleGroupWithFolderTitle(groupKey AlertRuleGroupKey, rules []AlertRule, folderTitle string) AlertRuleGroupWithFolderTitle {
 	SortAlertRulesByGroupIndex(rules)
 	var interval int64
+	var sequential bool
 	if len(rules) > 0 {
 		interval = rules[0].IntervalSeconds
+		sequential = rules[0].Sequential
 	}
 	var result = AlertRuleGroupWithFolderTitle{
 		AlertRuleGroup: &AlertRuleGroup{
-			Title:     groupKey.RuleGroup,
-			FolderUID: groupKey.NamespaceUID,
-			Interval:  interval,
-			Rules:     rules,
+			Title:      groupKey.RuleGroup,
+			FolderUID:  groupKey.NamespaceUID,
+			Interval:   interval,
+			Sequential: sequential,
+			Rules:      rules,
 		},
 		FolderTitle: folderTitle,
 		OrgID:       groupKey.OrgID,
@@ -201,10 +207,25 @@ type AlertRule struct {
 	ExecErrState    ExecutionErrorState
 	// ideally this field should have been apimodels.ApiDuration
 	// but this is currently not possible because of circular dependencies
-	For         time.Duration
-	Annotations map[string]string
-	Labels      map[string]string
-	IsPaused    bool
+	For time.Duration
+	// KeepFiringFor is how long a firing alert keeps firing after its condition
+	// stops being met, so that a flapping condition doesn't resolve it every time.
+	KeepFiringFor time.Duration
+	Annotations   map[string]string
+	Labels        map[string]string
+	IsPaused      bool
+	// Sequential is set for the rules of a sequential rule group, which are evaluated one after
+	// another in the order of RuleGroupIndex so that a rule can read the results of the rules before it.
+	Sequential bool
+	// Record is set for recording rules, which write the result of their
+	// queries as series instead of producing alerts.
+	Record *Record `xorm:"record"`
+	// NotificationSettings is set for rules whose alerts are sent directly to a contact point
+	// instead of being routed by the notification policy tree.
+	NotificationSettings *NotificationSettings `xorm:"notification_settings"`
+	// Template is set for rules instantiated from an alert rule template, whose condition, queries
+	// and annotations are updated when the template changes.
+	Template *AlertRuleTemplateRef `xorm:"template"`
 }
 
 // AlertRuleWithOptionals This is to avoid having to pass in additional arguments deep in the call stack. Alert rule
@@ -249,6 +270,21 @@ func (s AlertRulesSorter) Len() int           { return len(s.rules) }
 func (s AlertRulesSorter) Swap(i, j int)      { s.rules[i], s.rules[j] = s.rules[j], s.rules[i] }
 func (s AlertRulesSorter) Less(i, j int) bool { return s.by(s.rules[i], s.rules[j]) }
 
+// AfterLoad is called by xorm once the rule is loaded from the database.
+func (alertRule *AlertRule) AfterLoad() {
+	alertRule.Record = alertRule.Record.orNil()
+	alertRule.NotificationSettings = alertRule.NotificationSettings.orNil()
+	alertRule.Template = alertRule.Template.orNil()
+}
+
+// Type returns whether the rule is an alerting or a recording rule.
+func (alertRule *AlertRule) Type() RuleType {
+	if alertRule.Record != nil {
+		return RuleTypeRecording
+	}
+	return RuleTypeAlerting
+}
+
 // GetDashboardUID returns the DashboardUID or "".
 func (alertRule *AlertRule) GetDashboardUID() string {
 	if alertRule.DashboardUID != nil {
@@ -456,10 +492,22 @@ type AlertRuleVersion struct {
 	ExecErrState    ExecutionErrorState
 	// ideally this field should have been apimodels.ApiDuration
 	// but this is currently not possible because of circular dependencies
-	For         time.Duration
-	Annotations map[string]string
-	Labels      map[string]string
-	IsPaused    bool
+	For                  time.Duration
+	KeepFiringFor        time.Duration
+	Annotations          map[string]string
+	Labels               map[string]string
+	IsPaused             bool
+	Sequential           bool
+	Record               *Record               `xorm:"record"`
+	NotificationSettings *NotificationSettings `xorm:"notification_settings"`
+	Template             *AlertRuleTemplateRef `xorm:"template"`
+}
+
+// AfterLoad is called by xorm once the version is loaded from the database.
+func (v *AlertRuleVersion) AfterLoad() {
+	v.Record = v.Record.orNil()
+	v.NotificationSettings = v.NotificationSettings.orNil()
+	v.Template = v.Template.orNil()
 }
 
 // GetAlertRuleByUIDQuery is the query for retrieving/deleting an alert rule by UID and organisation ID.
@@ -575,9 +623,15 @@ func PatchPartialAlertRule(existingRule *AlertRule, ruleToPatch *AlertRuleWithOp
 	if ruleToPatch.For == -1 {
 		ruleToPatch.For = existingRule.For
 	}
+	if ruleToPatch.KeepFiringFor == -1 {
+		ruleToPatch.KeepFiringFor = existingRule.KeepFiringFor
+	}
 	if !ruleToPatch.HasPause {
 		ruleToPatch.IsPaused = existingRule.IsPaused
 	}
+	if ruleToPatch.Record == nil {
+		ruleToPatch.Record = existingRule.Record
+	}
 }
 
 func ValidateRuleGroupInterval(intervalSeconds, baseIntervalSeconds int64) error {
@@ -588,6 +642,35 @@ func ValidateRuleGroupInterval(intervalSeconds, baseIntervalSeconds int64) error
 	return nil
 }
 
+// ValidateRuleResultQueries checks that the rules of a rule group, sorted by RuleGroupIndex, read only
+// the results of the rules evaluated before them, which is possible only in a sequential rule group.
+func ValidateRuleResultQueries(rules []*AlertRule) error {
+	evaluatedBefore := make(map[string]struct{}, len(rules))
+	for _, rule := range rules {
+		for _, q := range rule.Data {
+			if !q.IsRuleResult() {
+				continue
+			}
+			if !rule.Sequential {
+				return fmt.Errorf("%w: query %s of rule '%s' reads the results of another alert rule, which is only possible in a sequential rule group",
+					ErrAlertRuleFailedValidation, q.RefID, rule.Title)
+			}
+			resultQuery, err := q.GetRuleResultQuery()
+			if err != nil {
+				return fmt.Errorf("%w: %s", ErrAlertRuleFailedValidation, err.Error())
+			}
+			if _, ok := evaluatedBefore[resultQuery.RuleUID]; !ok {
+				return fmt.Errorf("%w: query %s of rule '%s' reads the results of rule %s, which must be before it in the same rule group",
+					ErrAlertRuleFailedValidation, q.RefID, rule.Title, resultQuery.RuleUID)
+			}
+		}
+		if rule.UID != "" {
+			evaluatedBefore[rule.UID] = struct{}{}
+		}
+	}
+	return nil
+}
+
 type RulesGroup []*AlertRule
 
 func (g RulesGroup) SortByGroupIndex() {
@@ -635,3 +718,59 @@ func GroupByAlertRuleGroupKey(rules []*AlertRule) map[AlertRuleGroupKey]RulesGro
 	}
 	return result
 }
+
+// RuleType is the kind of a rule.
+type RuleType string
+
+const (
+	// RuleTypeAlerting rules evaluate a condition and produce alerts.
+	RuleTypeAlerting RuleType = "alerting"
+	// RuleTypeRecording rules evaluate queries and write the results as series.
+	RuleTypeRecording RuleType = "recording"
+)
+
+// Record contains the settings of a recording rule.
+type Record struct {
+	// Metric is the name of the series written with the results of the rule.
+	Metric string `json:"metric"`
+	// From is the RefID of the query or expression whose results are written.
+	From string `json:"from"`
+}
+
+// Validate checks that the metric name is valid and that the results are
+// taken from one of the queries or expressions of the rule.
+func (r *Record) Validate(data []AlertQuery) error {
+	if !prommodel.IsValidMetricName(prommodel.LabelValue(r.Metric)) {
+		return fmt.Errorf("%w: invalid metric name %q for recording rule", ErrAlertRuleFailedValidation, r.Metric)
+	}
+	for _, q := range data {
+		if q.RefID == r.From {
+			return nil
+		}
+	}
+	return fmt.Errorf("%w: recording rule results must come from one of the queries or expressions, got %q", ErrAlertRuleFailedValidation, r.From)
+}
+
+// FromDB loads the settings stored as JSON.
+func (r *Record) FromDB(b []byte) error {
+	if len(b) == 0 {
+		return nil
+	}
+	return json.Unmarshal(b, r)
+}
+
+// ToDB stores the settings as JSON.
+func (r *Record) ToDB() ([]byte, error) {
+	if r == nil {
+		return nil, nil
+	}
+	return json.Marshal(r)
+}
+
+// orNil returns nil for the empty settings xorm loads for rules stored without record.
+func (r *Record) orNil() *Record {
+	if r == nil || *r == (Record{}) {
+		return nil
+	}
+	return r
+}
@@ -0,0 +1,284 @@
+package models
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+	"sort"
+	"time"
+)
+
+var (
+	// ErrAlertRuleTemplateNotFound is an error for an unknown alert rule template.
+	ErrAlertRuleTemplateNotFound = errors.New("could not find alert rule template")
+	// ErrAlertRuleTemplateFailedValidation is an error for an invalid alert rule template.
+	ErrAlertRuleTemplateFailedValidation = errors.New("invalid alert rule template")
+	// ErrAlertRuleTemplateInUse is returned when a template that alert rules are instantiated from is deleted.
+	ErrAlertRuleTemplateInUse = errors.New("alert rule template is used by alert rules")
+)
+
+// AlertRuleTemplateMaxTitleLength is the maximum length of the title of an alert rule template.
+const AlertRuleTemplateMaxTitleLength = 190
+
+var (
+	alertRuleTemplateParameterNameRegexp = regexp.MustCompile(`^[a-zA-Z_][a-zA-Z0-9_]*$`)
+	// alertRuleTemplatePlaceholderRegexp matches the placeholders ${name} of the parameters of a template.
+	alertRuleTemplatePlaceholderRegexp = regexp.MustCompile(`\$\{([a-zA-Z_][a-zA-Z0-9_]*)\}`)
+)
+
+// AlertRuleTemplateParameterType is the type of the values of a parameter of an alert rule template.
+type AlertRuleTemplateParameterType string
+
+const (
+	AlertRuleTemplateParameterTypeString AlertRuleTemplateParameterType = "string"
+	AlertRuleTemplateParameterTypeNumber AlertRuleTemplateParameterType = "number"
+)
+
+// AlertRuleTemplateParameter is a parameter of an alert rule template. The queries, expressions and
+// annotations of the template refer to it with the placeholder ${name}.
+type AlertRuleTemplateParameter struct {
+	Name        string                         `json:"name"`
+	Type        AlertRuleTemplateParameterType `json:"type"`
+	Description string                         `json:"description,omitempty"`
+	// Default is the value of the parameter for rules that do not set it. Parameters without a default are required.
+	Default *string `json:"default,omitempty"`
+}
+
+func (p AlertRuleTemplateParameter) validateValue(value string) error {
+	if p.Type == AlertRuleTemplateParameterTypeNumber {
+		// the value must be a JSON number because it can replace a number in the models of the queries
+		var number float64
+		if err := json.Unmarshal([]byte(value), &number); err != nil {
+			return fmt.Errorf("value %q of the parameter %q is not a number", value, p.Name)
+		}
+	}
+	return nil
+}
+
+// AlertRuleTemplate holds the queries, expressions and annotations that alert rules are instantiated from.
+// The rules instantiated from a template are updated when the template changes.
+type AlertRuleTemplate struct {
+	ID          int64  `xorm:"pk autoincr 'id'"`
+	OrgID       int64  `xorm:"org_id"`
+	UID         string `xorm:"uid"`
+	Title       string
+	Condition   string
+	Data        []AlertQuery
+	Annotations map[string]string
+	Parameters  []AlertRuleTemplateParameter
+	Version     int64 `xorm:"version"` // this tag makes xorm add optimistic lock (see https://xorm.io/docs/chapter-06/1.lock/)
+	Updated     time.Time
+}
+
+func (t *AlertRuleTemplate) ResourceType() string {
+	return "alertRuleTemplate"
+}
+
+func (t *AlertRuleTemplate) ResourceID() string {
+	return t.UID
+}
+
+// Validate checks the title, the queries and the parameters of the template.
+func (t *AlertRuleTemplate) Validate() error {
+	if t.Title == "" {
+		return fmt.Errorf("%w: title cannot be empty", ErrAlertRuleTemplateFailedValidation)
+	}
+	if len(t.Title) > AlertRuleTemplateMaxTitleLength {
+		return fmt.Errorf("%w: title is too long. Max length is %d", ErrAlertRuleTemplateFailedValidation, AlertRuleTemplateMaxTitleLength)
+	}
+	if len(t.Data) == 0 {
+		return fmt.Errorf("%w: no queries or expressions are found", ErrAlertRuleTemplateFailedValidation)
+	}
+	hasCondition := false
+	for _, q := range t.Data {
+		if q.RefID == t.Condition {
+			hasCondition = true
+			break
+		}
+	}
+	if !hasCondition {
+		return fmt.Errorf("%w: condition %q does not exist, must be one of the queries or expressions", ErrAlertRuleTemplateFailedValidation, t.Condition)
+	}
+	names := make(map[string]struct{}, len(t.Parameters))
+	for _, p := range t.Parameters {
+		if !alertRuleTemplateParameterNameRegexp.MatchString(p.Name) {
+			return fmt.Errorf("%w: invalid parameter name %q", ErrAlertRuleTemplateFailedValidation, p.Name)
+		}
+		if _, ok := names[p.Name]; ok {
+			return fmt.Errorf("%w: duplicated parameter %q", ErrAlertRuleTemplateFailedValidation, p.Name)
+		}
+		names[p.Name] = struct{}{}
+		if p.Type != AlertRuleTemplateParameterTypeString && p.Type != AlertRuleTemplateParameterTypeNumber {
+			return fmt.Errorf("%w: unknown type %q of the parameter %q", ErrAlertRuleTemplateFailedValidation, p.Type, p.Name)
+		}
+		if p.Default != nil {
+			if err := p.validateValue(*p.Default); err != nil {
+				return fmt.Errorf("%w: %s", ErrAlertRuleTemplateFailedValidation, err)
+			}
+		}
+	}
+	for _, q := range t.Data {
+		var model any
+		if err := json.Unmarshal(q.Model, &model); err != nil {
+			return fmt.Errorf("%w: invalid model of the query %q: %s", ErrAlertRuleTemplateFailedValidation, q.RefID, err)
+		}
+	}
+	return nil
+}
+
+// Apply sets the condition, the queries and the expressions of the rule to the ones of the template and adds the
+// annotations of the template to the rule. The placeholders of the parameters are replaced by the values of the
+// template reference of the rule, or the defaults of the parameters. The annotations added by a previous version
+// of the template that the template no longer has are removed from the rule.
+func (t *AlertRuleTemplate) Apply(rule *AlertRule) error {
+	var params map[string]string
+	if rule.Template != nil {
+		params = rule.Template.Parameters
+	}
+	values, err := t.parameterValues(params)
+	if err != nil {
+		return err
+	}
+
+	data := make([]AlertQuery, 0, len(t.Data))
+	for _, q := range t.Data {
+		// decode numbers as json.Number so that the numbers that are not parameters are written unchanged
+		var model any
+		decoder := json.NewDecoder(bytes.NewReader(q.Model))
+		decoder.UseNumber()
+		if err := decoder.Decode(&model); err != nil {
+			return fmt.Errorf("failed to read the model of the query %q of the alert rule template %q: %w", q.RefID, t.UID, err)
+		}
+		q.Model, err = json.Marshal(values.substitute(model))
+		if err != nil {
+			return fmt.Errorf("failed to write the model of the query %q of the alert rule template %q: %w", q.RefID, t.UID, err)
+		}
+		data = append(data, q)
+	}
+
+	annotations := make(map[string]string, len(rule.Annotations)+len(t.Annotations))
+	for k, v := range rule.Annotations {
+		annotations[k] = v
+	}
+	if rule.Template != nil {
+		for _, k := range rule.Template.Annotations {
+			delete(annotations, k)
+		}
+	}
+	keys := make([]string, 0, len(t.Annotations))
+	for k, v := range t.Annotations {
+		annotations[k] = values.substituteString(v)
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	rule.Condition = t.Condition
+	rule.Data = data
+	rule.Annotations = annotations
+	if rule.Template != nil {
+		// the reference is replaced rather than changed because the caller can share it with other rules
+		ref := *rule.Template
+		ref.Annotations = keys
+		rule.Template = &ref
+	}
+	return nil
+}
+
+// parameterValues returns the values of all parameters of the template, given the values set by a rule.
+func (t *AlertRuleTemplate) parameterValues(params map[string]string) (templateParameterValues, error) {
+	values := make(templateParameterValues, len(t.Parameters))
+	for _, p := range t.Parameters {
+		value, ok := params[p.Name]
+		if !ok {
+			if p.Default == nil {
+				return nil, fmt.Errorf("%w: parameter %q of the alert rule template %q is required", ErrAlertRuleFailedValidation, p.Name, t.UID)
+			}
+			value = *p.Default
+		}
+		if err := p.validateValue(value); err != nil {
+			return nil, fmt.Errorf("%w: %s", ErrAlertRuleFailedValidation, err)
+		}
+		values[p.Name] = templateParameterValue{value: value, number: p.Type == AlertRuleTemplateParameterTypeNumber}
+	}
+	for name := range params {
+		if _, ok := values[name]; !ok {
+			return nil, fmt.Errorf("%w: alert rule template %q has no parameter %q", ErrAlertRuleFailedValidation, t.UID, name)
+		}
+	}
+	return values, nil
+}
+
+type templateParameterValue struct {
+	value  string
+	number bool
+}
+
+type templateParameterValues map[string]templateParameterValue
+
+// substitute replaces the placeholders in the strings of a decoded JSON value. A string that is only the
+// placeholder of a number parameter is replaced by the number, so that templates can set numeric fields.
+func (values templateParameterValues) substitute(v any) any {
+	switch val := v.(type) {
+	case string:
+		if m := alertRuleTemplatePlaceholderRegexp.FindStringSubmatch(val); m != nil && m[0] == val {
+			if p, ok := values[m[1]]; ok && p.number {
+				return json.Number(p.value)
+			}
+		}
+		return values.substituteString(val)
+	case []any:
+		for i := range val {
+			val[i] = values.substitute(val[i])
+		}
+	case map[string]any:
+		for k := range val {
+			val[k] = values.substitute(val[k])
+		}
+	}
+	return v
+}
+
+// substituteString replaces the placeholders of the parameters in s. Placeholders of unknown parameters are kept.
+func (values templateParameterValues) substituteString(s string) string {
+	return alertRuleTemplatePlaceholderRegexp.ReplaceAllStringFunc(s, func(placeholder string) string {
+		if p, ok := values[placeholder[2:len(placeholder)-1]]; ok {
+			return p.value
+		}
+		return placeholder
+	})
+}
+
+// AlertRuleTemplateRef is the template an alert rule is instantiated from and the values of its parameters.
+type AlertRuleTemplateRef struct {
+	UID        string            `json:"uid"`
+	Parameters map[string]string `json:"parameters,omitempty"`
+	// Annotations are the keys of the annotations of the rule set by the template, so that they are
+	// removed from the rule when they are removed from the template.
+	Annotations []string `json:"annotations,omitempty"`
+}
+
+// FromDB loads the reference stored as JSON.
+func (r *AlertRuleTemplateRef) FromDB(b []byte) error {
+	if len(b) == 0 {
+		return nil
+	}
+	return json.Unmarshal(b, r)
+}
+
+// ToDB stores the reference as JSON.
+func (r *AlertRuleTemplateRef) ToDB() ([]byte, error) {
+	if r == nil {
+		return nil, nil
+	}
+	return json.Marshal(r)
+}
+
+// orNil returns nil for the empty reference xorm loads for rules stored without a template.
+func (r *AlertRuleTemplateRef) orNil() *AlertRuleTemplateRef {
+	if r == nil || r.UID == "" {
+		return nil
+	}
+	return r
+}
@@ -0,0 +1,116 @@
+package models
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestAlertRuleTemplateValidate(t *testing.T) {
+	validTemplate := func() AlertRuleTemplate {
+		return AlertRuleTemplate{
+			UID:       "template",
+			Title:     "High error rate",
+			Condition: "B",
+			Data: []AlertQuery{
+				{RefID: "A", DatasourceUID: "prometheus", Model: json.RawMessage(`{"expr":"rate(errors{service=\"${service}\"}[5m])"}`)},
+				{RefID: "B", DatasourceUID: "__expr__", Model: json.RawMessage(`{"type":"threshold","expression":"A"}`)},
+			},
+			Parameters: []AlertRuleTemplateParameter{
+				{Name: "service", Type: AlertRuleTemplateParameterTypeString},
+				{Name: "threshold", Type: AlertRuleTemplateParameterTypeNumber, Default: func() *string { s := "0.5"; return &s }()},
+			},
+		}
+	}
+
+	testCases := []struct {
+		name   string
+		mutate func(*AlertRuleTemplate)
+	}{
+		{name: "empty title", mutate: func(t *AlertRuleTemplate) { t.Title = "" }},
+		{name: "no queries", mutate: func(t *AlertRuleTemplate) { t.Data = nil }},
+		{name: "unknown condition", mutate: func(t *AlertRuleTemplate) { t.Condition = "C" }},
+		{name: "invalid parameter name", mutate: func(t *AlertRuleTemplate) { t.Parameters[0].Name = "service-name" }},
+		{name: "duplicated parameter", mutate: func(t *AlertRuleTemplate) { t.Parameters[1].Name = "service" }},
+		{name: "unknown parameter type", mutate: func(t *AlertRuleTemplate) { t.Parameters[0].Type = "bool" }},
+		{name: "default that is not a number", mutate: func(t *AlertRuleTemplate) { t.Parameters[1].Default = &t.Parameters[0].Name }},
+	}
+
+	template := validTemplate()
+	require.NoError(t, template.Validate())
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			template := validTemplate()
+			tc.mutate(&template)
+			require.ErrorIs(t, template.Validate(), ErrAlertRuleTemplateFailedValidation)
+		})
+	}
+}
+
+func TestAlertRuleTemplateApply(t *testing.T) {
+	threshold := "0.5"
+	template := AlertRuleTemplate{
+		UID:       "template",
+		Title:     "High error rate",
+		Condition: "B",
+		Data: []AlertQuery{
+			{RefID: "A", DatasourceUID: "prometheus", Model: json.RawMessage(`{"expr":"rate(errors{service=\"${service}\"}[5m])","intervalMs":1000}`)},
+			{RefID: "B", DatasourceUID: "__expr__", Model: json.RawMessage(`{"conditions":[{"evaluator":{"params":["${threshold}"],"type":"gt"}}],"expression":"A > ${threshold}","legend":"${unknown}"}`)},
+		},
+		Annotations: map[string]string{"summary": "Error rate of ${service} is above ${threshold}"},
+		Parameters: []AlertRuleTemplateParameter{
+			{Name: "service", Type: AlertRuleTemplateParameterTypeString},
+			{Name: "threshold", Type: AlertRuleTemplateParameterTypeNumber, Default: &threshold},
+		},
+	}
+
+	t.Run("should replace the parameters in the queries and the annotations", func(t *testing.T) {
+		rule := AlertRule{
+			Annotations: map[string]string{"runbook_url": "https://example.com", "summary": "old"},
+			Template:    &AlertRuleTemplateRef{UID: "template", Parameters: map[string]string{"service": `api "v2"`, "threshold": "10"}},
+		}
+		require.NoError(t, template.Apply(&rule))
+
+		require.Equal(t, "B", rule.Condition)
+		require.Len(t, rule.Data, 2)
+		require.JSONEq(t, `{"expr":"rate(errors{service=\"api \"v2\"\"}[5m])","intervalMs":1000}`, string(rule.Data[0].Model))
+		require.JSONEq(t, `{"conditions":[{"evaluator":{"params":[10],"type":"gt"}}],"expression":"A > 10","legend":"${unknown}"}`, string(rule.Data[1].Model))
+		require.Equal(t, map[string]string{"runbook_url": "https://example.com", "summary": `Error rate of api "v2" is above 10`}, rule.Annotations)
+		require.JSONEq(t, `{"expr":"rate(errors{service=\"${service}\"}[5m])","intervalMs":1000}`, string(template.Data[0].Model), "the template should not change")
+	})
+
+	t.Run("should use the default of the parameters the rule does not set", func(t *testing.T) {
+		rule := AlertRule{Template: &AlertRuleTemplateRef{UID: "template", Parameters: map[string]string{"service": "api"}}}
+		require.NoError(t, template.Apply(&rule))
+		require.Equal(t, "Error rate of api is above 0.5", rule.Annotations["summary"])
+	})
+
+	t.Run("should remove the annotations removed from the template", func(t *testing.T) {
+		rule := AlertRule{
+			Annotations: map[string]string{"runbook_url": "https://example.com"},
+			Template:    &AlertRuleTemplateRef{UID: "template", Parameters: map[string]string{"service": "api"}},
+		}
+		previous := template
+		previous.Annotations = map[string]string{"summary": "summary", "description": "description"}
+		require.NoError(t, previous.Apply(&rule))
+		require.Equal(t, []string{"description", "summary"}, rule.Template.Annotations)
+
+		ref := rule.Template
+		require.NoError(t, template.Apply(&rule))
+		require.Equal(t, map[string]string{"runbook_url": "https://example.com", "summary": "Error rate of api is above 0.5"}, rule.Annotations)
+		require.Equal(t, []string{"summary"}, rule.Template.Annotations)
+		require.Equal(t, []string{"description", "summary"}, ref.Annotations, "the previous reference should not change")
+	})
+
+	t.Run("should fail if a parameter is invalid", func(t *testing.T) {
+		for _, params := range []map[string]string{
+			{"threshold": "1"},
+			{"service": "api", "threshold": "high"},
+			{"service": "api", "region": "eu"},
+		} {
+			rule := AlertRule{Template: &AlertRuleTemplateRef{UID: "template", Parameters: params}}
+			require.ErrorIs(t, template.Apply(&rule), ErrAlertRuleFailedValidation)
+		}
+	})
+}
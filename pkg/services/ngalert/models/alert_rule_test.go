@@ -6,6 +6,7 @@ import (
 	"math/rand"
 	"sort"
 	"strings"
+	"sync"
 	"testing"
 	"time"
 
@@ -716,6 +717,54 @@ func TestSortByGroupIndex(t *testing.T) {
 	})
 }
 
+func TestValidateRuleResultQueries(t *testing.T) {
+	ruleResultQuery := func(ruleUID string) AlertQuery {
+		return AlertQuery{
+			RefID:         "A",
+			DatasourceUID: RuleResultDatasourceUID,
+			Model:         json.RawMessage(fmt.Sprintf(`{"ruleUid": %q}`, ruleUID)),
+		}
+	}
+	group := func(sequential bool) []*AlertRule {
+		rules := GenerateAlertRules(3, AlertRuleGen(WithUniqueUID(&sync.Map{}), WithSequentialGroupIndex()))
+		for _, rule := range rules {
+			rule.Sequential = sequential
+		}
+		return rules
+	}
+
+	t.Run("should accept queries of earlier rules of a sequential group", func(t *testing.T) {
+		rules := group(true)
+		rules[1].Data = append(rules[1].Data, ruleResultQuery(rules[0].UID))
+		rules[2].Data = append(rules[2].Data, ruleResultQuery(rules[0].UID), ruleResultQuery(rules[1].UID))
+		require.NoError(t, ValidateRuleResultQueries(rules))
+	})
+
+	t.Run("should fail if the group is not sequential", func(t *testing.T) {
+		rules := group(false)
+		rules[1].Data = append(rules[1].Data, ruleResultQuery(rules[0].UID))
+		require.ErrorIs(t, ValidateRuleResultQueries(rules), ErrAlertRuleFailedValidation)
+	})
+
+	t.Run("should fail if the query reads a later rule", func(t *testing.T) {
+		rules := group(true)
+		rules[1].Data = append(rules[1].Data, ruleResultQuery(rules[2].UID))
+		require.ErrorIs(t, ValidateRuleResultQueries(rules), ErrAlertRuleFailedValidation)
+	})
+
+	t.Run("should fail if the query reads the rule itself", func(t *testing.T) {
+		rules := group(true)
+		rules[1].Data = append(rules[1].Data, ruleResultQuery(rules[1].UID))
+		require.ErrorIs(t, ValidateRuleResultQueries(rules), ErrAlertRuleFailedValidation)
+	})
+
+	t.Run("should fail if the query reads a rule of another group", func(t *testing.T) {
+		rules := group(true)
+		rules[1].Data = append(rules[1].Data, ruleResultQuery(util.GenerateShortUID()))
+		require.ErrorIs(t, ValidateRuleResultQueries(rules), ErrAlertRuleFailedValidation)
+	})
+}
+
 func TestTimeRangeYAML(t *testing.T) {
 	yamlRaw := "from: 600\nto: 0\n"
 	var rtr RelativeTimeRange
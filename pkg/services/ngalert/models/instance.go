@@ -14,6 +14,17 @@ type AlertInstance struct {
 	CurrentStateSince time.Time
 	CurrentStateEnd   time.Time
 	LastEvalTime      time.Time
+	// KeepFiringSince is when the condition of a firing alert stopped being met,
+	// while the alert keeps firing for the KeepFiringFor duration of the rule.
+	KeepFiringSince time.Time
+}
+
+// AfterLoad is called by xorm once the instance is loaded from the database.
+func (i *AlertInstance) AfterLoad() {
+	// the alerts that don't keep firing are stored with zero
+	if i.KeepFiringSince.Unix() == 0 {
+		i.KeepFiringSince = time.Time{}
+	}
 }
 
 type AlertInstanceKey struct {
@@ -0,0 +1,62 @@
+package models
+
+import (
+	"time"
+
+	"github.com/prometheus/common/model"
+)
+
+// NotificationStatus is the outcome of an attempt to send a notification.
+type NotificationStatus string
+
+const (
+	NotificationStatusSuccess NotificationStatus = "success"
+	NotificationStatusFailure NotificationStatus = "failure"
+)
+
+// NotificationHistoryAlert is an alert sent in a notification.
+type NotificationHistoryAlert struct {
+	Labels      map[string]string `json:"labels"`
+	Annotations map[string]string `json:"annotations,omitempty"`
+	// Status is either firing or resolved.
+	Status   model.AlertStatus `json:"status"`
+	StartsAt time.Time         `json:"startsAt"`
+	EndsAt   time.Time         `json:"endsAt"`
+}
+
+// NotificationHistoryEntry is an attempt of an integration of a receiver to send a notification
+// for a group of alerts.
+type NotificationHistoryEntry struct {
+	ID          int64
+	OrgID       int64
+	Receiver    string
+	Integration string
+	// IntegrationIndex is the position of the integration in the receiver, to tell apart integrations of the same type.
+	IntegrationIndex int
+	GroupKey         string
+	Alerts           []NotificationHistoryAlert
+	Status           NotificationStatus
+	Error            string
+	// Retry is true if the failed attempt is retried.
+	Retry bool
+	// Payload is the rendered payload sent by the integration, if the integration sends it through Grafana:
+	// the body of webhook requests and the subject of emails.
+	// It is truncated to the maximum payload size.
+	Payload  string
+	SentAt   time.Time
+	Duration time.Duration
+}
+
+// NotificationHistoryQuery filters the notification history of an organization.
+// Empty fields do not filter the entries.
+type NotificationHistoryQuery struct {
+	OrgID       int64
+	Receiver    string
+	Integration string
+	Status      NotificationStatus
+	GroupKey    string
+	From        time.Time
+	To          time.Time
+	// Limit is the maximum number of entries returned, the most recent first.
+	Limit int
+}
@@ -0,0 +1,149 @@
+package models
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"hash/fnv"
+	"sort"
+	"time"
+
+	prommodel "github.com/prometheus/common/model"
+	"golang.org/x/exp/slices"
+)
+
+const (
+	// AutogeneratedRouteLabel is set on the alerts of rules with notification settings, which are routed
+	// by the routes generated from the settings instead of the notification policy tree.
+	AutogeneratedRouteLabel = "__grafana_autogenerated__"
+	// AutogeneratedRouteReceiverNameLabel is the contact point the alerts of a rule with notification settings are routed to.
+	AutogeneratedRouteReceiverNameLabel = "__grafana_receiver__"
+	// AutogeneratedRouteSettingsHashLabel identifies the generated route of the notification settings of a rule.
+	AutogeneratedRouteSettingsHashLabel = "__grafana_route_settings_hash__"
+)
+
+// DefaultNotificationSettingsGroupBy are the labels the alerts of rules with notification settings are always grouped by.
+var DefaultNotificationSettingsGroupBy = []string{FolderTitleLabel, prommodel.AlertNameLabel}
+
+// NotificationSettings routes the alerts of a rule directly to a contact point,
+// without matching them against the notification policy tree.
+type NotificationSettings struct {
+	Receiver          string              `json:"receiver"`
+	GroupBy           []string            `json:"group_by,omitempty"`
+	GroupWait         *prommodel.Duration `json:"group_wait,omitempty"`
+	GroupInterval     *prommodel.Duration `json:"group_interval,omitempty"`
+	RepeatInterval    *prommodel.Duration `json:"repeat_interval,omitempty"`
+	MuteTimeIntervals []string            `json:"mute_time_intervals,omitempty"`
+}
+
+// Validate checks that the settings can be turned into a route.
+// It does not check that the contact point and the mute timings exist.
+func (s *NotificationSettings) Validate() error {
+	if s.Receiver == "" {
+		return fmt.Errorf("%w: notification settings must specify a receiver", ErrAlertRuleFailedValidation)
+	}
+	seen := make(map[string]struct{}, len(s.GroupBy))
+	for _, l := range s.GroupBy {
+		if l == "..." && len(s.GroupBy) > 1 {
+			return fmt.Errorf("%w: notification settings cannot group by all labels (`...`) and other labels at the same time", ErrAlertRuleFailedValidation)
+		}
+		if _, ok := seen[l]; ok {
+			return fmt.Errorf("%w: duplicated label %q in the group_by of the notification settings", ErrAlertRuleFailedValidation, l)
+		}
+		seen[l] = struct{}{}
+	}
+	if s.GroupWait != nil && *s.GroupWait < 0 {
+		return fmt.Errorf("%w: group_wait of the notification settings cannot be negative", ErrAlertRuleFailedValidation)
+	}
+	if s.GroupInterval != nil && *s.GroupInterval <= 0 {
+		return fmt.Errorf("%w: group_interval of the notification settings must be positive", ErrAlertRuleFailedValidation)
+	}
+	if s.RepeatInterval != nil && *s.RepeatInterval <= 0 {
+		return fmt.Errorf("%w: repeat_interval of the notification settings must be positive", ErrAlertRuleFailedValidation)
+	}
+	return nil
+}
+
+// NormalizedGroupBy returns the labels the alerts are grouped by, which always include the default ones
+// unless the alerts are grouped by all labels.
+func (s *NotificationSettings) NormalizedGroupBy() []string {
+	result := make([]string, 0, len(s.GroupBy)+len(DefaultNotificationSettingsGroupBy))
+	for _, l := range s.GroupBy {
+		if l == "..." {
+			return []string{"..."}
+		}
+	}
+	for _, l := range DefaultNotificationSettingsGroupBy {
+		if !slices.Contains(s.GroupBy, l) {
+			result = append(result, l)
+		}
+	}
+	result = append(result, s.GroupBy...)
+	sort.Strings(result)
+	return result
+}
+
+// Fingerprint returns a hash of the settings that is the same for settings that produce the same route.
+func (s *NotificationSettings) Fingerprint() string {
+	h := fnv.New64a()
+	tmp := make([]byte, 8)
+	writeString := func(v string) {
+		_, _ = h.Write([]byte(v))
+		_, _ = h.Write([]byte{255})
+	}
+	writeDuration := func(d *prommodel.Duration) {
+		if d == nil {
+			_, _ = h.Write([]byte{255})
+			return
+		}
+		binary.LittleEndian.PutUint64(tmp, uint64(time.Duration(*d)))
+		_, _ = h.Write(tmp)
+	}
+
+	writeString(s.Receiver)
+	for _, l := range s.NormalizedGroupBy() {
+		writeString(l)
+	}
+	writeDuration(s.GroupWait)
+	writeDuration(s.GroupInterval)
+	writeDuration(s.RepeatInterval)
+	muteTimeIntervals := append([]string(nil), s.MuteTimeIntervals...)
+	sort.Strings(muteTimeIntervals)
+	for _, m := range muteTimeIntervals {
+		writeString(m)
+	}
+	return fmt.Sprintf("%016x", h.Sum64())
+}
+
+// Labels returns the labels that route the alerts of the rule to the route generated from the settings.
+func (s *NotificationSettings) Labels() map[string]string {
+	return map[string]string{
+		AutogeneratedRouteLabel:             "true",
+		AutogeneratedRouteReceiverNameLabel: s.Receiver,
+		AutogeneratedRouteSettingsHashLabel: s.Fingerprint(),
+	}
+}
+
+// FromDB loads the settings stored as JSON.
+func (s *NotificationSettings) FromDB(b []byte) error {
+	if len(b) == 0 {
+		return nil
+	}
+	return json.Unmarshal(b, s)
+}
+
+// ToDB stores the settings as JSON.
+func (s *NotificationSettings) ToDB() ([]byte, error) {
+	if s == nil {
+		return nil, nil
+	}
+	return json.Marshal(s)
+}
+
+// orNil returns nil for the empty settings xorm loads for rules stored without notification settings.
+func (s *NotificationSettings) orNil() *NotificationSettings {
+	if s == nil || s.Receiver == "" {
+		return nil
+	}
+	return s
+}
@@ -0,0 +1,199 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"time"
+)
+
+var (
+	// ErrRecurringSilenceNotFound is an error for an unknown recurring silence.
+	ErrRecurringSilenceNotFound = errors.New("could not find recurring silence")
+	// ErrRecurringSilenceFailedValidation is an error for an invalid recurring silence.
+	ErrRecurringSilenceFailedValidation = errors.New("invalid recurring silence")
+)
+
+// RecurringSilenceFrequency is the unit of the period after which a recurring silence repeats.
+type RecurringSilenceFrequency string
+
+const (
+	// RecurringSilenceFrequencyNone is the frequency of a silence that does not repeat. It is scheduled once.
+	RecurringSilenceFrequencyNone    RecurringSilenceFrequency = ""
+	RecurringSilenceFrequencyDaily   RecurringSilenceFrequency = "daily"
+	RecurringSilenceFrequencyWeekly  RecurringSilenceFrequency = "weekly"
+	RecurringSilenceFrequencyMonthly RecurringSilenceFrequency = "monthly"
+)
+
+// RecurringSilenceMatcher is a label matcher of the silences created for a recurring silence.
+type RecurringSilenceMatcher struct {
+	Name    string `json:"name"`
+	Value   string `json:"value"`
+	IsRegex bool   `json:"isRegex"`
+	IsEqual bool   `json:"isEqual"`
+}
+
+// RecurringSilenceOccurrence is a silence created in the Alertmanager for an occurrence of a recurring silence.
+type RecurringSilenceOccurrence struct {
+	SilenceID string    `json:"silenceId"`
+	StartsAt  time.Time `json:"startsAt"`
+	EndsAt    time.Time `json:"endsAt"`
+}
+
+// RecurringSilence is a silence that is scheduled ahead of time and, optionally, repeats with a daily, weekly or
+// monthly frequency. Its occurrences are expanded into silences of the Alertmanager shortly before they start.
+type RecurringSilence struct {
+	ID        int64  `xorm:"pk autoincr 'id'"`
+	OrgID     int64  `xorm:"org_id"`
+	UID       string `xorm:"uid"`
+	Matchers  []RecurringSilenceMatcher
+	Comment   string
+	CreatedBy string
+	// StartsAt is the start of the first occurrence. The following occurrences start at the same wall clock time
+	// in the time zone of the silence.
+	StartsAt time.Time
+	Duration time.Duration
+	// Frequency and Interval define the period of the occurrences, e.g. every 2 weeks.
+	Frequency RecurringSilenceFrequency
+	Interval  int64 `xorm:"repeat_interval"`
+	// Until is the time after which no occurrence starts. The silence repeats forever if it is not set.
+	Until    *time.Time `xorm:"repeat_until"`
+	Timezone string
+	// ExpandedUntil is the time before which all occurrences that start were expanded into silences.
+	ExpandedUntil time.Time
+	// Silences are the silences created for the occurrences that have not ended.
+	Silences []RecurringSilenceOccurrence
+	Version  int64 `xorm:"version"` // this tag makes xorm add optimistic lock (see https://xorm.io/docs/chapter-06/1.lock/)
+	Updated  time.Time
+}
+
+// A XORM interface that defines the used table for this struct.
+func (s *RecurringSilence) TableName() string {
+	return "alert_recurring_silence"
+}
+
+// Location returns the time zone in which the occurrences of the silence are computed.
+func (s *RecurringSilence) Location() (*time.Location, error) {
+	return time.LoadLocation(s.Timezone)
+}
+
+// Validate checks the matchers, the schedule and the time zone of the recurring silence.
+func (s *RecurringSilence) Validate() error {
+	if len(s.Matchers) == 0 {
+		return fmt.Errorf("%w: at least one matcher is required", ErrRecurringSilenceFailedValidation)
+	}
+	for _, m := range s.Matchers {
+		if m.Name == "" {
+			return fmt.Errorf("%w: matcher name cannot be empty", ErrRecurringSilenceFailedValidation)
+		}
+		if m.IsRegex {
+			if _, err := regexp.Compile("^(?:" + m.Value + ")$"); err != nil {
+				return fmt.Errorf("%w: invalid regular expression of the matcher %q: %s", ErrRecurringSilenceFailedValidation, m.Name, err)
+			}
+		}
+	}
+	if s.Comment == "" {
+		return fmt.Errorf("%w: comment cannot be empty", ErrRecurringSilenceFailedValidation)
+	}
+	if s.StartsAt.IsZero() {
+		return fmt.Errorf("%w: start time is required", ErrRecurringSilenceFailedValidation)
+	}
+	if s.Duration <= 0 {
+		return fmt.Errorf("%w: duration must be positive", ErrRecurringSilenceFailedValidation)
+	}
+	if s.Until != nil && s.Until.Before(s.StartsAt) {
+		return fmt.Errorf("%w: end of the recurrence cannot be before the start time", ErrRecurringSilenceFailedValidation)
+	}
+	if _, err := s.Location(); err != nil {
+		return fmt.Errorf("%w: unknown time zone %q", ErrRecurringSilenceFailedValidation, s.Timezone)
+	}
+	if s.Frequency == RecurringSilenceFrequencyNone {
+		return nil
+	}
+	if s.Interval < 1 {
+		return fmt.Errorf("%w: interval must be at least 1", ErrRecurringSilenceFailedValidation)
+	}
+	var minPeriodDays int64
+	switch s.Frequency {
+	case RecurringSilenceFrequencyDaily:
+		minPeriodDays = s.Interval
+	case RecurringSilenceFrequencyWeekly:
+		minPeriodDays = 7 * s.Interval
+	case RecurringSilenceFrequencyMonthly:
+		minPeriodDays = 28 * s.Interval
+	default:
+		return fmt.Errorf("%w: unknown frequency %q", ErrRecurringSilenceFailedValidation, s.Frequency)
+	}
+	// occurrences must not overlap, otherwise the same alerts would be silenced by several silences
+	if s.Duration > time.Duration(minPeriodDays)*24*time.Hour {
+		return fmt.Errorf("%w: duration %s is longer than the period of the occurrences", ErrRecurringSilenceFailedValidation, s.Duration)
+	}
+	return nil
+}
+
+// Occurrences returns the start times of the occurrences of the silence that end after from and start before to.
+// Monthly occurrences of days that do not exist in a month are normalized the way time.AddDate does, e.g.
+// the occurrence after January 31 is March 3 (or 2 in leap years).
+func (s *RecurringSilence) Occurrences(from, to time.Time) ([]time.Time, error) {
+	loc, err := s.Location()
+	if err != nil {
+		return nil, err
+	}
+	start := s.StartsAt.In(loc)
+
+	var result []time.Time
+	for k := s.firstOccurrenceBefore(start, from); ; k++ {
+		occurrence := s.occurrence(start, k)
+		if !occurrence.Before(to) || (s.Until != nil && occurrence.After(*s.Until)) {
+			break
+		}
+		if occurrence.Add(s.Duration).After(from) {
+			result = append(result, occurrence)
+		}
+		if s.Frequency == RecurringSilenceFrequencyNone {
+			break
+		}
+	}
+	return result, nil
+}
+
+// occurrence returns the start time of the k-th occurrence of the silence.
+func (s *RecurringSilence) occurrence(start time.Time, k int64) time.Time {
+	n := int(k * s.Interval)
+	switch s.Frequency {
+	case RecurringSilenceFrequencyDaily:
+		return start.AddDate(0, 0, n)
+	case RecurringSilenceFrequencyWeekly:
+		return start.AddDate(0, 0, 7*n)
+	case RecurringSilenceFrequencyMonthly:
+		return start.AddDate(0, n, 0)
+	default:
+		return start
+	}
+}
+
+// firstOccurrenceBefore returns the index of an occurrence that is not after the first occurrence ending after from,
+// so that the occurrences of long-running silences are not enumerated from the first one.
+func (s *RecurringSilence) firstOccurrenceBefore(start, from time.Time) int64 {
+	if s.Interval < 1 || !from.After(start.Add(s.Duration)) {
+		return 0
+	}
+	var k int64
+	switch s.Frequency {
+	case RecurringSilenceFrequencyDaily, RecurringSilenceFrequencyWeekly:
+		days := s.Interval
+		if s.Frequency == RecurringSilenceFrequencyWeekly {
+			days *= 7
+		}
+		// daylight saving time changes shift the occurrences by at most a few hours, one period is enough margin
+		k = int64(from.Sub(start)/(time.Duration(days)*24*time.Hour)) - 1
+	case RecurringSilenceFrequencyMonthly:
+		from = from.In(start.Location())
+		months := int64(from.Year()-start.Year())*12 + int64(from.Month()-start.Month())
+		k = months/s.Interval - 2
+	}
+	if k < 0 {
+		return 0
+	}
+	return k
+}
@@ -0,0 +1,194 @@
+package models
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRecurringSilence_Validate(t *testing.T) {
+	valid := func() RecurringSilence {
+		return RecurringSilence{
+			Matchers:  []RecurringSilenceMatcher{{Name: "service", Value: "api", IsEqual: true}},
+			Comment:   "Weekly maintenance",
+			StartsAt:  time.Date(2023, 10, 1, 22, 0, 0, 0, time.UTC),
+			Duration:  2 * time.Hour,
+			Frequency: RecurringSilenceFrequencyWeekly,
+			Interval:  1,
+		}
+	}
+
+	testCases := []struct {
+		name   string
+		mutate func(s *RecurringSilence)
+		err    string
+	}{
+		{
+			name:   "valid",
+			mutate: func(s *RecurringSilence) {},
+		},
+		{
+			name: "no frequency needs no interval",
+			mutate: func(s *RecurringSilence) {
+				s.Frequency = RecurringSilenceFrequencyNone
+				s.Interval = 0
+			},
+		},
+		{
+			name:   "no matchers",
+			mutate: func(s *RecurringSilence) { s.Matchers = nil },
+			err:    "at least one matcher is required",
+		},
+		{
+			name: "invalid regular expression",
+			mutate: func(s *RecurringSilence) {
+				s.Matchers[0] = RecurringSilenceMatcher{Name: "service", Value: "(", IsRegex: true}
+			},
+			err: "invalid regular expression",
+		},
+		{
+			name:   "no comment",
+			mutate: func(s *RecurringSilence) { s.Comment = "" },
+			err:    "comment cannot be empty",
+		},
+		{
+			name:   "no duration",
+			mutate: func(s *RecurringSilence) { s.Duration = 0 },
+			err:    "duration must be positive",
+		},
+		{
+			name:   "unknown time zone",
+			mutate: func(s *RecurringSilence) { s.Timezone = "Mars/Olympus_Mons" },
+			err:    "unknown time zone",
+		},
+		{
+			name:   "unknown frequency",
+			mutate: func(s *RecurringSilence) { s.Frequency = "yearly" },
+			err:    "unknown frequency",
+		},
+		{
+			name:   "no interval",
+			mutate: func(s *RecurringSilence) { s.Interval = 0 },
+			err:    "interval must be at least 1",
+		},
+		{
+			name:   "overlapping occurrences",
+			mutate: func(s *RecurringSilence) { s.Duration = 8 * 24 * time.Hour },
+			err:    "longer than the period",
+		},
+		{
+			name: "until before start",
+			mutate: func(s *RecurringSilence) {
+				until := s.StartsAt.Add(-time.Hour)
+				s.Until = &until
+			},
+			err: "cannot be before the start time",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			s := valid()
+			tc.mutate(&s)
+			err := s.Validate()
+			if tc.err == "" {
+				require.NoError(t, err)
+				return
+			}
+			require.ErrorIs(t, err, ErrRecurringSilenceFailedValidation)
+			require.ErrorContains(t, err, tc.err)
+		})
+	}
+}
+
+func TestRecurringSilence_Occurrences(t *testing.T) {
+	berlin, err := time.LoadLocation("Europe/Berlin")
+	require.NoError(t, err)
+
+	t.Run("once", func(t *testing.T) {
+		s := RecurringSilence{StartsAt: time.Date(2023, 10, 1, 22, 0, 0, 0, time.UTC), Duration: time.Hour}
+
+		occurrences, err := s.Occurrences(s.StartsAt.Add(-24*time.Hour), s.StartsAt.Add(30*24*time.Hour))
+		require.NoError(t, err)
+		require.Equal(t, []time.Time{s.StartsAt}, occurrences)
+
+		occurrences, err = s.Occurrences(s.StartsAt.Add(time.Hour), s.StartsAt.Add(30*24*time.Hour))
+		require.NoError(t, err)
+		require.Empty(t, occurrences)
+	})
+
+	t.Run("includes the occurrence in progress", func(t *testing.T) {
+		s := RecurringSilence{
+			StartsAt:  time.Date(2023, 10, 1, 22, 0, 0, 0, time.UTC),
+			Duration:  2 * time.Hour,
+			Frequency: RecurringSilenceFrequencyDaily,
+			Interval:  1,
+		}
+		from := time.Date(2023, 10, 20, 23, 0, 0, 0, time.UTC)
+
+		occurrences, err := s.Occurrences(from, from.Add(24*time.Hour))
+		require.NoError(t, err)
+		require.Equal(t, []time.Time{
+			time.Date(2023, 10, 20, 22, 0, 0, 0, time.UTC),
+			time.Date(2023, 10, 21, 22, 0, 0, 0, time.UTC),
+		}, toUTC(occurrences))
+	})
+
+	t.Run("weekly with interval keeps the wall clock time across daylight saving time changes", func(t *testing.T) {
+		s := RecurringSilence{
+			StartsAt:  time.Date(2023, 10, 15, 2, 0, 0, 0, berlin),
+			Duration:  time.Hour,
+			Frequency: RecurringSilenceFrequencyWeekly,
+			Interval:  2,
+			Timezone:  "Europe/Berlin",
+		}
+
+		occurrences, err := s.Occurrences(s.StartsAt, time.Date(2023, 11, 20, 0, 0, 0, 0, berlin))
+		require.NoError(t, err)
+		require.Equal(t, []time.Time{
+			time.Date(2023, 10, 15, 0, 0, 0, 0, time.UTC),
+			time.Date(2023, 10, 29, 1, 0, 0, 0, time.UTC),
+			time.Date(2023, 11, 12, 1, 0, 0, 0, time.UTC),
+		}, toUTC(occurrences))
+	})
+
+	t.Run("monthly until", func(t *testing.T) {
+		until := time.Date(2024, 3, 15, 0, 0, 0, 0, time.UTC)
+		s := RecurringSilence{
+			StartsAt:  time.Date(2023, 1, 15, 6, 0, 0, 0, time.UTC),
+			Duration:  time.Hour,
+			Frequency: RecurringSilenceFrequencyMonthly,
+			Interval:  1,
+			Until:     &until,
+		}
+
+		occurrences, err := s.Occurrences(time.Date(2023, 12, 20, 0, 0, 0, 0, time.UTC), time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC))
+		require.NoError(t, err)
+		require.Equal(t, []time.Time{
+			time.Date(2024, 1, 15, 6, 0, 0, 0, time.UTC),
+			time.Date(2024, 2, 15, 6, 0, 0, 0, time.UTC),
+		}, toUTC(occurrences))
+	})
+
+	t.Run("not started yet", func(t *testing.T) {
+		s := RecurringSilence{
+			StartsAt:  time.Date(2023, 10, 1, 22, 0, 0, 0, time.UTC),
+			Duration:  time.Hour,
+			Frequency: RecurringSilenceFrequencyDaily,
+			Interval:  1,
+		}
+
+		occurrences, err := s.Occurrences(time.Date(2023, 9, 1, 0, 0, 0, 0, time.UTC), time.Date(2023, 10, 2, 0, 0, 0, 0, time.UTC))
+		require.NoError(t, err)
+		require.Equal(t, []time.Time{s.StartsAt}, toUTC(occurrences))
+	})
+}
+
+func toUTC(times []time.Time) []time.Time {
+	result := make([]time.Time, 0, len(times))
+	for _, t := range times {
+		result = append(result, t.UTC())
+	}
+	return result
+}
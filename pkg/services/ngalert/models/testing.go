@@ -164,6 +164,14 @@ func WithNamespace(namespace *folder.Folder) AlertRuleMutator {
 	}
 }
 
+func WithGroupKey(groupKey AlertRuleGroupKey) AlertRuleMutator {
+	return func(rule *AlertRule) {
+		rule.OrgID = groupKey.OrgID
+		rule.NamespaceUID = groupKey.NamespaceUID
+		rule.RuleGroup = groupKey.RuleGroup
+	}
+}
+
 func WithInterval(interval time.Duration) AlertRuleMutator {
 	return func(rule *AlertRule) {
 		rule.IntervalSeconds = int64(interval.Seconds())
@@ -188,6 +196,12 @@ func WithForNTimes(timesOfInterval int64) AlertRuleMutator {
 	}
 }
 
+func WithKeepFiringFor(duration time.Duration) AlertRuleMutator {
+	return func(rule *AlertRule) {
+		rule.KeepFiringFor = duration
+	}
+}
+
 func WithNoDataExecAs(nodata NoDataState) AlertRuleMutator {
 	return func(rule *AlertRule) {
 		rule.NoDataState = nodata
@@ -253,6 +267,31 @@ func WithQuery(query ...AlertQuery) AlertRuleMutator {
 	}
 }
 
+// WithRecord makes the rule a recording rule writing the results of its first query to the metric.
+func WithRecord(metric string) AlertRuleMutator {
+	return func(rule *AlertRule) {
+		if len(rule.Data) > 0 {
+			rule.Condition = rule.Data[0].RefID
+		}
+		rule.Record = &Record{Metric: metric, From: rule.Condition}
+		rule.For = 0
+	}
+}
+
+// WithNotificationSettings makes the rule send its alerts directly to the contact point.
+func WithNotificationSettings(settings NotificationSettings) AlertRuleMutator {
+	return func(rule *AlertRule) {
+		rule.NotificationSettings = &settings
+	}
+}
+
+// WithTemplate makes the rule an instance of the alert rule template with the parameters.
+func WithTemplate(uid string, parameters map[string]string) AlertRuleMutator {
+	return func(rule *AlertRule) {
+		rule.Template = &AlertRuleTemplateRef{UID: uid, Parameters: parameters}
+	}
+}
+
 func GenerateAlertLabels(count int, prefix string) data.Labels {
 	labels := make(data.Labels, count)
 	for i := 0; i < count; i++ {
@@ -345,6 +384,8 @@ func CopyRule(r *AlertRule) *AlertRule {
 		NoDataState:     r.NoDataState,
 		ExecErrState:    r.ExecErrState,
 		For:             r.For,
+		KeepFiringFor:   r.KeepFiringFor,
+		Sequential:      r.Sequential,
 	}
 
 	if r.DashboardUID != nil {
@@ -382,6 +423,29 @@ func CopyRule(r *AlertRule) *AlertRule {
 		}
 	}
 
+	if r.Record != nil {
+		record := *r.Record
+		result.Record = &record
+	}
+
+	if r.NotificationSettings != nil {
+		settings := *r.NotificationSettings
+		settings.GroupBy = append([]string(nil), r.NotificationSettings.GroupBy...)
+		settings.MuteTimeIntervals = append([]string(nil), r.NotificationSettings.MuteTimeIntervals...)
+		result.NotificationSettings = &settings
+	}
+
+	if r.Template != nil {
+		template := AlertRuleTemplateRef{UID: r.Template.UID, Annotations: append([]string(nil), r.Template.Annotations...)}
+		if r.Template.Parameters != nil {
+			template.Parameters = make(map[string]string, len(r.Template.Parameters))
+			for k, v := range r.Template.Parameters {
+				template.Parameters[k] = v
+			}
+		}
+		result.Template = &template
+	}
+
 	return &result
 }
 
@@ -19,6 +19,7 @@ import (
 	"github.com/grafana/grafana/pkg/infra/tracing"
 	"github.com/grafana/grafana/pkg/services/accesscontrol"
 	"github.com/grafana/grafana/pkg/services/annotations"
+	"github.com/grafana/grafana/pkg/services/cleanup/retention"
 	"github.com/grafana/grafana/pkg/services/dashboards"
 	"github.com/grafana/grafana/pkg/services/datasourceproxy"
 	"github.com/grafana/grafana/pkg/services/datasources"
@@ -36,14 +37,17 @@ import (
 	"github.com/grafana/grafana/pkg/services/ngalert/remote"
 	"github.com/grafana/grafana/pkg/services/ngalert/schedule"
 	"github.com/grafana/grafana/pkg/services/ngalert/sender"
+	"github.com/grafana/grafana/pkg/services/ngalert/sharding"
 	"github.com/grafana/grafana/pkg/services/ngalert/state"
 	"github.com/grafana/grafana/pkg/services/ngalert/state/historian"
 	"github.com/grafana/grafana/pkg/services/ngalert/store"
+	"github.com/grafana/grafana/pkg/services/ngalert/writer"
 	"github.com/grafana/grafana/pkg/services/notifications"
 	"github.com/grafana/grafana/pkg/services/pluginsintegration/pluginstore"
 	"github.com/grafana/grafana/pkg/services/quota"
 	"github.com/grafana/grafana/pkg/services/rendering"
 	"github.com/grafana/grafana/pkg/services/secrets"
+	"github.com/grafana/grafana/pkg/services/supportbundles"
 	"github.com/grafana/grafana/pkg/setting"
 )
 
@@ -72,6 +76,8 @@ func ProvideService(
 	tracer tracing.Tracer,
 	ruleStore *store.DBstore,
 	upgradeService migration.UpgradeService,
+	retentionJobs retention.JobRegistry,
+	bundleRegistry supportbundles.Service,
 
 	// This is necessary to ensure the guardian provider is initialized before we run the migration.
 	_ *guardian.Provider,
@@ -102,6 +108,8 @@ func ProvideService(
 		tracer:               tracer,
 		store:                ruleStore,
 		upgradeService:       upgradeService,
+		retentionJobs:        retentionJobs,
+		bundleRegistry:       bundleRegistry,
 	}
 
 	// Migration is called even if UA is disabled. If UA is disabled, this will do nothing except handle logic around
@@ -141,6 +149,7 @@ type AlertNG struct {
 	renderService       rendering.Service
 	ImageService        image.ImageService
 	schedule            schedule.ScheduleService
+	sharding            *sharding.Service
 	stateManager        *state.Manager
 	folderService       folder.Service
 	dashboardService    dashboards.DashboardService
@@ -148,6 +157,8 @@ type AlertNG struct {
 
 	// Alerting notification services
 	MultiOrgAlertmanager *notifier.MultiOrgAlertmanager
+	recurringSilences    *notifier.RecurringSilenceService
+	notificationHistory  *notifier.NotificationHistorian
 	AlertsRouter         *sender.AlertsRouter
 	accesscontrol        accesscontrol.AccessControl
 	accesscontrolService accesscontrol.Service
@@ -159,6 +170,8 @@ type AlertNG struct {
 	tracer       tracing.Tracer
 
 	upgradeService migration.UpgradeService
+	retentionJobs  retention.JobRegistry
+	bundleRegistry supportbundles.Service
 }
 
 func (ng *AlertNG) init() error {
@@ -182,6 +195,22 @@ func (ng *AlertNG) init() error {
 
 		overrides = append(overrides, override)
 	}
+	if ng.Cfg.UnifiedAlerting.NotificationHistory.Enabled {
+		ng.notificationHistory = notifier.NewNotificationHistorian(ng.store, ng.Cfg.UnifiedAlerting.NotificationHistory.MaxPayloadSize, clock.New(), ng.Log.New("component", "notification-history"))
+		overrides = append(overrides, notifier.WithNotificationHistorian(ng.notificationHistory))
+		ng.retentionJobs.RegisterRetentionJob(retention.Job{
+			DataType:  "alert_notification_history",
+			OrgScoped: true,
+			Default:   retention.Policy{MaxAge: 7 * 24 * time.Hour},
+			Apply: retention.Table{
+				Store:      ng.SQLStore,
+				Table:      "alert_notification_history",
+				TimeColumn: "sent_at",
+				TimeUnit:   time.Millisecond,
+				OrgColumn:  "org_id",
+			}.Apply,
+		})
+	}
 	ng.MultiOrgAlertmanager, err = notifier.NewMultiOrgAlertmanager(ng.Cfg, ng.store, ng.store, ng.KVStore, ng.store, decryptFn, multiOrgMetrics, ng.NotificationService, log.New("ngalert.multiorg.alertmanager"), ng.SecretsService, overrides...)
 	if err != nil {
 		return err
@@ -216,6 +245,11 @@ func (ng *AlertNG) init() error {
 
 	ng.AlertsRouter = alertsRouter
 
+	recordingWriter, err := createRecordingWriter(ng.Cfg.UnifiedAlerting.RecordingRules)
+	if err != nil {
+		return fmt.Errorf("failed to initialize recording rules writer: %w", err)
+	}
+
 	evalFactory := eval.NewEvaluatorFactory(ng.Cfg.UnifiedAlerting, ng.DataSourceCache, ng.ExpressionService, ng.pluginsStore)
 	schedCfg := schedule.SchedulerCfg{
 		MaxAttempts:          ng.Cfg.UnifiedAlerting.MaxAttempts,
@@ -228,10 +262,25 @@ func (ng *AlertNG) init() error {
 		RuleStore:            ng.store,
 		Metrics:              ng.Metrics.GetSchedulerMetrics(),
 		AlertSender:          alertsRouter,
+		RecordingWriter:      recordingWriter,
 		Tracer:               ng.tracer,
 		Log:                  log.New("ngalert.scheduler"),
 	}
 
+	if ng.Cfg.UnifiedAlerting.ExecuteAlerts && ng.Cfg.UnifiedAlerting.HAEvaluationSharding {
+		replicaID := ng.Cfg.UnifiedAlerting.HAReplicaID
+		if replicaID == "" {
+			replicaID, err = sharding.DefaultReplicaID(ng.Cfg.HTTPPort)
+			if err != nil {
+				return fmt.Errorf("failed to initialize sharding of rule evaluation: %w", err)
+			}
+		}
+		ng.sharding = sharding.NewService(replicaID, ng.Cfg.UnifiedAlerting.HAHeartbeatInterval, ng.Cfg.UnifiedAlerting.HAHeartbeatTimeout, ng.store, alertsRouter, clk, log.New("ngalert.sharding"))
+		schedCfg.Sharder = ng.sharding
+		// the alerts are shared with the other replicas so that every Alertmanager receives the alerts of all the rules
+		schedCfg.AlertSender = ng.sharding
+	}
+
 	// There are a set of feature toggles available that act as short-circuits for common configurations.
 	// If any are set, override the config accordingly.
 	applyStateHistoryFeatureToggles(&ng.Cfg.UnifiedAlerting.StateHistory, ng.FeatureToggles, ng.Log)
@@ -239,16 +288,39 @@ func (ng *AlertNG) init() error {
 	if err != nil {
 		return err
 	}
+	if storesHistoryInAnnotations(ng.Cfg.UnifiedAlerting.StateHistory) {
+		// State history stored as annotations is also removed by the annotations cleanup according to the
+		// max_annotation_age setting of [alerting], this job lets retention policies be configured per organization.
+		ng.retentionJobs.RegisterRetentionJob(retention.Job{
+			DataType:  "alert_state_history",
+			OrgScoped: true,
+			Apply: retention.Table{
+				Store:       ng.SQLStore,
+				Table:       "annotation",
+				TimeColumn:  "epoch",
+				TimeUnit:    time.Millisecond,
+				OrgColumn:   "org_id",
+				Where:       "alert_id <> 0",
+				AfterDelete: "DELETE FROM annotation_tag WHERE NOT EXISTS (SELECT 1 FROM annotation a WHERE annotation_id = a.id)",
+			}.Apply,
+		})
+	}
+	instanceStore, err := configureInstanceStore(initCtx, ng.Cfg.UnifiedAlerting.StateStore, ng.store, ng.FeatureToggles, ng.Log)
+	if err != nil {
+		return fmt.Errorf("failed to initialize alert state store: %w", err)
+	}
 	cfg := state.ManagerCfg{
 		Metrics:                        ng.Metrics.GetStateMetrics(),
 		ExternalURL:                    appUrl,
-		InstanceStore:                  ng.store,
+		InstanceStore:                  instanceStore,
 		Images:                         ng.ImageService,
 		Clock:                          clk,
 		Historian:                      history,
 		DoNotSaveNormalState:           ng.FeatureToggles.IsEnabled(featuremgmt.FlagAlertingNoNormalState),
 		MaxStateSaveConcurrency:        ng.Cfg.UnifiedAlerting.MaxStateSaveConcurrency,
+		SaveStatesPerRule:              ng.Cfg.UnifiedAlerting.StateStore == setting.StateStoreCompressed,
 		ApplyNoDataAndErrorToAllStates: ng.FeatureToggles.IsEnabled(featuremgmt.FlagAlertingNoDataErrorExecution),
+		ShardedEvaluation:              ng.sharding != nil,
 		Tracer:                         ng.tracer,
 		Log:                            log.New("ngalert.state.manager"),
 	}
@@ -262,15 +334,25 @@ func (ng *AlertNG) init() error {
 
 	ng.stateManager = stateManager
 	ng.schedule = scheduler
+	if ng.sharding != nil {
+		// the API returns the states of the rules evaluated by the other replicas from the database
+		ng.sharding.OnRefresh(func(ctx context.Context) {
+			stateManager.WarmSharedStates(ctx, ng.store)
+		})
+	}
+	ng.bundleRegistry.RegisterSupportItemCollector(ng.supportBundleCollector())
 
 	// Provisioning
 	policyService := provisioning.NewNotificationPolicyService(ng.store, ng.store, ng.store, ng.Cfg.UnifiedAlerting, ng.Log)
 	contactPointService := provisioning.NewContactPointService(ng.store, ng.SecretsService, ng.store, ng.store, ng.Log, ng.accesscontrol)
 	templateService := provisioning.NewTemplateService(ng.store, ng.store, ng.store, ng.Log)
 	muteTimingService := provisioning.NewMuteTimingService(ng.store, ng.store, ng.store, ng.Log)
-	alertRuleService := provisioning.NewAlertRuleService(ng.store, ng.store, ng.dashboardService, ng.QuotaService, ng.store,
+	alertRuleService := provisioning.NewAlertRuleService(ng.store, ng.store, ng.store, ng.dashboardService, ng.QuotaService, ng.store,
 		int64(ng.Cfg.UnifiedAlerting.DefaultRuleEvaluationInterval.Seconds()),
 		int64(ng.Cfg.UnifiedAlerting.BaseInterval.Seconds()), ng.Log)
+	alertRuleTemplateService := provisioning.NewAlertRuleTemplateService(ng.store, ng.store, ng.store, ng.store, ng.Log, ng.accesscontrol)
+
+	ng.recurringSilences = notifier.NewRecurringSilenceService(ng.store, ng.MultiOrgAlertmanager, clk, ng.Log.New("component", "recurring-silences"))
 
 	ng.api = &api.API{
 		Cfg:                  ng.Cfg,
@@ -285,6 +367,8 @@ func (ng *AlertNG) init() error {
 		AdminConfigStore:     ng.store,
 		ProvenanceStore:      ng.store,
 		MultiOrgAlertmanager: ng.MultiOrgAlertmanager,
+		RecurringSilences:    ng.recurringSilences,
+		NotificationHistory:  ng.notificationHistory,
 		StateManager:         ng.stateManager,
 		AccessControl:        ng.accesscontrol,
 		Policies:             policyService,
@@ -292,6 +376,7 @@ func (ng *AlertNG) init() error {
 		Templates:            templateService,
 		MuteTimings:          muteTimingService,
 		AlertRules:           alertRuleService,
+		AlertRuleTemplates:   alertRuleTemplateService,
 		AlertsRouter:         alertsRouter,
 		EvaluatorFactory:     evalFactory,
 		FeatureManager:       ng.FeatureToggles,
@@ -355,7 +440,15 @@ func (ng *AlertNG) Run(ctx context.Context) error {
 	}
 	ng.Log.Debug("Starting")
 
-	ng.stateManager.Warm(ctx, ng.store)
+	if ng.sharding != nil {
+		// Find the rule groups evaluated by this instance before the scheduler starts.
+		// The state of each rule is loaded when its evaluation starts.
+		if err := ng.sharding.Refresh(ctx); err != nil {
+			ng.Log.Error("Failed to find the replicas evaluating alert rules", "error", err)
+		}
+	} else {
+		ng.stateManager.Warm(ctx, ng.store)
+	}
 
 	children, subCtx := errgroup.WithContext(ctx)
 
@@ -365,12 +458,25 @@ func (ng *AlertNG) Run(ctx context.Context) error {
 	children.Go(func() error {
 		return ng.AlertsRouter.Run(subCtx)
 	})
+	children.Go(func() error {
+		return ng.recurringSilences.Run(subCtx)
+	})
+	if ng.notificationHistory != nil {
+		children.Go(func() error {
+			return ng.notificationHistory.Run(subCtx)
+		})
+	}
 
 	if ng.Cfg.UnifiedAlerting.ExecuteAlerts {
 		children.Go(func() error {
 			return ng.schedule.Run(subCtx)
 		})
 	}
+	if ng.sharding != nil {
+		children.Go(func() error {
+			return ng.sharding.Run(subCtx)
+		})
+	}
 	return children.Wait()
 }
 
@@ -419,6 +525,29 @@ type Historian interface {
 	state.Historian
 }
 
+// storesHistoryInAnnotations returns whether the state history is written to the annotations,
+// either as the only backend or as one of the targets of the multi-backend mode.
+func storesHistoryInAnnotations(cfg setting.UnifiedAlertingStateHistorySettings) bool {
+	if !cfg.Enabled {
+		return false
+	}
+
+	backend, err := historian.ParseBackendType(cfg.Backend)
+	if err != nil {
+		return false
+	}
+	if backend != historian.BackendTypeMultiple {
+		return backend == historian.BackendTypeAnnotations
+	}
+
+	for _, b := range append([]string{cfg.MultiPrimary}, cfg.MultiSecondaries...) {
+		if target, err := historian.ParseBackendType(b); err == nil && target == historian.BackendTypeAnnotations {
+			return true
+		}
+	}
+	return false
+}
+
 func configureHistorianBackend(ctx context.Context, cfg setting.UnifiedAlertingStateHistorySettings, ar annotations.Repository, ds dashboards.DashboardService, rs historian.RuleStore, met *metrics.Historian, l log.Logger) (Historian, error) {
 	if !cfg.Enabled {
 		met.Info.WithLabelValues("noop").Set(0)
@@ -476,6 +605,37 @@ func configureHistorianBackend(ctx context.Context, cfg setting.UnifiedAlertingS
 	return nil, fmt.Errorf("unrecognized state history backend: %s", backend)
 }
 
+// configureInstanceStore returns the configured store of the alert states, after moving the alert states persisted
+// in the other store to it.
+func configureInstanceStore(ctx context.Context, storeType string, dbStore *store.DBstore, ft featuremgmt.FeatureToggles, l log.Logger) (state.InstanceStore, error) {
+	compressed := store.CompressedInstanceStore{
+		SQLStore:       dbStore.SQLStore,
+		FeatureToggles: ft,
+		Logger:         l,
+	}
+	switch storeType {
+	// the settings that are not read from the configuration file have no state store
+	case "", setting.StateStoreInstances:
+		if err := compressed.MigrateToAlertInstances(ctx); err != nil {
+			return nil, err
+		}
+		return dbStore, nil
+	case setting.StateStoreCompressed:
+		if err := compressed.MigrateFromAlertInstances(ctx); err != nil {
+			return nil, err
+		}
+		return compressed, nil
+	}
+	return nil, fmt.Errorf("unrecognized state store: %s", storeType)
+}
+
+func createRecordingWriter(cfg setting.RecordingRuleSettings) (schedule.RecordingWriter, error) {
+	if !cfg.Enabled {
+		return writer.NoopWriter{}, nil
+	}
+	return writer.NewPrometheusWriter(cfg, log.New("ngalert.writer"))
+}
+
 // applyStateHistoryFeatureToggles edits state history configuration to comply with currently active feature toggles.
 func applyStateHistoryFeatureToggles(cfg *setting.UnifiedAlertingStateHistorySettings, ft featuremgmt.FeatureToggles, logger log.Logger) {
 	backend, _ := historian.ParseBackendType(cfg.Backend)
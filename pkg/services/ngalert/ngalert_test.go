@@ -164,3 +164,48 @@ grafana_alerting_state_history_info{backend="noop"} 0
 		require.NoError(t, err)
 	})
 }
+
+func TestStoresHistoryInAnnotations(t *testing.T) {
+	testCases := []struct {
+		name     string
+		cfg      setting.UnifiedAlertingStateHistorySettings
+		expected bool
+	}{
+		{
+			name:     "disabled",
+			cfg:      setting.UnifiedAlertingStateHistorySettings{Enabled: false, Backend: "annotations"},
+			expected: false,
+		},
+		{
+			name:     "annotations",
+			cfg:      setting.UnifiedAlertingStateHistorySettings{Enabled: true, Backend: "annotations"},
+			expected: true,
+		},
+		{
+			name:     "loki",
+			cfg:      setting.UnifiedAlertingStateHistorySettings{Enabled: true, Backend: "loki"},
+			expected: false,
+		},
+		{
+			name:     "multiple with annotations as primary",
+			cfg:      setting.UnifiedAlertingStateHistorySettings{Enabled: true, Backend: "multiple", MultiPrimary: "annotations", MultiSecondaries: []string{"loki"}},
+			expected: true,
+		},
+		{
+			name:     "multiple with annotations as secondary",
+			cfg:      setting.UnifiedAlertingStateHistorySettings{Enabled: true, Backend: "multiple", MultiPrimary: "loki", MultiSecondaries: []string{"annotations"}},
+			expected: true,
+		},
+		{
+			name:     "multiple without annotations",
+			cfg:      setting.UnifiedAlertingStateHistorySettings{Enabled: true, Backend: "multiple", MultiPrimary: "loki"},
+			expected: false,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			require.Equal(t, tc.expected, storesHistoryInAnnotations(tc.cfg))
+		})
+	}
+}
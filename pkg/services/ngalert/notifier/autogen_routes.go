@@ -0,0 +1,125 @@
+package notifier
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/prometheus/alertmanager/pkg/labels"
+	"golang.org/x/exp/maps"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	apimodels "github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+	ngmodels "github.com/grafana/grafana/pkg/services/ngalert/models"
+)
+
+// autogenRuleStore lists the notification settings of the alert rules of an organization.
+type autogenRuleStore interface {
+	ListNotificationSettings(ctx context.Context, orgID int64) ([]ngmodels.NotificationSettings, error)
+}
+
+// addAutogeneratedRoutes adds the routes generated from the notification settings of the alert rules
+// in front of the notification policies of the configuration. The generated routes are not stored with
+// the configuration. It returns true if any route was added.
+func addAutogeneratedRoutes(ctx context.Context, logger log.Logger, store autogenRuleStore, orgID int64, cfg *apimodels.PostableUserConfig) (bool, error) {
+	if cfg.AlertmanagerConfig.Route == nil {
+		return false, nil
+	}
+	settings, err := store.ListNotificationSettings(ctx, orgID)
+	if err != nil {
+		return false, fmt.Errorf("failed to list the notification settings of alert rules: %w", err)
+	}
+	autogenRoute := generateRoutes(logger, cfg.AlertmanagerConfig, settings)
+	if autogenRoute == nil {
+		return false, nil
+	}
+
+	// copy the root route so that the routes of the caller's configuration are left untouched
+	root := *cfg.AlertmanagerConfig.Route
+	root.Routes = append([]*apimodels.Route{autogenRoute}, root.Routes...)
+	if err := root.Validate(); err != nil {
+		return false, fmt.Errorf("failed to add the routes generated from the notification settings of alert rules: %w", err)
+	}
+	cfg.AlertmanagerConfig.Route = &root
+	return true, nil
+}
+
+// generateRoutes returns the route that matches the alerts of rules with notification settings, with a
+// route for each contact point and, under it, a route for each of the distinct settings that use it.
+// Settings that refer to contact points or mute timings missing from the configuration are skipped.
+func generateRoutes(logger log.Logger, cfg apimodels.PostableApiAlertingConfig, settings []ngmodels.NotificationSettings) *apimodels.Route {
+	receivers := make(map[string]struct{}, len(cfg.Receivers))
+	for _, r := range cfg.Receivers {
+		receivers[r.Name] = struct{}{}
+	}
+	muteTimings := make(map[string]struct{}, len(cfg.MuteTimeIntervals))
+	for _, mt := range cfg.MuteTimeIntervals {
+		muteTimings[mt.Name] = struct{}{}
+	}
+
+	byReceiver := make(map[string]map[string]ngmodels.NotificationSettings)
+	for _, s := range settings {
+		if _, ok := receivers[s.Receiver]; !ok {
+			logger.Warn("Skipping notification settings of alert rules that refer to a contact point that does not exist", "receiver", s.Receiver)
+			continue
+		}
+		if missing := missingMuteTiming(s, muteTimings); missing != "" {
+			logger.Warn("Skipping notification settings of alert rules that refer to a mute timing that does not exist", "receiver", s.Receiver, "muteTiming", missing)
+			continue
+		}
+		if byReceiver[s.Receiver] == nil {
+			byReceiver[s.Receiver] = make(map[string]ngmodels.NotificationSettings)
+		}
+		byReceiver[s.Receiver][s.Fingerprint()] = s
+	}
+	if len(byReceiver) == 0 {
+		return nil
+	}
+
+	autogenRoute := &apimodels.Route{
+		Receiver:       cfg.Route.Receiver,
+		ObjectMatchers: apimodels.ObjectMatchers{mustEqualMatcher(ngmodels.AutogeneratedRouteLabel, "true")},
+	}
+	receiverNames := maps.Keys(byReceiver)
+	sort.Strings(receiverNames)
+	for _, receiver := range receiverNames {
+		receiverRoute := &apimodels.Route{
+			Receiver:       receiver,
+			ObjectMatchers: apimodels.ObjectMatchers{mustEqualMatcher(ngmodels.AutogeneratedRouteReceiverNameLabel, receiver)},
+		}
+		fingerprints := maps.Keys(byReceiver[receiver])
+		sort.Strings(fingerprints)
+		for _, fingerprint := range fingerprints {
+			s := byReceiver[receiver][fingerprint]
+			receiverRoute.Routes = append(receiverRoute.Routes, &apimodels.Route{
+				Receiver:          receiver,
+				ObjectMatchers:    apimodels.ObjectMatchers{mustEqualMatcher(ngmodels.AutogeneratedRouteSettingsHashLabel, fingerprint)},
+				GroupByStr:        s.NormalizedGroupBy(),
+				GroupWait:         s.GroupWait,
+				GroupInterval:     s.GroupInterval,
+				RepeatInterval:    s.RepeatInterval,
+				MuteTimeIntervals: s.MuteTimeIntervals,
+			})
+		}
+		autogenRoute.Routes = append(autogenRoute.Routes, receiverRoute)
+	}
+	return autogenRoute
+}
+
+func missingMuteTiming(s ngmodels.NotificationSettings, muteTimings map[string]struct{}) string {
+	for _, mt := range s.MuteTimeIntervals {
+		if _, ok := muteTimings[mt]; !ok {
+			return mt
+		}
+	}
+	return ""
+}
+
+func mustEqualMatcher(name, value string) *labels.Matcher {
+	m, err := labels.NewMatcher(labels.MatchEqual, name, value)
+	if err != nil {
+		// equality matchers are always valid
+		panic(err)
+	}
+	return m
+}
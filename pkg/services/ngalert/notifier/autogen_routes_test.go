@@ -0,0 +1,98 @@
+package notifier
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/prometheus/alertmanager/config"
+	"github.com/prometheus/common/model"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	apimodels "github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+)
+
+func TestAddAutogeneratedRoutes(t *testing.T) {
+	newConfig := func() *apimodels.PostableUserConfig {
+		return &apimodels.PostableUserConfig{
+			AlertmanagerConfig: apimodels.PostableApiAlertingConfig{
+				Config: apimodels.Config{
+					Route: &apimodels.Route{
+						Receiver: "default",
+						Routes: []*apimodels.Route{
+							{Receiver: "slack", ObjectMatchers: apimodels.ObjectMatchers{mustEqualMatcher("team", "a")}},
+						},
+					},
+					MuteTimeIntervals: []config.MuteTimeInterval{{Name: "weekends"}},
+				},
+				Receivers: []*apimodels.PostableApiReceiver{
+					{Receiver: config.Receiver{Name: "default"}},
+					{Receiver: config.Receiver{Name: "slack"}},
+				},
+			},
+		}
+	}
+	repeatInterval := model.Duration(time.Hour)
+	byTeam := models.NotificationSettings{Receiver: "slack", GroupBy: []string{"team"}, RepeatInterval: &repeatInterval}
+	muted := models.NotificationSettings{Receiver: "slack", MuteTimeIntervals: []string{"weekends"}}
+	missingReceiver := models.NotificationSettings{Receiver: "email"}
+	missingMuteTiming := models.NotificationSettings{Receiver: "slack", MuteTimeIntervals: []string{"nights"}}
+
+	store := NewFakeConfigStore(t, map[int64]*models.AlertConfiguration{})
+	store.notificationSettings = map[int64][]models.NotificationSettings{
+		1: {byTeam, muted, missingReceiver, missingMuteTiming},
+		2: {missingReceiver},
+	}
+
+	t.Run("should add the routes of the notification settings before the notification policies", func(t *testing.T) {
+		cfg := newConfig()
+		original := cfg.AlertmanagerConfig.Route
+
+		added, err := addAutogeneratedRoutes(context.Background(), log.NewNopLogger(), store, 1, cfg)
+		require.NoError(t, err)
+		require.True(t, added)
+
+		root := cfg.AlertmanagerConfig.Route
+		require.Len(t, original.Routes, 1, "the routes of the original configuration should not change")
+		require.Len(t, root.Routes, 2)
+		require.Equal(t, original.Routes[0], root.Routes[1])
+
+		autogen := root.Routes[0]
+		require.Equal(t, "default", autogen.Receiver)
+		require.False(t, autogen.Continue)
+		require.Equal(t, apimodels.ObjectMatchers{mustEqualMatcher(models.AutogeneratedRouteLabel, "true")}, autogen.ObjectMatchers)
+		require.Len(t, autogen.Routes, 1, "settings that refer to missing contact points or mute timings should be skipped")
+
+		receiverRoute := autogen.Routes[0]
+		require.Equal(t, "slack", receiverRoute.Receiver)
+		require.Equal(t, apimodels.ObjectMatchers{mustEqualMatcher(models.AutogeneratedRouteReceiverNameLabel, "slack")}, receiverRoute.ObjectMatchers)
+		require.Len(t, receiverRoute.Routes, 2)
+
+		routes := map[string]*apimodels.Route{}
+		for _, r := range receiverRoute.Routes {
+			require.Len(t, r.ObjectMatchers, 1)
+			require.Equal(t, models.AutogeneratedRouteSettingsHashLabel, r.ObjectMatchers[0].Name)
+			routes[r.ObjectMatchers[0].Value] = r
+		}
+		byTeamRoute := routes[byTeam.Fingerprint()]
+		require.NotNil(t, byTeamRoute)
+		require.Equal(t, []model.LabelName{model.AlertNameLabel, models.FolderTitleLabel, "team"}, byTeamRoute.GroupBy)
+		require.Equal(t, &repeatInterval, byTeamRoute.RepeatInterval)
+		mutedRoute := routes[muted.Fingerprint()]
+		require.NotNil(t, mutedRoute)
+		require.Equal(t, []string{"weekends"}, mutedRoute.MuteTimeIntervals)
+	})
+
+	t.Run("should not change the configuration without valid notification settings", func(t *testing.T) {
+		for _, orgID := range []int64{2, 3} {
+			cfg := newConfig()
+			original := cfg.AlertmanagerConfig.Route
+			added, err := addAutogeneratedRoutes(context.Background(), log.NewNopLogger(), store, orgID, cfg)
+			require.NoError(t, err)
+			require.False(t, added)
+			require.Same(t, original, cfg.AlertmanagerConfig.Route)
+		}
+	})
+}
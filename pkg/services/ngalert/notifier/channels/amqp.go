@@ -0,0 +1,211 @@
+package channels
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"time"
+
+	alertingLogging "github.com/grafana/alerting/logging"
+	"github.com/grafana/alerting/receivers"
+	alertingTemplates "github.com/grafana/alerting/templates"
+	"github.com/prometheus/alertmanager/types"
+	amqp "github.com/rabbitmq/amqp091-go"
+)
+
+// amqpTimeout is the timeout of the connection to the broker.
+const amqpTimeout = 10 * time.Second
+
+type AMQPConfig struct {
+	// URL of the broker, including the virtual host. The credentials are taken from the URL if Username is empty.
+	URL      string
+	Username string
+	Password string
+	// Exchange is the exchange the messages are published to. The default exchange is used if it is empty.
+	Exchange      string
+	RoutingKey    string
+	MessageFormat string
+	Title         string
+	Message       string
+	// Persistent messages survive a restart of the broker if they are routed to durable queues.
+	Persistent bool
+	TLS        TLSConfig
+}
+
+func NewAMQPConfig(jsonData json.RawMessage, decryptFn receivers.DecryptFunc) (AMQPConfig, error) {
+	rawSettings := struct {
+		URL                  string `json:"url,omitempty" yaml:"url,omitempty"`
+		Username             string `json:"username,omitempty" yaml:"username,omitempty"`
+		Password             string `json:"password,omitempty" yaml:"password,omitempty"`
+		Exchange             string `json:"exchange,omitempty" yaml:"exchange,omitempty"`
+		RoutingKey           string `json:"routingKey,omitempty" yaml:"routingKey,omitempty"`
+		MessageFormat        string `json:"messageFormat,omitempty" yaml:"messageFormat,omitempty"`
+		Title                string `json:"title,omitempty" yaml:"title,omitempty"`
+		Message              string `json:"message,omitempty" yaml:"message,omitempty"`
+		Persistent           bool   `json:"persistent,omitempty" yaml:"persistent,omitempty"`
+		InsecureSkipVerify   bool   `json:"insecureSkipVerify,omitempty" yaml:"insecureSkipVerify,omitempty"`
+		TLSCACertificate     string `json:"tlsCACertificate,omitempty" yaml:"tlsCACertificate,omitempty"`
+		TLSClientCertificate string `json:"tlsClientCertificate,omitempty" yaml:"tlsClientCertificate,omitempty"`
+		TLSClientKey         string `json:"tlsClientKey,omitempty" yaml:"tlsClientKey,omitempty"`
+	}{}
+	if err := json.Unmarshal(jsonData, &rawSettings); err != nil {
+		return AMQPConfig{}, fmt.Errorf("failed to unmarshal settings: %w", err)
+	}
+
+	if rawSettings.URL == "" {
+		return AMQPConfig{}, errors.New("required field 'url' is not specified")
+	}
+	u, err := url.Parse(rawSettings.URL)
+	if err != nil {
+		return AMQPConfig{}, fmt.Errorf("invalid URL: %w", err)
+	}
+	if u.Scheme != "amqp" && u.Scheme != "amqps" {
+		return AMQPConfig{}, fmt.Errorf("invalid URL scheme %q, must be amqp or amqps", u.Scheme)
+	}
+	if u.Host == "" {
+		return AMQPConfig{}, errors.New("invalid URL: the host is missing")
+	}
+	if rawSettings.RoutingKey == "" {
+		return AMQPConfig{}, errors.New("required field 'routingKey' is not specified")
+	}
+
+	settings := AMQPConfig{
+		URL:        rawSettings.URL,
+		Username:   rawSettings.Username,
+		Password:   decryptFn("password", rawSettings.Password),
+		Exchange:   rawSettings.Exchange,
+		RoutingKey: rawSettings.RoutingKey,
+		Title:      rawSettings.Title,
+		Message:    rawSettings.Message,
+		Persistent: rawSettings.Persistent,
+		TLS: TLSConfig{
+			InsecureSkipVerify: rawSettings.InsecureSkipVerify,
+			CACertificate:      rawSettings.TLSCACertificate,
+			ClientCertificate:  rawSettings.TLSClientCertificate,
+			ClientKey:          decryptFn("tlsClientKey", rawSettings.TLSClientKey),
+		},
+	}
+	if settings.MessageFormat, err = validateMessageFormat(rawSettings.MessageFormat); err != nil {
+		return AMQPConfig{}, err
+	}
+	if settings.Title == "" {
+		settings.Title = alertingTemplates.DefaultMessageTitleEmbed
+	}
+	if settings.Message == "" {
+		settings.Message = alertingTemplates.DefaultMessageEmbed
+	}
+	if _, err := settings.TLS.build(); err != nil {
+		return AMQPConfig{}, err
+	}
+	return settings, nil
+}
+
+// amqpPublishFunc publishes the message to the broker and waits for the broker to confirm it.
+type amqpPublishFunc func(ctx context.Context, cfg AMQPConfig, routingKey string, msg amqp.Publishing) error
+
+// AMQPNotifier publishes alert notifications to an exchange of an AMQP 0-9-1 broker, such as RabbitMQ.
+type AMQPNotifier struct {
+	*receivers.Base
+	log      alertingLogging.Logger
+	tmpl     *alertingTemplates.Template
+	orgID    int64
+	settings AMQPConfig
+	publish  amqpPublishFunc
+}
+
+func NewAMQPNotifier(cfg AMQPConfig, meta receivers.Metadata, template *alertingTemplates.Template, logger alertingLogging.Logger, orgID int64) *AMQPNotifier {
+	return &AMQPNotifier{
+		Base:     receivers.NewBase(meta),
+		log:      logger,
+		tmpl:     template,
+		orgID:    orgID,
+		settings: cfg,
+		publish:  publishAMQP,
+	}
+}
+
+// Notify publishes the notification. The notification is retried if it cannot be published to the broker.
+func (n *AMQPNotifier) Notify(ctx context.Context, as ...*types.Alert) (bool, error) {
+	var tmplErr error
+	tmpl, data := alertingTemplates.TmplText(ctx, n.tmpl, as, n.log, &tmplErr)
+
+	routingKey := tmpl(n.settings.RoutingKey)
+	if tmplErr != nil {
+		return false, fmt.Errorf("failed to template the AMQP routing key: %w", tmplErr)
+	}
+
+	payload, err := buildMessage(ctx, tmpl, data, as, n.orgID, n.settings.MessageFormat, n.settings.Title, n.settings.Message)
+	if err != nil {
+		return false, err
+	}
+	if tmplErr != nil {
+		n.log.Warn("failed to template AMQP message", "error", tmplErr.Error())
+	}
+
+	msg := amqp.Publishing{
+		ContentType: "application/json",
+		AppId:       "grafana",
+		Timestamp:   time.Now(),
+		Body:        payload,
+	}
+	if n.settings.MessageFormat == MessageFormatText {
+		msg.ContentType = "text/plain"
+	}
+	if n.settings.Persistent {
+		msg.DeliveryMode = amqp.Persistent
+	}
+
+	if err := n.publish(ctx, n.settings, routingKey, msg); err != nil {
+		n.log.Error("Failed to publish notification to AMQP broker", "error", err, "exchange", n.settings.Exchange, "routingKey", routingKey)
+		return true, err
+	}
+	return true, nil
+}
+
+func (n *AMQPNotifier) SendResolved() bool {
+	return !n.GetDisableResolveMessage()
+}
+
+func publishAMQP(ctx context.Context, cfg AMQPConfig, routingKey string, msg amqp.Publishing) error {
+	tlsCfg, err := cfg.TLS.build()
+	if err != nil {
+		return err
+	}
+	amqpCfg := amqp.Config{
+		TLSClientConfig: tlsCfg,
+		Dial:            amqp.DefaultDial(amqpTimeout),
+	}
+	if cfg.Username != "" {
+		amqpCfg.SASL = []amqp.Authentication{&amqp.PlainAuth{Username: cfg.Username, Password: cfg.Password}}
+	}
+
+	conn, err := amqp.DialConfig(cfg.URL, amqpCfg)
+	if err != nil {
+		return fmt.Errorf("failed to connect to AMQP broker: %w", err)
+	}
+	defer func() {
+		_ = conn.Close()
+	}()
+	ch, err := conn.Channel()
+	if err != nil {
+		return fmt.Errorf("failed to open AMQP channel: %w", err)
+	}
+	if err := ch.Confirm(false); err != nil {
+		return fmt.Errorf("failed to enable AMQP publisher confirms: %w", err)
+	}
+
+	confirmation, err := ch.PublishWithDeferredConfirmWithContext(ctx, cfg.Exchange, routingKey, false, false, msg)
+	if err != nil {
+		return fmt.Errorf("failed to publish AMQP message: %w", err)
+	}
+	acked, err := confirmation.WaitContext(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to publish AMQP message: %w", err)
+	}
+	if !acked {
+		return errors.New("failed to publish AMQP message: the broker rejected the message")
+	}
+	return nil
+}
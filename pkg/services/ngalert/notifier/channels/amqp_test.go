@@ -0,0 +1,197 @@
+package channels
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/url"
+	"testing"
+
+	"github.com/grafana/alerting/logging"
+	"github.com/grafana/alerting/receivers"
+	"github.com/grafana/alerting/templates"
+	"github.com/prometheus/alertmanager/notify"
+	"github.com/prometheus/alertmanager/types"
+	"github.com/prometheus/common/model"
+	amqp "github.com/rabbitmq/amqp091-go"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewAMQPConfig(t *testing.T) {
+	cases := []struct {
+		name              string
+		settings          string
+		secureSettings    map[string]string
+		expectedConfig    AMQPConfig
+		expectedInitError string
+	}{
+		{
+			name:              "Error if empty",
+			settings:          "",
+			expectedInitError: `failed to unmarshal settings`,
+		},
+		{
+			name:              "Error if URL is missing",
+			settings:          `{"routingKey": "alerts"}`,
+			expectedInitError: `required field 'url' is not specified`,
+		},
+		{
+			name:              "Error if URL scheme is invalid",
+			settings:          `{"url": "http://localhost:5672", "routingKey": "alerts"}`,
+			expectedInitError: `invalid URL scheme "http"`,
+		},
+		{
+			name:              "Error if routing key is missing",
+			settings:          `{"url": "amqp://localhost:5672"}`,
+			expectedInitError: `required field 'routingKey' is not specified`,
+		},
+		{
+			name:              "Error if CA certificate is invalid",
+			settings:          `{"url": "amqps://localhost:5671", "routingKey": "alerts", "tlsCACertificate": "invalid"}`,
+			expectedInitError: `failed to parse the CA certificate`,
+		},
+		{
+			name:     "Minimal valid configuration",
+			settings: `{"url": "amqp://localhost:5672", "routingKey": "alerts"}`,
+			expectedConfig: AMQPConfig{
+				URL:           "amqp://localhost:5672",
+				RoutingKey:    "alerts",
+				MessageFormat: MessageFormatJSON,
+				Title:         templates.DefaultMessageTitleEmbed,
+				Message:       templates.DefaultMessageEmbed,
+			},
+		},
+		{
+			name: "All fields with secrets",
+			settings: `{
+				"url": "amqps://localhost:5671/vhost",
+				"username": "user",
+				"exchange": "grafana",
+				"routingKey": "alerts.{{ .CommonLabels.alertname }}",
+				"messageFormat": "text",
+				"title": "title",
+				"message": "message",
+				"persistent": true
+			}`,
+			secureSettings: map[string]string{"password": "secret"},
+			expectedConfig: AMQPConfig{
+				URL:           "amqps://localhost:5671/vhost",
+				Username:      "user",
+				Password:      "secret",
+				Exchange:      "grafana",
+				RoutingKey:    "alerts.{{ .CommonLabels.alertname }}",
+				MessageFormat: MessageFormatText,
+				Title:         "title",
+				Message:       "message",
+				Persistent:    true,
+			},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			actual, err := NewAMQPConfig(json.RawMessage(c.settings), testDecryptFunc(c.secureSettings))
+			if c.expectedInitError != "" {
+				require.ErrorContains(t, err, c.expectedInitError)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, c.expectedConfig, actual)
+		})
+	}
+}
+
+func TestAMQPNotifier(t *testing.T) {
+	tmpl := templates.ForTests(t)
+	externalURL, err := url.Parse("http://localhost")
+	require.NoError(t, err)
+	tmpl.ExternalURL = externalURL
+
+	alerts := []*types.Alert{
+		{
+			Alert: model.Alert{
+				Labels:      model.LabelSet{"alertname": "alert1", "lbl1": "val1"},
+				Annotations: model.LabelSet{"ann1": "annv1"},
+			},
+		},
+	}
+
+	cases := []struct {
+		name            string
+		settings        AMQPConfig
+		publishErr      error
+		expRoutingKey   string
+		expContentType  string
+		expDeliveryMode uint8
+		expMsg          string
+		expRetry        bool
+		expMsgError     string
+	}{
+		{
+			name: "JSON message with templated routing key",
+			settings: AMQPConfig{
+				Exchange:      "grafana",
+				RoutingKey:    "alerts.{{ .CommonLabels.alertname }}",
+				MessageFormat: MessageFormatJSON,
+				Title:         templates.DefaultMessageTitleEmbed,
+				Message:       "{{ len .Alerts.Firing }} firing",
+			},
+			expRoutingKey:  "alerts.alert1",
+			expContentType: "application/json",
+			expMsg:         `"title":"[FIRING:1]  (alert1 val1)"`,
+		},
+		{
+			name: "Persistent text message",
+			settings: AMQPConfig{
+				RoutingKey:    "alerts",
+				MessageFormat: MessageFormatText,
+				Message:       "{{ len .Alerts.Firing }} firing",
+				Persistent:    true,
+			},
+			expRoutingKey:   "alerts",
+			expContentType:  "text/plain",
+			expDeliveryMode: amqp.Persistent,
+			expMsg:          "1 firing",
+		},
+		{
+			name: "Error is retried if the message cannot be published",
+			settings: AMQPConfig{
+				RoutingKey:    "alerts",
+				MessageFormat: MessageFormatText,
+			},
+			publishErr:  errors.New("broker unavailable"),
+			expRetry:    true,
+			expMsgError: "broker unavailable",
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			var (
+				actualRoutingKey string
+				actualMsg        amqp.Publishing
+			)
+			n := NewAMQPNotifier(c.settings, receivers.Metadata{Type: AMQPType}, tmpl, &logging.FakeLogger{}, 1)
+			n.publish = func(_ context.Context, cfg AMQPConfig, routingKey string, msg amqp.Publishing) error {
+				require.Equal(t, c.settings, cfg)
+				actualRoutingKey = routingKey
+				actualMsg = msg
+				return c.publishErr
+			}
+
+			ctx := notify.WithGroupKey(context.Background(), "alertname")
+			retry, err := n.Notify(ctx, alerts...)
+			if c.expMsgError != "" {
+				require.ErrorContains(t, err, c.expMsgError)
+				require.Equal(t, c.expRetry, retry)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, c.expRoutingKey, actualRoutingKey)
+			require.Equal(t, c.expContentType, actualMsg.ContentType)
+			require.Equal(t, c.expDeliveryMode, actualMsg.DeliveryMode)
+			require.Equal(t, "grafana", actualMsg.AppId)
+			require.Contains(t, string(actualMsg.Body), c.expMsg)
+		})
+	}
+}
@@ -0,0 +1,134 @@
+// Package channels contains the integrations of contact points that are built by Grafana rather than by the
+// alerting module. They are configured, validated and built along with the integrations of the alerting module.
+package channels
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"strings"
+
+	alertingLogging "github.com/grafana/alerting/logging"
+	alertingNotify "github.com/grafana/alerting/notify"
+	"github.com/grafana/alerting/receivers"
+	alertingTemplates "github.com/grafana/alerting/templates"
+)
+
+const (
+	MQTTType = "mqtt"
+	AMQPType = "amqp"
+)
+
+// IsSupported returns true if the integrations of the type are built by this package.
+func IsSupported(integrationType string) bool {
+	switch strings.ToLower(integrationType) {
+	case MQTTType, AMQPType:
+		return true
+	}
+	return false
+}
+
+// ReceiverConfig is the configuration of the integrations of a receiver that are built by this package.
+type ReceiverConfig struct {
+	MQTTConfigs []*alertingNotify.NotifierConfig[MQTTConfig]
+	AMQPConfigs []*alertingNotify.NotifierConfig[AMQPConfig]
+}
+
+// BuildReceiverConfiguration parses, decrypts and validates the integrations of the receiver. The configuration of
+// the integrations built by the alerting module is returned separately from the configuration of the integrations
+// built by this package.
+func BuildReceiverConfiguration(ctx context.Context, api *alertingNotify.APIReceiver, decrypt alertingNotify.GetDecryptedValueFn) (alertingNotify.GrafanaReceiverConfig, ReceiverConfig, error) {
+	var (
+		result ReceiverConfig
+		others = make([]*alertingNotify.GrafanaIntegrationConfig, 0, len(api.Integrations))
+	)
+	for _, integration := range api.Integrations {
+		if !IsSupported(integration.Type) {
+			others = append(others, integration)
+			continue
+		}
+		if err := parseIntegration(ctx, &result, integration, decrypt); err != nil {
+			return alertingNotify.GrafanaReceiverConfig{}, ReceiverConfig{}, alertingNotify.IntegrationValidationError{
+				Integration: integration,
+				Err:         err,
+			}
+		}
+	}
+
+	receiver := *api
+	receiver.Integrations = others
+	cfg, err := alertingNotify.BuildReceiverConfiguration(ctx, &receiver, decrypt)
+	if err != nil {
+		return alertingNotify.GrafanaReceiverConfig{}, ReceiverConfig{}, err
+	}
+	return cfg, result, nil
+}
+
+func parseIntegration(ctx context.Context, result *ReceiverConfig, integration *alertingNotify.GrafanaIntegrationConfig, decrypt alertingNotify.GetDecryptedValueFn) error {
+	secureSettings, err := decodeSecretsFromBase64(integration.SecureSettings)
+	if err != nil {
+		return err
+	}
+	decryptFn := func(key string, fallback string) string {
+		return decrypt(ctx, secureSettings, key, fallback)
+	}
+
+	switch strings.ToLower(integration.Type) {
+	case MQTTType:
+		cfg, err := NewMQTTConfig(integration.Settings, decryptFn)
+		if err != nil {
+			return err
+		}
+		result.MQTTConfigs = append(result.MQTTConfigs, newNotifierConfig(integration, cfg))
+	case AMQPType:
+		cfg, err := NewAMQPConfig(integration.Settings, decryptFn)
+		if err != nil {
+			return err
+		}
+		result.AMQPConfigs = append(result.AMQPConfigs, newNotifierConfig(integration, cfg))
+	default:
+		return fmt.Errorf("notifier %s is not supported", integration.Type)
+	}
+	return nil
+}
+
+// BuildReceiverIntegrations builds the integrations of the receiver configured in cfg.
+func BuildReceiverIntegrations(cfg ReceiverConfig, tmpl *alertingTemplates.Template, logger alertingLogging.LoggerFactory, orgID int64) []*alertingNotify.Integration {
+	var integrations []*alertingNotify.Integration
+	nl := func(meta receivers.Metadata) alertingLogging.Logger {
+		return logger("ngalert.notifier."+meta.Type, "notifierUID", meta.UID)
+	}
+	for i, c := range cfg.MQTTConfigs {
+		n := NewMQTTNotifier(c.Settings, c.Metadata, tmpl, nl(c.Metadata), orgID)
+		integrations = append(integrations, alertingNotify.NewIntegration(n, n, c.Type, i, c.Name))
+	}
+	for i, c := range cfg.AMQPConfigs {
+		n := NewAMQPNotifier(c.Settings, c.Metadata, tmpl, nl(c.Metadata), orgID)
+		integrations = append(integrations, alertingNotify.NewIntegration(n, n, c.Type, i, c.Name))
+	}
+	return integrations
+}
+
+func decodeSecretsFromBase64(secrets map[string]string) (map[string][]byte, error) {
+	secureSettings := make(map[string][]byte, len(secrets))
+	for k, v := range secrets {
+		d, err := base64.StdEncoding.DecodeString(v)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode secure settings key %s: %w", k, err)
+		}
+		secureSettings[k] = d
+	}
+	return secureSettings, nil
+}
+
+func newNotifierConfig[T any](integration *alertingNotify.GrafanaIntegrationConfig, settings T) *alertingNotify.NotifierConfig[T] {
+	return &alertingNotify.NotifierConfig[T]{
+		Metadata: receivers.Metadata{
+			UID:                   integration.UID,
+			Name:                  integration.Name,
+			Type:                  integration.Type,
+			DisableResolveMessage: integration.DisableResolveMessage,
+		},
+		Settings: settings,
+	}
+}
@@ -0,0 +1,87 @@
+package channels
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/grafana/alerting/logging"
+	alertingNotify "github.com/grafana/alerting/notify"
+	"github.com/grafana/alerting/templates"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBuildReceiverConfiguration(t *testing.T) {
+	decrypt := func(_ context.Context, sjd map[string][]byte, key string, fallback string) string {
+		if v, ok := sjd[key]; ok {
+			return string(v)
+		}
+		return fallback
+	}
+
+	t.Run("should split the integrations built by this package", func(t *testing.T) {
+		api := &alertingNotify.APIReceiver{
+			GrafanaIntegrations: alertingNotify.GrafanaIntegrations{
+				Integrations: []*alertingNotify.GrafanaIntegrationConfig{
+					{
+						UID:      "mqtt",
+						Name:     "receiver",
+						Type:     MQTTType,
+						Settings: json.RawMessage(`{"brokerUrl": "tcp://localhost:1883", "topic": "alerts"}`),
+						SecureSettings: map[string]string{
+							"password": base64.StdEncoding.EncodeToString([]byte("secret")),
+						},
+					},
+					{
+						UID:      "amqp",
+						Name:     "receiver",
+						Type:     AMQPType,
+						Settings: json.RawMessage(`{"url": "amqp://localhost:5672", "routingKey": "alerts"}`),
+					},
+					{
+						UID:      "webhook",
+						Name:     "receiver",
+						Type:     "webhook",
+						Settings: json.RawMessage(`{"url": "http://localhost"}`),
+					},
+				},
+			},
+		}
+
+		cfg, channelsCfg, err := BuildReceiverConfiguration(context.Background(), api, decrypt)
+		require.NoError(t, err)
+		require.Len(t, cfg.WebhookConfigs, 1)
+		require.Len(t, channelsCfg.MQTTConfigs, 1)
+		require.Equal(t, "mqtt", channelsCfg.MQTTConfigs[0].UID)
+		require.Equal(t, "secret", channelsCfg.MQTTConfigs[0].Settings.Password)
+		require.Len(t, channelsCfg.AMQPConfigs, 1)
+		require.Equal(t, "amqp", channelsCfg.AMQPConfigs[0].UID)
+		require.Len(t, api.Integrations, 3, "the receiver should not be modified")
+
+		logger := func(string, ...interface{}) logging.Logger { return &logging.FakeLogger{} }
+		integrations := BuildReceiverIntegrations(channelsCfg, templates.ForTests(t), logger, 1)
+		require.Len(t, integrations, 2)
+	})
+
+	t.Run("should return validation error of the integration", func(t *testing.T) {
+		integration := &alertingNotify.GrafanaIntegrationConfig{
+			UID:      "mqtt",
+			Name:     "receiver",
+			Type:     MQTTType,
+			Settings: json.RawMessage(`{"brokerUrl": "tcp://localhost:1883"}`),
+		}
+		api := &alertingNotify.APIReceiver{
+			GrafanaIntegrations: alertingNotify.GrafanaIntegrations{
+				Integrations: []*alertingNotify.GrafanaIntegrationConfig{integration},
+			},
+		}
+
+		_, _, err := BuildReceiverConfiguration(context.Background(), api, decrypt)
+		var validationErr alertingNotify.IntegrationValidationError
+		require.True(t, errors.As(err, &validationErr))
+		require.Equal(t, integration, validationErr.Integration)
+		require.ErrorContains(t, err, "required field 'topic' is not specified")
+	})
+}
@@ -0,0 +1,105 @@
+package channels
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/grafana/alerting/receivers"
+	alertingTemplates "github.com/grafana/alerting/templates"
+	"github.com/prometheus/alertmanager/notify"
+	"github.com/prometheus/alertmanager/types"
+	"github.com/prometheus/common/model"
+)
+
+const (
+	// MessageFormatJSON sends the alerts as a JSON object, in the same format as the webhook contact point.
+	MessageFormatJSON = "json"
+	// MessageFormatText sends the templated message only.
+	MessageFormatText = "text"
+)
+
+// brokerMessage is the JSON object published to the brokers with the JSON message format.
+type brokerMessage struct {
+	*alertingTemplates.ExtendedData
+
+	// The protocol version.
+	Version  string `json:"version"`
+	GroupKey string `json:"groupKey"`
+	OrgID    int64  `json:"orgId"`
+	Title    string `json:"title"`
+	State    string `json:"state"`
+	Message  string `json:"message"`
+}
+
+func validateMessageFormat(format string) (string, error) {
+	switch format {
+	case "":
+		return MessageFormatJSON, nil
+	case MessageFormatJSON, MessageFormatText:
+		return format, nil
+	}
+	return "", fmt.Errorf("invalid message format %q, must be %q or %q", format, MessageFormatJSON, MessageFormatText)
+}
+
+// buildMessage templates the message of the alerts in the message format.
+func buildMessage(ctx context.Context, tmpl func(string) string, data *alertingTemplates.ExtendedData, as []*types.Alert, orgID int64, format, title, message string) ([]byte, error) {
+	if format == MessageFormatText {
+		return []byte(tmpl(message)), nil
+	}
+
+	groupKey, err := notify.ExtractGroupKey(ctx)
+	if err != nil {
+		return nil, err
+	}
+	msg := &brokerMessage{
+		Version:      "1",
+		ExtendedData: data,
+		GroupKey:     groupKey.String(),
+		OrgID:        orgID,
+		Title:        tmpl(title),
+		Message:      tmpl(message),
+	}
+	if types.Alerts(as...).Status() == model.AlertFiring {
+		msg.State = string(receivers.AlertStateAlerting)
+	} else {
+		msg.State = string(receivers.AlertStateOK)
+	}
+	return json.Marshal(msg)
+}
+
+// TLSConfig is the TLS configuration of the connection to a broker.
+type TLSConfig struct {
+	InsecureSkipVerify bool
+	CACertificate      string
+	ClientCertificate  string
+	ClientKey          string
+}
+
+func (c TLSConfig) build() (*tls.Config, error) {
+	cfg := &tls.Config{
+		InsecureSkipVerify: c.InsecureSkipVerify, //nolint:gosec
+		MinVersion:         tls.VersionTLS12,
+	}
+	if c.CACertificate != "" {
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM([]byte(c.CACertificate)) {
+			return nil, errors.New("failed to parse the CA certificate")
+		}
+		cfg.RootCAs = pool
+	}
+	if c.ClientCertificate != "" || c.ClientKey != "" {
+		if c.ClientCertificate == "" || c.ClientKey == "" {
+			return nil, errors.New("both the client certificate and the client key are required")
+		}
+		cert, err := tls.X509KeyPair([]byte(c.ClientCertificate), []byte(c.ClientKey))
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse the client certificate and key: %w", err)
+		}
+		cfg.Certificates = []tls.Certificate{cert}
+	}
+	return cfg, nil
+}
@@ -0,0 +1,203 @@
+package channels
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/rand"
+	"net/url"
+	"strings"
+	"time"
+
+	mqtt "github.com/eclipse/paho.mqtt.golang"
+	alertingLogging "github.com/grafana/alerting/logging"
+	"github.com/grafana/alerting/receivers"
+	alertingTemplates "github.com/grafana/alerting/templates"
+	"github.com/prometheus/alertmanager/types"
+)
+
+// mqttTimeout is the timeout of the connection to the broker.
+const mqttTimeout = 10 * time.Second
+
+type MQTTConfig struct {
+	BrokerURL string
+	// ClientID identifies the connection to the broker. A random client ID is used if it is empty.
+	ClientID      string
+	Topic         string
+	MessageFormat string
+	Title         string
+	Message       string
+	Username      string
+	Password      string
+	QoS           byte
+	Retain        bool
+	TLS           TLSConfig
+}
+
+func NewMQTTConfig(jsonData json.RawMessage, decryptFn receivers.DecryptFunc) (MQTTConfig, error) {
+	rawSettings := struct {
+		BrokerURL            string                   `json:"brokerUrl,omitempty" yaml:"brokerUrl,omitempty"`
+		ClientID             string                   `json:"clientId,omitempty" yaml:"clientId,omitempty"`
+		Topic                string                   `json:"topic,omitempty" yaml:"topic,omitempty"`
+		MessageFormat        string                   `json:"messageFormat,omitempty" yaml:"messageFormat,omitempty"`
+		Title                string                   `json:"title,omitempty" yaml:"title,omitempty"`
+		Message              string                   `json:"message,omitempty" yaml:"message,omitempty"`
+		Username             string                   `json:"username,omitempty" yaml:"username,omitempty"`
+		Password             string                   `json:"password,omitempty" yaml:"password,omitempty"`
+		QoS                  receivers.OptionalNumber `json:"qos,omitempty" yaml:"qos,omitempty"`
+		Retain               bool                     `json:"retain,omitempty" yaml:"retain,omitempty"`
+		InsecureSkipVerify   bool                     `json:"insecureSkipVerify,omitempty" yaml:"insecureSkipVerify,omitempty"`
+		TLSCACertificate     string                   `json:"tlsCACertificate,omitempty" yaml:"tlsCACertificate,omitempty"`
+		TLSClientCertificate string                   `json:"tlsClientCertificate,omitempty" yaml:"tlsClientCertificate,omitempty"`
+		TLSClientKey         string                   `json:"tlsClientKey,omitempty" yaml:"tlsClientKey,omitempty"`
+	}{}
+	if err := json.Unmarshal(jsonData, &rawSettings); err != nil {
+		return MQTTConfig{}, fmt.Errorf("failed to unmarshal settings: %w", err)
+	}
+
+	if rawSettings.BrokerURL == "" {
+		return MQTTConfig{}, errors.New("required field 'brokerUrl' is not specified")
+	}
+	u, err := url.Parse(rawSettings.BrokerURL)
+	if err != nil {
+		return MQTTConfig{}, fmt.Errorf("invalid broker URL: %w", err)
+	}
+	switch u.Scheme {
+	case "tcp", "mqtt", "ssl", "tls", "mqtts", "ws", "wss":
+	default:
+		return MQTTConfig{}, fmt.Errorf("invalid broker URL scheme %q, must be one of tcp, mqtt, ssl, tls, mqtts, ws or wss", u.Scheme)
+	}
+	if u.Host == "" {
+		return MQTTConfig{}, errors.New("invalid broker URL: the host is missing")
+	}
+	if rawSettings.Topic == "" {
+		return MQTTConfig{}, errors.New("required field 'topic' is not specified")
+	}
+
+	settings := MQTTConfig{
+		BrokerURL: rawSettings.BrokerURL,
+		ClientID:  rawSettings.ClientID,
+		Topic:     rawSettings.Topic,
+		Title:     rawSettings.Title,
+		Message:   rawSettings.Message,
+		Username:  rawSettings.Username,
+		Password:  decryptFn("password", rawSettings.Password),
+		Retain:    rawSettings.Retain,
+		TLS: TLSConfig{
+			InsecureSkipVerify: rawSettings.InsecureSkipVerify,
+			CACertificate:      rawSettings.TLSCACertificate,
+			ClientCertificate:  rawSettings.TLSClientCertificate,
+			ClientKey:          decryptFn("tlsClientKey", rawSettings.TLSClientKey),
+		},
+	}
+	if settings.MessageFormat, err = validateMessageFormat(rawSettings.MessageFormat); err != nil {
+		return MQTTConfig{}, err
+	}
+	qos, err := rawSettings.QoS.Int64()
+	if err != nil || qos < 0 || qos > 2 {
+		return MQTTConfig{}, fmt.Errorf("invalid QoS %q, must be 0, 1 or 2", rawSettings.QoS)
+	}
+	settings.QoS = byte(qos)
+	if settings.Title == "" {
+		settings.Title = alertingTemplates.DefaultMessageTitleEmbed
+	}
+	if settings.Message == "" {
+		settings.Message = alertingTemplates.DefaultMessageEmbed
+	}
+	if _, err := settings.TLS.build(); err != nil {
+		return MQTTConfig{}, err
+	}
+	return settings, nil
+}
+
+// MQTTNotifier publishes alert notifications to a topic of an MQTT broker.
+type MQTTNotifier struct {
+	*receivers.Base
+	log      alertingLogging.Logger
+	tmpl     *alertingTemplates.Template
+	orgID    int64
+	settings MQTTConfig
+}
+
+func NewMQTTNotifier(cfg MQTTConfig, meta receivers.Metadata, template *alertingTemplates.Template, logger alertingLogging.Logger, orgID int64) *MQTTNotifier {
+	return &MQTTNotifier{
+		Base:     receivers.NewBase(meta),
+		log:      logger,
+		tmpl:     template,
+		orgID:    orgID,
+		settings: cfg,
+	}
+}
+
+// Notify publishes the notification. The notification is retried if it cannot be published to the broker.
+func (n *MQTTNotifier) Notify(ctx context.Context, as ...*types.Alert) (bool, error) {
+	var tmplErr error
+	tmpl, data := alertingTemplates.TmplText(ctx, n.tmpl, as, n.log, &tmplErr)
+
+	topic := tmpl(n.settings.Topic)
+	if tmplErr != nil {
+		return false, fmt.Errorf("failed to template the MQTT topic: %w", tmplErr)
+	}
+	if topic == "" || strings.ContainsAny(topic, "+#") {
+		return false, fmt.Errorf("invalid MQTT topic %q, it must not be empty or contain wildcards", topic)
+	}
+
+	payload, err := buildMessage(ctx, tmpl, data, as, n.orgID, n.settings.MessageFormat, n.settings.Title, n.settings.Message)
+	if err != nil {
+		return false, err
+	}
+	if tmplErr != nil {
+		n.log.Warn("failed to template MQTT message", "error", tmplErr.Error())
+	}
+
+	if err := n.publish(ctx, topic, payload); err != nil {
+		n.log.Error("Failed to publish notification to MQTT broker", "error", err, "topic", topic)
+		return true, err
+	}
+	return true, nil
+}
+
+func (n *MQTTNotifier) publish(ctx context.Context, topic string, payload []byte) error {
+	tlsCfg, err := n.settings.TLS.build()
+	if err != nil {
+		return err
+	}
+	clientID := n.settings.ClientID
+	if clientID == "" {
+		clientID = fmt.Sprintf("grafana-%08x", rand.Uint32()) // #nosec G404 -- the client ID is not a secret
+	}
+	opts := mqtt.NewClientOptions().
+		AddBroker(n.settings.BrokerURL).
+		SetClientID(clientID).
+		SetUsername(n.settings.Username).
+		SetPassword(n.settings.Password).
+		SetTLSConfig(tlsCfg).
+		SetCleanSession(true).
+		SetAutoReconnect(false).
+		SetConnectTimeout(mqttTimeout)
+
+	client := mqtt.NewClient(opts)
+	if err := waitMQTTToken(ctx, client.Connect()); err != nil {
+		return fmt.Errorf("failed to connect to MQTT broker: %w", err)
+	}
+	defer client.Disconnect(250)
+
+	if err := waitMQTTToken(ctx, client.Publish(topic, n.settings.QoS, n.settings.Retain, payload)); err != nil {
+		return fmt.Errorf("failed to publish MQTT message: %w", err)
+	}
+	return nil
+}
+
+func waitMQTTToken(ctx context.Context, t mqtt.Token) error {
+	select {
+	case <-t.Done():
+		return t.Error()
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (n *MQTTNotifier) SendResolved() bool {
+	return !n.GetDisableResolveMessage()
+}
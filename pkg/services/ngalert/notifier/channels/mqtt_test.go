@@ -0,0 +1,324 @@
+package channels
+
+import (
+	"context"
+	"encoding/json"
+	"net"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/eclipse/paho.mqtt.golang/packets"
+	"github.com/grafana/alerting/logging"
+	"github.com/grafana/alerting/receivers"
+	"github.com/grafana/alerting/templates"
+	"github.com/prometheus/alertmanager/notify"
+	"github.com/prometheus/alertmanager/types"
+	"github.com/prometheus/common/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewMQTTConfig(t *testing.T) {
+	cases := []struct {
+		name              string
+		settings          string
+		secureSettings    map[string]string
+		expectedConfig    MQTTConfig
+		expectedInitError string
+	}{
+		{
+			name:              "Error if empty",
+			settings:          "",
+			expectedInitError: `failed to unmarshal settings`,
+		},
+		{
+			name:              "Error if broker URL is missing",
+			settings:          `{"topic": "alerts"}`,
+			expectedInitError: `required field 'brokerUrl' is not specified`,
+		},
+		{
+			name:              "Error if broker URL scheme is invalid",
+			settings:          `{"brokerUrl": "http://localhost:1883", "topic": "alerts"}`,
+			expectedInitError: `invalid broker URL scheme "http"`,
+		},
+		{
+			name:              "Error if topic is missing",
+			settings:          `{"brokerUrl": "tcp://localhost:1883"}`,
+			expectedInitError: `required field 'topic' is not specified`,
+		},
+		{
+			name:              "Error if QoS is invalid",
+			settings:          `{"brokerUrl": "tcp://localhost:1883", "topic": "alerts", "qos": 3}`,
+			expectedInitError: `invalid QoS "3"`,
+		},
+		{
+			name:              "Error if message format is invalid",
+			settings:          `{"brokerUrl": "tcp://localhost:1883", "topic": "alerts", "messageFormat": "xml"}`,
+			expectedInitError: `invalid message format "xml"`,
+		},
+		{
+			name:              "Error if client key is missing",
+			settings:          `{"brokerUrl": "ssl://localhost:8883", "topic": "alerts", "tlsClientCertificate": "cert"}`,
+			expectedInitError: `both the client certificate and the client key are required`,
+		},
+		{
+			name:     "Minimal valid configuration",
+			settings: `{"brokerUrl": "tcp://localhost:1883", "topic": "alerts"}`,
+			expectedConfig: MQTTConfig{
+				BrokerURL:     "tcp://localhost:1883",
+				Topic:         "alerts",
+				MessageFormat: MessageFormatJSON,
+				Title:         templates.DefaultMessageTitleEmbed,
+				Message:       templates.DefaultMessageEmbed,
+			},
+		},
+		{
+			name: "All fields with secrets",
+			settings: `{
+				"brokerUrl": "wss://localhost/mqtt",
+				"clientId": "grafana",
+				"topic": "alerts/{{ .CommonLabels.alertname }}",
+				"messageFormat": "text",
+				"title": "title",
+				"message": "message",
+				"username": "user",
+				"password": "plain",
+				"qos": "2",
+				"retain": true,
+				"insecureSkipVerify": true
+			}`,
+			secureSettings: map[string]string{"password": "secret"},
+			expectedConfig: MQTTConfig{
+				BrokerURL:     "wss://localhost/mqtt",
+				ClientID:      "grafana",
+				Topic:         "alerts/{{ .CommonLabels.alertname }}",
+				MessageFormat: MessageFormatText,
+				Title:         "title",
+				Message:       "message",
+				Username:      "user",
+				Password:      "secret",
+				QoS:           2,
+				Retain:        true,
+				TLS:           TLSConfig{InsecureSkipVerify: true},
+			},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			actual, err := NewMQTTConfig(json.RawMessage(c.settings), testDecryptFunc(c.secureSettings))
+			if c.expectedInitError != "" {
+				require.ErrorContains(t, err, c.expectedInitError)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, c.expectedConfig, actual)
+		})
+	}
+}
+
+func TestMQTTNotifier(t *testing.T) {
+	tmpl := templates.ForTests(t)
+	externalURL, err := url.Parse("http://localhost")
+	require.NoError(t, err)
+	tmpl.ExternalURL = externalURL
+
+	alerts := []*types.Alert{
+		{
+			Alert: model.Alert{
+				Labels:      model.LabelSet{"alertname": "alert1", "lbl1": "val1"},
+				Annotations: model.LabelSet{"ann1": "annv1"},
+			},
+		},
+	}
+
+	cases := []struct {
+		name        string
+		settings    MQTTConfig
+		expTopic    string
+		expQoS      byte
+		expRetain   bool
+		expMsg      string
+		expMsgError string
+	}{
+		{
+			name: "JSON message with templated topic",
+			settings: MQTTConfig{
+				Topic:         "alerts/{{ .CommonLabels.alertname }}",
+				MessageFormat: MessageFormatJSON,
+				Title:         templates.DefaultMessageTitleEmbed,
+				Message:       "{{ len .Alerts.Firing }} firing",
+			},
+			expTopic: "alerts/alert1",
+			expMsg:   `"title":"[FIRING:1]  (alert1 val1)"`,
+		},
+		{
+			name: "Text message with QoS and retain",
+			settings: MQTTConfig{
+				Topic:         "alerts",
+				MessageFormat: MessageFormatText,
+				Message:       "{{ len .Alerts.Firing }} firing",
+				QoS:           1,
+				Retain:        true,
+			},
+			expTopic:  "alerts",
+			expQoS:    1,
+			expRetain: true,
+			expMsg:    "1 firing",
+		},
+		{
+			name: "Exactly once delivery",
+			settings: MQTTConfig{
+				Topic:         "alerts",
+				MessageFormat: MessageFormatText,
+				Message:       "{{ len .Alerts.Firing }} firing",
+				QoS:           2,
+			},
+			expTopic: "alerts",
+			expQoS:   2,
+			expMsg:   "1 firing",
+		},
+		{
+			name: "Error if topic contains wildcards",
+			settings: MQTTConfig{
+				Topic:         "alerts/#",
+				MessageFormat: MessageFormatText,
+			},
+			expMsgError: `invalid MQTT topic "alerts/#"`,
+		},
+		{
+			name: "Error if credentials are rejected",
+			settings: MQTTConfig{
+				Topic:         "alerts",
+				MessageFormat: MessageFormatText,
+				Username:      "user",
+				Password:      "wrong",
+			},
+			expMsgError: "failed to connect to MQTT broker",
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			username, password := "", ""
+			if c.settings.Username != "" {
+				username, password = "user", "password"
+			}
+			broker := newTestMQTTBroker(t, username, password)
+			c.settings.BrokerURL = "tcp://" + broker.addr
+
+			n := NewMQTTNotifier(c.settings, receivers.Metadata{Type: MQTTType}, tmpl, &logging.FakeLogger{}, 1)
+			ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+			defer cancel()
+			ctx = notify.WithGroupKey(ctx, "alertname")
+
+			ok, err := n.Notify(ctx, alerts...)
+			if c.expMsgError != "" {
+				require.ErrorContains(t, err, c.expMsgError)
+				return
+			}
+			require.NoError(t, err)
+			require.True(t, ok)
+
+			select {
+			case p := <-broker.published:
+				assert.Equal(t, c.expTopic, p.TopicName)
+				assert.Equal(t, c.expQoS, p.Qos)
+				assert.Equal(t, c.expRetain, p.Retain)
+				assert.Contains(t, string(p.Payload), c.expMsg)
+			case <-ctx.Done():
+				t.Fatal("the message was not published")
+			}
+		})
+	}
+}
+
+// testMQTTBroker is a minimal MQTT broker that accepts connections and records the published messages.
+type testMQTTBroker struct {
+	addr      string
+	username  string
+	password  string
+	published chan *packets.PublishPacket
+}
+
+func newTestMQTTBroker(t *testing.T, username, password string) *testMQTTBroker {
+	t.Helper()
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		_ = l.Close()
+	})
+
+	b := &testMQTTBroker{
+		addr:      l.Addr().String(),
+		username:  username,
+		password:  password,
+		published: make(chan *packets.PublishPacket, 10),
+	}
+	go func() {
+		for {
+			conn, err := l.Accept()
+			if err != nil {
+				return
+			}
+			go b.serve(conn)
+		}
+	}()
+	return b
+}
+
+func (b *testMQTTBroker) serve(conn net.Conn) {
+	defer func() {
+		_ = conn.Close()
+	}()
+	for {
+		cp, err := packets.ReadPacket(conn)
+		if err != nil {
+			return
+		}
+		var reply packets.ControlPacket
+		switch p := cp.(type) {
+		case *packets.ConnectPacket:
+			ack := packets.NewControlPacket(packets.Connack).(*packets.ConnackPacket)
+			if p.Username != b.username || string(p.Password) != b.password {
+				ack.ReturnCode = packets.ErrRefusedBadUsernameOrPassword
+			}
+			reply = ack
+		case *packets.PublishPacket:
+			b.published <- p
+			switch p.Qos {
+			case 1:
+				ack := packets.NewControlPacket(packets.Puback).(*packets.PubackPacket)
+				ack.MessageID = p.MessageID
+				reply = ack
+			case 2:
+				rec := packets.NewControlPacket(packets.Pubrec).(*packets.PubrecPacket)
+				rec.MessageID = p.MessageID
+				reply = rec
+			}
+		case *packets.PubrelPacket:
+			comp := packets.NewControlPacket(packets.Pubcomp).(*packets.PubcompPacket)
+			comp.MessageID = p.MessageID
+			reply = comp
+		case *packets.PingreqPacket:
+			reply = packets.NewControlPacket(packets.Pingresp)
+		case *packets.DisconnectPacket:
+			return
+		}
+		if reply != nil {
+			if err := reply.Write(conn); err != nil {
+				return
+			}
+		}
+	}
+}
+
+func testDecryptFunc(secrets map[string]string) receivers.DecryptFunc {
+	return func(key string, fallback string) string {
+		if v, ok := secrets[key]; ok {
+			return v
+		}
+		return fallback
+	}
+}
@@ -243,6 +243,243 @@ func GetAvailableNotifiers() []*NotifierPlugin {
 				},
 			},
 		},
+		{
+			Type:        "mqtt",
+			Name:        "MQTT",
+			Description: "Publishes notifications to an MQTT broker",
+			Heading:     "MQTT settings",
+			Options: []NotifierOption{
+				{
+					Label:        "Broker URL",
+					Element:      ElementTypeInput,
+					InputType:    InputTypeText,
+					Description:  "The URL of the broker, with the tcp, ssl, ws or wss scheme.",
+					Placeholder:  "tcp://localhost:1883",
+					PropertyName: "brokerUrl",
+					Required:     true,
+				},
+				{
+					Label:        "Topic",
+					Element:      ElementTypeInput,
+					InputType:    InputTypeText,
+					Description:  "Templated topic the messages are published to.",
+					Placeholder:  "grafana/alerts",
+					PropertyName: "topic",
+					Required:     true,
+				},
+				{
+					Label:        "Client ID",
+					Element:      ElementTypeInput,
+					InputType:    InputTypeText,
+					Description:  "The client ID of the connection to the broker. A random client ID is used by default.",
+					PropertyName: "clientId",
+				},
+				{
+					Label:        "QoS",
+					Element:      ElementTypeSelect,
+					Description:  "The quality of service of the messages.",
+					PropertyName: "qos",
+					SelectOptions: []SelectOption{
+						{
+							Value: "0",
+							Label: "At most once (0)",
+						},
+						{
+							Value: "1",
+							Label: "At least once (1)",
+						},
+						{
+							Value: "2",
+							Label: "Exactly once (2)",
+						},
+					},
+				},
+				{
+					Label:        "Retain",
+					Element:      ElementTypeCheckbox,
+					Description:  "The broker keeps the last message of the topic and sends it to new subscribers.",
+					PropertyName: "retain",
+				},
+				{
+					Label:        "Message format",
+					Element:      ElementTypeSelect,
+					Description:  "The JSON format includes the alerts in the same format as the webhook contact point, the text format only includes the message.",
+					PropertyName: "messageFormat",
+					SelectOptions: []SelectOption{
+						{
+							Value: "json",
+							Label: "JSON",
+						},
+						{
+							Value: "text",
+							Label: "Text",
+						},
+					},
+				},
+				{
+					Label:        "Title",
+					Element:      ElementTypeInput,
+					InputType:    InputTypeText,
+					Description:  "Templated title of the message, only included in the JSON format.",
+					PropertyName: "title",
+					Placeholder:  alertingTemplates.DefaultMessageTitleEmbed,
+				},
+				{
+					Label:        "Message",
+					Element:      ElementTypeTextArea,
+					Description:  "Templated message.",
+					PropertyName: "message",
+					Placeholder:  alertingTemplates.DefaultMessageEmbed,
+				},
+				{
+					Label:        "Username",
+					Element:      ElementTypeInput,
+					InputType:    InputTypeText,
+					PropertyName: "username",
+				},
+				{
+					Label:        "Password",
+					Element:      ElementTypeInput,
+					InputType:    InputTypePassword,
+					PropertyName: "password",
+					Secure:       true,
+				},
+				{
+					Label:        "Disable TLS certificate validation",
+					Element:      ElementTypeCheckbox,
+					Description:  "Do not verify the certificate of the broker. Only use this for testing.",
+					PropertyName: "insecureSkipVerify",
+				},
+				{
+					Label:        "TLS CA certificate",
+					Element:      ElementTypeTextArea,
+					Description:  "PEM encoded certificate of the authority that signed the certificate of the broker.",
+					PropertyName: "tlsCACertificate",
+				},
+				{
+					Label:        "TLS client certificate",
+					Element:      ElementTypeTextArea,
+					Description:  "PEM encoded client certificate, to authenticate to the broker with TLS.",
+					PropertyName: "tlsClientCertificate",
+				},
+				{
+					Label:        "TLS client key",
+					Element:      ElementTypeTextArea,
+					Description:  "PEM encoded key of the client certificate.",
+					PropertyName: "tlsClientKey",
+					Secure:       true,
+				},
+			},
+		},
+		{
+			Type:        "amqp",
+			Name:        "AMQP",
+			Description: "Publishes notifications to an AMQP 0-9-1 broker such as RabbitMQ",
+			Heading:     "AMQP settings",
+			Options: []NotifierOption{
+				{
+					Label:        "URL",
+					Element:      ElementTypeInput,
+					InputType:    InputTypeText,
+					Description:  "The URL of the broker, with the amqp or amqps scheme and the virtual host as path.",
+					Placeholder:  "amqp://localhost:5672/",
+					PropertyName: "url",
+					Required:     true,
+				},
+				{
+					Label:        "Exchange",
+					Element:      ElementTypeInput,
+					InputType:    InputTypeText,
+					Description:  "The exchange the messages are published to. The default exchange is used if it is empty.",
+					PropertyName: "exchange",
+				},
+				{
+					Label:        "Routing key",
+					Element:      ElementTypeInput,
+					InputType:    InputTypeText,
+					Description:  "Templated routing key of the messages. With the default exchange, this is the name of the queue.",
+					Placeholder:  "grafana.alerts",
+					PropertyName: "routingKey",
+					Required:     true,
+				},
+				{
+					Label:        "Persistent",
+					Element:      ElementTypeCheckbox,
+					Description:  "Publish persistent messages, which survive a restart of the broker when they are routed to durable queues.",
+					PropertyName: "persistent",
+				},
+				{
+					Label:        "Message format",
+					Element:      ElementTypeSelect,
+					Description:  "The JSON format includes the alerts in the same format as the webhook contact point, the text format only includes the message.",
+					PropertyName: "messageFormat",
+					SelectOptions: []SelectOption{
+						{
+							Value: "json",
+							Label: "JSON",
+						},
+						{
+							Value: "text",
+							Label: "Text",
+						},
+					},
+				},
+				{
+					Label:        "Title",
+					Element:      ElementTypeInput,
+					InputType:    InputTypeText,
+					Description:  "Templated title of the message, only included in the JSON format.",
+					PropertyName: "title",
+					Placeholder:  alertingTemplates.DefaultMessageTitleEmbed,
+				},
+				{
+					Label:        "Message",
+					Element:      ElementTypeTextArea,
+					Description:  "Templated message.",
+					PropertyName: "message",
+					Placeholder:  alertingTemplates.DefaultMessageEmbed,
+				},
+				{
+					Label:        "Username",
+					Element:      ElementTypeInput,
+					InputType:    InputTypeText,
+					Description:  "The credentials are taken from the URL if the username is empty.",
+					PropertyName: "username",
+				},
+				{
+					Label:        "Password",
+					Element:      ElementTypeInput,
+					InputType:    InputTypePassword,
+					PropertyName: "password",
+					Secure:       true,
+				},
+				{
+					Label:        "Disable TLS certificate validation",
+					Element:      ElementTypeCheckbox,
+					Description:  "Do not verify the certificate of the broker. Only use this for testing.",
+					PropertyName: "insecureSkipVerify",
+				},
+				{
+					Label:        "TLS CA certificate",
+					Element:      ElementTypeTextArea,
+					Description:  "PEM encoded certificate of the authority that signed the certificate of the broker.",
+					PropertyName: "tlsCACertificate",
+				},
+				{
+					Label:        "TLS client certificate",
+					Element:      ElementTypeTextArea,
+					Description:  "PEM encoded client certificate, to authenticate to the broker with TLS.",
+					PropertyName: "tlsClientCertificate",
+				},
+				{
+					Label:        "TLS client key",
+					Element:      ElementTypeTextArea,
+					Description:  "PEM encoded key of the client certificate.",
+					PropertyName: "tlsClientKey",
+					Secure:       true,
+				},
+			},
+		},
 		{
 			Type:        "email",
 			Name:        "Email",
@@ -83,6 +83,8 @@ type MultiOrgAlertmanager struct {
 
 	metrics *metrics.MultiOrgAlertmanager
 	ns      notifications.Service
+
+	notificationHistorian *NotificationHistorian
 }
 
 type orgAlertmanagerFactory func(ctx context.Context, orgID int64) (Alertmanager, error)
@@ -95,6 +97,13 @@ func WithAlertmanagerOverride(f orgAlertmanagerFactory) Option {
 	}
 }
 
+// WithNotificationHistorian records the notification attempts of the Alertmanagers created by the default factory.
+func WithNotificationHistorian(h *NotificationHistorian) Option {
+	return func(moa *MultiOrgAlertmanager) {
+		moa.notificationHistorian = h
+	}
+}
+
 func NewMultiOrgAlertmanager(cfg *setting.Cfg, configStore AlertingStore, orgStore store.OrgStore,
 	kvStore kvstore.KVStore, provStore provisioningStore, decryptFn alertingNotify.GetDecryptedValueFn,
 	m *metrics.MultiOrgAlertmanager, ns notifications.Service, l log.Logger, s secrets.Service, opts ...Option,
@@ -122,7 +131,12 @@ func NewMultiOrgAlertmanager(cfg *setting.Cfg, configStore AlertingStore, orgSto
 	// Set up the default per tenant Alertmanager factory.
 	moa.factory = func(ctx context.Context, orgID int64) (Alertmanager, error) {
 		m := metrics.NewAlertmanagerMetrics(moa.metrics.GetOrCreateOrgRegistry(orgID))
-		return newAlertmanager(ctx, orgID, moa.settings, moa.configStore, moa.kvStore, moa.peer, moa.decryptFn, moa.ns, m)
+		am, err := newAlertmanager(ctx, orgID, moa.settings, moa.configStore, moa.kvStore, moa.peer, moa.decryptFn, moa.ns, m)
+		if err != nil {
+			return nil, err
+		}
+		am.notificationHistorian = moa.notificationHistorian
+		return am, nil
 	}
 
 	for _, opt := range opts {
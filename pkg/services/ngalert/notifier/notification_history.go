@@ -0,0 +1,278 @@
+package notifier
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+
+	"github.com/benbjohnson/clock"
+	alertingNotify "github.com/grafana/alerting/notify"
+	"github.com/prometheus/alertmanager/notify"
+	"github.com/prometheus/alertmanager/types"
+	"github.com/prometheus/common/model"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+)
+
+const (
+	// notificationHistoryBufferSize is the number of entries that can wait to be saved before new entries are dropped.
+	notificationHistoryBufferSize = 1024
+	// notificationHistoryBatchSize is the maximum number of entries saved at once.
+	notificationHistoryBatchSize = 100
+	// notificationHistoryFlushInterval is the interval at which the pending entries are saved.
+	notificationHistoryFlushInterval = 5 * time.Second
+)
+
+// NotificationHistoryStore stores the notification history.
+type NotificationHistoryStore interface {
+	SaveNotificationHistory(ctx context.Context, entries []models.NotificationHistoryEntry) error
+	GetNotificationHistory(ctx context.Context, query models.NotificationHistoryQuery) ([]models.NotificationHistoryEntry, error)
+}
+
+// NotificationHistorian records the attempts of the integrations to send notifications.
+// Entries are saved asynchronously, in batches, so that recording never delays the notifications.
+type NotificationHistorian struct {
+	store          NotificationHistoryStore
+	entries        chan models.NotificationHistoryEntry
+	maxPayloadSize int
+	clock          clock.Clock
+	log            log.Logger
+}
+
+func NewNotificationHistorian(store NotificationHistoryStore, maxPayloadSize int, clk clock.Clock, log log.Logger) *NotificationHistorian {
+	return &NotificationHistorian{
+		store:          store,
+		entries:        make(chan models.NotificationHistoryEntry, notificationHistoryBufferSize),
+		maxPayloadSize: maxPayloadSize,
+		clock:          clk,
+		log:            log,
+	}
+}
+
+// GetNotificationHistory returns the entries of the notification history that match the query.
+func (h *NotificationHistorian) GetNotificationHistory(ctx context.Context, query models.NotificationHistoryQuery) ([]models.NotificationHistoryEntry, error) {
+	return h.store.GetNotificationHistory(ctx, query)
+}
+
+// Record queues the entry to be saved, with its payload truncated to the maximum payload size. The entry is
+// dropped if too many entries are waiting to be saved.
+func (h *NotificationHistorian) Record(entry models.NotificationHistoryEntry) {
+	if h.maxPayloadSize <= 0 {
+		entry.Payload = ""
+	} else if len(entry.Payload) > h.maxPayloadSize {
+		// The payload is cut at the start of a character, as some databases reject invalid UTF-8.
+		size := h.maxPayloadSize
+		for size > 0 && !utf8.RuneStart(entry.Payload[size]) {
+			size--
+		}
+		entry.Payload = entry.Payload[:size]
+	}
+	select {
+	case h.entries <- entry:
+	default:
+		h.log.Warn("Dropping notification history entry, too many entries are waiting to be saved", "org", entry.OrgID, "receiver", entry.Receiver, "integration", entry.Integration)
+	}
+}
+
+// Run saves the recorded entries until the context is cancelled, then saves the entries that are still pending.
+func (h *NotificationHistorian) Run(ctx context.Context) error {
+	ticker := h.clock.Ticker(notificationHistoryFlushInterval)
+	defer ticker.Stop()
+
+	batch := make([]models.NotificationHistoryEntry, 0, notificationHistoryBatchSize)
+	flush := func(ctx context.Context) {
+		if len(batch) == 0 {
+			return
+		}
+		if err := h.store.SaveNotificationHistory(ctx, batch); err != nil {
+			h.log.Error("Failed to save notification history", "entries", len(batch), "error", err)
+		}
+		batch = batch[:0]
+	}
+
+	for {
+		select {
+		case entry := <-h.entries:
+			batch = append(batch, entry)
+			if len(batch) >= notificationHistoryBatchSize {
+				flush(ctx)
+			}
+		case <-ticker.C:
+			flush(ctx)
+		case <-ctx.Done():
+			// Detached context here is to make sure that the pending entries are saved when the service is shut down.
+			for {
+				select {
+				case entry := <-h.entries:
+					batch = append(batch, entry)
+					if len(batch) >= notificationHistoryBatchSize {
+						flush(context.Background())
+					}
+				default:
+					flush(context.Background())
+					return nil
+				}
+			}
+		}
+	}
+}
+
+// wrapIntegrations wraps the integrations of the receiver so that their notification attempts are recorded.
+// The secrets of the receiver are redacted from the recorded payloads.
+func (h *NotificationHistorian) wrapIntegrations(orgID int64, receiver string, integrations []*alertingNotify.Integration, secrets []string) []*alertingNotify.Integration {
+	redactor := newSecretsRedactor(secrets)
+	wrapped := make([]*alertingNotify.Integration, 0, len(integrations))
+	for _, i := range integrations {
+		n := &recordingNotifier{
+			historian:   h,
+			integration: i,
+			orgID:       orgID,
+			receiver:    receiver,
+			redactor:    redactor,
+		}
+		wrapped = append(wrapped, alertingNotify.NewIntegration(n, i, i.Name(), i.Index(), receiver))
+	}
+	return wrapped
+}
+
+// recordingNotifier records the notification attempts of an integration in the notification history.
+type recordingNotifier struct {
+	historian   *NotificationHistorian
+	integration *alertingNotify.Integration
+	orgID       int64
+	receiver    string
+	redactor    *strings.Replacer
+}
+
+func (n *recordingNotifier) Notify(ctx context.Context, alerts ...*types.Alert) (bool, error) {
+	payload := &notificationPayload{}
+	start := n.historian.clock.Now()
+	retry, err := n.integration.Notify(withNotificationPayload(ctx, payload), alerts...)
+
+	entry := models.NotificationHistoryEntry{
+		OrgID:            n.orgID,
+		Receiver:         n.receiver,
+		Integration:      n.integration.Name(),
+		IntegrationIndex: n.integration.Index(),
+		Alerts:           make([]models.NotificationHistoryAlert, 0, len(alerts)),
+		Status:           models.NotificationStatusSuccess,
+		Payload:          n.redactor.Replace(payload.String()),
+		SentAt:           start,
+		Duration:         n.historian.clock.Since(start),
+	}
+	if r, ok := notify.ReceiverName(ctx); ok {
+		entry.Receiver = r
+	}
+	if key, ok := notify.GroupKey(ctx); ok {
+		entry.GroupKey = key
+	}
+	if err != nil {
+		entry.Status = models.NotificationStatusFailure
+		// errors, like the ones of HTTP requests, may contain secrets such as the URL of the integration
+		entry.Error = n.redactor.Replace(err.Error())
+		entry.Retry = retry
+	}
+	for _, a := range alerts {
+		entry.Alerts = append(entry.Alerts, models.NotificationHistoryAlert{
+			Labels:      labelSetToMap(a.Labels),
+			Annotations: labelSetToMap(a.Annotations),
+			Status:      alertStatusAt(a, start),
+			StartsAt:    a.StartsAt,
+			EndsAt:      a.EndsAt,
+		})
+	}
+	n.historian.Record(entry)
+
+	return retry, err
+}
+
+func alertStatusAt(a *types.Alert, ts time.Time) model.AlertStatus {
+	if a.ResolvedAt(ts) {
+		return model.AlertResolved
+	}
+	return model.AlertFiring
+}
+
+func labelSetToMap(ls model.LabelSet) map[string]string {
+	if len(ls) == 0 {
+		return nil
+	}
+	m := make(map[string]string, len(ls))
+	for k, v := range ls {
+		m[string(k)] = string(v)
+	}
+	return m
+}
+
+// receiverSecrets returns the decrypted values of the secure settings of the integrations of the receiver.
+func receiverSecrets(ctx context.Context, receiver *alertingNotify.APIReceiver, decrypt alertingNotify.GetDecryptedValueFn) []string {
+	var secrets []string
+	for _, integration := range receiver.Integrations {
+		for key, value := range integration.SecureSettings {
+			encrypted, err := base64.StdEncoding.DecodeString(value)
+			if err != nil {
+				continue
+			}
+			if secret := decrypt(ctx, map[string][]byte{key: encrypted}, key, ""); secret != "" {
+				secrets = append(secrets, secret)
+			}
+		}
+	}
+	return secrets
+}
+
+// newSecretsRedactor returns a replacer of the secrets by a redacted value. The secrets are also replaced when
+// they are escaped in JSON strings or URLs, as the integrations put them in the payloads this way.
+func newSecretsRedactor(secrets []string) *strings.Replacer {
+	oldnew := make([]string, 0, len(secrets)*6)
+	for _, secret := range secrets {
+		oldnew = append(oldnew, secret, definitions.RedactedValue)
+		if b, err := json.Marshal(secret); err == nil {
+			if escaped := string(b[1 : len(b)-1]); escaped != secret {
+				oldnew = append(oldnew, escaped, definitions.RedactedValue)
+			}
+		}
+		if escaped := url.QueryEscape(secret); escaped != secret {
+			oldnew = append(oldnew, escaped, definitions.RedactedValue)
+		}
+	}
+	return strings.NewReplacer(oldnew...)
+}
+
+// notificationPayload collects the payloads that an integration sends through Grafana.
+type notificationPayload struct {
+	mtx      sync.Mutex
+	payloads []string
+}
+
+func (p *notificationPayload) add(payload string) {
+	p.mtx.Lock()
+	defer p.mtx.Unlock()
+	p.payloads = append(p.payloads, payload)
+}
+
+func (p *notificationPayload) String() string {
+	p.mtx.Lock()
+	defer p.mtx.Unlock()
+	return strings.Join(p.payloads, "\n")
+}
+
+type notificationPayloadKey struct{}
+
+func withNotificationPayload(ctx context.Context, p *notificationPayload) context.Context {
+	return context.WithValue(ctx, notificationPayloadKey{}, p)
+}
+
+// recordNotificationPayload adds the payload to the notification payload of the context, if any.
+func recordNotificationPayload(ctx context.Context, payload string) {
+	if p, ok := ctx.Value(notificationPayloadKey{}).(*notificationPayload); ok {
+		p.add(payload)
+	}
+}
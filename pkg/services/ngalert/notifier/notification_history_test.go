@@ -0,0 +1,227 @@
+package notifier
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"net/url"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/benbjohnson/clock"
+	alertingNotify "github.com/grafana/alerting/notify"
+	"github.com/grafana/alerting/receivers"
+	"github.com/prometheus/alertmanager/notify"
+	"github.com/prometheus/alertmanager/types"
+	"github.com/prometheus/common/model"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/services/notifications"
+)
+
+func TestNotificationHistorian(t *testing.T) {
+	now := time.Date(2023, 10, 2, 12, 0, 0, 0, time.UTC)
+	alert := &types.Alert{Alert: model.Alert{
+		Labels:      model.LabelSet{"alertname": "test"},
+		Annotations: model.LabelSet{"summary": "test alert"},
+		StartsAt:    now.Add(-time.Minute),
+	}}
+
+	setup := func(maxPayloadSize int) (*NotificationHistorian, *fakeNotificationHistoryStore, *clock.Mock) {
+		clk := clock.NewMock()
+		clk.Set(now)
+		st := &fakeNotificationHistoryStore{}
+		return NewNotificationHistorian(st, maxPayloadSize, clk, log.NewNopLogger()), st, clk
+	}
+
+	notifyCtx := func() context.Context {
+		ctx := notify.WithReceiverName(context.Background(), "team-a")
+		return notify.WithGroupKey(ctx, "{}:{alertname=\"test\"}")
+	}
+
+	t.Run("should record successful notifications and their payload", func(t *testing.T) {
+		h, _, _ := setup(1024)
+		integration := alertingNotify.NewIntegration(&fakePayloadNotifier{payload: `{"status":"firing"}`}, fakeResolvedSender{}, "webhook", 1, "team-a")
+		wrapped := h.wrapIntegrations(1, "team-a", []*alertingNotify.Integration{integration}, nil)
+		require.Len(t, wrapped, 1)
+		require.Equal(t, "webhook", wrapped[0].Name())
+		require.Equal(t, 1, wrapped[0].Index())
+
+		retry, err := wrapped[0].Notify(notifyCtx(), alert)
+		require.NoError(t, err)
+		require.False(t, retry)
+
+		entry := <-h.entries
+		require.Equal(t, int64(1), entry.OrgID)
+		require.Equal(t, "team-a", entry.Receiver)
+		require.Equal(t, "webhook", entry.Integration)
+		require.Equal(t, 1, entry.IntegrationIndex)
+		require.Equal(t, "{}:{alertname=\"test\"}", entry.GroupKey)
+		require.Equal(t, models.NotificationStatusSuccess, entry.Status)
+		require.Equal(t, `{"status":"firing"}`, entry.Payload)
+		require.Equal(t, now, entry.SentAt)
+		require.Equal(t, []models.NotificationHistoryAlert{{
+			Labels:      map[string]string{"alertname": "test"},
+			Annotations: map[string]string{"summary": "test alert"},
+			Status:      model.AlertFiring,
+			StartsAt:    now.Add(-time.Minute),
+		}}, entry.Alerts)
+	})
+
+	t.Run("should record failed notifications", func(t *testing.T) {
+		h, _, _ := setup(0)
+		integration := alertingNotify.NewIntegration(&fakePayloadNotifier{err: errors.New("connection refused"), retry: true}, fakeResolvedSender{}, "webhook", 0, "team-a")
+		wrapped := h.wrapIntegrations(1, "team-a", []*alertingNotify.Integration{integration}, nil)
+
+		retry, err := wrapped[0].Notify(notifyCtx(), alert)
+		require.Error(t, err)
+		require.True(t, retry)
+
+		entry := <-h.entries
+		require.Equal(t, models.NotificationStatusFailure, entry.Status)
+		require.Equal(t, "connection refused", entry.Error)
+		require.True(t, entry.Retry)
+	})
+
+	t.Run("should redact the secrets of the receiver from the payload", func(t *testing.T) {
+		h, _, _ := setup(1024)
+		payload := `{"token":"s3cr\"et","url":"https://example.com/?key=a%2Fb&other=a/b"}`
+		integration := alertingNotify.NewIntegration(&fakePayloadNotifier{payload: payload}, fakeResolvedSender{}, "webhook", 0, "team-a")
+		wrapped := h.wrapIntegrations(1, "team-a", []*alertingNotify.Integration{integration}, []string{`s3cr"et`, "a/b"})
+
+		_, err := wrapped[0].Notify(notifyCtx(), alert)
+		require.NoError(t, err)
+
+		entry := <-h.entries
+		require.Equal(t, `{"token":"[REDACTED]","url":"https://example.com/?key=[REDACTED]&other=[REDACTED]"}`, entry.Payload)
+	})
+
+	t.Run("should redact the secrets of the receiver from the error", func(t *testing.T) {
+		h, _, _ := setup(1024)
+		notifyErr := &url.Error{Op: "Post", URL: "https://hooks.slack.com/services/T000/B000/s3cret", Err: errors.New("connection refused")}
+		integration := alertingNotify.NewIntegration(&fakePayloadNotifier{err: notifyErr}, fakeResolvedSender{}, "slack", 0, "team-a")
+		wrapped := h.wrapIntegrations(1, "team-a", []*alertingNotify.Integration{integration}, []string{"https://hooks.slack.com/services/T000/B000/s3cret"})
+
+		_, err := wrapped[0].Notify(notifyCtx(), alert)
+		require.Error(t, err)
+
+		entry := <-h.entries
+		require.Equal(t, `Post "[REDACTED]": connection refused`, entry.Error)
+	})
+
+	t.Run("should return the decrypted secure settings of the receiver", func(t *testing.T) {
+		receiver := &alertingNotify.APIReceiver{GrafanaIntegrations: alertingNotify.GrafanaIntegrations{Integrations: []*alertingNotify.GrafanaIntegrationConfig{
+			{Type: "webhook", SecureSettings: map[string]string{"password": base64.StdEncoding.EncodeToString([]byte("encrypted-password"))}},
+			{Type: "slack", SecureSettings: map[string]string{"token": base64.StdEncoding.EncodeToString([]byte("encrypted-token")), "url": "not base64"}},
+		}}}
+		decrypt := func(_ context.Context, sjd map[string][]byte, key string, fallback string) string {
+			return strings.TrimPrefix(string(sjd[key]), "encrypted-")
+		}
+		require.ElementsMatch(t, []string{"password", "token"}, receiverSecrets(context.Background(), receiver, decrypt))
+	})
+
+	t.Run("should truncate the payload", func(t *testing.T) {
+		h, _, _ := setup(4)
+		h.Record(models.NotificationHistoryEntry{OrgID: 1, Payload: "truncated"})
+		entry := <-h.entries
+		require.Equal(t, "trun", entry.Payload)
+	})
+
+	t.Run("should not truncate the payload in the middle of a character", func(t *testing.T) {
+		h, _, _ := setup(4)
+		h.Record(models.NotificationHistoryEntry{OrgID: 1, Payload: "abcé"})
+		entry := <-h.entries
+		require.Equal(t, "abc", entry.Payload)
+	})
+
+	t.Run("should not store the payload if the maximum size is 0", func(t *testing.T) {
+		h, _, _ := setup(0)
+		h.Record(models.NotificationHistoryEntry{OrgID: 1, Payload: "payload"})
+		entry := <-h.entries
+		require.Empty(t, entry.Payload)
+	})
+
+	t.Run("should drop entries when too many are waiting to be saved", func(t *testing.T) {
+		h, _, _ := setup(0)
+		for i := 0; i < notificationHistoryBufferSize+10; i++ {
+			h.Record(models.NotificationHistoryEntry{OrgID: 1})
+		}
+		require.Len(t, h.entries, notificationHistoryBufferSize)
+	})
+
+	t.Run("should save the pending entries periodically and when stopped", func(t *testing.T) {
+		h, st, clk := setup(0)
+		ctx, cancel := context.WithCancel(context.Background())
+		done := make(chan error)
+		go func() {
+			done <- h.Run(ctx)
+		}()
+
+		h.Record(models.NotificationHistoryEntry{OrgID: 1, Receiver: "team-a"})
+		require.Eventually(t, func() bool {
+			clk.Add(notificationHistoryFlushInterval)
+			return len(st.saved()) == 1
+		}, time.Second, 10*time.Millisecond)
+
+		h.Record(models.NotificationHistoryEntry{OrgID: 1, Receiver: "team-b"})
+		h.Record(models.NotificationHistoryEntry{OrgID: 1, Receiver: "team-c"})
+		require.Eventually(t, func() bool {
+			return len(h.entries) == 0
+		}, time.Second, 10*time.Millisecond)
+		cancel()
+		require.NoError(t, <-done)
+
+		saved := st.saved()
+		require.Len(t, saved, 3)
+		require.Equal(t, "team-c", saved[2].Receiver)
+	})
+}
+
+type fakeNotificationHistoryStore struct {
+	mtx     sync.Mutex
+	entries []models.NotificationHistoryEntry
+}
+
+func (f *fakeNotificationHistoryStore) saved() []models.NotificationHistoryEntry {
+	f.mtx.Lock()
+	defer f.mtx.Unlock()
+	return append([]models.NotificationHistoryEntry(nil), f.entries...)
+}
+
+func (f *fakeNotificationHistoryStore) SaveNotificationHistory(_ context.Context, entries []models.NotificationHistoryEntry) error {
+	f.mtx.Lock()
+	defer f.mtx.Unlock()
+	f.entries = append(f.entries, entries...)
+	return nil
+}
+
+func (f *fakeNotificationHistoryStore) GetNotificationHistory(_ context.Context, _ models.NotificationHistoryQuery) ([]models.NotificationHistoryEntry, error) {
+	return f.saved(), nil
+}
+
+// fakePayloadNotifier sends its payload through the Grafana sender, like the integrations of Grafana do.
+type fakePayloadNotifier struct {
+	payload string
+	retry   bool
+	err     error
+}
+
+func (n *fakePayloadNotifier) Notify(ctx context.Context, _ ...*types.Alert) (bool, error) {
+	if n.payload != "" {
+		s := sender{ns: notifications.MockNotificationService()}
+		if err := s.SendWebhook(ctx, &receivers.SendWebhookSettings{URL: "http://localhost", Body: n.payload}); err != nil {
+			return false, err
+		}
+	}
+	return n.retry, n.err
+}
+
+type fakeResolvedSender struct{}
+
+func (fakeResolvedSender) SendResolved() bool {
+	return true
+}
@@ -0,0 +1,279 @@
+package notifier
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/benbjohnson/clock"
+	"github.com/go-openapi/strfmt"
+	alertingNotify "github.com/grafana/alerting/notify"
+	amv2 "github.com/prometheus/alertmanager/api/v2/models"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	apimodels "github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/util"
+)
+
+const (
+	// recurringSilencesExpandInterval is the interval at which the occurrences of recurring silences are expanded.
+	recurringSilencesExpandInterval = time.Minute
+	// recurringSilencesLookahead is how long before they start the occurrences are expanded into silences.
+	recurringSilencesLookahead = 24 * time.Hour
+)
+
+// RecurringSilenceStore stores recurring silences.
+type RecurringSilenceStore interface {
+	GetRecurringSilence(ctx context.Context, orgID int64, uid string) (*models.RecurringSilence, error)
+	ListRecurringSilences(ctx context.Context, orgID int64) ([]*models.RecurringSilence, error)
+	InsertRecurringSilence(ctx context.Context, silence models.RecurringSilence) (int64, error)
+	UpdateRecurringSilence(ctx context.Context, silence models.RecurringSilence) error
+	DeleteRecurringSilence(ctx context.Context, orgID int64, uid string) error
+}
+
+type silenceAlertmanagers interface {
+	AlertmanagerFor(orgID int64) (Alertmanager, error)
+}
+
+// RecurringSilenceService manages recurring silences and expands their occurrences into silences of the
+// Alertmanager of their organization.
+type RecurringSilenceService struct {
+	store         RecurringSilenceStore
+	alertmanagers silenceAlertmanagers
+	clock         clock.Clock
+	log           log.Logger
+}
+
+func NewRecurringSilenceService(store RecurringSilenceStore, alertmanagers silenceAlertmanagers, clk clock.Clock, log log.Logger) *RecurringSilenceService {
+	return &RecurringSilenceService{
+		store:         store,
+		alertmanagers: alertmanagers,
+		clock:         clk,
+		log:           log,
+	}
+}
+
+func (s *RecurringSilenceService) GetRecurringSilences(ctx context.Context, orgID int64) ([]*models.RecurringSilence, error) {
+	return s.store.ListRecurringSilences(ctx, orgID)
+}
+
+func (s *RecurringSilenceService) GetRecurringSilence(ctx context.Context, orgID int64, uid string) (*models.RecurringSilence, error) {
+	return s.store.GetRecurringSilence(ctx, orgID, uid)
+}
+
+// CreateRecurringSilence stores the recurring silence and expands the occurrences that start soon.
+func (s *RecurringSilenceService) CreateRecurringSilence(ctx context.Context, silence models.RecurringSilence) (*models.RecurringSilence, error) {
+	if silence.UID == "" {
+		silence.UID = util.GenerateShortUID()
+	}
+	silence.ExpandedUntil = time.Time{}
+	silence.Silences = nil
+	if err := silence.Validate(); err != nil {
+		return nil, err
+	}
+	if _, err := s.store.InsertRecurringSilence(ctx, silence); err != nil {
+		return nil, err
+	}
+	return s.expandStored(ctx, silence.OrgID, silence.UID)
+}
+
+// UpdateRecurringSilence replaces the recurring silence. The silences created for the previous schedule are
+// expired and the occurrences of the new schedule that start soon are expanded.
+func (s *RecurringSilenceService) UpdateRecurringSilence(ctx context.Context, silence models.RecurringSilence) (*models.RecurringSilence, error) {
+	stored, err := s.store.GetRecurringSilence(ctx, silence.OrgID, silence.UID)
+	if err != nil {
+		return nil, err
+	}
+	silence.ID = stored.ID
+	silence.Version = stored.Version
+	silence.ExpandedUntil = time.Time{}
+	silence.Silences = nil
+	if silence.CreatedBy == "" {
+		silence.CreatedBy = stored.CreatedBy
+	}
+	if err := silence.Validate(); err != nil {
+		return nil, err
+	}
+	if err := s.store.UpdateRecurringSilence(ctx, silence); err != nil {
+		return nil, err
+	}
+	s.expireSilences(ctx, stored)
+	return s.expandStored(ctx, silence.OrgID, silence.UID)
+}
+
+// DeleteRecurringSilence deletes the recurring silence and expires the silences created for it.
+func (s *RecurringSilenceService) DeleteRecurringSilence(ctx context.Context, orgID int64, uid string) error {
+	stored, err := s.store.GetRecurringSilence(ctx, orgID, uid)
+	if err != nil {
+		return err
+	}
+	if err := s.store.DeleteRecurringSilence(ctx, orgID, uid); err != nil {
+		return err
+	}
+	s.expireSilences(ctx, stored)
+	return nil
+}
+
+// Run expands the occurrences of all recurring silences periodically until the context is canceled.
+func (s *RecurringSilenceService) Run(ctx context.Context) error {
+	ticker := s.clock.Ticker(recurringSilencesExpandInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+			s.ExpandAll(ctx)
+		}
+	}
+}
+
+// ExpandAll expands the occurrences of the recurring silences of all organizations that start soon.
+func (s *RecurringSilenceService) ExpandAll(ctx context.Context) {
+	silences, err := s.store.ListRecurringSilences(ctx, 0)
+	if err != nil {
+		s.log.Error("Failed to list recurring silences", "error", err)
+		return
+	}
+	for _, silence := range silences {
+		if _, err := s.expand(ctx, silence); err != nil {
+			s.log.Error("Failed to expand recurring silence", "org", silence.OrgID, "uid", silence.UID, "error", err)
+		}
+	}
+}
+
+// expandStored expands the recurring silence that was just stored. The occurrences that cannot be expanded now
+// are expanded by the periodic job, so it only logs the errors.
+func (s *RecurringSilenceService) expandStored(ctx context.Context, orgID int64, uid string) (*models.RecurringSilence, error) {
+	stored, err := s.store.GetRecurringSilence(ctx, orgID, uid)
+	if err != nil {
+		return nil, err
+	}
+	expanded, err := s.expand(ctx, stored)
+	if err != nil {
+		s.log.Warn("Failed to expand recurring silence, it will be retried", "org", orgID, "uid", uid, "error", err)
+		return stored, nil
+	}
+	return expanded, nil
+}
+
+// expand creates the silences for the occurrences of the recurring silence that start before the lookahead and
+// were not expanded yet, and forgets the silences that ended. It returns the updated recurring silence.
+func (s *RecurringSilenceService) expand(ctx context.Context, silence *models.RecurringSilence) (*models.RecurringSilence, error) {
+	now := s.clock.Now()
+	to := now.Add(recurringSilencesLookahead)
+	occurrences, err := silence.Occurrences(now, to)
+	if err != nil {
+		return nil, err
+	}
+
+	updated := *silence
+	updated.Silences = make([]models.RecurringSilenceOccurrence, 0, len(silence.Silences))
+	for _, o := range silence.Silences {
+		if o.EndsAt.After(now) {
+			updated.Silences = append(updated.Silences, o)
+		}
+	}
+
+	var created []models.RecurringSilenceOccurrence
+	for _, start := range occurrences {
+		if start.Before(silence.ExpandedUntil) {
+			continue
+		}
+		o, err := s.createSilence(ctx, silence, start)
+		if err != nil {
+			s.deleteSilences(ctx, silence.OrgID, created)
+			return nil, err
+		}
+		created = append(created, o)
+	}
+	if len(created) == 0 && len(updated.Silences) == len(silence.Silences) {
+		return silence, nil
+	}
+	updated.Silences = append(updated.Silences, created...)
+	if len(created) > 0 {
+		updated.ExpandedUntil = to
+	}
+
+	// other replicas can expand the same recurring silence at the same time, the optimistic lock lets only one of them
+	// record its silences. The silences of the others are expired.
+	if err := s.store.UpdateRecurringSilence(ctx, updated); err != nil {
+		s.deleteSilences(ctx, silence.OrgID, created)
+		return nil, fmt.Errorf("failed to record the silences of the recurring silence: %w", err)
+	}
+	updated.Version++
+	if len(created) > 0 {
+		s.log.Debug("Expanded recurring silence", "org", silence.OrgID, "uid", silence.UID, "silences", len(created))
+	}
+	return &updated, nil
+}
+
+func (s *RecurringSilenceService) createSilence(ctx context.Context, silence *models.RecurringSilence, start time.Time) (models.RecurringSilenceOccurrence, error) {
+	am, err := s.alertmanagers.AlertmanagerFor(silence.OrgID)
+	if err != nil {
+		return models.RecurringSilenceOccurrence{}, err
+	}
+	end := start.Add(silence.Duration)
+	startsAt := strfmt.DateTime(start)
+	endsAt := strfmt.DateTime(end)
+	comment := silence.Comment
+	createdBy := silence.CreatedBy
+	ps := apimodels.PostableSilence{
+		Silence: amv2.Silence{
+			Comment:   &comment,
+			CreatedBy: &createdBy,
+			Matchers:  silenceMatchers(silence.Matchers),
+			StartsAt:  &startsAt,
+			EndsAt:    &endsAt,
+		},
+	}
+	id, err := am.CreateSilence(ctx, &ps)
+	if err != nil {
+		return models.RecurringSilenceOccurrence{}, fmt.Errorf("failed to create the silence of the occurrence at %s: %w", start.UTC().Format(time.RFC3339), err)
+	}
+	return models.RecurringSilenceOccurrence{SilenceID: id, StartsAt: start.UTC(), EndsAt: end.UTC()}, nil
+}
+
+// expireSilences expires the silences created for the recurring silence that have not ended.
+func (s *RecurringSilenceService) expireSilences(ctx context.Context, silence *models.RecurringSilence) {
+	now := s.clock.Now()
+	pending := make([]models.RecurringSilenceOccurrence, 0, len(silence.Silences))
+	for _, o := range silence.Silences {
+		if o.EndsAt.After(now) {
+			pending = append(pending, o)
+		}
+	}
+	s.deleteSilences(ctx, silence.OrgID, pending)
+}
+
+func (s *RecurringSilenceService) deleteSilences(ctx context.Context, orgID int64, silences []models.RecurringSilenceOccurrence) {
+	if len(silences) == 0 {
+		return
+	}
+	am, err := s.alertmanagers.AlertmanagerFor(orgID)
+	if err != nil {
+		s.log.Warn("Failed to expire the silences of a recurring silence", "org", orgID, "error", err)
+		return
+	}
+	for _, o := range silences {
+		if err := am.DeleteSilence(ctx, o.SilenceID); err != nil && !errors.Is(err, alertingNotify.ErrSilenceNotFound) {
+			s.log.Warn("Failed to expire the silence of a recurring silence", "org", orgID, "silence", o.SilenceID, "error", err)
+		}
+	}
+}
+
+func silenceMatchers(matchers []models.RecurringSilenceMatcher) amv2.Matchers {
+	result := make(amv2.Matchers, 0, len(matchers))
+	for _, m := range matchers {
+		m := m
+		result = append(result, &amv2.Matcher{
+			Name:    &m.Name,
+			Value:   &m.Value,
+			IsRegex: &m.IsRegex,
+			IsEqual: &m.IsEqual,
+		})
+	}
+	return result
+}
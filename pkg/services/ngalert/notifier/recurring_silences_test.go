@@ -0,0 +1,236 @@
+package notifier
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/benbjohnson/clock"
+	alertingNotify "github.com/grafana/alerting/notify"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	apimodels "github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/services/ngalert/store"
+)
+
+func TestRecurringSilenceService(t *testing.T) {
+	setup := func(t *testing.T) (*RecurringSilenceService, *fakeRecurringSilenceStore, *fakeSilencer, *clock.Mock) {
+		t.Helper()
+		clk := clock.NewMock()
+		clk.Set(time.Date(2023, 10, 2, 12, 0, 0, 0, time.UTC))
+		st := &fakeRecurringSilenceStore{silences: map[string]models.RecurringSilence{}}
+		am := &fakeSilencer{silences: map[string]apimodels.PostableSilence{}}
+		return NewRecurringSilenceService(st, &fakeSilenceAlertmanagers{am: am}, clk, log.NewNopLogger()), st, am, clk
+	}
+
+	weekly := models.RecurringSilence{
+		OrgID:     1,
+		UID:       "maintenance",
+		Matchers:  []models.RecurringSilenceMatcher{{Name: "service", Value: "api", IsEqual: true}},
+		Comment:   "Weekly maintenance",
+		CreatedBy: "admin",
+		// every Monday from 22:00 to 23:00
+		StartsAt:  time.Date(2023, 9, 25, 22, 0, 0, 0, time.UTC),
+		Duration:  time.Hour,
+		Frequency: models.RecurringSilenceFrequencyWeekly,
+		Interval:  1,
+	}
+
+	t.Run("should expand the occurrences that start soon when created", func(t *testing.T) {
+		svc, _, am, _ := setup(t)
+
+		created, err := svc.CreateRecurringSilence(context.Background(), weekly)
+		require.NoError(t, err)
+
+		require.Len(t, created.Silences, 1)
+		require.Equal(t, time.Date(2023, 10, 2, 22, 0, 0, 0, time.UTC), created.Silences[0].StartsAt)
+		require.Equal(t, time.Date(2023, 10, 2, 23, 0, 0, 0, time.UTC), created.Silences[0].EndsAt)
+		require.Equal(t, time.Date(2023, 10, 3, 12, 0, 0, 0, time.UTC), created.ExpandedUntil)
+
+		silence, ok := am.silences[created.Silences[0].SilenceID]
+		require.True(t, ok)
+		require.Equal(t, "Weekly maintenance", *silence.Comment)
+		require.Equal(t, "admin", *silence.CreatedBy)
+		require.Len(t, silence.Matchers, 1)
+		require.Equal(t, "service", *silence.Matchers[0].Name)
+		require.Equal(t, "api", *silence.Matchers[0].Value)
+		require.True(t, *silence.Matchers[0].IsEqual)
+	})
+
+	t.Run("should expand each occurrence once and forget the silences that ended", func(t *testing.T) {
+		svc, st, am, clk := setup(t)
+		_, err := svc.CreateRecurringSilence(context.Background(), weekly)
+		require.NoError(t, err)
+
+		svc.ExpandAll(context.Background())
+		require.Len(t, am.silences, 1)
+
+		// the occurrence of the next week is expanded one day before it starts
+		clk.Add(6*24*time.Hour + 12*time.Hour)
+		svc.ExpandAll(context.Background())
+		require.Len(t, am.silences, 2)
+
+		stored := st.silences[weekly.UID]
+		require.Len(t, stored.Silences, 1, "the silence of the first occurrence ended")
+		require.Equal(t, time.Date(2023, 10, 9, 22, 0, 0, 0, time.UTC), stored.Silences[0].StartsAt)
+
+		svc.ExpandAll(context.Background())
+		require.Len(t, am.silences, 2)
+	})
+
+	t.Run("should not record the silences created concurrently by another replica", func(t *testing.T) {
+		svc, st, am, _ := setup(t)
+		st.silences[weekly.UID] = weekly
+		st.failUpdates = true
+
+		svc.ExpandAll(context.Background())
+
+		require.Len(t, am.created, 1)
+		require.Equal(t, am.created, am.expired)
+	})
+
+	t.Run("should expire the pending silences when updated", func(t *testing.T) {
+		svc, st, am, _ := setup(t)
+		created, err := svc.CreateRecurringSilence(context.Background(), weekly)
+		require.NoError(t, err)
+
+		update := weekly
+		update.StartsAt = time.Date(2023, 9, 26, 22, 0, 0, 0, time.UTC)
+		update.CreatedBy = ""
+		updated, err := svc.UpdateRecurringSilence(context.Background(), update)
+		require.NoError(t, err)
+
+		require.Equal(t, []string{created.Silences[0].SilenceID}, am.expired)
+		require.Empty(t, updated.Silences, "the next occurrence starts in more than a day")
+		require.Equal(t, "admin", st.silences[weekly.UID].CreatedBy)
+	})
+
+	t.Run("should expire the pending silences when deleted", func(t *testing.T) {
+		svc, st, am, _ := setup(t)
+		created, err := svc.CreateRecurringSilence(context.Background(), weekly)
+		require.NoError(t, err)
+
+		require.NoError(t, svc.DeleteRecurringSilence(context.Background(), 1, weekly.UID))
+
+		require.Equal(t, []string{created.Silences[0].SilenceID}, am.expired)
+		require.Empty(t, st.silences)
+		require.ErrorIs(t, svc.DeleteRecurringSilence(context.Background(), 1, weekly.UID), models.ErrRecurringSilenceNotFound)
+	})
+
+	t.Run("should store the silence when the Alertmanager is not ready", func(t *testing.T) {
+		svc, st, _, _ := setup(t)
+		svc.alertmanagers = &fakeSilenceAlertmanagers{err: ErrAlertmanagerNotReady}
+
+		created, err := svc.CreateRecurringSilence(context.Background(), weekly)
+		require.NoError(t, err)
+		require.Empty(t, created.Silences)
+		require.Contains(t, st.silences, weekly.UID)
+	})
+
+	t.Run("should reject invalid silences", func(t *testing.T) {
+		svc, _, _, _ := setup(t)
+		invalid := weekly
+		invalid.Matchers = nil
+
+		_, err := svc.CreateRecurringSilence(context.Background(), invalid)
+		require.ErrorIs(t, err, models.ErrRecurringSilenceFailedValidation)
+	})
+}
+
+type fakeRecurringSilenceStore struct {
+	mtx         sync.Mutex
+	silences    map[string]models.RecurringSilence
+	failUpdates bool
+}
+
+func (f *fakeRecurringSilenceStore) GetRecurringSilence(_ context.Context, orgID int64, uid string) (*models.RecurringSilence, error) {
+	f.mtx.Lock()
+	defer f.mtx.Unlock()
+	s, ok := f.silences[uid]
+	if !ok || s.OrgID != orgID {
+		return nil, models.ErrRecurringSilenceNotFound
+	}
+	return &s, nil
+}
+
+func (f *fakeRecurringSilenceStore) ListRecurringSilences(_ context.Context, orgID int64) ([]*models.RecurringSilence, error) {
+	f.mtx.Lock()
+	defer f.mtx.Unlock()
+	result := make([]*models.RecurringSilence, 0, len(f.silences))
+	for _, s := range f.silences {
+		s := s
+		if orgID == 0 || s.OrgID == orgID {
+			result = append(result, &s)
+		}
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].UID < result[j].UID })
+	return result, nil
+}
+
+func (f *fakeRecurringSilenceStore) InsertRecurringSilence(_ context.Context, silence models.RecurringSilence) (int64, error) {
+	f.mtx.Lock()
+	defer f.mtx.Unlock()
+	silence.ID = int64(len(f.silences) + 1)
+	silence.Version = 1
+	f.silences[silence.UID] = silence
+	return silence.ID, nil
+}
+
+func (f *fakeRecurringSilenceStore) UpdateRecurringSilence(_ context.Context, silence models.RecurringSilence) error {
+	f.mtx.Lock()
+	defer f.mtx.Unlock()
+	stored, ok := f.silences[silence.UID]
+	if f.failUpdates || !ok || stored.Version != silence.Version {
+		return fmt.Errorf("%w: recurring silence UID %s version %d", store.ErrOptimisticLock, silence.UID, silence.Version)
+	}
+	silence.Version++
+	f.silences[silence.UID] = silence
+	return nil
+}
+
+func (f *fakeRecurringSilenceStore) DeleteRecurringSilence(_ context.Context, _ int64, uid string) error {
+	f.mtx.Lock()
+	defer f.mtx.Unlock()
+	delete(f.silences, uid)
+	return nil
+}
+
+type fakeSilenceAlertmanagers struct {
+	am  Alertmanager
+	err error
+}
+
+func (f *fakeSilenceAlertmanagers) AlertmanagerFor(_ int64) (Alertmanager, error) {
+	if f.err != nil {
+		return nil, f.err
+	}
+	return f.am, nil
+}
+
+// fakeSilencer implements the silences of an Alertmanager.
+type fakeSilencer struct {
+	Alertmanager
+	silences map[string]apimodels.PostableSilence
+	created  []string
+	expired  []string
+}
+
+func (f *fakeSilencer) CreateSilence(_ context.Context, ps *apimodels.PostableSilence) (string, error) {
+	id := fmt.Sprintf("silence-%d", len(f.created)+1)
+	f.silences[id] = *ps
+	f.created = append(f.created, id)
+	return id, nil
+}
+
+func (f *fakeSilencer) DeleteSilence(_ context.Context, id string) error {
+	if _, ok := f.silences[id]; !ok {
+		return alertingNotify.ErrSilenceNotFound
+	}
+	f.expired = append(f.expired, id)
+	return nil
+}
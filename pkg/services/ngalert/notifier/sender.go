@@ -13,6 +13,7 @@ type sender struct {
 }
 
 func (s sender) SendWebhook(ctx context.Context, cmd *receivers.SendWebhookSettings) error {
+	recordNotificationPayload(ctx, cmd.Body)
 	return s.ns.SendWebhookSync(ctx, &notifications.SendWebhookSync{
 		Url:         cmd.URL,
 		User:        cmd.User,
@@ -26,6 +27,7 @@ func (s sender) SendWebhook(ctx context.Context, cmd *receivers.SendWebhookSetti
 }
 
 func (s sender) SendEmail(ctx context.Context, cmd *receivers.SendEmailSettings) error {
+	recordNotificationPayload(ctx, cmd.Subject)
 	var attached []*notifications.SendEmailAttachFile
 	if cmd.AttachedFiles != nil {
 		attached = make([]*notifications.SendEmailAttachFile, 0, len(cmd.AttachedFiles))
@@ -19,6 +19,9 @@ type fakeConfigStore struct {
 
 	// historicConfigs stores configs by orgID.
 	historicConfigs map[int64][]*models.HistoricAlertConfiguration
+
+	// notificationSettings stores the notification settings of alert rules by orgID.
+	notificationSettings map[int64][]models.NotificationSettings
 }
 
 // Saves the image or returns an error.
@@ -57,6 +60,10 @@ func NewFakeConfigStore(t *testing.T, configs map[int64]*models.AlertConfigurati
 	}
 }
 
+func (f *fakeConfigStore) ListNotificationSettings(_ context.Context, orgID int64) ([]models.NotificationSettings, error) {
+	return f.notificationSettings[orgID], nil
+}
+
 func (f *fakeConfigStore) GetAllLatestAlertmanagerConfiguration(context.Context) ([]*models.AlertConfiguration, error) {
 	result := make([]*models.AlertConfiguration, 0, len(f.configs))
 	for _, configuration := range f.configs {
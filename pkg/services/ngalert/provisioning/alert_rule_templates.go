@@ -0,0 +1,243 @@
+package provisioning
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/services/accesscontrol"
+	"github.com/grafana/grafana/pkg/services/dashboards"
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/services/user"
+	"github.com/grafana/grafana/pkg/util"
+)
+
+type AlertRuleTemplateService struct {
+	templateStore   AlertRuleTemplateStore
+	ruleStore       RuleStore
+	provenanceStore ProvisioningStore
+	xact            TransactionManager
+	log             log.Logger
+	ac              accesscontrol.AccessControl
+}
+
+func NewAlertRuleTemplateService(templateStore AlertRuleTemplateStore,
+	ruleStore RuleStore,
+	provenanceStore ProvisioningStore,
+	xact TransactionManager,
+	log log.Logger,
+	ac accesscontrol.AccessControl) *AlertRuleTemplateService {
+	return &AlertRuleTemplateService{
+		templateStore:   templateStore,
+		ruleStore:       ruleStore,
+		provenanceStore: provenanceStore,
+		xact:            xact,
+		log:             log,
+		ac:              ac,
+	}
+}
+
+func (service *AlertRuleTemplateService) GetTemplates(ctx context.Context, orgID int64) ([]*models.AlertRuleTemplate, map[string]models.Provenance, error) {
+	templates, err := service.templateStore.ListAlertRuleTemplates(ctx, orgID)
+	if err != nil {
+		return nil, nil, err
+	}
+	provenances := make(map[string]models.Provenance)
+	if len(templates) > 0 {
+		provenances, err = service.provenanceStore.GetProvenances(ctx, orgID, templates[0].ResourceType())
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+	return templates, provenances, nil
+}
+
+func (service *AlertRuleTemplateService) GetTemplate(ctx context.Context, orgID int64, uid string) (models.AlertRuleTemplate, models.Provenance, error) {
+	template, err := service.templateStore.GetAlertRuleTemplate(ctx, orgID, uid)
+	if err != nil {
+		return models.AlertRuleTemplate{}, models.ProvenanceNone, err
+	}
+	provenance, err := service.provenanceStore.GetProvenance(ctx, template, orgID)
+	if err != nil {
+		return models.AlertRuleTemplate{}, models.ProvenanceNone, err
+	}
+	return *template, provenance, nil
+}
+
+// CreateTemplate creates a new alert rule template. A UID is generated if the template does not have one.
+func (service *AlertRuleTemplateService) CreateTemplate(ctx context.Context, template models.AlertRuleTemplate, provenance models.Provenance) (models.AlertRuleTemplate, error) {
+	if template.UID == "" {
+		template.UID = util.GenerateShortUID()
+	} else if err := util.ValidateUID(template.UID); err != nil {
+		return models.AlertRuleTemplate{}, errors.Join(models.ErrAlertRuleTemplateFailedValidation, fmt.Errorf("cannot create template with UID '%s': %w", template.UID, err))
+	}
+	if err := template.Validate(); err != nil {
+		return models.AlertRuleTemplate{}, err
+	}
+	err := service.xact.InTransaction(ctx, func(ctx context.Context) error {
+		id, err := service.templateStore.InsertAlertRuleTemplate(ctx, template)
+		if err != nil {
+			return err
+		}
+		template.ID = id
+		return service.provenanceStore.SetProvenance(ctx, &template, template.OrgID, provenance)
+	})
+	if err != nil {
+		return models.AlertRuleTemplate{}, err
+	}
+	created, err := service.templateStore.GetAlertRuleTemplate(ctx, template.OrgID, template.UID)
+	if err != nil {
+		return models.AlertRuleTemplate{}, err
+	}
+	return *created, nil
+}
+
+// UpdateTemplate updates an alert rule template and the alert rules that are instantiated from it.
+// It fails if a rule cannot be instantiated from the new template, for example because the rule
+// does not set the value of a new required parameter, if a rule is provisioned with another provenance,
+// or if the user cannot update the rules in the folder of a rule. The permissions are not checked
+// when the user is nil, for example when the template is provisioned from files.
+func (service *AlertRuleTemplateService) UpdateTemplate(ctx context.Context, template models.AlertRuleTemplate, provenance models.Provenance, u *user.SignedInUser) (models.AlertRuleTemplate, error) {
+	if err := template.Validate(); err != nil {
+		return models.AlertRuleTemplate{}, err
+	}
+	stored, storedProvenance, err := service.GetTemplate(ctx, template.OrgID, template.UID)
+	if err != nil {
+		return models.AlertRuleTemplate{}, err
+	}
+	if storedProvenance != provenance && storedProvenance != models.ProvenanceNone {
+		return models.AlertRuleTemplate{}, fmt.Errorf("cannot change provenance from '%s' to '%s'", storedProvenance, provenance)
+	}
+	template.ID = stored.ID
+	template.Version = stored.Version
+	err = service.xact.InTransaction(ctx, func(ctx context.Context) error {
+		if err := service.templateStore.UpdateAlertRuleTemplate(ctx, template); err != nil {
+			return err
+		}
+		if err := service.updateInstances(ctx, template, provenance, u); err != nil {
+			return err
+		}
+		return service.provenanceStore.SetProvenance(ctx, &template, template.OrgID, provenance)
+	})
+	if err != nil {
+		return models.AlertRuleTemplate{}, err
+	}
+	updated, err := service.templateStore.GetAlertRuleTemplate(ctx, template.OrgID, template.UID)
+	if err != nil {
+		return models.AlertRuleTemplate{}, err
+	}
+	return *updated, nil
+}
+
+// updateInstances instantiates the alert rules that refer to the template again and updates the ones that changed.
+func (service *AlertRuleTemplateService) updateInstances(ctx context.Context, template models.AlertRuleTemplate, provenance models.Provenance, u *user.SignedInUser) error {
+	rules, err := service.templateStore.ListAlertRulesByTemplate(ctx, template.OrgID, template.UID)
+	if err != nil {
+		return fmt.Errorf("failed to list the alert rules of the template: %w", err)
+	}
+	if len(rules) == 0 {
+		return nil
+	}
+	ruleProvenances, err := service.provenanceStore.GetProvenances(ctx, template.OrgID, rules[0].ResourceType())
+	if err != nil {
+		return err
+	}
+	updates := make([]models.UpdateRule, 0, len(rules))
+	for _, rule := range rules {
+		newRule := models.CopyRule(rule)
+		if err := template.Apply(newRule); err != nil {
+			return fmt.Errorf("failed to update alert rule '%s': %w", rule.UID, err)
+		}
+		if len(rule.Diff(newRule)) == 0 {
+			continue
+		}
+		if ruleProvenance, ok := ruleProvenances[rule.UID]; ok && ruleProvenance != provenance && ruleProvenance != models.ProvenanceNone {
+			return fmt.Errorf("%w: alert rule '%s' is provisioned with provenance '%s' and cannot be updated with provenance '%s'", models.ErrAlertRuleTemplateInUse, rule.UID, ruleProvenance, provenance)
+		}
+		if err := service.authorizeRuleUpdate(ctx, u, rule); err != nil {
+			return err
+		}
+		updates = append(updates, models.UpdateRule{
+			Existing: rule,
+			New:      *newRule,
+		})
+	}
+	if len(updates) == 0 {
+		return nil
+	}
+	service.log.Debug("Updating alert rules instantiated from the template", "org", template.OrgID, "template", template.UID, "rules", len(updates))
+	return service.ruleStore.UpdateAlertRules(ctx, updates)
+}
+
+// authorizeRuleUpdate checks that the user can update the alert rules in the folder of the rule.
+func (service *AlertRuleTemplateService) authorizeRuleUpdate(ctx context.Context, u *user.SignedInUser, rule *models.AlertRule) error {
+	if u == nil {
+		return nil
+	}
+	scope := dashboards.ScopeFoldersProvider.GetResourceScopeUID(rule.NamespaceUID)
+	permitted, err := service.ac.Evaluate(ctx, u, accesscontrol.EvalPermission(accesscontrol.ActionAlertingRuleUpdate, scope))
+	if err != nil {
+		return err
+	}
+	if !permitted {
+		return fmt.Errorf("%w: user cannot update alert rule '%s' instantiated from the template in folder '%s'", ErrPermissionDenied, rule.UID, rule.NamespaceUID)
+	}
+	return nil
+}
+
+// DeleteTemplate deletes an alert rule template that no alert rule is instantiated from.
+func (service *AlertRuleTemplateService) DeleteTemplate(ctx context.Context, orgID int64, uid string, provenance models.Provenance) error {
+	template := &models.AlertRuleTemplate{OrgID: orgID, UID: uid}
+	storedProvenance, err := service.provenanceStore.GetProvenance(ctx, template, orgID)
+	if err != nil {
+		return err
+	}
+	if storedProvenance != provenance && storedProvenance != models.ProvenanceNone {
+		return fmt.Errorf("cannot delete with provided provenance '%s', needs '%s'", provenance, storedProvenance)
+	}
+	return service.xact.InTransaction(ctx, func(ctx context.Context) error {
+		rules, err := service.templateStore.ListAlertRulesByTemplate(ctx, orgID, uid)
+		if err != nil {
+			return fmt.Errorf("failed to list the alert rules of the template: %w", err)
+		}
+		if len(rules) > 0 {
+			return fmt.Errorf("%w: %d alert rules including '%s' are instantiated from the template", models.ErrAlertRuleTemplateInUse, len(rules), rules[0].UID)
+		}
+		if err := service.templateStore.DeleteAlertRuleTemplate(ctx, orgID, uid); err != nil {
+			return err
+		}
+		return service.provenanceStore.DeleteProvenance(ctx, template, orgID)
+	})
+}
+
+// AlertRuleTemplateGetter represents the ability to read an alert rule template.
+type AlertRuleTemplateGetter interface {
+	GetAlertRuleTemplate(ctx context.Context, orgID int64, uid string) (*models.AlertRuleTemplate, error)
+}
+
+// ApplyAlertRuleTemplates instantiates the rules that refer to a template from it. Each template is read only once.
+func ApplyAlertRuleTemplates(ctx context.Context, store AlertRuleTemplateGetter, orgID int64, rules ...*models.AlertRule) error {
+	templates := make(map[string]*models.AlertRuleTemplate)
+	for _, rule := range rules {
+		if rule.Template == nil {
+			continue
+		}
+		template, ok := templates[rule.Template.UID]
+		if !ok {
+			var err error
+			template, err = store.GetAlertRuleTemplate(ctx, orgID, rule.Template.UID)
+			if err != nil {
+				if errors.Is(err, models.ErrAlertRuleTemplateNotFound) {
+					return fmt.Errorf("%w: alert rule template '%s' does not exist", models.ErrAlertRuleFailedValidation, rule.Template.UID)
+				}
+				return err
+			}
+			templates[rule.Template.UID] = template
+		}
+		if err := template.Apply(rule); err != nil {
+			return err
+		}
+	}
+	return nil
+}
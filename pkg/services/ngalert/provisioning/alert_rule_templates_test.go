@@ -0,0 +1,91 @@
+package provisioning
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/expr"
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/services/accesscontrol"
+	"github.com/grafana/grafana/pkg/services/accesscontrol/acimpl"
+	"github.com/grafana/grafana/pkg/services/dashboards"
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/services/ngalert/store"
+	"github.com/grafana/grafana/pkg/services/user"
+	"github.com/grafana/grafana/pkg/setting"
+)
+
+func TestAlertRuleTemplateServiceUpdateTemplate(t *testing.T) {
+	ctx := context.Background()
+	var orgID int64 = 1
+
+	ruleService := createAlertRuleService(t)
+	sut := NewAlertRuleTemplateService(ruleService.ruleStore.(store.DBstore), ruleService.ruleStore, ruleService.provenanceStore, ruleService.xact,
+		log.NewNopLogger(), acimpl.ProvideAccessControl(setting.NewCfg()))
+
+	template := models.AlertRuleTemplate{
+		OrgID:       orgID,
+		UID:         "template",
+		Title:       "template",
+		Condition:   "A",
+		Data:        []models.AlertQuery{{RefID: "A", DatasourceUID: expr.DatasourceUID, Model: json.RawMessage(`{"expression":"1"}`)}},
+		Annotations: map[string]string{"summary": "summary"},
+	}
+	template, err := sut.CreateTemplate(ctx, template, models.ProvenanceNone)
+	require.NoError(t, err)
+
+	createRule := func(t *testing.T, title, folderUID string, provenance models.Provenance) models.AlertRule {
+		t.Helper()
+		rule := createTestRule(title, "group", orgID, folderUID)
+		rule.Template = &models.AlertRuleTemplateRef{UID: template.UID}
+		require.NoError(t, template.Apply(&rule))
+		rule, err := ruleService.CreateAlertRule(ctx, rule, provenance, 0)
+		require.NoError(t, err)
+		return rule
+	}
+	writer := func(folderUIDs ...string) *user.SignedInUser {
+		scopes := make([]string, 0, len(folderUIDs))
+		for _, uid := range folderUIDs {
+			scopes = append(scopes, dashboards.ScopeFoldersProvider.GetResourceScopeUID(uid))
+		}
+		return &user.SignedInUser{OrgID: orgID, Permissions: map[int64]map[string][]string{
+			orgID: {accesscontrol.ActionAlertingRuleUpdate: scopes},
+		}}
+	}
+
+	rule := createRule(t, "rule", "folder-1", models.ProvenanceNone)
+
+	t.Run("should fail if the user cannot update the rules in the folder of a rule", func(t *testing.T) {
+		template.Annotations = map[string]string{"description": "description"}
+		_, err := sut.UpdateTemplate(ctx, template, models.ProvenanceNone, writer("folder-2"))
+		require.ErrorIs(t, err, ErrPermissionDenied)
+
+		stored, _, err := ruleService.GetAlertRule(ctx, orgID, rule.UID)
+		require.NoError(t, err)
+		require.Equal(t, map[string]string{"summary": "summary"}, stored.Annotations)
+	})
+
+	t.Run("should update the rules and remove the annotations removed from the template", func(t *testing.T) {
+		template.Annotations = map[string]string{"description": "description"}
+		_, err := sut.UpdateTemplate(ctx, template, models.ProvenanceNone, writer("folder-1"))
+		require.NoError(t, err)
+
+		stored, _, err := ruleService.GetAlertRule(ctx, orgID, rule.UID)
+		require.NoError(t, err)
+		require.Equal(t, map[string]string{"description": "description"}, stored.Annotations)
+	})
+
+	t.Run("should fail if a rule is provisioned with another provenance", func(t *testing.T) {
+		createRule(t, "provisioned rule", "folder-1", models.ProvenanceFile)
+
+		template.Annotations = map[string]string{"summary": "summary"}
+		_, err := sut.UpdateTemplate(ctx, template, models.ProvenanceNone, nil)
+		require.ErrorIs(t, err, models.ErrAlertRuleTemplateInUse)
+
+		_, err = sut.UpdateTemplate(ctx, template, models.ProvenanceFile, nil)
+		require.NoError(t, err)
+	})
+}
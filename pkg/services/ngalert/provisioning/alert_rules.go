@@ -19,6 +19,7 @@ type AlertRuleService struct {
 	baseIntervalSeconds    int64
 	ruleStore              RuleStore
 	provenanceStore        ProvisioningStore
+	amConfigStore          AMConfigStore
 	dashboardService       dashboards.DashboardService
 	quotas                 QuotaChecker
 	xact                   TransactionManager
@@ -27,6 +28,7 @@ type AlertRuleService struct {
 
 func NewAlertRuleService(ruleStore RuleStore,
 	provenanceStore ProvisioningStore,
+	amConfigStore AMConfigStore,
 	dashboardService dashboards.DashboardService,
 	quotas QuotaChecker,
 	xact TransactionManager,
@@ -38,6 +40,7 @@ func NewAlertRuleService(ruleStore RuleStore,
 		baseIntervalSeconds:    baseIntervalSeconds,
 		ruleStore:              ruleStore,
 		provenanceStore:        provenanceStore,
+		amConfigStore:          amConfigStore,
 		dashboardService:       dashboardService,
 		quotas:                 quotas,
 		xact:                   xact,
@@ -133,6 +136,12 @@ func (service *AlertRuleService) CreateAlertRule(ctx context.Context, rule model
 	if err != nil {
 		return models.AlertRule{}, err
 	}
+	if err := ApplyAlertRuleTemplates(ctx, service.ruleStore, rule.OrgID, &rule); err != nil {
+		return models.AlertRule{}, err
+	}
+	if err := service.validateNotificationSettings(ctx, rule.OrgID, &rule); err != nil {
+		return models.AlertRule{}, err
+	}
 	rule.Updated = time.Now()
 	err = service.xact.InTransaction(ctx, func(ctx context.Context) error {
 		ids, err := service.ruleStore.InsertAlertRules(ctx, []models.AlertRule{
@@ -179,10 +188,11 @@ func (service *AlertRuleService) GetRuleGroup(ctx context.Context, orgID int64,
 		return models.AlertRuleGroup{}, store.ErrAlertRuleGroupNotFound
 	}
 	res := models.AlertRuleGroup{
-		Title:     ruleList[0].RuleGroup,
-		FolderUID: ruleList[0].NamespaceUID,
-		Interval:  ruleList[0].IntervalSeconds,
-		Rules:     []models.AlertRule{},
+		Title:      ruleList[0].RuleGroup,
+		FolderUID:  ruleList[0].NamespaceUID,
+		Interval:   ruleList[0].IntervalSeconds,
+		Sequential: ruleList[0].Sequential,
+		Rules:      []models.AlertRule{},
 	}
 	for _, r := range ruleList {
 		if r != nil {
@@ -192,8 +202,8 @@ func (service *AlertRuleService) GetRuleGroup(ctx context.Context, orgID int64,
 	return res, nil
 }
 
-// UpdateRuleGroup will update the interval for all rules in the group.
-func (service *AlertRuleService) UpdateRuleGroup(ctx context.Context, orgID int64, namespaceUID string, ruleGroup string, intervalSeconds int64) error {
+// UpdateRuleGroup will update the interval and the sequential evaluation for all rules in the group.
+func (service *AlertRuleService) UpdateRuleGroup(ctx context.Context, orgID int64, namespaceUID string, ruleGroup string, intervalSeconds int64, sequential bool) error {
 	if err := models.ValidateRuleGroupInterval(intervalSeconds, service.baseIntervalSeconds); err != nil {
 		return err
 	}
@@ -207,18 +217,25 @@ func (service *AlertRuleService) UpdateRuleGroup(ctx context.Context, orgID int6
 		if err != nil {
 			return fmt.Errorf("failed to list alert rules: %w", err)
 		}
+		models.RulesGroup(ruleList).SortByGroupIndex()
 		updateRules := make([]models.UpdateRule, 0, len(ruleList))
+		newRules := make([]*models.AlertRule, 0, len(ruleList))
 		for _, rule := range ruleList {
-			if rule.IntervalSeconds == intervalSeconds {
-				continue
-			}
 			newRule := *rule
 			newRule.IntervalSeconds = intervalSeconds
+			newRule.Sequential = sequential
+			newRules = append(newRules, &newRule)
+			if rule.IntervalSeconds == intervalSeconds && rule.Sequential == sequential {
+				continue
+			}
 			updateRules = append(updateRules, models.UpdateRule{
 				Existing: rule,
 				New:      newRule,
 			})
 		}
+		if err := models.ValidateRuleResultQueries(newRules); err != nil {
+			return err
+		}
 		return service.ruleStore.UpdateAlertRules(ctx, updateRules)
 	})
 }
@@ -255,12 +272,25 @@ func (service *AlertRuleService) ReplaceRuleGroup(ctx context.Context, orgID int
 	}
 	rules := make([]*models.AlertRuleWithOptionals, len(group.Rules))
 	group = *syncGroupRuleFields(&group, orgID)
+	groupRules := make([]*models.AlertRule, 0, len(group.Rules))
 	for i := range group.Rules {
 		if err := group.Rules[i].SetDashboardAndPanelFromAnnotations(); err != nil {
 			return err
 		}
+		groupRules = append(groupRules, &group.Rules[i])
+	}
+	if err := ApplyAlertRuleTemplates(ctx, service.ruleStore, orgID, groupRules...); err != nil {
+		return err
+	}
+	for i := range group.Rules {
 		rules = append(rules, &models.AlertRuleWithOptionals{AlertRule: group.Rules[i], HasPause: true})
 	}
+	if err := models.ValidateRuleResultQueries(groupRules); err != nil {
+		return err
+	}
+	if err := service.validateNotificationSettings(ctx, orgID, groupRules...); err != nil {
+		return err
+	}
 	delta, err := store.CalculateChanges(ctx, service.ruleStore, key, rules)
 	if err != nil {
 		return fmt.Errorf("failed to calculate diff for alert rules: %w", err)
@@ -349,10 +379,17 @@ func (service *AlertRuleService) UpdateAlertRule(ctx context.Context, rule model
 	rule.Updated = time.Now()
 	rule.ID = storedRule.ID
 	rule.IntervalSeconds = storedRule.IntervalSeconds
+	rule.Sequential = storedRule.Sequential
 	err = rule.SetDashboardAndPanelFromAnnotations()
 	if err != nil {
 		return models.AlertRule{}, err
 	}
+	if err := ApplyAlertRuleTemplates(ctx, service.ruleStore, rule.OrgID, &rule); err != nil {
+		return models.AlertRule{}, err
+	}
+	if err := service.validateNotificationSettings(ctx, rule.OrgID, &rule); err != nil {
+		return models.AlertRule{}, err
+	}
 	err = service.xact.InTransaction(ctx, func(ctx context.Context) error {
 		err := service.ruleStore.UpdateAlertRules(ctx, []models.UpdateRule{
 			{
@@ -517,7 +554,9 @@ func (service *AlertRuleService) GetAlertGroupsWithFolderTitle(ctx context.Conte
 func syncGroupRuleFields(group *models.AlertRuleGroup, orgID int64) *models.AlertRuleGroup {
 	for i := range group.Rules {
 		group.Rules[i].IntervalSeconds = group.Interval
+		group.Rules[i].Sequential = group.Sequential
 		group.Rules[i].RuleGroup = group.Title
+		group.Rules[i].RuleGroupIndex = i + 1
 		group.Rules[i].NamespaceUID = group.FolderUID
 		group.Rules[i].OrgID = orgID
 	}
@@ -0,0 +1,70 @@
+package provisioning
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+)
+
+// NotificationSettingsValidator checks that the notification settings of alert rules refer to
+// contact points and mute timings that exist in an Alertmanager configuration.
+type NotificationSettingsValidator struct {
+	receivers   map[string]struct{}
+	muteTimings map[string]struct{}
+}
+
+// NewNotificationSettingsValidator creates a validator for the contact points and mute timings of the configuration.
+func NewNotificationSettingsValidator(cfg *definitions.PostableApiAlertingConfig) NotificationSettingsValidator {
+	v := NotificationSettingsValidator{
+		receivers:   make(map[string]struct{}, len(cfg.Receivers)),
+		muteTimings: make(map[string]struct{}, len(cfg.MuteTimeIntervals)),
+	}
+	for _, r := range cfg.Receivers {
+		v.receivers[r.Name] = struct{}{}
+	}
+	for _, mt := range cfg.MuteTimeIntervals {
+		v.muteTimings[mt.Name] = struct{}{}
+	}
+	return v
+}
+
+// Validate returns an error if the settings are invalid or refer to a contact point or a mute timing that does not exist.
+func (v NotificationSettingsValidator) Validate(s models.NotificationSettings) error {
+	if err := s.Validate(); err != nil {
+		return err
+	}
+	if _, ok := v.receivers[s.Receiver]; !ok {
+		return fmt.Errorf("%w: contact point '%s' of the notification settings does not exist", models.ErrAlertRuleFailedValidation, s.Receiver)
+	}
+	for _, mt := range s.MuteTimeIntervals {
+		if _, ok := v.muteTimings[mt]; !ok {
+			return fmt.Errorf("%w: mute timing '%s' of the notification settings does not exist", models.ErrAlertRuleFailedValidation, mt)
+		}
+	}
+	return nil
+}
+
+// validateNotificationSettings checks the notification settings of the rules against the latest
+// Alertmanager configuration of the organization, which is read only if any rule has notification settings.
+func (service *AlertRuleService) validateNotificationSettings(ctx context.Context, orgID int64, rules ...*models.AlertRule) error {
+	var validator *NotificationSettingsValidator
+	for _, rule := range rules {
+		if rule.NotificationSettings == nil {
+			continue
+		}
+		if validator == nil {
+			revision, err := getLastConfiguration(ctx, orgID, service.amConfigStore)
+			if err != nil {
+				return err
+			}
+			v := NewNotificationSettingsValidator(&revision.cfg.AlertmanagerConfig)
+			validator = &v
+		}
+		if err := validator.Validate(*rule.NotificationSettings); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -42,6 +42,17 @@ type RuleStore interface {
 	UpdateAlertRules(ctx context.Context, rule []models.UpdateRule) error
 	DeleteAlertRulesByUID(ctx context.Context, orgID int64, ruleUID ...string) error
 	GetAlertRulesGroupByRuleUID(ctx context.Context, query *models.GetAlertRulesGroupByRuleUIDQuery) ([]*models.AlertRule, error)
+	GetAlertRuleTemplate(ctx context.Context, orgID int64, uid string) (*models.AlertRuleTemplate, error)
+}
+
+// AlertRuleTemplateStore represents the ability to persist and query alert rule templates.
+type AlertRuleTemplateStore interface {
+	GetAlertRuleTemplate(ctx context.Context, orgID int64, uid string) (*models.AlertRuleTemplate, error)
+	ListAlertRuleTemplates(ctx context.Context, orgID int64) ([]*models.AlertRuleTemplate, error)
+	InsertAlertRuleTemplate(ctx context.Context, template models.AlertRuleTemplate) (int64, error)
+	UpdateAlertRuleTemplate(ctx context.Context, template models.AlertRuleTemplate) error
+	DeleteAlertRuleTemplate(ctx context.Context, orgID int64, uid string) error
+	ListAlertRulesByTemplate(ctx context.Context, orgID int64, templateUID string) (models.RulesGroup, error)
 }
 
 // QuotaChecker represents the ability to evaluate whether quotas are met.
@@ -16,7 +16,10 @@ import (
 	"github.com/grafana/grafana/pkg/util"
 )
 
-var errRuleDeleted = errors.New("rule deleted")
+var (
+	errRuleDeleted   = errors.New("rule deleted")
+	errRuleHandedOff = errors.New("rule evaluated by another instance")
+)
 
 type alertRuleInfoRegistry struct {
 	mu            sync.Mutex
@@ -130,6 +133,8 @@ type evaluation struct {
 	scheduledAt time.Time
 	rule        *models.AlertRule
 	folderTitle string
+	// sequence is set for the rules of sequential rule groups.
+	sequence *sequenceStep
 }
 
 type alertRulesRegistry struct {
@@ -276,6 +281,9 @@ func (r ruleWithFolder) Fingerprint() fingerprint {
 	// allocate a slice that will be used for sorting keys, so we allocate it only once
 	var keys []string
 	maxLen := int(math.Max(math.Max(float64(len(rule.Annotations)), float64(len(rule.Labels))), float64(len(rule.Data))))
+	if rule.Template != nil && len(rule.Template.Parameters) > maxLen {
+		maxLen = len(rule.Template.Parameters)
+	}
 	if maxLen > 0 {
 		keys = make([]string, maxLen)
 	}
@@ -329,6 +337,17 @@ func (r ruleWithFolder) Fingerprint() fingerprint {
 	writeLabels(rule.Labels)
 	writeString(rule.Condition)
 	writeQuery()
+	if rule.Record != nil {
+		writeString(rule.Record.Metric)
+		writeString(rule.Record.From)
+	}
+	if rule.NotificationSettings != nil {
+		writeString(rule.NotificationSettings.Fingerprint())
+	}
+	if rule.Template != nil {
+		writeString(rule.Template.UID)
+		writeLabels(rule.Template.Parameters)
+	}
 
 	if rule.IsPaused {
 		writeInt(1)
@@ -342,6 +361,7 @@ func (r ruleWithFolder) Fingerprint() fingerprint {
 	writeInt(rule.OrgID)
 	writeInt(rule.IntervalSeconds)
 	writeInt(int64(rule.For))
+	writeInt(int64(rule.KeepFiringFor))
 	writeLabels(rule.Annotations)
 	if rule.DashboardUID != nil {
 		writeString(*rule.DashboardUID)
@@ -353,5 +373,10 @@ func (r ruleWithFolder) Fingerprint() fingerprint {
 	writeInt(int64(rule.RuleGroupIndex))
 	writeString(string(rule.NoDataState))
 	writeString(string(rule.ExecErrState))
+	if rule.Sequential {
+		writeInt(1)
+	} else {
+		writeInt(0)
+	}
 	return fingerprint(sum.Sum64())
 }
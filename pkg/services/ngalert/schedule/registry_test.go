@@ -408,13 +408,26 @@ func TestRuleWithFolderFingerprint(t *testing.T) {
 			NoDataState:     "test-nodata",
 			ExecErrState:    "test-err",
 			For:             12,
+			KeepFiringFor:   34,
 			Annotations: map[string]string{
 				"key-annotation": "value-annotation",
 			},
 			Labels: map[string]string{
 				"key-label": "value-label",
 			},
-			IsPaused: false,
+			IsPaused:   false,
+			Sequential: false,
+			Record: &models.Record{
+				Metric: "test_metric",
+				From:   "A",
+			},
+			NotificationSettings: &models.NotificationSettings{
+				Receiver: "slack",
+			},
+			Template: &models.AlertRuleTemplateRef{
+				UID:        "template-1",
+				Parameters: map[string]string{"service": "api"},
+			},
 		}
 		r2 := &models.AlertRule{
 			ID:        2,
@@ -443,13 +456,26 @@ func TestRuleWithFolderFingerprint(t *testing.T) {
 			NoDataState:     "test-nodata2",
 			ExecErrState:    "test-err2",
 			For:             1141,
+			KeepFiringFor:   4321,
 			Annotations: map[string]string{
 				"key-annotation2": "value-annotation",
 			},
 			Labels: map[string]string{
 				"key-label": "value-label23",
 			},
-			IsPaused: true,
+			IsPaused:   true,
+			Sequential: true,
+			Record: &models.Record{
+				Metric: "test_metric_2",
+				From:   "B",
+			},
+			NotificationSettings: &models.NotificationSettings{
+				Receiver: "email",
+			},
+			Template: &models.AlertRuleTemplateRef{
+				UID:        "template-2",
+				Parameters: map[string]string{"service": "web"},
+			},
 		}
 
 		excludedFields := map[string]struct{}{
@@ -8,6 +8,8 @@ import (
 	"time"
 
 	"github.com/benbjohnson/clock"
+	"github.com/grafana/grafana-plugin-sdk-go/backend"
+	"github.com/grafana/grafana-plugin-sdk-go/data"
 	"go.opentelemetry.io/otel/attribute"
 	"go.opentelemetry.io/otel/codes"
 	"go.opentelemetry.io/otel/trace"
@@ -32,6 +34,18 @@ type ScheduleService interface {
 	// Run the scheduler until the context is canceled or the scheduler returns
 	// an error. The scheduler is terminated when this function returns.
 	Run(context.Context) error
+	// Status returns a snapshot of the alert rules known to the scheduler, grouped by organization.
+	Status() map[int64]OrgStatus
+}
+
+// OrgStatus summarizes the alert rules of an organization known to the scheduler.
+type OrgStatus struct {
+	// ScheduledRules is the number of alert rules considered for evaluation.
+	ScheduledRules int `json:"scheduledRules"`
+	// PausedRules is the number of scheduled alert rules that are paused.
+	PausedRules int `json:"pausedRules"`
+	// RuleRoutines is the number of alert rules that have an evaluation routine running.
+	RuleRoutines int `json:"ruleRoutines"`
 }
 
 // AlertsSender is an interface for a service that is responsible for sending notifications to the end-user.
@@ -41,6 +55,17 @@ type AlertsSender interface {
 	Send(ctx context.Context, key ngmodels.AlertRuleKey, alerts definitions.PostableAlerts)
 }
 
+// RecordingWriter is an interface for a service that writes the results of recording rules.
+type RecordingWriter interface {
+	Write(ctx context.Context, name string, t time.Time, frames data.Frames, orgID int64, extraLabels map[string]string) error
+}
+
+// Sharder decides which rule groups are evaluated by this instance when the evaluation
+// of alert rules is sharded between the instances of a HA cluster.
+type Sharder interface {
+	Owns(key ngmodels.AlertRuleGroupKey) bool
+}
+
 // RulesStore is a store that provides alert rules for scheduling
 type RulesStore interface {
 	GetAlertRulesKeysForScheduling(ctx context.Context) ([]ngmodels.AlertRuleKeyWithVersion, error)
@@ -82,8 +107,12 @@ type schedule struct {
 	metrics *metrics.Scheduler
 
 	alertsSender    AlertsSender
+	recordingWriter RecordingWriter
 	minRuleInterval time.Duration
 
+	// sharder is nil when every rule is evaluated by this instance.
+	sharder Sharder
+
 	// schedulableAlertRules contains the alert rules that are considered for
 	// evaluation in the current tick. The evaluation of an alert rule in the
 	// current tick depends on its evaluation interval and when it was
@@ -105,6 +134,8 @@ type SchedulerCfg struct {
 	RuleStore            RulesStore
 	Metrics              *metrics.Scheduler
 	AlertSender          AlertsSender
+	RecordingWriter      RecordingWriter
+	Sharder              Sharder
 	Tracer               tracing.Tracer
 	Log                  log.Logger
 }
@@ -126,6 +157,8 @@ func NewScheduler(cfg SchedulerCfg, stateManager *state.Manager) *schedule {
 		minRuleInterval:       cfg.MinRuleInterval,
 		schedulableAlertRules: alertRulesRegistry{rules: make(map[ngmodels.AlertRuleKey]*ngmodels.AlertRule)},
 		alertsSender:          cfg.AlertSender,
+		recordingWriter:       cfg.RecordingWriter,
+		sharder:               cfg.Sharder,
 		tracer:                cfg.Tracer,
 	}
 
@@ -143,6 +176,25 @@ func (sch *schedule) Run(ctx context.Context) error {
 	return nil
 }
 
+func (sch *schedule) Status() map[int64]OrgStatus {
+	result := make(map[int64]OrgStatus)
+	rules, _ := sch.schedulableAlertRules.all()
+	for _, rule := range rules {
+		status := result[rule.OrgID]
+		status.ScheduledRules++
+		if rule.IsPaused {
+			status.PausedRules++
+		}
+		result[rule.OrgID] = status
+	}
+	for key := range sch.registry.keyMap() {
+		status := result[key.OrgID]
+		status.RuleRoutines++
+		result[key.OrgID] = status
+	}
+	return result
+}
+
 // deleteAlertRule stops evaluation of the rule, deletes it from active rules, and cleans up state cache.
 func (sch *schedule) deleteAlertRule(keys ...ngmodels.AlertRuleKey) {
 	for _, key := range keys {
@@ -166,6 +218,19 @@ func (sch *schedule) deleteAlertRule(keys ...ngmodels.AlertRuleKey) {
 	sch.updateRulesMetrics(alertRules)
 }
 
+// handOffAlertRule stops evaluation of the rule because it is evaluated by another instance. Unlike deleteAlertRule,
+// the state of the rule is kept in the database for the other instance to continue from it.
+func (sch *schedule) handOffAlertRule(keys ...ngmodels.AlertRuleKey) {
+	for _, key := range keys {
+		ruleInfo, ok := sch.registry.del(key)
+		if !ok {
+			continue
+		}
+		sch.log.Info("Alert rule is now evaluated by another instance", key.LogContext()...)
+		ruleInfo.stop(errRuleHandedOff)
+	}
+}
+
 func (sch *schedule) schedulePeriodic(ctx context.Context, t *ticker.T) error {
 	dispatcherGroup, ctx := errgroup.WithContext(ctx)
 	for {
@@ -245,8 +310,17 @@ func (sch *schedule) processTick(ctx context.Context, dispatcherGroup *errgroup.
 	readyToRun := make([]readyToRunItem, 0)
 	updatedRules := make([]ngmodels.AlertRuleKeyWithVersion, 0, len(updated)) // this is needed for tests only
 	missingFolder := make(map[string][]string)
+	handedOff := make([]ngmodels.AlertRuleKey, 0)
 	for _, item := range alertRules {
 		key := item.GetKey()
+		if sch.sharder != nil && !sch.sharder.Owns(item.GetGroupKey()) {
+			// the rule group is evaluated by another instance
+			if _, ok := registeredDefinitions[key]; ok {
+				delete(registeredDefinitions, key)
+				handedOff = append(handedOff, key)
+			}
+			continue
+		}
 		ruleInfo, newRoutine := sch.registry.getOrCreateInfo(ctx, key)
 
 		// enforce minimum evaluation interval
@@ -313,6 +387,8 @@ func (sch *schedule) processTick(ctx context.Context, dispatcherGroup *errgroup.
 		sch.log.Warn("Unable to obtain folder titles for some rules", "missingFolderUIDToRuleUID", missingFolder)
 	}
 
+	chainSequentialGroups(readyToRun)
+
 	var step int64 = 0
 	if len(readyToRun) > 0 {
 		step = sch.baseInterval.Nanoseconds() / int64(len(readyToRun))
@@ -325,10 +401,12 @@ func (sch *schedule) processTick(ctx context.Context, dispatcherGroup *errgroup.
 			key := item.rule.GetKey()
 			success, dropped := item.ruleInfo.eval(&item.evaluation)
 			if !success {
+				item.sequence.finish()
 				sch.log.Debug("Scheduled evaluation was canceled because evaluation routine was stopped", append(key.LogContext(), "time", tick)...)
 				return
 			}
 			if dropped != nil {
+				dropped.sequence.finish()
 				sch.log.Warn("Tick dropped because alert rule evaluation is too slow", append(key.LogContext(), "time", tick)...)
 				orgID := fmt.Sprint(key.OrgID)
 				sch.metrics.EvaluationMissed.WithLabelValues(orgID, item.rule.Title).Inc()
@@ -342,6 +420,7 @@ func (sch *schedule) processTick(ctx context.Context, dispatcherGroup *errgroup.
 		toDelete = append(toDelete, key)
 	}
 	sch.deleteAlertRule(toDelete...)
+	sch.handOffAlertRule(handedOff...)
 	return readyToRun, registeredDefinitions, updatedRules
 }
 
@@ -379,6 +458,13 @@ func (sch *schedule) ruleRoutine(grafanaCtx context.Context, key ngmodels.AlertR
 		start := sch.clock.Now()
 
 		evalCtx := eval.NewContext(ctx, SchedulerUserFor(e.rule.OrgID))
+		if e.rule.Sequential {
+			evalCtx = eval.NewContextWithRuleResults(ctx, SchedulerUserFor(e.rule.OrgID), groupResultsReader{
+				group:        e.rule.GetGroupKey(),
+				rules:        &sch.schedulableAlertRules,
+				stateManager: sch.stateManager,
+			})
+		}
 		ruleEval, err := sch.evaluatorFactory.Create(evalCtx, e.rule.GetEvalCondition())
 		var results eval.Results
 		var dur time.Duration
@@ -442,6 +528,57 @@ func (sch *schedule) ruleRoutine(grafanaCtx context.Context, key ngmodels.AlertR
 		sendDuration.Observe(sch.clock.Now().Sub(start).Seconds())
 	}
 
+	// record evaluates a recording rule and writes the result instead of updating the state of alerts.
+	record := func(ctx context.Context, f fingerprint, attempt int64, e *evaluation, span trace.Span) error {
+		logger := logger.New("version", e.rule.Version, "fingerprint", f, "attempt", attempt, "now", e.scheduledAt).FromContext(ctx)
+		start := sch.clock.Now()
+
+		evalCtx := eval.NewContext(ctx, SchedulerUserFor(e.rule.OrgID))
+		condition := ngmodels.Condition{Condition: e.rule.Record.From, Data: e.rule.Data}
+		var frames data.Frames
+		ruleEval, err := sch.evaluatorFactory.Create(evalCtx, condition)
+		if err == nil {
+			var resp *backend.QueryDataResponse
+			resp, err = ruleEval.EvaluateRaw(ctx, e.scheduledAt)
+			if err == nil {
+				result, ok := resp.Responses[e.rule.Record.From]
+				if !ok {
+					err = fmt.Errorf("no result for query or expression %s", e.rule.Record.From)
+				} else {
+					frames, err = result.Frames, result.Error
+				}
+			}
+		}
+		dur := sch.clock.Now().Sub(start)
+
+		evalTotal.Inc()
+		evalDuration.Observe(dur.Seconds())
+
+		if err != nil {
+			evalTotalFailures.Inc()
+			logger.Error("Failed to evaluate recording rule", "error", err, "duration", dur)
+			span.SetStatus(codes.Error, "rule evaluation failed")
+			span.RecordError(err)
+			return nil
+		}
+		logger.Debug("Recording rule evaluated", "frames", len(frames), "duration", dur)
+
+		if ctx.Err() != nil {
+			logger.Debug("Skip writing the result because the context has been cancelled")
+			return nil
+		}
+		if err := sch.recordingWriter.Write(ctx, e.rule.Record.Metric, e.scheduledAt, frames, e.rule.OrgID, e.rule.Labels); err != nil {
+			logger.Error("Failed to write the result of recording rule", "error", err)
+			span.SetStatus(codes.Error, "writing the result failed")
+			span.RecordError(err)
+			return err
+		}
+		span.AddEvent("result written", trace.WithAttributes(
+			attribute.Int64("frames", int64(len(frames))),
+		))
+		return nil
+	}
+
 	retryIfError := func(f func(attempt int64) error) error {
 		var attempt int64
 		var err error
@@ -454,6 +591,13 @@ func (sch *schedule) ruleRoutine(grafanaCtx context.Context, key ngmodels.AlertR
 		return err
 	}
 
+	if sch.sharder != nil {
+		// the rule might have been evaluated by another instance until now, continue from the state it saved
+		if rule := sch.schedulableAlertRules.get(key); rule != nil {
+			sch.stateManager.WarmRule(grafanaCtx, rule)
+		}
+	}
+
 	evalRunning := false
 	var currentFingerprint fingerprint
 	defer sch.stopApplied(key)
@@ -477,6 +621,7 @@ func (sch *schedule) ruleRoutine(grafanaCtx context.Context, key ngmodels.AlertR
 				return nil
 			}
 			if evalRunning {
+				ctx.sequence.finish()
 				continue
 			}
 
@@ -484,9 +629,16 @@ func (sch *schedule) ruleRoutine(grafanaCtx context.Context, key ngmodels.AlertR
 				evalRunning = true
 				defer func() {
 					evalRunning = false
+					ctx.sequence.finish()
 					sch.evalApplied(key, ctx.scheduledAt)
 				}()
 
+				// the rules of a sequential rule group are evaluated after the rule before them
+				if err := ctx.sequence.wait(grafanaCtx); err != nil {
+					logger.Debug("Skip rule evaluation because the routine is stopping")
+					return
+				}
+
 				err := retryIfError(func(attempt int64) error {
 					isPaused := ctx.rule.IsPaused
 					f := ruleWithFolder{ctx.rule, ctx.folderTitle}.Fingerprint()
@@ -520,6 +672,9 @@ func (sch *schedule) ruleRoutine(grafanaCtx context.Context, key ngmodels.AlertR
 					))
 					defer span.End()
 
+					if ctx.rule.Type() == ngmodels.RuleTypeRecording {
+						return record(tracingCtx, f, attempt, ctx, span)
+					}
 					evaluate(tracingCtx, f, attempt, ctx, span)
 					return nil
 				})
@@ -538,6 +693,10 @@ func (sch *schedule) ruleRoutine(grafanaCtx context.Context, key ngmodels.AlertR
 				states := sch.stateManager.DeleteStateByRuleUID(ngmodels.WithRuleKey(ctx, key), key, ngmodels.StateReasonRuleDeleted)
 				notify(states)
 			}
+			// keep the state in the database for the instance that evaluates the rule now
+			if errors.Is(grafanaCtx.Err(), errRuleHandedOff) {
+				sch.stateManager.ForgetStateByRuleUID(key)
+			}
 			logger.Debug("Stopping alert rule routine")
 			return nil
 		}
@@ -566,6 +566,28 @@ func TestSchedule_ruleRoutine(t *testing.T) {
 
 			require.Empty(t, sch.stateManager.GetStatesForRuleUID(rule.OrgID, rule.UID))
 		})
+		t.Run("and forget the state without resolving it if the rule is evaluated by another instance", func(t *testing.T) {
+			stoppedChan := make(chan error)
+			sender := &AlertsSenderMock{}
+			sch, _, _, _ := createSchedule(make(chan time.Time), sender)
+
+			rule := models.AlertRuleGen()()
+			_ = sch.stateManager.ProcessEvalResults(context.Background(), sch.clock.Now(), rule, eval.GenerateResults(rand.Intn(5)+1, eval.ResultGen(eval.WithEvaluatedAt(sch.clock.Now()), eval.WithState(eval.Alerting))), nil)
+			require.NotEmpty(t, sch.stateManager.GetStatesForRuleUID(rule.OrgID, rule.UID))
+
+			ctx, cancel := util.WithCancelCause(context.Background())
+			go func() {
+				err := sch.ruleRoutine(ctx, rule.GetKey(), make(chan *evaluation), make(chan ruleVersionAndPauseStatus))
+				stoppedChan <- err
+			}()
+
+			cancel(errRuleHandedOff)
+			err := waitForErrChannel(t, stoppedChan)
+			require.NoError(t, err)
+
+			require.Empty(t, sch.stateManager.GetStatesForRuleUID(rule.OrgID, rule.UID))
+			sender.AssertNotCalled(t, "Send", mock.Anything, mock.Anything, mock.Anything)
+		})
 	})
 
 	t.Run("when a message is sent to update channel", func(t *testing.T) {
@@ -651,6 +673,59 @@ func TestSchedule_ruleRoutine(t *testing.T) {
 		})
 	})
 
+	t.Run("when the rule is a recording rule", func(t *testing.T) {
+		rule := models.AlertRuleGen(withQueryForState(t, eval.Normal), models.WithRecord("test_metric"))()
+
+		evalChan := make(chan *evaluation)
+		evalAppliedChan := make(chan time.Time)
+
+		// the sender fails the test if it's called
+		sch, ruleStore, _, reg := createSchedule(evalAppliedChan, &AlertsSenderMock{})
+		ruleStore.PutRule(context.Background(), rule)
+
+		go func() {
+			ctx, cancel := context.WithCancel(context.Background())
+			t.Cleanup(cancel)
+			_ = sch.ruleRoutine(ctx, rule.GetKey(), evalChan, make(chan ruleVersionAndPauseStatus))
+		}()
+
+		expectedTime := time.UnixMicro(rand.Int63())
+		evalChan <- &evaluation{
+			scheduledAt: expectedTime,
+			rule:        rule,
+		}
+
+		waitForTimeChannel(t, evalAppliedChan)
+
+		t.Run("it should write the result", func(t *testing.T) {
+			writes := sch.recordingWriter.(*fakeRecordingWriter).Writes()
+			require.Len(t, writes, 1)
+			assert.Equal(t, "test_metric", writes[0].Name)
+			assert.Equal(t, expectedTime, writes[0].T)
+			assert.Equal(t, rule.OrgID, writes[0].OrgID)
+			assert.Equal(t, rule.Labels, writes[0].ExtraLabels)
+			require.Len(t, writes[0].Frames, 1)
+			v, err := writes[0].Frames[0].Fields[0].NullableFloatAt(0)
+			require.NoError(t, err)
+			assert.Equal(t, float64(0), *v)
+		})
+
+		t.Run("it should not create alert states", func(t *testing.T) {
+			assert.Empty(t, sch.stateManager.GetStatesForRuleUID(rule.OrgID, rule.UID))
+		})
+
+		t.Run("it should count the evaluation", func(t *testing.T) {
+			expectedMetric := fmt.Sprintf(
+				`# HELP grafana_alerting_rule_evaluations_total The total number of rule evaluations.
+				# TYPE grafana_alerting_rule_evaluations_total counter
+				grafana_alerting_rule_evaluations_total{org="%[1]d"} 1
+				`, rule.OrgID)
+
+			err := testutil.GatherAndCompare(reg, bytes.NewBufferString(expectedMetric), "grafana_alerting_rule_evaluations_total")
+			require.NoError(t, err)
+		})
+	})
+
 	t.Run("when evaluation fails", func(t *testing.T) {
 		rule := models.AlertRuleGen(withQueryForState(t, eval.Error))()
 		rule.ExecErrState = models.ErrorErrState
@@ -842,6 +917,111 @@ func TestSchedule_deleteAlertRule(t *testing.T) {
 	})
 }
 
+func TestSchedule_sharding(t *testing.T) {
+	ctx := context.Background()
+	dispatcherGroup, ctx := errgroup.WithContext(ctx)
+	ruleStore := newFakeRulesStore()
+	instanceStore := &state.FakeInstanceStore{}
+	sch := setupScheduler(t, ruleStore, instanceStore, nil, nil, nil)
+	sharder := &fakeSharder{owned: map[models.AlertRuleGroupKey]bool{}}
+	sch.sharder = sharder
+
+	evalAppliedCh := make(chan evalAppliedInfo, 1)
+	stopAppliedCh := make(chan models.AlertRuleKey, 1)
+	sch.evalAppliedFunc = func(key models.AlertRuleKey, now time.Time) {
+		evalAppliedCh <- evalAppliedInfo{alertDefKey: key, now: now}
+	}
+	sch.stopAppliedFunc = func(key models.AlertRuleKey) {
+		stopAppliedCh <- key
+	}
+
+	rule1 := models.AlertRuleGen(withQueryForState(t, eval.Normal), models.WithInterval(sch.baseInterval), models.WithGroupKey(models.AlertRuleGroupKey{OrgID: 1, NamespaceUID: "folder", RuleGroup: "group-1"}))()
+	rule2 := models.AlertRuleGen(withQueryForState(t, eval.Normal), models.WithInterval(sch.baseInterval), models.WithGroupKey(models.AlertRuleGroupKey{OrgID: 1, NamespaceUID: "folder", RuleGroup: "group-2"}))()
+	ruleStore.PutRule(ctx, rule1, rule2)
+	sharder.setOwned(rule1.GetGroupKey(), true)
+
+	tick := time.Time{}
+
+	t.Run("only the rule groups owned are evaluated", func(t *testing.T) {
+		tick = tick.Add(sch.baseInterval)
+		scheduled, stopped, _ := sch.processTick(ctx, dispatcherGroup, tick)
+
+		require.Len(t, scheduled, 1)
+		require.Equal(t, rule1, scheduled[0].rule)
+		require.Empty(t, stopped)
+		assertEvalRun(t, evalAppliedCh, tick, rule1.GetKey())
+		require.False(t, sch.registry.exists(rule2.GetKey()))
+	})
+
+	t.Run("the state of a rule group taken over is loaded from the database", func(t *testing.T) {
+		sharder.setOwned(rule1.GetGroupKey(), false)
+		sharder.setOwned(rule2.GetGroupKey(), true)
+
+		tick = tick.Add(sch.baseInterval)
+		scheduled, stopped, _ := sch.processTick(ctx, dispatcherGroup, tick)
+
+		require.Len(t, scheduled, 1)
+		require.Equal(t, rule2, scheduled[0].rule)
+		require.Empty(t, stopped, "rules evaluated by another instance are not deleted")
+		assertStopRun(t, stopAppliedCh, rule1.GetKey())
+		assertEvalRun(t, evalAppliedCh, tick, rule2.GetKey())
+
+		require.Contains(t, instanceStore.RecordedOps, models.ListAlertInstancesQuery{RuleOrgID: rule2.OrgID, RuleUID: rule2.UID})
+		require.NotNil(t, sch.schedulableAlertRules.get(rule1.GetKey()))
+	})
+}
+
+func TestSchedule_sequentialGroups(t *testing.T) {
+	ctx := context.Background()
+	dispatcherGroup, ctx := errgroup.WithContext(ctx)
+	ruleStore := newFakeRulesStore()
+	sch := setupScheduler(t, ruleStore, nil, nil, nil, nil)
+
+	evalAppliedCh := make(chan evalAppliedInfo, 2)
+	sch.evalAppliedFunc = func(key models.AlertRuleKey, now time.Time) {
+		evalAppliedCh <- evalAppliedInfo{alertDefKey: key, now: now}
+	}
+
+	groupKey := models.AlertRuleGroupKey{OrgID: 1, NamespaceUID: "folder", RuleGroup: "group"}
+	upstream := models.AlertRuleGen(withQueryForState(t, eval.Alerting), models.WithInterval(sch.baseInterval), models.WithGroupKey(groupKey))()
+	upstream.Sequential = true
+	upstream.RuleGroupIndex = 1
+	upstream.For = 0
+
+	// the downstream rule fires while the upstream rule fires
+	downstream := models.AlertRuleGen(models.WithInterval(sch.baseInterval), models.WithGroupKey(groupKey))()
+	downstream.Sequential = true
+	downstream.RuleGroupIndex = 2
+	downstream.For = 0
+	downstream.Condition = "B"
+	downstream.Data = []models.AlertQuery{
+		{
+			RefID:         "UP",
+			DatasourceUID: models.RuleResultDatasourceUID,
+			Model:         json.RawMessage(fmt.Sprintf(`{"ruleUid": %q}`, upstream.UID)),
+		},
+		{
+			RefID:         "B",
+			DatasourceUID: expr.DatasourceUID,
+			Model: json.RawMessage(`{
+				"datasourceUid": "__expr__",
+				"type":"math",
+				"expression":"$UP > 0"
+			}`),
+		},
+	}
+	ruleStore.PutRule(ctx, upstream, downstream)
+
+	tick := time.Time{}.Add(sch.baseInterval)
+	scheduled, _, _ := sch.processTick(ctx, dispatcherGroup, tick)
+	require.Len(t, scheduled, 2)
+	assertEvalRun(t, evalAppliedCh, tick, upstream.GetKey(), downstream.GetKey())
+
+	states := sch.stateManager.GetStatesForRuleUID(downstream.OrgID, downstream.UID)
+	require.Len(t, states, 1)
+	require.Equal(t, eval.Alerting, states[0].State, "the downstream rule must read the results of the upstream rule of the same evaluation")
+}
+
 func setupScheduler(t *testing.T, rs *fakeRulesStore, is *state.FakeInstanceStore, registry *prometheus.Registry, senderMock *AlertsSenderMock, evalMock eval.EvaluatorFactory) *schedule {
 	t.Helper()
 	testTracer := tracing.InitializeTracerForTest()
@@ -890,6 +1070,7 @@ func setupScheduler(t *testing.T, rs *fakeRulesStore, is *state.FakeInstanceStor
 		RuleStore:        rs,
 		Metrics:          m.GetSchedulerMetrics(),
 		AlertSender:      senderMock,
+		RecordingWriter:  &fakeRecordingWriter{},
 		Tracer:           testTracer,
 		Log:              log.New("ngalert.scheduler"),
 	}
@@ -0,0 +1,113 @@
+package schedule
+
+import (
+	"context"
+	"sort"
+	"sync"
+
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+
+	"github.com/grafana/grafana/pkg/services/ngalert/eval"
+	ngmodels "github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/services/ngalert/state"
+)
+
+// sequenceStep orders the evaluation of a rule of a sequential rule group after the evaluation of the rule before it.
+type sequenceStep struct {
+	// previous is closed once the rule before is evaluated. It is nil for the first rule of the group.
+	previous <-chan struct{}
+	done     chan struct{}
+	once     sync.Once
+}
+
+func newSequenceStep(previous <-chan struct{}) *sequenceStep {
+	return &sequenceStep{previous: previous, done: make(chan struct{})}
+}
+
+// wait blocks until the rule before is evaluated or the context is done.
+func (s *sequenceStep) wait(ctx context.Context) error {
+	if s == nil || s.previous == nil {
+		return nil
+	}
+	select {
+	case <-s.previous:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// finish lets the next rule of the group be evaluated. It must be called even if the evaluation is skipped.
+func (s *sequenceStep) finish() {
+	if s == nil {
+		return
+	}
+	s.once.Do(func() {
+		close(s.done)
+	})
+}
+
+// chainSequentialGroups orders the evaluations of the rules of sequential rule groups,
+// so that each rule is evaluated after the rule before it in the group.
+func chainSequentialGroups(items []readyToRunItem) {
+	groups := make(map[ngmodels.AlertRuleGroupKey][]*evaluation)
+	for i := range items {
+		if !items[i].rule.Sequential {
+			continue
+		}
+		key := items[i].rule.GetGroupKey()
+		groups[key] = append(groups[key], &items[i].evaluation)
+	}
+
+	for _, evaluations := range groups {
+		sort.SliceStable(evaluations, func(i, j int) bool {
+			if evaluations[i].rule.RuleGroupIndex == evaluations[j].rule.RuleGroupIndex {
+				return evaluations[i].rule.ID < evaluations[j].rule.ID
+			}
+			return evaluations[i].rule.RuleGroupIndex < evaluations[j].rule.RuleGroupIndex
+		})
+		var previous <-chan struct{}
+		for _, e := range evaluations {
+			e.sequence = newSequenceStep(previous)
+			previous = e.sequence.done
+		}
+	}
+}
+
+// groupResultsReader reads the results of the rules of a rule group from the current state of their alerts.
+type groupResultsReader struct {
+	group        ngmodels.AlertRuleGroupKey
+	rules        *alertRulesRegistry
+	stateManager *state.Manager
+}
+
+func (r groupResultsReader) Read(orgID int64, ruleUID string) []eval.AlertResult {
+	rule := r.rules.get(ngmodels.AlertRuleKey{OrgID: orgID, UID: ruleUID})
+	if rule == nil || rule.GetGroupKey() != r.group {
+		return nil
+	}
+
+	// remove the labels that the rule adds to its alerts, so that the results
+	// match the series of the queries of the rule that reads them
+	added := state.GetRuleExtraLabels(rule, "", true)
+	for k := range rule.Labels {
+		added[k] = ""
+	}
+
+	states := r.stateManager.GetStatesForRuleUID(orgID, ruleUID)
+	result := make([]eval.AlertResult, 0, len(states))
+	for _, s := range states {
+		labels := make(data.Labels, len(s.Labels))
+		for k, v := range s.Labels {
+			if _, ok := added[k]; !ok {
+				labels[k] = v
+			}
+		}
+		result = append(result, eval.AlertResult{
+			Labels: labels,
+			State:  s.State,
+			Values: s.Values,
+		})
+	}
+	return result
+}
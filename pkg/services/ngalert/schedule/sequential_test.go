@@ -0,0 +1,49 @@
+package schedule
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+)
+
+func TestChainSequentialGroups(t *testing.T) {
+	sequentialKey := models.AlertRuleGroupKey{OrgID: 1, NamespaceUID: "folder", RuleGroup: "sequential"}
+	rule := func(groupKey models.AlertRuleGroupKey, idx int, sequential bool) readyToRunItem {
+		r := models.AlertRuleGen(models.WithGroupKey(groupKey))()
+		r.RuleGroupIndex = idx
+		r.Sequential = sequential
+		return readyToRunItem{evaluation: evaluation{rule: r}}
+	}
+	items := []readyToRunItem{
+		rule(sequentialKey, 3, true),
+		rule(models.AlertRuleGroupKey{OrgID: 1, NamespaceUID: "folder", RuleGroup: "independent"}, 1, false),
+		rule(sequentialKey, 1, true),
+		rule(sequentialKey, 2, true),
+	}
+
+	chainSequentialGroups(items)
+
+	require.Nil(t, items[1].sequence, "the rules of other groups are not chained")
+	first, second, third := items[2].sequence, items[3].sequence, items[0].sequence
+
+	isBlocked := func(s *sequenceStep) bool {
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+		defer cancel()
+		return s.wait(ctx) != nil
+	}
+	require.False(t, isBlocked(first))
+	require.True(t, isBlocked(second))
+	require.True(t, isBlocked(third))
+
+	first.finish()
+	require.False(t, isBlocked(second))
+	require.True(t, isBlocked(third))
+
+	second.finish()
+	second.finish()
+	require.False(t, isBlocked(third))
+}
@@ -2,9 +2,12 @@ package schedule
 
 import (
 	"context"
+	"sync"
 	"testing"
 	"time"
 
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+
 	"github.com/grafana/grafana/pkg/services/ngalert/models"
 )
 
@@ -80,3 +83,47 @@ func (f *fakeRulesStore) DeleteRule(rules ...*models.AlertRule) {
 func (f *fakeRulesStore) getNamespaceTitle(uid string) string {
 	return "TEST-FOLDER-" + uid
 }
+
+type recordedWrite struct {
+	Name        string
+	T           time.Time
+	Frames      data.Frames
+	OrgID       int64
+	ExtraLabels map[string]string
+}
+
+type fakeRecordingWriter struct {
+	mu     sync.Mutex
+	writes []recordedWrite
+}
+
+func (w *fakeRecordingWriter) Write(_ context.Context, name string, t time.Time, frames data.Frames, orgID int64, extraLabels map[string]string) error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.writes = append(w.writes, recordedWrite{Name: name, T: t, Frames: frames, OrgID: orgID, ExtraLabels: extraLabels})
+	return nil
+}
+
+func (w *fakeRecordingWriter) Writes() []recordedWrite {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return append([]recordedWrite(nil), w.writes...)
+}
+
+// fakeSharder owns the rule groups in owned.
+type fakeSharder struct {
+	mu    sync.Mutex
+	owned map[models.AlertRuleGroupKey]bool
+}
+
+func (s *fakeSharder) Owns(key models.AlertRuleGroupKey) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.owned[key]
+}
+
+func (s *fakeSharder) setOwned(key models.AlertRuleGroupKey, owned bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.owned[key] = owned
+}
@@ -0,0 +1,77 @@
+package sharding
+
+import (
+	"hash/fnv"
+	"sort"
+	"strconv"
+)
+
+// tokensPerReplica is the number of positions each replica takes on the ring.
+// More positions spread the rule groups more evenly between the replicas.
+const tokensPerReplica = 128
+
+// Ring assigns keys to replicas with consistent hashing, so that only the keys
+// of a replica joining or leaving the ring move to another replica.
+type Ring struct {
+	replicas []string
+	tokens   []uint32
+	owners   map[uint32]string
+}
+
+// NewRing returns a ring of the replicas.
+func NewRing(replicas []string) *Ring {
+	sorted := make([]string, len(replicas))
+	copy(sorted, replicas)
+	sort.Strings(sorted)
+
+	r := &Ring{
+		replicas: sorted,
+		tokens:   make([]uint32, 0, len(sorted)*tokensPerReplica),
+		owners:   make(map[uint32]string, len(sorted)*tokensPerReplica),
+	}
+	for _, replica := range sorted {
+		for i := 0; i < tokensPerReplica; i++ {
+			token := hash(replica + "#" + strconv.Itoa(i))
+			// the first replica in order keeps the token on collisions
+			if _, ok := r.owners[token]; ok {
+				continue
+			}
+			r.owners[token] = replica
+			r.tokens = append(r.tokens, token)
+		}
+	}
+	sort.Slice(r.tokens, func(i, j int) bool { return r.tokens[i] < r.tokens[j] })
+	return r
+}
+
+// Owner returns the replica the key belongs to, or an empty string if the ring is empty.
+func (r *Ring) Owner(key string) string {
+	if len(r.tokens) == 0 {
+		return ""
+	}
+	h := hash(key)
+	i := sort.Search(len(r.tokens), func(i int) bool { return r.tokens[i] >= h })
+	if i == len(r.tokens) {
+		i = 0
+	}
+	return r.owners[r.tokens[i]]
+}
+
+// Replicas returns the replicas of the ring, in order.
+func (r *Ring) Replicas() []string {
+	return r.replicas
+}
+
+func hash(s string) uint32 {
+	h := fnv.New64a()
+	_, _ = h.Write([]byte(s))
+	// mix the bits with the finalizer of murmur3 because similar strings,
+	// such as the tokens of a replica, have close FNV hashes
+	k := h.Sum64()
+	k ^= k >> 33
+	k *= 0xff51afd7ed558ccd
+	k ^= k >> 33
+	k *= 0xc4ceb9fe1a85ec53
+	k ^= k >> 33
+	return uint32(k)
+}
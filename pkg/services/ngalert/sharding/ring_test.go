@@ -0,0 +1,60 @@
+package sharding
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRing(t *testing.T) {
+	keys := make([]string, 0, 3000)
+	for i := 0; i < cap(keys); i++ {
+		keys = append(keys, fmt.Sprintf("group-%d", i))
+	}
+
+	t.Run("empty ring owns nothing", func(t *testing.T) {
+		assert.Equal(t, "", NewRing(nil).Owner("group"))
+	})
+
+	t.Run("spreads keys between replicas", func(t *testing.T) {
+		ring := NewRing([]string{"replica-a", "replica-b", "replica-c"})
+		owned := map[string]int{}
+		for _, key := range keys {
+			owned[ring.Owner(key)]++
+		}
+		require.Len(t, owned, 3)
+		for replica, n := range owned {
+			assert.InDelta(t, len(keys)/3, n, float64(len(keys))/10, "replica %s owns %d keys", replica, n)
+		}
+	})
+
+	t.Run("doesn't depend on the order of replicas", func(t *testing.T) {
+		r1 := NewRing([]string{"replica-a", "replica-b", "replica-c"})
+		r2 := NewRing([]string{"replica-c", "replica-a", "replica-b"})
+		for _, key := range keys {
+			assert.Equal(t, r1.Owner(key), r2.Owner(key))
+		}
+	})
+
+	t.Run("only the keys of a replica leaving move", func(t *testing.T) {
+		before := NewRing([]string{"replica-a", "replica-b", "replica-c"})
+		after := NewRing([]string{"replica-a", "replica-b"})
+		for _, key := range keys {
+			if owner := before.Owner(key); owner != "replica-c" {
+				assert.Equal(t, owner, after.Owner(key))
+			}
+		}
+	})
+
+	t.Run("only keys moving to a replica joining move", func(t *testing.T) {
+		before := NewRing([]string{"replica-a", "replica-b"})
+		after := NewRing([]string{"replica-a", "replica-b", "replica-c"})
+		for _, key := range keys {
+			if owner := after.Owner(key); owner != "replica-c" {
+				assert.Equal(t, before.Owner(key), owner)
+			}
+		}
+	})
+}
@@ -0,0 +1,211 @@
+package sharding
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/benbjohnson/clock"
+	"golang.org/x/exp/slices"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+)
+
+// ReplicaStore keeps track of the replicas that share the evaluation of alert rules.
+type ReplicaStore interface {
+	// Heartbeat records that the replica is alive at the given time.
+	Heartbeat(ctx context.Context, replicaID string, at time.Time) error
+	// GetReplicas returns the identifiers of the replicas alive since the given time, in order.
+	GetReplicas(ctx context.Context, since time.Time) ([]string, error)
+	// DeleteReplica removes the replica, for example when it shuts down.
+	DeleteReplica(ctx context.Context, replicaID string) error
+	// DeleteExpired removes the replicas without heartbeats and the alerts saved before the given time.
+	DeleteExpired(ctx context.Context, before time.Time) error
+	// SaveReplicaAlerts saves the alerts sent by a replica at the given time.
+	SaveReplicaAlerts(ctx context.Context, alerts ReplicaAlerts, at time.Time) error
+	// GetReplicaAlerts returns the alerts saved since the given time by the replicas other than replicaID, in order.
+	GetReplicaAlerts(ctx context.Context, replicaID string, since time.Time) ([]ReplicaAlerts, error)
+}
+
+// ReplicaAlerts are alerts of a rule sent by a replica to the Alertmanagers.
+type ReplicaAlerts struct {
+	ID        int64
+	ReplicaID string
+	RuleKey   models.AlertRuleKey
+	Alerts    definitions.PostableAlerts
+}
+
+// AlertsSender sends alerts to the Alertmanagers of the replica.
+type AlertsSender interface {
+	Send(ctx context.Context, key models.AlertRuleKey, alerts definitions.PostableAlerts)
+}
+
+// Service shards the evaluation of rule groups between the replicas of a HA cluster.
+// Each replica sends heartbeats to the database and assigns the rule groups to the
+// replicas alive on a consistent hash ring. When a replica joins or leaves, only the
+// rule groups of that replica move to another one.
+//
+// As the Alertmanagers of the replicas don't share alerts, the alerts sent by each replica
+// are saved to the database and sent by the other replicas to their Alertmanagers too, so
+// that every Alertmanager has the alerts of all the rules as without sharding.
+type Service struct {
+	replicaID         string
+	heartbeatInterval time.Duration
+	heartbeatTimeout  time.Duration
+
+	store  ReplicaStore
+	sender AlertsSender
+	clock  clock.Clock
+	logger log.Logger
+
+	mtx  sync.RWMutex
+	ring *Ring
+
+	// sentAlerts are the ids of the alerts of the other replicas already sent by this one.
+	sentAlerts map[int64]struct{}
+	onRefresh  []func(ctx context.Context)
+}
+
+func NewService(replicaID string, heartbeatInterval, heartbeatTimeout time.Duration, store ReplicaStore, sender AlertsSender, clk clock.Clock, logger log.Logger) *Service {
+	return &Service{
+		replicaID:         replicaID,
+		heartbeatInterval: heartbeatInterval,
+		heartbeatTimeout:  heartbeatTimeout,
+		store:             store,
+		sender:            sender,
+		clock:             clk,
+		logger:            logger.New("replica", replicaID),
+		sentAlerts:        map[int64]struct{}{},
+	}
+}
+
+// DefaultReplicaID returns the identifier of the replica when none is configured: the hostname followed by the HTTP port.
+func DefaultReplicaID(httpPort string) (string, error) {
+	hostname, err := os.Hostname()
+	if err != nil {
+		return "", fmt.Errorf("failed to get the hostname: %w", err)
+	}
+	return net.JoinHostPort(hostname, httpPort), nil
+}
+
+// OnRefresh registers a function called after every refresh of the replicas, for example to load the
+// state of the rules evaluated by the other replicas. It must be called before Run.
+func (s *Service) OnRefresh(fn func(ctx context.Context)) {
+	s.onRefresh = append(s.onRefresh, fn)
+}
+
+// Run sends heartbeats, updates the replicas of the ring and sends the alerts of the other replicas
+// until the context is canceled. The replica leaves the ring when it stops so that its rule groups
+// move to the others right away.
+func (s *Service) Run(ctx context.Context) error {
+	s.logger.Info("Starting sharding of rule evaluation", "heartbeatInterval", s.heartbeatInterval, "heartbeatTimeout", s.heartbeatTimeout)
+	ticker := s.clock.Ticker(s.heartbeatInterval)
+	defer ticker.Stop()
+
+	for {
+		if err := s.Refresh(ctx); err != nil {
+			s.logger.Error("Failed to update the replicas evaluating alert rules", "error", err)
+		}
+		if err := s.SendReplicaAlerts(ctx); err != nil {
+			s.logger.Error("Failed to send the alerts of the other replicas", "error", err)
+		}
+		for _, fn := range s.onRefresh {
+			fn(ctx)
+		}
+
+		select {
+		case <-ticker.C:
+		case <-ctx.Done():
+			leaveCtx, cancel := context.WithTimeout(context.Background(), s.heartbeatInterval)
+			defer cancel()
+			if err := s.store.DeleteReplica(leaveCtx, s.replicaID); err != nil {
+				s.logger.Warn("Failed to leave the replicas evaluating alert rules", "error", err)
+			}
+			return nil
+		}
+	}
+}
+
+// Refresh sends a heartbeat and updates the ring with the replicas alive. The ring doesn't
+// change if the replicas can't be read, so that the rule groups don't move during a database outage.
+func (s *Service) Refresh(ctx context.Context) error {
+	now := s.clock.Now()
+	if err := s.store.Heartbeat(ctx, s.replicaID, now); err != nil {
+		return err
+	}
+	if err := s.store.DeleteExpired(ctx, now.Add(-s.heartbeatTimeout)); err != nil {
+		s.logger.Warn("Failed to delete the expired replicas", "error", err)
+	}
+	replicas, err := s.store.GetReplicas(ctx, now.Add(-s.heartbeatTimeout))
+	if err != nil {
+		return err
+	}
+	if !slices.Contains(replicas, s.replicaID) {
+		replicas = append(replicas, s.replicaID)
+	}
+
+	ring := NewRing(replicas)
+
+	s.mtx.Lock()
+	previous := s.ring
+	s.ring = ring
+	s.mtx.Unlock()
+
+	if previous == nil || !slices.Equal(previous.Replicas(), ring.Replicas()) {
+		s.logger.Info("Replicas evaluating alert rules changed", "replicas", ring.Replicas())
+	}
+	return nil
+}
+
+// Owns returns true if the rule group is evaluated by this replica. Every rule
+// group is owned until the replicas are known, so that no rule group is skipped at startup.
+func (s *Service) Owns(key models.AlertRuleGroupKey) bool {
+	s.mtx.RLock()
+	defer s.mtx.RUnlock()
+	if s.ring == nil {
+		return true
+	}
+	return s.ring.Owner(key.String()) == s.replicaID
+}
+
+// Send sends the alerts of a rule evaluated by this replica to its Alertmanagers, and
+// saves them for the other replicas to send them to theirs.
+func (s *Service) Send(ctx context.Context, key models.AlertRuleKey, alerts definitions.PostableAlerts) {
+	s.sender.Send(ctx, key, alerts)
+	if len(alerts.PostableAlerts) == 0 {
+		return
+	}
+
+	replicaAlerts := ReplicaAlerts{ReplicaID: s.replicaID, RuleKey: key, Alerts: alerts}
+	if err := s.store.SaveReplicaAlerts(ctx, replicaAlerts, s.clock.Now()); err != nil {
+		s.logger.Error("Failed to share alerts with the other replicas", append(key.LogContext(), "error", err)...)
+	}
+}
+
+// SendReplicaAlerts sends the alerts saved by the other replicas since the last call to the Alertmanagers
+// of this replica. The alerts are kept in the database for the heartbeat timeout, longer than the
+// interval between two calls.
+func (s *Service) SendReplicaAlerts(ctx context.Context) error {
+	replicaAlerts, err := s.store.GetReplicaAlerts(ctx, s.replicaID, s.clock.Now().Add(-s.heartbeatTimeout))
+	if err != nil {
+		return err
+	}
+
+	// the alerts are identified by id rather than read after the last id, because
+	// the alerts saved concurrently by several replicas are not committed in order.
+	sent := make(map[int64]struct{}, len(replicaAlerts))
+	for _, a := range replicaAlerts {
+		sent[a.ID] = struct{}{}
+		if _, ok := s.sentAlerts[a.ID]; ok {
+			continue
+		}
+		s.sender.Send(ctx, a.RuleKey, a.Alerts)
+	}
+	s.sentAlerts = sent
+	return nil
+}
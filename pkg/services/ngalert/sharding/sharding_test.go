@@ -0,0 +1,203 @@
+package sharding
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/benbjohnson/clock"
+	amv2 "github.com/prometheus/alertmanager/api/v2/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+)
+
+type fakeReplicaStore struct {
+	heartbeats map[string]time.Time
+	alerts     []ReplicaAlerts
+	alertsAt   []time.Time
+	err        error
+}
+
+func (f *fakeReplicaStore) Heartbeat(_ context.Context, replicaID string, at time.Time) error {
+	if f.err != nil {
+		return f.err
+	}
+	f.heartbeats[replicaID] = at
+	return nil
+}
+
+func (f *fakeReplicaStore) GetReplicas(_ context.Context, since time.Time) ([]string, error) {
+	if f.err != nil {
+		return nil, f.err
+	}
+	var result []string
+	for id, at := range f.heartbeats {
+		if !at.Before(since) {
+			result = append(result, id)
+		}
+	}
+	return result, nil
+}
+
+func (f *fakeReplicaStore) DeleteReplica(_ context.Context, replicaID string) error {
+	delete(f.heartbeats, replicaID)
+	return nil
+}
+
+func (f *fakeReplicaStore) DeleteExpired(_ context.Context, before time.Time) error {
+	for id, at := range f.heartbeats {
+		if at.Before(before) {
+			delete(f.heartbeats, id)
+		}
+	}
+	return nil
+}
+
+func (f *fakeReplicaStore) SaveReplicaAlerts(_ context.Context, alerts ReplicaAlerts, at time.Time) error {
+	if f.err != nil {
+		return f.err
+	}
+	alerts.ID = int64(len(f.alerts) + 1)
+	f.alerts = append(f.alerts, alerts)
+	f.alertsAt = append(f.alertsAt, at)
+	return nil
+}
+
+func (f *fakeReplicaStore) GetReplicaAlerts(_ context.Context, replicaID string, since time.Time) ([]ReplicaAlerts, error) {
+	if f.err != nil {
+		return nil, f.err
+	}
+	var result []ReplicaAlerts
+	for i, a := range f.alerts {
+		if a.ReplicaID != replicaID && !f.alertsAt[i].Before(since) {
+			result = append(result, a)
+		}
+	}
+	return result, nil
+}
+
+type sentAlerts struct {
+	key    models.AlertRuleKey
+	alerts definitions.PostableAlerts
+}
+
+type fakeSender struct {
+	sent []sentAlerts
+}
+
+func (f *fakeSender) Send(_ context.Context, key models.AlertRuleKey, alerts definitions.PostableAlerts) {
+	f.sent = append(f.sent, sentAlerts{key: key, alerts: alerts})
+}
+
+func TestService(t *testing.T) {
+	groups := make([]models.AlertRuleGroupKey, 0, 100)
+	for i := 0; i < cap(groups); i++ {
+		groups = append(groups, models.AlertRuleGroupKey{OrgID: 1, NamespaceUID: "folder", RuleGroup: fmt.Sprintf("group-%d", i)})
+	}
+
+	// owners returns the number of services owning each group
+	owners := func(services ...*Service) []int {
+		result := make([]int, len(groups))
+		for i, g := range groups {
+			for _, s := range services {
+				if s.Owns(g) {
+					result[i]++
+				}
+			}
+		}
+		return result
+	}
+	ownedBy := func(s *Service) int {
+		n := 0
+		for _, g := range groups {
+			if s.Owns(g) {
+				n++
+			}
+		}
+		return n
+	}
+
+	ctx := context.Background()
+	clk := clock.NewMock()
+	store := &fakeReplicaStore{heartbeats: map[string]time.Time{}}
+	a := NewService("a", 10*time.Second, time.Minute, store, &fakeSender{}, clk, log.NewNopLogger())
+	b := NewService("b", 10*time.Second, time.Minute, store, &fakeSender{}, clk, log.NewNopLogger())
+
+	// every group is owned before the replicas are known
+	assert.Equal(t, len(groups), ownedBy(a))
+
+	require.NoError(t, a.Refresh(ctx))
+	assert.Equal(t, len(groups), ownedBy(a))
+
+	// a replica joining takes a share of the groups
+	require.NoError(t, b.Refresh(ctx))
+	require.NoError(t, a.Refresh(ctx))
+	for _, n := range owners(a, b) {
+		assert.Equal(t, 1, n)
+	}
+	assert.NotZero(t, ownedBy(b))
+
+	// the store is unavailable, the groups don't move
+	store.err = errors.New("unavailable")
+	require.Error(t, a.Refresh(ctx))
+	for _, n := range owners(a, b) {
+		assert.Equal(t, 1, n)
+	}
+	store.err = nil
+
+	// a replica without heartbeats leaves the ring once they time out
+	clk.Add(30 * time.Second)
+	require.NoError(t, a.Refresh(ctx))
+	assert.Less(t, ownedBy(a), len(groups))
+	clk.Add(31 * time.Second)
+	require.NoError(t, a.Refresh(ctx))
+	assert.Equal(t, len(groups), ownedBy(a))
+
+	// the heartbeats of the replicas that left are deleted
+	assert.NotContains(t, store.heartbeats, "b")
+}
+
+func TestServiceSendsAlertsToAllReplicas(t *testing.T) {
+	ctx := context.Background()
+	clk := clock.NewMock()
+	store := &fakeReplicaStore{heartbeats: map[string]time.Time{}}
+	senderA, senderB := &fakeSender{}, &fakeSender{}
+	a := NewService("a", 10*time.Second, time.Minute, store, senderA, clk, log.NewNopLogger())
+	b := NewService("b", 10*time.Second, time.Minute, store, senderB, clk, log.NewNopLogger())
+
+	key := models.AlertRuleKey{OrgID: 1, UID: "rule"}
+	alerts := definitions.PostableAlerts{PostableAlerts: []amv2.PostableAlert{
+		{Alert: amv2.Alert{Labels: amv2.LabelSet{"alertname": "test"}}},
+	}}
+
+	// the alerts are sent by the replica evaluating the rule and shared with the others
+	a.Send(ctx, key, alerts)
+	a.Send(ctx, key, definitions.PostableAlerts{})
+	require.Len(t, senderA.sent, 2)
+	require.Len(t, store.alerts, 1)
+
+	require.NoError(t, b.SendReplicaAlerts(ctx))
+	require.Equal(t, []sentAlerts{{key: key, alerts: alerts}}, senderB.sent)
+
+	// the replica doesn't send its own alerts, nor the ones already sent
+	require.NoError(t, a.SendReplicaAlerts(ctx))
+	require.NoError(t, b.SendReplicaAlerts(ctx))
+	assert.Len(t, senderA.sent, 2)
+	assert.Len(t, senderB.sent, 1)
+
+	a.Send(ctx, key, alerts)
+	require.NoError(t, b.SendReplicaAlerts(ctx))
+	assert.Len(t, senderB.sent, 2)
+
+	// the alerts older than the heartbeat timeout are not sent
+	clk.Add(2 * time.Minute)
+	b = NewService("b", 10*time.Second, time.Minute, store, senderB, clk, log.NewNopLogger())
+	require.NoError(t, b.SendReplicaAlerts(ctx))
+	assert.Len(t, senderB.sent, 2)
+}
@@ -244,6 +244,16 @@ func (c *cache) setAllStates(newStates map[int64]map[string]*ruleStates) {
 	c.states = newStates
 }
 
+// setRuleStates replaces the states of a rule.
+func (c *cache) setRuleStates(orgID int64, ruleUID string, states map[string]*State) {
+	c.mtxStates.Lock()
+	defer c.mtxStates.Unlock()
+	if _, ok := c.states[orgID]; !ok {
+		c.states[orgID] = make(map[string]*ruleStates)
+	}
+	c.states[orgID][ruleUID] = &ruleStates{states: states}
+}
+
 func (c *cache) set(entry *State) {
 	c.mtxStates.Lock()
 	defer c.mtxStates.Unlock()
@@ -306,6 +316,14 @@ func (c *cache) getStatesForRuleUID(orgID int64, alertRuleUID string, skipNormal
 	return result
 }
 
+// hasRuleStates returns true if the cache has states for the rule.
+func (c *cache) hasRuleStates(orgID int64, alertRuleUID string) bool {
+	c.mtxStates.RLock()
+	defer c.mtxStates.RUnlock()
+	_, ok := c.states[orgID][alertRuleUID]
+	return ok
+}
+
 // removeByRuleUID deletes all entries in the state cache that match the given UID. Returns removed states
 func (c *cache) removeByRuleUID(orgID int64, uid string) []*State {
 	c.mtxStates.Lock()
@@ -38,6 +38,10 @@ type Manager struct {
 	cache       *cache
 	ResendDelay time.Duration
 
+	// sharedStates are the states of all the rules loaded from the instance store when the evaluation
+	// of the rules is sharded between several instances. They are returned for the rules evaluated by other instances.
+	sharedStates *cache
+
 	instanceStore InstanceStore
 	images        ImageCapturer
 	historian     Historian
@@ -45,6 +49,7 @@ type Manager struct {
 
 	doNotSaveNormalState           bool
 	maxStateSaveConcurrency        int
+	saveStatesPerRule              bool
 	applyNoDataAndErrorToAllStates bool
 }
 
@@ -59,11 +64,18 @@ type ManagerCfg struct {
 	DoNotSaveNormalState bool
 	// MaxStateSaveConcurrency controls the number of goroutines (per rule) that can save alert state in parallel.
 	MaxStateSaveConcurrency int
+	// SaveStatesPerRule saves all the states of a rule at once after each evaluation instead of saving the changed
+	// states one by one. It suits instance stores that store the states of a rule together.
+	SaveStatesPerRule bool
 
 	// ApplyNoDataAndErrorToAllStates makes state manager to apply exceptional results (NoData and Error)
 	// to all states when corresponding execution in the rule definition is set to either `Alerting` or `OK`
 	ApplyNoDataAndErrorToAllStates bool
 
+	// ShardedEvaluation tells that the rules are evaluated by several instances, each evaluating a share of them.
+	// The states of the rules evaluated by the other instances are then returned from the instance store.
+	ShardedEvaluation bool
+
 	Tracer tracing.Tracer
 	Log    log.Logger
 }
@@ -87,10 +99,15 @@ func NewManager(cfg ManagerCfg) *Manager {
 		externalURL:                    cfg.ExternalURL,
 		doNotSaveNormalState:           cfg.DoNotSaveNormalState,
 		maxStateSaveConcurrency:        cfg.MaxStateSaveConcurrency,
+		saveStatesPerRule:              cfg.SaveStatesPerRule,
 		applyNoDataAndErrorToAllStates: cfg.ApplyNoDataAndErrorToAllStates,
 		tracer:                         cfg.Tracer,
 	}
 
+	if cfg.ShardedEvaluation {
+		m.sharedStates = newCache()
+	}
+
 	if m.applyNoDataAndErrorToAllStates {
 		m.log.Info("Running in alternative execution of Error/NoData mode")
 	}
@@ -106,6 +123,24 @@ func (st *Manager) Warm(ctx context.Context, rulesReader RuleReader) {
 	startTime := time.Now()
 	st.log.Info("Warming state cache for startup")
 
+	states, statesCount := st.loadStates(ctx, rulesReader)
+	st.cache.setAllStates(states)
+	st.log.Info("State cache has been initialized", "states", statesCount, "duration", time.Since(startTime))
+}
+
+// WarmSharedStates loads the states of all the rules from the instance store. They are returned by GetAll and
+// GetStatesForRuleUID for the rules evaluated by other instances when the evaluation is sharded.
+func (st *Manager) WarmSharedStates(ctx context.Context, rulesReader RuleReader) {
+	if st.sharedStates == nil || st.instanceStore == nil {
+		return
+	}
+	states, statesCount := st.loadStates(ctx, rulesReader)
+	st.sharedStates.setAllStates(states)
+	st.log.Debug("Shared states have been loaded", "states", statesCount)
+}
+
+// loadStates returns the states of all the rules saved in the instance store, and their number.
+func (st *Manager) loadStates(ctx context.Context, rulesReader RuleReader) (map[int64]map[string]*ruleStates, int) {
 	orgIds, err := st.instanceStore.FetchOrgIds(ctx)
 	if err != nil {
 		st.log.Error("Unable to fetch orgIds", "error", err)
@@ -153,29 +188,66 @@ func (st *Manager) Warm(ctx context.Context, rulesReader RuleReader) {
 				orgStates[entry.RuleUID] = rulesStates
 			}
 
-			lbs := map[string]string(entry.Labels)
-			cacheID, err := entry.Labels.StringKey()
-			if err != nil {
-				st.log.Error("Error getting cacheId for entry", "error", err)
-			}
-			rulesStates.states[cacheID] = &State{
-				AlertRuleUID:         entry.RuleUID,
-				OrgID:                entry.RuleOrgID,
-				CacheID:              cacheID,
-				Labels:               lbs,
-				State:                translateInstanceState(entry.CurrentState),
-				StateReason:          entry.CurrentReason,
-				LastEvaluationString: "",
-				StartsAt:             entry.CurrentStateSince,
-				EndsAt:               entry.CurrentStateEnd,
-				LastEvaluationTime:   entry.LastEvalTime,
-				Annotations:          ruleForEntry.Annotations,
-			}
+			s := stateFromInstance(entry, ruleForEntry, st.log)
+			rulesStates.states[s.CacheID] = s
 			statesCount++
 		}
 	}
-	st.cache.setAllStates(states)
-	st.log.Info("State cache has been initialized", "states", statesCount, "duration", time.Since(startTime))
+	return states, statesCount
+}
+
+// WarmRule loads the states of the rule from the instance store, replacing the states of the rule in the cache.
+// It is used when the rule starts being evaluated by this instance after being evaluated by another one.
+func (st *Manager) WarmRule(ctx context.Context, rule *ngModels.AlertRule) {
+	if st.instanceStore == nil {
+		return
+	}
+	logger := st.log.FromContext(ctx)
+
+	cmd := ngModels.ListAlertInstancesQuery{
+		RuleOrgID: rule.OrgID,
+		RuleUID:   rule.UID,
+	}
+	alertInstances, err := st.instanceStore.ListAlertInstances(ctx, &cmd)
+	if err != nil {
+		logger.Error("Unable to fetch previous state of the rule", "error", err)
+		return
+	}
+
+	states := make(map[string]*State, len(alertInstances))
+	for _, entry := range alertInstances {
+		s := stateFromInstance(entry, rule, logger)
+		states[s.CacheID] = s
+	}
+	st.cache.setRuleStates(rule.OrgID, rule.UID, states)
+	logger.Debug("State of the rule has been loaded", "states", len(states))
+}
+
+// ForgetStateByRuleUID removes the states of the rule from the cache but keeps them in the instance store,
+// so that another instance can continue evaluating the rule from them.
+func (st *Manager) ForgetStateByRuleUID(ruleKey ngModels.AlertRuleKey) {
+	st.cache.removeByRuleUID(ruleKey.OrgID, ruleKey.UID)
+}
+
+func stateFromInstance(entry *ngModels.AlertInstance, rule *ngModels.AlertRule, logger log.Logger) *State {
+	cacheID, err := entry.Labels.StringKey()
+	if err != nil {
+		logger.Error("Error getting cacheId for entry", "error", err)
+	}
+	return &State{
+		AlertRuleUID:         entry.RuleUID,
+		OrgID:                entry.RuleOrgID,
+		CacheID:              cacheID,
+		Labels:               map[string]string(entry.Labels),
+		State:                translateInstanceState(entry.CurrentState),
+		StateReason:          entry.CurrentReason,
+		LastEvaluationString: "",
+		StartsAt:             entry.CurrentStateSince,
+		EndsAt:               entry.CurrentStateEnd,
+		LastEvaluationTime:   entry.LastEvalTime,
+		KeepFiringSince:      entry.KeepFiringSince,
+		Annotations:          rule.Annotations,
+	}
 }
 
 func (st *Manager) Get(orgID int64, alertRuleUID, stateId string) *State {
@@ -269,7 +341,9 @@ func (st *Manager) ProcessEvalResults(ctx context.Context, evaluatedAt time.Time
 	))
 
 	staleStates := st.deleteStaleStatesFromCache(ctx, logger, evaluatedAt, alertRule)
-	st.deleteAlertStates(tracingCtx, logger, staleStates)
+	if !st.saveStatesPerRule {
+		st.deleteAlertStates(tracingCtx, logger, staleStates)
+	}
 
 	if len(staleStates) > 0 {
 		span.AddEvent("deleted stale states", trace.WithAttributes(
@@ -277,7 +351,11 @@ func (st *Manager) ProcessEvalResults(ctx context.Context, evaluatedAt time.Time
 		))
 	}
 
-	st.saveAlertStates(tracingCtx, logger, states...)
+	if st.saveStatesPerRule {
+		st.saveRuleStates(tracingCtx, logger, alertRule.GetKey())
+	} else {
+		st.saveAlertStates(tracingCtx, logger, states...)
+	}
 	span.AddEvent("updated database")
 
 	allChanges := append(states, staleStates...)
@@ -387,6 +465,10 @@ func (st *Manager) setNextState(ctx context.Context, alertRule *ngModels.AlertRu
 		currentState.StateReason = result.State.String()
 	}
 
+	if !currentState.KeepFiringSince.IsZero() {
+		currentState.StateReason = ngModels.StateReasonKeepFiring
+	}
+
 	// Set Resolved property so the scheduler knows to send a postable alert
 	// to Alertmanager.
 	currentState.Resolved = oldState == eval.Alerting && currentState.State == eval.Normal
@@ -420,9 +502,20 @@ func (st *Manager) setNextState(ctx context.Context, alertRule *ngModels.AlertRu
 
 func (st *Manager) GetAll(orgID int64) []*State {
 	allStates := st.cache.getAll(orgID, st.doNotSaveNormalState)
+	if st.sharedStates != nil {
+		// the rules without states in the cache are evaluated by other instances
+		for _, s := range st.sharedStates.getAll(orgID, st.doNotSaveNormalState) {
+			if !st.cache.hasRuleStates(orgID, s.AlertRuleUID) {
+				allStates = append(allStates, s)
+			}
+		}
+	}
 	return allStates
 }
 func (st *Manager) GetStatesForRuleUID(orgID int64, alertRuleUID string) []*State {
+	if st.sharedStates != nil && !st.cache.hasRuleStates(orgID, alertRuleUID) {
+		return st.sharedStates.getStatesForRuleUID(orgID, alertRuleUID, st.doNotSaveNormalState)
+	}
 	return st.cache.getStatesForRuleUID(orgID, alertRuleUID, st.doNotSaveNormalState)
 }
 
@@ -445,20 +538,11 @@ func (st *Manager) saveAlertStates(ctx context.Context, logger log.Logger, state
 			return nil
 		}
 
-		key, err := s.GetAlertInstanceKey()
+		instance, err := instanceFromState(s.State)
 		if err != nil {
 			logger.Error("Failed to create a key for alert state to save it to database. The state will be ignored ", "cacheID", s.CacheID, "error", err, "labels", s.Labels.String())
 			return nil
 		}
-		instance := ngModels.AlertInstance{
-			AlertInstanceKey:  key,
-			Labels:            ngModels.InstanceLabels(s.Labels),
-			CurrentState:      ngModels.InstanceStateType(s.State.State.String()),
-			CurrentReason:     s.StateReason,
-			LastEvalTime:      s.LastEvaluationTime,
-			CurrentStateSince: s.StartsAt,
-			CurrentStateEnd:   s.EndsAt,
-		}
 
 		err = st.instanceStore.SaveAlertInstance(ctx, instance)
 		if err != nil {
@@ -474,6 +558,50 @@ func (st *Manager) saveAlertStates(ctx context.Context, logger log.Logger, state
 	logger.Debug("Saving alert states done", "count", len(states), "max_state_save_concurrency", st.maxStateSaveConcurrency, "duration", time.Since(start))
 }
 
+// saveRuleStates replaces the states of the rule in the instance store with the states of the rule in the cache,
+// which includes neither the stale states nor, if they are not saved, the normal states.
+func (st *Manager) saveRuleStates(ctx context.Context, logger log.Logger, key ngModels.AlertRuleKey) {
+	if st.instanceStore == nil {
+		return
+	}
+
+	states := st.cache.getStatesForRuleUID(key.OrgID, key.UID, st.doNotSaveNormalState)
+	instances := make([]ngModels.AlertInstance, 0, len(states))
+	for _, s := range states {
+		instance, err := instanceFromState(s)
+		if err != nil {
+			logger.Error("Failed to create a key for alert state to save it to database. The state will be ignored ", "cacheID", s.CacheID, "error", err, "labels", s.Labels.String())
+			continue
+		}
+		instances = append(instances, instance)
+	}
+
+	start := time.Now()
+	logger.Debug("Saving alert states of the rule", "count", len(instances))
+	if err := st.instanceStore.SaveAlertInstancesForRule(ctx, key, instances); err != nil {
+		logger.Error("Failed to save alert states of the rule", "count", len(instances), "error", err)
+		return
+	}
+	logger.Debug("Saving alert states of the rule done", "count", len(instances), "duration", time.Since(start))
+}
+
+func instanceFromState(s *State) (ngModels.AlertInstance, error) {
+	key, err := s.GetAlertInstanceKey()
+	if err != nil {
+		return ngModels.AlertInstance{}, err
+	}
+	return ngModels.AlertInstance{
+		AlertInstanceKey:  key,
+		Labels:            ngModels.InstanceLabels(s.Labels),
+		CurrentState:      ngModels.InstanceStateType(s.State.String()),
+		CurrentReason:     s.StateReason,
+		LastEvalTime:      s.LastEvaluationTime,
+		CurrentStateSince: s.StartsAt,
+		CurrentStateEnd:   s.EndsAt,
+		KeepFiringSince:   s.KeepFiringSince,
+	}, nil
+}
+
 func (st *Manager) deleteAlertStates(ctx context.Context, logger log.Logger, states []StateTransition) {
 	if st.instanceStore == nil || len(states) == 0 {
 		return
@@ -670,6 +670,119 @@ func TestProcessEvalResults_StateTransitions(t *testing.T) {
 				},
 			},
 		},
+		{
+			desc:      "t1[1:alerting] t2[1:normal] t3[1:normal] t4[1:normal] and 'keep_firing_for'=2 at t2,t3,t4",
+			alertRule: baseRuleWith(ngmodels.WithKeepFiringFor(2 * evaluationInterval)),
+			results: map[time.Time]eval.Results{
+				t1: {
+					newResult(eval.WithState(eval.Alerting), eval.WithLabels(labels1)),
+				},
+				t2: {
+					newResult(eval.WithState(eval.Normal), eval.WithLabels(labels1)),
+				},
+				t3: {
+					newResult(eval.WithState(eval.Normal), eval.WithLabels(labels1)),
+				},
+				tN(4): {
+					newResult(eval.WithState(eval.Normal), eval.WithLabels(labels1)),
+				},
+			},
+			expectedTransitions: map[time.Time][]StateTransition{
+				t2: {
+					{
+						PreviousState: eval.Alerting,
+						State: &State{
+							Labels:      labels["system + rule + labels1"],
+							State:       eval.Alerting,
+							StateReason: ngmodels.StateReasonKeepFiring,
+							Results: []Evaluation{
+								newEvaluation(t1, eval.Alerting),
+								newEvaluation(t2, eval.Normal),
+							},
+							KeepFiringSince:    t2,
+							StartsAt:           t1,
+							EndsAt:             t2.Add(ResendDelay * 4),
+							LastEvaluationTime: t2,
+						},
+					},
+				},
+				t3: {
+					{
+						PreviousState:       eval.Alerting,
+						PreviousStateReason: ngmodels.StateReasonKeepFiring,
+						State: &State{
+							Labels:      labels["system + rule + labels1"],
+							State:       eval.Alerting,
+							StateReason: ngmodels.StateReasonKeepFiring,
+							Results: []Evaluation{
+								newEvaluation(t1, eval.Alerting),
+								newEvaluation(t2, eval.Normal),
+								newEvaluation(t3, eval.Normal),
+							},
+							KeepFiringSince:    t2,
+							StartsAt:           t1,
+							EndsAt:             t3.Add(ResendDelay * 4),
+							LastEvaluationTime: t3,
+						},
+					},
+				},
+				tN(4): {
+					{
+						PreviousState:       eval.Alerting,
+						PreviousStateReason: ngmodels.StateReasonKeepFiring,
+						State: &State{
+							Labels: labels["system + rule + labels1"],
+							State:  eval.Normal,
+							Results: []Evaluation{
+								newEvaluation(t1, eval.Alerting),
+								newEvaluation(t2, eval.Normal),
+								newEvaluation(t3, eval.Normal),
+								newEvaluation(tN(4), eval.Normal),
+							},
+							StartsAt:           tN(4),
+							EndsAt:             tN(4),
+							LastEvaluationTime: tN(4),
+							Resolved:           true,
+						},
+					},
+				},
+			},
+		},
+		{
+			desc:      "t1[1:alerting] t2[1:normal] t3[1:alerting] and 'keep_firing_for'=2 at t3",
+			alertRule: baseRuleWith(ngmodels.WithKeepFiringFor(2 * evaluationInterval)),
+			results: map[time.Time]eval.Results{
+				t1: {
+					newResult(eval.WithState(eval.Alerting), eval.WithLabels(labels1)),
+				},
+				t2: {
+					newResult(eval.WithState(eval.Normal), eval.WithLabels(labels1)),
+				},
+				t3: {
+					newResult(eval.WithState(eval.Alerting), eval.WithLabels(labels1)),
+				},
+			},
+			expectedTransitions: map[time.Time][]StateTransition{
+				t3: {
+					{
+						PreviousState:       eval.Alerting,
+						PreviousStateReason: ngmodels.StateReasonKeepFiring,
+						State: &State{
+							Labels: labels["system + rule + labels1"],
+							State:  eval.Alerting,
+							Results: []Evaluation{
+								newEvaluation(t1, eval.Alerting),
+								newEvaluation(t2, eval.Normal),
+								newEvaluation(t3, eval.Alerting),
+							},
+							StartsAt:           t1,
+							EndsAt:             t3.Add(ResendDelay * 4),
+							LastEvaluationTime: t3,
+						},
+					},
+				},
+			},
+		},
 		{
 			desc:      "t1[1:normal,2:alerting,3:normal] t2[3:normal] t3[3:normal] at t2,t3",
 			alertRule: baseRule,
@@ -217,6 +217,46 @@ func TestWarmStateCache(t *testing.T) {
 			}
 		}
 	})
+
+	t.Run("rule states can be loaded and forgotten", func(t *testing.T) {
+		cfg.Metrics = metrics.NewNGAlert(prometheus.NewPedanticRegistry()).GetStateMetrics()
+		st := state.NewManager(cfg)
+		st.WarmRule(ctx, rule)
+		for _, entry := range expectedEntries {
+			setCacheID(entry)
+			cacheEntry := st.Get(entry.OrgID, entry.AlertRuleUID, entry.CacheID)
+
+			if diff := cmp.Diff(entry, cacheEntry, cmpopts.IgnoreFields(state.State{}, "Results")); diff != "" {
+				t.Errorf("Result mismatch (-want +got):\n%s", diff)
+				t.FailNow()
+			}
+		}
+
+		st.ForgetStateByRuleUID(rule.GetKey())
+		require.Empty(t, st.GetStatesForRuleUID(rule.OrgID, rule.UID))
+
+		// the states are still in the database
+		st.WarmRule(ctx, rule)
+		require.Len(t, st.GetStatesForRuleUID(rule.OrgID, rule.UID), len(expectedEntries))
+	})
+
+	t.Run("states of the rules evaluated by other instances are shared", func(t *testing.T) {
+		cfg := cfg
+		cfg.Metrics = metrics.NewNGAlert(prometheus.NewPedanticRegistry()).GetStateMetrics()
+		cfg.ShardedEvaluation = true
+		st := state.NewManager(cfg)
+		require.Empty(t, st.GetAll(rule.OrgID))
+
+		st.WarmSharedStates(ctx, dbstore)
+		require.Len(t, st.GetAll(rule.OrgID), len(expectedEntries))
+		require.Len(t, st.GetStatesForRuleUID(rule.OrgID, rule.UID), len(expectedEntries))
+		require.Nil(t, st.Get(rule.OrgID, rule.UID, expectedEntries[0].CacheID), "shared states should not be evaluated")
+
+		// the states of the rules evaluated by this instance are returned from the cache
+		st.Put([]*state.State{expectedEntries[0]})
+		require.Equal(t, []*state.State{expectedEntries[0]}, st.GetAll(rule.OrgID))
+		require.Equal(t, []*state.State{expectedEntries[0]}, st.GetStatesForRuleUID(rule.OrgID, rule.UID))
+	})
 }
 
 func TestDashboardAnnotations(t *testing.T) {
@@ -1633,6 +1673,58 @@ func TestStaleResults(t *testing.T) {
 	})
 }
 
+func TestSaveStatesPerRule(t *testing.T) {
+	ctx := context.Background()
+	clk := clock.NewMock()
+	store := &state.FakeInstanceStore{}
+
+	cfg := state.ManagerCfg{
+		Metrics:                 metrics.NewNGAlert(prometheus.NewPedanticRegistry()).GetStateMetrics(),
+		InstanceStore:           store,
+		Images:                  &state.NoopImageService{},
+		Clock:                   clk,
+		Historian:               &state.FakeHistorian{},
+		DoNotSaveNormalState:    true,
+		MaxStateSaveConcurrency: 1,
+		SaveStatesPerRule:       true,
+		Tracer:                  tracing.InitializeTracerForTest(),
+		Log:                     log.New("ngalert.state.manager"),
+	}
+	st := state.NewManager(cfg)
+
+	rule := models.AlertRuleGen(models.WithFor(0))()
+	alerting := eval.ResultGen(eval.WithState(eval.Alerting), eval.WithEvaluatedAt(clk.Now()))()
+	normal := eval.ResultGen(eval.WithState(eval.Normal), eval.WithEvaluatedAt(clk.Now()))()
+
+	savedInstances := func(t *testing.T) []models.AlertInstance {
+		t.Helper()
+		require.Len(t, store.RecordedOps, 1)
+		op, ok := store.RecordedOps[0].(state.FakeInstanceStoreOp)
+		require.True(t, ok)
+		require.Equal(t, "SaveAlertInstancesForRule", op.Name)
+		require.Equal(t, rule.GetKey(), op.Args[1])
+		store.RecordedOps = nil
+		return op.Args[2].([]models.AlertInstance)
+	}
+
+	t.Run("should save all the states of the rule at once", func(t *testing.T) {
+		st.ProcessEvalResults(ctx, clk.Now(), rule, eval.Results{alerting, normal}, nil)
+
+		instances := savedInstances(t)
+		require.Len(t, instances, 1, "the normal state should not be saved")
+		require.Equal(t, models.InstanceStateFiring, instances[0].CurrentState)
+		require.Equal(t, rule.UID, instances[0].RuleUID)
+	})
+
+	t.Run("should not save the stale states", func(t *testing.T) {
+		clk.Add(2 * time.Duration(rule.IntervalSeconds) * time.Second)
+		normal.EvaluatedAt = clk.Now()
+		st.ProcessEvalResults(ctx, clk.Now(), rule, eval.Results{normal}, nil)
+
+		require.Empty(t, savedInstances(t))
+	})
+}
+
 func TestDeleteStateByRuleUID(t *testing.T) {
 	interval := time.Minute
 	ctx := context.Background()
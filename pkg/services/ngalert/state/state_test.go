@@ -666,3 +666,24 @@ func TestTakeImage(t *testing.T) {
 		assert.Equal(t, ngmodels.Image{Path: "foo.png"}, *image)
 	})
 }
+
+func TestGetRuleExtraLabels(t *testing.T) {
+	rule := ngmodels.AlertRuleGen()()
+	rule.NotificationSettings = nil
+
+	t.Run("should not add routing labels to rules without notification settings", func(t *testing.T) {
+		labels := GetRuleExtraLabels(rule, "folder", true)
+		require.NotContains(t, labels, ngmodels.AutogeneratedRouteLabel)
+		require.Equal(t, "folder", labels[ngmodels.FolderTitleLabel])
+	})
+
+	t.Run("should add routing labels to rules with notification settings", func(t *testing.T) {
+		settings := ngmodels.NotificationSettings{Receiver: "slack"}
+		rule := ngmodels.CopyRule(rule)
+		rule.NotificationSettings = &settings
+		labels := GetRuleExtraLabels(rule, "folder", true)
+		require.Equal(t, "true", labels[ngmodels.AutogeneratedRouteLabel])
+		require.Equal(t, "slack", labels[ngmodels.AutogeneratedRouteReceiverNameLabel])
+		require.Equal(t, settings.Fingerprint(), labels[ngmodels.AutogeneratedRouteSettingsHashLabel])
+	})
+}
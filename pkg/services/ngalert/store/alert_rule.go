@@ -140,22 +140,27 @@ func (st DBstore) InsertAlertRules(ctx context.Context, rules []ngmodels.AlertRu
 			}
 			newRules = append(newRules, r)
 			ruleVersions = append(ruleVersions, ngmodels.AlertRuleVersion{
-				RuleUID:          r.UID,
-				RuleOrgID:        r.OrgID,
-				RuleNamespaceUID: r.NamespaceUID,
-				RuleGroup:        r.RuleGroup,
-				ParentVersion:    0,
-				Version:          r.Version,
-				Created:          r.Updated,
-				Condition:        r.Condition,
-				Title:            r.Title,
-				Data:             r.Data,
-				IntervalSeconds:  r.IntervalSeconds,
-				NoDataState:      r.NoDataState,
-				ExecErrState:     r.ExecErrState,
-				For:              r.For,
-				Annotations:      r.Annotations,
-				Labels:           r.Labels,
+				RuleUID:              r.UID,
+				RuleOrgID:            r.OrgID,
+				RuleNamespaceUID:     r.NamespaceUID,
+				RuleGroup:            r.RuleGroup,
+				ParentVersion:        0,
+				Version:              r.Version,
+				Created:              r.Updated,
+				Condition:            r.Condition,
+				Title:                r.Title,
+				Data:                 r.Data,
+				IntervalSeconds:      r.IntervalSeconds,
+				NoDataState:          r.NoDataState,
+				ExecErrState:         r.ExecErrState,
+				For:                  r.For,
+				KeepFiringFor:        r.KeepFiringFor,
+				Sequential:           r.Sequential,
+				Annotations:          r.Annotations,
+				Labels:               r.Labels,
+				Record:               r.Record,
+				NotificationSettings: r.NotificationSettings,
+				Template:             r.Template,
 			})
 		}
 		if len(newRules) > 0 {
@@ -215,23 +220,28 @@ func (st DBstore) UpdateAlertRules(ctx context.Context, rules []ngmodels.UpdateR
 			}
 			parentVersion = r.Existing.Version
 			ruleVersions = append(ruleVersions, ngmodels.AlertRuleVersion{
-				RuleOrgID:        r.New.OrgID,
-				RuleUID:          r.New.UID,
-				RuleNamespaceUID: r.New.NamespaceUID,
-				RuleGroup:        r.New.RuleGroup,
-				RuleGroupIndex:   r.New.RuleGroupIndex,
-				ParentVersion:    parentVersion,
-				Version:          r.New.Version + 1,
-				Created:          r.New.Updated,
-				Condition:        r.New.Condition,
-				Title:            r.New.Title,
-				Data:             r.New.Data,
-				IntervalSeconds:  r.New.IntervalSeconds,
-				NoDataState:      r.New.NoDataState,
-				ExecErrState:     r.New.ExecErrState,
-				For:              r.New.For,
-				Annotations:      r.New.Annotations,
-				Labels:           r.New.Labels,
+				RuleOrgID:            r.New.OrgID,
+				RuleUID:              r.New.UID,
+				RuleNamespaceUID:     r.New.NamespaceUID,
+				RuleGroup:            r.New.RuleGroup,
+				RuleGroupIndex:       r.New.RuleGroupIndex,
+				ParentVersion:        parentVersion,
+				Version:              r.New.Version + 1,
+				Created:              r.New.Updated,
+				Condition:            r.New.Condition,
+				Title:                r.New.Title,
+				Data:                 r.New.Data,
+				IntervalSeconds:      r.New.IntervalSeconds,
+				NoDataState:          r.New.NoDataState,
+				ExecErrState:         r.New.ExecErrState,
+				For:                  r.New.For,
+				KeepFiringFor:        r.New.KeepFiringFor,
+				Sequential:           r.New.Sequential,
+				Annotations:          r.New.Annotations,
+				Labels:               r.New.Labels,
+				Record:               r.New.Record,
+				NotificationSettings: r.New.NotificationSettings,
+				Template:             r.New.Template,
 			})
 		}
 		if len(ruleVersions) > 0 {
@@ -425,6 +435,54 @@ func (st DBstore) GetRuleGroupInterval(ctx context.Context, orgID int64, namespa
 	})
 }
 
+// ListNotificationSettings returns the distinct notification settings of the alert rules of the organization.
+func (st DBstore) ListNotificationSettings(ctx context.Context, orgID int64) ([]ngmodels.NotificationSettings, error) {
+	var result []ngmodels.NotificationSettings
+	err := st.SQLStore.WithDbSession(ctx, func(sess *db.Session) error {
+		rules := make([]ngmodels.AlertRule, 0)
+		err := sess.Table(ngmodels.AlertRule{}).
+			Where("org_id = ? AND notification_settings IS NOT NULL", orgID).
+			Cols("notification_settings").
+			Find(&rules)
+		if err != nil {
+			return err
+		}
+		seen := make(map[string]struct{}, len(rules))
+		for _, rule := range rules {
+			if rule.NotificationSettings == nil {
+				continue
+			}
+			fp := rule.NotificationSettings.Fingerprint()
+			if _, ok := seen[fp]; ok {
+				continue
+			}
+			seen[fp] = struct{}{}
+			result = append(result, *rule.NotificationSettings)
+		}
+		return nil
+	})
+	return result, err
+}
+
+// ListAlertRulesByTemplate returns the alert rules of the organization that are instantiated from the alert rule template.
+func (st DBstore) ListAlertRulesByTemplate(ctx context.Context, orgID int64, templateUID string) (ngmodels.RulesGroup, error) {
+	var result ngmodels.RulesGroup
+	err := st.SQLStore.WithDbSession(ctx, func(sess *db.Session) error {
+		rules := make([]*ngmodels.AlertRule, 0)
+		err := sess.Table(ngmodels.AlertRule{}).Where("org_id = ? AND template IS NOT NULL", orgID).Find(&rules)
+		if err != nil {
+			return err
+		}
+		for _, rule := range rules {
+			if rule.Template != nil && rule.Template.UID == templateUID {
+				result = append(result, rule)
+			}
+		}
+		return nil
+	})
+	return result, err
+}
+
 // GetUserVisibleNamespaces returns the folders that are visible to the user and have at least one alert in it
 func (st DBstore) GetUserVisibleNamespaces(ctx context.Context, orgID int64, user *user.SignedInUser) (map[string]*folder.Folder, error) {
 	namespaceMap := make(map[string]*folder.Folder)
@@ -677,5 +735,30 @@ func (st DBstore) validateAlertRule(alertRule ngmodels.AlertRule) error {
 	if alertRule.For < 0 {
 		return fmt.Errorf("%w: field `for` cannot be negative", ngmodels.ErrAlertRuleFailedValidation)
 	}
+
+	if alertRule.KeepFiringFor < 0 {
+		return fmt.Errorf("%w: field `keep_firing_for` cannot be negative", ngmodels.ErrAlertRuleFailedValidation)
+	}
+
+	if alertRule.Record != nil {
+		if err := alertRule.Record.Validate(alertRule.Data); err != nil {
+			return err
+		}
+		if alertRule.For != 0 {
+			return fmt.Errorf("%w: recording rules cannot have field `for`", ngmodels.ErrAlertRuleFailedValidation)
+		}
+		if alertRule.KeepFiringFor != 0 {
+			return fmt.Errorf("%w: recording rules cannot have field `keep_firing_for`", ngmodels.ErrAlertRuleFailedValidation)
+		}
+		if alertRule.NotificationSettings != nil {
+			return fmt.Errorf("%w: recording rules cannot have notification settings", ngmodels.ErrAlertRuleFailedValidation)
+		}
+	}
+
+	if alertRule.NotificationSettings != nil {
+		if err := alertRule.NotificationSettings.Validate(); err != nil {
+			return err
+		}
+	}
 	return nil
 }
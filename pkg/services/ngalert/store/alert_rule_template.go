@@ -0,0 +1,80 @@
+package store
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/grafana/grafana/pkg/infra/db"
+	ngmodels "github.com/grafana/grafana/pkg/services/ngalert/models"
+)
+
+// GetAlertRuleTemplate returns the alert rule template of the organization with the UID.
+// It returns ngmodels.ErrAlertRuleTemplateNotFound if the template does not exist.
+func (st DBstore) GetAlertRuleTemplate(ctx context.Context, orgID int64, uid string) (*ngmodels.AlertRuleTemplate, error) {
+	template := ngmodels.AlertRuleTemplate{OrgID: orgID, UID: uid}
+	err := st.SQLStore.WithDbSession(ctx, func(sess *db.Session) error {
+		has, err := sess.Get(&template)
+		if err != nil {
+			return err
+		}
+		if !has {
+			return ngmodels.ErrAlertRuleTemplateNotFound
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &template, nil
+}
+
+// ListAlertRuleTemplates returns the alert rule templates of the organization ordered by UID.
+func (st DBstore) ListAlertRuleTemplates(ctx context.Context, orgID int64) ([]*ngmodels.AlertRuleTemplate, error) {
+	templates := make([]*ngmodels.AlertRuleTemplate, 0)
+	err := st.SQLStore.WithDbSession(ctx, func(sess *db.Session) error {
+		return sess.Where("org_id = ?", orgID).Asc("uid").Find(&templates)
+	})
+	return templates, err
+}
+
+// InsertAlertRuleTemplate creates the alert rule template and returns its ID.
+func (st DBstore) InsertAlertRuleTemplate(ctx context.Context, template ngmodels.AlertRuleTemplate) (int64, error) {
+	err := st.SQLStore.WithDbSession(ctx, func(sess *db.Session) error {
+		template.ID = 0
+		template.Version = 1
+		template.Updated = TimeNow()
+		if _, err := sess.Insert(&template); err != nil {
+			if st.SQLStore.GetDialect().IsUniqueConstraintViolation(err) {
+				return fmt.Errorf("%w: template with UID '%s' already exists", ngmodels.ErrAlertRuleTemplateFailedValidation, template.UID)
+			}
+			return fmt.Errorf("failed to create alert rule template: %w", err)
+		}
+		return nil
+	})
+	return template.ID, err
+}
+
+// UpdateAlertRuleTemplate updates the alert rule template with the ID and the version of the template.
+// It returns ErrOptimisticLock if the template was changed since that version was read.
+func (st DBstore) UpdateAlertRuleTemplate(ctx context.Context, template ngmodels.AlertRuleTemplate) error {
+	return st.SQLStore.WithDbSession(ctx, func(sess *db.Session) error {
+		template.Updated = TimeNow()
+		// xorm increases the version and checks that the stored version is the one of the template (see https://xorm.io/docs/chapter-06/1.lock/)
+		updated, err := sess.ID(template.ID).AllCols().Update(&template)
+		if err != nil {
+			return fmt.Errorf("failed to update alert rule template %s: %w", template.UID, err)
+		}
+		if updated == 0 {
+			return fmt.Errorf("%w: alert rule template UID %s version %d", ErrOptimisticLock, template.UID, template.Version)
+		}
+		return nil
+	})
+}
+
+// DeleteAlertRuleTemplate deletes the alert rule template of the organization with the UID.
+func (st DBstore) DeleteAlertRuleTemplate(ctx context.Context, orgID int64, uid string) error {
+	return st.SQLStore.WithDbSession(ctx, func(sess *db.Session) error {
+		_, err := sess.Where("org_id = ? AND uid = ?", orgID, uid).Delete(&ngmodels.AlertRuleTemplate{})
+		return err
+	})
+}
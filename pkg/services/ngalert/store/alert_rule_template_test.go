@@ -0,0 +1,110 @@
+package store
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/infra/db"
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/setting"
+)
+
+func TestIntegrationAlertRuleTemplates(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+
+	sqlStore := db.InitTestDB(t)
+	cfg := setting.NewCfg()
+	cfg.UnifiedAlerting.BaseInterval = 1 * time.Second
+	store := &DBstore{
+		SQLStore: sqlStore,
+		Logger:   log.New("test-dbstore"),
+		Cfg:      cfg.UnifiedAlerting,
+	}
+	ctx := context.Background()
+
+	template := models.AlertRuleTemplate{
+		OrgID:       1,
+		UID:         "high-errors",
+		Title:       "High error rate",
+		Condition:   "A",
+		Data:        []models.AlertQuery{{RefID: "A", DatasourceUID: "prometheus", Model: json.RawMessage(`{"expr":"errors{service=\"${service}\"}"}`)}},
+		Annotations: map[string]string{"summary": "${service}"},
+		Parameters:  []models.AlertRuleTemplateParameter{{Name: "service", Type: models.AlertRuleTemplateParameterTypeString}},
+	}
+
+	id, err := store.InsertAlertRuleTemplate(ctx, template)
+	require.NoError(t, err)
+	_, err = store.InsertAlertRuleTemplate(ctx, template)
+	require.ErrorIs(t, err, models.ErrAlertRuleTemplateFailedValidation, "the UID should be unique in the organization")
+	other := template
+	other.OrgID = 2
+	_, err = store.InsertAlertRuleTemplate(ctx, other)
+	require.NoError(t, err)
+
+	t.Run("should get the template", func(t *testing.T) {
+		stored, err := store.GetAlertRuleTemplate(ctx, 1, template.UID)
+		require.NoError(t, err)
+		require.Equal(t, id, stored.ID)
+		require.Equal(t, int64(1), stored.Version)
+		require.Equal(t, template.Parameters, stored.Parameters)
+		require.Equal(t, template.Annotations, stored.Annotations)
+		require.JSONEq(t, string(template.Data[0].Model), string(stored.Data[0].Model))
+
+		_, err = store.GetAlertRuleTemplate(ctx, 3, template.UID)
+		require.ErrorIs(t, err, models.ErrAlertRuleTemplateNotFound)
+	})
+
+	t.Run("should update the template with optimistic locking", func(t *testing.T) {
+		stored, err := store.GetAlertRuleTemplate(ctx, 1, template.UID)
+		require.NoError(t, err)
+		stored.Title = "Very high error rate"
+		require.NoError(t, store.UpdateAlertRuleTemplate(ctx, *stored))
+		require.ErrorIs(t, store.UpdateAlertRuleTemplate(ctx, *stored), ErrOptimisticLock)
+
+		updated, err := store.GetAlertRuleTemplate(ctx, 1, template.UID)
+		require.NoError(t, err)
+		require.Equal(t, "Very high error rate", updated.Title)
+		require.Equal(t, int64(2), updated.Version)
+	})
+
+	t.Run("should list the rules instantiated from the template", func(t *testing.T) {
+		gen := models.AlertRuleGen(models.WithOrgID(1), withIntervalMatching(store.Cfg.BaseInterval))
+		rules := []models.AlertRule{
+			*gen(),
+			*models.AlertRuleGen(models.WithOrgID(1), withIntervalMatching(store.Cfg.BaseInterval), models.WithTemplate(template.UID, map[string]string{"service": "api"}))(),
+			*models.AlertRuleGen(models.WithOrgID(1), withIntervalMatching(store.Cfg.BaseInterval), models.WithTemplate("other", nil))(),
+		}
+		for i := range rules {
+			rules[i].Record = nil
+		}
+		_, err := store.InsertAlertRules(ctx, rules)
+		require.NoError(t, err)
+
+		result, err := store.ListAlertRulesByTemplate(ctx, 1, template.UID)
+		require.NoError(t, err)
+		require.Len(t, result, 1)
+		require.Equal(t, rules[1].UID, result[0].UID)
+		require.Equal(t, rules[1].Template, result[0].Template)
+	})
+
+	t.Run("should list and delete the templates of the organization", func(t *testing.T) {
+		templates, err := store.ListAlertRuleTemplates(ctx, 1)
+		require.NoError(t, err)
+		require.Len(t, templates, 1)
+
+		require.NoError(t, store.DeleteAlertRuleTemplate(ctx, 1, template.UID))
+		templates, err = store.ListAlertRuleTemplates(ctx, 1)
+		require.NoError(t, err)
+		require.Empty(t, templates)
+		templates, err = store.ListAlertRuleTemplates(ctx, 2)
+		require.NoError(t, err)
+		require.Len(t, templates, 1)
+	})
+}
@@ -558,6 +558,68 @@ func TestIntegrationInsertAlertRules(t *testing.T) {
 	}
 }
 
+func TestIntegrationAlertRuleRecord(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+
+	sqlStore := db.InitTestDB(t)
+	cfg := setting.NewCfg()
+	cfg.UnifiedAlerting.BaseInterval = 1 * time.Second
+	store := &DBstore{
+		SQLStore:      sqlStore,
+		FolderService: setupFolderService(t, sqlStore, cfg),
+		Logger:        log.New("test-dbstore"),
+		Cfg:           cfg.UnifiedAlerting,
+	}
+
+	gen := models.AlertRuleGen(models.WithOrgID(1), withIntervalMatching(store.Cfg.BaseInterval))
+	recording := gen()
+	models.WithRecord("test_metric")(recording)
+	alerting := gen()
+	alerting.Record = nil
+
+	ids, err := store.InsertAlertRules(context.Background(), []models.AlertRule{*recording, *alerting})
+	require.NoError(t, err)
+	require.Len(t, ids, 2)
+
+	get := func(uid string) *models.AlertRule {
+		t.Helper()
+		rule, err := store.GetAlertRuleByUID(context.Background(), &models.GetAlertRuleByUIDQuery{OrgID: 1, UID: uid})
+		require.NoError(t, err)
+		return rule
+	}
+
+	t.Run("should store the record of recording rules", func(t *testing.T) {
+		rule := get(recording.UID)
+		require.Equal(t, recording.Record, rule.Record)
+		require.Equal(t, models.RuleTypeRecording, rule.Type())
+	})
+
+	t.Run("should not set a record for alerting rules", func(t *testing.T) {
+		rule := get(alerting.UID)
+		require.Nil(t, rule.Record)
+		require.Equal(t, models.RuleTypeAlerting, rule.Type())
+	})
+
+	t.Run("should update the record", func(t *testing.T) {
+		existing := get(recording.UID)
+		updated := models.CopyRule(existing)
+		updated.Record.Metric = "updated_metric"
+		err := store.UpdateAlertRules(context.Background(), []models.UpdateRule{{Existing: existing, New: *updated}})
+		require.NoError(t, err)
+		require.Equal(t, "updated_metric", get(recording.UID).Record.Metric)
+	})
+
+	t.Run("should reject invalid records", func(t *testing.T) {
+		rule := gen()
+		rule.Record = &models.Record{Metric: "invalid metric", From: rule.Data[0].RefID}
+		rule.For = 0
+		_, err := store.InsertAlertRules(context.Background(), []models.AlertRule{*rule})
+		require.ErrorIs(t, err, models.ErrAlertRuleFailedValidation)
+	})
+}
+
 func createRule(t *testing.T, store *DBstore, generate func() *models.AlertRule) *models.AlertRule {
 	t.Helper()
 	if generate == nil {
@@ -616,3 +678,60 @@ func setupFolderService(t *testing.T, sqlStore *sqlstore.SQLStore, cfg *setting.
 
 	return testutil.SetupFolderService(t, cfg, sqlStore, dashboardStore, folderStore, inProcBus)
 }
+
+func TestIntegrationListNotificationSettings(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+
+	sqlStore := db.InitTestDB(t)
+	cfg := setting.NewCfg()
+	cfg.UnifiedAlerting.BaseInterval = 1 * time.Second
+	store := &DBstore{
+		SQLStore:      sqlStore,
+		FolderService: setupFolderService(t, sqlStore, cfg),
+		Logger:        log.New("test-dbstore"),
+		Cfg:           cfg.UnifiedAlerting,
+	}
+
+	slack := models.NotificationSettings{Receiver: "slack", GroupBy: []string{"team"}}
+	email := models.NotificationSettings{Receiver: "email"}
+	gen := models.AlertRuleGen(models.WithOrgID(1), withIntervalMatching(store.Cfg.BaseInterval))
+	rules := []models.AlertRule{
+		*gen(),
+		*models.AlertRuleGen(models.WithOrgID(1), withIntervalMatching(store.Cfg.BaseInterval), models.WithNotificationSettings(slack))(),
+		*models.AlertRuleGen(models.WithOrgID(1), withIntervalMatching(store.Cfg.BaseInterval), models.WithNotificationSettings(slack))(),
+		*models.AlertRuleGen(models.WithOrgID(1), withIntervalMatching(store.Cfg.BaseInterval), models.WithNotificationSettings(email))(),
+		*models.AlertRuleGen(models.WithOrgID(2), withIntervalMatching(store.Cfg.BaseInterval), models.WithNotificationSettings(email))(),
+	}
+	for i := range rules {
+		rules[i].Record = nil
+	}
+	rules[0].NotificationSettings = nil
+	_, err := store.InsertAlertRules(context.Background(), rules)
+	require.NoError(t, err)
+
+	t.Run("should return the distinct settings of the organization", func(t *testing.T) {
+		settings, err := store.ListNotificationSettings(context.Background(), 1)
+		require.NoError(t, err)
+		require.ElementsMatch(t, []models.NotificationSettings{slack, email}, settings)
+	})
+
+	t.Run("should store the settings of the rules", func(t *testing.T) {
+		rule, err := store.GetAlertRuleByUID(context.Background(), &models.GetAlertRuleByUIDQuery{OrgID: 1, UID: rules[1].UID})
+		require.NoError(t, err)
+		require.Equal(t, &slack, rule.NotificationSettings)
+
+		rule, err = store.GetAlertRuleByUID(context.Background(), &models.GetAlertRuleByUIDQuery{OrgID: 1, UID: rules[0].UID})
+		require.NoError(t, err)
+		require.Nil(t, rule.NotificationSettings)
+	})
+
+	t.Run("should reject settings without a receiver", func(t *testing.T) {
+		rule := gen()
+		rule.Record = nil
+		rule.NotificationSettings = &models.NotificationSettings{GroupBy: []string{"team"}}
+		_, err := store.InsertAlertRules(context.Background(), []models.AlertRule{*rule})
+		require.ErrorIs(t, err, models.ErrAlertRuleFailedValidation)
+	})
+}
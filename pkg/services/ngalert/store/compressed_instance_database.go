@@ -0,0 +1,329 @@
+package store
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/golang/snappy"
+
+	"github.com/grafana/grafana/pkg/infra/db"
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/services/featuremgmt"
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/services/sqlstore/migrator"
+)
+
+// CompressedInstanceStore is an instance store that stores all the alert instances of a rule in a single row of the
+// alert_rule_state table, as snappy-compressed JSON. Saving the states of a rule after an evaluation writes a single
+// row, however many alert instances the rule has.
+type CompressedInstanceStore struct {
+	SQLStore       db.DB
+	FeatureToggles featuremgmt.FeatureToggles
+	Logger         log.Logger
+}
+
+// ruleState is a row of the alert_rule_state table.
+type ruleState struct {
+	ID      int64  `xorm:"pk autoincr 'id'"`
+	OrgID   int64  `xorm:"org_id"`
+	RuleUID string `xorm:"rule_uid"`
+	Data    []byte `xorm:"data"`
+	// UpdatedAt is when the row was last written, in Unix milliseconds.
+	UpdatedAt int64 `xorm:"updated_at"`
+}
+
+func (ruleState) TableName() string {
+	return "alert_rule_state"
+}
+
+// compressedInstance is an alert instance in the data of the alert_rule_state table.
+// The times are in Unix milliseconds, zero times are stored as 0.
+type compressedInstance struct {
+	Labels          models.InstanceLabels    `json:"labels"`
+	LabelsHash      string                   `json:"labelsHash"`
+	State           models.InstanceStateType `json:"state"`
+	Reason          string                   `json:"reason,omitempty"`
+	StateSince      int64                    `json:"stateSince"`
+	StateEnd        int64                    `json:"stateEnd"`
+	LastEvalTime    int64                    `json:"lastEvalTime"`
+	KeepFiringSince int64                    `json:"keepFiringSince,omitempty"`
+}
+
+// ListAlertInstances returns the alert instances of the organization, and of the rule if the query has a rule UID.
+func (st CompressedInstanceStore) ListAlertInstances(ctx context.Context, cmd *models.ListAlertInstancesQuery) (result []*models.AlertInstance, err error) {
+	err = st.SQLStore.WithDbSession(ctx, func(sess *db.Session) error {
+		q := sess.Where("org_id = ?", cmd.RuleOrgID)
+		if cmd.RuleUID != "" {
+			q = q.And("rule_uid = ?", cmd.RuleUID)
+		}
+		var rows []ruleState
+		if err := q.Find(&rows); err != nil {
+			return err
+		}
+
+		skipNormal := st.FeatureToggles.IsEnabled(featuremgmt.FlagAlertingNoNormalState)
+		result = make([]*models.AlertInstance, 0, len(rows))
+		for _, row := range rows {
+			instances, err := decodeRuleState(row)
+			if err != nil {
+				return err
+			}
+			for _, instance := range instances {
+				if skipNormal && instance.CurrentState == models.InstanceStateNormal && instance.CurrentReason == "" {
+					continue
+				}
+				result = append(result, instance)
+			}
+		}
+		return nil
+	})
+	return result, err
+}
+
+// SaveAlertInstance adds the alert instance to the alert instances of its rule, or replaces the alert instance of
+// the rule with the same labels.
+func (st CompressedInstanceStore) SaveAlertInstance(ctx context.Context, alertInstance models.AlertInstance) error {
+	if err := models.ValidateAlertInstance(alertInstance); err != nil {
+		return err
+	}
+	return st.SQLStore.WithTransactionalDbSession(ctx, func(sess *db.Session) error {
+		current, err := getRuleInstances(sess, alertInstance.RuleOrgID, alertInstance.RuleUID)
+		if err != nil {
+			return err
+		}
+		instances := make([]models.AlertInstance, 0, len(current)+1)
+		for _, instance := range current {
+			if instance.LabelsHash != alertInstance.LabelsHash {
+				instances = append(instances, *instance)
+			}
+		}
+		instances = append(instances, alertInstance)
+		return saveRuleInstances(sess, st.SQLStore.GetDialect(), alertInstance.RuleOrgID, alertInstance.RuleUID, instances)
+	})
+}
+
+// SaveAlertInstancesForRule replaces all the alert instances of the rule with a single write.
+func (st CompressedInstanceStore) SaveAlertInstancesForRule(ctx context.Context, key models.AlertRuleKey, instances []models.AlertInstance) error {
+	for _, instance := range instances {
+		if err := models.ValidateAlertInstance(instance); err != nil {
+			return err
+		}
+	}
+	return st.SQLStore.WithDbSession(ctx, func(sess *db.Session) error {
+		return saveRuleInstances(sess, st.SQLStore.GetDialect(), key.OrgID, key.UID, instances)
+	})
+}
+
+func (st CompressedInstanceStore) FetchOrgIds(ctx context.Context) ([]int64, error) {
+	orgIds := []int64{}
+	err := st.SQLStore.WithDbSession(ctx, func(sess *db.Session) error {
+		return sess.SQL("SELECT DISTINCT org_id FROM alert_rule_state").Find(&orgIds)
+	})
+	return orgIds, err
+}
+
+// DeleteAlertInstances deletes the alert instances with the provided keys in a single transaction.
+func (st CompressedInstanceStore) DeleteAlertInstances(ctx context.Context, keys ...models.AlertInstanceKey) error {
+	if len(keys) == 0 {
+		return nil
+	}
+
+	toDelete := make(map[models.AlertRuleKey]map[string]struct{})
+	for _, k := range keys {
+		ruleKey := models.AlertRuleKey{OrgID: k.RuleOrgID, UID: k.RuleUID}
+		if _, ok := toDelete[ruleKey]; !ok {
+			toDelete[ruleKey] = make(map[string]struct{})
+		}
+		toDelete[ruleKey][k.LabelsHash] = struct{}{}
+	}
+
+	return st.SQLStore.WithTransactionalDbSession(ctx, func(sess *db.Session) error {
+		for ruleKey, hashes := range toDelete {
+			current, err := getRuleInstances(sess, ruleKey.OrgID, ruleKey.UID)
+			if err != nil {
+				return err
+			}
+			instances := make([]models.AlertInstance, 0, len(current))
+			for _, instance := range current {
+				if _, ok := hashes[instance.LabelsHash]; !ok {
+					instances = append(instances, *instance)
+				}
+			}
+			if len(instances) == len(current) {
+				continue
+			}
+			if err := saveRuleInstances(sess, st.SQLStore.GetDialect(), ruleKey.OrgID, ruleKey.UID, instances); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+func (st CompressedInstanceStore) DeleteAlertInstancesByRule(ctx context.Context, key models.AlertRuleKey) error {
+	return st.SQLStore.WithDbSession(ctx, func(sess *db.Session) error {
+		_, err := sess.Exec("DELETE FROM alert_rule_state WHERE org_id = ? AND rule_uid = ?", key.OrgID, key.UID)
+		return err
+	})
+}
+
+// MigrateFromAlertInstances moves the alert instances stored in the alert_instance table to this store.
+// The alert instances replace the alert instances of their rules in this store.
+func (st CompressedInstanceStore) MigrateFromAlertInstances(ctx context.Context) error {
+	return st.SQLStore.WithTransactionalDbSession(ctx, func(sess *db.Session) error {
+		var rows []*models.AlertInstance
+		if err := sess.SQL("SELECT * FROM alert_instance").Find(&rows); err != nil {
+			return err
+		}
+		if len(rows) == 0 {
+			return nil
+		}
+
+		byRule := make(map[models.AlertRuleKey][]models.AlertInstance)
+		for _, instance := range rows {
+			key := models.AlertRuleKey{OrgID: instance.RuleOrgID, UID: instance.RuleUID}
+			byRule[key] = append(byRule[key], *instance)
+		}
+		for key, instances := range byRule {
+			if err := saveRuleInstances(sess, st.SQLStore.GetDialect(), key.OrgID, key.UID, instances); err != nil {
+				return fmt.Errorf("failed to migrate the alert instances of rule %s: %w", key.UID, err)
+			}
+		}
+		if _, err := sess.Exec("DELETE FROM alert_instance"); err != nil {
+			return err
+		}
+		st.Logger.Info("Migrated alert instances to the compressed state store", "instances", len(rows), "rules", len(byRule))
+		return nil
+	})
+}
+
+// MigrateToAlertInstances moves the alert instances stored in this store to the alert_instance table.
+// The alert instances replace the alert instances of their rules in the alert_instance table.
+func (st CompressedInstanceStore) MigrateToAlertInstances(ctx context.Context) error {
+	return st.SQLStore.WithTransactionalDbSession(ctx, func(sess *db.Session) error {
+		var rows []ruleState
+		if err := sess.Find(&rows); err != nil {
+			return err
+		}
+		if len(rows) == 0 {
+			return nil
+		}
+
+		count := 0
+		for _, row := range rows {
+			instances, err := decodeRuleState(row)
+			if err != nil {
+				return err
+			}
+			if _, err := sess.Exec("DELETE FROM alert_instance WHERE rule_org_id = ? AND rule_uid = ?", row.OrgID, row.RuleUID); err != nil {
+				return err
+			}
+			for _, instance := range instances {
+				if err := upsertAlertInstance(sess, st.SQLStore.GetDialect(), *instance); err != nil {
+					return fmt.Errorf("failed to migrate the alert instances of rule %s: %w", row.RuleUID, err)
+				}
+			}
+			count += len(instances)
+		}
+		if _, err := sess.Exec("DELETE FROM alert_rule_state"); err != nil {
+			return err
+		}
+		st.Logger.Info("Migrated alert instances from the compressed state store", "instances", count, "rules", len(rows))
+		return nil
+	})
+}
+
+func getRuleInstances(sess *db.Session, orgID int64, ruleUID string) ([]*models.AlertInstance, error) {
+	row := ruleState{}
+	has, err := sess.Where("org_id = ? AND rule_uid = ?", orgID, ruleUID).Get(&row)
+	if err != nil || !has {
+		return nil, err
+	}
+	return decodeRuleState(row)
+}
+
+// saveRuleInstances replaces the alert instances of the rule. The row of the rule is deleted if there are no alert instances.
+func saveRuleInstances(sess *db.Session, dialect migrator.Dialect, orgID int64, ruleUID string, instances []models.AlertInstance) error {
+	if len(instances) == 0 {
+		_, err := sess.Exec("DELETE FROM alert_rule_state WHERE org_id = ? AND rule_uid = ?", orgID, ruleUID)
+		return err
+	}
+
+	data, err := encodeInstances(instances)
+	if err != nil {
+		return err
+	}
+	upsertSQL := dialect.UpsertSQL(
+		"alert_rule_state",
+		[]string{"org_id", "rule_uid"},
+		[]string{"org_id", "rule_uid", "data", "updated_at"})
+	_, err = sess.Exec(upsertSQL, orgID, ruleUID, data, time.Now().UnixMilli())
+	return err
+}
+
+func encodeInstances(instances []models.AlertInstance) ([]byte, error) {
+	compressed := make([]compressedInstance, 0, len(instances))
+	for _, instance := range instances {
+		compressed = append(compressed, compressedInstance{
+			Labels:          instance.Labels,
+			LabelsHash:      instance.LabelsHash,
+			State:           instance.CurrentState,
+			Reason:          instance.CurrentReason,
+			StateSince:      toUnixMilli(instance.CurrentStateSince),
+			StateEnd:        toUnixMilli(instance.CurrentStateEnd),
+			LastEvalTime:    toUnixMilli(instance.LastEvalTime),
+			KeepFiringSince: toUnixMilli(instance.KeepFiringSince),
+		})
+	}
+	b, err := json.Marshal(compressed)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode alert instances: %w", err)
+	}
+	return snappy.Encode(nil, b), nil
+}
+
+func decodeRuleState(row ruleState) ([]*models.AlertInstance, error) {
+	b, err := snappy.Decode(nil, row.Data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decompress the alert instances of rule %s: %w", row.RuleUID, err)
+	}
+	var compressed []compressedInstance
+	if err := json.Unmarshal(b, &compressed); err != nil {
+		return nil, fmt.Errorf("failed to decode the alert instances of rule %s: %w", row.RuleUID, err)
+	}
+
+	instances := make([]*models.AlertInstance, 0, len(compressed))
+	for _, c := range compressed {
+		instances = append(instances, &models.AlertInstance{
+			AlertInstanceKey: models.AlertInstanceKey{
+				RuleOrgID:  row.OrgID,
+				RuleUID:    row.RuleUID,
+				LabelsHash: c.LabelsHash,
+			},
+			Labels:            c.Labels,
+			CurrentState:      c.State,
+			CurrentReason:     c.Reason,
+			CurrentStateSince: fromUnixMilli(c.StateSince),
+			CurrentStateEnd:   fromUnixMilli(c.StateEnd),
+			LastEvalTime:      fromUnixMilli(c.LastEvalTime),
+			KeepFiringSince:   fromUnixMilli(c.KeepFiringSince),
+		})
+	}
+	return instances, nil
+}
+
+func toUnixMilli(t time.Time) int64 {
+	if t.IsZero() {
+		return 0
+	}
+	return t.UnixMilli()
+}
+
+func fromUnixMilli(ms int64) time.Time {
+	if ms == 0 {
+		return time.Time{}
+	}
+	return time.UnixMilli(ms)
+}
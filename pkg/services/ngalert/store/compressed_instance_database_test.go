@@ -0,0 +1,132 @@
+package store_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/services/ngalert/store"
+	"github.com/grafana/grafana/pkg/services/ngalert/tests"
+)
+
+func TestIntegrationCompressedInstanceStore(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+	ctx := context.Background()
+	_, dbstore := tests.SetupTestEnv(t, baseIntervalSeconds)
+	compressed := store.CompressedInstanceStore{
+		SQLStore:       dbstore.SQLStore,
+		FeatureToggles: dbstore.FeatureToggles,
+		Logger:         log.NewNopLogger(),
+	}
+
+	const mainOrgID int64 = 1
+	rule1 := tests.CreateTestAlertRule(t, ctx, dbstore, 60, mainOrgID)
+	rule2 := tests.CreateTestAlertRule(t, ctx, dbstore, 60, mainOrgID)
+
+	// the alert_instance table stores the times in seconds
+	now := time.Unix(time.Now().Unix(), 0)
+	newInstance := func(rule *models.AlertRule, i int, state models.InstanceStateType) models.AlertInstance {
+		labels := models.InstanceLabels{"test": fmt.Sprint(i)}
+		_, hash, err := labels.StringAndHash()
+		require.NoError(t, err)
+		return models.AlertInstance{
+			AlertInstanceKey: models.AlertInstanceKey{
+				RuleOrgID:  rule.OrgID,
+				RuleUID:    rule.UID,
+				LabelsHash: hash,
+			},
+			Labels:            labels,
+			CurrentState:      state,
+			CurrentStateSince: now.Add(-time.Minute),
+			CurrentStateEnd:   now.Add(time.Minute),
+			LastEvalTime:      now,
+		}
+	}
+	listRule := func(t *testing.T, rule *models.AlertRule) []models.AlertInstance {
+		t.Helper()
+		result, err := compressed.ListAlertInstances(ctx, &models.ListAlertInstancesQuery{RuleOrgID: rule.OrgID, RuleUID: rule.UID})
+		require.NoError(t, err)
+		instances := make([]models.AlertInstance, 0, len(result))
+		for _, i := range result {
+			instances = append(instances, *i)
+		}
+		return instances
+	}
+
+	t.Run("saves and lists all the alert instances of a rule", func(t *testing.T) {
+		instances := make([]models.AlertInstance, 0, 1000)
+		for i := 0; i < 1000; i++ {
+			instances = append(instances, newInstance(rule1, i, models.InstanceStateFiring))
+		}
+		instances[0].CurrentReason = models.StateReasonMissingSeries
+		instances[1].KeepFiringSince = now
+
+		require.NoError(t, compressed.SaveAlertInstancesForRule(ctx, rule1.GetKey(), instances))
+		require.Equal(t, instances, listRule(t, rule1))
+		require.Empty(t, listRule(t, rule2))
+
+		orgIDs, err := compressed.FetchOrgIds(ctx)
+		require.NoError(t, err)
+		require.Equal(t, []int64{mainOrgID}, orgIDs)
+
+		replaced := []models.AlertInstance{newInstance(rule1, 0, models.InstanceStateNormal)}
+		require.NoError(t, compressed.SaveAlertInstancesForRule(ctx, rule1.GetKey(), replaced))
+		require.Equal(t, replaced, listRule(t, rule1))
+	})
+
+	t.Run("saves and deletes single alert instances", func(t *testing.T) {
+		i1 := newInstance(rule2, 1, models.InstanceStateFiring)
+		i2 := newInstance(rule2, 2, models.InstanceStatePending)
+		require.NoError(t, compressed.SaveAlertInstance(ctx, i1))
+		require.NoError(t, compressed.SaveAlertInstance(ctx, i2))
+		i1.CurrentState = models.InstanceStateNormal
+		require.NoError(t, compressed.SaveAlertInstance(ctx, i1))
+		require.ElementsMatch(t, []models.AlertInstance{i1, i2}, listRule(t, rule2))
+
+		require.NoError(t, compressed.DeleteAlertInstances(ctx, i1.AlertInstanceKey))
+		require.Equal(t, []models.AlertInstance{i2}, listRule(t, rule2))
+
+		require.NoError(t, compressed.DeleteAlertInstancesByRule(ctx, rule2.GetKey()))
+		require.Empty(t, listRule(t, rule2))
+	})
+
+	t.Run("deletes the row of the rule without alert instances", func(t *testing.T) {
+		require.NoError(t, compressed.SaveAlertInstancesForRule(ctx, rule1.GetKey(), nil))
+		orgIDs, err := compressed.FetchOrgIds(ctx)
+		require.NoError(t, err)
+		require.Empty(t, orgIDs)
+	})
+
+	t.Run("migrates the alert instances between the stores", func(t *testing.T) {
+		i1 := newInstance(rule1, 1, models.InstanceStateFiring)
+		i2 := newInstance(rule2, 2, models.InstanceStateNoData)
+		require.NoError(t, dbstore.SaveAlertInstancesForRule(ctx, rule1.GetKey(), []models.AlertInstance{i1}))
+		require.NoError(t, dbstore.SaveAlertInstance(ctx, i2))
+
+		require.NoError(t, compressed.MigrateFromAlertInstances(ctx))
+		require.Equal(t, []models.AlertInstance{i1}, listRule(t, rule1))
+		require.Equal(t, []models.AlertInstance{i2}, listRule(t, rule2))
+		rows, err := dbstore.ListAlertInstances(ctx, &models.ListAlertInstancesQuery{RuleOrgID: mainOrgID})
+		require.NoError(t, err)
+		require.Empty(t, rows)
+
+		require.NoError(t, compressed.MigrateToAlertInstances(ctx))
+		require.Empty(t, listRule(t, rule1))
+		rows, err = dbstore.ListAlertInstances(ctx, &models.ListAlertInstancesQuery{RuleOrgID: mainOrgID, RuleUID: rule1.UID})
+		require.NoError(t, err)
+		require.Len(t, rows, 1)
+		require.Equal(t, i1.AlertInstanceKey, rows[0].AlertInstanceKey)
+		require.Equal(t, i1.Labels, rows[0].Labels)
+		require.Equal(t, i1.CurrentState, rows[0].CurrentState)
+		require.True(t, i1.CurrentStateSince.Equal(rows[0].CurrentStateSince))
+		require.True(t, i1.LastEvalTime.Equal(rows[0].LastEvalTime))
+		require.True(t, rows[0].KeepFiringSince.IsZero())
+	})
+}
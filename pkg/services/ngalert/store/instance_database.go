@@ -9,6 +9,7 @@ import (
 	"github.com/grafana/grafana/pkg/infra/db"
 	"github.com/grafana/grafana/pkg/services/featuremgmt"
 	"github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/services/sqlstore/migrator"
 )
 
 // ListAlertInstances is a handler for retrieving alert instances within specific organisation
@@ -46,29 +47,53 @@ func (st DBstore) ListAlertInstances(ctx context.Context, cmd *models.ListAlertI
 // SaveAlertInstance is a handler for saving a new alert instance.
 func (st DBstore) SaveAlertInstance(ctx context.Context, alertInstance models.AlertInstance) error {
 	return st.SQLStore.WithDbSession(ctx, func(sess *db.Session) error {
-		if err := models.ValidateAlertInstance(alertInstance); err != nil {
-			return err
-		}
+		return upsertAlertInstance(sess, st.SQLStore.GetDialect(), alertInstance)
+	})
+}
 
-		labelTupleJSON, err := alertInstance.Labels.StringKey()
-		if err != nil {
+// SaveAlertInstancesForRule replaces all the alert instances of the rule in a single transaction.
+func (st DBstore) SaveAlertInstancesForRule(ctx context.Context, key models.AlertRuleKey, instances []models.AlertInstance) error {
+	return st.SQLStore.WithTransactionalDbSession(ctx, func(sess *db.Session) error {
+		if _, err := sess.Exec("DELETE FROM alert_instance WHERE rule_org_id = ? AND rule_uid = ?", key.OrgID, key.UID); err != nil {
 			return err
 		}
-		params := append(make([]any, 0), alertInstance.RuleOrgID, alertInstance.RuleUID, labelTupleJSON, alertInstance.LabelsHash, alertInstance.CurrentState, alertInstance.CurrentReason, alertInstance.CurrentStateSince.Unix(), alertInstance.CurrentStateEnd.Unix(), alertInstance.LastEvalTime.Unix())
-
-		upsertSQL := st.SQLStore.GetDialect().UpsertSQL(
-			"alert_instance",
-			[]string{"rule_org_id", "rule_uid", "labels_hash"},
-			[]string{"rule_org_id", "rule_uid", "labels", "labels_hash", "current_state", "current_reason", "current_state_since", "current_state_end", "last_eval_time"})
-		_, err = sess.SQL(upsertSQL, params...).Query()
-		if err != nil {
-			return err
+		for _, instance := range instances {
+			if err := upsertAlertInstance(sess, st.SQLStore.GetDialect(), instance); err != nil {
+				return err
+			}
 		}
-
 		return nil
 	})
 }
 
+func upsertAlertInstance(sess *db.Session, dialect migrator.Dialect, alertInstance models.AlertInstance) error {
+	if err := models.ValidateAlertInstance(alertInstance); err != nil {
+		return err
+	}
+
+	labelTupleJSON, err := alertInstance.Labels.StringKey()
+	if err != nil {
+		return err
+	}
+	// zero when the alert doesn't keep firing
+	var keepFiringSince int64
+	if !alertInstance.KeepFiringSince.IsZero() {
+		keepFiringSince = alertInstance.KeepFiringSince.Unix()
+	}
+	params := append(make([]any, 0), alertInstance.RuleOrgID, alertInstance.RuleUID, labelTupleJSON, alertInstance.LabelsHash, alertInstance.CurrentState, alertInstance.CurrentReason, alertInstance.CurrentStateSince.Unix(), alertInstance.CurrentStateEnd.Unix(), alertInstance.LastEvalTime.Unix(), keepFiringSince)
+
+	upsertSQL := dialect.UpsertSQL(
+		"alert_instance",
+		[]string{"rule_org_id", "rule_uid", "labels_hash"},
+		[]string{"rule_org_id", "rule_uid", "labels", "labels_hash", "current_state", "current_reason", "current_state_since", "current_state_end", "last_eval_time", "keep_firing_since"})
+	_, err = sess.SQL(upsertSQL, params...).Query()
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
 func (st DBstore) FetchOrgIds(ctx context.Context) ([]int64, error) {
 	orgIds := []int64{}
 
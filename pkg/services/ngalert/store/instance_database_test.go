@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/require"
 
@@ -144,6 +145,44 @@ func TestIntegrationAlertInstanceOperations(t *testing.T) {
 		require.Equal(t, instance.Labels, alerts[0].Labels)
 	})
 
+	t.Run("can save and read the time since an alert keeps firing", func(t *testing.T) {
+		alertRule := tests.CreateTestAlertRule(t, ctx, dbstore, 60, mainOrgID)
+		keepFiring := models.InstanceLabels{"test": "keepFiring"}
+		_, keepFiringHash, _ := keepFiring.StringAndHash()
+		firing := models.InstanceLabels{"test": "firing"}
+		_, firingHash, _ := firing.StringAndHash()
+
+		since := time.Unix(time.Now().Unix(), 0)
+		for _, instance := range []models.AlertInstance{
+			{
+				AlertInstanceKey: models.AlertInstanceKey{RuleOrgID: alertRule.OrgID, RuleUID: alertRule.UID, LabelsHash: keepFiringHash},
+				CurrentState:     models.InstanceStateFiring,
+				Labels:           keepFiring,
+				KeepFiringSince:  since,
+			},
+			{
+				AlertInstanceKey: models.AlertInstanceKey{RuleOrgID: alertRule.OrgID, RuleUID: alertRule.UID, LabelsHash: firingHash},
+				CurrentState:     models.InstanceStateFiring,
+				Labels:           firing,
+			},
+		} {
+			require.NoError(t, dbstore.SaveAlertInstance(ctx, instance))
+		}
+
+		alerts, err := dbstore.ListAlertInstances(ctx, &models.ListAlertInstancesQuery{RuleOrgID: alertRule.OrgID, RuleUID: alertRule.UID})
+		require.NoError(t, err)
+		require.Len(t, alerts, 2)
+		for _, alert := range alerts {
+			if alert.LabelsHash == keepFiringHash {
+				require.True(t, since.Equal(alert.KeepFiringSince))
+			} else {
+				require.True(t, alert.KeepFiringSince.IsZero())
+			}
+		}
+
+		require.NoError(t, dbstore.DeleteAlertInstancesByRule(ctx, alertRule.GetKey()))
+	})
+
 	t.Run("can save two instances with same org_id, uid and different labels", func(t *testing.T) {
 		labels := models.InstanceLabels{"test": "testValue"}
 		_, hash, _ := labels.StringAndHash()
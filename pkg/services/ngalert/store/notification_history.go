@@ -0,0 +1,116 @@
+package store
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/grafana/grafana/pkg/infra/db"
+	ngmodels "github.com/grafana/grafana/pkg/services/ngalert/models"
+)
+
+type notificationHistoryEntry struct {
+	ID               int64                               `xorm:"pk autoincr 'id'"`
+	OrgID            int64                               `xorm:"org_id"`
+	Receiver         string                              `xorm:"receiver"`
+	Integration      string                              `xorm:"integration"`
+	IntegrationIndex int                                 `xorm:"integration_index"`
+	GroupKey         string                              `xorm:"group_key"`
+	Alerts           []ngmodels.NotificationHistoryAlert `xorm:"alerts"`
+	Status           string                              `xorm:"status"`
+	Error            string                              `xorm:"error"`
+	Retry            bool                                `xorm:"retry"`
+	Payload          string                              `xorm:"payload"`
+	// SentAt is a unix timestamp in milliseconds so that the retention policies can remove old entries.
+	SentAt   int64 `xorm:"sent_at"`
+	Duration int64 `xorm:"duration"`
+}
+
+func (notificationHistoryEntry) TableName() string {
+	return "alert_notification_history"
+}
+
+// SaveNotificationHistory stores the entries of the notification history.
+func (st DBstore) SaveNotificationHistory(ctx context.Context, entries []ngmodels.NotificationHistoryEntry) error {
+	if len(entries) == 0 {
+		return nil
+	}
+	rows := make([]notificationHistoryEntry, 0, len(entries))
+	for _, e := range entries {
+		rows = append(rows, notificationHistoryEntry{
+			OrgID:            e.OrgID,
+			Receiver:         e.Receiver,
+			Integration:      e.Integration,
+			IntegrationIndex: e.IntegrationIndex,
+			GroupKey:         e.GroupKey,
+			Alerts:           e.Alerts,
+			Status:           string(e.Status),
+			Error:            e.Error,
+			Retry:            e.Retry,
+			Payload:          e.Payload,
+			SentAt:           e.SentAt.UnixMilli(),
+			Duration:         int64(e.Duration),
+		})
+	}
+	return st.SQLStore.WithDbSession(ctx, func(sess *db.Session) error {
+		if _, err := sess.InsertMulti(&rows); err != nil {
+			return fmt.Errorf("failed to save notification history: %w", err)
+		}
+		return nil
+	})
+}
+
+// GetNotificationHistory returns the entries of the notification history of the organization that match the query,
+// the most recent first.
+func (st DBstore) GetNotificationHistory(ctx context.Context, query ngmodels.NotificationHistoryQuery) ([]ngmodels.NotificationHistoryEntry, error) {
+	var rows []notificationHistoryEntry
+	err := st.SQLStore.WithDbSession(ctx, func(sess *db.Session) error {
+		q := sess.Where("org_id = ?", query.OrgID)
+		if query.Receiver != "" {
+			q = q.And("receiver = ?", query.Receiver)
+		}
+		if query.Integration != "" {
+			q = q.And("integration = ?", query.Integration)
+		}
+		if query.Status != "" {
+			q = q.And("status = ?", string(query.Status))
+		}
+		if query.GroupKey != "" {
+			q = q.And("group_key = ?", query.GroupKey)
+		}
+		if !query.From.IsZero() {
+			q = q.And("sent_at >= ?", query.From.UnixMilli())
+		}
+		if !query.To.IsZero() {
+			q = q.And("sent_at <= ?", query.To.UnixMilli())
+		}
+		q = q.Desc("sent_at", "id")
+		if query.Limit > 0 {
+			q = q.Limit(query.Limit)
+		}
+		return q.Find(&rows)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get notification history: %w", err)
+	}
+
+	result := make([]ngmodels.NotificationHistoryEntry, 0, len(rows))
+	for _, r := range rows {
+		result = append(result, ngmodels.NotificationHistoryEntry{
+			ID:               r.ID,
+			OrgID:            r.OrgID,
+			Receiver:         r.Receiver,
+			Integration:      r.Integration,
+			IntegrationIndex: r.IntegrationIndex,
+			GroupKey:         r.GroupKey,
+			Alerts:           r.Alerts,
+			Status:           ngmodels.NotificationStatus(r.Status),
+			Error:            r.Error,
+			Retry:            r.Retry,
+			Payload:          r.Payload,
+			SentAt:           time.UnixMilli(r.SentAt),
+			Duration:         time.Duration(r.Duration),
+		})
+	}
+	return result, nil
+}
@@ -0,0 +1,94 @@
+package store
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/prometheus/common/model"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/infra/db"
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/setting"
+)
+
+func TestIntegrationNotificationHistory(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+
+	sqlStore := db.InitTestDB(t)
+	cfg := setting.NewCfg()
+	store := &DBstore{
+		SQLStore: sqlStore,
+		Logger:   log.New("test-dbstore"),
+		Cfg:      cfg.UnifiedAlerting,
+	}
+	ctx := context.Background()
+
+	sentAt := time.Date(2023, 10, 2, 12, 0, 0, 0, time.UTC)
+	entry := func(orgID int64, receiver string, status models.NotificationStatus, sentAt time.Time) models.NotificationHistoryEntry {
+		return models.NotificationHistoryEntry{
+			OrgID:       orgID,
+			Receiver:    receiver,
+			Integration: "webhook",
+			GroupKey:    "{}:{alertname=\"test\"}",
+			Alerts: []models.NotificationHistoryAlert{{
+				Labels:   map[string]string{"alertname": "test"},
+				Status:   model.AlertFiring,
+				StartsAt: sentAt.Add(-time.Minute),
+			}},
+			Status:   status,
+			Payload:  `{"status":"firing"}`,
+			SentAt:   sentAt,
+			Duration: 150 * time.Millisecond,
+		}
+	}
+
+	require.NoError(t, store.SaveNotificationHistory(ctx, []models.NotificationHistoryEntry{
+		entry(1, "team-a", models.NotificationStatusSuccess, sentAt),
+		entry(1, "team-b", models.NotificationStatusSuccess, sentAt.Add(time.Minute)),
+		entry(1, "team-a", models.NotificationStatusFailure, sentAt.Add(2*time.Minute)),
+		entry(2, "team-a", models.NotificationStatusSuccess, sentAt),
+	}))
+
+	t.Run("should return the entries of the organization, the most recent first", func(t *testing.T) {
+		entries, err := store.GetNotificationHistory(ctx, models.NotificationHistoryQuery{OrgID: 1})
+		require.NoError(t, err)
+		require.Len(t, entries, 3)
+		require.Equal(t, sentAt.Add(2*time.Minute), entries[0].SentAt.UTC())
+		require.Equal(t, models.NotificationStatusFailure, entries[0].Status)
+		require.Equal(t, sentAt, entries[2].SentAt.UTC())
+
+		require.Equal(t, "webhook", entries[2].Integration)
+		require.Equal(t, `{"status":"firing"}`, entries[2].Payload)
+		require.Equal(t, 150*time.Millisecond, entries[2].Duration)
+		require.Len(t, entries[2].Alerts, 1)
+		require.Equal(t, map[string]string{"alertname": "test"}, entries[2].Alerts[0].Labels)
+		require.Equal(t, model.AlertFiring, entries[2].Alerts[0].Status)
+	})
+
+	t.Run("should filter the entries", func(t *testing.T) {
+		entries, err := store.GetNotificationHistory(ctx, models.NotificationHistoryQuery{OrgID: 1, Receiver: "team-a"})
+		require.NoError(t, err)
+		require.Len(t, entries, 2)
+
+		entries, err = store.GetNotificationHistory(ctx, models.NotificationHistoryQuery{OrgID: 1, Status: models.NotificationStatusSuccess})
+		require.NoError(t, err)
+		require.Len(t, entries, 2)
+
+		entries, err = store.GetNotificationHistory(ctx, models.NotificationHistoryQuery{OrgID: 1, From: sentAt.Add(time.Minute), To: sentAt.Add(time.Minute)})
+		require.NoError(t, err)
+		require.Len(t, entries, 1)
+		require.Equal(t, "team-b", entries[0].Receiver)
+	})
+
+	t.Run("should limit the number of entries", func(t *testing.T) {
+		entries, err := store.GetNotificationHistory(ctx, models.NotificationHistoryQuery{OrgID: 1, Limit: 1})
+		require.NoError(t, err)
+		require.Len(t, entries, 1)
+		require.Equal(t, models.NotificationStatusFailure, entries[0].Status)
+	})
+}
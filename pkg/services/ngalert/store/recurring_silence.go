@@ -0,0 +1,85 @@
+package store
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/grafana/grafana/pkg/infra/db"
+	ngmodels "github.com/grafana/grafana/pkg/services/ngalert/models"
+)
+
+// GetRecurringSilence returns the recurring silence of the organization with the UID.
+// It returns ngmodels.ErrRecurringSilenceNotFound if the recurring silence does not exist.
+func (st DBstore) GetRecurringSilence(ctx context.Context, orgID int64, uid string) (*ngmodels.RecurringSilence, error) {
+	silence := ngmodels.RecurringSilence{OrgID: orgID, UID: uid}
+	err := st.SQLStore.WithDbSession(ctx, func(sess *db.Session) error {
+		has, err := sess.Get(&silence)
+		if err != nil {
+			return err
+		}
+		if !has {
+			return ngmodels.ErrRecurringSilenceNotFound
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &silence, nil
+}
+
+// ListRecurringSilences returns the recurring silences of the organization ordered by UID.
+// It returns the recurring silences of all organizations if orgID is 0.
+func (st DBstore) ListRecurringSilences(ctx context.Context, orgID int64) ([]*ngmodels.RecurringSilence, error) {
+	silences := make([]*ngmodels.RecurringSilence, 0)
+	err := st.SQLStore.WithDbSession(ctx, func(sess *db.Session) error {
+		q := sess.Asc("org_id", "uid")
+		if orgID > 0 {
+			q = q.Where("org_id = ?", orgID)
+		}
+		return q.Find(&silences)
+	})
+	return silences, err
+}
+
+// InsertRecurringSilence creates the recurring silence and returns its ID.
+func (st DBstore) InsertRecurringSilence(ctx context.Context, silence ngmodels.RecurringSilence) (int64, error) {
+	err := st.SQLStore.WithDbSession(ctx, func(sess *db.Session) error {
+		silence.ID = 0
+		silence.Version = 1
+		silence.Updated = TimeNow()
+		if _, err := sess.Insert(&silence); err != nil {
+			if st.SQLStore.GetDialect().IsUniqueConstraintViolation(err) {
+				return fmt.Errorf("%w: recurring silence with UID '%s' already exists", ngmodels.ErrRecurringSilenceFailedValidation, silence.UID)
+			}
+			return fmt.Errorf("failed to create recurring silence: %w", err)
+		}
+		return nil
+	})
+	return silence.ID, err
+}
+
+// UpdateRecurringSilence updates the recurring silence with the ID and the version of the silence.
+// It returns ErrOptimisticLock if the recurring silence was changed since that version was read.
+func (st DBstore) UpdateRecurringSilence(ctx context.Context, silence ngmodels.RecurringSilence) error {
+	return st.SQLStore.WithDbSession(ctx, func(sess *db.Session) error {
+		silence.Updated = TimeNow()
+		// xorm increases the version and checks that the stored version is the one of the silence (see https://xorm.io/docs/chapter-06/1.lock/)
+		updated, err := sess.ID(silence.ID).AllCols().Update(&silence)
+		if err != nil {
+			return fmt.Errorf("failed to update recurring silence %s: %w", silence.UID, err)
+		}
+		if updated == 0 {
+			return fmt.Errorf("%w: recurring silence UID %s version %d", ErrOptimisticLock, silence.UID, silence.Version)
+		}
+		return nil
+	})
+}
+
+// DeleteRecurringSilence deletes the recurring silence of the organization with the UID.
+func (st DBstore) DeleteRecurringSilence(ctx context.Context, orgID int64, uid string) error {
+	return st.SQLStore.WithDbSession(ctx, func(sess *db.Session) error {
+		_, err := sess.Where("org_id = ? AND uid = ?", orgID, uid).Delete(&ngmodels.RecurringSilence{})
+		return err
+	})
+}
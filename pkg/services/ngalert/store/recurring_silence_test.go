@@ -0,0 +1,108 @@
+package store
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/infra/db"
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/setting"
+)
+
+func TestIntegrationRecurringSilences(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+
+	sqlStore := db.InitTestDB(t)
+	cfg := setting.NewCfg()
+	store := &DBstore{
+		SQLStore: sqlStore,
+		Logger:   log.New("test-dbstore"),
+		Cfg:      cfg.UnifiedAlerting,
+	}
+	ctx := context.Background()
+
+	until := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	silence := models.RecurringSilence{
+		OrgID:     1,
+		UID:       "maintenance",
+		Matchers:  []models.RecurringSilenceMatcher{{Name: "service", Value: "api|db", IsRegex: true, IsEqual: true}},
+		Comment:   "Weekly maintenance",
+		CreatedBy: "admin",
+		StartsAt:  time.Date(2023, 9, 25, 22, 0, 0, 0, time.UTC),
+		Duration:  time.Hour,
+		Frequency: models.RecurringSilenceFrequencyWeekly,
+		Interval:  2,
+		Until:     &until,
+		Timezone:  "Europe/Berlin",
+	}
+
+	id, err := store.InsertRecurringSilence(ctx, silence)
+	require.NoError(t, err)
+	_, err = store.InsertRecurringSilence(ctx, silence)
+	require.ErrorIs(t, err, models.ErrRecurringSilenceFailedValidation, "the UID should be unique in the organization")
+	other := silence
+	other.OrgID = 2
+	_, err = store.InsertRecurringSilence(ctx, other)
+	require.NoError(t, err)
+
+	t.Run("should get the recurring silence", func(t *testing.T) {
+		stored, err := store.GetRecurringSilence(ctx, 1, silence.UID)
+		require.NoError(t, err)
+		require.Equal(t, id, stored.ID)
+		require.Equal(t, int64(1), stored.Version)
+		require.Equal(t, silence.Matchers, stored.Matchers)
+		require.Equal(t, silence.Frequency, stored.Frequency)
+		require.Equal(t, silence.Interval, stored.Interval)
+		require.Equal(t, silence.Duration, stored.Duration)
+		require.True(t, silence.StartsAt.Equal(stored.StartsAt))
+		require.NotNil(t, stored.Until)
+		require.True(t, until.Equal(*stored.Until))
+
+		_, err = store.GetRecurringSilence(ctx, 3, silence.UID)
+		require.ErrorIs(t, err, models.ErrRecurringSilenceNotFound)
+	})
+
+	t.Run("should update the recurring silence with optimistic locking", func(t *testing.T) {
+		stored, err := store.GetRecurringSilence(ctx, 1, silence.UID)
+		require.NoError(t, err)
+		stored.ExpandedUntil = time.Date(2023, 10, 3, 0, 0, 0, 0, time.UTC)
+		stored.Silences = []models.RecurringSilenceOccurrence{{
+			SilenceID: "silence-1",
+			StartsAt:  time.Date(2023, 10, 2, 22, 0, 0, 0, time.UTC),
+			EndsAt:    time.Date(2023, 10, 2, 23, 0, 0, 0, time.UTC),
+		}}
+		require.NoError(t, store.UpdateRecurringSilence(ctx, *stored))
+		require.ErrorIs(t, store.UpdateRecurringSilence(ctx, *stored), ErrOptimisticLock)
+
+		updated, err := store.GetRecurringSilence(ctx, 1, silence.UID)
+		require.NoError(t, err)
+		require.Equal(t, int64(2), updated.Version)
+		require.Equal(t, stored.Silences, updated.Silences)
+		require.True(t, stored.ExpandedUntil.Equal(updated.ExpandedUntil))
+	})
+
+	t.Run("should list the recurring silences", func(t *testing.T) {
+		silences, err := store.ListRecurringSilences(ctx, 1)
+		require.NoError(t, err)
+		require.Len(t, silences, 1)
+
+		silences, err = store.ListRecurringSilences(ctx, 0)
+		require.NoError(t, err)
+		require.Len(t, silences, 2)
+	})
+
+	t.Run("should delete the recurring silence", func(t *testing.T) {
+		require.NoError(t, store.DeleteRecurringSilence(ctx, 1, silence.UID))
+		_, err := store.GetRecurringSilence(ctx, 1, silence.UID)
+		require.ErrorIs(t, err, models.ErrRecurringSilenceNotFound)
+
+		_, err = store.GetRecurringSilence(ctx, 2, silence.UID)
+		require.NoError(t, err)
+	})
+}
@@ -0,0 +1,128 @@
+package store
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/grafana/grafana/pkg/infra/db"
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/services/ngalert/sharding"
+)
+
+type alertReplica struct {
+	ID            int64  `xorm:"pk autoincr 'id'"`
+	ReplicaID     string `xorm:"replica_id"`
+	LastHeartbeat int64  `xorm:"last_heartbeat"`
+}
+
+func (alertReplica) TableName() string {
+	return "alert_replica"
+}
+
+type alertReplicaAlert struct {
+	ID        int64  `xorm:"pk autoincr 'id'"`
+	ReplicaID string `xorm:"replica_id"`
+	RuleOrgID int64  `xorm:"rule_org_id"`
+	RuleUID   string `xorm:"rule_uid"`
+	Alerts    string `xorm:"alerts"`
+	CreatedAt int64  `xorm:"created_at"`
+}
+
+func (alertReplicaAlert) TableName() string {
+	return "alert_replica_alert"
+}
+
+func (st DBstore) Heartbeat(ctx context.Context, replicaID string, at time.Time) error {
+	return st.SQLStore.WithDbSession(ctx, func(sess *db.Session) error {
+		upsertSQL := st.SQLStore.GetDialect().UpsertSQL(
+			"alert_replica",
+			[]string{"replica_id"},
+			[]string{"replica_id", "last_heartbeat"})
+		if _, err := sess.SQL(upsertSQL, replicaID, at.Unix()).Query(); err != nil {
+			return fmt.Errorf("failed to save heartbeat: %w", err)
+		}
+		return nil
+	})
+}
+
+func (st DBstore) GetReplicas(ctx context.Context, since time.Time) ([]string, error) {
+	var replicas []alertReplica
+	err := st.SQLStore.WithDbSession(ctx, func(sess *db.Session) error {
+		return sess.Where("last_heartbeat >= ?", since.Unix()).Asc("replica_id").Find(&replicas)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get replicas: %w", err)
+	}
+
+	result := make([]string, 0, len(replicas))
+	for _, r := range replicas {
+		result = append(result, r.ReplicaID)
+	}
+	return result, nil
+}
+
+func (st DBstore) DeleteReplica(ctx context.Context, replicaID string) error {
+	return st.SQLStore.WithDbSession(ctx, func(sess *db.Session) error {
+		_, err := sess.Exec("DELETE FROM alert_replica WHERE replica_id = ?", replicaID)
+		return err
+	})
+}
+
+func (st DBstore) DeleteExpired(ctx context.Context, before time.Time) error {
+	return st.SQLStore.WithDbSession(ctx, func(sess *db.Session) error {
+		if _, err := sess.Exec("DELETE FROM alert_replica WHERE last_heartbeat < ?", before.Unix()); err != nil {
+			return fmt.Errorf("failed to delete expired replicas: %w", err)
+		}
+		if _, err := sess.Exec("DELETE FROM alert_replica_alert WHERE created_at < ?", before.Unix()); err != nil {
+			return fmt.Errorf("failed to delete expired replica alerts: %w", err)
+		}
+		return nil
+	})
+}
+
+func (st DBstore) SaveReplicaAlerts(ctx context.Context, alerts sharding.ReplicaAlerts, at time.Time) error {
+	b, err := json.Marshal(alerts.Alerts)
+	if err != nil {
+		return fmt.Errorf("failed to marshal alerts: %w", err)
+	}
+
+	return st.SQLStore.WithDbSession(ctx, func(sess *db.Session) error {
+		_, err := sess.Insert(&alertReplicaAlert{
+			ReplicaID: alerts.ReplicaID,
+			RuleOrgID: alerts.RuleKey.OrgID,
+			RuleUID:   alerts.RuleKey.UID,
+			Alerts:    string(b),
+			CreatedAt: at.Unix(),
+		})
+		if err != nil {
+			return fmt.Errorf("failed to save replica alerts: %w", err)
+		}
+		return nil
+	})
+}
+
+func (st DBstore) GetReplicaAlerts(ctx context.Context, replicaID string, since time.Time) ([]sharding.ReplicaAlerts, error) {
+	var rows []alertReplicaAlert
+	err := st.SQLStore.WithDbSession(ctx, func(sess *db.Session) error {
+		return sess.Where("replica_id <> ? AND created_at >= ?", replicaID, since.Unix()).Asc("id").Find(&rows)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get replica alerts: %w", err)
+	}
+
+	result := make([]sharding.ReplicaAlerts, 0, len(rows))
+	for _, row := range rows {
+		alerts := sharding.ReplicaAlerts{
+			ID:        row.ID,
+			ReplicaID: row.ReplicaID,
+			RuleKey:   models.AlertRuleKey{OrgID: row.RuleOrgID, UID: row.RuleUID},
+		}
+		if err := json.Unmarshal([]byte(row.Alerts), &alerts.Alerts); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal alerts %d: %w", row.ID, err)
+		}
+		result = append(result, alerts)
+	}
+	return result, nil
+}
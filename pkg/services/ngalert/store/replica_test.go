@@ -0,0 +1,86 @@
+package store_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	amv2 "github.com/prometheus/alertmanager/api/v2/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/services/ngalert/sharding"
+	"github.com/grafana/grafana/pkg/services/ngalert/tests"
+)
+
+func TestIntegrationReplicas(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+
+	ctx := context.Background()
+	_, dbstore := tests.SetupTestEnv(t, baseIntervalSeconds)
+
+	now := time.Now().Truncate(time.Second)
+	require.NoError(t, dbstore.Heartbeat(ctx, "replica-b", now))
+	require.NoError(t, dbstore.Heartbeat(ctx, "replica-a", now.Add(-time.Minute)))
+	require.NoError(t, dbstore.Heartbeat(ctx, "replica-c", now.Add(-time.Hour)))
+
+	replicas, err := dbstore.GetReplicas(ctx, now.Add(-time.Minute))
+	require.NoError(t, err)
+	assert.Equal(t, []string{"replica-a", "replica-b"}, replicas)
+
+	// a new heartbeat brings the replica back
+	require.NoError(t, dbstore.Heartbeat(ctx, "replica-c", now))
+	replicas, err = dbstore.GetReplicas(ctx, now.Add(-time.Minute))
+	require.NoError(t, err)
+	assert.Equal(t, []string{"replica-a", "replica-b", "replica-c"}, replicas)
+
+	require.NoError(t, dbstore.DeleteReplica(ctx, "replica-a"))
+	replicas, err = dbstore.GetReplicas(ctx, now.Add(-time.Minute))
+	require.NoError(t, err)
+	assert.Equal(t, []string{"replica-b", "replica-c"}, replicas)
+
+	require.NoError(t, dbstore.Heartbeat(ctx, "replica-c", now.Add(-time.Hour)))
+	require.NoError(t, dbstore.DeleteExpired(ctx, now.Add(-time.Minute)))
+	replicas, err = dbstore.GetReplicas(ctx, time.Time{})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"replica-b"}, replicas)
+}
+
+func TestIntegrationReplicaAlerts(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+
+	ctx := context.Background()
+	_, dbstore := tests.SetupTestEnv(t, baseIntervalSeconds)
+
+	now := time.Now().Truncate(time.Second)
+	alerts := func(name string) definitions.PostableAlerts {
+		return definitions.PostableAlerts{PostableAlerts: []amv2.PostableAlert{
+			{Alert: amv2.Alert{Labels: amv2.LabelSet{"alertname": name}}},
+		}}
+	}
+	key := models.AlertRuleKey{OrgID: 1, UID: "rule"}
+	require.NoError(t, dbstore.SaveReplicaAlerts(ctx, sharding.ReplicaAlerts{ReplicaID: "replica-a", RuleKey: key, Alerts: alerts("old")}, now.Add(-time.Hour)))
+	require.NoError(t, dbstore.SaveReplicaAlerts(ctx, sharding.ReplicaAlerts{ReplicaID: "replica-a", RuleKey: key, Alerts: alerts("a")}, now))
+	require.NoError(t, dbstore.SaveReplicaAlerts(ctx, sharding.ReplicaAlerts{ReplicaID: "replica-b", RuleKey: key, Alerts: alerts("b")}, now))
+
+	// the alerts of the other replicas are returned
+	result, err := dbstore.GetReplicaAlerts(ctx, "replica-b", now.Add(-time.Minute))
+	require.NoError(t, err)
+	require.Len(t, result, 1)
+	assert.Equal(t, "replica-a", result[0].ReplicaID)
+	assert.Equal(t, key, result[0].RuleKey)
+	assert.Equal(t, alerts("a"), result[0].Alerts)
+
+	require.NoError(t, dbstore.DeleteExpired(ctx, now.Add(-time.Minute)))
+	result, err = dbstore.GetReplicaAlerts(ctx, "replica-c", time.Time{})
+	require.NoError(t, err)
+	require.Len(t, result, 2)
+	assert.Equal(t, "replica-a", result[0].ReplicaID)
+	assert.Equal(t, "replica-b", result[1].ReplicaID)
+}
@@ -0,0 +1,113 @@
+package ngalert
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+	"github.com/grafana/grafana/pkg/services/ngalert/schedule"
+	"github.com/grafana/grafana/pkg/services/supportbundles"
+	"github.com/grafana/grafana/pkg/setting"
+)
+
+func (ng *AlertNG) supportBundleCollector() supportbundles.Collector {
+	collectorFn := func(ctx context.Context) (*supportbundles.SupportItem, error) {
+		type orgInfo struct {
+			OrgID int64 `json:"orgId"`
+			// Scheduler is the state of the rules of the organization in the scheduler.
+			Scheduler schedule.OrgStatus `json:"scheduler"`
+			// AlertInstances is the number of alert instances by state.
+			AlertInstances map[string]int `json:"alertInstances"`
+			// AlertmanagerConfig is the current Alertmanager configuration, secure settings are never included.
+			AlertmanagerConfig *definitions.GettableUserConfig `json:"alertmanagerConfig,omitempty"`
+			// AlertmanagerConfigError explains why the Alertmanager configuration is missing.
+			AlertmanagerConfigError string `json:"alertmanagerConfigError,omitempty"`
+		}
+
+		orgIDs, err := ng.store.GetOrgs(ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		schedulerStatus := ng.schedule.Status()
+		orgs := make([]orgInfo, 0, len(orgIDs))
+		for _, orgID := range orgIDs {
+			info := orgInfo{
+				OrgID:          orgID,
+				Scheduler:      schedulerStatus[orgID],
+				AlertInstances: map[string]int{},
+			}
+
+			for _, s := range ng.stateManager.GetAll(orgID) {
+				info.AlertInstances[s.State.String()]++
+			}
+
+			amConfig, err := ng.MultiOrgAlertmanager.GetAlertmanagerConfiguration(ctx, orgID)
+			if err != nil {
+				info.AlertmanagerConfigError = err.Error()
+			} else {
+				redactReceiverSettings(&amConfig)
+				info.AlertmanagerConfig = &amConfig
+			}
+
+			orgs = append(orgs, info)
+		}
+
+		data, err := json.MarshalIndent(orgs, "", " ")
+		if err != nil {
+			return nil, err
+		}
+
+		return &supportbundles.SupportItem{
+			Filename:  "alerting.json",
+			FileBytes: data,
+		}, nil
+	}
+
+	return supportbundles.Collector{
+		UID:               "alerting",
+		DisplayName:       "Alerting",
+		Description:       "Alerting configuration and scheduler state per organization",
+		IncludedByDefault: false,
+		Default:           true,
+		Fn:                collectorFn,
+	}
+}
+
+// redactReceiverSettings redacts the values of the receiver settings that look sensitive.
+// Secure settings are not part of the configuration already, but secrets can still be found in
+// the plain settings, for example credentials in a webhook URL.
+func redactReceiverSettings(cfg *definitions.GettableUserConfig) {
+	for _, recv := range cfg.AlertmanagerConfig.Receivers {
+		for _, gr := range recv.GrafanaManagedReceivers {
+			if len(gr.Settings) == 0 {
+				continue
+			}
+
+			settings := map[string]any{}
+			if err := json.Unmarshal(gr.Settings, &settings); err != nil {
+				gr.Settings = nil
+				continue
+			}
+			redactValues(settings)
+
+			redacted, err := json.Marshal(settings)
+			if err != nil {
+				gr.Settings = nil
+				continue
+			}
+			gr.Settings = redacted
+		}
+	}
+}
+
+func redactValues(values map[string]any) {
+	for k, v := range values {
+		switch value := v.(type) {
+		case string:
+			values[k] = setting.RedactedValue(k, value)
+		case map[string]any:
+			redactValues(value)
+		}
+	}
+}
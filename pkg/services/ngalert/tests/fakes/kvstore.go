@@ -5,20 +5,23 @@ import (
 	"strings"
 	"sync"
 	"testing"
+	"time"
 
 	"github.com/grafana/grafana/pkg/infra/kvstore"
 )
 
 type FakeKVStore struct {
-	Mtx   sync.Mutex
-	Store map[int64]map[string]map[string]string
+	Mtx      sync.Mutex
+	Store    map[int64]map[string]map[string]string
+	Versions map[kvstore.Key]int64
 }
 
 func NewFakeKVStore(t *testing.T) *FakeKVStore {
 	t.Helper()
 
 	return &FakeKVStore{
-		Store: map[int64]map[string]map[string]string{},
+		Store:    map[int64]map[string]map[string]string{},
+		Versions: map[kvstore.Key]int64{},
 	}
 }
 
@@ -54,6 +57,7 @@ func (fkv *FakeKVStore) Set(_ context.Context, orgId int64, namespace string, ke
 	}
 
 	fkv.Store[orgId][namespace][key] = value
+	fkv.Versions[kvstore.Key{OrgId: orgId, Namespace: namespace, Key: key}]++
 
 	return nil
 }
@@ -100,3 +104,64 @@ func (fkv *FakeKVStore) Keys(ctx context.Context, orgID int64, namespace string,
 func (fkv *FakeKVStore) GetAll(ctx context.Context, orgId int64, namespace string) (map[int64]map[string]string, error) {
 	return nil, nil
 }
+
+func (fkv *FakeKVStore) GetEntry(ctx context.Context, orgId int64, namespace string, key string) (kvstore.Entry, bool, error) {
+	value, ok, err := fkv.Get(ctx, orgId, namespace, key)
+	if !ok || err != nil {
+		return kvstore.Entry{}, ok, err
+	}
+
+	fkv.Mtx.Lock()
+	defer fkv.Mtx.Unlock()
+	return kvstore.Entry{
+		OrgId:     orgId,
+		Namespace: namespace,
+		Key:       key,
+		Value:     value,
+		Version:   fkv.Versions[kvstore.Key{OrgId: orgId, Namespace: namespace, Key: key}],
+	}, true, nil
+}
+
+func (fkv *FakeKVStore) GetEntries(ctx context.Context, orgId int64, namespace string) ([]kvstore.Entry, error) {
+	fkv.Mtx.Lock()
+	defer fkv.Mtx.Unlock()
+	var entries []kvstore.Entry
+	for orgIDFromStore, namespaceMap := range fkv.Store {
+		if orgId != kvstore.AllOrganizations && orgId != orgIDFromStore {
+			continue
+		}
+		for k, v := range namespaceMap[namespace] {
+			entries = append(entries, kvstore.Entry{
+				OrgId:     orgIDFromStore,
+				Namespace: namespace,
+				Key:       k,
+				Value:     v,
+				Version:   fkv.Versions[kvstore.Key{OrgId: orgIDFromStore, Namespace: namespace, Key: k}],
+			})
+		}
+	}
+	return entries, nil
+}
+
+// CompareAndSwap ignores the ttl, values never expire.
+func (fkv *FakeKVStore) CompareAndSwap(ctx context.Context, orgId int64, namespace string, key string, version int64, value string, _ time.Duration) (int64, error) {
+	entry, ok, _ := fkv.GetEntry(ctx, orgId, namespace, key)
+	if (ok && entry.Version != version) || (!ok && version != 0) {
+		return 0, kvstore.ErrVersionMismatch
+	}
+	if err := fkv.Set(ctx, orgId, namespace, key, value); err != nil {
+		return 0, err
+	}
+
+	fkv.Mtx.Lock()
+	defer fkv.Mtx.Unlock()
+	return fkv.Versions[kvstore.Key{OrgId: orgId, Namespace: namespace, Key: key}], nil
+}
+
+func (fkv *FakeKVStore) CompareAndDelete(ctx context.Context, orgId int64, namespace string, key string, version int64) error {
+	entry, ok, _ := fkv.GetEntry(ctx, orgId, namespace, key)
+	if !ok || entry.Version != version {
+		return kvstore.ErrVersionMismatch
+	}
+	return fkv.Del(ctx, orgId, namespace, key)
+}
@@ -25,6 +25,8 @@ type RuleStore struct {
 	Hook        func(cmd any) error // use Hook if you need to intercept some query and return an error
 	RecordedOps []any
 	Folders     map[int64][]*folder.Folder
+	// OrgID -> alert rule templates
+	Templates map[int64][]*models.AlertRuleTemplate
 }
 
 type GenericRecordedQuery struct {
@@ -39,7 +41,8 @@ func NewRuleStore(t *testing.T) *RuleStore {
 		Hook: func(any) error {
 			return nil
 		},
-		Folders: map[int64][]*folder.Folder{},
+		Folders:   map[int64][]*folder.Folder{},
+		Templates: map[int64][]*models.AlertRuleTemplate{},
 	}
 }
 
@@ -141,6 +144,17 @@ func (f *RuleStore) GetAlertRuleByUID(_ context.Context, q *models.GetAlertRuleB
 	return nil, nil
 }
 
+func (f *RuleStore) GetAlertRuleTemplate(_ context.Context, orgID int64, uid string) (*models.AlertRuleTemplate, error) {
+	f.mtx.Lock()
+	defer f.mtx.Unlock()
+	for _, template := range f.Templates[orgID] {
+		if template.UID == uid {
+			return template, nil
+		}
+	}
+	return nil, models.ErrAlertRuleTemplateNotFound
+}
+
 func (f *RuleStore) GetAlertRulesGroupByRuleUID(_ context.Context, q *models.GetAlertRulesGroupByRuleUIDQuery) ([]*models.AlertRule, error) {
 	f.mtx.Lock()
 	defer f.mtx.Unlock()
@@ -0,0 +1,244 @@
+package writer
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"github.com/prometheus/prometheus/prompb"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/services/live/remotewrite"
+	"github.com/grafana/grafana/pkg/setting"
+)
+
+const (
+	// TenantHeader is the header carrying the tenant of the written series.
+	TenantHeader = "X-Scope-OrgID"
+	// OrgIDLabel is the label carrying the organization of the recording rule that produced a series,
+	// so that the series of the organizations sharing the remote write endpoint can be told apart.
+	OrgIDLabel = "grafana_org_id"
+
+	metricNameLabel = "__name__"
+)
+
+var ErrDuplicateSeries = errors.New("duplicate series")
+
+// PrometheusWriter writes the results of recording rules to a Prometheus
+// compatible remote write endpoint.
+type PrometheusWriter struct {
+	url      string
+	username string
+	password string
+	tenantID string
+	client   *http.Client
+	logger   log.Logger
+}
+
+func NewPrometheusWriter(cfg setting.RecordingRuleSettings, l log.Logger) (*PrometheusWriter, error) {
+	if _, err := url.ParseRequestURI(cfg.URL); err != nil {
+		return nil, fmt.Errorf("invalid remote write url: %w", err)
+	}
+
+	return &PrometheusWriter{
+		url:      cfg.URL,
+		username: cfg.BasicAuthUsername,
+		password: cfg.BasicAuthPassword,
+		tenantID: cfg.TenantID,
+		client:   &http.Client{Timeout: cfg.Timeout},
+		logger:   l,
+	}, nil
+}
+
+// Write writes the series of frames as metric name at time t. The labels in
+// extraLabels are added to every series, replacing the labels of the frames,
+// and every series is labeled with orgID.
+func (w *PrometheusWriter) Write(ctx context.Context, name string, t time.Time, frames data.Frames, orgID int64, extraLabels map[string]string) error {
+	series, err := seriesFromFrames(frames)
+	if err != nil {
+		return err
+	}
+
+	ts, err := timeSeries(name, t, series, orgID, extraLabels)
+	if err != nil {
+		return err
+	}
+	if len(ts) == 0 {
+		w.logger.Debug("No series to write", "metric", name, "org", orgID)
+		return nil
+	}
+
+	body, err := remotewrite.TimeSeriesToBytes(ts)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, w.url, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to create remote write request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/x-protobuf")
+	req.Header.Set("Content-Encoding", "snappy")
+	req.Header.Set("X-Prometheus-Remote-Write-Version", "0.1.0")
+	if w.username != "" {
+		req.SetBasicAuth(w.username, w.password)
+	}
+	if w.tenantID != "" {
+		req.Header.Set(TenantHeader, w.tenantID)
+	}
+
+	started := time.Now()
+	resp, err := w.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to send remote write request: %w", err)
+	}
+	defer func() {
+		if err := resp.Body.Close(); err != nil {
+			w.logger.Warn("Failed to close response body", "error", err)
+		}
+	}()
+
+	if resp.StatusCode/100 != 2 {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return fmt.Errorf("remote write endpoint responded with status %d: %s", resp.StatusCode, strings.TrimSpace(string(msg)))
+	}
+
+	w.logger.Debug("Wrote recording rule series", "metric", name, "org", orgID, "series", len(ts), "duration", time.Since(started))
+	return nil
+}
+
+type series struct {
+	labels map[string]string
+	value  float64
+}
+
+// seriesFromFrames extracts a value for each series of frames. Time series are
+// reduced to their last value. In tables, each row is a series labeled with
+// the string columns.
+func seriesFromFrames(frames data.Frames) ([]series, error) {
+	var result []series
+	for _, frame := range frames {
+		var labelFields, valueFields []*data.Field
+		for _, field := range frame.Fields {
+			switch {
+			case field.Type() == data.FieldTypeString || field.Type() == data.FieldTypeNullableString:
+				labelFields = append(labelFields, field)
+			case field.Type().Numeric():
+				valueFields = append(valueFields, field)
+			}
+		}
+
+		if len(labelFields) == 0 {
+			for _, field := range valueFields {
+				v, ok, err := lastValue(field)
+				if err != nil {
+					return nil, err
+				}
+				if ok {
+					result = append(result, series{labels: field.Labels, value: v})
+				}
+			}
+			continue
+		}
+
+		for i := 0; i < frame.Rows(); i++ {
+			for _, field := range valueFields {
+				v, err := field.NullableFloatAt(i)
+				if err != nil {
+					return nil, err
+				}
+				if v == nil {
+					continue
+				}
+				labels := make(map[string]string, len(field.Labels)+len(labelFields))
+				for k, v := range field.Labels {
+					labels[k] = v
+				}
+				for _, lf := range labelFields {
+					if value, ok := lf.ConcreteAt(i); ok {
+						labels[lf.Name] = value.(string)
+					}
+				}
+				result = append(result, series{labels: labels, value: *v})
+			}
+		}
+	}
+	return result, nil
+}
+
+func lastValue(field *data.Field) (float64, bool, error) {
+	for i := field.Len() - 1; i >= 0; i-- {
+		v, err := field.NullableFloatAt(i)
+		if err != nil {
+			return 0, false, err
+		}
+		if v != nil {
+			return *v, true, nil
+		}
+	}
+	return 0, false, nil
+}
+
+func timeSeries(name string, t time.Time, series []series, orgID int64, extraLabels map[string]string) ([]prompb.TimeSeries, error) {
+	result := make([]prompb.TimeSeries, 0, len(series))
+	seen := make(map[string]struct{}, len(series))
+	for _, s := range series {
+		labels := make(map[string]string, len(s.labels)+len(extraLabels)+2)
+		for k, v := range s.labels {
+			labels[sanitizeLabelName(k)] = v
+		}
+		for k, v := range extraLabels {
+			labels[sanitizeLabelName(k)] = v
+		}
+		labels[metricNameLabel] = name
+		labels[OrgIDLabel] = strconv.FormatInt(orgID, 10)
+
+		names := make([]string, 0, len(labels))
+		for k := range labels {
+			names = append(names, k)
+		}
+		sort.Strings(names)
+
+		promLabels := make([]prompb.Label, 0, len(names))
+		var key strings.Builder
+		for _, k := range names {
+			promLabels = append(promLabels, prompb.Label{Name: k, Value: labels[k]})
+			key.WriteString(k)
+			key.WriteByte(0)
+			key.WriteString(labels[k])
+			key.WriteByte(0)
+		}
+		if _, ok := seen[key.String()]; ok {
+			return nil, fmt.Errorf("%w: %v", ErrDuplicateSeries, labels)
+		}
+		seen[key.String()] = struct{}{}
+
+		result = append(result, prompb.TimeSeries{
+			Labels:  promLabels,
+			Samples: []prompb.Sample{{Value: s.value, Timestamp: t.UnixMilli()}},
+		})
+	}
+	return result, nil
+}
+
+// sanitizeLabelName replaces the characters that are not allowed in Prometheus label names.
+func sanitizeLabelName(name string) string {
+	var b strings.Builder
+	for i, r := range name {
+		if r == '_' || (r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z') || (r >= '0' && r <= '9' && i > 0) {
+			b.WriteRune(r)
+			continue
+		}
+		b.WriteRune('_')
+	}
+	return b.String()
+}
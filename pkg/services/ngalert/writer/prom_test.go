@@ -0,0 +1,188 @@
+package writer
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gogo/protobuf/proto"
+	"github.com/golang/snappy"
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"github.com/prometheus/prometheus/prompb"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/setting"
+	"github.com/grafana/grafana/pkg/util"
+)
+
+type receiver struct {
+	requests []*http.Request
+	written  []prompb.TimeSeries
+	status   int
+}
+
+func (r *receiver) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	r.requests = append(r.requests, req)
+	compressed, err := io.ReadAll(req.Body)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	b, err := snappy.Decode(nil, compressed)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	var wr prompb.WriteRequest
+	if err := proto.Unmarshal(b, &wr); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	r.written = append(r.written, wr.Timeseries...)
+	if r.status != 0 {
+		http.Error(w, "rejected", r.status)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func newTestWriter(t *testing.T, r *receiver) *PrometheusWriter {
+	t.Helper()
+	srv := httptest.NewServer(r)
+	t.Cleanup(srv.Close)
+
+	w, err := NewPrometheusWriter(setting.RecordingRuleSettings{
+		URL:               srv.URL,
+		BasicAuthUsername: "user",
+		BasicAuthPassword: "password",
+		TenantID:          "tenant",
+		Timeout:           time.Second,
+	}, log.NewNopLogger())
+	require.NoError(t, err)
+	return w
+}
+
+func TestPrometheusWriter_Write(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+
+	t.Run("writes the last value of each series", func(t *testing.T) {
+		r := &receiver{}
+		w := newTestWriter(t, r)
+
+		frames := data.Frames{
+			data.NewFrame("",
+				data.NewField("time", nil, []time.Time{now.Add(-time.Minute), now}),
+				data.NewField("value", data.Labels{"instance": "a"}, []*float64{util.Pointer(1.0), util.Pointer(2.0)}),
+			),
+			data.NewFrame("",
+				data.NewField("value", data.Labels{"instance": "b", "team": "x"}, []float64{3}),
+			),
+		}
+		err := w.Write(context.Background(), "test_metric", now, frames, 1, map[string]string{"team": "y", "bad-name": "z"})
+		require.NoError(t, err)
+
+		require.Len(t, r.requests, 1)
+		req := r.requests[0]
+		assert.Equal(t, "snappy", req.Header.Get("Content-Encoding"))
+		assert.Equal(t, "tenant", req.Header.Get(TenantHeader))
+		user, password, ok := req.BasicAuth()
+		assert.True(t, ok)
+		assert.Equal(t, "user", user)
+		assert.Equal(t, "password", password)
+
+		expected := []prompb.TimeSeries{
+			{
+				Labels: []prompb.Label{
+					{Name: "__name__", Value: "test_metric"},
+					{Name: "bad_name", Value: "z"},
+					{Name: "grafana_org_id", Value: "1"},
+					{Name: "instance", Value: "a"},
+					{Name: "team", Value: "y"},
+				},
+				Samples: []prompb.Sample{{Value: 2, Timestamp: now.UnixMilli()}},
+			},
+			{
+				Labels: []prompb.Label{
+					{Name: "__name__", Value: "test_metric"},
+					{Name: "bad_name", Value: "z"},
+					{Name: "grafana_org_id", Value: "1"},
+					{Name: "instance", Value: "b"},
+					{Name: "team", Value: "y"},
+				},
+				Samples: []prompb.Sample{{Value: 3, Timestamp: now.UnixMilli()}},
+			},
+		}
+		assert.Equal(t, expected, r.written)
+	})
+
+	t.Run("writes each row of a table", func(t *testing.T) {
+		r := &receiver{}
+		w := newTestWriter(t, r)
+
+		frames := data.Frames{
+			data.NewFrame("",
+				data.NewField("host", nil, []string{"a", "b"}),
+				data.NewField("value", nil, []*int64{util.Pointer(int64(1)), nil}),
+			),
+		}
+		err := w.Write(context.Background(), "test_metric", now, frames, 1, nil)
+		require.NoError(t, err)
+
+		require.Len(t, r.written, 1)
+		assert.Equal(t, []prompb.Label{{Name: "__name__", Value: "test_metric"}, {Name: "grafana_org_id", Value: "1"}, {Name: "host", Value: "a"}}, r.written[0].Labels)
+		assert.Equal(t, float64(1), r.written[0].Samples[0].Value)
+	})
+
+	t.Run("fails on duplicate series", func(t *testing.T) {
+		r := &receiver{}
+		w := newTestWriter(t, r)
+
+		frames := data.Frames{
+			data.NewFrame("", data.NewField("value", data.Labels{"instance": "a"}, []float64{1})),
+			data.NewFrame("", data.NewField("value", data.Labels{"instance": "b"}, []float64{2})),
+		}
+		err := w.Write(context.Background(), "test_metric", now, frames, 1, map[string]string{"instance": "c"})
+		require.ErrorIs(t, err, ErrDuplicateSeries)
+		assert.Empty(t, r.requests)
+	})
+
+	t.Run("labels the series with the organization", func(t *testing.T) {
+		r := &receiver{}
+		w := newTestWriter(t, r)
+
+		frames := data.Frames{data.NewFrame("", data.NewField("value", data.Labels{"grafana_org_id": "2"}, []float64{1}))}
+		err := w.Write(context.Background(), "test_metric", now, frames, 3, map[string]string{"grafana_org_id": "4"})
+		require.NoError(t, err)
+
+		require.Len(t, r.written, 1)
+		assert.Equal(t, []prompb.Label{{Name: "__name__", Value: "test_metric"}, {Name: "grafana_org_id", Value: "3"}}, r.written[0].Labels)
+	})
+
+	t.Run("doesn't send empty results", func(t *testing.T) {
+		r := &receiver{}
+		w := newTestWriter(t, r)
+
+		err := w.Write(context.Background(), "test_metric", now, data.Frames{data.NewFrame("")}, 1, nil)
+		require.NoError(t, err)
+		assert.Empty(t, r.requests)
+	})
+
+	t.Run("fails when the endpoint rejects the series", func(t *testing.T) {
+		r := &receiver{status: http.StatusBadRequest}
+		w := newTestWriter(t, r)
+
+		frames := data.Frames{data.NewFrame("", data.NewField("value", nil, []float64{1}))}
+		err := w.Write(context.Background(), "test_metric", now, frames, 1, nil)
+		require.ErrorContains(t, err, "status 400")
+	})
+}
+
+func TestNewPrometheusWriter(t *testing.T) {
+	_, err := NewPrometheusWriter(setting.RecordingRuleSettings{URL: "not a url"}, log.NewNopLogger())
+	require.Error(t, err)
+}
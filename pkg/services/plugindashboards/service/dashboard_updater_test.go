@@ -436,6 +436,10 @@ func (s *pluginsSettingsServiceMock) UpdatePluginSetting(_ context.Context, _ *p
 	return s.err
 }
 
+func (s *pluginsSettingsServiceMock) DeletePluginSetting(_ context.Context, _ *pluginsettings.DeleteArgs) error {
+	return s.err
+}
+
 func (s *pluginsSettingsServiceMock) DecryptedValues(_ *pluginsettings.DTO) map[string]string {
 	return nil
 }
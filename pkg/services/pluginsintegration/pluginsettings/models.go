@@ -46,6 +46,11 @@ type UpdatePluginVersionArgs struct {
 	OrgID         int64
 }
 
+type DeleteArgs struct {
+	PluginID string
+	OrgID    int64
+}
+
 type GetArgs struct {
 	OrgID int64
 }
@@ -112,6 +112,30 @@ func (s *Service) UpdatePluginSettingPluginVersion(ctx context.Context, args *pl
 	})
 }
 
+func (s *Service) DeletePluginSetting(ctx context.Context, args *pluginsettings.DeleteArgs) error {
+	return s.db.WithTransactionalDbSession(ctx, func(sess *db.Session) error {
+		var pluginSetting pluginsettings.PluginSetting
+		exists, err := sess.Where("org_id=? and plugin_id=?", args.OrgID, args.PluginID).Get(&pluginSetting)
+		if err != nil || !exists {
+			return err
+		}
+
+		if _, err := sess.Exec("DELETE FROM plugin_setting WHERE org_id=? AND plugin_id=?", args.OrgID, args.PluginID); err != nil {
+			return err
+		}
+
+		if pluginSetting.Enabled {
+			// the plugin is disabled once its settings are gone
+			sess.PublishAfterCommit(&pluginsettings.PluginStateChangedEvent{
+				PluginId: args.PluginID,
+				OrgId:    args.OrgID,
+				Enabled:  false,
+			})
+		}
+		return nil
+	})
+}
+
 func (s *Service) DecryptedValues(ps *pluginsettings.DTO) map[string]string {
 	s.decryptionCache.Lock()
 	defer s.decryptionCache.Unlock()
@@ -267,6 +267,29 @@ func TestIntegrationPluginSettings(t *testing.T) {
 				require.Equal(t, cmd.PluginVersion, ps.PluginVersion)
 				require.False(t, ps.Pinned)
 			})
+
+			t.Run("DeletePluginSetting should delete plugin settings and publish PluginStateChangedEvent", func(t *testing.T) {
+				pluginStateChangedEvent = nil
+				err := psService.DeletePluginSetting(context.Background(), &pluginsettings.DeleteArgs{
+					OrgID:    cmd.OrgID,
+					PluginID: cmd.PluginID,
+				})
+				require.NoError(t, err)
+				require.NotNil(t, pluginStateChangedEvent)
+				require.False(t, pluginStateChangedEvent.Enabled)
+
+				_, err = psService.GetPluginSettingByPluginID(context.Background(), &pluginsettings.GetByPluginIDArgs{
+					OrgID:    cmd.OrgID,
+					PluginID: cmd.PluginID,
+				})
+				require.ErrorIs(t, err, pluginsettings.ErrPluginSettingNotFound)
+
+				err = psService.DeletePluginSetting(context.Background(), &pluginsettings.DeleteArgs{
+					OrgID:    cmd.OrgID,
+					PluginID: cmd.PluginID,
+				})
+				require.NoError(t, err)
+			})
 		})
 	})
 }
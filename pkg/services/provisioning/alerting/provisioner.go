@@ -7,6 +7,7 @@ import (
 	"github.com/grafana/grafana/pkg/infra/log"
 	"github.com/grafana/grafana/pkg/services/dashboards"
 	"github.com/grafana/grafana/pkg/services/ngalert/provisioning"
+	"github.com/grafana/grafana/pkg/services/provisioning/utils"
 )
 
 type ProvisionerConfig struct {
@@ -18,6 +19,10 @@ type ProvisionerConfig struct {
 	NotificiationPolicyService provisioning.NotificationPolicyService
 	MuteTimingService          provisioning.MuteTimingService
 	TemplateService            provisioning.TemplateService
+	AlertRuleTemplateService   provisioning.AlertRuleTemplateService
+	// Files restricts provisioning to the files with the given names. All files are
+	// provisioned when it is nil.
+	Files []string
 }
 
 func Provision(ctx context.Context, cfg ProvisionerConfig) error {
@@ -27,8 +32,23 @@ func Provision(ctx context.Context, cfg ProvisionerConfig) error {
 	if err != nil {
 		return err
 	}
+	if cfg.Files != nil {
+		changed := make([]*AlertingFile, 0, len(cfg.Files))
+		for _, file := range files {
+			if utils.ContainsFile(cfg.Files, file.Filename) {
+				changed = append(changed, file)
+			}
+		}
+		files = changed
+	}
 	logger.Info("starting to provision alerting")
 	logger.Debug("read all alerting files", "file_count", len(files))
+	// rule templates are provisioned first because the rules are instantiated from them
+	rtProvisioner := NewRuleTemplatesProvisioner(logger, cfg.AlertRuleTemplateService)
+	err = rtProvisioner.Provision(ctx, files)
+	if err != nil {
+		return fmt.Errorf("rule templates: %w", err)
+	}
 	ruleProvisioner := NewAlertRuleProvisioner(
 		logger,
 		cfg.DashboardService,
@@ -74,6 +94,10 @@ func Provision(ctx context.Context, cfg ProvisionerConfig) error {
 	if err != nil {
 		return fmt.Errorf("text templates: %w", err)
 	}
+	err = rtProvisioner.Unprovision(ctx, files)
+	if err != nil {
+		return fmt.Errorf("rule templates: %w", err)
+	}
 	logger.Info("finished to provision alerting")
 	return nil
 }
@@ -0,0 +1,62 @@
+package alerting
+
+import (
+	"context"
+	"errors"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/services/ngalert/provisioning"
+)
+
+type RuleTemplatesProvisioner interface {
+	Provision(ctx context.Context, files []*AlertingFile) error
+	Unprovision(ctx context.Context, files []*AlertingFile) error
+}
+
+type defaultRuleTemplatesProvisioner struct {
+	logger                   log.Logger
+	alertRuleTemplateService provisioning.AlertRuleTemplateService
+}
+
+func NewRuleTemplatesProvisioner(logger log.Logger,
+	alertRuleTemplateService provisioning.AlertRuleTemplateService) RuleTemplatesProvisioner {
+	return &defaultRuleTemplatesProvisioner{
+		logger:                   logger,
+		alertRuleTemplateService: alertRuleTemplateService,
+	}
+}
+
+func (c *defaultRuleTemplatesProvisioner) Provision(ctx context.Context,
+	files []*AlertingFile) error {
+	for _, file := range files {
+		for _, ruleTemplate := range file.RuleTemplates {
+			_, _, err := c.alertRuleTemplateService.GetTemplate(ctx, ruleTemplate.Template.OrgID, ruleTemplate.Template.UID)
+			if err != nil && !errors.Is(err, models.ErrAlertRuleTemplateNotFound) {
+				return err
+			}
+			if errors.Is(err, models.ErrAlertRuleTemplateNotFound) {
+				_, err = c.alertRuleTemplateService.CreateTemplate(ctx, ruleTemplate.Template, models.ProvenanceFile)
+			} else {
+				_, err = c.alertRuleTemplateService.UpdateTemplate(ctx, ruleTemplate.Template, models.ProvenanceFile, nil)
+			}
+			if err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func (c *defaultRuleTemplatesProvisioner) Unprovision(ctx context.Context,
+	files []*AlertingFile) error {
+	for _, file := range files {
+		for _, deleteRuleTemplate := range file.DeleteRuleTemplates {
+			err := c.alertRuleTemplateService.DeleteTemplate(ctx, deleteRuleTemplate.OrgID, deleteRuleTemplate.UID, models.ProvenanceFile)
+			if err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,116 @@
+package alerting
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/services/provisioning/values"
+)
+
+type RuleTemplateV1 struct {
+	OrgID      values.Int64Value         `json:"orgId" yaml:"orgId"`
+	UID        values.StringValue        `json:"uid" yaml:"uid"`
+	Title      values.StringValue        `json:"title" yaml:"title"`
+	Condition  values.StringValue        `json:"condition" yaml:"condition"`
+	Data       []QueryV1                 `json:"data" yaml:"data"`
+	Parameters []RuleTemplateParameterV1 `json:"parameters" yaml:"parameters"`
+	// Annotations are not interpolated so that they can contain the ${name} placeholders of the parameters.
+	Annotations values.StringMapValue `json:"annotations" yaml:"annotations"`
+}
+
+type RuleTemplateParameterV1 struct {
+	Name        values.StringValue  `json:"name" yaml:"name"`
+	Type        values.StringValue  `json:"type" yaml:"type"`
+	Description values.StringValue  `json:"description" yaml:"description"`
+	Default     *values.StringValue `json:"default" yaml:"default"`
+}
+
+func (v1 *RuleTemplateV1) mapToModel() (RuleTemplate, error) {
+	orgID := v1.OrgID.Value()
+	if orgID < 1 {
+		orgID = 1
+	}
+	template := models.AlertRuleTemplate{
+		OrgID:       orgID,
+		UID:         strings.TrimSpace(v1.UID.Value()),
+		Title:       v1.Title.Value(),
+		Condition:   v1.Condition.Value(),
+		Annotations: v1.Annotations.Raw,
+	}
+	if template.UID == "" {
+		return RuleTemplate{}, fmt.Errorf("rule template '%s' failed to parse: no UID set", template.Title)
+	}
+	for _, queryV1 := range v1.Data {
+		query, err := queryV1.mapToModel()
+		if err != nil {
+			return RuleTemplate{}, fmt.Errorf("rule template '%s' failed to parse: %w", template.UID, err)
+		}
+		template.Data = append(template.Data, query)
+	}
+	for _, paramV1 := range v1.Parameters {
+		param := models.AlertRuleTemplateParameter{
+			Name:        paramV1.Name.Value(),
+			Type:        models.AlertRuleTemplateParameterType(paramV1.Type.Value()),
+			Description: paramV1.Description.Value(),
+		}
+		if param.Type == "" {
+			param.Type = models.AlertRuleTemplateParameterTypeString
+		}
+		if paramV1.Default != nil {
+			def := paramV1.Default.Value()
+			param.Default = &def
+		}
+		template.Parameters = append(template.Parameters, param)
+	}
+	if err := template.Validate(); err != nil {
+		return RuleTemplate{}, fmt.Errorf("rule template '%s' failed to parse: %w", template.UID, err)
+	}
+	return RuleTemplate{Template: template}, nil
+}
+
+type RuleTemplate struct {
+	Template models.AlertRuleTemplate
+}
+
+type DeleteRuleTemplateV1 struct {
+	OrgID values.Int64Value  `json:"orgId" yaml:"orgId"`
+	UID   values.StringValue `json:"uid" yaml:"uid"`
+}
+
+func (v1 *DeleteRuleTemplateV1) mapToModel() (DeleteRuleTemplate, error) {
+	uid := strings.TrimSpace(v1.UID.Value())
+	if uid == "" {
+		return DeleteRuleTemplate{}, errors.New("delete rule template missing uid")
+	}
+	orgID := v1.OrgID.Value()
+	if orgID < 1 {
+		orgID = 1
+	}
+	return DeleteRuleTemplate{
+		OrgID: orgID,
+		UID:   uid,
+	}, nil
+}
+
+type DeleteRuleTemplate struct {
+	OrgID int64
+	UID   string
+}
+
+type RuleTemplateRefV1 struct {
+	UID        values.StringValue    `json:"uid" yaml:"uid"`
+	Parameters values.StringMapValue `json:"parameters" yaml:"parameters"`
+}
+
+func (v1 *RuleTemplateRefV1) mapToModel() (models.AlertRuleTemplateRef, error) {
+	ref := models.AlertRuleTemplateRef{
+		UID:        strings.TrimSpace(v1.UID.Value()),
+		Parameters: v1.Parameters.Value(),
+	}
+	if ref.UID == "" {
+		return models.AlertRuleTemplateRef{}, errors.New("template has no uid set")
+	}
+	return ref, nil
+}
@@ -49,15 +49,16 @@ func (prov *defaultAlertRuleProvisioner) Provision(ctx context.Context,
 				"folder", group.FolderTitle,
 				"folderUID", folderUID,
 				"name", group.Title)
-			for _, rule := range group.Rules {
+			for i, rule := range group.Rules {
 				rule.NamespaceUID = folderUID
 				rule.RuleGroup = group.Title
+				rule.RuleGroupIndex = i + 1
 				err = prov.provisionRule(ctx, group.OrgID, rule)
 				if err != nil {
 					return err
 				}
 			}
-			err = prov.ruleService.UpdateRuleGroup(ctx, group.OrgID, folderUID, group.Title, group.Interval)
+			err = prov.ruleService.UpdateRuleGroup(ctx, group.OrgID, folderUID, group.Title, group.Interval, group.Sequential)
 			if err != nil {
 				return err
 			}
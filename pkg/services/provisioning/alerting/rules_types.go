@@ -24,11 +24,12 @@ type RuleDeleteV1 struct {
 }
 
 type AlertRuleGroupV1 struct {
-	OrgID    values.Int64Value  `json:"orgId" yaml:"orgId"`
-	Name     values.StringValue `json:"name" yaml:"name"`
-	Folder   values.StringValue `json:"folder" yaml:"folder"`
-	Interval values.StringValue `json:"interval" yaml:"interval"`
-	Rules    []AlertRuleV1      `json:"rules" yaml:"rules"`
+	OrgID      values.Int64Value  `json:"orgId" yaml:"orgId"`
+	Name       values.StringValue `json:"name" yaml:"name"`
+	Folder     values.StringValue `json:"folder" yaml:"folder"`
+	Interval   values.StringValue `json:"interval" yaml:"interval"`
+	Sequential values.BoolValue   `json:"sequential" yaml:"sequential"`
+	Rules      []AlertRuleV1      `json:"rules" yaml:"rules"`
 }
 
 func (ruleGroupV1 *AlertRuleGroupV1) MapToModel() (models.AlertRuleGroupWithFolderTitle, error) {
@@ -46,6 +47,7 @@ func (ruleGroupV1 *AlertRuleGroupV1) MapToModel() (models.AlertRuleGroupWithFold
 		return models.AlertRuleGroupWithFolderTitle{}, err
 	}
 	ruleGroup.Interval = int64(time.Duration(interval).Seconds())
+	ruleGroup.Sequential = ruleGroupV1.Sequential.Value()
 	ruleGroup.FolderTitle = ruleGroupV1.Folder.Value()
 	if strings.TrimSpace(ruleGroup.FolderTitle) == "" {
 		return models.AlertRuleGroupWithFolderTitle{}, errors.New("rule group has no folder set")
@@ -61,18 +63,74 @@ func (ruleGroupV1 *AlertRuleGroupV1) MapToModel() (models.AlertRuleGroupWithFold
 }
 
 type AlertRuleV1 struct {
-	UID          values.StringValue    `json:"uid" yaml:"uid"`
-	Title        values.StringValue    `json:"title" yaml:"title"`
-	Condition    values.StringValue    `json:"condition" yaml:"condition"`
-	Data         []QueryV1             `json:"data" yaml:"data"`
-	DashboardUID values.StringValue    `json:"dasboardUid" yaml:"dashboardUid"`
-	PanelID      values.Int64Value     `json:"panelId" yaml:"panelId"`
-	NoDataState  values.StringValue    `json:"noDataState" yaml:"noDataState"`
-	ExecErrState values.StringValue    `json:"execErrState" yaml:"execErrState"`
-	For          values.StringValue    `json:"for" yaml:"for"`
-	Annotations  values.StringMapValue `json:"annotations" yaml:"annotations"`
-	Labels       values.StringMapValue `json:"labels" yaml:"labels"`
-	IsPaused     values.BoolValue      `json:"isPaused" yaml:"isPaused"`
+	UID           values.StringValue    `json:"uid" yaml:"uid"`
+	Title         values.StringValue    `json:"title" yaml:"title"`
+	Condition     values.StringValue    `json:"condition" yaml:"condition"`
+	Data          []QueryV1             `json:"data" yaml:"data"`
+	DashboardUID  values.StringValue    `json:"dasboardUid" yaml:"dashboardUid"`
+	PanelID       values.Int64Value     `json:"panelId" yaml:"panelId"`
+	NoDataState   values.StringValue    `json:"noDataState" yaml:"noDataState"`
+	ExecErrState  values.StringValue    `json:"execErrState" yaml:"execErrState"`
+	For           values.StringValue    `json:"for" yaml:"for"`
+	KeepFiringFor values.StringValue    `json:"keepFiringFor" yaml:"keepFiringFor"`
+	Annotations   values.StringMapValue `json:"annotations" yaml:"annotations"`
+	Labels        values.StringMapValue `json:"labels" yaml:"labels"`
+	IsPaused      values.BoolValue      `json:"isPaused" yaml:"isPaused"`
+	Record        *RecordV1             `json:"record" yaml:"record"`
+	// NotificationSettings sends the alerts of the rule directly to a contact point.
+	NotificationSettings *NotificationSettingsV1 `json:"notification_settings" yaml:"notification_settings"`
+	// Template instantiates the condition, the data and the annotations of the rule from an alert rule template.
+	Template *RuleTemplateRefV1 `json:"template" yaml:"template"`
+}
+
+type RecordV1 struct {
+	Metric values.StringValue `json:"metric" yaml:"metric"`
+	From   values.StringValue `json:"from" yaml:"from"`
+}
+
+type NotificationSettingsV1 struct {
+	Receiver          values.StringValue   `json:"receiver" yaml:"receiver"`
+	GroupBy           []values.StringValue `json:"group_by" yaml:"group_by"`
+	GroupWait         values.StringValue   `json:"group_wait" yaml:"group_wait"`
+	GroupInterval     values.StringValue   `json:"group_interval" yaml:"group_interval"`
+	RepeatInterval    values.StringValue   `json:"repeat_interval" yaml:"repeat_interval"`
+	MuteTimeIntervals []values.StringValue `json:"mute_time_intervals" yaml:"mute_time_intervals"`
+}
+
+func (settings *NotificationSettingsV1) mapToModel() (models.NotificationSettings, error) {
+	result := models.NotificationSettings{
+		Receiver: settings.Receiver.Value(),
+	}
+	if result.Receiver == "" {
+		return models.NotificationSettings{}, fmt.Errorf("notification settings have no receiver set")
+	}
+	for _, l := range settings.GroupBy {
+		result.GroupBy = append(result.GroupBy, l.Value())
+	}
+	for _, mt := range settings.MuteTimeIntervals {
+		result.MuteTimeIntervals = append(result.MuteTimeIntervals, mt.Value())
+	}
+	parseDuration := func(name string, value values.StringValue) (*model.Duration, error) {
+		if value.Value() == "" {
+			return nil, nil
+		}
+		d, err := model.ParseDuration(value.Value())
+		if err != nil {
+			return nil, fmt.Errorf("invalid %s of the notification settings: %w", name, err)
+		}
+		return &d, nil
+	}
+	var err error
+	if result.GroupWait, err = parseDuration("group_wait", settings.GroupWait); err != nil {
+		return models.NotificationSettings{}, err
+	}
+	if result.GroupInterval, err = parseDuration("group_interval", settings.GroupInterval); err != nil {
+		return models.NotificationSettings{}, err
+	}
+	if result.RepeatInterval, err = parseDuration("repeat_interval", settings.RepeatInterval); err != nil {
+		return models.NotificationSettings{}, err
+	}
+	return result, nil
 }
 
 func (rule *AlertRuleV1) mapToModel(orgID int64) (models.AlertRule, error) {
@@ -86,11 +144,27 @@ func (rule *AlertRuleV1) mapToModel(orgID int64) (models.AlertRule, error) {
 		return models.AlertRule{}, fmt.Errorf("rule '%s' failed to parse: no UID set", alertRule.Title)
 	}
 	alertRule.OrgID = orgID
-	duration, err := model.ParseDuration(rule.For.Value())
-	if err != nil {
-		return models.AlertRule{}, fmt.Errorf("rule '%s' failed to parse: %w", alertRule.Title, err)
+	if rule.Record != nil {
+		alertRule.Record = &models.Record{
+			Metric: rule.Record.Metric.Value(),
+			From:   rule.Record.From.Value(),
+		}
+	}
+	// recording rules don't have a pending period
+	if alertRule.Record == nil || rule.For.Value() != "" {
+		duration, err := model.ParseDuration(rule.For.Value())
+		if err != nil {
+			return models.AlertRule{}, fmt.Errorf("rule '%s' failed to parse: %w", alertRule.Title, err)
+		}
+		alertRule.For = time.Duration(duration)
+	}
+	if rule.KeepFiringFor.Value() != "" {
+		duration, err := model.ParseDuration(rule.KeepFiringFor.Value())
+		if err != nil {
+			return models.AlertRule{}, fmt.Errorf("rule '%s' failed to parse: %w", alertRule.Title, err)
+		}
+		alertRule.KeepFiringFor = time.Duration(duration)
 	}
-	alertRule.For = time.Duration(duration)
 	dashboardUID := rule.DashboardUID.Value()
 	alertRule.DashboardUID = &dashboardUID
 	panelID := rule.PanelID.Value()
@@ -114,7 +188,18 @@ func (rule *AlertRuleV1) mapToModel(orgID int64) (models.AlertRule, error) {
 	}
 	alertRule.NoDataState = noDataState
 	alertRule.Condition = rule.Condition.Value()
-	if alertRule.Condition == "" {
+	if alertRule.Condition == "" && alertRule.Record != nil {
+		alertRule.Condition = alertRule.Record.From
+	}
+	if rule.Template != nil {
+		ref, err := rule.Template.mapToModel()
+		if err != nil {
+			return models.AlertRule{}, fmt.Errorf("rule '%s' failed to parse: %w", alertRule.Title, err)
+		}
+		alertRule.Template = &ref
+	}
+	// the condition and the data of rules instantiated from a template are set by the template
+	if alertRule.Condition == "" && alertRule.Template == nil {
 		return models.AlertRule{}, fmt.Errorf("rule '%s' failed to parse: no condition set", alertRule.Title)
 	}
 	alertRule.Annotations = rule.Annotations.Raw
@@ -126,10 +211,17 @@ func (rule *AlertRuleV1) mapToModel(orgID int64) (models.AlertRule, error) {
 		}
 		alertRule.Data = append(alertRule.Data, query)
 	}
-	if len(alertRule.Data) == 0 {
+	if len(alertRule.Data) == 0 && alertRule.Template == nil {
 		return models.AlertRule{}, fmt.Errorf("rule '%s' failed to parse: no data set", alertRule.Title)
 	}
 	alertRule.IsPaused = rule.IsPaused.Value()
+	if rule.NotificationSettings != nil {
+		settings, err := rule.NotificationSettings.mapToModel()
+		if err != nil {
+			return models.AlertRule{}, fmt.Errorf("rule '%s' failed to parse: %w", alertRule.Title, err)
+		}
+		alertRule.NotificationSettings = &settings
+	}
 	return alertRule, nil
 }
 
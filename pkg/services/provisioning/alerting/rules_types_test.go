@@ -4,6 +4,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/prometheus/common/model"
 	"github.com/stretchr/testify/require"
 	"gopkg.in/yaml.v3"
 
@@ -63,6 +64,20 @@ func TestRuleGroup(t *testing.T) {
 		require.NoError(t, err)
 		require.Equal(t, int64(48*time.Hour/time.Second), rgMapped.Interval)
 	})
+	t.Run("a rule group can be sequential", func(t *testing.T) {
+		rg := validRuleGroupV1(t)
+		rgMapped, err := rg.MapToModel()
+		require.NoError(t, err)
+		require.False(t, rgMapped.Sequential)
+
+		var sequential values.BoolValue
+		err = yaml.Unmarshal([]byte("true"), &sequential)
+		require.NoError(t, err)
+		rg.Sequential = sequential
+		rgMapped, err = rg.MapToModel()
+		require.NoError(t, err)
+		require.True(t, rgMapped.Sequential)
+	})
 	t.Run("a rule group with an empty org id should default to 1", func(t *testing.T) {
 		rg := validRuleGroupV1(t)
 		rg.OrgID = values.Int64Value{}
@@ -125,6 +140,25 @@ func TestRules(t *testing.T) {
 		require.NoError(t, err)
 		require.Equal(t, 48*time.Hour, ruleMapped.For)
 	})
+	t.Run("a rule with a keep firing for duration should map it", func(t *testing.T) {
+		rule := validRuleV1(t)
+		keepFiringFor := values.StringValue{}
+		err := yaml.Unmarshal([]byte("5m"), &keepFiringFor)
+		require.NoError(t, err)
+		rule.KeepFiringFor = keepFiringFor
+		ruleMapped, err := rule.mapToModel(1)
+		require.NoError(t, err)
+		require.Equal(t, 5*time.Minute, ruleMapped.KeepFiringFor)
+	})
+	t.Run("a rule with an invalid keep firing for duration should error", func(t *testing.T) {
+		rule := validRuleV1(t)
+		keepFiringFor := values.StringValue{}
+		err := yaml.Unmarshal([]byte("5x"), &keepFiringFor)
+		require.NoError(t, err)
+		rule.KeepFiringFor = keepFiringFor
+		_, err = rule.mapToModel(1)
+		require.Error(t, err)
+	})
 	t.Run("a rule with out a condition should error", func(t *testing.T) {
 		rule := validRuleV1(t)
 		rule.Condition = values.StringValue{}
@@ -187,6 +221,64 @@ func TestRules(t *testing.T) {
 		require.NoError(t, err)
 		require.Equal(t, ruleMapped.NoDataState, models.NoData)
 	})
+	t.Run("a recording rule should map the record", func(t *testing.T) {
+		rule := validRuleV1(t)
+		var metric, from values.StringValue
+		require.NoError(t, yaml.Unmarshal([]byte("test_metric"), &metric))
+		require.NoError(t, yaml.Unmarshal([]byte("A"), &from))
+		rule.Record = &RecordV1{Metric: metric, From: from}
+		rule.For = values.StringValue{}
+		rule.Condition = values.StringValue{}
+		ruleMapped, err := rule.mapToModel(1)
+		require.NoError(t, err)
+		require.Equal(t, &models.Record{Metric: "test_metric", From: "A"}, ruleMapped.Record)
+		require.Equal(t, "A", ruleMapped.Condition)
+		require.Zero(t, ruleMapped.For)
+	})
+	t.Run("a rule with notification settings should map the settings", func(t *testing.T) {
+		rule := validRuleV1(t)
+		var receiver, groupBy, repeatInterval, muteTiming values.StringValue
+		require.NoError(t, yaml.Unmarshal([]byte("slack"), &receiver))
+		require.NoError(t, yaml.Unmarshal([]byte("team"), &groupBy))
+		require.NoError(t, yaml.Unmarshal([]byte("1h"), &repeatInterval))
+		require.NoError(t, yaml.Unmarshal([]byte("weekends"), &muteTiming))
+		rule.NotificationSettings = &NotificationSettingsV1{
+			Receiver:          receiver,
+			GroupBy:           []values.StringValue{groupBy},
+			RepeatInterval:    repeatInterval,
+			MuteTimeIntervals: []values.StringValue{muteTiming},
+		}
+		ruleMapped, err := rule.mapToModel(1)
+		require.NoError(t, err)
+		hour := model.Duration(time.Hour)
+		require.Equal(t, &models.NotificationSettings{
+			Receiver:          "slack",
+			GroupBy:           []string{"team"},
+			RepeatInterval:    &hour,
+			MuteTimeIntervals: []string{"weekends"},
+		}, ruleMapped.NotificationSettings)
+	})
+	t.Run("a rule with notification settings without a receiver should error", func(t *testing.T) {
+		rule := validRuleV1(t)
+		rule.NotificationSettings = &NotificationSettingsV1{}
+		_, err := rule.mapToModel(1)
+		require.Error(t, err)
+	})
+	t.Run("a rule with a template should not require condition and data", func(t *testing.T) {
+		rule := validRuleV1(t)
+		rule.Condition = values.StringValue{}
+		rule.Data = nil
+		require.NoError(t, yaml.Unmarshal([]byte("uid: high_cpu\nparameters:\n  service: checkout"), &rule.Template))
+		ruleMapped, err := rule.mapToModel(1)
+		require.NoError(t, err)
+		require.Equal(t, &models.AlertRuleTemplateRef{UID: "high_cpu", Parameters: map[string]string{"service": "checkout"}}, ruleMapped.Template)
+	})
+	t.Run("a rule with a template without a uid should error", func(t *testing.T) {
+		rule := validRuleV1(t)
+		rule.Template = &RuleTemplateRefV1{}
+		_, err := rule.mapToModel(1)
+		require.Error(t, err)
+	})
 }
 
 func validRuleGroupV1(t *testing.T) AlertRuleGroupV1 {
@@ -238,3 +330,46 @@ func validRuleV1(t *testing.T) AlertRuleV1 {
 		Data:      []QueryV1{{}},
 	}
 }
+
+func TestRuleTemplates(t *testing.T) {
+	t.Run("a valid rule template should map the template and keep the placeholders", func(t *testing.T) {
+		var template RuleTemplateV1
+		require.NoError(t, yaml.Unmarshal([]byte(`
+uid: high_cpu
+title: High CPU usage
+condition: A
+data:
+  - refId: A
+    datasourceUid: prometheus
+    model:
+      expr: cpu{service="${service}"} > ${threshold}
+annotations:
+  summary: CPU of ${service}
+parameters:
+  - name: service
+  - name: threshold
+    type: number
+    default: "80"
+`), &template))
+		mapped, err := template.mapToModel()
+		require.NoError(t, err)
+		require.Equal(t, int64(1), mapped.Template.OrgID)
+		require.Equal(t, "high_cpu", mapped.Template.UID)
+		require.Equal(t, map[string]string{"summary": "CPU of ${service}"}, mapped.Template.Annotations)
+		require.JSONEq(t, `{"expr":"cpu{service=\"${service}\"} > ${threshold}"}`, string(mapped.Template.Data[0].Model))
+		require.Equal(t, models.AlertRuleTemplateParameterTypeString, mapped.Template.Parameters[0].Type)
+		require.Equal(t, "80", *mapped.Template.Parameters[1].Default)
+	})
+	t.Run("a rule template without a uid should error", func(t *testing.T) {
+		var template RuleTemplateV1
+		require.NoError(t, yaml.Unmarshal([]byte("title: High CPU usage\ncondition: A"), &template))
+		_, err := template.mapToModel()
+		require.Error(t, err)
+	})
+	t.Run("a rule template that fails validation should error", func(t *testing.T) {
+		var template RuleTemplateV1
+		require.NoError(t, yaml.Unmarshal([]byte("uid: high_cpu\ntitle: High CPU usage\ncondition: A"), &template))
+		_, err := template.mapToModel()
+		require.ErrorIs(t, err, models.ErrAlertRuleTemplateFailedValidation)
+	})
+}
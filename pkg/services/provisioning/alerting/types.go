@@ -26,6 +26,8 @@ type AlertingFile struct {
 	DeleteMuteTimes     []DeleteMuteTime
 	Templates           []Template
 	DeleteTemplates     []DeleteTemplate
+	RuleTemplates       []RuleTemplate
+	DeleteRuleTemplates []DeleteRuleTemplate
 }
 
 type AlertingFileV1 struct {
@@ -41,6 +43,8 @@ type AlertingFileV1 struct {
 	DeleteMuteTimes     []DeleteMuteTimeV1      `json:"deleteMuteTimes" yaml:"deleteMuteTimes"`
 	Templates           []TemplateV1            `json:"templates" yaml:"templates"`
 	DeleteTemplates     []DeleteTemplateV1      `json:"deleteTemplates" yaml:"deleteTemplates"`
+	RuleTemplates       []RuleTemplateV1        `json:"ruleTemplates" yaml:"ruleTemplates"`
+	DeleteRuleTemplates []DeleteRuleTemplateV1  `json:"deleteRuleTemplates" yaml:"deleteRuleTemplates"`
 }
 
 func (fileV1 *AlertingFileV1) MapToModel() (AlertingFile, error) {
@@ -61,9 +65,30 @@ func (fileV1 *AlertingFileV1) MapToModel() (AlertingFile, error) {
 	if err := fileV1.mapTemplates(&alertingFile); err != nil {
 		return AlertingFile{}, fmt.Errorf("failure parsing templates: %w", err)
 	}
+	if err := fileV1.mapRuleTemplates(&alertingFile); err != nil {
+		return AlertingFile{}, fmt.Errorf("failure parsing rule templates: %w", err)
+	}
 	return alertingFile, nil
 }
 
+func (fileV1 *AlertingFileV1) mapRuleTemplates(alertingFile *AlertingFile) error {
+	for _, rtV1 := range fileV1.RuleTemplates {
+		ruleTemplate, err := rtV1.mapToModel()
+		if err != nil {
+			return err
+		}
+		alertingFile.RuleTemplates = append(alertingFile.RuleTemplates, ruleTemplate)
+	}
+	for _, deleteV1 := range fileV1.DeleteRuleTemplates {
+		delReq, err := deleteV1.mapToModel()
+		if err != nil {
+			return err
+		}
+		alertingFile.DeleteRuleTemplates = append(alertingFile.DeleteRuleTemplates, delReq)
+	}
+	return nil
+}
+
 func (fileV1 *AlertingFileV1) mapTemplates(alertingFile *AlertingFile) error {
 	for _, ttV1 := range fileV1.Templates {
 		alertingFile.Templates = append(alertingFile.Templates, ttV1.mapToModel())
@@ -0,0 +1,108 @@
+package alerting
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/grafana/grafana/pkg/expr"
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/services/provisioning/utils"
+	"github.com/grafana/grafana/pkg/util"
+)
+
+// ValidationResult is the outcome of validating the alerting provisioning files of a directory.
+type ValidationResult struct {
+	// DatasourceReferences contains the data sources queried by the provisioned alert rules.
+	DatasourceReferences []utils.DatasourceReference
+	// Errors contains every problem found, each prefixed with the file it was found in.
+	Errors []error
+}
+
+// Validate reads all alerting provisioning files in path with the config reader used at runtime
+// and validates the rules without accessing the database. Rule group intervals are validated
+// against baseInterval, the scheduler interval.
+func Validate(ctx context.Context, path string, baseInterval time.Duration) ValidationResult {
+	result := ValidationResult{}
+	if _, err := os.Stat(path); os.IsNotExist(err) {
+		return result
+	}
+
+	cr := newRulesConfigReader(log.New("provisioning.alerting"))
+	files, err := cr.readConfig(ctx, path)
+	if err != nil {
+		result.Errors = append(result.Errors, err)
+		return result
+	}
+
+	ruleUIDs := map[models.AlertRuleKey]string{}
+	for _, file := range files {
+		errorf := func(format string, args ...any) {
+			result.Errors = append(result.Errors, fmt.Errorf("%s: %s", file.Filename, fmt.Sprintf(format, args...)))
+		}
+
+		for _, group := range file.Groups {
+			if err := models.ValidateRuleGroupInterval(group.Interval, int64(baseInterval.Seconds())); err != nil {
+				errorf("rule group %q: %s", group.Title, err)
+			}
+
+			for _, rule := range group.Rules {
+				rule := rule
+				location := fmt.Sprintf("%s: rule %q", file.Filename, rule.UID)
+				for _, err := range validateRule(&rule) {
+					errorf("rule %q: %s", rule.UID, err)
+				}
+
+				key := rule.GetKey()
+				if other, ok := ruleUIDs[key]; ok {
+					errorf("rule %q: uid is already used in %s", rule.UID, other)
+				}
+				ruleUIDs[key] = file.Filename
+
+				for _, query := range rule.Data {
+					if expr.IsDataSource(query.DatasourceUID) {
+						continue
+					}
+					result.DatasourceReferences = append(result.DatasourceReferences, utils.DatasourceReference{
+						OrgID:    rule.OrgID,
+						UID:      query.DatasourceUID,
+						Location: location,
+					})
+				}
+			}
+		}
+	}
+
+	return result
+}
+
+func validateRule(rule *models.AlertRule) []error {
+	var errs []error
+	if err := util.ValidateUID(rule.UID); err != nil {
+		errs = append(errs, fmt.Errorf("invalid uid: %w", err))
+	}
+
+	refIDs := map[string]struct{}{}
+	for i := range rule.Data {
+		query := rule.Data[i]
+		if query.DatasourceUID == "" {
+			errs = append(errs, fmt.Errorf("query %q has no datasourceUid set", query.RefID))
+		}
+		if _, ok := refIDs[query.RefID]; ok {
+			errs = append(errs, fmt.Errorf("refId %q is used more than once", query.RefID))
+		}
+		refIDs[query.RefID] = struct{}{}
+
+		if err := query.PreSave(); err != nil {
+			errs = append(errs, fmt.Errorf("invalid query %q: %w", query.RefID, err))
+		}
+	}
+
+	if _, ok := refIDs[rule.Condition]; !ok {
+		errs = append(errs, fmt.Errorf("condition %q does not match any refId", rule.Condition))
+	}
+
+	return errs
+}
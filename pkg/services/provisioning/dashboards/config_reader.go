@@ -17,6 +17,7 @@ import (
 
 type configReader struct {
 	path       string
+	dataPath   string
 	log        log.Logger
 	orgService org.Service
 }
@@ -101,9 +102,11 @@ func (cr *configReader) readConfig(ctx context.Context) ([]*config, error) {
 		}
 
 		if dashboard.Type == "" {
-			dashboard.Type = "file"
+			dashboard.Type = typeFile
 		}
 
+		dashboard.DataPath = cr.dataPath
+
 		if dashboard.UpdateIntervalSeconds == 0 {
 			dashboard.UpdateIntervalSeconds = 10
 		}
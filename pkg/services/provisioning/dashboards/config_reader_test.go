@@ -36,13 +36,14 @@ func TestDashboardsAsConfig(t *testing.T) {
 		})
 
 		t.Run("default values should be applied", func(t *testing.T) {
-			cfgProvider := configReader{path: appliedDefaults, log: logger, orgService: orgFake}
+			cfgProvider := configReader{path: appliedDefaults, dataPath: "/var/lib/grafana", log: logger, orgService: orgFake}
 			cfg, err := cfgProvider.readConfig(context.Background())
 			require.NoError(t, err)
 
 			require.Equal(t, "file", cfg[0].Type)
 			require.Equal(t, int64(1), cfg[0].OrgID)
 			require.Equal(t, int64(10), cfg[0].UpdateIntervalSeconds)
+			require.Equal(t, "/var/lib/grafana", cfg[0].DataPath)
 		})
 
 		t.Run("Can read config file version 1 format", func(t *testing.T) {
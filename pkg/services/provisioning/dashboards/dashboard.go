@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"os"
+	"time"
 
 	"github.com/grafana/grafana/pkg/infra/log"
 	"github.com/grafana/grafana/pkg/services/dashboards"
@@ -17,13 +18,14 @@ type DashboardProvisioner interface {
 	HasDashboardSources() bool
 	Provision(ctx context.Context) error
 	PollChanges(ctx context.Context)
+	WatchChanges(ctx context.Context, debounce time.Duration)
 	GetProvisionerResolvedPath(name string) string
 	GetAllowUIUpdatesFromConfig(name string) bool
 	CleanUpOrphanedDashboards(ctx context.Context)
 }
 
 // DashboardProvisionerFactory creates DashboardProvisioners based on input
-type DashboardProvisionerFactory func(context.Context, string, dashboards.DashboardProvisioningService, org.Service, utils.DashboardStore) (DashboardProvisioner, error)
+type DashboardProvisionerFactory func(context.Context, string, string, dashboards.DashboardProvisioningService, org.Service, utils.DashboardStore) (DashboardProvisioner, error)
 
 // Provisioner is responsible for syncing dashboard from disk to Grafana's database.
 type Provisioner struct {
@@ -38,10 +40,11 @@ func (provider *Provisioner) HasDashboardSources() bool {
 	return len(provider.fileReaders) > 0
 }
 
-// New returns a new DashboardProvisioner
-func New(ctx context.Context, configDirectory string, provisioner dashboards.DashboardProvisioningService, orgService org.Service, dashboardStore utils.DashboardStore) (DashboardProvisioner, error) {
+// New returns a new DashboardProvisioner. The dashboards of remote sources are
+// synchronized into dataPath.
+func New(ctx context.Context, configDirectory string, dataPath string, provisioner dashboards.DashboardProvisioningService, orgService org.Service, dashboardStore utils.DashboardStore) (DashboardProvisioner, error) {
 	logger := log.New("provisioning.dashboard")
-	cfgReader := &configReader{path: configDirectory, log: logger, orgService: orgService}
+	cfgReader := &configReader{path: configDirectory, dataPath: dataPath, log: logger, orgService: orgService}
 	configs, err := cfgReader.readConfig(ctx)
 	if err != nil {
 		return nil, fmt.Errorf("%v: %w", "Failed to read dashboards config", err)
@@ -105,6 +108,16 @@ func (provider *Provisioner) PollChanges(ctx context.Context) {
 	go provider.duplicateValidator.Run(ctx)
 }
 
+// WatchChanges starts watching dashboard definition files for changes and applies only the changed files.
+// Providers that cannot be watched, like remote sources, are polled instead.
+func (provider *Provisioner) WatchChanges(ctx context.Context, debounce time.Duration) {
+	for _, reader := range provider.fileReaders {
+		go reader.watchChanges(ctx, debounce)
+	}
+
+	go provider.duplicateValidator.Run(ctx)
+}
+
 // GetProvisionerResolvedPath returns resolved path for the specified provisioner name. Can be used to generate
 // relative path to provisioning file from its external_id.
 func (provider *Provisioner) GetProvisionerResolvedPath(name string) string {
@@ -133,7 +146,7 @@ func getFileReaders(
 
 	for _, config := range configs {
 		switch config.Type {
-		case "file":
+		case typeFile, typeGit, typeHTTP:
 			fileReader, err := NewDashboardFileReader(config, logger.New("type", config.Type, "name", config.Name), service, store)
 			if err != nil {
 				return nil, fmt.Errorf("failed to create file reader for config %v: %w", config.Name, err)
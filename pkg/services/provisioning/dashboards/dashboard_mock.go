@@ -1,11 +1,15 @@
 package dashboards
 
-import "context"
+import (
+	"context"
+	"time"
+)
 
 // Calls is a mock implementation of the provisioner interface
 type calls struct {
 	Provision                   []any
 	PollChanges                 []any
+	WatchChanges                []any
 	GetProvisionerResolvedPath  []any
 	GetAllowUIUpdatesFromConfig []any
 }
@@ -15,6 +19,7 @@ type ProvisionerMock struct {
 	Calls                           *calls
 	ProvisionFunc                   func(ctx context.Context) error
 	PollChangesFunc                 func(ctx context.Context)
+	WatchChangesFunc                func(ctx context.Context, debounce time.Duration)
 	GetProvisionerResolvedPathFunc  func(name string) string
 	GetAllowUIUpdatesFromConfigFunc func(name string) bool
 }
@@ -47,6 +52,14 @@ func (dpm *ProvisionerMock) PollChanges(ctx context.Context) {
 	}
 }
 
+// WatchChanges is a mock implementation of `Provisioner.WatchChanges`
+func (dpm *ProvisionerMock) WatchChanges(ctx context.Context, debounce time.Duration) {
+	dpm.Calls.WatchChanges = append(dpm.Calls.WatchChanges, ctx)
+	if dpm.WatchChangesFunc != nil {
+		dpm.WatchChangesFunc(ctx, debounce)
+	}
+}
+
 // GetProvisionerResolvedPath is a mock implementation of `Provisioner.GetProvisionerResolvedPath`
 func (dpm *ProvisionerMock) GetProvisionerResolvedPath(name string) string {
 	dpm.Calls.GetProvisionerResolvedPath = append(dpm.Calls.GetProvisionerResolvedPath, name)
@@ -34,6 +34,7 @@ type FileReader struct {
 	dashboardProvisioningService dashboards.DashboardProvisioningService
 	dashboardStore               utils.DashboardStore
 	FoldersFromFilesStructure    bool
+	source                       remoteSource
 
 	mux                     sync.RWMutex
 	usageTracker            *usageTracker
@@ -42,15 +43,26 @@ type FileReader struct {
 
 // NewDashboardFileReader returns a new filereader based on `config`
 func NewDashboardFileReader(cfg *config, log log.Logger, service dashboards.DashboardProvisioningService, dashboardStore utils.DashboardStore) (*FileReader, error) {
+	source, err := newRemoteSource(cfg, log)
+	if err != nil {
+		return nil, err
+	}
+
 	var path string
-	path, ok := cfg.Options["path"].(string)
-	if !ok {
-		path, ok = cfg.Options["folder"].(string)
+	if source != nil {
+		// remote sources are synchronized to a local directory which is then read like any other path
+		path = source.dir()
+	} else {
+		var ok bool
+		path, ok = cfg.Options["path"].(string)
 		if !ok {
-			return nil, fmt.Errorf("failed to load dashboards, path param is not a string")
-		}
+			path, ok = cfg.Options["folder"].(string)
+			if !ok {
+				return nil, fmt.Errorf("failed to load dashboards, path param is not a string")
+			}
 
-		log.Warn("[Deprecated] The folder property is deprecated. Please use path instead.")
+			log.Warn("[Deprecated] The folder property is deprecated. Please use path instead.")
+		}
 	}
 
 	foldersFromFilesStructure, _ := cfg.Options["foldersFromFilesStructure"].(bool)
@@ -65,10 +77,25 @@ func NewDashboardFileReader(cfg *config, log log.Logger, service dashboards.Dash
 		dashboardProvisioningService: service,
 		dashboardStore:               dashboardStore,
 		FoldersFromFilesStructure:    foldersFromFilesStructure,
+		source:                       source,
 		usageTracker:                 newUsageTracker(),
 	}, nil
 }
 
+// watchChanges watches the dashboards path for changes and only reprocesses the files that changed.
+// It falls back to pollChanges for remote sources or if the path cannot be watched.
+func (fr *FileReader) watchChanges(ctx context.Context, debounce time.Duration) {
+	if fr.source != nil {
+		fr.pollChanges(ctx)
+		return
+	}
+
+	if err := utils.WatchDirectory(ctx, fr.log, fr.resolvedPath(), debounce, fr.applyFileChanges); err != nil {
+		fr.log.Warn("Failed to watch dashboards path, falling back to polling", "path", fr.Path, "error", err)
+		fr.pollChanges(ctx)
+	}
+}
+
 // pollChanges periodically runs walkDisk based on interval specified in the config.
 func (fr *FileReader) pollChanges(ctx context.Context) {
 	ticker := time.NewTicker(time.Duration(int64(time.Second) * fr.Cfg.UpdateIntervalSeconds))
@@ -88,6 +115,14 @@ func (fr *FileReader) pollChanges(ctx context.Context) {
 // and applies any change to the database.
 func (fr *FileReader) walkDisk(ctx context.Context) error {
 	fr.log.Debug("Start walking disk", "path", fr.Path)
+	if fr.source != nil {
+		if err := fr.source.sync(ctx); err != nil {
+			// keep provisioning from the last synchronized state, if any, instead of
+			// deleting dashboards because the remote is temporarily unavailable
+			fr.log.Warn("Failed to synchronize dashboards from remote source", "error", err)
+		}
+	}
+
 	resolvedPath := fr.resolvedPath()
 	if _, err := os.Stat(resolvedPath); err != nil {
 		return err
@@ -123,6 +158,71 @@ func (fr *FileReader) walkDisk(ctx context.Context) error {
 	return nil
 }
 
+// applyFileChanges reprocesses only the given paths, which were reported as changed by the file watcher.
+// Paths that no longer exist are handled like dashboards missing on disk.
+func (fr *FileReader) applyFileChanges(ctx context.Context, changed []string) {
+	resolvedPath := fr.resolvedPath()
+	provisionedDashboardRefs, err := getProvisionedDashboardsByPath(ctx, fr.dashboardProvisioningService, fr.Cfg.Name)
+	if err != nil {
+		fr.log.Error("failed to get provisioned dashboards", "error", err)
+		return
+	}
+
+	filesFoundOnDisk := map[string]os.FileInfo{}
+	missingDashboardRefs := map[string]*dashboards.DashboardProvisioning{}
+	for _, path := range changed {
+		fileInfo, err := os.Lstat(path)
+		switch {
+		case err == nil && fileInfo.IsDir():
+			if err := filepath.Walk(path, createWalkFn(filesFoundOnDisk)); err != nil {
+				fr.log.Error("failed to walk changed directory", "path", path, "error", err)
+			}
+		case err == nil:
+			if isValid, _ := validateWalkablePath(fileInfo); isValid {
+				filesFoundOnDisk[path] = fileInfo
+			}
+		case os.IsNotExist(err):
+			// the path might have been a file or a whole directory
+			for externalID, ref := range provisionedDashboardRefs {
+				if externalID == path || strings.HasPrefix(externalID, path+string(filepath.Separator)) {
+					missingDashboardRefs[externalID] = ref
+				}
+			}
+		default:
+			fr.log.Error("failed to read changed path", "path", path, "error", err)
+		}
+	}
+
+	if len(filesFoundOnDisk) == 0 && len(missingDashboardRefs) == 0 {
+		return
+	}
+
+	fr.log.Debug("Applying dashboard changes", "changed", len(filesFoundOnDisk), "removed", len(missingDashboardRefs))
+	fr.handleMissingDashboardFiles(ctx, missingDashboardRefs, filesFoundOnDisk)
+
+	usageTracker := fr.getUsageTracker().clone()
+	for path := range missingDashboardRefs {
+		usageTracker.untrack(path)
+	}
+	for path := range filesFoundOnDisk {
+		usageTracker.untrack(path)
+	}
+
+	if fr.FoldersFromFilesStructure {
+		err = fr.storeDashboardsInFoldersFromFileStructure(ctx, filesFoundOnDisk, provisionedDashboardRefs, resolvedPath, usageTracker)
+	} else {
+		err = fr.storeDashboardsInFolder(ctx, filesFoundOnDisk, provisionedDashboardRefs, usageTracker)
+	}
+	if err != nil {
+		fr.log.Error("failed to apply dashboard changes", "error", err)
+	}
+
+	fr.mux.Lock()
+	defer fr.mux.Unlock()
+
+	fr.usageTracker = usageTracker
+}
+
 func (fr *FileReader) changeWritePermissions(restrict bool) {
 	fr.mux.Lock()
 	defer fr.mux.Unlock()
@@ -153,7 +253,7 @@ func (fr *FileReader) storeDashboardsInFolder(ctx context.Context, filesFoundOnD
 			continue
 		}
 
-		usageTracker.track(provisioningMetadata)
+		usageTracker.track(path, provisioningMetadata)
 	}
 	return nil
 }
@@ -176,7 +276,7 @@ func (fr *FileReader) storeDashboardsInFoldersFromFileStructure(ctx context.Cont
 		}
 
 		provisioningMetadata, err := fr.saveDashboard(ctx, path, folderID, fileInfo, dashboardRefs)
-		usageTracker.track(provisioningMetadata)
+		usageTracker.track(path, provisioningMetadata)
 		if err != nil {
 			fr.log.Error("failed to save dashboard", "file", path, "error", err)
 		}
@@ -474,15 +574,18 @@ func newUsageTracker() *usageTracker {
 	return &usageTracker{
 		uidUsage:   map[string]uint8{},
 		titleUsage: map[dashboardIdentity]uint8{},
+		byPath:     map[string]provisioningMetadata{},
 	}
 }
 
 type usageTracker struct {
 	uidUsage   map[string]uint8
 	titleUsage map[dashboardIdentity]uint8
+	byPath     map[string]provisioningMetadata
 }
 
-func (t *usageTracker) track(pm provisioningMetadata) {
+func (t *usageTracker) track(path string, pm provisioningMetadata) {
+	t.byPath[path] = pm
 	if len(pm.uid) > 0 {
 		t.uidUsage[pm.uid]++
 	}
@@ -490,3 +593,31 @@ func (t *usageTracker) track(pm provisioningMetadata) {
 		t.titleUsage[pm.identity]++
 	}
 }
+
+// untrack removes the usage recorded for the dashboard at path, if any.
+func (t *usageTracker) untrack(path string) {
+	pm, ok := t.byPath[path]
+	if !ok {
+		return
+	}
+	delete(t.byPath, path)
+
+	if len(pm.uid) > 0 {
+		if t.uidUsage[pm.uid]--; t.uidUsage[pm.uid] == 0 {
+			delete(t.uidUsage, pm.uid)
+		}
+	}
+	if pm.identity.Exists() {
+		if t.titleUsage[pm.identity]--; t.titleUsage[pm.identity] == 0 {
+			delete(t.titleUsage, pm.identity)
+		}
+	}
+}
+
+func (t *usageTracker) clone() *usageTracker {
+	c := newUsageTracker()
+	for path, pm := range t.byPath {
+		c.track(path, pm)
+	}
+	return c
+}
@@ -481,6 +481,43 @@ func TestDashboardFileReader(t *testing.T) {
 	})
 }
 
+func TestDashboardFileReaderApplyFileChanges(t *testing.T) {
+	dir := t.TempDir()
+	dashboardJSON, err := os.ReadFile(filepath.Join(oneDashboard, "dashboard1.json"))
+	require.NoError(t, err)
+
+	changedPath := filepath.Join(dir, "changed.json")
+	untouchedPath := filepath.Join(dir, "untouched.json")
+	removedPath := filepath.Join(dir, "removed.json")
+	require.NoError(t, os.WriteFile(changedPath, dashboardJSON, 0600))
+	require.NoError(t, os.WriteFile(untouchedPath, dashboardJSON, 0600))
+
+	provisionedDashboards := []*dashboards.DashboardProvisioning{
+		{DashboardID: 1, Name: configName, ExternalID: changedPath, CheckSum: "outdated"},
+		{DashboardID: 2, Name: configName, ExternalID: untouchedPath, CheckSum: "outdated"},
+		{DashboardID: 3, Name: configName, ExternalID: removedPath},
+	}
+
+	fakeService := &dashboards.FakeDashboardProvisioning{}
+	defer fakeService.AssertExpectations(t)
+	fakeService.On("GetProvisionedDashboardData", mock.Anything, configName).Return(provisionedDashboards, nil).Once()
+	fakeService.On("SaveProvisionedDashboard", mock.Anything, mock.Anything, mock.MatchedBy(func(dp *dashboards.DashboardProvisioning) bool {
+		return dp.ExternalID == changedPath
+	})).Return(&dashboards.Dashboard{}, nil).Once()
+	fakeService.On("DeleteProvisionedDashboard", mock.Anything, int64(3), int64(1)).Return(nil).Once()
+
+	cfg := &config{Name: configName, Type: "file", OrgID: 1, Options: map[string]any{"path": dir}}
+	reader, err := NewDashboardFileReader(cfg, log.New("test-logger"), fakeService, &fakeDashboardStore{})
+	require.NoError(t, err)
+
+	reader.applyFileChanges(context.Background(), []string{changedPath, removedPath})
+
+	tracker := reader.getUsageTracker()
+	require.Contains(t, tracker.byPath, changedPath)
+	require.NotContains(t, tracker.byPath, untouchedPath)
+	require.NotContains(t, tracker.byPath, removedPath)
+}
+
 type FakeFileInfo struct {
 	isDirectory bool
 	name        string
@@ -0,0 +1,451 @@
+package dashboards
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-git/go-git/v5"
+	gitconfig "github.com/go-git/go-git/v5/config"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/transport"
+	githttp "github.com/go-git/go-git/v5/plumbing/transport/http"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+)
+
+const (
+	typeFile = "file"
+	typeGit  = "git"
+	typeHTTP = "http"
+
+	// maxBundleSize is the maximum size of a downloaded dashboard bundle.
+	maxBundleSize = 256 << 20
+	// maxExtractedBundleSize is the maximum total size of the files extracted from a
+	// dashboard bundle, which protects against highly compressed archives.
+	maxExtractedBundleSize = 1 << 30
+)
+
+var invalidCacheDirChars = regexp.MustCompile(`[^a-zA-Z0-9_.-]+`)
+
+// remoteSource synchronizes dashboards from a remote location into a local
+// directory, which is then walked by the FileReader like any other path.
+type remoteSource interface {
+	// sync fetches the latest state of the remote source into the local directory.
+	sync(ctx context.Context) error
+	// dir returns the local directory containing the dashboards.
+	dir() string
+}
+
+// newRemoteSource returns the remoteSource for the config type, or nil when
+// the config type reads dashboards from the local filesystem.
+func newRemoteSource(cfg *config, logger log.Logger) (remoteSource, error) {
+	switch cfg.Type {
+	case typeGit:
+		return newGitSource(cfg, logger)
+	case typeHTTP:
+		return newHTTPSource(cfg, logger)
+	default:
+		return nil, nil
+	}
+}
+
+// cacheDir returns the directory remote dashboards of the config are synchronized into,
+// which is kept in the Grafana data directory unless the config sets another one.
+func cacheDir(cfg *config) string {
+	if p, ok := cfg.Options["cachePath"].(string); ok && p != "" {
+		return p
+	}
+	name := invalidCacheDirChars.ReplaceAllString(cfg.Name, "_")
+	return filepath.Join(cfg.DataPath, "provisioning", "dashboards", fmt.Sprintf("%d-%s-%s", cfg.OrgID, cfg.Type, name))
+}
+
+// subPath joins the optional `path` option of a remote source with its root
+// directory. The path is anchored to the root so it can never escape it.
+func subPath(root string, cfg *config) string {
+	p, _ := cfg.Options["path"].(string)
+	return filepath.Join(root, filepath.Clean("/"+p))
+}
+
+// gitSource synchronizes dashboards from a branch or tag of a git repository.
+type gitSource struct {
+	log      log.Logger
+	url      string
+	ref      string
+	auth     *githttp.BasicAuth
+	repoPath string
+	path     string
+
+	mu     sync.Mutex
+	commit plumbing.Hash
+}
+
+func newGitSource(cfg *config, logger log.Logger) (*gitSource, error) {
+	url, _ := cfg.Options["url"].(string)
+	if url == "" {
+		return nil, fmt.Errorf("failed to load dashboards, url param is required for the git type")
+	}
+
+	ref, _ := cfg.Options["ref"].(string)
+	if ref == "" {
+		ref = "main"
+	}
+
+	repoPath := filepath.Join(cacheDir(cfg), "repository")
+	src := &gitSource{
+		log:      logger,
+		url:      url,
+		ref:      ref,
+		repoPath: repoPath,
+		path:     subPath(repoPath, cfg),
+	}
+
+	username, _ := cfg.Options["username"].(string)
+	token, _ := cfg.Options["token"].(string)
+	if token != "" {
+		if username == "" {
+			// Most git hosting services accept any non-empty username together with an access token.
+			username = "grafana"
+		}
+		src.auth = &githttp.BasicAuth{Username: username, Password: token}
+	}
+
+	return src, nil
+}
+
+func (s *gitSource) dir() string {
+	return s.path
+}
+
+func (s *gitSource) sync(ctx context.Context) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	repo, err := s.openOrClone(ctx)
+	if err != nil {
+		return err
+	}
+
+	err = repo.FetchContext(ctx, &git.FetchOptions{
+		RemoteName: git.DefaultRemoteName,
+		RefSpecs: []gitconfig.RefSpec{
+			"+refs/heads/*:refs/remotes/origin/*",
+			"+refs/tags/*:refs/tags/*",
+		},
+		Auth:  s.authMethod(),
+		Force: true,
+	})
+	if err != nil && !errors.Is(err, git.NoErrAlreadyUpToDate) {
+		return fmt.Errorf("failed to fetch %s: %w", s.url, err)
+	}
+
+	hash, err := s.resolveRef(repo)
+	if err != nil {
+		return err
+	}
+
+	wt, err := repo.Worktree()
+	if err != nil {
+		return err
+	}
+
+	if s.commit == *hash {
+		return nil
+	}
+
+	s.log.Debug("Checking out dashboards from git", "url", s.url, "ref", s.ref, "commit", hash.String())
+	if err := wt.Reset(&git.ResetOptions{Commit: *hash, Mode: git.HardReset}); err != nil {
+		return err
+	}
+
+	s.commit = *hash
+	return nil
+}
+
+func (s *gitSource) openOrClone(ctx context.Context) (*git.Repository, error) {
+	repo, err := git.PlainOpen(s.repoPath)
+	if err == nil {
+		return repo, nil
+	}
+	if !errors.Is(err, git.ErrRepositoryNotExists) {
+		return nil, err
+	}
+
+	if err := os.MkdirAll(s.repoPath, 0750); err != nil {
+		return nil, err
+	}
+
+	s.log.Info("Cloning dashboards repository", "url", s.url, "path", s.repoPath)
+	repo, err = git.PlainCloneContext(ctx, s.repoPath, false, &git.CloneOptions{
+		URL:        s.url,
+		Auth:       s.authMethod(),
+		NoCheckout: true,
+	})
+	if err != nil {
+		// remove the partial clone so that the next attempt starts from scratch
+		_ = os.RemoveAll(s.repoPath)
+		return nil, fmt.Errorf("failed to clone %s: %w", s.url, err)
+	}
+
+	return repo, nil
+}
+
+// resolveRef resolves the configured ref as a branch, a tag or a commit hash, in that order.
+func (s *gitSource) resolveRef(repo *git.Repository) (*plumbing.Hash, error) {
+	candidates := []plumbing.Revision{
+		plumbing.Revision(plumbing.NewRemoteReferenceName(git.DefaultRemoteName, s.ref)),
+		plumbing.Revision(plumbing.NewTagReferenceName(s.ref)),
+		plumbing.Revision(s.ref),
+	}
+
+	for _, rev := range candidates {
+		hash, err := repo.ResolveRevision(rev)
+		if err == nil {
+			return hash, nil
+		}
+	}
+
+	return nil, fmt.Errorf("could not resolve ref %q in %s", s.ref, s.url)
+}
+
+func (s *gitSource) authMethod() transport.AuthMethod {
+	if s.auth == nil {
+		return nil
+	}
+	return s.auth
+}
+
+// httpSource synchronizes dashboards from a tar.gz or zip bundle served over HTTP(S).
+// The ETag of the last downloaded bundle is sent with every request so unchanged
+// bundles are not downloaded and extracted again.
+type httpSource struct {
+	log     log.Logger
+	url     string
+	headers map[string]string
+	client  *http.Client
+	root    string
+	path    string
+
+	mu   sync.Mutex
+	etag string
+}
+
+func newHTTPSource(cfg *config, logger log.Logger) (*httpSource, error) {
+	url, _ := cfg.Options["url"].(string)
+	if url == "" {
+		return nil, fmt.Errorf("failed to load dashboards, url param is required for the http type")
+	}
+
+	headers := map[string]string{}
+	if raw, ok := cfg.Options["headers"].(map[string]any); ok {
+		for k, v := range raw {
+			if s, ok := v.(string); ok {
+				headers[k] = s
+			}
+		}
+	}
+
+	root := filepath.Join(cacheDir(cfg), "bundle")
+	return &httpSource{
+		log:     logger,
+		url:     url,
+		headers: headers,
+		client:  &http.Client{Timeout: time.Minute},
+		root:    root,
+		path:    subPath(root, cfg),
+	}, nil
+}
+
+func (s *httpSource) dir() string {
+	return s.path
+}
+
+func (s *httpSource) sync(ctx context.Context) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, s.url, nil)
+	if err != nil {
+		return err
+	}
+	for k, v := range s.headers {
+		req.Header.Set(k, v)
+	}
+	// only use the cached ETag when the extracted bundle is still around
+	if _, err := os.Stat(s.root); s.etag != "" && err == nil {
+		req.Header.Set("If-None-Match", s.etag)
+	}
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to download dashboards bundle: %w", err)
+	}
+	defer func() {
+		if err := resp.Body.Close(); err != nil {
+			s.log.Warn("Failed to close response body", "err", err)
+		}
+	}()
+
+	switch resp.StatusCode {
+	case http.StatusNotModified:
+		return nil
+	case http.StatusOK:
+	default:
+		return fmt.Errorf("failed to download dashboards bundle: unexpected status %s", resp.Status)
+	}
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxBundleSize+1))
+	if err != nil {
+		return err
+	}
+	if len(body) > maxBundleSize {
+		return fmt.Errorf("dashboards bundle exceeds the maximum size of %d bytes", maxBundleSize)
+	}
+
+	if err := s.replaceBundle(body); err != nil {
+		return err
+	}
+
+	s.etag = resp.Header.Get("ETag")
+	s.log.Debug("Downloaded dashboards bundle", "url", s.url, "etag", s.etag)
+	return nil
+}
+
+// replaceBundle extracts the bundle into a staging directory and swaps it with the
+// current one so the FileReader never walks a partially extracted bundle.
+func (s *httpSource) replaceBundle(body []byte) error {
+	if err := os.MkdirAll(filepath.Dir(s.root), 0750); err != nil {
+		return err
+	}
+
+	staging, err := os.MkdirTemp(filepath.Dir(s.root), "bundle-")
+	if err != nil {
+		return err
+	}
+
+	if err := extractBundle(body, staging, maxExtractedBundleSize); err != nil {
+		_ = os.RemoveAll(staging)
+		return err
+	}
+
+	if err := os.RemoveAll(s.root); err != nil {
+		_ = os.RemoveAll(staging)
+		return err
+	}
+
+	return os.Rename(staging, s.root)
+}
+
+// extractBundle extracts the bundle into dest, failing once the extracted files
+// exceed maxSize bytes in total.
+func extractBundle(body []byte, dest string, maxSize int64) error {
+	w := &bundleWriter{dest: dest, maxSize: maxSize, remaining: maxSize}
+	if bytes.HasPrefix(body, []byte("PK\x03\x04")) {
+		return extractZip(body, w)
+	}
+	return extractTarGz(body, w)
+}
+
+func extractTarGz(body []byte, w *bundleWriter) error {
+	gz, err := gzip.NewReader(bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("dashboards bundle is neither a zip nor a tar.gz archive: %w", err)
+	}
+
+	tr := tar.NewReader(gz)
+	for {
+		header, err := tr.Next()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		if header.Typeflag != tar.TypeReg {
+			continue
+		}
+
+		if err := w.writeFile(header.Name, tr); err != nil {
+			return err
+		}
+	}
+}
+
+func extractZip(body []byte, w *bundleWriter) error {
+	zr, err := zip.NewReader(bytes.NewReader(body), int64(len(body)))
+	if err != nil {
+		return err
+	}
+
+	for _, f := range zr.File {
+		if f.FileInfo().IsDir() {
+			continue
+		}
+
+		rc, err := f.Open()
+		if err != nil {
+			return err
+		}
+		err = w.writeFile(f.Name, rc)
+		_ = rc.Close()
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// bundleWriter writes the files of a bundle into dest and keeps track of how
+// many bytes can still be extracted.
+type bundleWriter struct {
+	dest      string
+	maxSize   int64
+	remaining int64
+}
+
+func (w *bundleWriter) writeFile(name string, r io.Reader) error {
+	target := filepath.Join(w.dest, filepath.Clean("/"+name))
+	if !strings.HasPrefix(target, w.dest+string(filepath.Separator)) {
+		return fmt.Errorf("invalid file path %q in dashboards bundle", name)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(target), 0750); err != nil {
+		return err
+	}
+
+	// nolint:gosec
+	// We can ignore the gosec G304 warning on this one because `target` is verified to be within `dest`.
+	f, err := os.OpenFile(target, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0640)
+	if err != nil {
+		return err
+	}
+
+	n, err := io.Copy(f, io.LimitReader(r, w.remaining+1))
+	if err != nil {
+		_ = f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+
+	w.remaining -= n
+	if w.remaining < 0 {
+		return fmt.Errorf("extracted dashboards bundle exceeds the maximum size of %d bytes", w.maxSize)
+	}
+	return nil
+}
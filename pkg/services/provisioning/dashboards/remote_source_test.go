@@ -0,0 +1,231 @@
+package dashboards
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+)
+
+func TestHTTPSource(t *testing.T) {
+	bundle := createTarGz(t, map[string]string{
+		"dashboards/team-a/dashboard1.json": `{"title": "one"}`,
+		"dashboards/dashboard2.json":        `{"title": "two"}`,
+		"README.md":                         "not a dashboard",
+	})
+
+	var downloads int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, "Bearer secret", r.Header.Get("Authorization"))
+		if r.Header.Get("If-None-Match") == `"v1"` {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		atomic.AddInt32(&downloads, 1)
+		w.Header().Set("ETag", `"v1"`)
+		_, _ = w.Write(bundle)
+	}))
+	t.Cleanup(server.Close)
+
+	cfg := &config{
+		Name:  "bundle",
+		Type:  typeHTTP,
+		OrgID: 1,
+		Options: map[string]any{
+			"url":       server.URL,
+			"path":      "dashboards",
+			"cachePath": t.TempDir(),
+			"headers":   map[string]any{"Authorization": "Bearer secret"},
+		},
+	}
+
+	src, err := newRemoteSource(cfg, log.New("test-logger"))
+	require.NoError(t, err)
+
+	require.NoError(t, src.sync(context.Background()))
+	require.FileExists(t, filepath.Join(src.dir(), "team-a", "dashboard1.json"))
+	require.FileExists(t, filepath.Join(src.dir(), "dashboard2.json"))
+
+	t.Run("unchanged bundle is not downloaded again", func(t *testing.T) {
+		require.NoError(t, src.sync(context.Background()))
+		require.Equal(t, int32(1), atomic.LoadInt32(&downloads))
+	})
+
+	t.Run("file reader uses the synchronized directory", func(t *testing.T) {
+		reader, err := NewDashboardFileReader(cfg, log.New("test-logger"), nil, nil)
+		require.NoError(t, err)
+		require.Equal(t, src.dir(), reader.Path)
+		require.NotNil(t, reader.source)
+	})
+}
+
+func TestHTTPSourceAnchorsPaths(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write(createTarGz(t, map[string]string{"../../evil.json": "{}"}))
+	}))
+	t.Cleanup(server.Close)
+
+	cachePath := t.TempDir()
+	src, err := newRemoteSource(&config{
+		Name:    "bundle",
+		Type:    typeHTTP,
+		Options: map[string]any{"url": server.URL, "path": "../../", "cachePath": cachePath},
+	}, log.New("test-logger"))
+	require.NoError(t, err)
+	require.Equal(t, filepath.Join(cachePath, "bundle"), src.dir())
+
+	// entries are anchored to the bundle root, so nothing is written outside of it
+	require.NoError(t, src.sync(context.Background()))
+	require.FileExists(t, filepath.Join(src.dir(), "evil.json"))
+}
+
+func TestRemoteSourceIsCachedInDataPath(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write(createTarGz(t, map[string]string{"dashboard1.json": "{}"}))
+	}))
+	t.Cleanup(server.Close)
+
+	dataPath := t.TempDir()
+	src, err := newRemoteSource(&config{
+		Name:     "team bundle",
+		Type:     typeHTTP,
+		OrgID:    1,
+		Options:  map[string]any{"url": server.URL},
+		DataPath: dataPath,
+	}, log.New("test-logger"))
+	require.NoError(t, err)
+
+	cachePath := filepath.Join(dataPath, "provisioning", "dashboards", "1-http-team_bundle")
+	require.Equal(t, filepath.Join(cachePath, "bundle"), src.dir())
+
+	require.NoError(t, src.sync(context.Background()))
+	require.FileExists(t, filepath.Join(src.dir(), "dashboard1.json"))
+
+	info, err := os.Stat(cachePath)
+	require.NoError(t, err)
+	require.Equal(t, os.FileMode(0750), info.Mode().Perm())
+}
+
+func TestExtractBundleLimitsSize(t *testing.T) {
+	bundle := createTarGz(t, map[string]string{
+		"dashboard1.json": strings.Repeat("a", 60),
+		"dashboard2.json": strings.Repeat("b", 60),
+	})
+
+	require.NoError(t, extractBundle(bundle, t.TempDir(), 120))
+
+	// the limit applies to the total size of the entries, not to each entry
+	err := extractBundle(bundle, t.TempDir(), 100)
+	require.ErrorContains(t, err, "exceeds the maximum size of 100 bytes")
+}
+
+func TestGitSource(t *testing.T) {
+	if _, err := exec.LookPath("git-upload-pack"); err != nil {
+		t.Skip("git-upload-pack is required to clone local repositories")
+	}
+
+	remotePath := t.TempDir()
+	remote, err := git.PlainInit(remotePath, false)
+	require.NoError(t, err)
+
+	commit := func(files map[string]string) plumbing.Hash {
+		wt, err := remote.Worktree()
+		require.NoError(t, err)
+		for name, content := range files {
+			p := filepath.Join(remotePath, name)
+			require.NoError(t, os.MkdirAll(filepath.Dir(p), 0750))
+			require.NoError(t, os.WriteFile(p, []byte(content), 0600))
+			_, err := wt.Add(name)
+			require.NoError(t, err)
+		}
+		hash, err := wt.Commit("update dashboards", &git.CommitOptions{
+			Author: &object.Signature{Name: "test", Email: "test@example.com", When: time.Now()},
+		})
+		require.NoError(t, err)
+		return hash
+	}
+
+	first := commit(map[string]string{"dashboards/one.json": `{"title": "one"}`})
+	_, err = remote.CreateTag("v1", first, nil)
+	require.NoError(t, err)
+	commit(map[string]string{"dashboards/two.json": `{"title": "two"}`})
+
+	head, err := remote.Head()
+	require.NoError(t, err)
+
+	newSource := func(ref string) remoteSource {
+		src, err := newRemoteSource(&config{
+			Name:  "repo",
+			Type:  typeGit,
+			OrgID: 1,
+			Options: map[string]any{
+				"url":       remotePath,
+				"ref":       ref,
+				"path":      "dashboards",
+				"cachePath": t.TempDir(),
+			},
+		}, log.New("test-logger"))
+		require.NoError(t, err)
+		return src
+	}
+
+	t.Run("checks out branch", func(t *testing.T) {
+		src := newSource(head.Name().Short())
+		require.NoError(t, src.sync(context.Background()))
+		require.FileExists(t, filepath.Join(src.dir(), "one.json"))
+		require.FileExists(t, filepath.Join(src.dir(), "two.json"))
+
+		commit(map[string]string{"dashboards/three.json": `{"title": "three"}`})
+		require.NoError(t, src.sync(context.Background()))
+		require.FileExists(t, filepath.Join(src.dir(), "three.json"))
+	})
+
+	t.Run("checks out tag", func(t *testing.T) {
+		src := newSource("v1")
+		require.NoError(t, src.sync(context.Background()))
+		require.FileExists(t, filepath.Join(src.dir(), "one.json"))
+		require.NoFileExists(t, filepath.Join(src.dir(), "two.json"))
+	})
+
+	t.Run("fails on unknown ref", func(t *testing.T) {
+		src := newSource("does-not-exist")
+		require.Error(t, src.sync(context.Background()))
+	})
+}
+
+func createTarGz(t *testing.T, files map[string]string) []byte {
+	t.Helper()
+
+	var buf bytes.Buffer
+	gz := gzip.NewWriter(&buf)
+	tw := tar.NewWriter(gz)
+	for name, content := range files {
+		require.NoError(t, tw.WriteHeader(&tar.Header{
+			Name:     name,
+			Mode:     0600,
+			Size:     int64(len(content)),
+			Typeflag: tar.TypeReg,
+		}))
+		_, err := tw.Write([]byte(content))
+		require.NoError(t, err)
+	}
+	require.NoError(t, tw.Close())
+	require.NoError(t, gz.Close())
+	return buf.Bytes()
+}
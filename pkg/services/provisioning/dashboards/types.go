@@ -20,6 +20,8 @@ type config struct {
 	DisableDeletion       bool
 	UpdateIntervalSeconds int64
 	AllowUIUpdates        bool
+	// DataPath is the Grafana data directory the remote dashboards are synchronized into.
+	DataPath string
 }
 
 type configV0 struct {
@@ -0,0 +1,169 @@
+package dashboards
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/grafana/grafana/pkg/components/simplejson"
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/services/provisioning/utils"
+	"github.com/grafana/grafana/pkg/util"
+)
+
+// builtinDatasourceUIDs are data source UIDs that are not backed by a data source instance.
+var builtinDatasourceUIDs = map[string]struct{}{
+	"grafana":         {},
+	"-- Grafana --":   {},
+	"-- Mixed --":     {},
+	"-- Dashboard --": {},
+	"__expr__":        {},
+}
+
+// ValidationResult is the outcome of validating the dashboard provisioning files of a directory.
+type ValidationResult struct {
+	// DatasourceReferences contains the data sources referenced by UID from panels and targets.
+	DatasourceReferences []utils.DatasourceReference
+	// Errors contains every problem found, each prefixed with the file it was found in.
+	Errors []error
+	// Warnings contains problems that don't prevent provisioning, like providers that can't be read offline.
+	Warnings []string
+}
+
+// Validate reads the dashboard provider configs in configDirectory with the config reader used
+// at runtime and validates the dashboards of every provider of type file without accessing the
+// database. Dashboards of git and http providers are not fetched.
+func Validate(ctx context.Context, configDirectory string) ValidationResult {
+	result := ValidationResult{}
+	if _, err := os.Stat(configDirectory); os.IsNotExist(err) {
+		return result
+	}
+
+	cr := &configReader{path: configDirectory, log: log.New("provisioning.dashboard")}
+	configs, err := cr.readConfig(ctx)
+	if err != nil {
+		result.Errors = append(result.Errors, err)
+		return result
+	}
+
+	uids := map[int64]map[string]string{}
+	titles := map[int64]map[string]string{}
+	for _, cfg := range configs {
+		if uids[cfg.OrgID] == nil {
+			uids[cfg.OrgID] = map[string]string{}
+			titles[cfg.OrgID] = map[string]string{}
+		}
+
+		reader, err := NewDashboardFileReader(cfg, cr.log, nil, nil)
+		if err != nil {
+			result.Errors = append(result.Errors, fmt.Errorf("provider %q: %w", cfg.Name, err))
+			continue
+		}
+		if reader.source != nil {
+			result.Warnings = append(result.Warnings, fmt.Sprintf("provider %q: dashboards of type %s are not validated", cfg.Name, cfg.Type))
+			continue
+		}
+
+		filesOnDisk := map[string]os.FileInfo{}
+		if err := filepath.Walk(reader.Path, createWalkFn(filesOnDisk)); err != nil {
+			result.Warnings = append(result.Warnings, fmt.Sprintf("provider %q: cannot read dashboards: %s", cfg.Name, err))
+			continue
+		}
+
+		paths := make([]string, 0, len(filesOnDisk))
+		for path := range filesOnDisk {
+			paths = append(paths, path)
+		}
+		sort.Strings(paths)
+
+		for _, path := range paths {
+			validateDashboardFile(reader, path, uids[cfg.OrgID], titles[cfg.OrgID], &result)
+		}
+	}
+
+	return result
+}
+
+func validateDashboardFile(reader *FileReader, path string, uids, titles map[string]string, result *ValidationResult) {
+	errorf := func(format string, args ...any) {
+		result.Errors = append(result.Errors, fmt.Errorf("%s: %s", path, fmt.Sprintf(format, args...)))
+	}
+
+	// nolint:gosec
+	// We can ignore the gosec G304 warning on this one because `path` comes from the provisioning configuration file.
+	content, err := os.ReadFile(path)
+	if err != nil {
+		errorf("%s", err)
+		return
+	}
+
+	data, err := simplejson.NewJson(content)
+	if err != nil {
+		errorf("invalid JSON: %s", err)
+		return
+	}
+
+	title := data.Get("title").MustString()
+	if title == "" {
+		errorf("dashboard title cannot be empty")
+	}
+
+	folder := reader.Cfg.Folder
+	if reader.FoldersFromFilesStructure && filepath.Dir(path) != filepath.Clean(reader.Path) {
+		folder = filepath.Base(filepath.Dir(path))
+	}
+	titleKey := folder + "/" + title
+	if other, ok := titles[titleKey]; ok && title != "" {
+		errorf("dashboard title %q is already used in the same folder by %s", title, other)
+	}
+	titles[titleKey] = path
+
+	if uid := data.Get("uid").MustString(); uid != "" {
+		if err := util.ValidateUID(uid); err != nil {
+			errorf("invalid uid %q: %s", uid, err)
+		}
+		if other, ok := uids[uid]; ok {
+			errorf("uid %q is already used by %s", uid, other)
+		}
+		uids[uid] = path
+	}
+
+	collectDatasourceReferences(data.Interface(), func(uid string) {
+		result.DatasourceReferences = append(result.DatasourceReferences, utils.DatasourceReference{
+			OrgID:    reader.Cfg.OrgID,
+			UID:      uid,
+			Location: path,
+		})
+	})
+}
+
+// collectDatasourceReferences calls ref for every data source referenced by UID in the dashboard
+// model, skipping template variables and built-in data sources.
+func collectDatasourceReferences(node any, ref func(uid string)) {
+	switch v := node.(type) {
+	case map[string]any:
+		for key, value := range v {
+			if key == "datasource" {
+				if ds, ok := value.(map[string]any); ok {
+					uid, _ := ds["uid"].(string)
+					if _, builtin := builtinDatasourceUIDs[uid]; uid != "" && !builtin && !strings.Contains(uid, "$") {
+						ref(uid)
+					}
+				}
+				continue
+			}
+			// template variables reference data sources that are picked at runtime
+			if key == "templating" {
+				continue
+			}
+			collectDatasourceReferences(value, ref)
+		}
+	case []any:
+		for _, value := range v {
+			collectDatasourceReferences(value, ref)
+		}
+	}
+}
@@ -22,6 +22,16 @@ type configReader struct {
 }
 
 func (cr *configReader) readConfig(ctx context.Context, path string) ([]*configs, error) {
+	datasources, errs := cr.readConfigs(ctx, path)
+	if len(errs) > 0 {
+		return nil, errs[0]
+	}
+	return datasources, nil
+}
+
+// readConfigs reads the data source provisioning files in path and returns
+// the configs that could be parsed along with all the problems found.
+func (cr *configReader) readConfigs(ctx context.Context, path string) ([]*configs, []error) {
 	var datasources []*configs
 
 	files, err := os.ReadDir(path)
@@ -30,11 +40,13 @@ func (cr *configReader) readConfig(ctx context.Context, path string) ([]*configs
 		return datasources, nil
 	}
 
+	var errs []error
 	for _, file := range files {
 		if strings.HasSuffix(file.Name(), ".yaml") || strings.HasSuffix(file.Name(), ".yml") {
 			datasource, err := cr.parseDatasourceConfig(path, file)
 			if err != nil {
-				return nil, err
+				errs = append(errs, fmt.Errorf("failure to parse file %s: %w", file.Name(), err))
+				continue
 			}
 
 			if datasource != nil {
@@ -43,12 +55,8 @@ func (cr *configReader) readConfig(ctx context.Context, path string) ([]*configs
 		}
 	}
 
-	err = cr.validateDefaultUniqueness(ctx, datasources)
-	if err != nil {
-		return nil, err
-	}
-
-	return datasources, nil
+	errs = append(errs, cr.validateDefaultUniqueness(ctx, datasources)...)
+	return datasources, errs
 }
 
 func (cr *configReader) parseDatasourceConfig(path string, file fs.DirEntry) (*configs, error) {
@@ -78,7 +86,9 @@ func (cr *configReader) parseDatasourceConfig(path string, file fs.DirEntry) (*c
 			return nil, err
 		}
 
-		return v1.mapToDatasourceFromConfig(apiVersion.APIVersion), nil
+		cfg := v1.mapToDatasourceFromConfig(apiVersion.APIVersion)
+		cfg.Filename = file.Name()
+		return cfg, nil
 	}
 
 	var v0 *configsV0
@@ -89,10 +99,13 @@ func (cr *configReader) parseDatasourceConfig(path string, file fs.DirEntry) (*c
 
 	cr.log.Warn("[Deprecated] the datasource provisioning config is outdated. please upgrade", "filename", filename)
 
-	return v0.mapToDatasourceFromConfig(apiVersion.APIVersion), nil
+	cfg := v0.mapToDatasourceFromConfig(apiVersion.APIVersion)
+	cfg.Filename = file.Name()
+	return cfg, nil
 }
 
-func (cr *configReader) validateDefaultUniqueness(ctx context.Context, datasources []*configs) error {
+func (cr *configReader) validateDefaultUniqueness(ctx context.Context, datasources []*configs) []error {
+	var errs []error
 	defaultCount := map[int64]int{}
 	for i := range datasources {
 		for _, ds := range datasources[i].Datasources {
@@ -105,13 +118,15 @@ func (cr *configReader) validateDefaultUniqueness(ctx context.Context, datasourc
 			}
 
 			if err := cr.validateAccessAndOrgID(ctx, ds); err != nil {
-				return fmt.Errorf("failed to provision %q data source: %w", ds.Name, err)
+				errs = append(errs, fmt.Errorf("failed to provision %q data source: %w", ds.Name, err))
+				continue
 			}
 
 			if ds.IsDefault {
 				defaultCount[ds.OrgID]++
-				if defaultCount[ds.OrgID] > 1 {
-					return ErrInvalidConfigToManyDefault
+				// reported once per organization
+				if defaultCount[ds.OrgID] == 2 {
+					errs = append(errs, ErrInvalidConfigToManyDefault)
 				}
 			}
 		}
@@ -127,7 +142,7 @@ func (cr *configReader) validateDefaultUniqueness(ctx context.Context, datasourc
 		}
 	}
 
-	return nil
+	return errs
 }
 
 func (cr *configReader) validateAccessAndOrgID(ctx context.Context, ds *upsertDataSourceFromConfig) error {
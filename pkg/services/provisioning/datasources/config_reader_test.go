@@ -6,11 +6,13 @@ import (
 
 	"github.com/stretchr/testify/require"
 
+	"github.com/grafana/grafana/pkg/infra/kvstore"
 	"github.com/grafana/grafana/pkg/infra/log"
 	"github.com/grafana/grafana/pkg/services/correlations"
 	"github.com/grafana/grafana/pkg/services/datasources"
 	"github.com/grafana/grafana/pkg/services/org"
 	"github.com/grafana/grafana/pkg/services/org/orgtest"
+	"github.com/grafana/grafana/pkg/services/provisioning/utils"
 	"github.com/grafana/grafana/pkg/util"
 )
 
@@ -28,6 +30,9 @@ var (
 	multipleOrgsWithDefault         = "testdata/multiple-org-default"
 	withoutDefaults                 = "testdata/appliedDefaults"
 	invalidAccess                   = "testdata/invalid-access"
+	pruneTwoDatasources             = "testdata/prune-two"
+	pruneOneDatasource              = "testdata/prune-one"
+	pruneOneDatasourceDryRun        = "testdata/prune-one-dry-run"
 
 	oneDatasourceWithTwoCorrelations   = "testdata/one-datasource-two-correlations"
 	correlationsDifferentOrganizations = "testdata/correlations-different-organizations"
@@ -84,6 +89,19 @@ func TestDatasourceAsConfig(t *testing.T) {
 		require.Equal(t, len(store.updated), 0)
 	})
 
+	t.Run("only the datasources of the changed files are provisioned", func(t *testing.T) {
+		store := &spyStore{}
+		orgFake := &orgtest.FakeOrgService{}
+		correlationsStore := &mockCorrelationsStore{}
+		dc := newDatasourceProvisioner(logger, store, correlationsStore, orgFake)
+
+		require.NoError(t, dc.applyFileChanges(context.Background(), twoDatasourcesConfig, []string{"other.yaml"}))
+		require.Empty(t, store.inserted)
+
+		require.NoError(t, dc.applyFileChanges(context.Background(), twoDatasourcesConfig, []string{"two-datasources.yaml"}))
+		require.Len(t, store.inserted, 2)
+	})
+
 	t.Run("One datasource in database with same name should update one datasource", func(t *testing.T) {
 		store := &spyStore{items: []*datasources.DataSource{{Name: "Graphite", OrgID: 1, ID: 1}}}
 		orgFake := &orgtest.FakeOrgService{}
@@ -170,7 +188,7 @@ func TestDatasourceAsConfig(t *testing.T) {
 	})
 
 	t.Run("broken yaml should return error", func(t *testing.T) {
-		reader := &configReader{}
+		reader := &configReader{log: logger}
 		_, err := reader.readConfig(context.Background(), brokenYaml)
 		require.NotNil(t, err)
 	})
@@ -335,6 +353,50 @@ func TestDatasourceAsConfig(t *testing.T) {
 	})
 }
 
+func TestPruneDatasources(t *testing.T) {
+	store := &spyStore{}
+	orgFake := &orgtest.FakeOrgService{}
+	correlationsStore := &mockCorrelationsStore{}
+	dc := newDatasourceProvisioner(logger, store, correlationsStore, orgFake)
+	dc.pruneTracker = utils.NewPruneTracker(kvstore.NewFakeKVStore(), pruneNamespace, logger)
+
+	err := dc.applyChanges(context.Background(), pruneTwoDatasources)
+	require.NoError(t, err)
+	require.Len(t, store.inserted, 2)
+	require.Empty(t, store.deleted)
+
+	t.Run("dry run only reports data sources to delete", func(t *testing.T) {
+		err := dc.applyChanges(context.Background(), pruneOneDatasourceDryRun)
+		require.NoError(t, err)
+		require.Empty(t, store.deleted)
+	})
+
+	t.Run("removed data sources are deleted", func(t *testing.T) {
+		err := dc.applyChanges(context.Background(), pruneOneDatasource)
+		require.NoError(t, err)
+		require.Len(t, store.deleted, 1)
+		require.Equal(t, "Prometheus", store.deleted[0].Name)
+		require.Equal(t, int64(1), store.deleted[0].OrgID)
+	})
+
+	t.Run("data sources of removed files are deleted", func(t *testing.T) {
+		err := dc.applyChanges(context.Background(), t.TempDir())
+		require.NoError(t, err)
+		require.Len(t, store.deleted, 2)
+		require.Equal(t, "Graphite", store.deleted[1].Name)
+	})
+
+	t.Run("data sources are not deleted without prune", func(t *testing.T) {
+		store := &spyStore{}
+		dc := newDatasourceProvisioner(logger, store, correlationsStore, orgFake)
+		dc.pruneTracker = utils.NewPruneTracker(kvstore.NewFakeKVStore(), pruneNamespace, logger)
+
+		require.NoError(t, dc.applyChanges(context.Background(), twoDatasourcesConfig))
+		require.NoError(t, dc.applyChanges(context.Background(), t.TempDir()))
+		require.Empty(t, store.deleted)
+	})
+}
+
 func validateDeleteDatasources(t *testing.T, dsCfg *configs) {
 	require.Equal(t, len(dsCfg.DeleteDatasources), 1)
 	deleteDs := dsCfg.DeleteDatasources[0]
@@ -5,10 +5,12 @@ import (
 	"errors"
 	"fmt"
 
+	"github.com/grafana/grafana/pkg/infra/kvstore"
 	"github.com/grafana/grafana/pkg/infra/log"
 	"github.com/grafana/grafana/pkg/services/correlations"
 	"github.com/grafana/grafana/pkg/services/datasources"
 	"github.com/grafana/grafana/pkg/services/org"
+	"github.com/grafana/grafana/pkg/services/provisioning/utils"
 	jsoniter "github.com/json-iterator/go"
 )
 
@@ -32,11 +34,24 @@ var (
 	ErrInvalidConfigToManyDefault = errors.New("datasource.yaml config is invalid. Only one datasource per organization can be marked as default")
 )
 
+// pruneNamespace is the kvstore namespace used to track the data sources provisioned by files with prune enabled.
+const pruneNamespace = "provisioning.datasources"
+
 // Provision scans a directory for provisioning config files
 // and provisions the datasource in those files.
-func Provision(ctx context.Context, configDirectory string, store Store, correlationsStore CorrelationsStore, orgService org.Service) error {
+func Provision(ctx context.Context, configDirectory string, store Store, correlationsStore CorrelationsStore, orgService org.Service, kvStore kvstore.KVStore) error {
+	return ProvisionFiles(ctx, configDirectory, nil, store, correlationsStore, orgService, kvStore)
+}
+
+// ProvisionFiles provisions only the datasources of the config files with the given names
+// in configDirectory. The other files are still read to validate the configuration and to
+// find the data sources to prune.
+func ProvisionFiles(ctx context.Context, configDirectory string, filenames []string, store Store, correlationsStore CorrelationsStore, orgService org.Service, kvStore kvstore.KVStore) error {
 	dc := newDatasourceProvisioner(log.New("provisioning.datasources"), store, correlationsStore, orgService)
-	return dc.applyChanges(ctx, configDirectory)
+	if kvStore != nil {
+		dc.pruneTracker = utils.NewPruneTracker(kvStore, pruneNamespace, dc.log)
+	}
+	return dc.applyFileChanges(ctx, configDirectory, filenames)
 }
 
 // DatasourceProvisioner is responsible for provisioning datasources based on
@@ -46,6 +61,7 @@ type DatasourceProvisioner struct {
 	cfgProvider       *configReader
 	store             Store
 	correlationsStore CorrelationsStore
+	pruneTracker      *utils.PruneTracker
 }
 
 func newDatasourceProvisioner(log log.Logger, store Store, correlationsStore CorrelationsStore, orgService org.Service) DatasourceProvisioner {
@@ -133,6 +149,12 @@ type DataSourceMapKey struct {
 }
 
 func (dc *DatasourceProvisioner) applyChanges(ctx context.Context, configPath string) error {
+	return dc.applyFileChanges(ctx, configPath, nil)
+}
+
+// applyFileChanges provisions the data sources of the files with the given names, or of
+// all files when filenames is nil, and prunes the ones no longer declared in any file.
+func (dc *DatasourceProvisioner) applyFileChanges(ctx context.Context, configPath string, filenames []string) error {
 	configs, err := dc.cfgProvider.readConfig(ctx, configPath)
 	if err != nil {
 		return err
@@ -150,18 +172,52 @@ func (dc *DatasourceProvisioner) applyChanges(ctx context.Context, configPath st
 	}
 
 	for _, cfg := range configs {
+		if !utils.ContainsFile(filenames, cfg.Filename) {
+			continue
+		}
 		if err := dc.provisionDataSources(ctx, cfg, willExistAfterProvisioning); err != nil {
 			return err
 		}
 	}
 
 	for _, cfg := range configs {
+		if !utils.ContainsFile(filenames, cfg.Filename) {
+			continue
+		}
 		if err := dc.provisionCorrelations(ctx, cfg); err != nil {
 			return err
 		}
 	}
 
-	return nil
+	return dc.pruneDatasources(ctx, configs)
+}
+
+// pruneDatasources deletes the data sources that were provisioned by a file with prune enabled
+// but are no longer declared in any provisioning file.
+func (dc *DatasourceProvisioner) pruneDatasources(ctx context.Context, configs []*configs) error {
+	if dc.pruneTracker == nil {
+		return nil
+	}
+
+	files := make([]utils.PruneFile, 0, len(configs))
+	for _, cfg := range configs {
+		file := utils.PruneFile{Name: cfg.Filename, Prune: cfg.Prune, DryRun: cfg.PruneDryRun}
+		for _, ds := range cfg.Datasources {
+			file.Items = append(file.Items, utils.ProvisionedItem{OrgID: ds.OrgID, Name: ds.Name})
+		}
+		files = append(files, file)
+	}
+
+	return dc.pruneTracker.Prune(ctx, files, func(ctx context.Context, item utils.ProvisionedItem) error {
+		cmd := &datasources.DeleteDataSourceCommand{OrgID: item.OrgID, Name: item.Name}
+		if err := dc.store.DeleteDataSource(ctx, cmd); err != nil {
+			return err
+		}
+		if cmd.DeletedDatasourcesCount > 0 {
+			dc.log.Info("deleted datasource no longer present in provisioning files", "name", item.Name, "orgId", item.OrgID)
+		}
+		return nil
+	})
 }
 
 func makeCreateCorrelationCommand(correlation map[string]any, SourceUID string, OrgId int64) (correlations.CreateCorrelationCommand, error) {
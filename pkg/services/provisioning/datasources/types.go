@@ -18,6 +18,11 @@ type configVersion struct {
 
 type configs struct {
 	APIVersion int64
+	// Filename is the name of the provisioning file the config was read from.
+	Filename string
+	// Prune deletes data sources this file provisioned earlier that aren't declared anymore.
+	Prune       bool
+	PruneDryRun bool
 
 	Datasources       []*upsertDataSourceFromConfig
 	DeleteDatasources []*deleteDatasourceConfig
@@ -62,6 +67,8 @@ type configsV1 struct {
 
 	Datasources       []*upsertDataSourceFromConfigV1 `json:"datasources" yaml:"datasources"`
 	DeleteDatasources []*deleteDatasourceConfigV1     `json:"deleteDatasources" yaml:"deleteDatasources"`
+	Prune             values.BoolValue                `json:"prune" yaml:"prune"`
+	PruneDryRun       values.BoolValue                `json:"pruneDryRun" yaml:"pruneDryRun"`
 }
 
 type deleteDatasourceConfigV0 struct {
@@ -122,6 +129,9 @@ func (cfg *configsV1) mapToDatasourceFromConfig(apiVersion int64) *configs {
 		return r
 	}
 
+	r.Prune = cfg.Prune.Value()
+	r.PruneDryRun = cfg.PruneDryRun.Value()
+
 	for _, ds := range cfg.Datasources {
 		r.Datasources = append(r.Datasources, &upsertDataSourceFromConfig{
 			OrgID:           ds.OrgID.Value(),
@@ -0,0 +1,44 @@
+package datasources
+
+import (
+	"context"
+	"os"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+)
+
+// ValidationResult is the outcome of validating the data source provisioning files of a directory.
+type ValidationResult struct {
+	// UIDs contains the UIDs of all data sources provisioned with an explicit UID, by org ID.
+	UIDs map[int64]map[string]struct{}
+	// Errors are the problems found in the files.
+	Errors []error
+}
+
+// Validate reads all data source provisioning files in configDirectory with the config reader
+// used at runtime, but without accessing the database, so it does not check whether the
+// referenced organizations exist. The files are all validated, whatever the problems found.
+func Validate(ctx context.Context, configDirectory string) ValidationResult {
+	result := ValidationResult{UIDs: map[int64]map[string]struct{}{}}
+	if _, err := os.Stat(configDirectory); os.IsNotExist(err) {
+		return result
+	}
+
+	cr := &configReader{log: log.New("provisioning.datasources")}
+	configs, errs := cr.readConfigs(ctx, configDirectory)
+	result.Errors = errs
+
+	for _, cfg := range configs {
+		for _, ds := range cfg.Datasources {
+			if ds == nil || ds.UID == "" {
+				continue
+			}
+			if result.UIDs[ds.OrgID] == nil {
+				result.UIDs[ds.OrgID] = map[string]struct{}{}
+			}
+			result.UIDs[ds.OrgID][ds.UID] = struct{}{}
+		}
+	}
+
+	return result
+}
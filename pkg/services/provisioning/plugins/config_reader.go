@@ -28,6 +28,16 @@ func newConfigReader(logger log.Logger, pluginStore pluginstore.Store) configRea
 }
 
 func (cr *configReaderImpl) readConfig(ctx context.Context, path string) ([]*pluginsAsConfig, error) {
+	apps, errs := cr.readConfigs(ctx, path)
+	if len(errs) > 0 {
+		return nil, errs[0]
+	}
+	return apps, nil
+}
+
+// readConfigs reads the plugin provisioning files in path and returns
+// the configs that could be parsed along with all the problems found.
+func (cr *configReaderImpl) readConfigs(ctx context.Context, path string) ([]*pluginsAsConfig, []error) {
 	var apps []*pluginsAsConfig
 	cr.log.Debug("Looking for plugin provisioning files", "path", path)
 
@@ -37,12 +47,14 @@ func (cr *configReaderImpl) readConfig(ctx context.Context, path string) ([]*plu
 		return apps, nil
 	}
 
+	var errs []error
 	for _, file := range files {
 		if strings.HasSuffix(file.Name(), ".yaml") || strings.HasSuffix(file.Name(), ".yml") {
 			cr.log.Debug("Parsing plugin provisioning file", "path", path, "file.Name", file.Name())
 			app, err := cr.parsePluginConfig(path, file)
 			if err != nil {
-				return nil, err
+				errs = append(errs, fmt.Errorf("failure to parse file %s: %w", file.Name(), err))
+				continue
 			}
 
 			if app != nil {
@@ -52,18 +64,14 @@ func (cr *configReaderImpl) readConfig(ctx context.Context, path string) ([]*plu
 	}
 
 	cr.log.Debug("Validating plugins")
-	if err := validateRequiredField(apps); err != nil {
-		return nil, err
+	errs = append(errs, validateRequiredField(apps)...)
+	if len(errs) > 0 {
+		return apps, errs
 	}
 
 	checkOrgIDAndOrgName(apps)
 
-	err = cr.validatePluginsConfig(ctx, apps)
-	if err != nil {
-		return nil, err
-	}
-
-	return apps, nil
+	return apps, cr.validatePluginsConfig(ctx, apps)
 }
 
 func (cr *configReaderImpl) parsePluginConfig(path string, file fs.DirEntry) (*pluginsAsConfig, error) {
@@ -85,10 +93,13 @@ func (cr *configReaderImpl) parsePluginConfig(path string, file fs.DirEntry) (*p
 		return nil, err
 	}
 
-	return cfg.mapToPluginsFromConfig(), nil
+	apps := cfg.mapToPluginsFromConfig()
+	apps.Filename = file.Name()
+	return apps, nil
 }
 
-func validateRequiredField(apps []*pluginsAsConfig) error {
+func validateRequiredField(apps []*pluginsAsConfig) []error {
+	var errs []error
 	for i := range apps {
 		var errStrings []string
 		for index, app := range apps[i].Apps {
@@ -101,14 +112,20 @@ func validateRequiredField(apps []*pluginsAsConfig) error {
 		}
 
 		if len(errStrings) != 0 {
-			return fmt.Errorf(strings.Join(errStrings, "\n"))
+			errs = append(errs, fmt.Errorf(strings.Join(errStrings, "\n")))
 		}
 	}
 
-	return nil
+	return errs
 }
 
-func (cr *configReaderImpl) validatePluginsConfig(ctx context.Context, apps []*pluginsAsConfig) error {
+func (cr *configReaderImpl) validatePluginsConfig(ctx context.Context, apps []*pluginsAsConfig) []error {
+	// the installed plugins are unknown when the files are validated offline
+	if cr.pluginStore == nil {
+		return nil
+	}
+
+	var errs []error
 	for i := range apps {
 		if apps[i].Apps == nil {
 			continue
@@ -116,12 +133,12 @@ func (cr *configReaderImpl) validatePluginsConfig(ctx context.Context, apps []*p
 
 		for _, app := range apps[i].Apps {
 			if _, exists := cr.pluginStore.Plugin(ctx, app.PluginID); !exists {
-				return fmt.Errorf("plugin not installed: %q", app.PluginID)
+				errs = append(errs, fmt.Errorf("plugin not installed: %q", app.PluginID))
 			}
 		}
 	}
 
-	return nil
+	return errs
 }
 
 func checkOrgIDAndOrgName(apps []*pluginsAsConfig) {
@@ -4,15 +4,26 @@ import (
 	"context"
 	"errors"
 
+	"github.com/grafana/grafana/pkg/infra/kvstore"
 	"github.com/grafana/grafana/pkg/infra/log"
 	"github.com/grafana/grafana/pkg/services/org"
 	"github.com/grafana/grafana/pkg/services/pluginsintegration/pluginsettings"
 	"github.com/grafana/grafana/pkg/services/pluginsintegration/pluginstore"
+	"github.com/grafana/grafana/pkg/services/provisioning/utils"
 )
 
+// pruneNamespace is the kvstore namespace used to track the plugin settings provisioned by files with prune enabled.
+const pruneNamespace = "provisioning.plugins"
+
 // Provision scans a directory for provisioning config files
 // and provisions the app in those files.
-func Provision(ctx context.Context, configDirectory string, pluginStore pluginstore.Store, pluginSettings pluginsettings.Service, orgService org.Service) error {
+func Provision(ctx context.Context, configDirectory string, pluginStore pluginstore.Store, pluginSettings pluginsettings.Service, orgService org.Service, kvStore kvstore.KVStore) error {
+	return ProvisionFiles(ctx, configDirectory, nil, pluginStore, pluginSettings, orgService, kvStore)
+}
+
+// ProvisionFiles provisions only the apps of the config files with the given names in
+// configDirectory. The other files are still read to find the plugin settings to prune.
+func ProvisionFiles(ctx context.Context, configDirectory string, filenames []string, pluginStore pluginstore.Store, pluginSettings pluginsettings.Service, orgService org.Service, kvStore kvstore.KVStore) error {
 	logger := log.New("provisioning.plugins")
 	ap := PluginProvisioner{
 		log:            logger,
@@ -20,7 +31,10 @@ func Provision(ctx context.Context, configDirectory string, pluginStore pluginst
 		pluginSettings: pluginSettings,
 		orgService:     orgService,
 	}
-	return ap.applyChanges(ctx, configDirectory)
+	if kvStore != nil {
+		ap.pruneTracker = utils.NewPruneTracker(kvStore, pruneNamespace, logger)
+	}
+	return ap.applyFileChanges(ctx, configDirectory, filenames)
 }
 
 // PluginProvisioner is responsible for provisioning apps based on
@@ -30,9 +44,11 @@ type PluginProvisioner struct {
 	cfgProvider    configReader
 	pluginSettings pluginsettings.Service
 	orgService     org.Service
+	pruneTracker   *utils.PruneTracker
 }
 
-func (ap *PluginProvisioner) apply(ctx context.Context, cfg *pluginsAsConfig) error {
+// resolveOrgIDs sets the org ID of the apps that reference their org by name.
+func (ap *PluginProvisioner) resolveOrgIDs(ctx context.Context, cfg *pluginsAsConfig) error {
 	for _, app := range cfg.Apps {
 		if app.OrgID == 0 && app.OrgName != "" {
 			getOrgQuery := &org.GetOrgByNameQuery{Name: app.OrgName}
@@ -44,7 +60,12 @@ func (ap *PluginProvisioner) apply(ctx context.Context, cfg *pluginsAsConfig) er
 		} else if app.OrgID < 0 {
 			app.OrgID = 1
 		}
+	}
+	return nil
+}
 
+func (ap *PluginProvisioner) apply(ctx context.Context, cfg *pluginsAsConfig) error {
+	for _, app := range cfg.Apps {
 		ps, err := ap.pluginSettings.GetPluginSettingByPluginID(ctx, &pluginsettings.GetByPluginIDArgs{
 			OrgID:    app.OrgID,
 			PluginID: app.PluginID,
@@ -75,16 +96,53 @@ func (ap *PluginProvisioner) apply(ctx context.Context, cfg *pluginsAsConfig) er
 }
 
 func (ap *PluginProvisioner) applyChanges(ctx context.Context, configPath string) error {
+	return ap.applyFileChanges(ctx, configPath, nil)
+}
+
+// applyFileChanges provisions the apps of the files with the given names, or of all files
+// when filenames is nil, and prunes the plugin settings no longer declared in any file.
+func (ap *PluginProvisioner) applyFileChanges(ctx context.Context, configPath string, filenames []string) error {
 	configs, err := ap.cfgProvider.readConfig(ctx, configPath)
 	if err != nil {
 		return err
 	}
 
 	for _, cfg := range configs {
+		if err := ap.resolveOrgIDs(ctx, cfg); err != nil {
+			return err
+		}
+		if !utils.ContainsFile(filenames, cfg.Filename) {
+			continue
+		}
 		if err := ap.apply(ctx, cfg); err != nil {
 			return err
 		}
 	}
 
-	return nil
+	return ap.prunePluginSettings(ctx, configs)
+}
+
+// prunePluginSettings deletes the plugin settings that were provisioned by a file with prune enabled
+// but are no longer declared in any provisioning file. It relies on resolveOrgIDs having been called for every config.
+func (ap *PluginProvisioner) prunePluginSettings(ctx context.Context, configs []*pluginsAsConfig) error {
+	if ap.pruneTracker == nil {
+		return nil
+	}
+
+	files := make([]utils.PruneFile, 0, len(configs))
+	for _, cfg := range configs {
+		file := utils.PruneFile{Name: cfg.Filename, Prune: cfg.Prune, DryRun: cfg.PruneDryRun}
+		for _, app := range cfg.Apps {
+			file.Items = append(file.Items, utils.ProvisionedItem{OrgID: app.OrgID, Name: app.PluginID})
+		}
+		files = append(files, file)
+	}
+
+	return ap.pruneTracker.Prune(ctx, files, func(ctx context.Context, item utils.ProvisionedItem) error {
+		ap.log.Info("Deleting app settings no longer present in provisioning files", "type", item.Name, "orgId", item.OrgID)
+		return ap.pluginSettings.DeletePluginSetting(ctx, &pluginsettings.DeleteArgs{
+			OrgID:    item.OrgID,
+			PluginID: item.Name,
+		})
+	})
 }
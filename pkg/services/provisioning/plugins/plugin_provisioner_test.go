@@ -7,10 +7,12 @@ import (
 
 	"github.com/stretchr/testify/require"
 
+	"github.com/grafana/grafana/pkg/infra/kvstore"
 	"github.com/grafana/grafana/pkg/infra/log"
 	"github.com/grafana/grafana/pkg/services/org"
 	"github.com/grafana/grafana/pkg/services/org/orgtest"
 	"github.com/grafana/grafana/pkg/services/pluginsintegration/pluginsettings"
+	"github.com/grafana/grafana/pkg/services/provisioning/utils"
 )
 
 func TestPluginProvisioner(t *testing.T) {
@@ -68,6 +70,61 @@ func TestPluginProvisioner(t *testing.T) {
 			require.Equal(t, tc.ExpectedSecureJSONData, cmd.SecureJSONData)
 		}
 	})
+
+	t.Run("Should apply only the configurations of the changed files", func(t *testing.T) {
+		reader := &testConfigReader{result: []*pluginsAsConfig{
+			{Filename: "a.yaml", Apps: []*appFromConfig{{PluginID: "test-plugin", OrgName: "Org 4", Enabled: true}}},
+			{Filename: "b.yaml", Apps: []*appFromConfig{{PluginID: "test-plugin-2", OrgID: 1, Enabled: true}}},
+		}}
+		store := &mockStore{}
+		orgMock := orgtest.NewOrgServiceFake()
+		orgMock.ExpectedOrg = &org.Org{ID: 4}
+		ap := PluginProvisioner{log: log.New("test"), cfgProvider: reader, pluginSettings: store, orgService: orgMock}
+
+		require.NoError(t, ap.applyFileChanges(context.Background(), "", []string{"b.yaml"}))
+		require.Len(t, store.updateRequests, 1)
+		require.Equal(t, "test-plugin-2", store.updateRequests[0].PluginID)
+		// the org of the unchanged files is resolved as well, so they are pruned correctly
+		require.Equal(t, int64(4), reader.result[0].Apps[0].OrgID)
+	})
+
+	t.Run("Should prune plugin settings removed from a file with prune enabled", func(t *testing.T) {
+		reader := &testConfigReader{result: []*pluginsAsConfig{
+			{
+				Filename: "apps.yaml",
+				Prune:    true,
+				Apps: []*appFromConfig{
+					{PluginID: "test-plugin", OrgID: 2, Enabled: true},
+					{PluginID: "test-plugin-2", OrgID: 3, Enabled: true},
+				},
+			},
+		}}
+		store := &mockStore{}
+		ap := PluginProvisioner{
+			log:            log.New("test"),
+			cfgProvider:    reader,
+			pluginSettings: store,
+			pruneTracker:   utils.NewPruneTracker(kvstore.NewFakeKVStore(), pruneNamespace, log.New("test")),
+		}
+
+		require.NoError(t, ap.applyChanges(context.Background(), ""))
+		require.Empty(t, store.deleteRequests)
+
+		reader.result[0].PruneDryRun = true
+		reader.result[0].Apps = reader.result[0].Apps[:1]
+		require.NoError(t, ap.applyChanges(context.Background(), ""))
+		require.Empty(t, store.deleteRequests)
+
+		reader.result[0].PruneDryRun = false
+		require.NoError(t, ap.applyChanges(context.Background(), ""))
+		require.Equal(t, []*pluginsettings.DeleteArgs{{OrgID: 3, PluginID: "test-plugin-2"}}, store.deleteRequests)
+
+		// removing the file prunes everything it provisioned
+		reader.result = nil
+		require.NoError(t, ap.applyChanges(context.Background(), ""))
+		require.Len(t, store.deleteRequests, 2)
+		require.Equal(t, &pluginsettings.DeleteArgs{OrgID: 2, PluginID: "test-plugin"}, store.deleteRequests[1])
+	})
 }
 
 type testConfigReader struct {
@@ -81,6 +138,7 @@ func (tcr *testConfigReader) readConfig(_ context.Context, _ string) ([]*plugins
 
 type mockStore struct {
 	updateRequests []*pluginsettings.UpdateArgs
+	deleteRequests []*pluginsettings.DeleteArgs
 }
 
 func (m *mockStore) GetPluginSettingByPluginID(_ context.Context, args *pluginsettings.GetByPluginIDArgs) (*pluginsettings.DTO, error) {
@@ -98,6 +156,11 @@ func (m *mockStore) UpdatePluginSetting(_ context.Context, args *pluginsettings.
 	return nil
 }
 
+func (m *mockStore) DeletePluginSetting(_ context.Context, args *pluginsettings.DeleteArgs) error {
+	m.deleteRequests = append(m.deleteRequests, args)
+	return nil
+}
+
 func (m *mockStore) UpdatePluginSettingPluginVersion(_ context.Context, _ *pluginsettings.UpdatePluginVersionArgs) error {
 	return nil
 }
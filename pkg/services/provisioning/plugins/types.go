@@ -6,6 +6,11 @@ import "github.com/grafana/grafana/pkg/services/provisioning/values"
 // to this type.
 type pluginsAsConfig struct {
 	Apps []*appFromConfig
+	// Filename is the name of the provisioning file the config was read from.
+	Filename string
+	// Prune deletes plugin settings this file provisioned earlier that aren't declared anymore.
+	Prune       bool
+	PruneDryRun bool
 }
 
 type appFromConfig struct {
@@ -30,7 +35,9 @@ type appFromConfigV0 struct {
 
 // pluginsAsConfigV0 is a mapping for zero version configs. This is mapped to its normalised version.
 type pluginsAsConfigV0 struct {
-	Apps []*appFromConfigV0 `json:"apps" yaml:"apps"`
+	Apps        []*appFromConfigV0 `json:"apps" yaml:"apps"`
+	Prune       values.BoolValue   `json:"prune" yaml:"prune"`
+	PruneDryRun values.BoolValue   `json:"pruneDryRun" yaml:"pruneDryRun"`
 }
 
 // mapToPluginsFromConfig maps config syntax to a normalized notificationsAsConfig object. Every version
@@ -41,6 +48,9 @@ func (cfg *pluginsAsConfigV0) mapToPluginsFromConfig() *pluginsAsConfig {
 		return r
 	}
 
+	r.Prune = cfg.Prune.Value()
+	r.PruneDryRun = cfg.PruneDryRun.Value()
+
 	for _, app := range cfg.Apps {
 		r.Apps = append(r.Apps, &appFromConfig{
 			OrgID:          app.OrgID.Value(),
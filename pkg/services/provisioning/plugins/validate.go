@@ -0,0 +1,21 @@
+package plugins
+
+import (
+	"context"
+	"os"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+)
+
+// Validate reads all plugin provisioning files in configDirectory with the config reader used
+// at runtime, but without accessing the database or the plugin store, so it does not check
+// whether the plugins are installed. It returns all the problems found in the files.
+func Validate(ctx context.Context, configDirectory string) []error {
+	if _, err := os.Stat(configDirectory); os.IsNotExist(err) {
+		return nil
+	}
+
+	cr := &configReaderImpl{log: log.New("provisioning.plugins")}
+	_, errs := cr.readConfigs(ctx, configDirectory)
+	return errs
+}
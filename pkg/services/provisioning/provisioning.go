@@ -7,6 +7,7 @@ import (
 	"sync"
 
 	"github.com/grafana/grafana/pkg/infra/db"
+	"github.com/grafana/grafana/pkg/infra/kvstore"
 	"github.com/grafana/grafana/pkg/infra/log"
 	"github.com/grafana/grafana/pkg/registry"
 	"github.com/grafana/grafana/pkg/services/accesscontrol"
@@ -27,6 +28,7 @@ import (
 	"github.com/grafana/grafana/pkg/services/provisioning/datasources"
 	"github.com/grafana/grafana/pkg/services/provisioning/notifiers"
 	"github.com/grafana/grafana/pkg/services/provisioning/plugins"
+	"github.com/grafana/grafana/pkg/services/provisioning/utils"
 	"github.com/grafana/grafana/pkg/services/quota"
 	"github.com/grafana/grafana/pkg/services/searchV2"
 	"github.com/grafana/grafana/pkg/services/secrets"
@@ -51,6 +53,7 @@ func ProvideService(
 	quotaService quota.Service,
 	secrectService secrets.Service,
 	orgService org.Service,
+	kvStore kvstore.KVStore,
 ) (*ProvisioningServiceImpl, error) {
 	s := &ProvisioningServiceImpl{
 		Cfg:                          cfg,
@@ -75,6 +78,7 @@ func ProvideService(
 		secretService:                secrectService,
 		log:                          log.New("provisioning"),
 		orgService:                   orgService,
+		kvStore:                      kvStore,
 	}
 	return s, nil
 }
@@ -107,8 +111,8 @@ func NewProvisioningServiceImpl() *ProvisioningServiceImpl {
 func newProvisioningServiceImpl(
 	newDashboardProvisioner dashboards.DashboardProvisionerFactory,
 	provisionNotifiers func(context.Context, string, notifiers.Manager, org.Service, encryption.Internal, *notifications.NotificationService) error,
-	provisionDatasources func(context.Context, string, datasources.Store, datasources.CorrelationsStore, org.Service) error,
-	provisionPlugins func(context.Context, string, pluginstore.Store, pluginsettings.Service, org.Service) error,
+	provisionDatasources func(context.Context, string, datasources.Store, datasources.CorrelationsStore, org.Service, kvstore.KVStore) error,
+	provisionPlugins func(context.Context, string, pluginstore.Store, pluginsettings.Service, org.Service, kvstore.KVStore) error,
 ) *ProvisioningServiceImpl {
 	return &ProvisioningServiceImpl{
 		log:                     log.New("provisioning"),
@@ -132,8 +136,8 @@ type ProvisioningServiceImpl struct {
 	newDashboardProvisioner      dashboards.DashboardProvisionerFactory
 	dashboardProvisioner         dashboards.DashboardProvisioner
 	provisionNotifiers           func(context.Context, string, notifiers.Manager, org.Service, encryption.Internal, *notifications.NotificationService) error
-	provisionDatasources         func(context.Context, string, datasources.Store, datasources.CorrelationsStore, org.Service) error
-	provisionPlugins             func(context.Context, string, pluginstore.Store, pluginsettings.Service, org.Service) error
+	provisionDatasources         func(context.Context, string, datasources.Store, datasources.CorrelationsStore, org.Service, kvstore.KVStore) error
+	provisionPlugins             func(context.Context, string, pluginstore.Store, pluginsettings.Service, org.Service, kvstore.KVStore) error
 	provisionAlerting            func(context.Context, prov_alerting.ProvisionerConfig) error
 	mutex                        sync.Mutex
 	dashboardProvisioningService dashboardservice.DashboardProvisioningService
@@ -145,6 +149,7 @@ type ProvisioningServiceImpl struct {
 	searchService                searchV2.SearchService
 	quotaService                 quota.Service
 	secretService                secrets.Service
+	kvStore                      kvstore.KVStore
 }
 
 func (ps *ProvisioningServiceImpl) RunInitProvisioners(ctx context.Context) error {
@@ -185,6 +190,10 @@ func (ps *ProvisioningServiceImpl) Run(ctx context.Context) error {
 		ps.searchService.TriggerReIndex()
 	}
 
+	if ps.Cfg.Provisioning.Watch {
+		ps.watchProvisioningDirectories(ctx)
+	}
+
 	for {
 		// Wait for unlock. This is tied to new dashboardProvisioner to be instantiated before we start polling.
 		ps.mutex.Lock()
@@ -192,7 +201,11 @@ func (ps *ProvisioningServiceImpl) Run(ctx context.Context) error {
 		// non-deterministically take one of the route possibly going into one polling loop before exiting.
 		pollingContext, cancelFun := context.WithCancel(context.Background())
 		ps.pollingCtxCancel = cancelFun
-		ps.dashboardProvisioner.PollChanges(pollingContext)
+		if ps.Cfg.Provisioning.Watch {
+			ps.dashboardProvisioner.WatchChanges(pollingContext, ps.Cfg.Provisioning.WatchDebounce)
+		} else {
+			ps.dashboardProvisioner.PollChanges(pollingContext)
+		}
 		ps.mutex.Unlock()
 
 		select {
@@ -209,7 +222,7 @@ func (ps *ProvisioningServiceImpl) Run(ctx context.Context) error {
 
 func (ps *ProvisioningServiceImpl) ProvisionDatasources(ctx context.Context) error {
 	datasourcePath := filepath.Join(ps.Cfg.ProvisioningPath, "datasources")
-	if err := ps.provisionDatasources(ctx, datasourcePath, ps.datasourceService, ps.correlationsService, ps.orgService); err != nil {
+	if err := ps.provisionDatasources(ctx, datasourcePath, ps.datasourceService, ps.correlationsService, ps.orgService, ps.kvStore); err != nil {
 		err = fmt.Errorf("%v: %w", "Datasource provisioning error", err)
 		ps.log.Error("Failed to provision data sources", "error", err)
 		return err
@@ -219,7 +232,7 @@ func (ps *ProvisioningServiceImpl) ProvisionDatasources(ctx context.Context) err
 
 func (ps *ProvisioningServiceImpl) ProvisionPlugins(ctx context.Context) error {
 	appPath := filepath.Join(ps.Cfg.ProvisioningPath, "plugins")
-	if err := ps.provisionPlugins(ctx, appPath, ps.pluginStore, ps.pluginsSettings, ps.orgService); err != nil {
+	if err := ps.provisionPlugins(ctx, appPath, ps.pluginStore, ps.pluginsSettings, ps.orgService, ps.kvStore); err != nil {
 		err = fmt.Errorf("%v: %w", "app provisioning error", err)
 		ps.log.Error("Failed to provision plugins", "error", err)
 		return err
@@ -239,7 +252,7 @@ func (ps *ProvisioningServiceImpl) ProvisionNotifications(ctx context.Context) e
 
 func (ps *ProvisioningServiceImpl) ProvisionDashboards(ctx context.Context) error {
 	dashboardPath := filepath.Join(ps.Cfg.ProvisioningPath, "dashboards")
-	dashProvisioner, err := ps.newDashboardProvisioner(ctx, dashboardPath, ps.dashboardProvisioningService, ps.orgService, ps.dashboardService)
+	dashProvisioner, err := ps.newDashboardProvisioner(ctx, dashboardPath, ps.Cfg.DataPath, ps.dashboardProvisioningService, ps.orgService, ps.dashboardService)
 	if err != nil {
 		return fmt.Errorf("%v: %w", "Failed to create provisioner", err)
 	}
@@ -261,6 +274,12 @@ func (ps *ProvisioningServiceImpl) ProvisionDashboards(ctx context.Context) erro
 }
 
 func (ps *ProvisioningServiceImpl) ProvisionAlerting(ctx context.Context) error {
+	return ps.provisionAlertingFiles(ctx, nil)
+}
+
+// provisionAlertingFiles provisions the alerting resources of the files with the given
+// names, or of all files when filenames is nil.
+func (ps *ProvisioningServiceImpl) provisionAlertingFiles(ctx context.Context, filenames []string) error {
 	alertingPath := filepath.Join(ps.Cfg.ProvisioningPath, "alerting")
 	st := store.DBstore{
 		Cfg:              ps.Cfg.UnifiedAlerting,
@@ -273,6 +292,7 @@ func (ps *ProvisioningServiceImpl) ProvisionAlerting(ctx context.Context) error
 	ruleService := provisioning.NewAlertRuleService(
 		st,
 		st,
+		&st,
 		ps.dashboardService,
 		ps.quotaService,
 		ps.SQLStore,
@@ -285,6 +305,7 @@ func (ps *ProvisioningServiceImpl) ProvisionAlerting(ctx context.Context) error
 		st, ps.SQLStore, ps.Cfg.UnifiedAlerting, ps.log)
 	mutetimingsService := provisioning.NewMuteTimingService(&st, st, &st, ps.log)
 	templateService := provisioning.NewTemplateService(&st, st, &st, ps.log)
+	alertRuleTemplateService := provisioning.NewAlertRuleTemplateService(st, st, st, ps.SQLStore, ps.log, ps.ac)
 	cfg := prov_alerting.ProvisionerConfig{
 		Path:                       alertingPath,
 		RuleService:                *ruleService,
@@ -294,6 +315,8 @@ func (ps *ProvisioningServiceImpl) ProvisionAlerting(ctx context.Context) error
 		NotificiationPolicyService: *notificationPolicyService,
 		MuteTimingService:          *mutetimingsService,
 		TemplateService:            *templateService,
+		AlertRuleTemplateService:   *alertRuleTemplateService,
+		Files:                      filenames,
 	}
 	return ps.provisionAlerting(ctx, cfg)
 }
@@ -306,6 +329,67 @@ func (ps *ProvisioningServiceImpl) GetAllowUIUpdatesFromConfig(name string) bool
 	return ps.dashboardProvisioner.GetAllowUIUpdatesFromConfig(name)
 }
 
+// watchProvisioningDirectories reapplies the datasource, plugin and alerting provisioning
+// of the config files that changed in their directory.
+func (ps *ProvisioningServiceImpl) watchProvisioningDirectories(ctx context.Context) {
+	watches := map[string]func(context.Context, []string) error{
+		"datasources": func(ctx context.Context, filenames []string) error {
+			path := filepath.Join(ps.Cfg.ProvisioningPath, "datasources")
+			return datasources.ProvisionFiles(ctx, path, filenames, ps.datasourceService, ps.correlationsService, ps.orgService, ps.kvStore)
+		},
+		"plugins": func(ctx context.Context, filenames []string) error {
+			path := filepath.Join(ps.Cfg.ProvisioningPath, "plugins")
+			return plugins.ProvisionFiles(ctx, path, filenames, ps.pluginStore, ps.pluginsSettings, ps.orgService, ps.kvStore)
+		},
+		"alerting": ps.provisionAlertingFiles,
+	}
+	ps.watchDirectories(ctx, watches)
+}
+
+// watchDirectories calls the provision function of each directory of the provisioning path
+// with the config files that changed in it. The provisioning is applied one directory at a
+// time, while holding the same lock as the dashboard provisioning.
+func (ps *ProvisioningServiceImpl) watchDirectories(ctx context.Context, watches map[string]func(context.Context, []string) error) {
+	for dir, provision := range watches {
+		dir, provision := dir, provision
+		path := filepath.Join(ps.Cfg.ProvisioningPath, dir)
+		onChange := func(ctx context.Context, changed []string) {
+			filenames := changedConfigFiles(path, changed)
+			if len(filenames) == 0 {
+				return
+			}
+
+			ps.mutex.Lock()
+			defer ps.mutex.Unlock()
+
+			ps.log.Info("Provisioning files changed, reapplying", "type", dir, "files", filenames)
+			if err := provision(ctx, filenames); err != nil {
+				ps.log.Error("Failed to reapply provisioning", "type", dir, "error", err)
+			}
+		}
+
+		if err := utils.WatchDirectory(ctx, ps.log, path, ps.Cfg.Provisioning.WatchDebounce, onChange); err != nil {
+			ps.log.Warn("Failed to watch provisioning directory", "type", dir, "path", path, "error", err)
+		}
+	}
+}
+
+// changedConfigFiles returns the names of the config files directly in dir among the
+// changed paths, as the provisioners don't read config files from subdirectories.
+func changedConfigFiles(dir string, changed []string) []string {
+	var filenames []string
+	for _, p := range changed {
+		if filepath.Dir(p) != filepath.Clean(dir) {
+			continue
+		}
+		ext := filepath.Ext(p)
+		if ext == ".yaml" || ext == ".yml" || ext == ".json" {
+			filenames = append(filenames, filepath.Base(p))
+		}
+	}
+	return filenames
+}
+
 func (ps *ProvisioningServiceImpl) cancelPolling() {
 	if ps.pollingCtxCancel != nil {
 		ps.log.Debug("Stop polling for dashboard changes")
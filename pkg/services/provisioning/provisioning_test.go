@@ -3,10 +3,14 @@ package provisioning
 import (
 	"context"
 	"errors"
+	"os"
+	"path/filepath"
+	"sync/atomic"
 	"testing"
 	"time"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 
 	dashboardstore "github.com/grafana/grafana/pkg/services/dashboards"
 	"github.com/grafana/grafana/pkg/services/org"
@@ -82,6 +86,60 @@ type serviceTestStruct struct {
 	service *ProvisioningServiceImpl
 }
 
+func TestWatchDirectoriesSerializesProvisioning(t *testing.T) {
+	provisioningPath := t.TempDir()
+	for _, dir := range []string{"datasources", "plugins"} {
+		require.NoError(t, os.Mkdir(filepath.Join(provisioningPath, dir), 0750))
+	}
+
+	cfg := setting.NewCfg()
+	cfg.ProvisioningPath = provisioningPath
+	cfg.Provisioning.WatchDebounce = 10 * time.Millisecond
+	service := newProvisioningServiceImpl(nil, nil, nil, nil)
+	service.Cfg = cfg
+
+	var running, maxRunning int32
+	provisioned := make(chan string, 2)
+	provision := func(dir string) func(context.Context, []string) error {
+		return func(context.Context, []string) error {
+			n := atomic.AddInt32(&running, 1)
+			defer atomic.AddInt32(&running, -1)
+			for {
+				max := atomic.LoadInt32(&maxRunning)
+				if n <= max || atomic.CompareAndSwapInt32(&maxRunning, max, n) {
+					break
+				}
+			}
+			time.Sleep(100 * time.Millisecond)
+			provisioned <- dir
+			return nil
+		}
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+	service.watchDirectories(ctx, map[string]func(context.Context, []string) error{
+		"datasources": provision("datasources"),
+		"plugins":     provision("plugins"),
+	})
+
+	// both directories change at once
+	require.NoError(t, os.WriteFile(filepath.Join(provisioningPath, "datasources", "datasources.yaml"), []byte("apiVersion: 1"), 0600))
+	require.NoError(t, os.WriteFile(filepath.Join(provisioningPath, "plugins", "plugins.yaml"), []byte("apiVersion: 1"), 0600))
+
+	var dirs []string
+	for len(dirs) < 2 {
+		select {
+		case dir := <-provisioned:
+			dirs = append(dirs, dir)
+		case <-time.After(5 * time.Second):
+			t.Fatalf("the changes were not provisioned, got %v", dirs)
+		}
+	}
+	assert.ElementsMatch(t, []string{"datasources", "plugins"}, dirs)
+	assert.Equal(t, int32(1), atomic.LoadInt32(&maxRunning), "the provisioning of the two directories overlapped")
+}
+
 func setup() *serviceTestStruct {
 	serviceTest := &serviceTestStruct{}
 	serviceTest.waitTimeout = time.Second
@@ -95,7 +153,7 @@ func setup() *serviceTestStruct {
 	}
 
 	serviceTest.service = newProvisioningServiceImpl(
-		func(context.Context, string, dashboardstore.DashboardProvisioningService, org.Service, utils.DashboardStore) (dashboards.DashboardProvisioner, error) {
+		func(context.Context, string, string, dashboardstore.DashboardProvisioningService, org.Service, utils.DashboardStore) (dashboards.DashboardProvisioner, error) {
 			return serviceTest.mock, nil
 		},
 		nil,
@@ -0,0 +1,166 @@
+package utils
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/grafana/grafana/pkg/infra/kvstore"
+	"github.com/grafana/grafana/pkg/infra/log"
+)
+
+// ProvisionedItem identifies a resource created from a provisioning file, like a data source
+// by name or the settings of an app plugin by plugin ID.
+type ProvisionedItem struct {
+	OrgID int64  `json:"orgId"`
+	Name  string `json:"name"`
+}
+
+// PruneFile describes the prune settings of a provisioning file and the items it currently declares.
+type PruneFile struct {
+	Name   string
+	Prune  bool
+	DryRun bool
+	Items  []ProvisionedItem
+}
+
+// DeleteItemFunc deletes a provisioned item that is no longer declared in any provisioning file.
+type DeleteItemFunc func(ctx context.Context, item ProvisionedItem) error
+
+type pruneRecord struct {
+	DryRun bool              `json:"dryRun"`
+	Items  []ProvisionedItem `json:"items"`
+}
+
+// PruneTracker remembers the items provisioned by every file with prune enabled, so items that
+// were removed from the file, or whose file was removed, can be deleted on the next run.
+type PruneTracker struct {
+	kv  *kvstore.NamespacedKVStore
+	log log.Logger
+}
+
+// NewPruneTracker returns a PruneTracker that stores its records in namespace.
+func NewPruneTracker(kv kvstore.KVStore, namespace string, logger log.Logger) *PruneTracker {
+	return &PruneTracker{
+		kv:  kvstore.WithNamespace(kv, 0, namespace),
+		log: logger,
+	}
+}
+
+// Prune calls deleteItem for every item that a prune enabled file declared in the previous run but
+// that isn't declared by any file anymore. Files removed since the previous run are pruned with the
+// settings they had when they were last applied. In dry-run mode the stale items are only reported
+// and stay tracked, so they are deleted once dry-run is disabled.
+func (t *PruneTracker) Prune(ctx context.Context, files []PruneFile, deleteItem DeleteItemFunc) error {
+	previous, err := t.records(ctx)
+	if err != nil {
+		return err
+	}
+
+	declared := map[ProvisionedItem]struct{}{}
+	current := map[string]PruneFile{}
+	for _, f := range files {
+		for _, item := range f.Items {
+			declared[item] = struct{}{}
+		}
+		current[f.Name] = f
+	}
+
+	for name, record := range previous {
+		f, exists := current[name]
+		if exists && !f.Prune {
+			// prune was disabled for the file, so we forget about what it provisioned
+			if err := t.kv.Del(ctx, name); err != nil {
+				return err
+			}
+			continue
+		}
+
+		dryRun := record.DryRun
+		if exists {
+			dryRun = f.DryRun
+		}
+
+		var stale []ProvisionedItem
+		for _, item := range record.Items {
+			if _, ok := declared[item]; !ok {
+				stale = append(stale, item)
+			}
+		}
+
+		if dryRun {
+			for _, item := range stale {
+				t.log.Info("Prune dry run: item is no longer provisioned and would be deleted", "file", name, "orgId", item.OrgID, "name", item.Name)
+			}
+		} else {
+			for _, item := range stale {
+				if err := deleteItem(ctx, item); err != nil {
+					return fmt.Errorf("failed to prune %q in org %d: %w", item.Name, item.OrgID, err)
+				}
+			}
+			stale = nil
+		}
+
+		if !exists {
+			if len(stale) == 0 {
+				if err := t.kv.Del(ctx, name); err != nil {
+					return err
+				}
+				continue
+			}
+			if err := t.save(ctx, name, pruneRecord{DryRun: dryRun, Items: stale}); err != nil {
+				return err
+			}
+			continue
+		}
+
+		f.Items = append(f.Items, stale...)
+		current[name] = f
+	}
+
+	for name, f := range current {
+		if !f.Prune {
+			continue
+		}
+		if err := t.save(ctx, name, pruneRecord{DryRun: f.DryRun, Items: f.Items}); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (t *PruneTracker) records(ctx context.Context) (map[string]pruneRecord, error) {
+	keys, err := t.kv.Keys(ctx, "")
+	if err != nil {
+		return nil, err
+	}
+
+	records := make(map[string]pruneRecord, len(keys))
+	for _, key := range keys {
+		value, ok, err := t.kv.Get(ctx, key.Key)
+		if err != nil {
+			return nil, err
+		}
+		if !ok {
+			continue
+		}
+
+		var record pruneRecord
+		if err := json.Unmarshal([]byte(value), &record); err != nil {
+			t.log.Warn("Ignoring invalid prune record", "file", key.Key, "error", err)
+			continue
+		}
+		records[key.Key] = record
+	}
+
+	return records, nil
+}
+
+func (t *PruneTracker) save(ctx context.Context, name string, record pruneRecord) error {
+	value, err := json.Marshal(record)
+	if err != nil {
+		return err
+	}
+	return t.kv.Set(ctx, name, string(value))
+}
@@ -13,7 +13,12 @@ type DashboardStore interface {
 	GetDashboard(context.Context, *dashboards.GetDashboardQuery) (*dashboards.Dashboard, error)
 }
 
+// CheckOrgExists returns an error if the org with the given ID doesn't exist. The check is
+// skipped without an org service, like when provisioning files are validated offline.
 func CheckOrgExists(ctx context.Context, orgService org.Service, orgID int64) error {
+	if orgService == nil {
+		return nil
+	}
 	query := org.GetOrgByIDQuery{ID: orgID}
 	_, err := orgService.GetByID(ctx, &query)
 	if err != nil {
@@ -24,3 +29,25 @@ func CheckOrgExists(ctx context.Context, orgService org.Service, orgID int64) er
 	}
 	return nil
 }
+
+// ContainsFile reports whether filename is one of filenames. A nil list of file names
+// selects every file.
+func ContainsFile(filenames []string, filename string) bool {
+	if filenames == nil {
+		return true
+	}
+	for _, f := range filenames {
+		if f == filename {
+			return true
+		}
+	}
+	return false
+}
+
+// DatasourceReference is a reference to a data source by UID found while validating provisioning files.
+type DatasourceReference struct {
+	OrgID int64
+	UID   string
+	// Location describes where the reference was found, for example the file and the rule or panel.
+	Location string
+}
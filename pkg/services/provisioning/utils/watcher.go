@@ -0,0 +1,128 @@
+package utils
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+)
+
+// DefaultWatchDebounce is the time changes are collected for before they are handed over
+// to the change handler when no other debounce duration is configured.
+const DefaultWatchDebounce = time.Second
+
+// ChangeHandler is called with the deduplicated and sorted list of paths that
+// changed on disk since the last call.
+type ChangeHandler func(ctx context.Context, changed []string)
+
+// WatchDirectory watches path and all of its subdirectories for changes and calls
+// onChange once no further change happened for the debounce duration. Hidden
+// directories are not watched, but when a hidden entry of path changes, like the
+// ..data symlink swapped by Kubernetes when a mounted ConfigMap is updated, all the
+// entries of path are reported as changed. It returns an error if the watcher cannot be set up,
+// in which case the caller is expected to fall back to polling. The watcher stops
+// when ctx is done.
+func WatchDirectory(ctx context.Context, logger log.Logger, path string, debounce time.Duration, onChange ChangeHandler) error {
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return err
+	}
+
+	if err := addRecursive(watcher, path); err != nil {
+		_ = watcher.Close()
+		return err
+	}
+
+	if debounce <= 0 {
+		debounce = DefaultWatchDebounce
+	}
+
+	go func() {
+		defer func() {
+			if err := watcher.Close(); err != nil {
+				logger.Warn("Failed to close file watcher", "path", path, "error", err)
+			}
+		}()
+
+		pending := map[string]struct{}{}
+		timer := time.NewTimer(debounce)
+		if !timer.Stop() {
+			<-timer.C
+		}
+
+		for {
+			select {
+			case event, ok := <-watcher.Events:
+				if !ok {
+					return
+				}
+				if event.Has(fsnotify.Chmod) && !event.Has(fsnotify.Write) {
+					continue
+				}
+				if event.Has(fsnotify.Create) && !strings.HasPrefix(filepath.Base(event.Name), ".") {
+					if fi, err := os.Stat(event.Name); err == nil && fi.IsDir() {
+						if err := addRecursive(watcher, event.Name); err != nil {
+							logger.Warn("Failed to watch new directory", "path", event.Name, "error", err)
+						}
+					}
+				}
+
+				if filepath.Dir(event.Name) == filepath.Clean(path) && strings.HasPrefix(filepath.Base(event.Name), ".") {
+					// the visible entries are symlinks through the hidden entry, so any of them might have changed
+					entries, err := os.ReadDir(path)
+					if err != nil {
+						logger.Warn("Failed to read watched directory", "path", path, "error", err)
+					}
+					for _, entry := range entries {
+						if !strings.HasPrefix(entry.Name(), ".") {
+							pending[filepath.Join(path, entry.Name())] = struct{}{}
+						}
+					}
+				} else {
+					pending[event.Name] = struct{}{}
+				}
+				timer.Reset(debounce)
+			case err, ok := <-watcher.Errors:
+				if !ok {
+					return
+				}
+				logger.Error("File watcher error", "path", path, "error", err)
+			case <-timer.C:
+				changed := make([]string, 0, len(pending))
+				for p := range pending {
+					changed = append(changed, p)
+				}
+				sort.Strings(changed)
+				pending = map[string]struct{}{}
+
+				onChange(ctx, changed)
+			case <-ctx.Done():
+				timer.Stop()
+				return
+			}
+		}
+	}()
+
+	return nil
+}
+
+func addRecursive(watcher *fsnotify.Watcher, root string) error {
+	return filepath.Walk(root, func(p string, fi os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if !fi.IsDir() {
+			return nil
+		}
+		if p != root && strings.HasPrefix(fi.Name(), ".") {
+			return filepath.SkipDir
+		}
+		return watcher.Add(p)
+	})
+}
@@ -0,0 +1,99 @@
+package utils
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+)
+
+func TestWatchDirectory(t *testing.T) {
+	dir := t.TempDir()
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+
+	changes := make(chan []string, 10)
+	err := WatchDirectory(ctx, log.New("test-logger"), dir, 50*time.Millisecond, func(_ context.Context, changed []string) {
+		changes <- changed
+	})
+	require.NoError(t, err)
+
+	waitForChange := func(t *testing.T, expected string) {
+		t.Helper()
+		timeout := time.After(5 * time.Second)
+		for {
+			select {
+			case changed := <-changes:
+				for _, p := range changed {
+					if p == expected {
+						return
+					}
+				}
+			case <-timeout:
+				t.Fatalf("no change reported for %s", expected)
+			}
+		}
+	}
+
+	t.Run("reports written files once debounced", func(t *testing.T) {
+		file := filepath.Join(dir, "dashboard.json")
+		require.NoError(t, os.WriteFile(file, []byte("{}"), 0600))
+		require.NoError(t, os.WriteFile(file, []byte(`{"title": "updated"}`), 0600))
+		waitForChange(t, file)
+	})
+
+	t.Run("watches new subdirectories", func(t *testing.T) {
+		sub := filepath.Join(dir, "team-a")
+		require.NoError(t, os.Mkdir(sub, 0750))
+		waitForChange(t, sub)
+
+		file := filepath.Join(sub, "dashboard.json")
+		require.NoError(t, os.WriteFile(file, []byte("{}"), 0600))
+		waitForChange(t, file)
+	})
+
+	t.Run("reports removed files", func(t *testing.T) {
+		file := filepath.Join(dir, "dashboard.json")
+		require.NoError(t, os.Remove(file))
+		waitForChange(t, file)
+	})
+}
+
+func TestWatchDirectoryReportsSymlinkSwap(t *testing.T) {
+	// mimic the layout of a ConfigMap mounted by Kubernetes
+	dir := t.TempDir()
+	require.NoError(t, os.Mkdir(filepath.Join(dir, "..v1"), 0750))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "..v1", "config.yaml"), []byte("v1"), 0600))
+	require.NoError(t, os.Symlink("..v1", filepath.Join(dir, "..data")))
+	require.NoError(t, os.Symlink(filepath.Join("..data", "config.yaml"), filepath.Join(dir, "config.yaml")))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+	changes := make(chan []string, 10)
+	err := WatchDirectory(ctx, log.New("test-logger"), dir, 50*time.Millisecond, func(_ context.Context, changed []string) {
+		changes <- changed
+	})
+	require.NoError(t, err)
+
+	require.NoError(t, os.Mkdir(filepath.Join(dir, "..v2"), 0750))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "..v2", "config.yaml"), []byte("v2"), 0600))
+	require.NoError(t, os.Symlink("..v2", filepath.Join(dir, "..data_tmp")))
+	require.NoError(t, os.Rename(filepath.Join(dir, "..data_tmp"), filepath.Join(dir, "..data")))
+
+	select {
+	case changed := <-changes:
+		require.Equal(t, []string{filepath.Join(dir, "config.yaml")}, changed)
+	case <-time.After(5 * time.Second):
+		t.Fatal("the symlink swap was not reported")
+	}
+}
+
+func TestWatchDirectoryFailsForMissingPath(t *testing.T) {
+	err := WatchDirectory(context.Background(), log.New("test-logger"), filepath.Join(t.TempDir(), "missing"), time.Second, func(context.Context, []string) {})
+	require.Error(t, err)
+}
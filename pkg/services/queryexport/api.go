@@ -0,0 +1,134 @@
+package queryexport
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+
+	"github.com/grafana/grafana/pkg/api/dtos"
+	"github.com/grafana/grafana/pkg/api/response"
+	"github.com/grafana/grafana/pkg/api/routing"
+	ac "github.com/grafana/grafana/pkg/services/accesscontrol"
+	contextmodel "github.com/grafana/grafana/pkg/services/contexthandler/model"
+	"github.com/grafana/grafana/pkg/services/dashboards"
+	"github.com/grafana/grafana/pkg/services/datasources"
+	"github.com/grafana/grafana/pkg/web"
+)
+
+func (s *Service) registerAPIEndpoints(routeRegister routing.RouteRegister) {
+	authorize := ac.Middleware(s.accessControl)
+	dashboardRead := ac.EvalPermission(dashboards.ActionDashboardsRead, dashboards.ScopeDashboardsProvider.GetResourceScopeUID(ac.Parameter(":uid")))
+
+	routeRegister.Group("/api/export", func(r routing.RouteRegister) {
+		r.Get("/dashboards/:uid/panels/:panelId", authorize(ac.EvalAll(dashboardRead, ac.EvalPermission(datasources.ActionQuery))), routing.Wrap(s.handleExportPanel))
+		r.Post("/query", authorize(ac.EvalPermission(datasources.ActionQuery)), routing.Wrap(s.handleExportQuery))
+	})
+}
+
+// handleExportPanel exports the results of the queries of a dashboard panel.
+// The time range and variables are overridden with the from, to and var-<name>
+// query parameters, the same way as in the dashboard URL.
+func (s *Service) handleExportPanel(c *contextmodel.ReqContext) response.Response {
+	format, err := ParseFormat(c.Query("format"))
+	if err != nil {
+		return response.Err(err)
+	}
+
+	panelID, err := strconv.ParseInt(web.Params(c.Req)[":panelId"], 10, 64)
+	if err != nil {
+		return response.Error(http.StatusBadRequest, "panelId is invalid", err)
+	}
+
+	q := PanelQuery{
+		OrgID:         c.SignedInUser.GetOrgID(),
+		DashboardUID:  web.Params(c.Req)[":uid"],
+		PanelID:       panelID,
+		From:          c.Query("from"),
+		To:            c.Query("to"),
+		Variables:     map[string][]string{},
+		MaxDataPoints: c.QueryInt64("maxDataPoints"),
+	}
+	for name, values := range c.Req.URL.Query() {
+		if variable, ok := strings.CutPrefix(name, "var-"); ok && variable != "" {
+			q.Variables[variable] = values
+		}
+	}
+
+	resp, err := s.QueryPanel(c.Req.Context(), c.SignedInUser, q)
+	if err != nil {
+		return response.ErrOrFallback(http.StatusInternalServerError, "Failed to query panel", err)
+	}
+
+	frames, err := ResponseFrames(resp)
+	if err != nil {
+		return response.Err(err)
+	}
+	return exportResponse(format, frames, fmt.Sprintf("%s-panel-%d", q.DashboardUID, panelID))
+}
+
+// handleExportQuery exports the results of raw queries, with the same body as /api/ds/query.
+func (s *Service) handleExportQuery(c *contextmodel.ReqContext) response.Response {
+	format, err := ParseFormat(c.Query("format"))
+	if err != nil {
+		return response.Err(err)
+	}
+
+	reqDTO := dtos.MetricRequest{}
+	if err := web.Bind(c.Req, &reqDTO); err != nil {
+		return response.Error(http.StatusBadRequest, "bad request data", err)
+	}
+
+	resp, err := s.Query(c.Req.Context(), c.SignedInUser, reqDTO)
+	if err != nil {
+		return response.ErrOrFallback(http.StatusBadRequest, "Failed to run queries", err)
+	}
+
+	frames, err := ResponseFrames(resp)
+	if err != nil {
+		return response.Err(err)
+	}
+	return exportResponse(format, frames, "query")
+}
+
+// exportResponse returns the response streaming the export file. The frames are validated first, as
+// the status of the response is sent before the file and can't report the errors of the writers.
+func exportResponse(format Format, frames data.Frames, filename string) response.Response {
+	if err := Validate(format, frames); err != nil {
+		return response.Err(err)
+	}
+
+	header := make(http.Header)
+	header.Set("Content-Type", format.ContentType())
+	header.Set("Content-Disposition", fmt.Sprintf(`attachment;filename="%s.%s"`, filename, format))
+	return &fileResponse{format: format, frames: frames, header: header}
+}
+
+// fileResponse streams the frames in the export format instead of buffering the file.
+type fileResponse struct {
+	format Format
+	frames data.Frames
+	header http.Header
+}
+
+func (r *fileResponse) Status() int {
+	return http.StatusOK
+}
+
+func (r *fileResponse) Body() []byte {
+	return nil
+}
+
+func (r *fileResponse) WriteTo(ctx *contextmodel.ReqContext) {
+	header := ctx.Resp.Header()
+	for k, v := range r.header {
+		header[k] = v
+	}
+	ctx.Resp.WriteHeader(http.StatusOK)
+
+	if err := Write(ctx.Resp, r.format, r.frames); err != nil {
+		ctx.Logger.Error("Error writing export to response", "format", r.format, "err", err)
+	}
+}
@@ -0,0 +1,56 @@
+package queryexport
+
+import (
+	"github.com/grafana/grafana/pkg/util/errutil"
+)
+
+var (
+	ErrPanelNotFound  = errutil.NotFound("queryexport.panelNotFound", errutil.WithPublicMessage("Panel not found"))
+	ErrNoQueries      = errutil.BadRequest("queryexport.noQueries", errutil.WithPublicMessage("The panel has no queries to export"))
+	ErrInvalidFormat  = errutil.BadRequest("queryexport.invalidFormat", errutil.WithPublicMessage("Unsupported export format, use csv or xlsx"))
+	ErrInvalidRequest = errutil.BadRequest("queryexport.invalidRequest")
+	ErrQueryFailed    = errutil.BadRequest("queryexport.queryFailed")
+)
+
+// Format is the file format of an export.
+type Format string
+
+const (
+	FormatCSV  Format = "csv"
+	FormatXLSX Format = "xlsx"
+)
+
+// ParseFormat returns the format with the given name, defaulting to CSV.
+func ParseFormat(name string) (Format, error) {
+	switch Format(name) {
+	case "", FormatCSV:
+		return FormatCSV, nil
+	case FormatXLSX:
+		return FormatXLSX, nil
+	default:
+		return "", ErrInvalidFormat.Errorf("unsupported export format %q", name)
+	}
+}
+
+// ContentType returns the MIME type of the format.
+func (f Format) ContentType() string {
+	if f == FormatXLSX {
+		return "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+	}
+	return "text/csv; charset=utf-8"
+}
+
+// PanelQuery selects the panel whose queries are exported, and overrides the
+// time range and template variables of its dashboard.
+type PanelQuery struct {
+	OrgID        int64
+	DashboardUID string
+	PanelID      int64
+	// From and To default to the time range of the dashboard.
+	From string
+	To   string
+	// Variables override the current values of the dashboard template variables.
+	Variables map[string][]string
+	// MaxDataPoints defaults to the one of the panel, or 1500.
+	MaxDataPoints int64
+}
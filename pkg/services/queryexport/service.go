@@ -0,0 +1,247 @@
+package queryexport
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend"
+	"github.com/grafana/grafana-plugin-sdk-go/backend/gtime"
+
+	"github.com/grafana/grafana/pkg/api/dtos"
+	"github.com/grafana/grafana/pkg/api/routing"
+	"github.com/grafana/grafana/pkg/components/simplejson"
+	"github.com/grafana/grafana/pkg/expr"
+	"github.com/grafana/grafana/pkg/infra/log"
+	ac "github.com/grafana/grafana/pkg/services/accesscontrol"
+	"github.com/grafana/grafana/pkg/services/auth/identity"
+	"github.com/grafana/grafana/pkg/services/dashboards"
+	"github.com/grafana/grafana/pkg/services/datasources"
+	"github.com/grafana/grafana/pkg/services/libraryelements"
+	"github.com/grafana/grafana/pkg/services/libraryelements/model"
+	"github.com/grafana/grafana/pkg/services/query"
+	"github.com/grafana/grafana/pkg/tsdb/intervalv2"
+	"github.com/grafana/grafana/pkg/tsdb/legacydata"
+)
+
+// Service runs the queries of dashboard panels, or raw queries, on behalf of
+// the caller so that their results can be exported as files.
+type Service struct {
+	accessControl         ac.AccessControl
+	dashboardService      dashboards.DashboardService
+	dataSourceService     datasources.DataSourceService
+	libraryElementService libraryelements.Service
+	queryService          query.Service
+	intervalCalculator    intervalv2.Calculator
+	log                   log.Logger
+}
+
+func ProvideService(
+	routeRegister routing.RouteRegister,
+	accessControl ac.AccessControl,
+	dashboardService dashboards.DashboardService,
+	dataSourceService datasources.DataSourceService,
+	libraryElementService libraryelements.Service,
+	queryService query.Service,
+) *Service {
+	s := &Service{
+		accessControl:         accessControl,
+		dashboardService:      dashboardService,
+		dataSourceService:     dataSourceService,
+		libraryElementService: libraryElementService,
+		queryService:          queryService,
+		intervalCalculator:    intervalv2.NewCalculator(),
+		log:                   log.New("queryexport"),
+	}
+	s.registerAPIEndpoints(routeRegister)
+	return s
+}
+
+// QueryPanel runs the queries of a dashboard panel with the permissions of user.
+func (s *Service) QueryPanel(ctx context.Context, user identity.Requester, q PanelQuery) (*backend.QueryDataResponse, error) {
+	dashboard, err := s.dashboardService.GetDashboard(ctx, &dashboards.GetDashboardQuery{OrgID: q.OrgID, UID: q.DashboardUID})
+	if err != nil {
+		return nil, err
+	}
+
+	panel, err := s.findPanel(ctx, user, dashboard.Data, q)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := s.buildPanelRequest(dashboard.Data, panel, q)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.setDefaultDataSource(ctx, q, req.Queries); err != nil {
+		return nil, err
+	}
+
+	return s.Query(ctx, user, req)
+}
+
+// Query runs raw queries with the permissions of user.
+func (s *Service) Query(ctx context.Context, user identity.Requester, req dtos.MetricRequest) (*backend.QueryDataResponse, error) {
+	return s.queryService.QueryData(ctx, user, false, req)
+}
+
+// findPanel returns the panel of the dashboard to export. The model of a library panel
+// is loaded as the dashboard only holds a reference to it.
+func (s *Service) findPanel(ctx context.Context, user identity.Requester, dashboard *simplejson.Json, q PanelQuery) (*simplejson.Json, error) {
+	panel := findPanel(dashboard.Get("panels").MustArray(), q.PanelID)
+	if panel == nil {
+		return nil, ErrPanelNotFound.Errorf("panel %d not found in dashboard %s", q.PanelID, q.DashboardUID)
+	}
+
+	uid := panel.Get("libraryPanel").Get("uid").MustString()
+	if uid == "" {
+		return panel, nil
+	}
+
+	element, err := s.libraryElementService.GetElement(ctx, user, model.GetLibraryElementCommand{UID: uid, FolderName: dashboards.RootFolderName})
+	if err != nil {
+		if errors.Is(err, model.ErrLibraryElementNotFound) {
+			return nil, ErrPanelNotFound.Errorf("library panel %s of panel %d not found: %w", uid, q.PanelID, err)
+		}
+		return nil, err
+	}
+
+	libraryPanel, err := simplejson.NewJson(element.Model)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load library panel %s: %w", uid, err)
+	}
+	libraryPanel.Set("id", q.PanelID)
+	return libraryPanel, nil
+}
+
+// setDefaultDataSource sets the default data source of the organization to the queries
+// without a data source, as done by the frontend when the panel has no data source either.
+func (s *Service) setDefaultDataSource(ctx context.Context, q PanelQuery, queries []*simplejson.Json) error {
+	var defaultDS *datasources.DataSource
+	for _, query := range queries {
+		if hasDatasource(query) {
+			continue
+		}
+
+		if defaultDS == nil {
+			var err error
+			defaultDS, err = s.dataSourceService.GetDefaultDataSource(ctx, &datasources.GetDefaultDataSourceQuery{OrgID: q.OrgID})
+			if err != nil {
+				if errors.Is(err, datasources.ErrDataSourceNotFound) {
+					return ErrInvalidRequest.Errorf("panel %d of dashboard %s has no data source and there is no default data source", q.PanelID, q.DashboardUID)
+				}
+				return err
+			}
+		}
+		query.Set("datasource", map[string]any{"type": defaultDS.Type, "uid": defaultDS.UID})
+	}
+	return nil
+}
+
+// buildPanelRequest returns the request running the queries of a panel, with
+// the template variables of the dashboard replaced by their values.
+func (s *Service) buildPanelRequest(dashboard *simplejson.Json, panel *simplejson.Json, q PanelQuery) (dtos.MetricRequest, error) {
+	from, to := q.From, q.To
+	if from == "" || to == "" {
+		from = dashboard.Get("time").Get("from").MustString("now-6h")
+		to = dashboard.Get("time").Get("to").MustString("now")
+	}
+	timeRange := legacydata.NewDataTimeRange(from, to)
+	start, err := timeRange.ParseFrom()
+	if err != nil {
+		return dtos.MetricRequest{}, ErrInvalidRequest.Errorf("invalid from %q: %w", from, err)
+	}
+	end, err := timeRange.ParseTo()
+	if err != nil {
+		return dtos.MetricRequest{}, ErrInvalidRequest.Errorf("invalid to %q: %w", to, err)
+	}
+
+	maxDataPoints := q.MaxDataPoints
+	if maxDataPoints <= 0 {
+		maxDataPoints = panel.Get("maxDataPoints").MustInt64(intervalv2.DefaultRes)
+	}
+	minInterval := time.Millisecond
+	if interval, err := gtime.ParseInterval(panel.Get("interval").MustString()); err == nil && interval > 0 {
+		minInterval = interval
+	}
+	interval := s.intervalCalculator.Calculate(backend.TimeRange{From: start, To: end}, minInterval, maxDataPoints)
+
+	variables := dashboardVariables(dashboard, q.Variables)
+	queries := panelQueries(panel)
+	for i, query := range queries {
+		query.Set("intervalMs", interval.Milliseconds())
+		query.Set("maxDataPoints", maxDataPoints)
+		queries[i] = simplejson.NewFromAny(interpolate(query.Interface(), variables))
+	}
+	if len(queries) == 0 {
+		return dtos.MetricRequest{}, ErrNoQueries.Errorf("panel %d of dashboard %s has no queries", q.PanelID, q.DashboardUID)
+	}
+
+	return dtos.MetricRequest{
+		From:    from,
+		To:      to,
+		Queries: queries,
+	}, nil
+}
+
+// findPanel returns the panel with the given id, including the panels of collapsed rows.
+func findPanel(panels []any, id int64) *simplejson.Json {
+	for _, panelObj := range panels {
+		panel := simplejson.NewFromAny(panelObj)
+		if panel.Get("type").MustString() == "row" {
+			if found := findPanel(panel.Get("panels").MustArray(), id); found != nil {
+				return found
+			}
+			continue
+		}
+		if panel.Get("id").MustInt64() == id {
+			return panel
+		}
+	}
+	return nil
+}
+
+// panelQueries returns the queries of a panel that contribute to its data. Hidden
+// queries are kept when the panel has expressions, because they may use them.
+func panelQueries(panel *simplejson.Json) []*simplejson.Json {
+	targets := panel.Get("targets").MustArray()
+
+	hasExpression := false
+	for _, targetObj := range targets {
+		if expr.NodeTypeFromDatasourceUID(datasourceUID(simplejson.NewFromAny(targetObj))) == expr.TypeCMDNode {
+			hasExpression = true
+		}
+	}
+
+	queries := make([]*simplejson.Json, 0, len(targets))
+	for _, targetObj := range targets {
+		query := simplejson.NewFromAny(targetObj)
+		if !hasExpression && query.Get("hide").MustBool() {
+			continue
+		}
+		if !hasDatasource(query) {
+			query.Set("datasource", panel.Get("datasource").Interface())
+		}
+		queries = append(queries, query)
+	}
+	return queries
+}
+
+// hasDatasource returns whether a query has a data source, saved dashboards
+// using null for the default data source.
+func hasDatasource(query *simplejson.Json) bool {
+	return datasourceUID(query) != "" || query.Get("datasourceId").MustInt64() > 0
+}
+
+func datasourceUID(query *simplejson.Json) string {
+	uid := query.Get("datasource").Get("uid").MustString()
+
+	// before 8.3 special types could be sent as datasource (expr)
+	if uid == "" {
+		uid = query.Get("datasource").MustString()
+	}
+
+	return uid
+}
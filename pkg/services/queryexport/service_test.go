@@ -0,0 +1,252 @@
+package queryexport
+
+import (
+	"context"
+	"testing"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/api/dtos"
+	"github.com/grafana/grafana/pkg/components/simplejson"
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/services/auth/identity"
+	"github.com/grafana/grafana/pkg/services/dashboards"
+	"github.com/grafana/grafana/pkg/services/datasources"
+	"github.com/grafana/grafana/pkg/services/libraryelements"
+	"github.com/grafana/grafana/pkg/services/libraryelements/model"
+	"github.com/grafana/grafana/pkg/services/query"
+	"github.com/grafana/grafana/pkg/services/user"
+	"github.com/grafana/grafana/pkg/tsdb/intervalv2"
+)
+
+const testDashboard = `{
+	"time": {"from": "now-1h", "to": "now"},
+	"templating": {"list": [
+		{"name": "job", "current": {"value": ["api", "web"]}},
+		{"name": "env", "current": {"value": "prod"}},
+		{"name": "region", "current": {"value": ["$__all"]}, "options": [{"value": "$__all"}, {"value": "eu"}, {"value": "us"}]},
+		{"name": "ds", "current": {"value": "prom-uid"}}
+	]},
+	"panels": [
+		{
+			"id": 1,
+			"datasource": {"type": "prometheus", "uid": "$ds"},
+			"maxDataPoints": 100,
+			"targets": [
+				{"refId": "A", "expr": "up{job=~\"$job\", env=\"${env}\", region=~\"${region:regex}\"}"},
+				{"refId": "B", "expr": "hidden", "hide": true}
+			]
+		},
+		{
+			"type": "row",
+			"collapsed": true,
+			"panels": [
+				{
+					"id": 2,
+					"datasource": {"type": "prometheus", "uid": "prom-uid"},
+					"targets": [
+						{"refId": "A", "expr": "rate(x[$__interval])", "hide": true},
+						{"refId": "B", "datasource": {"type": "__expr__", "uid": "__expr__"}, "type": "math", "expression": "$A * 2"}
+					]
+				}
+			]
+		},
+		{"id": 3, "type": "text"},
+		{
+			"id": 4,
+			"datasource": null,
+			"targets": [{"refId": "A", "datasource": null, "expr": "up"}]
+		},
+		{"id": 5, "libraryPanel": {"uid": "lib-uid", "name": "Library panel"}}
+	]
+}`
+
+func TestBuildPanelRequest(t *testing.T) {
+	dashboard, err := simplejson.NewJson([]byte(testDashboard))
+	require.NoError(t, err)
+	s := &Service{intervalCalculator: intervalv2.NewCalculator(), log: log.NewNopLogger()}
+	buildPanelRequest := func(q PanelQuery) (dtos.MetricRequest, error) {
+		panel, err := s.findPanel(context.Background(), &user.SignedInUser{OrgID: 1}, dashboard, q)
+		if err != nil {
+			return dtos.MetricRequest{}, err
+		}
+		return s.buildPanelRequest(dashboard, panel, q)
+	}
+
+	t.Run("interpolates the variables and skips hidden queries", func(t *testing.T) {
+		req, err := buildPanelRequest(PanelQuery{DashboardUID: "dash", PanelID: 1})
+		require.NoError(t, err)
+		assert.Equal(t, "now-1h", req.From)
+		assert.Equal(t, "now", req.To)
+		require.Len(t, req.Queries, 1)
+
+		q := req.Queries[0]
+		assert.Equal(t, `up{job=~"(api|web)", env="prod", region=~"(eu|us)"}`, q.Get("expr").MustString())
+		assert.Equal(t, "prom-uid", q.Get("datasource").Get("uid").MustString())
+		assert.Equal(t, int64(100), q.Get("maxDataPoints").MustInt64())
+		assert.Equal(t, int64(30000), q.Get("intervalMs").MustInt64())
+	})
+
+	t.Run("uses the overrides", func(t *testing.T) {
+		req, err := buildPanelRequest(PanelQuery{
+			DashboardUID: "dash",
+			PanelID:      1,
+			From:         "now-7d",
+			To:           "now-1d",
+			Variables:    map[string][]string{"job": {"db"}, "region": {"$__all"}},
+		})
+		require.NoError(t, err)
+		assert.Equal(t, "now-7d", req.From)
+		assert.Equal(t, `up{job=~"db", env="prod", region=~"(eu|us)"}`, req.Queries[0].Get("expr").MustString())
+	})
+
+	t.Run("keeps hidden queries used by expressions in collapsed rows", func(t *testing.T) {
+		req, err := buildPanelRequest(PanelQuery{DashboardUID: "dash", PanelID: 2})
+		require.NoError(t, err)
+		require.Len(t, req.Queries, 2)
+		assert.Equal(t, "rate(x[$__interval])", req.Queries[0].Get("expr").MustString())
+		assert.Equal(t, "$A * 2", req.Queries[1].Get("expression").MustString())
+	})
+
+	t.Run("fails for missing panels and panels without queries", func(t *testing.T) {
+		_, err := buildPanelRequest(PanelQuery{DashboardUID: "dash", PanelID: 42})
+		require.ErrorIs(t, err, ErrPanelNotFound)
+
+		_, err = buildPanelRequest(PanelQuery{DashboardUID: "dash", PanelID: 3})
+		require.ErrorIs(t, err, ErrNoQueries)
+	})
+
+	t.Run("fails for invalid time ranges", func(t *testing.T) {
+		_, err := buildPanelRequest(PanelQuery{DashboardUID: "dash", PanelID: 1, From: "yesterday", To: "now"})
+		require.ErrorIs(t, err, ErrInvalidRequest)
+	})
+}
+
+func TestQueryPanel(t *testing.T) {
+	dashboard, err := simplejson.NewJson([]byte(testDashboard))
+	require.NoError(t, err)
+
+	dashboardService := dashboards.NewFakeDashboardService(t)
+	dashboardService.On("GetDashboard", mock.Anything, &dashboards.GetDashboardQuery{OrgID: 1, UID: "dash"}).
+		Return(&dashboards.Dashboard{UID: "dash", OrgID: 1, Data: dashboard}, nil)
+
+	signedInUser := &user.SignedInUser{UserID: 1, OrgID: 1}
+	queryService := query.NewFakeQueryService(t)
+	queryService.On("QueryData", mock.Anything, signedInUser, false, mock.AnythingOfType("dtos.MetricRequest")).
+		Run(func(args mock.Arguments) {
+			req := args.Get(3).(dtos.MetricRequest)
+			assert.Len(t, req.Queries, 1)
+		}).
+		Return(&backend.QueryDataResponse{}, nil)
+
+	s := &Service{
+		dashboardService:   dashboardService,
+		queryService:       queryService,
+		intervalCalculator: intervalv2.NewCalculator(),
+		log:                log.NewNopLogger(),
+	}
+
+	_, err = s.QueryPanel(context.Background(), signedInUser, PanelQuery{OrgID: 1, DashboardUID: "dash", PanelID: 1})
+	require.NoError(t, err)
+}
+
+type fakeLibraryElementService struct {
+	libraryelements.Service
+	elements map[string]model.LibraryElementDTO
+}
+
+func (f *fakeLibraryElementService) GetElement(_ context.Context, _ identity.Requester, cmd model.GetLibraryElementCommand) (model.LibraryElementDTO, error) {
+	element, ok := f.elements[cmd.UID]
+	if !ok {
+		return model.LibraryElementDTO{}, model.ErrLibraryElementNotFound
+	}
+	return element, nil
+}
+
+type fakeDataSourceService struct {
+	datasources.DataSourceService
+	defaultDS *datasources.DataSource
+}
+
+func (f *fakeDataSourceService) GetDefaultDataSource(_ context.Context, _ *datasources.GetDefaultDataSourceQuery) (*datasources.DataSource, error) {
+	if f.defaultDS == nil {
+		return nil, datasources.ErrDataSourceNotFound
+	}
+	return f.defaultDS, nil
+}
+
+func TestQueryPanel_ResolvesPanels(t *testing.T) {
+	dashboard, err := simplejson.NewJson([]byte(testDashboard))
+	require.NoError(t, err)
+
+	dashboardService := dashboards.NewFakeDashboardService(t)
+	dashboardService.On("GetDashboard", mock.Anything, &dashboards.GetDashboardQuery{OrgID: 1, UID: "dash"}).
+		Return(&dashboards.Dashboard{UID: "dash", OrgID: 1, Data: dashboard}, nil)
+
+	signedInUser := &user.SignedInUser{UserID: 1, OrgID: 1}
+	var queries []*simplejson.Json
+	queryService := query.NewFakeQueryService(t)
+	queryService.On("QueryData", mock.Anything, signedInUser, false, mock.AnythingOfType("dtos.MetricRequest")).
+		Run(func(args mock.Arguments) {
+			queries = args.Get(3).(dtos.MetricRequest).Queries
+		}).
+		Return(&backend.QueryDataResponse{}, nil).Maybe()
+
+	dataSourceService := &fakeDataSourceService{defaultDS: &datasources.DataSource{UID: "default-uid", Type: "prometheus"}}
+	s := &Service{
+		dashboardService:  dashboardService,
+		dataSourceService: dataSourceService,
+		libraryElementService: &fakeLibraryElementService{elements: map[string]model.LibraryElementDTO{
+			"lib-uid": {UID: "lib-uid", Model: []byte(`{"datasource": {"type": "loki", "uid": "loki-uid"}, "targets": [{"refId": "A", "expr": "{job=\"$job\"}"}]}`)},
+		}},
+		queryService:       queryService,
+		intervalCalculator: intervalv2.NewCalculator(),
+		log:                log.NewNopLogger(),
+	}
+
+	t.Run("uses the default data source for panels without a data source", func(t *testing.T) {
+		_, err := s.QueryPanel(context.Background(), signedInUser, PanelQuery{OrgID: 1, DashboardUID: "dash", PanelID: 4})
+		require.NoError(t, err)
+		require.Len(t, queries, 1)
+		assert.Equal(t, "default-uid", queries[0].Get("datasource").Get("uid").MustString())
+		assert.Equal(t, "prometheus", queries[0].Get("datasource").Get("type").MustString())
+	})
+
+	t.Run("runs the queries of library panels", func(t *testing.T) {
+		_, err := s.QueryPanel(context.Background(), signedInUser, PanelQuery{OrgID: 1, DashboardUID: "dash", PanelID: 5})
+		require.NoError(t, err)
+		require.Len(t, queries, 1)
+		assert.Equal(t, "loki-uid", queries[0].Get("datasource").Get("uid").MustString())
+		assert.Equal(t, `{job="(api|web)"}`, queries[0].Get("expr").MustString())
+	})
+
+	t.Run("fails without a default data source", func(t *testing.T) {
+		dataSourceService.defaultDS = nil
+		_, err := s.QueryPanel(context.Background(), signedInUser, PanelQuery{OrgID: 1, DashboardUID: "dash", PanelID: 4})
+		require.ErrorIs(t, err, ErrInvalidRequest)
+	})
+}
+
+func TestInterpolateString(t *testing.T) {
+	variables := map[string][]string{"one": {"a.b"}, "many": {"x", "y"}}
+
+	testCases := map[string]string{
+		"$one":              "a.b",
+		"${one}":            "a.b",
+		"[[one]]":           "a.b",
+		"${one:regex}":      `a\.b`,
+		"$many":             "(x|y)",
+		"${many:csv}":       "x,y",
+		"${many:pipe}":      "x|y",
+		"${many:glob}":      "{x,y}",
+		"[[many:csv]]":      "x,y",
+		"$unknown $__range": "$unknown $__range",
+		"$one-$many":        "a.b-(x|y)",
+	}
+	for input, expected := range testCases {
+		assert.Equal(t, expected, interpolateString(input, variables), input)
+	}
+}
@@ -0,0 +1,140 @@
+package queryexport
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/grafana/grafana/pkg/components/simplejson"
+)
+
+const allValue = "$__all"
+
+// variableRegexp matches the $var, ${var}, ${var:format} and [[var]] syntaxes
+// of dashboard template variables.
+var variableRegexp = regexp.MustCompile(`\$(\w+)|\[\[(\w+?)(?::(\w+))?\]\]|\$\{(\w+)(?::(\w+))?\}`)
+
+// dashboardVariables returns the values of the template variables of a dashboard.
+// The overrides take precedence over the current values saved in the dashboard.
+func dashboardVariables(dashboard *simplejson.Json, overrides map[string][]string) map[string][]string {
+	variables := make(map[string][]string)
+	for _, variableObj := range dashboard.Get("templating").Get("list").MustArray() {
+		variable := simplejson.NewFromAny(variableObj)
+		name := variable.Get("name").MustString()
+		if name == "" {
+			continue
+		}
+
+		values, ok := overrides[name]
+		if !ok {
+			values = jsonStrings(variable.Get("current").Get("value"))
+		}
+
+		if len(values) == 1 && values[0] == allValue {
+			if custom := variable.Get("allValue").MustString(); custom != "" {
+				values = []string{custom}
+			} else {
+				values = nil
+				for _, optionObj := range variable.Get("options").MustArray() {
+					if v := simplejson.NewFromAny(optionObj).Get("value").MustString(); v != allValue {
+						values = append(values, v)
+					}
+				}
+			}
+		}
+		variables[name] = values
+	}
+
+	// overrides of variables missing from the dashboard are still applied
+	for name, values := range overrides {
+		if _, ok := variables[name]; !ok {
+			variables[name] = values
+		}
+	}
+	return variables
+}
+
+func jsonStrings(j *simplejson.Json) []string {
+	if s, err := j.String(); err == nil {
+		return []string{s}
+	}
+	return j.MustStringArray()
+}
+
+// interpolate replaces the template variables in the strings of a query model.
+// Built-in variables such as $__interval are left to the data sources.
+func interpolate(v any, variables map[string][]string) any {
+	switch value := v.(type) {
+	case string:
+		return interpolateString(value, variables)
+	case map[string]any:
+		result := make(map[string]any, len(value))
+		for k, item := range value {
+			result[k] = interpolate(item, variables)
+		}
+		return result
+	case []any:
+		result := make([]any, len(value))
+		for i, item := range value {
+			result[i] = interpolate(item, variables)
+		}
+		return result
+	default:
+		return v
+	}
+}
+
+func interpolateString(s string, variables map[string][]string) string {
+	if !strings.ContainsAny(s, "$[") {
+		return s
+	}
+
+	return variableRegexp.ReplaceAllStringFunc(s, func(match string) string {
+		groups := variableRegexp.FindStringSubmatch(match)
+		name, format := groups[1], ""
+		switch {
+		case groups[2] != "":
+			name, format = groups[2], groups[3]
+		case groups[4] != "":
+			name, format = groups[4], groups[5]
+		}
+
+		values, ok := variables[name]
+		if !ok {
+			return match
+		}
+		return formatValues(values, format)
+	})
+}
+
+// formatValues formats the values of a variable the way the frontend does for
+// the given format. Without a format, several values are formatted as a regular
+// expression, which is what the multi-value variables are mostly used for.
+func formatValues(values []string, format string) string {
+	switch format {
+	case "csv":
+		return strings.Join(values, ",")
+	case "pipe":
+		return strings.Join(values, "|")
+	case "glob":
+		if len(values) == 1 {
+			return values[0]
+		}
+		return "{" + strings.Join(values, ",") + "}"
+	case "raw":
+		return strings.Join(values, ",")
+	case "regex", "":
+		if len(values) == 1 {
+			if format == "regex" {
+				return regexp.QuoteMeta(values[0])
+			}
+			return values[0]
+		}
+		quoted := make([]string, len(values))
+		for i, v := range values {
+			quoted[i] = regexp.QuoteMeta(v)
+		}
+		return "(" + strings.Join(quoted, "|") + ")"
+	default:
+		return strings.Join(values, ",")
+	}
+}
@@ -0,0 +1,152 @@
+package queryexport
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend"
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+)
+
+// ResponseFrames returns the frames of a query response ordered by query refId.
+// It fails if any of the queries failed, to avoid exporting partial results.
+func ResponseFrames(resp *backend.QueryDataResponse) (data.Frames, error) {
+	refIDs := make([]string, 0, len(resp.Responses))
+	for refID := range resp.Responses {
+		refIDs = append(refIDs, refID)
+	}
+	sort.Strings(refIDs)
+
+	frames := make(data.Frames, 0, len(refIDs))
+	for _, refID := range refIDs {
+		res := resp.Responses[refID]
+		if res.Error != nil {
+			return nil, ErrQueryFailed.Errorf("query %s failed: %w", refID, res.Error)
+		}
+		for _, frame := range res.Frames {
+			if frame.RefID == "" {
+				frame.RefID = refID
+			}
+			frames = append(frames, frame)
+		}
+	}
+	return frames, nil
+}
+
+// Validate checks that frames can be exported in the given format, so that the request
+// can be rejected before the file is streamed.
+func Validate(format Format, frames data.Frames) error {
+	switch format {
+	case FormatCSV:
+		return nil
+	case FormatXLSX:
+		return validateXLSX(frames)
+	default:
+		return ErrInvalidFormat.Errorf("unsupported export format %q", format)
+	}
+}
+
+// Write writes frames to w in the given format. With CSV, frames are written one
+// after the other, each with its own header and separated by an empty line.
+// With XLSX, each frame is written to its own sheet.
+func Write(w io.Writer, format Format, frames data.Frames) error {
+	switch format {
+	case FormatCSV:
+		return writeCSV(w, frames)
+	case FormatXLSX:
+		return writeXLSX(w, frames)
+	default:
+		return ErrInvalidFormat.Errorf("unsupported export format %q", format)
+	}
+}
+
+func writeCSV(w io.Writer, frames data.Frames) error {
+	cw := csv.NewWriter(w)
+	for i, frame := range frames {
+		if i > 0 {
+			if err := cw.Write([]string{}); err != nil {
+				return err
+			}
+		}
+
+		header := columnNames(frame)
+		for col := range header {
+			header[col] = escapeFormula(header[col])
+		}
+		if err := cw.Write(header); err != nil {
+			return err
+		}
+
+		record := make([]string, len(frame.Fields))
+		for row := 0; row < frame.Rows(); row++ {
+			for col, field := range frame.Fields {
+				record[col] = formatValue(fieldValue(field, row))
+			}
+			if err := cw.Write(record); err != nil {
+				return err
+			}
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// columnNames returns the names of the fields of a frame, including their labels.
+func columnNames(frame *data.Frame) []string {
+	names := make([]string, len(frame.Fields))
+	for i, field := range frame.Fields {
+		switch {
+		case field.Config != nil && field.Config.DisplayNameFromDS != "":
+			names[i] = field.Config.DisplayNameFromDS
+		case len(field.Labels) > 0:
+			names[i] = field.Name + " {" + field.Labels.String() + "}"
+		default:
+			names[i] = field.Name
+		}
+	}
+	return names
+}
+
+// fieldValue returns the value of a field at row, nil for null values.
+func fieldValue(field *data.Field, row int) any {
+	v, ok := field.ConcreteAt(row)
+	if !ok {
+		return nil
+	}
+	return v
+}
+
+// escapeFormula prefixes the text cells that spreadsheet applications would evaluate as
+// a formula with a quote, so exported values can't run formulas when the file is opened.
+func escapeFormula(s string) string {
+	if s != "" && strings.ContainsRune("=+-@\t\r", rune(s[0])) {
+		return "'" + s
+	}
+	return s
+}
+
+// formatValue formats a field value, nil being an empty cell. Text is escaped with escapeFormula.
+func formatValue(v any) string {
+	switch value := v.(type) {
+	case nil:
+		return ""
+	case string:
+		return escapeFormula(value)
+	case time.Time:
+		return value.Format(time.RFC3339Nano)
+	case float64:
+		return strconv.FormatFloat(value, 'f', -1, 64)
+	case float32:
+		return strconv.FormatFloat(float64(value), 'f', -1, 32)
+	case json.RawMessage:
+		return string(value)
+	default:
+		return fmt.Sprint(value)
+	}
+}
@@ -0,0 +1,174 @@
+package queryexport
+
+import (
+	"archive/zip"
+	"bytes"
+	"errors"
+	"io"
+	"math"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend"
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testFrames() data.Frames {
+	ts := time.Date(2023, 10, 2, 12, 0, 0, 0, time.UTC)
+	value := 2.5
+	return data.Frames{
+		data.NewFrame("cpu",
+			data.NewField("time", nil, []time.Time{ts, ts.Add(time.Minute)}),
+			data.NewField("value", data.Labels{"host": "a"}, []*float64{&value, nil}),
+		),
+		data.NewFrame("cpu",
+			data.NewField("name", nil, []string{`quoted "name", with comma`}),
+			data.NewField("up", nil, []bool{true}),
+			data.NewField("ratio", nil, []float64{math.NaN()}),
+		),
+	}
+}
+
+func TestWriteCSV(t *testing.T) {
+	var buf bytes.Buffer
+	require.NoError(t, Write(&buf, FormatCSV, testFrames()))
+
+	expected := "time,value {host=a}\n" +
+		"2023-10-02T12:00:00Z,2.5\n" +
+		"2023-10-02T12:01:00Z,\n" +
+		"\n" +
+		"name,up,ratio\n" +
+		"\"quoted \"\"name\"\", with comma\",true,NaN\n"
+	assert.Equal(t, expected, buf.String())
+}
+
+func TestWriteXLSX(t *testing.T) {
+	var buf bytes.Buffer
+	require.NoError(t, Write(&buf, FormatXLSX, testFrames()))
+
+	files := readZip(t, buf.Bytes())
+	require.Contains(t, files, "[Content_Types].xml")
+	require.Contains(t, files, "_rels/.rels")
+	require.Contains(t, files, "xl/_rels/workbook.xml.rels")
+
+	assert.Contains(t, files["xl/workbook.xml"], `<sheet name="cpu" sheetId="1" r:id="rId1"/><sheet name="cpu (2)" sheetId="2" r:id="rId2"/>`)
+
+	sheet1 := files["xl/worksheets/sheet1.xml"]
+	assert.Contains(t, sheet1, `<row r="1"><c r="A1" t="inlineStr"><is><t xml:space="preserve">time</t></is></c><c r="B1" t="inlineStr"><is><t xml:space="preserve">value {host=a}</t></is></c></row>`)
+	assert.Contains(t, sheet1, `<c r="B2"><v>2.5</v></c>`)
+	assert.Contains(t, sheet1, `<row r="3"><c r="A3" t="inlineStr"><is><t xml:space="preserve">2023-10-02T12:01:00Z</t></is></c></row>`)
+
+	sheet2 := files["xl/worksheets/sheet2.xml"]
+	assert.Contains(t, sheet2, `<t xml:space="preserve">quoted &#34;name&#34;, with comma</t>`)
+	assert.Contains(t, sheet2, `<c r="B2" t="b"><v>1</v></c>`)
+	assert.Contains(t, sheet2, `<c r="C2" t="inlineStr"><is><t xml:space="preserve">NaN</t></is></c>`)
+}
+
+func TestWriteEscapesFormulas(t *testing.T) {
+	frames := data.Frames{
+		data.NewFrame("injection",
+			data.NewField("=cmd", nil, []string{"=HYPERLINK(\"http://example.com\")", "+1", "-1", "@SUM(A1)", "safe"}),
+			data.NewField("value", nil, []float64{-1, 2, 3, 4, 5}),
+		),
+	}
+
+	var buf bytes.Buffer
+	require.NoError(t, Write(&buf, FormatCSV, frames))
+
+	expected := "'=cmd,value\n" +
+		"\"'=HYPERLINK(\"\"http://example.com\"\")\",-1\n" +
+		"'+1,2\n" +
+		"'-1,3\n" +
+		"'@SUM(A1),4\n" +
+		"safe,5\n"
+	assert.Equal(t, expected, buf.String())
+
+	buf.Reset()
+	require.NoError(t, Write(&buf, FormatXLSX, frames))
+
+	sheet := readZip(t, buf.Bytes())["xl/worksheets/sheet1.xml"]
+	assert.Contains(t, sheet, `<c r="A1" t="inlineStr"><is><t xml:space="preserve">&#39;=cmd</t></is></c>`)
+	assert.Contains(t, sheet, `<c r="A4" t="inlineStr"><is><t xml:space="preserve">&#39;-1</t></is></c>`)
+	assert.Contains(t, sheet, `<c r="B2"><v>-1</v></c>`)
+}
+
+func TestWriteXLSX_NoFrames(t *testing.T) {
+	var buf bytes.Buffer
+	require.NoError(t, Write(&buf, FormatXLSX, nil))
+
+	files := readZip(t, buf.Bytes())
+	assert.Contains(t, files["xl/workbook.xml"], `<sheet name="Sheet1" sheetId="1" r:id="rId1"/>`)
+	assert.Contains(t, files["xl/worksheets/sheet1.xml"], `<sheetData></sheetData>`)
+}
+
+func TestExportResponse_RejectsOversizedFrames(t *testing.T) {
+	fields := make([]*data.Field, xlsxMaxColumns+1)
+	for i := range fields {
+		fields[i] = data.NewField(columnName(i), nil, []float64{})
+	}
+	frames := data.Frames{data.NewFrame("wide", fields...)}
+
+	resp := exportResponse(FormatXLSX, frames, "wide")
+	assert.Equal(t, http.StatusBadRequest, resp.Status())
+
+	// CSV files have no size limit
+	resp = exportResponse(FormatCSV, frames, "wide")
+	assert.Equal(t, http.StatusOK, resp.Status())
+}
+
+func TestColumnName(t *testing.T) {
+	assert.Equal(t, "A", columnName(0))
+	assert.Equal(t, "Z", columnName(25))
+	assert.Equal(t, "AA", columnName(26))
+	assert.Equal(t, "AZ", columnName(51))
+	assert.Equal(t, "XFD", columnName(xlsxMaxColumns-1))
+}
+
+func TestSheetNames(t *testing.T) {
+	frames := data.Frames{
+		data.NewFrame("a/b:c"),
+		{RefID: "A"},
+		{},
+		data.NewFrame("a very long frame name that does not fit"),
+		data.NewFrame("A very long frame name that does not fit"),
+	}
+	assert.Equal(t, []string{"abc", "A", "Sheet3", "a very long frame name that doe", "A very long frame name that (2)"}, sheetNames(frames))
+}
+
+func TestResponseFrames(t *testing.T) {
+	resp := &backend.QueryDataResponse{Responses: backend.Responses{
+		"B": {Frames: data.Frames{data.NewFrame("b")}},
+		"A": {Frames: data.Frames{data.NewFrame("a")}},
+	}}
+
+	frames, err := ResponseFrames(resp)
+	require.NoError(t, err)
+	require.Len(t, frames, 2)
+	assert.Equal(t, "A", frames[0].RefID)
+	assert.Equal(t, "B", frames[1].RefID)
+
+	resp.Responses["C"] = backend.DataResponse{Error: errors.New("timeout")}
+	_, err = ResponseFrames(resp)
+	require.ErrorIs(t, err, ErrQueryFailed)
+}
+
+func readZip(t *testing.T, b []byte) map[string]string {
+	t.Helper()
+
+	r, err := zip.NewReader(bytes.NewReader(b), int64(len(b)))
+	require.NoError(t, err)
+
+	files := make(map[string]string, len(r.File))
+	for _, f := range r.File {
+		rc, err := f.Open()
+		require.NoError(t, err)
+		content, err := io.ReadAll(rc)
+		require.NoError(t, err)
+		require.NoError(t, rc.Close())
+		files[f.Name] = string(content)
+	}
+	return files
+}
@@ -0,0 +1,267 @@
+package queryexport
+
+import (
+	"archive/zip"
+	"bufio"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+)
+
+const (
+	xlsxMaxRows         = 1048576
+	xlsxMaxColumns      = 16384
+	xlsxMaxSheetNameLen = 31
+	xmlHeader           = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>` + "\n"
+)
+
+var sheetNameReplacer = strings.NewReplacer("[", "", "]", "", ":", "", "*", "", "?", "", "/", "", "\\", "")
+
+// writeXLSX writes an Office Open XML workbook with a sheet per frame. Only the
+// parts required by spreadsheet applications are written, with inline strings
+// so that the workbook can be streamed row by row.
+func writeXLSX(w io.Writer, frames data.Frames) error {
+	if err := validateXLSX(frames); err != nil {
+		return err
+	}
+
+	names := sheetNames(frames)
+	zw := zip.NewWriter(w)
+
+	if err := writeZipFile(zw, "[Content_Types].xml", func(w io.Writer) error {
+		return writeContentTypes(w, len(names))
+	}); err != nil {
+		return err
+	}
+	if err := writeZipFile(zw, "_rels/.rels", func(w io.Writer) error {
+		_, err := io.WriteString(w, xmlHeader+`<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">`+
+			`<Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/officeDocument" Target="xl/workbook.xml"/>`+
+			`</Relationships>`)
+		return err
+	}); err != nil {
+		return err
+	}
+	if err := writeZipFile(zw, "xl/workbook.xml", func(w io.Writer) error {
+		return writeWorkbook(w, names)
+	}); err != nil {
+		return err
+	}
+	if err := writeZipFile(zw, "xl/_rels/workbook.xml.rels", func(w io.Writer) error {
+		return writeWorkbookRels(w, len(names))
+	}); err != nil {
+		return err
+	}
+
+	for i := range names {
+		var frame *data.Frame
+		if i < len(frames) {
+			frame = frames[i]
+		}
+		if err := writeZipFile(zw, fmt.Sprintf("xl/worksheets/sheet%d.xml", i+1), func(w io.Writer) error {
+			return writeSheet(w, frame)
+		}); err != nil {
+			return err
+		}
+	}
+
+	return zw.Close()
+}
+
+// validateXLSX checks that the frames, with their header row, fit in the sheets of a workbook.
+func validateXLSX(frames data.Frames) error {
+	for _, frame := range frames {
+		if frame.Rows()+1 > xlsxMaxRows || len(frame.Fields) > xlsxMaxColumns {
+			return ErrInvalidRequest.Errorf("frame %q is too large for a spreadsheet", frame.Name)
+		}
+	}
+	return nil
+}
+
+func writeZipFile(zw *zip.Writer, name string, write func(w io.Writer) error) error {
+	f, err := zw.Create(name)
+	if err != nil {
+		return err
+	}
+	bw := bufio.NewWriter(f)
+	if err := write(bw); err != nil {
+		return err
+	}
+	return bw.Flush()
+}
+
+func writeContentTypes(w io.Writer, sheets int) error {
+	var b strings.Builder
+	b.WriteString(xmlHeader + `<Types xmlns="http://schemas.openxmlformats.org/package/2006/content-types">`)
+	b.WriteString(`<Default Extension="rels" ContentType="application/vnd.openxmlformats-package.relationships+xml"/>`)
+	b.WriteString(`<Default Extension="xml" ContentType="application/xml"/>`)
+	b.WriteString(`<Override PartName="/xl/workbook.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.sheet.main+xml"/>`)
+	for i := 1; i <= sheets; i++ {
+		fmt.Fprintf(&b, `<Override PartName="/xl/worksheets/sheet%d.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.worksheet+xml"/>`, i)
+	}
+	b.WriteString(`</Types>`)
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+func writeWorkbook(w io.Writer, names []string) error {
+	var b strings.Builder
+	b.WriteString(xmlHeader + `<workbook xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main" xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships"><sheets>`)
+	for i, name := range names {
+		b.WriteString(`<sheet name="`)
+		if err := xml.EscapeText(&b, []byte(name)); err != nil {
+			return err
+		}
+		fmt.Fprintf(&b, `" sheetId="%d" r:id="rId%d"/>`, i+1, i+1)
+	}
+	b.WriteString(`</sheets></workbook>`)
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+func writeWorkbookRels(w io.Writer, sheets int) error {
+	var b strings.Builder
+	b.WriteString(xmlHeader + `<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">`)
+	for i := 1; i <= sheets; i++ {
+		fmt.Fprintf(&b, `<Relationship Id="rId%d" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/worksheet" Target="worksheets/sheet%d.xml"/>`, i, i)
+	}
+	b.WriteString(`</Relationships>`)
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// writeSheet writes the header and the rows of a frame. A nil frame is written as an empty sheet.
+func writeSheet(w io.Writer, frame *data.Frame) error {
+	if _, err := io.WriteString(w, xmlHeader+`<worksheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main"><sheetData>`); err != nil {
+		return err
+	}
+
+	if frame != nil {
+		header := columnNames(frame)
+		if err := writeRow(w, 1, len(header), func(col int) any { return header[col] }); err != nil {
+			return err
+		}
+		for row := 0; row < frame.Rows(); row++ {
+			err := writeRow(w, row+2, len(frame.Fields), func(col int) any {
+				return fieldValue(frame.Fields[col], row)
+			})
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	_, err := io.WriteString(w, `</sheetData></worksheet>`)
+	return err
+}
+
+func writeRow(w io.Writer, row int, columns int, value func(col int) any) error {
+	if _, err := fmt.Fprintf(w, `<row r="%d">`, row); err != nil {
+		return err
+	}
+	for col := 0; col < columns; col++ {
+		if err := writeCell(w, columnName(col)+strconv.Itoa(row), value(col)); err != nil {
+			return err
+		}
+	}
+	_, err := io.WriteString(w, `</row>`)
+	return err
+}
+
+// writeCell writes numbers and booleans as such, and everything else as text.
+func writeCell(w io.Writer, ref string, v any) error {
+	if v == nil {
+		return nil
+	}
+
+	if number, ok := cellNumber(v); ok {
+		_, err := fmt.Fprintf(w, `<c r="%s"><v>%s</v></c>`, ref, number)
+		return err
+	}
+	if b, ok := v.(bool); ok {
+		value := 0
+		if b {
+			value = 1
+		}
+		_, err := fmt.Fprintf(w, `<c r="%s" t="b"><v>%d</v></c>`, ref, value)
+		return err
+	}
+
+	if _, err := fmt.Fprintf(w, `<c r="%s" t="inlineStr"><is><t xml:space="preserve">`, ref); err != nil {
+		return err
+	}
+	if err := xml.EscapeText(w, []byte(formatValue(v))); err != nil {
+		return err
+	}
+	_, err := io.WriteString(w, `</t></is></c>`)
+	return err
+}
+
+func cellNumber(v any) (string, bool) {
+	switch value := v.(type) {
+	case int8, int16, int32, int64, uint8, uint16, uint32, uint64:
+		return fmt.Sprint(value), true
+	case float32:
+		if math.IsNaN(float64(value)) || math.IsInf(float64(value), 0) {
+			return "", false
+		}
+		return strconv.FormatFloat(float64(value), 'g', -1, 32), true
+	case float64:
+		if math.IsNaN(value) || math.IsInf(value, 0) {
+			return "", false
+		}
+		return strconv.FormatFloat(value, 'g', -1, 64), true
+	default:
+		return "", false
+	}
+}
+
+// columnName returns the spreadsheet name of a zero based column index: A, B, ..., Z, AA, AB...
+func columnName(col int) string {
+	name := ""
+	for col >= 0 {
+		name = string(rune('A'+col%26)) + name
+		col = col/26 - 1
+	}
+	return name
+}
+
+// sheetNames returns a unique and valid sheet name for each frame, named after
+// the frame or its query. A workbook needs at least one sheet.
+func sheetNames(frames data.Frames) []string {
+	if len(frames) == 0 {
+		return []string{"Sheet1"}
+	}
+
+	names := make([]string, len(frames))
+	used := make(map[string]bool, len(frames))
+	for i, frame := range frames {
+		base := strings.TrimSpace(sheetNameReplacer.Replace(frame.Name))
+		if base == "" {
+			base = strings.TrimSpace(sheetNameReplacer.Replace(frame.RefID))
+		}
+		if base == "" {
+			base = "Sheet" + strconv.Itoa(i+1)
+		}
+
+		name := truncateSheetName(base, "")
+		for n := 2; used[strings.ToLower(name)]; n++ {
+			name = truncateSheetName(base, fmt.Sprintf(" (%d)", n))
+		}
+		used[strings.ToLower(name)] = true
+		names[i] = name
+	}
+	return names
+}
+
+func truncateSheetName(base, suffix string) string {
+	runes := []rune(base)
+	if max := xlsxMaxSheetNameLen - len(suffix); len(runes) > max {
+		runes = runes[:max]
+	}
+	return string(runes) + suffix
+}
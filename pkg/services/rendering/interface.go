@@ -22,6 +22,28 @@ const (
 	RenderPNG RenderType = "png"
 )
 
+// Priority orders the render requests waiting in the queue.
+type Priority int
+
+const (
+	// PriorityLow is for renders nobody is waiting for, such as alert and report images.
+	PriorityLow    Priority = -1
+	PriorityNormal Priority = 0
+	// PriorityHigh is for interactive renders, such as image downloads.
+	PriorityHigh Priority = 1
+)
+
+func (p Priority) String() string {
+	switch {
+	case p < PriorityNormal:
+		return "low"
+	case p > PriorityNormal:
+		return "high"
+	default:
+		return "normal"
+	}
+}
+
 type TimeoutOpts struct {
 	Timeout                  time.Duration // Timeout param passed to image-renderer service
 	RequestTimeoutMultiplier time.Duration // RequestTimeoutMultiplier used for plugin/HTTP request context timeout
@@ -54,6 +76,7 @@ type Opts struct {
 	DeviceScaleFactor float64
 	Headers           map[string][]string
 	Theme             models.Theme
+	Priority          Priority
 }
 
 type ErrorOpts struct {
@@ -82,6 +105,7 @@ type CSVOpts struct {
 	Timezone        string
 	ConcurrentLimit int
 	Headers         map[string][]string
+	Priority        Priority
 }
 
 type RenderResult struct {
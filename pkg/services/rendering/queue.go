@@ -0,0 +1,396 @@
+package rendering
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/grafana/grafana/pkg/infra/metrics"
+)
+
+var (
+	errQueueFull    = errors.New("rendering queue is full")
+	errQueueTimeout = errors.New("timed out waiting in the rendering queue")
+)
+
+// priorityLevels are the priorities served by the queue, highest first.
+var priorityLevels = []Priority{PriorityHigh, PriorityNormal, PriorityLow}
+
+// renderQueue limits the number of renders in progress. The requests over the
+// limit wait for a slot and are served by priority, then in turn between
+// organizations so that a burst of requests from one organization doesn't
+// starve the others. Identical requests share the same render.
+type renderQueue struct {
+	maxConcurrent int
+	maxSize       int
+	timeout       time.Duration
+
+	mu       sync.Mutex
+	running  int
+	waiting  int
+	levels   map[Priority]*orgQueues
+	inflight map[string]*inflightRender
+}
+
+// orgQueues holds the requests waiting with the same priority.
+type orgQueues struct {
+	// orgs are the organizations with waiting requests, in turn order.
+	orgs  []int64
+	byOrg map[int64][]*queuedRequest
+}
+
+type queuedRequest struct {
+	priority Priority
+	orgID    int64
+	// limit is the number of renders in progress under which the request can
+	// start, so that requests with a lower limit yield to the others when busy.
+	limit    int
+	ready    chan struct{}
+	admitted bool
+}
+
+type inflightRender struct {
+	done      chan struct{}
+	result    any
+	err       error
+	followers sync.WaitGroup
+}
+
+func newRenderQueue(maxConcurrent int, maxSize int, timeout time.Duration) *renderQueue {
+	if maxConcurrent <= 0 {
+		maxConcurrent = 1
+	}
+
+	levels := make(map[Priority]*orgQueues, len(priorityLevels))
+	for _, p := range priorityLevels {
+		levels[p] = &orgQueues{byOrg: map[int64][]*queuedRequest{}}
+	}
+
+	return &renderQueue{
+		maxConcurrent: maxConcurrent,
+		maxSize:       maxSize,
+		timeout:       timeout,
+		levels:        levels,
+		inflight:      map[string]*inflightRender{},
+	}
+}
+
+// acquire waits for a render slot and returns the function releasing it.
+func (q *renderQueue) acquire(ctx context.Context, priority Priority, orgID int64, limit int) (func(), error) {
+	if limit <= 0 || limit > q.maxConcurrent {
+		limit = q.maxConcurrent
+	}
+	req := &queuedRequest{
+		priority: priorityLevel(priority),
+		orgID:    orgID,
+		limit:    limit,
+		ready:    make(chan struct{}),
+	}
+	start := time.Now()
+
+	q.mu.Lock()
+	q.push(req)
+	q.dispatch()
+	if !req.admitted && q.waiting > q.maxSize {
+		q.remove(req)
+		q.mu.Unlock()
+		metrics.MRenderingQueueRejectedTotal.WithLabelValues("full").Inc()
+		return nil, errQueueFull
+	}
+	q.mu.Unlock()
+
+	timer := time.NewTimer(q.timeout)
+	defer timer.Stop()
+
+	var err error
+	select {
+	case <-req.ready:
+	case <-ctx.Done():
+		err = ctx.Err()
+	case <-timer.C:
+		err = errQueueTimeout
+	}
+
+	if err != nil {
+		q.mu.Lock()
+		// the request may have been admitted while giving up
+		if !req.admitted {
+			q.remove(req)
+			q.mu.Unlock()
+			if errors.Is(err, errQueueTimeout) {
+				metrics.MRenderingQueueRejectedTotal.WithLabelValues("timeout").Inc()
+			}
+			return nil, err
+		}
+		q.mu.Unlock()
+	}
+
+	metrics.MRenderingQueueWaitDuration.WithLabelValues(req.priority.String()).Observe(time.Since(start).Seconds())
+
+	var once sync.Once
+	return func() {
+		once.Do(q.release)
+	}, nil
+}
+
+func (q *renderQueue) release() {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	q.running--
+	q.dispatch()
+}
+
+// dispatch starts waiting requests while there are free slots. It must be called with the lock held.
+func (q *renderQueue) dispatch() {
+	for q.running < q.maxConcurrent {
+		req := q.next()
+		if req == nil {
+			return
+		}
+		q.running++
+		req.admitted = true
+		close(req.ready)
+	}
+}
+
+// next removes and returns the next request that can start, or nil if none can.
+// Within an organization, requests start in order unless they are over their limit.
+func (q *renderQueue) next() *queuedRequest {
+	for _, p := range priorityLevels {
+		level := q.levels[p]
+		for i, orgID := range level.orgs {
+			reqs := level.byOrg[orgID]
+			for j, req := range reqs {
+				if q.running >= req.limit {
+					continue
+				}
+
+				level.orgs = append(level.orgs[:i:i], level.orgs[i+1:]...)
+				if reqs = append(reqs[:j:j], reqs[j+1:]...); len(reqs) > 0 {
+					level.byOrg[orgID] = reqs
+					// the organization takes its next turn after the others
+					level.orgs = append(level.orgs, orgID)
+				} else {
+					delete(level.byOrg, orgID)
+				}
+				q.setWaiting(req.priority, -1)
+				return req
+			}
+		}
+	}
+	return nil
+}
+
+func (q *renderQueue) push(req *queuedRequest) {
+	level := q.levels[req.priority]
+	if _, ok := level.byOrg[req.orgID]; !ok {
+		level.orgs = append(level.orgs, req.orgID)
+	}
+	level.byOrg[req.orgID] = append(level.byOrg[req.orgID], req)
+	q.setWaiting(req.priority, 1)
+}
+
+func (q *renderQueue) remove(req *queuedRequest) {
+	level := q.levels[req.priority]
+	reqs := level.byOrg[req.orgID]
+	for i, r := range reqs {
+		if r != req {
+			continue
+		}
+
+		reqs = append(reqs[:i:i], reqs[i+1:]...)
+		if len(reqs) > 0 {
+			level.byOrg[req.orgID] = reqs
+			break
+		}
+
+		delete(level.byOrg, req.orgID)
+		for j, orgID := range level.orgs {
+			if orgID == req.orgID {
+				level.orgs = append(level.orgs[:j:j], level.orgs[j+1:]...)
+				break
+			}
+		}
+		break
+	}
+	q.setWaiting(req.priority, -1)
+}
+
+func (q *renderQueue) setWaiting(priority Priority, delta int) {
+	q.waiting += delta
+	metrics.MRenderingQueueWaiting.WithLabelValues(priority.String()).Add(float64(delta))
+}
+
+// share runs render once for identical requests in progress at the same time.
+// The requests joining a render in progress get a copy of its result made with
+// copyResult, because callers remove the rendered files once they are done with them.
+func (q *renderQueue) share(ctx context.Context, key string, render func() (any, error), copyResult func(any) (any, error)) (any, error) {
+	for {
+		q.mu.Lock()
+		r, ok := q.inflight[key]
+		if !ok {
+			r = &inflightRender{done: make(chan struct{})}
+			q.inflight[key] = r
+			q.mu.Unlock()
+
+			r.result, r.err = render()
+
+			q.mu.Lock()
+			delete(q.inflight, key)
+			q.mu.Unlock()
+
+			close(r.done)
+			// keep the result until every follower has its copy
+			r.followers.Wait()
+			return r.result, r.err
+		}
+		r.followers.Add(1)
+		q.mu.Unlock()
+		metrics.MRenderingDeduplicatedTotal.Inc()
+
+		select {
+		case <-r.done:
+		case <-ctx.Done():
+			r.followers.Done()
+			return nil, ctx.Err()
+		}
+
+		if r.err != nil {
+			r.followers.Done()
+			// the render was canceled with the request that started it, not this one
+			if (errors.Is(r.err, context.Canceled) || errors.Is(r.err, context.DeadlineExceeded)) && ctx.Err() == nil {
+				continue
+			}
+			return nil, r.err
+		}
+
+		result, err := copyResult(r.result)
+		r.followers.Done()
+		return result, err
+	}
+}
+
+func priorityLevel(p Priority) Priority {
+	switch {
+	case p < PriorityNormal:
+		return PriorityLow
+	case p > PriorityNormal:
+		return PriorityHigh
+	default:
+		return PriorityNormal
+	}
+}
+
+// renderQueued renders an image once a slot is available in the queue.
+func (rs *RenderingService) renderQueued(ctx context.Context, opts Opts, renderKeyProvider renderKeyProvider) (*RenderResult, error) {
+	result, err := rs.queue.share(ctx, queueKey(RenderPNG, opts),
+		func() (any, error) {
+			release, err := rs.queue.acquire(ctx, opts.Priority, opts.OrgID, opts.ConcurrentLimit)
+			if err != nil {
+				return nil, err
+			}
+			defer release()
+			return rs.doRender(ctx, opts, renderKeyProvider)
+		},
+		func(v any) (any, error) {
+			filePath, err := rs.copyRenderedFile(v.(*RenderResult).FilePath, RenderPNG)
+			if err != nil {
+				return nil, err
+			}
+			return &RenderResult{FilePath: filePath}, nil
+		},
+	)
+	if errors.Is(err, errQueueFull) || errors.Is(err, errQueueTimeout) {
+		rs.log.Warn("Could not render image, the rendering queue is busy", "error", err, "path", opts.Path)
+		return rs.renderLimitReached(opts)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return result.(*RenderResult), nil
+}
+
+// renderCSVQueued renders a CSV once a slot is available in the queue.
+func (rs *RenderingService) renderCSVQueued(ctx context.Context, opts CSVOpts, renderKeyProvider renderKeyProvider) (*RenderCSVResult, error) {
+	result, err := rs.queue.share(ctx, queueKey(RenderCSV, opts),
+		func() (any, error) {
+			release, err := rs.queue.acquire(ctx, opts.Priority, opts.OrgID, opts.ConcurrentLimit)
+			if err != nil {
+				return nil, err
+			}
+			defer release()
+			return rs.doRenderCSV(ctx, opts, renderKeyProvider)
+		},
+		func(v any) (any, error) {
+			res := v.(*RenderCSVResult)
+			filePath, err := rs.copyRenderedFile(res.FilePath, RenderCSV)
+			if err != nil {
+				return nil, err
+			}
+			return &RenderCSVResult{FilePath: filePath, FileName: res.FileName}, nil
+		},
+	)
+	if errors.Is(err, errQueueFull) || errors.Is(err, errQueueTimeout) {
+		rs.log.Warn("Could not render CSV, the rendering queue is busy", "error", err, "path", opts.Path)
+		return nil, ErrConcurrentLimitReached
+	}
+	if err != nil {
+		return nil, err
+	}
+	return result.(*RenderCSVResult), nil
+}
+
+// queueKey identifies identical render requests. The options that don't change
+// the rendered file, such as the priority, aren't part of it.
+func queueKey(renderType RenderType, opts any) string {
+	switch o := opts.(type) {
+	case Opts:
+		o.ConcurrentLimit, o.Priority, o.ErrorOpts = 0, 0, ErrorOpts{}
+		opts = o
+	case CSVOpts:
+		o.ConcurrentLimit, o.Priority = 0, 0
+		opts = o
+	}
+
+	b, err := json.Marshal(opts)
+	if err != nil {
+		// never shared with another request
+		return fmt.Sprintf("%p", &b)
+	}
+	return string(renderType) + ":" + string(b)
+}
+
+func (rs *RenderingService) copyRenderedFile(src string, renderType RenderType) (string, error) {
+	dst, err := rs.getNewFilePath(renderType)
+	if err != nil {
+		return "", err
+	}
+
+	// nolint:gosec
+	// src is a file created by the renderer in the images or CSVs directory.
+	in, err := os.Open(src)
+	if err != nil {
+		return "", err
+	}
+	defer func() {
+		if err := in.Close(); err != nil {
+			rs.log.Warn("Failed to close rendered file", "path", src, "error", err)
+		}
+	}()
+
+	out, err := os.OpenFile(dst, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0600)
+	if err != nil {
+		return "", err
+	}
+	if _, err := io.Copy(out, in); err != nil {
+		_ = out.Close()
+		return "", err
+	}
+	return dst, out.Close()
+}
@@ -0,0 +1,224 @@
+package rendering
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/infra/metrics"
+	"github.com/grafana/grafana/pkg/setting"
+)
+
+type queueWaiter struct {
+	name    string
+	release func()
+	err     error
+}
+
+// enqueue starts an acquire in the background and waits for the request to be queued.
+func enqueue(t *testing.T, q *renderQueue, admitted chan<- queueWaiter, name string, priority Priority, orgID int64, limit int) {
+	t.Helper()
+
+	q.mu.Lock()
+	waiting := q.waiting
+	q.mu.Unlock()
+
+	go func() {
+		release, err := q.acquire(context.Background(), priority, orgID, limit)
+		admitted <- queueWaiter{name: name, release: release, err: err}
+	}()
+
+	require.Eventually(t, func() bool {
+		q.mu.Lock()
+		defer q.mu.Unlock()
+		return q.waiting == waiting+1
+	}, time.Second, time.Millisecond)
+}
+
+// admitOrder releases the slot held and returns the order in which n waiting requests start.
+func admitOrder(t *testing.T, release func(), admitted <-chan queueWaiter, n int) []string {
+	t.Helper()
+
+	var order []string
+	release()
+	for i := 0; i < n; i++ {
+		w := <-admitted
+		require.NoError(t, w.err)
+		order = append(order, w.name)
+		w.release()
+	}
+	return order
+}
+
+func TestRenderQueue(t *testing.T) {
+	t.Run("serves higher priorities first", func(t *testing.T) {
+		q := newRenderQueue(1, 10, time.Minute)
+		release, err := q.acquire(context.Background(), PriorityNormal, 1, 0)
+		require.NoError(t, err)
+
+		admitted := make(chan queueWaiter)
+		enqueue(t, q, admitted, "low", PriorityLow, 1, 0)
+		enqueue(t, q, admitted, "normal", PriorityNormal, 1, 0)
+		enqueue(t, q, admitted, "high", PriorityHigh, 1, 0)
+
+		assert.Equal(t, []string{"high", "normal", "low"}, admitOrder(t, release, admitted, 3))
+	})
+
+	t.Run("serves organizations in turn", func(t *testing.T) {
+		q := newRenderQueue(1, 10, time.Minute)
+		release, err := q.acquire(context.Background(), PriorityNormal, 1, 0)
+		require.NoError(t, err)
+
+		admitted := make(chan queueWaiter)
+		enqueue(t, q, admitted, "org1-a", PriorityNormal, 1, 0)
+		enqueue(t, q, admitted, "org1-b", PriorityNormal, 1, 0)
+		enqueue(t, q, admitted, "org1-c", PriorityNormal, 1, 0)
+		enqueue(t, q, admitted, "org2-a", PriorityNormal, 2, 0)
+		enqueue(t, q, admitted, "org2-b", PriorityNormal, 2, 0)
+
+		assert.Equal(t, []string{"org1-a", "org2-a", "org1-b", "org2-b", "org1-c"}, admitOrder(t, release, admitted, 5))
+	})
+
+	t.Run("requests with a lower limit yield to the others", func(t *testing.T) {
+		q := newRenderQueue(3, 10, time.Minute)
+		release1, err := q.acquire(context.Background(), PriorityNormal, 1, 0)
+		require.NoError(t, err)
+		release2, err := q.acquire(context.Background(), PriorityNormal, 1, 0)
+		require.NoError(t, err)
+
+		admitted := make(chan queueWaiter, 2)
+		enqueue(t, q, admitted, "limited", PriorityNormal, 1, 1)
+
+		release3, err := q.acquire(context.Background(), PriorityNormal, 1, 0)
+		require.NoError(t, err)
+		release1()
+		release2()
+		select {
+		case w := <-admitted:
+			t.Fatalf("%s started over its limit", w.name)
+		case <-time.After(20 * time.Millisecond):
+		}
+
+		release3()
+		w := <-admitted
+		require.NoError(t, w.err)
+		w.release()
+	})
+
+	t.Run("rejects requests when the queue is full", func(t *testing.T) {
+		q := newRenderQueue(1, 1, time.Minute)
+		release, err := q.acquire(context.Background(), PriorityNormal, 1, 0)
+		require.NoError(t, err)
+
+		admitted := make(chan queueWaiter, 1)
+		enqueue(t, q, admitted, "queued", PriorityNormal, 1, 0)
+
+		_, err = q.acquire(context.Background(), PriorityHigh, 2, 0)
+		require.ErrorIs(t, err, errQueueFull)
+
+		assert.Equal(t, []string{"queued"}, admitOrder(t, release, admitted, 1))
+	})
+
+	t.Run("rejects requests waiting for too long", func(t *testing.T) {
+		q := newRenderQueue(1, 10, 10*time.Millisecond)
+		release, err := q.acquire(context.Background(), PriorityNormal, 1, 0)
+		require.NoError(t, err)
+		defer release()
+
+		_, err = q.acquire(context.Background(), PriorityNormal, 1, 0)
+		require.ErrorIs(t, err, errQueueTimeout)
+		assert.Equal(t, 0, q.waiting)
+	})
+
+	t.Run("stops waiting when the context is canceled", func(t *testing.T) {
+		q := newRenderQueue(1, 10, time.Minute)
+		release, err := q.acquire(context.Background(), PriorityNormal, 1, 0)
+		require.NoError(t, err)
+		defer release()
+
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		_, err = q.acquire(ctx, PriorityNormal, 1, 0)
+		require.ErrorIs(t, err, context.Canceled)
+		assert.Equal(t, 0, q.waiting)
+	})
+}
+
+func TestRenderQueueShare(t *testing.T) {
+	dir := t.TempDir()
+	cfg := setting.NewCfg()
+	cfg.ImagesDir = dir
+	rs := &RenderingService{Cfg: cfg, log: log.New("test"), queue: newRenderQueue(2, 10, time.Minute)}
+
+	rendered := filepath.Join(dir, "rendered.png")
+	require.NoError(t, os.WriteFile(rendered, []byte("image"), 0600))
+
+	started := make(chan struct{})
+	unblock := make(chan struct{})
+	renders := 0
+	render := func() (any, error) {
+		renders++
+		close(started)
+		<-unblock
+		return &RenderResult{FilePath: rendered}, nil
+	}
+	copyResult := func(v any) (any, error) {
+		filePath, err := rs.copyRenderedFile(v.(*RenderResult).FilePath, RenderPNG)
+		return &RenderResult{FilePath: filePath}, err
+	}
+
+	deduplicated := testutil.ToFloat64(metrics.MRenderingDeduplicatedTotal)
+	var wg sync.WaitGroup
+	results := make([]*RenderResult, 3)
+	for i := range results {
+		if i > 0 {
+			<-started
+		}
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			res, err := rs.queue.share(context.Background(), "key", render, copyResult)
+			assert.NoError(t, err)
+			results[i] = res.(*RenderResult)
+		}(i)
+	}
+
+	// both followers joined the render in progress
+	require.Eventually(t, func() bool {
+		return testutil.ToFloat64(metrics.MRenderingDeduplicatedTotal) == deduplicated+2
+	}, time.Second, time.Millisecond)
+
+	close(unblock)
+	wg.Wait()
+
+	assert.Equal(t, 1, renders)
+	assert.Equal(t, rendered, results[0].FilePath)
+	assert.NotEqual(t, results[1].FilePath, results[2].FilePath)
+	for _, res := range results[1:] {
+		assert.NotEqual(t, rendered, res.FilePath)
+		content, err := os.ReadFile(res.FilePath)
+		require.NoError(t, err)
+		assert.Equal(t, "image", string(content))
+	}
+}
+
+func TestQueueKey(t *testing.T) {
+	opts := Opts{Path: "d/uid", Width: 100, Height: 50, AuthOpts: AuthOpts{OrgID: 1}}
+	other := opts
+	other.Priority = PriorityHigh
+	other.ConcurrentLimit = 5
+	other.ErrorOpts = ErrorOpts{ErrorConcurrentLimitReached: true}
+	assert.Equal(t, queueKey(RenderPNG, opts), queueKey(RenderPNG, other))
+
+	other.AuthOpts.OrgID = 2
+	assert.NotEqual(t, queueKey(RenderPNG, opts), queueKey(RenderPNG, other))
+	assert.NotEqual(t, queueKey(RenderPNG, opts), queueKey(RenderCSV, CSVOpts{TimeoutOpts: opts.TimeoutOpts, AuthOpts: opts.AuthOpts, Path: opts.Path}))
+}
@@ -38,6 +38,7 @@ type RenderingService struct {
 	version           string
 	versionMutex      sync.RWMutex
 	capabilities      []Capability
+	queue             *renderQueue
 
 	perRequestRenderKeyProvider renderKeyProvider
 	Cfg                         *setting.Cfg
@@ -123,6 +124,10 @@ func ProvideService(cfg *setting.Cfg, features *featuremgmt.FeatureManager, remo
 		sanitizeURL:           sanitizeURL,
 	}
 
+	if cfg.RendererQueueEnabled {
+		s.queue = newRenderQueue(cfg.RendererConcurrentRequestLimit, cfg.RendererQueueMaxSize, cfg.RendererQueueTimeout)
+	}
+
 	gob.Register(&RenderUser{})
 
 	return s, nil
@@ -256,20 +261,9 @@ func (rs *RenderingService) Render(ctx context.Context, opts Opts, session Sessi
 }
 
 func (rs *RenderingService) render(ctx context.Context, opts Opts, renderKeyProvider renderKeyProvider) (*RenderResult, error) {
-	if int(atomic.LoadInt32(&rs.inProgressCount)) > opts.ConcurrentLimit {
+	if rs.queue == nil && int(atomic.LoadInt32(&rs.inProgressCount)) > opts.ConcurrentLimit {
 		rs.log.Warn("Could not render image, hit the currency limit", "concurrencyLimit", opts.ConcurrentLimit, "path", opts.Path)
-		if opts.ErrorConcurrentLimitReached {
-			return nil, ErrConcurrentLimitReached
-		}
-
-		theme := models.ThemeDark
-		if opts.Theme != "" {
-			theme = opts.Theme
-		}
-		filePath := fmt.Sprintf("public/img/rendering_limit_%s.png", theme)
-		return &RenderResult{
-			FilePath: filepath.Join(rs.Cfg.HomePath, filePath),
-		}, nil
+		return rs.renderLimitReached(opts)
 	}
 
 	if !rs.IsAvailable(ctx) {
@@ -282,10 +276,18 @@ func (rs *RenderingService) render(ctx context.Context, opts Opts, renderKeyProv
 		return rs.renderUnavailableImage(), nil
 	}
 
-	rs.log.Info("Rendering", "path", opts.Path)
 	if math.IsInf(opts.DeviceScaleFactor, 0) || math.IsNaN(opts.DeviceScaleFactor) || opts.DeviceScaleFactor == 0 {
 		opts.DeviceScaleFactor = 1
 	}
+
+	if rs.queue != nil {
+		return rs.renderQueued(ctx, opts, renderKeyProvider)
+	}
+	return rs.doRender(ctx, opts, renderKeyProvider)
+}
+
+func (rs *RenderingService) doRender(ctx context.Context, opts Opts, renderKeyProvider renderKeyProvider) (*RenderResult, error) {
+	rs.log.Info("Rendering", "path", opts.Path)
 	renderKey, err := renderKeyProvider.get(ctx, opts.AuthOpts)
 	if err != nil {
 		return nil, err
@@ -301,6 +303,22 @@ func (rs *RenderingService) render(ctx context.Context, opts Opts, renderKeyProv
 	return rs.renderAction(ctx, renderKey, opts)
 }
 
+// renderLimitReached returns the result of a render rejected because too many are in progress.
+func (rs *RenderingService) renderLimitReached(opts Opts) (*RenderResult, error) {
+	if opts.ErrorConcurrentLimitReached {
+		return nil, ErrConcurrentLimitReached
+	}
+
+	theme := models.ThemeDark
+	if opts.Theme != "" {
+		theme = opts.Theme
+	}
+	filePath := fmt.Sprintf("public/img/rendering_limit_%s.png", theme)
+	return &RenderResult{
+		FilePath: filepath.Join(rs.Cfg.HomePath, filePath),
+	}, nil
+}
+
 func (rs *RenderingService) RenderCSV(ctx context.Context, opts CSVOpts, session Session) (*RenderCSVResult, error) {
 	startTime := time.Now()
 
@@ -335,7 +353,7 @@ func (rs *RenderingService) SanitizeSVG(ctx context.Context, req *SanitizeSVGReq
 }
 
 func (rs *RenderingService) renderCSV(ctx context.Context, opts CSVOpts, renderKeyProvider renderKeyProvider) (*RenderCSVResult, error) {
-	if int(atomic.LoadInt32(&rs.inProgressCount)) > opts.ConcurrentLimit {
+	if rs.queue == nil && int(atomic.LoadInt32(&rs.inProgressCount)) > opts.ConcurrentLimit {
 		return nil, ErrConcurrentLimitReached
 	}
 
@@ -343,6 +361,13 @@ func (rs *RenderingService) renderCSV(ctx context.Context, opts CSVOpts, renderK
 		return nil, ErrRenderUnavailable
 	}
 
+	if rs.queue != nil {
+		return rs.renderCSVQueued(ctx, opts, renderKeyProvider)
+	}
+	return rs.doRenderCSV(ctx, opts, renderKeyProvider)
+}
+
+func (rs *RenderingService) doRenderCSV(ctx context.Context, opts CSVOpts, renderKeyProvider renderKeyProvider) (*RenderCSVResult, error) {
 	rs.log.Info("Rendering", "path", opts.Path)
 	renderKey, err := renderKeyProvider.get(ctx, opts.AuthOpts)
 	if err != nil {
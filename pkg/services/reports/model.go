@@ -0,0 +1,120 @@
+package reports
+
+import (
+	"time"
+
+	"github.com/grafana/grafana/pkg/util/errutil"
+)
+
+var (
+	ErrReportNotFound     = errutil.NotFound("reports.notFound", errutil.WithPublicMessage("Report not found"))
+	ErrReportInvalid      = errutil.ValidationFailed("reports.invalid")
+	ErrDashboardForbidden = errutil.Forbidden("reports.dashboardForbidden", errutil.WithPublicMessage("You don't have access to the dashboard of the report"))
+)
+
+const (
+	ActionRead  = "reports:read"
+	ActionWrite = "reports:write"
+	ActionSend  = "reports:send"
+)
+
+// Format is the format of a rendered attachment.
+type Format string
+
+const (
+	FormatPDF Format = "pdf"
+	FormatPNG Format = "png"
+)
+
+// RunState is the result of a report run.
+type RunState string
+
+const (
+	RunStateSuccess RunState = "success"
+	RunStateFailed  RunState = "failed"
+)
+
+// RunTrigger is what started a report run.
+type RunTrigger string
+
+const (
+	RunTriggerSchedule RunTrigger = "schedule"
+	RunTriggerManual   RunTrigger = "manual"
+)
+
+// Report renders a dashboard on a schedule and sends it by email.
+type Report struct {
+	ID           int64  `json:"id" xorm:"pk autoincr 'id'"`
+	UID          string `json:"uid" xorm:"uid"`
+	OrgID        int64  `json:"orgId" xorm:"org_id"`
+	Name         string `json:"name"`
+	DashboardUID string `json:"dashboardUid" xorm:"dashboard_uid"`
+
+	// Schedule is a cron expression with five fields, evaluated in Timezone.
+	Schedule string `json:"schedule"`
+	// Timezone is an IANA time zone name, used for the schedule and for rendering the dashboard.
+	Timezone string `json:"timezone"`
+
+	Recipients []string `json:"recipients"`
+	ReplyTo    string   `json:"replyTo,omitempty" xorm:"reply_to"`
+	Message    string   `json:"message,omitempty"`
+
+	// TimeFrom and TimeTo override the time range of the dashboard, for example now-7d and now.
+	TimeFrom string `json:"timeFrom" xorm:"time_from"`
+	TimeTo   string `json:"timeTo" xorm:"time_to"`
+	// Variables override the values of the dashboard template variables.
+	Variables map[string][]string `json:"variables,omitempty"`
+	Formats   []Format            `json:"formats"`
+
+	IsPaused bool `json:"isPaused" xorm:"is_paused"`
+	// UserID is the user whose permissions are used to render the report, the last one who saved it.
+	UserID  int64     `json:"userId" xorm:"user_id"`
+	Created time.Time `json:"created"`
+	Updated time.Time `json:"updated"`
+	// NextRunAt is the time of the next scheduled run in unix milliseconds.
+	NextRunAt int64 `json:"nextRunAt" xorm:"next_run_at"`
+}
+
+// ReportRun is an entry of the send history of a report.
+type ReportRun struct {
+	ID       int64 `json:"id" xorm:"pk autoincr 'id'"`
+	ReportID int64 `json:"-" xorm:"report_id"`
+	OrgID    int64 `json:"-" xorm:"org_id"`
+
+	Trigger    RunTrigger `json:"trigger" xorm:"triggered_by"`
+	State      RunState   `json:"state"`
+	Error      string     `json:"error,omitempty"`
+	Recipients int        `json:"recipients"`
+	// StartedAt and FinishedAt are unix milliseconds.
+	StartedAt  int64 `json:"startedAt" xorm:"started_at"`
+	FinishedAt int64 `json:"finishedAt" xorm:"finished_at"`
+}
+
+// ReportSpec holds the fields of a report set by its owner.
+type ReportSpec struct {
+	Name         string              `json:"name"`
+	DashboardUID string              `json:"dashboardUid"`
+	Schedule     string              `json:"schedule"`
+	Timezone     string              `json:"timezone"`
+	Recipients   []string            `json:"recipients"`
+	ReplyTo      string              `json:"replyTo"`
+	Message      string              `json:"message"`
+	TimeFrom     string              `json:"timeFrom"`
+	TimeTo       string              `json:"timeTo"`
+	Variables    map[string][]string `json:"variables"`
+	Formats      []Format            `json:"formats"`
+	IsPaused     bool                `json:"isPaused"`
+}
+
+type CreateReportCommand struct {
+	ReportSpec
+	OrgID  int64 `json:"-"`
+	UserID int64 `json:"-"`
+}
+
+type UpdateReportCommand struct {
+	ReportSpec
+	UID    string `json:"-"`
+	OrgID  int64  `json:"-"`
+	UserID int64  `json:"-"`
+}
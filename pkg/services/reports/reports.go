@@ -0,0 +1,19 @@
+package reports
+
+import (
+	"context"
+)
+
+// Service manages the scheduled dashboard reports. Reports are rendered with the permissions
+// of the user who created them and sent by email to their recipients.
+type Service interface {
+	Create(ctx context.Context, cmd *CreateReportCommand) (*Report, error)
+	Update(ctx context.Context, cmd *UpdateReportCommand) (*Report, error)
+	Delete(ctx context.Context, orgID int64, uid string) error
+	Get(ctx context.Context, orgID int64, uid string) (*Report, error)
+	List(ctx context.Context, orgID int64) ([]*Report, error)
+	// ListRuns returns the most recent runs of a report first.
+	ListRuns(ctx context.Context, orgID int64, uid string, limit int) ([]*ReportRun, error)
+	// Send renders and sends a report immediately, regardless of its schedule.
+	Send(ctx context.Context, orgID int64, uid string) (*ReportRun, error)
+}
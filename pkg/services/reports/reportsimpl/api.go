@@ -0,0 +1,120 @@
+package reportsimpl
+
+import (
+	"net/http"
+
+	"github.com/grafana/grafana/pkg/api/response"
+	"github.com/grafana/grafana/pkg/api/routing"
+	ac "github.com/grafana/grafana/pkg/services/accesscontrol"
+	contextmodel "github.com/grafana/grafana/pkg/services/contexthandler/model"
+	"github.com/grafana/grafana/pkg/services/reports"
+	"github.com/grafana/grafana/pkg/web"
+)
+
+func (s *Service) registerAPIEndpoints(routeRegister routing.RouteRegister) {
+	authorize := ac.Middleware(s.accessControl)
+
+	routeRegister.Group("/api/reports", func(r routing.RouteRegister) {
+		r.Get("/", authorize(ac.EvalPermission(reports.ActionRead)), routing.Wrap(s.handleList))
+		r.Post("/", authorize(ac.EvalPermission(reports.ActionWrite)), routing.Wrap(s.handleCreate))
+		r.Get("/:uid", authorize(ac.EvalPermission(reports.ActionRead)), routing.Wrap(s.handleGet))
+		r.Put("/:uid", authorize(ac.EvalPermission(reports.ActionWrite)), routing.Wrap(s.handleUpdate))
+		r.Delete("/:uid", authorize(ac.EvalPermission(reports.ActionWrite)), routing.Wrap(s.handleDelete))
+		r.Get("/:uid/runs", authorize(ac.EvalPermission(reports.ActionRead)), routing.Wrap(s.handleListRuns))
+		r.Post("/:uid/send", authorize(ac.EvalPermission(reports.ActionSend)), routing.Wrap(s.handleSend))
+	})
+}
+
+func (s *Service) handleList(c *contextmodel.ReqContext) response.Response {
+	result, err := s.List(c.Req.Context(), c.SignedInUser.GetOrgID())
+	if err != nil {
+		return response.Error(http.StatusInternalServerError, "Failed to list reports", err)
+	}
+	return response.JSON(http.StatusOK, result)
+}
+
+func (s *Service) handleGet(c *contextmodel.ReqContext) response.Response {
+	report, err := s.Get(c.Req.Context(), c.SignedInUser.GetOrgID(), web.Params(c.Req)[":uid"])
+	if err != nil {
+		return response.ErrOrFallback(http.StatusInternalServerError, "Failed to get report", err)
+	}
+	return response.JSON(http.StatusOK, report)
+}
+
+func (s *Service) handleCreate(c *contextmodel.ReqContext) response.Response {
+	cmd := reports.CreateReportCommand{}
+	if err := web.Bind(c.Req, &cmd); err != nil {
+		return response.Error(http.StatusBadRequest, "bad request data", err)
+	}
+	cmd.OrgID = c.SignedInUser.GetOrgID()
+	cmd.UserID = c.SignedInUser.UserID
+
+	if resp := s.checkDashboardAccess(c, cmd.DashboardUID); resp != nil {
+		return resp
+	}
+
+	report, err := s.Create(c.Req.Context(), &cmd)
+	if err != nil {
+		return response.ErrOrFallback(http.StatusInternalServerError, "Failed to create report", err)
+	}
+	return response.JSON(http.StatusOK, report)
+}
+
+func (s *Service) handleUpdate(c *contextmodel.ReqContext) response.Response {
+	cmd := reports.UpdateReportCommand{}
+	if err := web.Bind(c.Req, &cmd); err != nil {
+		return response.Error(http.StatusBadRequest, "bad request data", err)
+	}
+	cmd.UID = web.Params(c.Req)[":uid"]
+	cmd.OrgID = c.SignedInUser.GetOrgID()
+	cmd.UserID = c.SignedInUser.UserID
+
+	if resp := s.checkDashboardAccess(c, cmd.DashboardUID); resp != nil {
+		return resp
+	}
+
+	report, err := s.Update(c.Req.Context(), &cmd)
+	if err != nil {
+		return response.ErrOrFallback(http.StatusInternalServerError, "Failed to update report", err)
+	}
+	return response.JSON(http.StatusOK, report)
+}
+
+func (s *Service) handleDelete(c *contextmodel.ReqContext) response.Response {
+	if err := s.Delete(c.Req.Context(), c.SignedInUser.GetOrgID(), web.Params(c.Req)[":uid"]); err != nil {
+		return response.ErrOrFallback(http.StatusInternalServerError, "Failed to delete report", err)
+	}
+	return response.Success("Report deleted")
+}
+
+func (s *Service) handleListRuns(c *contextmodel.ReqContext) response.Response {
+	runs, err := s.ListRuns(c.Req.Context(), c.SignedInUser.GetOrgID(), web.Params(c.Req)[":uid"], c.QueryInt("limit"))
+	if err != nil {
+		return response.ErrOrFallback(http.StatusInternalServerError, "Failed to list report runs", err)
+	}
+	return response.JSON(http.StatusOK, runs)
+}
+
+func (s *Service) handleSend(c *contextmodel.ReqContext) response.Response {
+	run, err := s.Send(c.Req.Context(), c.SignedInUser.GetOrgID(), web.Params(c.Req)[":uid"])
+	if err != nil {
+		return response.ErrOrFallback(http.StatusInternalServerError, "Failed to send report", err)
+	}
+	return response.JSON(http.StatusOK, run)
+}
+
+// checkDashboardAccess prevents users from creating reports of dashboards they can't read,
+// because reports are rendered with the permissions of the user who saved them.
+func (s *Service) checkDashboardAccess(c *contextmodel.ReqContext, dashboardUID string) response.Response {
+	if dashboardUID == "" {
+		return nil
+	}
+	allowed, err := s.accessControl.Evaluate(c.Req.Context(), c.SignedInUser, dashboardReadEvaluator(dashboardUID))
+	if err != nil {
+		return response.Error(http.StatusInternalServerError, "Failed to check dashboard access", err)
+	}
+	if !allowed {
+		return response.Err(reports.ErrDashboardForbidden.Errorf("user can't read dashboard %s", dashboardUID))
+	}
+	return nil
+}
@@ -0,0 +1,44 @@
+package reportsimpl
+
+import (
+	ac "github.com/grafana/grafana/pkg/services/accesscontrol"
+	"github.com/grafana/grafana/pkg/services/org"
+	"github.com/grafana/grafana/pkg/services/reports"
+)
+
+var (
+	reportsReaderRole = ac.RoleDTO{
+		Name:        "fixed:reports:reader",
+		DisplayName: "Report reader",
+		Description: "List reports and their send history",
+		Group:       "Reports",
+		Permissions: []ac.Permission{
+			{Action: reports.ActionRead},
+		},
+	}
+
+	reportsWriterRole = ac.RoleDTO{
+		Name:        "fixed:reports:writer",
+		DisplayName: "Report writer",
+		Description: "Create, update, delete and send reports",
+		Group:       "Reports",
+		Permissions: []ac.Permission{
+			{Action: reports.ActionRead},
+			{Action: reports.ActionWrite},
+			{Action: reports.ActionSend},
+		},
+	}
+)
+
+func declareFixedRoles(service ac.Service) error {
+	reader := ac.RoleRegistration{
+		Role:   reportsReaderRole,
+		Grants: []string{string(org.RoleAdmin)},
+	}
+	writer := ac.RoleRegistration{
+		Role:   reportsWriterRole,
+		Grants: []string{string(org.RoleAdmin)},
+	}
+
+	return service.DeclareFixedRoles(reader, writer)
+}
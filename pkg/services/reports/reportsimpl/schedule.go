@@ -0,0 +1,40 @@
+package reportsimpl
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/robfig/cron/v3"
+)
+
+var cronParser = cron.NewParser(cron.Minute | cron.Hour | cron.Dom | cron.Month | cron.Dow | cron.Descriptor)
+
+// nextRun returns the first time the cron schedule fires after now in the given time zone.
+func nextRun(schedule, timezone string, now time.Time) (time.Time, error) {
+	s, err := cronParser.Parse(schedule)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid schedule %q: %w", schedule, err)
+	}
+
+	loc, err := loadLocation(timezone)
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	next := s.Next(now.In(loc))
+	if next.IsZero() {
+		return time.Time{}, fmt.Errorf("schedule %q never fires", schedule)
+	}
+	return next, nil
+}
+
+func loadLocation(timezone string) (*time.Location, error) {
+	if timezone == "" {
+		return time.UTC, nil
+	}
+	loc, err := time.LoadLocation(timezone)
+	if err != nil {
+		return nil, fmt.Errorf("invalid timezone %q: %w", timezone, err)
+	}
+	return loc, nil
+}
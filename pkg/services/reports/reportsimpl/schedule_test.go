@@ -0,0 +1,61 @@
+package reportsimpl
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNextRun(t *testing.T) {
+	now := time.Date(2023, 10, 2, 12, 30, 0, 0, time.UTC)
+
+	testCases := []struct {
+		desc     string
+		schedule string
+		timezone string
+		expected time.Time
+	}{
+		{
+			desc:     "daily in UTC",
+			schedule: "0 8 * * *",
+			expected: time.Date(2023, 10, 3, 8, 0, 0, 0, time.UTC),
+		},
+		{
+			desc:     "daily in a time zone ahead of UTC",
+			schedule: "0 8 * * *",
+			timezone: "Europe/Paris",
+			expected: time.Date(2023, 10, 3, 6, 0, 0, 0, time.UTC),
+		},
+		{
+			desc:     "daily in a time zone behind UTC",
+			schedule: "0 8 * * *",
+			timezone: "America/New_York",
+			expected: time.Date(2023, 10, 3, 12, 0, 0, 0, time.UTC),
+		},
+		{
+			desc:     "descriptor",
+			schedule: "@weekly",
+			expected: time.Date(2023, 10, 8, 0, 0, 0, 0, time.UTC),
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			next, err := nextRun(tc.schedule, tc.timezone, now)
+			require.NoError(t, err)
+			assert.True(t, tc.expected.Equal(next), "expected %s, got %s", tc.expected, next.UTC())
+		})
+	}
+
+	t.Run("invalid schedule", func(t *testing.T) {
+		_, err := nextRun("* * *", "", now)
+		require.Error(t, err)
+	})
+
+	t.Run("invalid timezone", func(t *testing.T) {
+		_, err := nextRun("0 8 * * *", "Mars/Olympus", now)
+		require.Error(t, err)
+	})
+}
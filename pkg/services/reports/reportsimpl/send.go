@@ -0,0 +1,205 @@
+package reportsimpl
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"image"
+	_ "image/png" // registers the PNG decoder used by image.DecodeConfig
+	"net/url"
+	"os"
+	"path"
+	"time"
+
+	"github.com/grafana/gofpdf"
+
+	"github.com/grafana/grafana/pkg/models"
+	"github.com/grafana/grafana/pkg/services/dashboards"
+	"github.com/grafana/grafana/pkg/services/notifications"
+	"github.com/grafana/grafana/pkg/services/rendering"
+	"github.com/grafana/grafana/pkg/services/reports"
+	"github.com/grafana/grafana/pkg/services/user"
+)
+
+// fallbackRenderHeight is used when the image renderer can't render full height images.
+const fallbackRenderHeight = 1200
+
+// send renders a report and sends it to its recipients, then records the run.
+// The returned error is only about recording the run, the failure of the
+// report itself is reported in the state of the run.
+func (s *Service) send(ctx context.Context, report *reports.Report, trigger reports.RunTrigger) (*reports.ReportRun, error) {
+	run := &reports.ReportRun{
+		ReportID:   report.ID,
+		OrgID:      report.OrgID,
+		Trigger:    trigger,
+		State:      reports.RunStateSuccess,
+		Recipients: len(report.Recipients),
+		StartedAt:  time.Now().UnixMilli(),
+	}
+
+	if err := s.renderAndSend(ctx, report); err != nil {
+		s.log.Warn("Failed to send report", "orgID", report.OrgID, "uid", report.UID, "error", err)
+		run.State = reports.RunStateFailed
+		run.Error = err.Error()
+	}
+	run.FinishedAt = time.Now().UnixMilli()
+
+	if err := s.store.InsertRun(ctx, run); err != nil {
+		return nil, err
+	}
+	return run, nil
+}
+
+func (s *Service) renderAndSend(ctx context.Context, report *reports.Report) error {
+	owner, err := s.userService.GetSignedInUser(ctx, &user.GetSignedInUserQuery{OrgID: report.OrgID, UserID: report.UserID})
+	if err != nil {
+		return fmt.Errorf("failed to get the owner of the report: %w", err)
+	}
+
+	dashboard, err := s.dashboardService.GetDashboard(ctx, &dashboards.GetDashboardQuery{OrgID: report.OrgID, UID: report.DashboardUID})
+	if err != nil {
+		return fmt.Errorf("failed to get dashboard: %w", err)
+	}
+
+	allowed, err := s.accessControl.Evaluate(ctx, owner, dashboardReadEvaluator(report.DashboardUID))
+	if err != nil {
+		return err
+	}
+	if !allowed {
+		return reports.ErrDashboardForbidden.Errorf("user %d can't read dashboard %s", report.UserID, report.DashboardUID)
+	}
+
+	query := dashboardQuery(report)
+	png, err := s.render(ctx, report, owner, dashboard, query)
+	if err != nil {
+		return err
+	}
+
+	attachments := make([]*notifications.SendEmailAttachFile, 0, len(report.Formats))
+	for _, format := range report.Formats {
+		switch format {
+		case reports.FormatPNG:
+			attachments = append(attachments, &notifications.SendEmailAttachFile{Name: dashboard.Slug + ".png", Content: png})
+		case reports.FormatPDF:
+			pdf, err := pngToPDF(png)
+			if err != nil {
+				return fmt.Errorf("failed to create PDF: %w", err)
+			}
+			attachments = append(attachments, &notifications.SendEmailAttachFile{Name: dashboard.Slug + ".pdf", Content: pdf})
+		}
+	}
+
+	var replyTo []string
+	if report.ReplyTo != "" {
+		replyTo = []string{report.ReplyTo}
+	}
+
+	cmd := &notifications.SendEmailCommandSync{
+		SendEmailCommand: notifications.SendEmailCommand{
+			To:       report.Recipients,
+			Template: "report",
+			Subject:  report.Name,
+			ReplyTo:  replyTo,
+			Data: map[string]any{
+				"Name":           report.Name,
+				"DashboardTitle": dashboard.Title,
+				"DashboardURL":   dashboards.GetFullDashboardURL(dashboard.UID, dashboard.Slug) + "?" + query.Encode(),
+				"Message":        report.Message,
+				"TimeFrom":       report.TimeFrom,
+				"TimeTo":         report.TimeTo,
+				"Timezone":       report.Timezone,
+			},
+			AttachedFiles: attachments,
+		},
+	}
+	if err := s.notificationService.SendEmailCommandHandlerSync(ctx, cmd); err != nil {
+		return fmt.Errorf("failed to send email: %w", err)
+	}
+	return nil
+}
+
+// render renders the dashboard of a report as a PNG image.
+func (s *Service) render(ctx context.Context, report *reports.Report, owner *user.SignedInUser, dashboard *dashboards.Dashboard, query url.Values) ([]byte, error) {
+	height := fallbackRenderHeight
+	if res, err := s.renderService.HasCapability(ctx, rendering.FullHeightImages); err == nil && res.IsSupported {
+		height = -1
+	}
+
+	result, err := s.renderService.Render(ctx, rendering.Opts{
+		TimeoutOpts: rendering.TimeoutOpts{Timeout: s.renderTimeout},
+		AuthOpts: rendering.AuthOpts{
+			OrgID:   report.OrgID,
+			UserID:  report.UserID,
+			OrgRole: owner.OrgRole,
+		},
+		ErrorOpts: rendering.ErrorOpts{
+			ErrorConcurrentLimitReached: true,
+			ErrorRenderUnavailable:      true,
+		},
+		Width:           s.renderWidth,
+		Height:          height,
+		Path:            path.Join("d", dashboard.UID, dashboard.Slug) + "?" + query.Encode(),
+		Timezone:        report.Timezone,
+		ConcurrentLimit: s.cfg.RendererConcurrentRequestLimit,
+		Priority:        rendering.PriorityLow,
+		Theme:           models.ThemeLight,
+	}, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to render dashboard: %w", err)
+	}
+	defer func() {
+		if err := os.Remove(result.FilePath); err != nil {
+			s.log.Warn("Failed to remove rendered image", "path", result.FilePath, "error", err)
+		}
+	}()
+
+	b, err := os.ReadFile(result.FilePath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read rendered image: %w", err)
+	}
+	return b, nil
+}
+
+// dashboardQuery returns the query parameters overriding the time range and
+// the variables of the dashboard of a report.
+func dashboardQuery(report *reports.Report) url.Values {
+	query := url.Values{}
+	query.Set("orgId", fmt.Sprint(report.OrgID))
+	if report.TimeFrom != "" {
+		query.Set("from", report.TimeFrom)
+		query.Set("to", report.TimeTo)
+	}
+	for name, values := range report.Variables {
+		for _, v := range values {
+			query.Add("var-"+name, v)
+		}
+	}
+	return query
+}
+
+// pngToPDF returns a single page PDF with the size of the image.
+func pngToPDF(img []byte) ([]byte, error) {
+	config, _, err := image.DecodeConfig(bytes.NewReader(img))
+	if err != nil {
+		return nil, err
+	}
+
+	w, h := float64(config.Width), float64(config.Height)
+	pdf := gofpdf.NewCustom(&gofpdf.InitType{
+		UnitStr: "pt",
+		Size:    gofpdf.SizeType{Wd: w, Ht: h},
+	})
+	pdf.SetMargins(0, 0, 0)
+	pdf.SetAutoPageBreak(false, 0)
+	pdf.AddPage()
+
+	opts := gofpdf.ImageOptions{ImageType: "PNG"}
+	pdf.RegisterImageOptionsReader("dashboard", opts, bytes.NewReader(img))
+	pdf.ImageOptions("dashboard", 0, 0, w, h, false, opts, 0, "")
+
+	var buf bytes.Buffer
+	if err := pdf.Output(&buf); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
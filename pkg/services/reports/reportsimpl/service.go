@@ -0,0 +1,309 @@
+package reportsimpl
+
+import (
+	"context"
+	"errors"
+	"net/mail"
+	"strings"
+	"time"
+
+	"github.com/grafana/grafana/pkg/api/routing"
+	"github.com/grafana/grafana/pkg/infra/db"
+	"github.com/grafana/grafana/pkg/infra/log"
+	ac "github.com/grafana/grafana/pkg/services/accesscontrol"
+	"github.com/grafana/grafana/pkg/services/cleanup/retention"
+	"github.com/grafana/grafana/pkg/services/dashboards"
+	"github.com/grafana/grafana/pkg/services/notifications"
+	"github.com/grafana/grafana/pkg/services/rendering"
+	"github.com/grafana/grafana/pkg/services/reports"
+	"github.com/grafana/grafana/pkg/services/user"
+	"github.com/grafana/grafana/pkg/setting"
+	"github.com/grafana/grafana/pkg/util"
+)
+
+const (
+	checkInterval      = time.Minute
+	defaultRunsLimit   = 20
+	maxRecipients      = 100
+	defaultRenderWidth = 1600
+)
+
+type Service struct {
+	cfg                 *setting.Cfg
+	store               store
+	accessControl       ac.AccessControl
+	dashboardService    dashboards.DashboardService
+	renderService       rendering.Service
+	notificationService notifications.EmailSender
+	userService         user.Service
+	log                 log.Logger
+
+	enabled       bool
+	renderTimeout time.Duration
+	renderWidth   int
+}
+
+var _ reports.Service = (*Service)(nil)
+
+func ProvideService(
+	cfg *setting.Cfg,
+	db db.DB,
+	routeRegister routing.RouteRegister,
+	accessControl ac.AccessControl,
+	accesscontrolService ac.Service,
+	dashboardService dashboards.DashboardService,
+	renderService rendering.Service,
+	notificationService notifications.EmailSender,
+	userService user.Service,
+	retentionJobs retention.JobRegistry,
+) (*Service, error) {
+	section := cfg.SectionWithEnvOverrides("reports")
+	s := &Service{
+		cfg:                 cfg,
+		store:               &sqlStore{db: db},
+		accessControl:       accessControl,
+		dashboardService:    dashboardService,
+		renderService:       renderService,
+		notificationService: notificationService,
+		userService:         userService,
+		log:                 log.New("reports"),
+		enabled:             section.Key("enabled").MustBool(false),
+		renderTimeout:       section.Key("render_timeout").MustDuration(time.Minute),
+		renderWidth:         section.Key("render_width").MustInt(defaultRenderWidth),
+	}
+
+	if !s.enabled {
+		return s, nil
+	}
+
+	if err := declareFixedRoles(accesscontrolService); err != nil {
+		return nil, err
+	}
+
+	s.registerAPIEndpoints(routeRegister)
+
+	retentionJobs.RegisterRetentionJob(retention.Job{
+		DataType:  "report_runs",
+		OrgScoped: true,
+		Default:   retention.Policy{MaxAge: 90 * 24 * time.Hour},
+		Apply:     retention.Table{Store: db, Table: "report_run", TimeColumn: "started_at", TimeUnit: time.Millisecond, OrgColumn: "org_id"}.Apply,
+	})
+
+	return s, nil
+}
+
+// Run sends the reports when they are due. Each run is claimed in the database
+// so that only one instance sends it when several share the database.
+func (s *Service) Run(ctx context.Context) error {
+	if !s.enabled {
+		return nil
+	}
+
+	ticker := time.NewTicker(checkInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			s.sendDueReports(ctx)
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+func (s *Service) sendDueReports(ctx context.Context) {
+	now := time.Now()
+	due, err := s.store.GetDue(ctx, now.UnixMilli())
+	if err != nil {
+		s.log.Error("Failed to get due reports", "error", err)
+		return
+	}
+
+	for _, report := range due {
+		logger := s.log.New("orgID", report.OrgID, "uid", report.UID)
+
+		// the next run is computed from now so that the runs missed while Grafana was down are sent once
+		next, err := nextRun(report.Schedule, report.Timezone, now)
+		if err != nil {
+			logger.Error("Failed to compute the next run of the report", "error", err)
+			continue
+		}
+
+		claimed, err := s.store.ClaimRun(ctx, report.ID, report.NextRunAt, next.UnixMilli())
+		if err != nil {
+			logger.Error("Failed to claim report run", "error", err)
+			continue
+		}
+		if !claimed {
+			logger.Debug("Report run claimed by another instance")
+			continue
+		}
+
+		if _, err := s.send(ctx, report, reports.RunTriggerSchedule); err != nil {
+			logger.Error("Failed to record report run", "error", err)
+		}
+	}
+}
+
+func (s *Service) Create(ctx context.Context, cmd *reports.CreateReportCommand) (*reports.Report, error) {
+	now := time.Now()
+	report := &reports.Report{
+		UID:     util.GenerateShortUID(),
+		OrgID:   cmd.OrgID,
+		UserID:  cmd.UserID,
+		Created: now,
+		Updated: now,
+	}
+	if err := s.applySpec(ctx, report, cmd.ReportSpec, now); err != nil {
+		return nil, err
+	}
+
+	if err := s.store.Insert(ctx, report); err != nil {
+		return nil, err
+	}
+	return report, nil
+}
+
+func (s *Service) Update(ctx context.Context, cmd *reports.UpdateReportCommand) (*reports.Report, error) {
+	report, err := s.store.Get(ctx, cmd.OrgID, cmd.UID)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	report.UserID = cmd.UserID
+	report.Updated = now
+	if err := s.applySpec(ctx, report, cmd.ReportSpec, now); err != nil {
+		return nil, err
+	}
+
+	if err := s.store.Update(ctx, report); err != nil {
+		return nil, err
+	}
+	return report, nil
+}
+
+func (s *Service) Delete(ctx context.Context, orgID int64, uid string) error {
+	return s.store.Delete(ctx, orgID, uid)
+}
+
+func (s *Service) Get(ctx context.Context, orgID int64, uid string) (*reports.Report, error) {
+	return s.store.Get(ctx, orgID, uid)
+}
+
+func (s *Service) List(ctx context.Context, orgID int64) ([]*reports.Report, error) {
+	return s.store.List(ctx, orgID)
+}
+
+func (s *Service) ListRuns(ctx context.Context, orgID int64, uid string, limit int) ([]*reports.ReportRun, error) {
+	report, err := s.store.Get(ctx, orgID, uid)
+	if err != nil {
+		return nil, err
+	}
+	if limit <= 0 {
+		limit = defaultRunsLimit
+	}
+	return s.store.ListRuns(ctx, report.ID, limit)
+}
+
+func (s *Service) Send(ctx context.Context, orgID int64, uid string) (*reports.ReportRun, error) {
+	report, err := s.store.Get(ctx, orgID, uid)
+	if err != nil {
+		return nil, err
+	}
+	return s.send(ctx, report, reports.RunTriggerManual)
+}
+
+// applySpec validates spec and sets it on report, along with its next run.
+func (s *Service) applySpec(ctx context.Context, report *reports.Report, spec reports.ReportSpec, now time.Time) error {
+	spec.Name = strings.TrimSpace(spec.Name)
+	if spec.Name == "" {
+		return reports.ErrReportInvalid.Errorf("name is required")
+	}
+
+	if spec.DashboardUID == "" {
+		return reports.ErrReportInvalid.Errorf("dashboard is required")
+	}
+	_, err := s.dashboardService.GetDashboard(ctx, &dashboards.GetDashboardQuery{OrgID: report.OrgID, UID: spec.DashboardUID})
+	if err != nil {
+		if errors.Is(err, dashboards.ErrDashboardNotFound) {
+			return reports.ErrReportInvalid.Errorf("dashboard %s not found", spec.DashboardUID)
+		}
+		return err
+	}
+
+	next, err := nextRun(spec.Schedule, spec.Timezone, now)
+	if err != nil {
+		return reports.ErrReportInvalid.Errorf("%s", err)
+	}
+
+	if len(spec.Recipients) == 0 {
+		return reports.ErrReportInvalid.Errorf("at least one recipient is required")
+	}
+	if len(spec.Recipients) > maxRecipients {
+		return reports.ErrReportInvalid.Errorf("a report can't have more than %d recipients", maxRecipients)
+	}
+	for _, r := range spec.Recipients {
+		if _, err := mail.ParseAddress(r); err != nil {
+			return reports.ErrReportInvalid.Errorf("invalid recipient %q: %s", r, err)
+		}
+	}
+	if spec.ReplyTo != "" {
+		if _, err := mail.ParseAddress(spec.ReplyTo); err != nil {
+			return reports.ErrReportInvalid.Errorf("invalid reply to address %q: %s", spec.ReplyTo, err)
+		}
+	}
+
+	if (spec.TimeFrom == "") != (spec.TimeTo == "") {
+		return reports.ErrReportInvalid.Errorf("both ends of the time range must be set")
+	}
+
+	for name := range spec.Variables {
+		if name == "" {
+			return reports.ErrReportInvalid.Errorf("variable name is required")
+		}
+	}
+
+	formats := make([]reports.Format, 0, len(spec.Formats))
+	for _, f := range spec.Formats {
+		if f != reports.FormatPDF && f != reports.FormatPNG {
+			return reports.ErrReportInvalid.Errorf("unsupported format %q", f)
+		}
+		if !containsFormat(formats, f) {
+			formats = append(formats, f)
+		}
+	}
+	if len(formats) == 0 {
+		formats = []reports.Format{reports.FormatPDF}
+	}
+
+	report.Name = spec.Name
+	report.DashboardUID = spec.DashboardUID
+	report.Schedule = spec.Schedule
+	report.Timezone = spec.Timezone
+	report.Recipients = spec.Recipients
+	report.ReplyTo = spec.ReplyTo
+	report.Message = spec.Message
+	report.TimeFrom = spec.TimeFrom
+	report.TimeTo = spec.TimeTo
+	report.Variables = spec.Variables
+	report.Formats = formats
+	report.IsPaused = spec.IsPaused
+	report.NextRunAt = next.UnixMilli()
+	return nil
+}
+
+func containsFormat(formats []reports.Format, format reports.Format) bool {
+	for _, f := range formats {
+		if f == format {
+			return true
+		}
+	}
+	return false
+}
+
+// dashboardReadEvaluator checks that a user can read the dashboard of a report.
+func dashboardReadEvaluator(dashboardUID string) ac.Evaluator {
+	return ac.EvalPermission(dashboards.ActionDashboardsRead, dashboards.ScopeDashboardsProvider.GetResourceScopeUID(dashboardUID))
+}
@@ -0,0 +1,265 @@
+package reportsimpl
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"image"
+	"image/png"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/services/accesscontrol/actest"
+	"github.com/grafana/grafana/pkg/services/dashboards"
+	"github.com/grafana/grafana/pkg/services/notifications"
+	"github.com/grafana/grafana/pkg/services/org"
+	"github.com/grafana/grafana/pkg/services/rendering"
+	"github.com/grafana/grafana/pkg/services/reports"
+	"github.com/grafana/grafana/pkg/services/user"
+	"github.com/grafana/grafana/pkg/services/user/usertest"
+	"github.com/grafana/grafana/pkg/setting"
+)
+
+func TestService_Create(t *testing.T) {
+	s, _ := setupTestService(t, gomock.NewController(t))
+
+	validSpec := func() reports.ReportSpec {
+		return reports.ReportSpec{
+			Name:         "Weekly",
+			DashboardUID: "dash",
+			Schedule:     "0 8 * * 1",
+			Timezone:     "Europe/Paris",
+			Recipients:   []string{"ops@example.com"},
+		}
+	}
+
+	t.Run("defaults to PDF and sets the next run", func(t *testing.T) {
+		report, err := s.Create(context.Background(), &reports.CreateReportCommand{ReportSpec: validSpec(), OrgID: 1, UserID: 2})
+		require.NoError(t, err)
+		assert.NotEmpty(t, report.UID)
+		assert.Equal(t, []reports.Format{reports.FormatPDF}, report.Formats)
+		assert.Greater(t, report.NextRunAt, time.Now().UnixMilli())
+	})
+
+	testCases := []struct {
+		desc   string
+		modify func(spec *reports.ReportSpec)
+	}{
+		{desc: "missing name", modify: func(spec *reports.ReportSpec) { spec.Name = " " }},
+		{desc: "invalid schedule", modify: func(spec *reports.ReportSpec) { spec.Schedule = "every day" }},
+		{desc: "invalid timezone", modify: func(spec *reports.ReportSpec) { spec.Timezone = "Mars/Olympus" }},
+		{desc: "no recipients", modify: func(spec *reports.ReportSpec) { spec.Recipients = nil }},
+		{desc: "invalid recipient", modify: func(spec *reports.ReportSpec) { spec.Recipients = []string{"ops"} }},
+		{desc: "half time range", modify: func(spec *reports.ReportSpec) { spec.TimeFrom = "now-7d" }},
+		{desc: "unsupported format", modify: func(spec *reports.ReportSpec) { spec.Formats = []reports.Format{"docx"} }},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			spec := validSpec()
+			tc.modify(&spec)
+			_, err := s.Create(context.Background(), &reports.CreateReportCommand{ReportSpec: spec, OrgID: 1, UserID: 2})
+			require.ErrorIs(t, err, reports.ErrReportInvalid)
+		})
+	}
+}
+
+func TestService_Send(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	s, ns := setupTestService(t, ctrl)
+	renderer := s.renderService.(*rendering.MockService)
+
+	report := &reports.Report{
+		ID:           1,
+		UID:          "report",
+		OrgID:        1,
+		Name:         "Weekly",
+		DashboardUID: "dash",
+		Schedule:     "0 8 * * 1",
+		Timezone:     "Europe/Paris",
+		Recipients:   []string{"ops@example.com", "dev@example.com"},
+		ReplyTo:      "owner@example.com",
+		TimeFrom:     "now-7d",
+		TimeTo:       "now",
+		Variables:    map[string][]string{"server": {"a"}},
+		Formats:      []reports.Format{reports.FormatPDF, reports.FormatPNG},
+		UserID:       2,
+	}
+	require.NoError(t, s.store.Insert(context.Background(), report))
+
+	t.Run("renders the dashboard and sends it by email", func(t *testing.T) {
+		renderer.EXPECT().HasCapability(gomock.Any(), rendering.FullHeightImages).Return(rendering.CapabilitySupportRequestResult{IsSupported: true}, nil)
+		renderer.EXPECT().Render(gomock.Any(), gomock.Any(), nil).DoAndReturn(func(_ context.Context, opts rendering.Opts, _ rendering.Session) (*rendering.RenderResult, error) {
+			assert.Equal(t, "d/dash/dashboard?from=now-7d&orgId=1&to=now&var-server=a", opts.Path)
+			assert.Equal(t, -1, opts.Height)
+			assert.Equal(t, "Europe/Paris", opts.Timezone)
+			assert.Equal(t, int64(2), opts.UserID)
+			assert.Equal(t, org.RoleEditor, opts.OrgRole)
+			return &rendering.RenderResult{FilePath: writeTestImage(t)}, nil
+		})
+
+		run, err := s.Send(context.Background(), 1, "report")
+		require.NoError(t, err)
+		assert.Equal(t, reports.RunStateSuccess, run.State)
+		assert.Equal(t, reports.RunTriggerManual, run.Trigger)
+		assert.Equal(t, 2, run.Recipients)
+
+		sent := ns.EmailSync
+		assert.Equal(t, report.Recipients, sent.To)
+		assert.Equal(t, []string{"owner@example.com"}, sent.ReplyTo)
+		assert.Equal(t, "Weekly", sent.Subject)
+		require.Len(t, sent.AttachedFiles, 2)
+		assert.Equal(t, "dashboard.pdf", sent.AttachedFiles[0].Name)
+		assert.True(t, bytes.HasPrefix(sent.AttachedFiles[0].Content, []byte("%PDF")))
+		assert.Equal(t, "dashboard.png", sent.AttachedFiles[1].Name)
+	})
+
+	t.Run("records failed runs", func(t *testing.T) {
+		renderer.EXPECT().HasCapability(gomock.Any(), rendering.FullHeightImages).Return(rendering.CapabilitySupportRequestResult{}, nil)
+		renderer.EXPECT().Render(gomock.Any(), gomock.Any(), nil).Return(nil, errors.New("renderer unavailable"))
+
+		run, err := s.Send(context.Background(), 1, "report")
+		require.NoError(t, err)
+		assert.Equal(t, reports.RunStateFailed, run.State)
+		assert.Contains(t, run.Error, "renderer unavailable")
+
+		runs, err := s.ListRuns(context.Background(), 1, "report", 0)
+		require.NoError(t, err)
+		require.Len(t, runs, 2)
+	})
+
+	t.Run("fails when the owner can't read the dashboard", func(t *testing.T) {
+		s.accessControl = actest.FakeAccessControl{ExpectedEvaluate: false}
+
+		run, err := s.Send(context.Background(), 1, "report")
+		require.NoError(t, err)
+		assert.Equal(t, reports.RunStateFailed, run.State)
+	})
+}
+
+func setupTestService(t *testing.T, ctrl *gomock.Controller) (*Service, *notifications.NotificationServiceMock) {
+	t.Helper()
+
+	dashboardService := dashboards.NewFakeDashboardService(t)
+	dashboardService.On("GetDashboard", mock.Anything, mock.AnythingOfType("*dashboards.GetDashboardQuery")).
+		Return(&dashboards.Dashboard{ID: 1, UID: "dash", Slug: "dashboard", Title: "Dashboard", OrgID: 1}, nil).Maybe()
+
+	userService := usertest.NewUserServiceFake()
+	userService.ExpectedSignedInUser = &user.SignedInUser{UserID: 2, OrgID: 1, OrgRole: org.RoleEditor}
+
+	ns := notifications.MockNotificationService()
+
+	return &Service{
+		cfg:                 setting.NewCfg(),
+		store:               newFakeStore(),
+		accessControl:       actest.FakeAccessControl{ExpectedEvaluate: true},
+		dashboardService:    dashboardService,
+		renderService:       rendering.NewMockService(ctrl),
+		notificationService: ns,
+		userService:         userService,
+		log:                 log.NewNopLogger(),
+		enabled:             true,
+		renderTimeout:       time.Minute,
+		renderWidth:         defaultRenderWidth,
+	}, ns
+}
+
+// writeTestImage writes a PNG image to a temporary file, which the service removes once sent.
+func writeTestImage(t *testing.T) string {
+	t.Helper()
+
+	var buf bytes.Buffer
+	require.NoError(t, png.Encode(&buf, image.NewRGBA(image.Rect(0, 0, 40, 20))))
+
+	path := filepath.Join(t.TempDir(), "dashboard.png")
+	require.NoError(t, os.WriteFile(path, buf.Bytes(), 0600))
+	return path
+}
+
+type fakeStore struct {
+	reports map[string]*reports.Report
+	runs    []*reports.ReportRun
+}
+
+var _ store = &fakeStore{}
+
+func newFakeStore() *fakeStore {
+	return &fakeStore{reports: map[string]*reports.Report{}}
+}
+
+func (f *fakeStore) Insert(_ context.Context, report *reports.Report) error {
+	report.ID = int64(len(f.reports) + 1)
+	f.reports[report.UID] = report
+	return nil
+}
+
+func (f *fakeStore) Update(_ context.Context, report *reports.Report) error {
+	f.reports[report.UID] = report
+	return nil
+}
+
+func (f *fakeStore) Delete(_ context.Context, orgID int64, uid string) error {
+	delete(f.reports, uid)
+	return nil
+}
+
+func (f *fakeStore) Get(_ context.Context, orgID int64, uid string) (*reports.Report, error) {
+	report, ok := f.reports[uid]
+	if !ok || report.OrgID != orgID {
+		return nil, reports.ErrReportNotFound
+	}
+	return report, nil
+}
+
+func (f *fakeStore) List(_ context.Context, orgID int64) ([]*reports.Report, error) {
+	result := make([]*reports.Report, 0)
+	for _, r := range f.reports {
+		if r.OrgID == orgID {
+			result = append(result, r)
+		}
+	}
+	return result, nil
+}
+
+func (f *fakeStore) GetDue(_ context.Context, now int64) ([]*reports.Report, error) {
+	result := make([]*reports.Report, 0)
+	for _, r := range f.reports {
+		if !r.IsPaused && r.NextRunAt <= now {
+			result = append(result, r)
+		}
+	}
+	return result, nil
+}
+
+func (f *fakeStore) ClaimRun(_ context.Context, id int64, nextRunAt int64, newNextRunAt int64) (bool, error) {
+	for _, r := range f.reports {
+		if r.ID == id && r.NextRunAt == nextRunAt {
+			r.NextRunAt = newNextRunAt
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (f *fakeStore) InsertRun(_ context.Context, run *reports.ReportRun) error {
+	run.ID = int64(len(f.runs) + 1)
+	f.runs = append(f.runs, run)
+	return nil
+}
+
+func (f *fakeStore) ListRuns(_ context.Context, reportID int64, limit int) ([]*reports.ReportRun, error) {
+	result := make([]*reports.ReportRun, 0)
+	for i := len(f.runs) - 1; i >= 0 && len(result) < limit; i-- {
+		if f.runs[i].ReportID == reportID {
+			result = append(result, f.runs[i])
+		}
+	}
+	return result, nil
+}
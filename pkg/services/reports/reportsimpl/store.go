@@ -0,0 +1,132 @@
+package reportsimpl
+
+import (
+	"context"
+
+	"github.com/grafana/grafana/pkg/infra/db"
+	"github.com/grafana/grafana/pkg/services/reports"
+)
+
+type store interface {
+	Insert(ctx context.Context, report *reports.Report) error
+	Update(ctx context.Context, report *reports.Report) error
+	Delete(ctx context.Context, orgID int64, uid string) error
+	Get(ctx context.Context, orgID int64, uid string) (*reports.Report, error)
+	List(ctx context.Context, orgID int64) ([]*reports.Report, error)
+	// GetDue returns the reports that aren't paused and whose next run is at or before now.
+	GetDue(ctx context.Context, now int64) ([]*reports.Report, error)
+	// ClaimRun moves the next run of a report from nextRunAt to newNextRunAt. It returns false when
+	// the next run is no longer nextRunAt, meaning that another instance claimed the run or that
+	// the report was updated.
+	ClaimRun(ctx context.Context, id int64, nextRunAt int64, newNextRunAt int64) (bool, error)
+	InsertRun(ctx context.Context, run *reports.ReportRun) error
+	ListRuns(ctx context.Context, reportID int64, limit int) ([]*reports.ReportRun, error)
+}
+
+type sqlStore struct {
+	db db.DB
+}
+
+var _ store = &sqlStore{}
+
+func (s *sqlStore) Insert(ctx context.Context, report *reports.Report) error {
+	return s.db.WithDbSession(ctx, func(sess *db.Session) error {
+		_, err := sess.Insert(report)
+		return err
+	})
+}
+
+func (s *sqlStore) Update(ctx context.Context, report *reports.Report) error {
+	return s.db.WithDbSession(ctx, func(sess *db.Session) error {
+		affected, err := sess.ID(report.ID).AllCols().Update(report)
+		if err != nil {
+			return err
+		}
+		if affected == 0 {
+			return reports.ErrReportNotFound
+		}
+		return nil
+	})
+}
+
+func (s *sqlStore) Delete(ctx context.Context, orgID int64, uid string) error {
+	return s.db.WithTransactionalDbSession(ctx, func(sess *db.Session) error {
+		report := reports.Report{OrgID: orgID, UID: uid}
+		has, err := sess.Get(&report)
+		if err != nil {
+			return err
+		}
+		if !has {
+			return reports.ErrReportNotFound
+		}
+
+		if _, err := sess.Exec("DELETE FROM report_run WHERE report_id = ?", report.ID); err != nil {
+			return err
+		}
+		_, err = sess.Exec("DELETE FROM report WHERE id = ?", report.ID)
+		return err
+	})
+}
+
+func (s *sqlStore) Get(ctx context.Context, orgID int64, uid string) (*reports.Report, error) {
+	report := reports.Report{OrgID: orgID, UID: uid}
+	err := s.db.WithDbSession(ctx, func(sess *db.Session) error {
+		has, err := sess.Get(&report)
+		if err != nil {
+			return err
+		}
+		if !has {
+			return reports.ErrReportNotFound
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &report, nil
+}
+
+func (s *sqlStore) List(ctx context.Context, orgID int64) ([]*reports.Report, error) {
+	result := make([]*reports.Report, 0)
+	err := s.db.WithDbSession(ctx, func(sess *db.Session) error {
+		return sess.Where("org_id = ?", orgID).Asc("name").Find(&result)
+	})
+	return result, err
+}
+
+func (s *sqlStore) GetDue(ctx context.Context, now int64) ([]*reports.Report, error) {
+	result := make([]*reports.Report, 0)
+	err := s.db.WithDbSession(ctx, func(sess *db.Session) error {
+		return sess.Where("is_paused = ? AND next_run_at <= ?", s.db.GetDialect().BooleanStr(false), now).Asc("next_run_at").Find(&result)
+	})
+	return result, err
+}
+
+func (s *sqlStore) ClaimRun(ctx context.Context, id int64, nextRunAt int64, newNextRunAt int64) (bool, error) {
+	var claimed bool
+	err := s.db.WithDbSession(ctx, func(sess *db.Session) error {
+		res, err := sess.Exec("UPDATE report SET next_run_at = ? WHERE id = ? AND next_run_at = ?", newNextRunAt, id, nextRunAt)
+		if err != nil {
+			return err
+		}
+		affected, err := res.RowsAffected()
+		claimed = affected == 1
+		return err
+	})
+	return claimed, err
+}
+
+func (s *sqlStore) InsertRun(ctx context.Context, run *reports.ReportRun) error {
+	return s.db.WithDbSession(ctx, func(sess *db.Session) error {
+		_, err := sess.Insert(run)
+		return err
+	})
+}
+
+func (s *sqlStore) ListRuns(ctx context.Context, reportID int64, limit int) ([]*reports.ReportRun, error) {
+	result := make([]*reports.ReportRun, 0)
+	err := s.db.WithDbSession(ctx, func(sess *db.Session) error {
+		return sess.Where("report_id = ?", reportID).Desc("started_at").Limit(limit).Find(&result)
+	})
+	return result, err
+}
@@ -0,0 +1,132 @@
+package reportsimpl
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/infra/db"
+	"github.com/grafana/grafana/pkg/services/reports"
+)
+
+func TestIntegrationReportStore(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+
+	ctx := context.Background()
+	s := &sqlStore{db: db.InitTestDB(t)}
+
+	newReport := func(uid string, nextRunAt int64) *reports.Report {
+		return &reports.Report{
+			UID:          uid,
+			OrgID:        1,
+			Name:         "report " + uid,
+			DashboardUID: "dash",
+			Schedule:     "0 8 * * *",
+			Recipients:   []string{"ops@example.com"},
+			Variables:    map[string][]string{"server": {"a", "b"}},
+			Formats:      []reports.Format{reports.FormatPDF},
+			UserID:       1,
+			Created:      time.Now(),
+			Updated:      time.Now(),
+			NextRunAt:    nextRunAt,
+		}
+	}
+
+	due := newReport("due", 1000)
+	later := newReport("later", 5000)
+	paused := newReport("paused", 1000)
+	paused.IsPaused = true
+	for _, r := range []*reports.Report{due, later, paused} {
+		require.NoError(t, s.Insert(ctx, r))
+	}
+
+	t.Run("get returns the stored report", func(t *testing.T) {
+		got, err := s.Get(ctx, 1, "due")
+		require.NoError(t, err)
+		assert.Equal(t, due.ID, got.ID)
+		assert.Equal(t, []string{"ops@example.com"}, got.Recipients)
+		assert.Equal(t, map[string][]string{"server": {"a", "b"}}, got.Variables)
+		assert.Equal(t, []reports.Format{reports.FormatPDF}, got.Formats)
+
+		_, err = s.Get(ctx, 2, "due")
+		require.ErrorIs(t, err, reports.ErrReportNotFound)
+	})
+
+	t.Run("list returns the reports of the org", func(t *testing.T) {
+		list, err := s.List(ctx, 1)
+		require.NoError(t, err)
+		require.Len(t, list, 3)
+
+		list, err = s.List(ctx, 2)
+		require.NoError(t, err)
+		require.Empty(t, list)
+	})
+
+	t.Run("get due skips paused and future reports", func(t *testing.T) {
+		list, err := s.GetDue(ctx, 2000)
+		require.NoError(t, err)
+		require.Len(t, list, 1)
+		assert.Equal(t, "due", list[0].UID)
+	})
+
+	t.Run("a run can only be claimed once", func(t *testing.T) {
+		claimed, err := s.ClaimRun(ctx, due.ID, 1000, 9000)
+		require.NoError(t, err)
+		require.True(t, claimed)
+
+		claimed, err = s.ClaimRun(ctx, due.ID, 1000, 9000)
+		require.NoError(t, err)
+		require.False(t, claimed)
+
+		list, err := s.GetDue(ctx, 2000)
+		require.NoError(t, err)
+		require.Empty(t, list)
+	})
+
+	t.Run("runs are listed most recent first", func(t *testing.T) {
+		for i := int64(1); i <= 3; i++ {
+			require.NoError(t, s.InsertRun(ctx, &reports.ReportRun{
+				ReportID:  later.ID,
+				OrgID:     1,
+				Trigger:   reports.RunTriggerManual,
+				State:     reports.RunStateSuccess,
+				StartedAt: i * 1000,
+			}))
+		}
+
+		runs, err := s.ListRuns(ctx, later.ID, 2)
+		require.NoError(t, err)
+		require.Len(t, runs, 2)
+		assert.Equal(t, int64(3000), runs[0].StartedAt)
+		assert.Equal(t, int64(2000), runs[1].StartedAt)
+	})
+
+	t.Run("update overwrites the report", func(t *testing.T) {
+		later.Name = "renamed"
+		later.IsPaused = true
+		require.NoError(t, s.Update(ctx, later))
+
+		got, err := s.Get(ctx, 1, "later")
+		require.NoError(t, err)
+		assert.Equal(t, "renamed", got.Name)
+		assert.True(t, got.IsPaused)
+	})
+
+	t.Run("delete removes the report and its runs", func(t *testing.T) {
+		require.NoError(t, s.Delete(ctx, 1, "later"))
+
+		_, err := s.Get(ctx, 1, "later")
+		require.ErrorIs(t, err, reports.ErrReportNotFound)
+
+		runs, err := s.ListRuns(ctx, later.ID, 10)
+		require.NoError(t, err)
+		require.Empty(t, runs)
+
+		require.ErrorIs(t, s.Delete(ctx, 1, "later"), reports.ErrReportNotFound)
+	})
+}
@@ -121,6 +121,7 @@ func (s *HeadlessScreenshotService) Take(ctx context.Context, opts ScreenshotOpt
 		Height:          opts.Height,
 		Theme:           opts.Theme,
 		ConcurrentLimit: setting.AlertingRenderLimit,
+		Priority:        rendering.PriorityLow,
 		Path:            u.String(),
 	}
 
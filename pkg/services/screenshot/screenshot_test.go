@@ -54,6 +54,7 @@ func TestHeadlessScreenshotService(t *testing.T) {
 		Theme:           DefaultTheme,
 		Path:            "d-solo/foo/bar?from=now-6h&orgId=2&panelId=4&to=now-2h",
 		ConcurrentLimit: setting.AlertingRenderLimit,
+		Priority:        rendering.PriorityLow,
 	}
 
 	opts.From = "now-6h"
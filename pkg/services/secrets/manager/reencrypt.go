@@ -0,0 +1,211 @@
+package manager
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"time"
+
+	"github.com/grafana/grafana/pkg/infra/db"
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/services/encryption"
+	"github.com/grafana/grafana/pkg/services/kmsproviders"
+	"github.com/grafana/grafana/pkg/services/secrets"
+	"github.com/grafana/grafana/pkg/setting"
+)
+
+// ReEncryptionService re-encrypts, in the background, the data keys and the secrets of the
+// SecretTargets that were encrypted with another algorithm than the one configured in [security.encryption],
+// for example after switching from aes-cfb to aes-gcm.
+type ReEncryptionService struct {
+	cfg            *setting.Cfg
+	secretsService *SecretsService
+	sqlStore       db.DB
+	log            log.Logger
+
+	enabled    bool
+	batchSize  int
+	retryDelay time.Duration
+	targets    []SecretTarget
+}
+
+func ProvideReEncryptionService(cfg *setting.Cfg, secretsService *SecretsService, sqlStore db.DB) *ReEncryptionService {
+	section := cfg.SectionWithEnvOverrides("security.encryption")
+	return &ReEncryptionService{
+		cfg:            cfg,
+		secretsService: secretsService,
+		sqlStore:       sqlStore,
+		log:            log.New("secrets.reencryption"),
+		enabled:        section.Key("background_reencryption").MustBool(false),
+		batchSize:      section.Key("background_reencryption_batch_size").MustInt(100),
+		retryDelay:     section.Key("background_reencryption_retry_interval").MustDuration(time.Hour),
+		targets:        SecretTargets(),
+	}
+}
+
+func (s *ReEncryptionService) IsDisabled() bool {
+	return !s.enabled
+}
+
+// Run re-encrypts the outdated secrets and retries after the retry interval
+// until every secret is encrypted with the current algorithm.
+func (s *ReEncryptionService) Run(ctx context.Context) error {
+	for {
+		remaining, err := s.ReEncrypt(ctx)
+		if err != nil && !errors.Is(err, context.Canceled) {
+			s.log.Error("Background re-encryption failed", "error", err)
+		}
+		if err == nil && remaining == 0 {
+			return nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-time.After(s.retryDelay):
+		}
+	}
+}
+
+// ReEncrypt re-encrypts the data keys and the secrets that weren't encrypted with the
+// current algorithm. It returns the number of secrets that couldn't be re-encrypted.
+func (s *ReEncryptionService) ReEncrypt(ctx context.Context) (int, error) {
+	algorithm := s.currentAlgorithm()
+	s.log.Info("Re-encrypting secrets", "algorithm", algorithm)
+
+	if err := s.reEncryptDataKeys(ctx, algorithm); err != nil {
+		return 0, err
+	}
+
+	var remaining, reEncrypted int
+	for _, t := range s.targets {
+		done, failed, err := s.reEncryptTarget(ctx, t, algorithm)
+		if err != nil {
+			return 0, err
+		}
+		reEncrypted += done
+		remaining += failed
+	}
+
+	if remaining > 0 {
+		s.log.Warn("Secrets have been re-encrypted with errors", "algorithm", algorithm, "reEncrypted", reEncrypted, "failed", remaining)
+	} else {
+		s.log.Info("Secrets have been re-encrypted successfully", "algorithm", algorithm, "reEncrypted", reEncrypted)
+	}
+
+	return remaining, nil
+}
+
+func (s *ReEncryptionService) currentAlgorithm() string {
+	return s.cfg.SectionWithEnvOverrides("security.encryption").Key("algorithm").MustString(encryption.DefaultAlgorithm)
+}
+
+// reEncryptDataKeys re-encrypts all data keys if any data key protected by the secret key
+// provider was encrypted with another algorithm. Data keys of other providers are encrypted
+// by their KMS and are left as they are.
+func (s *ReEncryptionService) reEncryptDataKeys(ctx context.Context, algorithm string) error {
+	dataKeys, err := s.secretsService.store.GetAllDataKeys(ctx)
+	if err != nil {
+		return err
+	}
+
+	outdated := 0
+	for _, dataKey := range dataKeys {
+		if kmsproviders.NormalizeProviderID(dataKey.Provider) != kmsproviders.Default {
+			continue
+		}
+		if payloadAlgorithm(dataKey.EncryptedData) != algorithm {
+			outdated++
+		}
+	}
+
+	if outdated == 0 {
+		return nil
+	}
+
+	s.log.Info("Re-encrypting data keys", "outdated", outdated)
+	return s.secretsService.ReEncryptDataKeys(ctx)
+}
+
+// reEncryptTarget re-encrypts, batch by batch, the rows of t with at least one
+// payload encrypted with another algorithm.
+func (s *ReEncryptionService) reEncryptTarget(ctx context.Context, t SecretTarget, algorithm string) (int, int, error) {
+	var reEncrypted, failed int
+
+	err := WalkSecretRows(ctx, s.sqlStore, t, 0, s.batchSize, func(rows []SecretRow) error {
+		for _, row := range rows {
+			if !s.isOutdated(row.Payloads, algorithm) {
+				continue
+			}
+
+			if err := s.reEncryptRow(ctx, t, row, algorithm); err != nil {
+				if ctx.Err() != nil {
+					return ctx.Err()
+				}
+				s.log.Warn("Could not re-encrypt secret", "target", t.Name(), "id", row.ID, "error", err)
+				failed++
+				continue
+			}
+			reEncrypted++
+		}
+		return nil
+	})
+
+	return reEncrypted, failed, err
+}
+
+func (s *ReEncryptionService) isOutdated(payloads map[string][]byte, algorithm string) bool {
+	for _, payload := range payloads {
+		if len(payload) > 0 && payloadAlgorithm(payload) != algorithm {
+			return true
+		}
+	}
+	return false
+}
+
+func (s *ReEncryptionService) reEncryptRow(ctx context.Context, t SecretTarget, row SecretRow, algorithm string) error {
+	return RewriteSecretRow(ctx, s.sqlStore, t, row, func(ctx context.Context, row SecretRow) (bool, error) {
+		// a row read again after a concurrent change may have been encrypted with the current algorithm already
+		if !s.isOutdated(row.Payloads, algorithm) {
+			return false, nil
+		}
+
+		for field, payload := range row.Payloads {
+			if len(payload) == 0 {
+				continue
+			}
+
+			decrypted, err := s.secretsService.Decrypt(ctx, payload)
+			if err != nil {
+				return false, err
+			}
+
+			row.Payloads[field], err = s.secretsService.Encrypt(ctx, decrypted, secrets.WithoutScope())
+			if err != nil {
+				return false, err
+			}
+		}
+		return true, nil
+	})
+}
+
+// payloadAlgorithm returns the algorithm of a payload encrypted by the SecretsService,
+// with or without envelope encryption, or an empty string if it can't be derived.
+func payloadAlgorithm(payload []byte) string {
+	if len(payload) > 0 && payload[0] == keyIdDelimiter {
+		endOfKey := bytes.IndexByte(payload[1:], keyIdDelimiter)
+		if endOfKey == -1 {
+			return ""
+		}
+		payload = payload[endOfKey+2:]
+	}
+
+	algorithm, _, err := encryption.DeriveAlgorithm(payload)
+	if err != nil {
+		return ""
+	}
+	if algorithm == "" {
+		return encryption.AesCfb
+	}
+	return algorithm
+}
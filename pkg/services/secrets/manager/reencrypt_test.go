@@ -0,0 +1,257 @@
+package manager
+
+import (
+	"context"
+	"crypto/md5"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/infra/db"
+	"github.com/grafana/grafana/pkg/services/encryption"
+	"github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+	"github.com/grafana/grafana/pkg/services/secrets"
+	"github.com/grafana/grafana/pkg/services/secrets/database"
+)
+
+func TestReEncryptionService(t *testing.T) {
+	testDB := db.InitTestDB(t)
+	store := database.ProvideSecretsStore(testDB)
+	svc := SetupTestService(t, store)
+	ctx := context.Background()
+
+	setAlgorithm := func(algorithm string) {
+		svc.cfg.Raw.Section("security.encryption").Key("algorithm").SetValue(algorithm)
+	}
+
+	type pluginSetting struct {
+		Id             int64
+		OrgId          int64
+		PluginId       string
+		Enabled        bool
+		Pinned         bool
+		SecureJsonData map[string][]byte
+		Created        time.Time
+		Updated        time.Time
+	}
+
+	setAlgorithm(encryption.AesCfb)
+	for _, pluginID := range []string{"plugin-a", "plugin-b", "plugin-c"} {
+		encrypted, err := svc.EncryptJsonData(ctx, map[string]string{"token": pluginID + "-secret"}, secrets.WithoutScope())
+		require.NoError(t, err)
+		require.NoError(t, testDB.WithDbSession(ctx, func(sess *db.Session) error {
+			_, err := sess.Table("plugin_setting").Insert(&pluginSetting{
+				OrgId:          1,
+				PluginId:       pluginID,
+				SecureJsonData: encrypted,
+				Created:        time.Now(),
+				Updated:        time.Now(),
+			})
+			return err
+		}))
+	}
+
+	encrypt := func(value string) []byte {
+		encrypted, err := svc.Encrypt(ctx, []byte(value), secrets.WithoutScope())
+		require.NoError(t, err)
+		return encrypted
+	}
+
+	require.NoError(t, testDB.WithDbSession(ctx, func(sess *db.Session) error {
+		_, err := sess.Exec("INSERT INTO secrets (org_id, namespace, type, value, created, updated) VALUES (?, ?, ?, ?, ?, ?)",
+			1, "namespace", "type", base64.RawStdEncoding.EncodeToString(encrypt("kv-secret")), time.Now(), time.Now())
+		if err != nil {
+			return err
+		}
+
+		amConfig := func(secret string) string {
+			return fmt.Sprintf(`{"alertmanager_config": {"route": {"receiver": "email"}, "receivers": [{"name": "email",
+				"grafana_managed_receiver_configs": [{"uid": "email", "name": "email", "type": "email", "settings": {},
+				"secureSettings": {"password": %q}}]}]}}`, base64.StdEncoding.EncodeToString(encrypt(secret)))
+		}
+
+		// the history holds a previous configuration and a copy of the current one
+		current, previous := amConfig("receiver-secret"), amConfig("previous-receiver-secret")
+		for _, cfg := range []struct {
+			table  string
+			config string
+		}{{"alert_configuration_history", previous}, {"alert_configuration_history", current}, {"alert_configuration", current}} {
+			_, err = sess.Exec("INSERT INTO "+cfg.table+" (alertmanager_configuration, configuration_version, created_at, `default`, org_id, configuration_hash) VALUES (?, ?, ?, ?, ?, ?)",
+				cfg.config, "v1", time.Now().Unix(), false, 1, fmt.Sprintf("%x", md5.Sum([]byte(cfg.config))))
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	}))
+
+	kvSecret := func() []byte {
+		var value string
+		require.NoError(t, testDB.WithDbSession(ctx, func(sess *db.Session) error {
+			_, err := sess.SQL("SELECT value FROM secrets").Get(&value)
+			return err
+		}))
+		decoded, err := base64.RawStdEncoding.DecodeString(value)
+		require.NoError(t, err)
+		return decoded
+	}
+
+	receiverSecret := func(table string, id int64) ([]byte, string) {
+		var result struct {
+			AlertmanagerConfiguration string
+			ConfigurationHash         string
+		}
+		require.NoError(t, testDB.WithDbSession(ctx, func(sess *db.Session) error {
+			_, err := sess.Table(table).Cols("alertmanager_configuration", "configuration_hash").Where("id = ?", id).Get(&result)
+			return err
+		}))
+		assert.Equal(t, fmt.Sprintf("%x", md5.Sum([]byte(result.AlertmanagerConfiguration))), result.ConfigurationHash)
+
+		cfg := definitions.PostableUserConfig{}
+		require.NoError(t, json.Unmarshal([]byte(result.AlertmanagerConfiguration), &cfg))
+		decoded, err := base64.StdEncoding.DecodeString(cfg.AlertmanagerConfig.Receivers[0].GrafanaManagedReceivers[0].SecureSettings["password"])
+		require.NoError(t, err)
+		return decoded, result.ConfigurationHash
+	}
+
+	settings := func() []pluginSetting {
+		var rows []pluginSetting
+		require.NoError(t, testDB.WithDbSession(ctx, func(sess *db.Session) error {
+			return sess.Table("plugin_setting").Asc("id").Find(&rows)
+		}))
+		return rows
+	}
+
+	for _, row := range settings() {
+		assert.Equal(t, encryption.AesCfb, payloadAlgorithm(row.SecureJsonData["token"]))
+	}
+	assert.Equal(t, encryption.AesCfb, payloadAlgorithm(kvSecret()))
+	for _, id := range []int64{1, 2} {
+		secret, _ := receiverSecret("alert_configuration_history", id)
+		assert.Equal(t, encryption.AesCfb, payloadAlgorithm(secret))
+	}
+	receiver, _ := receiverSecret("alert_configuration", 1)
+	assert.Equal(t, encryption.AesCfb, payloadAlgorithm(receiver))
+
+	setAlgorithm(encryption.AesGcm)
+	reEncryption := ProvideReEncryptionService(svc.cfg, svc, testDB)
+	reEncryption.batchSize = 2
+
+	remaining, err := reEncryption.ReEncrypt(ctx)
+	require.NoError(t, err)
+	assert.Zero(t, remaining)
+
+	rows := settings()
+	require.Len(t, rows, 3)
+	for _, row := range rows {
+		assert.Equal(t, encryption.AesGcm, payloadAlgorithm(row.SecureJsonData["token"]))
+
+		decrypted, err := svc.DecryptJsonData(ctx, row.SecureJsonData)
+		require.NoError(t, err)
+		assert.Equal(t, row.PluginId+"-secret", decrypted["token"])
+	}
+
+	receiver, currentHash := receiverSecret("alert_configuration", 1)
+	previousReceiver, _ := receiverSecret("alert_configuration_history", 1)
+	// the history entry of the current configuration keeps the hash of the current configuration
+	historyReceiver, historyHash := receiverSecret("alert_configuration_history", 2)
+	assert.Equal(t, currentHash, historyHash)
+	assert.Equal(t, receiver, historyReceiver)
+
+	for value, payload := range map[string][]byte{"kv-secret": kvSecret(), "receiver-secret": receiver, "previous-receiver-secret": previousReceiver} {
+		assert.Equal(t, encryption.AesGcm, payloadAlgorithm(payload))
+		decrypted, err := svc.Decrypt(ctx, payload)
+		require.NoError(t, err)
+		assert.Equal(t, value, string(decrypted))
+	}
+
+	dataKeys, err := store.GetAllDataKeys(ctx)
+	require.NoError(t, err)
+	require.NotEmpty(t, dataKeys)
+	for _, dataKey := range dataKeys {
+		assert.Equal(t, encryption.AesGcm, payloadAlgorithm(dataKey.EncryptedData))
+	}
+
+	t.Run("secrets already encrypted with the current algorithm are left untouched", func(t *testing.T) {
+		before := settings()
+
+		remaining, err := reEncryption.ReEncrypt(ctx)
+		require.NoError(t, err)
+		assert.Zero(t, remaining)
+		assert.Equal(t, before, settings())
+	})
+
+	t.Run("is disabled by default", func(t *testing.T) {
+		assert.True(t, reEncryption.IsDisabled())
+	})
+}
+
+// changingTarget changes the first row it reads, as a user saving it would, before it is written back.
+type changingTarget struct {
+	SecretTarget
+	change func(ctx context.Context, id int64)
+}
+
+func (t *changingTarget) Batch(ctx context.Context, sqlStore db.DB, afterID int64, limit int) ([]SecretRow, error) {
+	rows, err := t.SecretTarget.Batch(ctx, sqlStore, afterID, limit)
+	if err == nil && len(rows) > 0 && t.change != nil {
+		t.change(ctx, rows[0].ID)
+		t.change = nil
+	}
+	return rows, err
+}
+
+func TestReEncryptionServiceConcurrentChange(t *testing.T) {
+	testDB := db.InitTestDB(t)
+	store := database.ProvideSecretsStore(testDB)
+	svc := SetupTestService(t, store)
+	ctx := context.Background()
+
+	setAlgorithm := func(algorithm string) {
+		svc.cfg.Raw.Section("security.encryption").Key("algorithm").SetValue(algorithm)
+	}
+	encrypt := func(value string) string {
+		encrypted, err := svc.Encrypt(ctx, []byte(value), secrets.WithoutScope())
+		require.NoError(t, err)
+		return base64.RawStdEncoding.EncodeToString(encrypted)
+	}
+
+	setAlgorithm(encryption.AesCfb)
+	require.NoError(t, testDB.WithDbSession(ctx, func(sess *db.Session) error {
+		_, err := sess.Exec("INSERT INTO secrets (org_id, namespace, type, value, created, updated) VALUES (?, ?, ?, ?, ?, ?)",
+			1, "namespace", "type", encrypt("kv-secret"), time.Now(), time.Now())
+		return err
+	}))
+
+	setAlgorithm(encryption.AesGcm)
+	reEncryption := ProvideReEncryptionService(svc.cfg, svc, testDB)
+	reEncryption.targets = []SecretTarget{&changingTarget{
+		SecretTarget: kvStoreTarget{},
+		change: func(ctx context.Context, id int64) {
+			require.NoError(t, testDB.WithDbSession(ctx, func(sess *db.Session) error {
+				_, err := sess.Exec("UPDATE secrets SET value = ? WHERE id = ?", encrypt("changed-kv-secret"), id)
+				return err
+			}))
+		},
+	}}
+
+	remaining, err := reEncryption.ReEncrypt(ctx)
+	require.NoError(t, err)
+	assert.Zero(t, remaining)
+
+	var value string
+	require.NoError(t, testDB.WithDbSession(ctx, func(sess *db.Session) error {
+		_, err := sess.SQL("SELECT value FROM secrets").Get(&value)
+		return err
+	}))
+	decoded, err := base64.RawStdEncoding.DecodeString(value)
+	require.NoError(t, err)
+	decrypted, err := svc.Decrypt(ctx, decoded)
+	require.NoError(t, err)
+	assert.Equal(t, "changed-kv-secret", string(decrypted))
+}
@@ -0,0 +1,313 @@
+package manager
+
+import (
+	"context"
+	"crypto/md5"
+	"database/sql"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/grafana/grafana/pkg/infra/db"
+	"github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+)
+
+// SecretRow is a database row holding one or more encrypted payloads.
+type SecretRow struct {
+	ID int64
+	// Payloads are the encrypted payloads of the row, keyed by field.
+	Payloads map[string][]byte
+	// raw is the target specific content of the row, used to write the payloads back.
+	raw any
+}
+
+// SecretTarget is a set of database rows with encrypted payloads, like the secure JSON data
+// of the data sources, that are encrypted again when the encryption settings change.
+type SecretTarget interface {
+	Name() string
+	// Batch returns up to limit rows with an id greater than afterID, ordered by id.
+	Batch(ctx context.Context, sqlStore db.DB, afterID int64, limit int) ([]SecretRow, error)
+	// Update writes the payloads of r back to the database. It returns ErrSecretRowChanged
+	// if the row was changed since it was read.
+	Update(ctx context.Context, sqlStore db.DB, r SecretRow) error
+}
+
+// ErrSecretRowChanged is returned when a row was changed, for example by a user saving a data
+// source, between the time it was read and the time its payloads were written back.
+var ErrSecretRowChanged = errors.New("secret row changed since it was read")
+
+// maxSecretRowAttempts is the number of times a row changed concurrently is read and rewritten.
+const maxSecretRowAttempts = 3
+
+// SecretTargets returns the targets holding the secrets encrypted by the SecretsService: the
+// secure JSON data of data sources and plugin settings, the secrets kvstore and the secure
+// settings of the alerting receivers, including the ones of previous alerting configurations.
+func SecretTargets() []SecretTarget {
+	return []SecretTarget{
+		secureJSONTarget{table: "data_source"},
+		secureJSONTarget{table: "plugin_setting"},
+		kvStoreTarget{},
+		alertConfigurationTarget{table: "alert_configuration"},
+		alertConfigurationTarget{table: "alert_configuration_history"},
+	}
+}
+
+// WalkSecretRows calls fn with the rows of t that have an id greater than afterID, batch by batch,
+// until all rows have been walked or fn returns an error.
+func WalkSecretRows(ctx context.Context, sqlStore db.DB, t SecretTarget, afterID int64, batchSize int, fn func(rows []SecretRow) error) error {
+	for {
+		rows, err := t.Batch(ctx, sqlStore, afterID, batchSize)
+		if err != nil {
+			return err
+		}
+		if err := fn(rows); err != nil {
+			return err
+		}
+		if len(rows) < batchSize {
+			return nil
+		}
+		afterID = rows[len(rows)-1].ID
+	}
+}
+
+// RewriteSecretRow calls rewrite with r and writes its payloads back to the database in a transaction, unless
+// rewrite returns false. If the row was changed since it was read, it is read again and rewritten from its new
+// payloads, so that the change is not overwritten.
+func RewriteSecretRow(ctx context.Context, sqlStore db.DB, t SecretTarget, r SecretRow, rewrite func(ctx context.Context, r SecretRow) (bool, error)) error {
+	for attempt := 1; ; attempt++ {
+		err := sqlStore.InTransaction(ctx, func(ctx context.Context) error {
+			ok, err := rewrite(ctx, r)
+			if err != nil || !ok {
+				return err
+			}
+			return t.Update(ctx, sqlStore, r)
+		})
+		if !errors.Is(err, ErrSecretRowChanged) || attempt == maxSecretRowAttempts {
+			return err
+		}
+
+		rows, err := t.Batch(ctx, sqlStore, r.ID-1, 1)
+		if err != nil {
+			return err
+		}
+		if len(rows) == 0 || rows[0].ID != r.ID {
+			// the row has been deleted
+			return nil
+		}
+		r = rows[0]
+	}
+}
+
+// checkRowsAffected returns ErrSecretRowChanged if the update of a row didn't affect it.
+func checkRowsAffected(result sql.Result) error {
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return ErrSecretRowChanged
+	}
+	return nil
+}
+
+type secureJSONTarget struct {
+	table string
+}
+
+func (t secureJSONTarget) Name() string { return t.table }
+
+func (t secureJSONTarget) Batch(ctx context.Context, sqlStore db.DB, afterID int64, limit int) ([]SecretRow, error) {
+	var results []struct {
+		Id             int64
+		SecureJsonData string
+	}
+
+	if err := sqlStore.WithDbSession(ctx, func(sess *db.Session) error {
+		return sess.Table(t.table).Cols("id", "secure_json_data").
+			Where("id > ?", afterID).Asc("id").Limit(limit).Find(&results)
+	}); err != nil {
+		return nil, err
+	}
+
+	rows := make([]SecretRow, 0, len(results))
+	for _, result := range results {
+		var payloads map[string][]byte
+		if result.SecureJsonData != "" {
+			if err := json.Unmarshal([]byte(result.SecureJsonData), &payloads); err != nil {
+				return nil, fmt.Errorf("failed to load secure json data %d: %w", result.Id, err)
+			}
+		}
+		// the secure json data as read is kept to only write the row back if it hasn't changed since
+		rows = append(rows, SecretRow{ID: result.Id, Payloads: payloads, raw: result.SecureJsonData})
+	}
+	return rows, nil
+}
+
+func (t secureJSONTarget) Update(ctx context.Context, sqlStore db.DB, r SecretRow) error {
+	previous, ok := r.raw.(string)
+	if !ok {
+		return fmt.Errorf("unexpected secure json data type %T", r.raw)
+	}
+
+	marshalled, err := json.Marshal(r.Payloads)
+	if err != nil {
+		return err
+	}
+
+	return sqlStore.WithDbSession(ctx, func(sess *db.Session) error {
+		result, err := sess.Exec("UPDATE "+t.table+" SET secure_json_data = ?, updated = ? WHERE id = ? AND secure_json_data = ?",
+			string(marshalled), time.Now(), r.ID, previous)
+		if err != nil {
+			return err
+		}
+		return checkRowsAffected(result)
+	})
+}
+
+// kvStoreTarget holds the values of the secrets kvstore, stored base64 encoded in the secrets table.
+type kvStoreTarget struct{}
+
+func (kvStoreTarget) Name() string { return "secrets" }
+
+func (kvStoreTarget) Batch(ctx context.Context, sqlStore db.DB, afterID int64, limit int) ([]SecretRow, error) {
+	var results []struct {
+		Id    int64
+		Value string
+	}
+
+	if err := sqlStore.WithDbSession(ctx, func(sess *db.Session) error {
+		return sess.Table("secrets").Cols("id", "value").
+			Where("id > ?", afterID).Asc("id").Limit(limit).Find(&results)
+	}); err != nil {
+		return nil, err
+	}
+
+	rows := make([]SecretRow, 0, len(results))
+	for _, result := range results {
+		decoded, err := base64.RawStdEncoding.DecodeString(result.Value)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode secret %d: %w", result.Id, err)
+		}
+		rows = append(rows, SecretRow{ID: result.Id, Payloads: map[string][]byte{"value": decoded}, raw: result.Value})
+	}
+	return rows, nil
+}
+
+func (kvStoreTarget) Update(ctx context.Context, sqlStore db.DB, r SecretRow) error {
+	previous, ok := r.raw.(string)
+	if !ok {
+		return fmt.Errorf("unexpected secret value type %T", r.raw)
+	}
+
+	return sqlStore.WithDbSession(ctx, func(sess *db.Session) error {
+		result, err := sess.Exec("UPDATE secrets SET value = ?, updated = ? WHERE id = ? AND value = ?",
+			base64.RawStdEncoding.EncodeToString(r.Payloads["value"]), time.Now(), r.ID, previous)
+		if err != nil {
+			return err
+		}
+		return checkRowsAffected(result)
+	})
+}
+
+// alertConfigurationTarget holds the secure settings of the Grafana managed receivers of the
+// alertmanager configurations stored in table, either the current configurations or their history.
+type alertConfigurationTarget struct {
+	table string
+}
+
+type alertConfigurationRow struct {
+	orgID  int64
+	hash   string
+	config *definitions.PostableUserConfig
+}
+
+func (t alertConfigurationTarget) Name() string { return t.table }
+
+func (t alertConfigurationTarget) Batch(ctx context.Context, sqlStore db.DB, afterID int64, limit int) ([]SecretRow, error) {
+	var results []struct {
+		Id                        int64
+		OrgId                     int64
+		AlertmanagerConfiguration string
+		ConfigurationHash         string
+	}
+
+	if err := sqlStore.WithDbSession(ctx, func(sess *db.Session) error {
+		return sess.Table(t.table).Cols("id", "org_id", "alertmanager_configuration", "configuration_hash").
+			Where("id > ?", afterID).Asc("id").Limit(limit).Find(&results)
+	}); err != nil {
+		return nil, err
+	}
+
+	rows := make([]SecretRow, 0, len(results))
+	for _, result := range results {
+		row := &alertConfigurationRow{orgID: result.OrgId, hash: result.ConfigurationHash, config: &definitions.PostableUserConfig{}}
+		if err := json.Unmarshal([]byte(result.AlertmanagerConfiguration), row.config); err != nil {
+			return nil, fmt.Errorf("failed to load alertmanager configuration %d: %w", result.Id, err)
+		}
+
+		payloads := map[string][]byte{}
+		for i, receiver := range row.config.AlertmanagerConfig.Receivers {
+			for j, gmr := range receiver.GrafanaManagedReceivers {
+				for k, v := range gmr.SecureSettings {
+					decoded, err := base64.StdEncoding.DecodeString(v)
+					if err != nil {
+						return nil, fmt.Errorf("failed to decode secure setting %q of receiver %q: %w", k, gmr.UID, err)
+					}
+					payloads[secureSettingField(i, j, k)] = decoded
+				}
+			}
+		}
+		rows = append(rows, SecretRow{ID: result.Id, Payloads: payloads, raw: row})
+	}
+	return rows, nil
+}
+
+// Update writes the configuration back together with its new hash, as the hash identifies the
+// configuration applied by the Alertmanager. The history entries of a current configuration are
+// updated with it, so the applied configuration can still be found in the history. The hash read
+// with the configuration guards against overwriting a configuration saved since.
+func (t alertConfigurationTarget) Update(ctx context.Context, sqlStore db.DB, r SecretRow) error {
+	row, ok := r.raw.(*alertConfigurationRow)
+	if !ok {
+		return fmt.Errorf("unexpected alertmanager configuration type %T", r.raw)
+	}
+
+	for i, receiver := range row.config.AlertmanagerConfig.Receivers {
+		for j, gmr := range receiver.GrafanaManagedReceivers {
+			for k := range gmr.SecureSettings {
+				if payload, ok := r.Payloads[secureSettingField(i, j, k)]; ok {
+					gmr.SecureSettings[k] = base64.StdEncoding.EncodeToString(payload)
+				}
+			}
+		}
+	}
+
+	marshalled, err := json.Marshal(row.config)
+	if err != nil {
+		return err
+	}
+	hash := fmt.Sprintf("%x", md5.Sum(marshalled))
+
+	return sqlStore.WithDbSession(ctx, func(sess *db.Session) error {
+		result, err := sess.Exec("UPDATE "+t.table+" SET alertmanager_configuration = ?, configuration_hash = ? WHERE id = ? AND configuration_hash = ?",
+			string(marshalled), hash, r.ID, row.hash)
+		if err != nil {
+			return err
+		}
+		if err := checkRowsAffected(result); err != nil {
+			return err
+		}
+		if t.table != "alert_configuration" {
+			return nil
+		}
+		_, err = sess.Exec("UPDATE alert_configuration_history SET alertmanager_configuration = ?, configuration_hash = ? WHERE org_id = ? AND configuration_hash = ?",
+			string(marshalled), hash, row.orgID, row.hash)
+		return err
+	})
+}
+
+func secureSettingField(receiver, integration int, key string) string {
+	return fmt.Sprintf("%d/%d/%s", receiver, integration, key)
+}
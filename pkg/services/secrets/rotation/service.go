@@ -0,0 +1,399 @@
+package rotation
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"sort"
+	"time"
+
+	"github.com/grafana/grafana/pkg/infra/db"
+	"github.com/grafana/grafana/pkg/infra/kvstore"
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/infra/serverlock"
+	"github.com/grafana/grafana/pkg/services/featuremgmt"
+	"github.com/grafana/grafana/pkg/services/secrets"
+	"github.com/grafana/grafana/pkg/services/secrets/manager"
+	"github.com/grafana/grafana/pkg/setting"
+)
+
+const (
+	kvNamespace = "secrets.rotation"
+	stateKey    = "state"
+
+	// leaderElectionName is the name of the lease held by the instance that rotates the data keys.
+	leaderElectionName = "secrets-data-keys-rotation-leader"
+
+	checkInterval = 10 * time.Minute
+
+	keyIdDelimiter = '#'
+)
+
+// Service rotates the data encryption keys at the interval configured in [security.encryption]
+// and re-wraps the secure fields encrypted with the rotated keys using the new ones.
+//
+// Only the instance elected leader rotates the keys. It renews its lease while re-wrapping and stops
+// when the lease is lost, so only one instance runs the job at a time however long it takes. The
+// progress is saved after each batch so an interrupted job is resumed where it stopped.
+type Service struct {
+	features       featuremgmt.FeatureToggles
+	secretsService *manager.SecretsService
+	secretsStore   secrets.Store
+	sqlStore       db.DB
+	serverLock     *serverlock.ServerLockService
+	kv             *kvstore.NamespacedKVStore
+	log            log.Logger
+
+	interval  time.Duration
+	batchSize int
+	targets   []manager.SecretTarget
+	now       func() time.Time
+}
+
+func ProvideService(
+	cfg *setting.Cfg,
+	features featuremgmt.FeatureToggles,
+	secretsService *manager.SecretsService,
+	secretsStore secrets.Store,
+	sqlStore db.DB,
+	serverLock *serverlock.ServerLockService,
+	kv kvstore.KVStore,
+) *Service {
+	section := cfg.SectionWithEnvOverrides("security.encryption")
+	return &Service{
+		features:       features,
+		secretsService: secretsService,
+		secretsStore:   secretsStore,
+		sqlStore:       sqlStore,
+		serverLock:     serverLock,
+		kv:             kvstore.WithNamespace(kv, 0, kvNamespace),
+		log:            log.New("secrets.rotation"),
+		interval:       section.Key("data_keys_rotation_interval").MustDuration(0),
+		batchSize:      section.Key("data_keys_rotation_batch_size").MustInt(100),
+		targets:        manager.SecretTargets(),
+		now:            time.Now,
+	}
+}
+
+// state is the rotation state shared by all instances.
+type state struct {
+	LastRotation time.Time `json:"lastRotation"`
+	Job          *job      `json:"job,omitempty"`
+}
+
+// job is a rotation whose secrets haven't all been re-wrapped yet.
+type job struct {
+	StartedAt time.Time `json:"startedAt"`
+	// RetiredKeys are the ids of the data keys that existed when the keys were rotated.
+	RetiredKeys []string `json:"retiredKeys"`
+	// Cursors are the ids of the last processed row of each target.
+	Cursors   map[string]int64 `json:"cursors"`
+	Completed []string         `json:"completed"`
+	Failed    int              `json:"failed"`
+}
+
+func (s *Service) IsDisabled() bool {
+	return s.interval <= 0 || s.features.IsEnabled(featuremgmt.FlagDisableEnvelopeEncryption)
+}
+
+// Run takes part in the election of the instance that rotates the data keys. The leader rotates the
+// data keys whenever the rotation interval has elapsed and resumes the re-wrap jobs that haven't been completed.
+func (s *Service) Run(ctx context.Context) error {
+	election, err := s.serverLock.NewLeaderElection(leaderElectionName, serverlock.LeaderElectionConfig{
+		OnStartedLeading: s.rotateWhileLeading,
+	})
+	if err != nil {
+		return err
+	}
+
+	if err := election.Run(ctx); err != nil && !errors.Is(err, context.Canceled) {
+		return err
+	}
+	return nil
+}
+
+// rotateWhileLeading checks whether the data keys are due for rotation until the context
+// is cancelled, when the leadership is lost or the service is stopped.
+func (s *Service) rotateWhileLeading(ctx context.Context) {
+	ticker := time.NewTicker(checkInterval)
+	defer ticker.Stop()
+
+	for {
+		if err := s.RotateIfDue(ctx); err != nil && !errors.Is(err, context.Canceled) {
+			s.log.Error("Data keys rotation failed", "error", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// RotateIfDue rotates the data keys if the rotation interval has elapsed since the last
+// rotation, and re-wraps the secrets encrypted with the rotated keys. It must only be called
+// by the leader, the re-wrap stops when ctx is cancelled and is resumed by the next call.
+func (s *Service) RotateIfDue(ctx context.Context) error {
+	st, err := s.loadState(ctx)
+	if err != nil {
+		return err
+	}
+	if !s.due(st) {
+		return nil
+	}
+
+	if st.Job == nil {
+		dataKeys, err := s.secretsStore.GetAllDataKeys(ctx)
+		if err != nil {
+			return err
+		}
+
+		retired := make([]string, 0, len(dataKeys))
+		for _, dataKey := range dataKeys {
+			retired = append(retired, dataKey.Id)
+		}
+
+		if err := s.secretsService.RotateDataKeys(ctx); err != nil {
+			return err
+		}
+
+		st.Job = &job{StartedAt: s.now(), RetiredKeys: retired, Cursors: map[string]int64{}}
+		if err := s.saveState(ctx, st); err != nil {
+			return err
+		}
+	} else {
+		s.log.Info("Resuming the re-wrap of secrets", "rotatedAt", st.Job.StartedAt, "cursors", st.Job.Cursors)
+	}
+
+	return s.reWrap(ctx, st)
+}
+
+func (s *Service) due(st state) bool {
+	return st.Job != nil || s.now().Sub(st.LastRotation) >= s.interval
+}
+
+// reWrap re-encrypts, target by target and batch by batch, the secrets encrypted with
+// a retired data key or with the secret key, saving the progress after each batch.
+func (s *Service) reWrap(ctx context.Context, st state) error {
+	j := st.Job
+	retired := make(map[string]bool, len(j.RetiredKeys))
+	for _, id := range j.RetiredKeys {
+		retired[id] = true
+	}
+
+	completed := make(map[string]bool, len(j.Completed))
+	for _, name := range j.Completed {
+		completed[name] = true
+	}
+
+	for _, t := range s.targets {
+		if completed[t.Name()] {
+			continue
+		}
+
+		err := manager.WalkSecretRows(ctx, s.sqlStore, t, j.Cursors[t.Name()], s.batchSize, func(rows []manager.SecretRow) error {
+			for _, r := range rows {
+				if err := s.reWrapRow(ctx, t, r, retired); err != nil {
+					if ctx.Err() != nil {
+						return errors.Join(ctx.Err(), s.saveState(context.Background(), st))
+					}
+					s.log.Warn("Could not re-wrap secret", "target", t.Name(), "id", r.ID, "error", err)
+					j.Failed++
+				}
+				j.Cursors[t.Name()] = r.ID
+			}
+			return s.saveState(ctx, st)
+		})
+		if err != nil {
+			return err
+		}
+
+		j.Completed = append(j.Completed, t.Name())
+		if err := s.saveState(ctx, st); err != nil {
+			return err
+		}
+	}
+
+	if j.Failed > 0 {
+		s.log.Warn("Secrets have been re-wrapped with errors", "failed", j.Failed)
+	} else {
+		s.log.Info("Secrets have been re-wrapped successfully")
+	}
+
+	st.LastRotation = j.StartedAt
+	st.Job = nil
+	return s.saveState(ctx, st)
+}
+
+func (s *Service) reWrapRow(ctx context.Context, t manager.SecretTarget, r manager.SecretRow, retired map[string]bool) error {
+	needsReWrap := func(payload []byte) bool {
+		if len(payload) == 0 {
+			return false
+		}
+		id, ok := payloadKeyID(payload)
+		return !ok || retired[id]
+	}
+
+	return manager.RewriteSecretRow(ctx, s.sqlStore, t, r, func(ctx context.Context, r manager.SecretRow) (bool, error) {
+		var outdated bool
+		for field, payload := range r.Payloads {
+			if !needsReWrap(payload) {
+				continue
+			}
+			outdated = true
+
+			decrypted, err := s.secretsService.Decrypt(ctx, payload)
+			if err != nil {
+				return false, err
+			}
+
+			r.Payloads[field], err = s.secretsService.Encrypt(ctx, decrypted, secrets.WithoutScope())
+			if err != nil {
+				return false, err
+			}
+		}
+		return outdated, nil
+	})
+}
+
+// DataKeyUsage is a data key and the number of payloads encrypted with it.
+type DataKeyUsage struct {
+	Id       string    `json:"id"`
+	Label    string    `json:"label"`
+	Scope    string    `json:"scope"`
+	Provider string    `json:"provider"`
+	Active   bool      `json:"active"`
+	Created  time.Time `json:"created"`
+	Payloads int       `json:"payloads"`
+}
+
+// UsageReport is the usage of the data keys by the secure fields re-wrapped on rotation.
+type UsageReport struct {
+	DataKeys []DataKeyUsage `json:"dataKeys"`
+	// LegacyPayloads is the number of payloads encrypted with the secret key rather than a data key.
+	LegacyPayloads int `json:"legacyPayloads"`
+	// UnknownKeyPayloads is the number of payloads encrypted with a data key that doesn't exist anymore.
+	UnknownKeyPayloads int `json:"unknownKeyPayloads"`
+
+	RotationInterval   string     `json:"rotationInterval"`
+	LastRotation       *time.Time `json:"lastRotation,omitempty"`
+	RotationInProgress bool       `json:"rotationInProgress"`
+	// PendingTargets are the targets whose secrets haven't all been re-wrapped by the rotation in progress.
+	PendingTargets []string `json:"pendingTargets,omitempty"`
+}
+
+// Usage counts the payloads encrypted with each data key.
+func (s *Service) Usage(ctx context.Context) (*UsageReport, error) {
+	dataKeys, err := s.secretsStore.GetAllDataKeys(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	st, err := s.loadState(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	counts := map[string]int{}
+	report := &UsageReport{DataKeys: make([]DataKeyUsage, 0, len(dataKeys))}
+	for _, t := range s.targets {
+		err := manager.WalkSecretRows(ctx, s.sqlStore, t, 0, s.batchSize, func(rows []manager.SecretRow) error {
+			for _, r := range rows {
+				for _, payload := range r.Payloads {
+					if len(payload) == 0 {
+						continue
+					}
+					if id, ok := payloadKeyID(payload); ok {
+						counts[id]++
+					} else {
+						report.LegacyPayloads++
+					}
+				}
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	for _, dataKey := range dataKeys {
+		report.DataKeys = append(report.DataKeys, DataKeyUsage{
+			Id:       dataKey.Id,
+			Label:    dataKey.Label,
+			Scope:    dataKey.Scope,
+			Provider: string(dataKey.Provider),
+			Active:   dataKey.Active,
+			Created:  dataKey.Created,
+			Payloads: counts[dataKey.Id],
+		})
+		delete(counts, dataKey.Id)
+	}
+	for _, count := range counts {
+		report.UnknownKeyPayloads += count
+	}
+	sort.Slice(report.DataKeys, func(i, j int) bool {
+		return report.DataKeys[i].Created.After(report.DataKeys[j].Created)
+	})
+
+	if s.interval > 0 {
+		report.RotationInterval = s.interval.String()
+	}
+	if !st.LastRotation.IsZero() {
+		report.LastRotation = &st.LastRotation
+	}
+	if st.Job != nil {
+		report.RotationInProgress = true
+		for _, t := range s.targets {
+			pending := true
+			for _, name := range st.Job.Completed {
+				pending = pending && name != t.Name()
+			}
+			if pending {
+				report.PendingTargets = append(report.PendingTargets, t.Name())
+			}
+		}
+	}
+
+	return report, nil
+}
+
+func (s *Service) loadState(ctx context.Context) (state, error) {
+	var st state
+	value, ok, err := s.kv.Get(ctx, stateKey)
+	if err != nil || !ok {
+		return st, err
+	}
+	err = json.Unmarshal([]byte(value), &st)
+	return st, err
+}
+
+func (s *Service) saveState(ctx context.Context, st state) error {
+	value, err := json.Marshal(st)
+	if err != nil {
+		return err
+	}
+	return s.kv.Set(ctx, stateKey, string(value))
+}
+
+// payloadKeyID returns the id of the data key a payload was encrypted with,
+// or false if it wasn't encrypted with envelope encryption.
+func payloadKeyID(payload []byte) (string, bool) {
+	if len(payload) == 0 || payload[0] != keyIdDelimiter {
+		return "", false
+	}
+
+	endOfKey := bytes.IndexByte(payload[1:], keyIdDelimiter)
+	if endOfKey == -1 {
+		return "", false
+	}
+
+	id, err := base64.RawStdEncoding.DecodeString(string(payload[1 : endOfKey+1]))
+	if err != nil {
+		return "", false
+	}
+	return string(id), true
+}
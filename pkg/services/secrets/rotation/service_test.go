@@ -0,0 +1,309 @@
+package rotation
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/infra/db"
+	"github.com/grafana/grafana/pkg/infra/kvstore"
+	"github.com/grafana/grafana/pkg/infra/serverlock"
+	"github.com/grafana/grafana/pkg/infra/tracing"
+	"github.com/grafana/grafana/pkg/services/featuremgmt"
+	"github.com/grafana/grafana/pkg/services/secrets"
+	"github.com/grafana/grafana/pkg/services/secrets/database"
+	"github.com/grafana/grafana/pkg/services/secrets/manager"
+	"github.com/grafana/grafana/pkg/setting"
+)
+
+type pluginSetting struct {
+	Id             int64
+	OrgId          int64
+	PluginId       string
+	Enabled        bool
+	Pinned         bool
+	SecureJsonData map[string][]byte
+	Created        time.Time
+	Updated        time.Time
+}
+
+// failingTarget fails once its batch has been called failAfter times.
+type failingTarget struct {
+	manager.SecretTarget
+	calls     *int
+	failAfter int
+}
+
+func (t failingTarget) Batch(ctx context.Context, sqlStore db.DB, afterID int64, limit int) ([]manager.SecretRow, error) {
+	*t.calls++
+	if *t.calls > t.failAfter {
+		return nil, errors.New("database unavailable")
+	}
+	return t.SecretTarget.Batch(ctx, sqlStore, afterID, limit)
+}
+
+// leadershipLosingTarget cancels the context of the leader, as losing the leadership does, once
+// its batch has been called loseAfter times.
+type leadershipLosingTarget struct {
+	manager.SecretTarget
+	calls     *int
+	loseAfter int
+	cancel    context.CancelFunc
+}
+
+func (t leadershipLosingTarget) Batch(ctx context.Context, sqlStore db.DB, afterID int64, limit int) ([]manager.SecretRow, error) {
+	*t.calls++
+	if *t.calls > t.loseAfter {
+		t.cancel()
+		return nil, ctx.Err()
+	}
+	return t.SecretTarget.Batch(ctx, sqlStore, afterID, limit)
+}
+
+// countingTarget counts the batches read from it.
+type countingTarget struct {
+	manager.SecretTarget
+	calls *atomic.Int64
+}
+
+func (t countingTarget) Batch(ctx context.Context, sqlStore db.DB, afterID int64, limit int) ([]manager.SecretRow, error) {
+	t.calls.Add(1)
+	return t.SecretTarget.Batch(ctx, sqlStore, afterID, limit)
+}
+
+func TestService(t *testing.T) {
+	testDB := db.InitTestDB(t)
+	store := database.ProvideSecretsStore(testDB)
+	secretsService := manager.SetupTestService(t, store)
+	ctx := context.Background()
+
+	cfg := setting.NewCfg()
+	cfg.Raw.Section("security.encryption").Key("data_keys_rotation_interval").SetValue("24h")
+	cfg.Raw.Section("security.encryption").Key("data_keys_rotation_batch_size").SetValue("2")
+
+	svc := ProvideService(cfg, featuremgmt.WithFeatures(), secretsService, store, testDB,
+		serverlock.ProvideService(testDB, tracing.InitializeTracerForTest()), kvstore.ProvideService(testDB))
+	now := time.Now()
+	svc.now = func() time.Time { return now }
+
+	for _, pluginID := range []string{"plugin-a", "plugin-b", "plugin-c"} {
+		encrypted, err := secretsService.EncryptJsonData(ctx, map[string]string{"token": pluginID + "-secret"}, secrets.WithoutScope())
+		require.NoError(t, err)
+		require.NoError(t, testDB.WithDbSession(ctx, func(sess *db.Session) error {
+			_, err := sess.Table("plugin_setting").Insert(&pluginSetting{
+				OrgId:          1,
+				PluginId:       pluginID,
+				SecureJsonData: encrypted,
+				Created:        time.Now(),
+				Updated:        time.Now(),
+			})
+			return err
+		}))
+	}
+
+	settings := func() []pluginSetting {
+		var rows []pluginSetting
+		require.NoError(t, testDB.WithDbSession(ctx, func(sess *db.Session) error {
+			return sess.Table("plugin_setting").Asc("id").Find(&rows)
+		}))
+		return rows
+	}
+
+	activeKey := func(report *UsageReport) DataKeyUsage {
+		for _, dataKey := range report.DataKeys {
+			if dataKey.Active {
+				return dataKey
+			}
+		}
+		t.Fatal("no active data key")
+		return DataKeyUsage{}
+	}
+
+	report, err := svc.Usage(ctx)
+	require.NoError(t, err)
+	require.Len(t, report.DataKeys, 1)
+	firstKey := report.DataKeys[0]
+	assert.Equal(t, 3, firstKey.Payloads)
+	assert.Nil(t, report.LastRotation)
+
+	t.Run("rotates the data keys and re-wraps the secrets", func(t *testing.T) {
+		require.NoError(t, svc.RotateIfDue(ctx))
+
+		report, err := svc.Usage(ctx)
+		require.NoError(t, err)
+		require.Len(t, report.DataKeys, 2)
+		assert.Equal(t, 3, activeKey(report).Payloads)
+		assert.NotEqual(t, firstKey.Id, activeKey(report).Id)
+		require.NotNil(t, report.LastRotation)
+		assert.False(t, report.RotationInProgress)
+
+		for _, setting := range settings() {
+			decrypted, err := secretsService.DecryptJsonData(ctx, setting.SecureJsonData)
+			require.NoError(t, err)
+			assert.Equal(t, setting.PluginId+"-secret", decrypted["token"])
+		}
+	})
+
+	t.Run("doesn't rotate the data keys before the interval has elapsed", func(t *testing.T) {
+		before := settings()
+		now = now.Add(time.Hour)
+		require.NoError(t, svc.RotateIfDue(ctx))
+		assert.Equal(t, before, settings())
+	})
+
+	t.Run("resumes an interrupted re-wrap", func(t *testing.T) {
+		before := settings()
+		now = now.Add(48 * time.Hour)
+
+		var pluginSettings manager.SecretTarget
+		for _, target := range svc.targets {
+			if target.Name() == "plugin_setting" {
+				pluginSettings = target
+			}
+		}
+		require.NotNil(t, pluginSettings)
+
+		calls := 0
+		targets := svc.targets
+		t.Cleanup(func() { svc.targets = targets })
+		svc.targets = []manager.SecretTarget{failingTarget{SecretTarget: pluginSettings, calls: &calls, failAfter: 1}}
+
+		require.Error(t, svc.RotateIfDue(ctx))
+
+		svc.targets = []manager.SecretTarget{pluginSettings}
+		report, err := svc.Usage(ctx)
+		require.NoError(t, err)
+		assert.True(t, report.RotationInProgress)
+		assert.Equal(t, []string{"plugin_setting"}, report.PendingTargets)
+
+		// the first batch has been re-wrapped, the last setting is still encrypted with the retired key
+		after := settings()
+		assert.NotEqual(t, before[0].SecureJsonData, after[0].SecureJsonData)
+		assert.NotEqual(t, before[1].SecureJsonData, after[1].SecureJsonData)
+		assert.Equal(t, before[2].SecureJsonData, after[2].SecureJsonData)
+
+		require.NoError(t, svc.RotateIfDue(ctx))
+
+		resumed := settings()
+		assert.Equal(t, after[:2], resumed[:2])
+		assert.NotEqual(t, after[2].SecureJsonData, resumed[2].SecureJsonData)
+
+		report, err = svc.Usage(ctx)
+		require.NoError(t, err)
+		assert.False(t, report.RotationInProgress)
+		assert.Equal(t, 3, activeKey(report).Payloads)
+		assert.True(t, now.Equal(*report.LastRotation))
+	})
+
+	t.Run("stops the re-wrap when the leadership is lost and resumes it on the leader", func(t *testing.T) {
+		now = now.Add(48 * time.Hour)
+
+		var pluginSettings manager.SecretTarget
+		for _, target := range svc.targets {
+			if target.Name() == "plugin_setting" {
+				pluginSettings = target
+			}
+		}
+		require.NotNil(t, pluginSettings)
+
+		calls := 0
+		leaderCtx, cancel := context.WithCancel(ctx)
+		defer cancel()
+		targets := svc.targets
+		t.Cleanup(func() { svc.targets = targets })
+		svc.targets = []manager.SecretTarget{leadershipLosingTarget{SecretTarget: pluginSettings, calls: &calls, loseAfter: 1, cancel: cancel}}
+
+		require.ErrorIs(t, svc.RotateIfDue(leaderCtx), context.Canceled)
+
+		svc.targets = []manager.SecretTarget{pluginSettings}
+		report, err := svc.Usage(ctx)
+		require.NoError(t, err)
+		assert.True(t, report.RotationInProgress)
+
+		runCtx, stop := context.WithCancel(ctx)
+		done := make(chan error)
+		go func() {
+			done <- svc.Run(runCtx)
+		}()
+
+		require.Eventually(t, func() bool {
+			report, err := svc.Usage(ctx)
+			return err == nil && !report.RotationInProgress
+		}, 5*time.Second, 50*time.Millisecond)
+		stop()
+		require.NoError(t, <-done)
+
+		report, err = svc.Usage(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, 3, activeKey(report).Payloads)
+		assert.True(t, now.Equal(*report.LastRotation))
+	})
+
+	t.Run("is disabled without a rotation interval", func(t *testing.T) {
+		assert.False(t, svc.IsDisabled())
+		assert.True(t, ProvideService(setting.NewCfg(), featuremgmt.WithFeatures(), secretsService, store, testDB, nil, kvstore.NewFakeKVStore()).IsDisabled())
+	})
+}
+
+func TestIntegrationServiceLeaderElection(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+
+	testDB := db.InitTestDB(t)
+	store := database.ProvideSecretsStore(testDB)
+	secretsService := manager.SetupTestService(t, store)
+	ctx := context.Background()
+
+	encrypted, err := secretsService.Encrypt(ctx, []byte("secret"), secrets.WithoutScope())
+	require.NoError(t, err)
+	require.NoError(t, testDB.WithDbSession(ctx, func(sess *db.Session) error {
+		_, err := sess.Exec("INSERT INTO secrets (org_id, namespace, type, value, created, updated) VALUES (?, ?, ?, ?, ?, ?)",
+			1, "namespace", "type", base64.RawStdEncoding.EncodeToString(encrypted), time.Now(), time.Now())
+		return err
+	}))
+
+	cfg := setting.NewCfg()
+	cfg.Raw.Section("security.encryption").Key("data_keys_rotation_interval").SetValue("24h")
+
+	newService := func() *Service {
+		return ProvideService(cfg, featuremgmt.WithFeatures(), secretsService, store, testDB,
+			serverlock.ProvideService(testDB, tracing.InitializeTracerForTest()), kvstore.ProvideService(testDB))
+	}
+
+	// the instances share the database and run the rotation at the same time
+	runCtx, stop := context.WithCancel(ctx)
+	done := make(chan error, 2)
+	calls := make([]*atomic.Int64, 2)
+	for i := range calls {
+		calls[i] = &atomic.Int64{}
+		svc := newService()
+		for j, target := range svc.targets {
+			svc.targets[j] = countingTarget{SecretTarget: target, calls: calls[i]}
+		}
+		go func() {
+			done <- svc.Run(runCtx)
+		}()
+	}
+
+	observer := newService()
+	require.Eventually(t, func() bool {
+		report, err := observer.Usage(ctx)
+		return err == nil && report.LastRotation != nil && !report.RotationInProgress
+	}, 5*time.Second, 50*time.Millisecond)
+	stop()
+	require.NoError(t, <-done)
+	require.NoError(t, <-done)
+
+	// only the leader re-wrapped the secrets
+	assert.True(t, (calls[0].Load() == 0) != (calls[1].Load() == 0), "calls: %d, %d", calls[0].Load(), calls[1].Load())
+	dataKeys, err := store.GetAllDataKeys(ctx)
+	require.NoError(t, err)
+	assert.Len(t, dataKeys, 2)
+}
@@ -19,4 +19,21 @@ func addCacheMigration(mg *migrator.Migrator) {
 	mg.AddMigration("create cache_data table", migrator.NewAddTableMigration(cacheDataV1))
 
 	mg.AddMigration("add unique index cache_data.cache_key", migrator.NewAddIndexMigration(cacheDataV1, cacheDataV1.Indices[0]))
+
+	cacheInvalidationV1 := migrator.Table{
+		Name: "cache_invalidation",
+		Columns: []*migrator.Column{
+			{Name: "id", Type: migrator.DB_BigInt, IsPrimaryKey: true, IsAutoIncrement: true},
+			{Name: "cache_key", Type: migrator.DB_NVarchar, Length: 168, Nullable: false},
+			{Name: "source", Type: migrator.DB_NVarchar, Length: 40, Nullable: false},
+			{Name: "created_at", Type: migrator.DB_BigInt, Nullable: false},
+		},
+		Indices: []*migrator.Index{
+			{Cols: []string{"created_at"}},
+		},
+	}
+
+	mg.AddMigration("create cache_invalidation table", migrator.NewAddTableMigration(cacheInvalidationV1))
+
+	mg.AddMigration("add index cache_invalidation.created_at", migrator.NewAddIndexMigration(cacheInvalidationV1, cacheInvalidationV1.Indices[0]))
 }
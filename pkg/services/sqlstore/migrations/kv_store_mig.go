@@ -24,4 +24,12 @@ func addKVStoreMigrations(mg *Migrator) {
 	mg.AddMigration("create kv_store table v1", NewAddTableMigration(kvStoreV1))
 
 	mg.AddMigration("add index kv_store.org_id-namespace-key", NewAddIndexMigration(kvStoreV1, kvStoreV1.Indices[0]))
+
+	mg.AddMigration("add version column to kv_store", NewAddColumnMigration(kvStoreV1, &Column{
+		Name: "version", Type: DB_BigInt, Nullable: false, Default: "1",
+	}))
+
+	mg.AddMigration("add expires_at column to kv_store", NewAddColumnMigration(kvStoreV1, &Column{
+		Name: "expires_at", Type: DB_BigInt, Nullable: false, Default: "0",
+	}))
 }
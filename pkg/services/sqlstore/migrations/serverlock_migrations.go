@@ -19,4 +19,8 @@ func addServerlockMigrations(mg *migrator.Migrator) {
 	mg.AddMigration("create server_lock table", migrator.NewAddTableMigration(serverLock))
 
 	mg.AddMigration("add index server_lock.operation_uid", migrator.NewAddIndexMigration(serverLock, serverLock.Indices[0]))
+
+	mg.AddMigration("add holder column to server_lock", migrator.NewAddColumnMigration(serverLock, &migrator.Column{
+		Name: "holder", Type: migrator.DB_NVarchar, Length: 190, Nullable: false, Default: "''",
+	}))
 }
@@ -1,12 +1,15 @@
 package supportbundlesimpl
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
 	"fmt"
 	"runtime"
 	"time"
 
+	gokitlog "github.com/go-kit/log"
+
 	"github.com/grafana/grafana/pkg/infra/log"
 	"github.com/grafana/grafana/pkg/plugins"
 	"github.com/grafana/grafana/pkg/services/pluginsintegration/pluginsettings"
@@ -189,3 +192,36 @@ func pluginInfoCollector(pluginStore pluginstore.Store, pluginSettings pluginset
 		},
 	}
 }
+
+func recentErrorsCollector() supportbundles.Collector {
+	return supportbundles.Collector{
+		UID:               "recent-errors",
+		DisplayName:       "Recent error logs",
+		Description:       "Error log records kept in memory by the Grafana instance",
+		IncludedByDefault: false,
+		Default:           true,
+		Fn: func(ctx context.Context) (*supportbundles.SupportItem, error) {
+			bWriter := bytes.NewBuffer(nil)
+			logger := gokitlog.NewLogfmtLogger(bWriter)
+
+			for _, record := range log.RecentErrors() {
+				redacted := make([]any, len(record))
+				copy(redacted, record)
+				for i := 1; i < len(redacted); i += 2 {
+					key, _ := redacted[i-1].(string)
+					value, _ := redacted[i].(string)
+					redacted[i] = setting.RedactedValue(key, value)
+				}
+
+				if err := logger.Log(redacted...); err != nil {
+					return nil, err
+				}
+			}
+
+			return &supportbundles.SupportItem{
+				Filename:  "recent-errors.log",
+				FileBytes: bWriter.Bytes(),
+			}, nil
+		},
+	}
+}
@@ -0,0 +1,111 @@
+package supportbundlesimpl
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/plugins"
+	ac "github.com/grafana/grafana/pkg/services/accesscontrol"
+	"github.com/grafana/grafana/pkg/services/datasources"
+	"github.com/grafana/grafana/pkg/services/org"
+	"github.com/grafana/grafana/pkg/services/pluginsintegration/plugincontext"
+	"github.com/grafana/grafana/pkg/services/pluginsintegration/pluginstore"
+	"github.com/grafana/grafana/pkg/services/supportbundles"
+	"github.com/grafana/grafana/pkg/setting"
+)
+
+// dataSourceHealthCheckTimeout bounds the time spent checking a single data source
+// so that an unreachable one doesn't use up the whole bundle creation timeout.
+const dataSourceHealthCheckTimeout = 10 * time.Second
+
+func dataSourceHealthCollector(dataSourceService datasources.DataSourceService, pluginStore pluginstore.Store,
+	pluginContextProvider *plugincontext.Provider, pluginClient plugins.Client, logger log.Logger) supportbundles.Collector {
+	collectorFn := func(ctx context.Context) (*supportbundles.SupportItem, error) {
+		type dataSourceHealth struct {
+			UID     string `json:"uid"`
+			Name    string `json:"name"`
+			Type    string `json:"type"`
+			OrgID   int64  `json:"orgId"`
+			URL     string `json:"url,omitempty"`
+			Status  string `json:"status"`
+			Message string `json:"message,omitempty"`
+		}
+
+		dataSources, err := dataSourceService.GetAllDataSources(ctx, &datasources.GetAllDataSourcesQuery{})
+		if err != nil {
+			return nil, err
+		}
+
+		results := make([]dataSourceHealth, 0, len(dataSources))
+		for _, ds := range dataSources {
+			result := dataSourceHealth{
+				UID:   ds.UID,
+				Name:  ds.Name,
+				Type:  ds.Type,
+				OrgID: ds.OrgID,
+			}
+			if ds.URL != "" {
+				// the URL is kept as it helps diagnosing network issues, but without credentials
+				result.URL = setting.RedactedValue("url", ds.URL)
+			}
+
+			plugin, exists := pluginStore.Plugin(ctx, ds.Type)
+			switch {
+			case !exists:
+				result.Status = "plugin not installed"
+			case !plugin.Backend:
+				result.Status = "no backend"
+			default:
+				result.Status, result.Message = checkDataSourceHealth(ctx, ds, pluginContextProvider, pluginClient)
+			}
+			logger.Debug("Checked data source health", "uid", ds.UID, "status", result.Status)
+
+			results = append(results, result)
+		}
+
+		data, err := json.MarshalIndent(results, "", " ")
+		if err != nil {
+			return nil, err
+		}
+
+		return &supportbundles.SupportItem{
+			Filename:  "datasources-health.json",
+			FileBytes: data,
+		}, nil
+	}
+
+	return supportbundles.Collector{
+		UID:               "datasources-health",
+		DisplayName:       "Data source health",
+		Description:       "Health check results for all data sources",
+		IncludedByDefault: false,
+		Default:           false,
+		Fn:                collectorFn,
+	}
+}
+
+func checkDataSourceHealth(ctx context.Context, ds *datasources.DataSource, pluginContextProvider *plugincontext.Provider,
+	pluginClient plugins.Client) (string, string) {
+	ctx, cancel := context.WithTimeout(ctx, dataSourceHealthCheckTimeout)
+	defer cancel()
+
+	usr := ac.BackgroundUser("support_bundle", ds.OrgID, org.RoleAdmin, nil)
+	pCtx, err := pluginContextProvider.GetWithDataSource(ctx, ds.Type, usr, ds)
+	if err != nil {
+		return "unable to get plugin context", err.Error()
+	}
+
+	resp, err := pluginClient.CheckHealth(ctx, &backend.CheckHealthRequest{
+		PluginContext: pCtx,
+		Headers:       map[string]string{},
+	})
+	if err != nil {
+		return backend.HealthStatusError.String(), err.Error()
+	}
+
+	return resp.Status.String(), resp.Message
+}
@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"context"
 	"fmt"
+	"time"
 
 	"github.com/grafana/grafana/pkg/infra/db"
 	"github.com/grafana/grafana/pkg/services/sqlstore/migrator"
@@ -44,11 +45,14 @@ func dbCollector(sql db.DB) supportbundles.Collector {
 			bWriter.WriteString("version: " + v + "  \n")
 		}
 
+		writeConnectionInfo(ctx, sql, bWriter)
+		writeTableSizes(ctx, sql, bWriter)
+
 		errD := sql.WithDbSession(ctx, func(sess *db.Session) error {
 			return sess.Table("migration_log").Find(&logItems)
 		})
 		if errD != nil {
-			return nil, err
+			return nil, errD
 		}
 
 		bWriter.WriteString("\n## Migration Log\n\n")
@@ -66,10 +70,83 @@ func dbCollector(sql db.DB) supportbundles.Collector {
 
 	return supportbundles.Collector{
 		UID:               "db",
-		Description:       "Database information and migration log",
+		Description:       "Database information, health, table sizes and migration log",
 		DisplayName:       "Database and migration information",
 		IncludedByDefault: false,
 		Default:           true,
 		Fn:                collectorFn,
 	}
 }
+
+// writeConnectionInfo writes the result of a ping to the database and the connection pool statistics.
+func writeConnectionInfo(ctx context.Context, sql db.DB, bWriter *bytes.Buffer) {
+	bWriter.WriteString("\n## Connection\n\n")
+
+	err := sql.WithDbSession(ctx, func(sess *db.Session) error {
+		start := time.Now()
+		if err := sess.PingContext(ctx); err != nil {
+			return err
+		}
+		bWriter.WriteString(fmt.Sprintf("ping: %s  \n", time.Since(start)))
+
+		stats := sess.DB().Stats()
+		bWriter.WriteString(fmt.Sprintf("maxOpenConnections: %d  \nopenConnections: %d  \ninUse: %d  \nidle: %d  \n",
+			stats.MaxOpenConnections, stats.OpenConnections, stats.InUse, stats.Idle))
+		bWriter.WriteString(fmt.Sprintf("waitCount: %d  \nwaitDuration: %s  \nmaxIdleClosed: %d  \nmaxLifetimeClosed: %d  \n",
+			stats.WaitCount, stats.WaitDuration, stats.MaxIdleClosed, stats.MaxLifetimeClosed))
+		return nil
+	})
+	if err != nil {
+		bWriter.WriteString(fmt.Sprintf("Unable to reach the database  \nErr: %s  \n", err))
+	}
+}
+
+type tableSize struct {
+	Name     string `xorm:"name"`
+	RowCount int64  `xorm:"row_count"`
+	Size     int64  `xorm:"size"`
+}
+
+// writeTableSizes writes the number of rows of every table and, for databases
+// exposing it, the size on disk of the table including its indices.
+func writeTableSizes(ctx context.Context, sql db.DB, bWriter *bytes.Buffer) {
+	bWriter.WriteString("\n## Table sizes\n\n")
+
+	sizes := make([]tableSize, 0)
+	err := sql.WithDbSession(ctx, func(sess *db.Session) error {
+		switch string(sql.GetDBType()) {
+		case migrator.MySQL:
+			return sess.SQL("SELECT table_name AS name, table_rows AS row_count, data_length + index_length AS size " +
+				"FROM information_schema.tables WHERE table_schema = DATABASE() ORDER BY table_name").Find(&sizes)
+		case migrator.Postgres:
+			return sess.SQL("SELECT relname AS name, n_live_tup AS row_count, pg_total_relation_size(relid) AS size " +
+				"FROM pg_stat_user_tables ORDER BY relname").Find(&sizes)
+		case migrator.SQLite:
+			if err := sess.SQL("SELECT name FROM sqlite_master WHERE type = 'table' ORDER BY name").Find(&sizes); err != nil {
+				return err
+			}
+			// SQLite doesn't keep statistics, the rows are counted instead
+			for i := range sizes {
+				if _, err := sess.SQL("SELECT COUNT(*) FROM " + sql.Quote(sizes[i].Name)).Get(&sizes[i].RowCount); err != nil {
+					return err
+				}
+			}
+			return nil
+		default:
+			return fmt.Errorf("unsupported dbType: %s", sql.GetDBType())
+		}
+	})
+	if err != nil {
+		bWriter.WriteString(fmt.Sprintf("Unable to retrieve table sizes  \nErr: %s  \n", err))
+		return
+	}
+
+	bWriter.WriteString("| table | rows | size (bytes) |\n| --- | --- | --- |\n")
+	for _, t := range sizes {
+		size := "-"
+		if t.Size > 0 {
+			size = fmt.Sprintf("%d", t.Size)
+		}
+		bWriter.WriteString(fmt.Sprintf("| %s | %d | %s |\n", t.Name, t.RowCount, size))
+	}
+}
@@ -0,0 +1,31 @@
+package supportbundlesimpl
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/infra/db"
+)
+
+func TestIntegrationDBCollector(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+
+	store := db.InitTestDB(t)
+
+	item, err := dbCollector(store).Fn(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, "db.md", item.Filename)
+
+	content := string(item.FileBytes)
+	assert.Contains(t, content, "## Connection")
+	assert.Contains(t, content, "ping: ")
+	assert.NotContains(t, content, "Unable to")
+	assert.Contains(t, content, "## Table sizes")
+	assert.Contains(t, content, "| migration_log | ")
+	assert.Contains(t, content, "## Migration Log")
+}
@@ -11,9 +11,12 @@ import (
 	"github.com/grafana/grafana/pkg/infra/kvstore"
 	"github.com/grafana/grafana/pkg/infra/log"
 	"github.com/grafana/grafana/pkg/infra/usagestats"
+	"github.com/grafana/grafana/pkg/plugins"
 	ac "github.com/grafana/grafana/pkg/services/accesscontrol"
 	"github.com/grafana/grafana/pkg/services/auth/identity"
+	"github.com/grafana/grafana/pkg/services/datasources"
 	"github.com/grafana/grafana/pkg/services/featuremgmt"
+	"github.com/grafana/grafana/pkg/services/pluginsintegration/plugincontext"
 	"github.com/grafana/grafana/pkg/services/pluginsintegration/pluginsettings"
 	"github.com/grafana/grafana/pkg/services/pluginsintegration/pluginstore"
 	"github.com/grafana/grafana/pkg/services/supportbundles"
@@ -47,9 +50,12 @@ func ProvideService(
 	accesscontrolService ac.Service,
 	bundleRegistry *bundleregistry.Service,
 	cfg *setting.Cfg,
+	dataSourceService datasources.DataSourceService,
 	features *featuremgmt.FeatureManager,
 	httpServer *grafanaApi.HTTPServer,
 	kvStore kvstore.KVStore,
+	pluginClient plugins.Client,
+	pluginContextProvider *plugincontext.Provider,
 	pluginSettings pluginsettings.Service,
 	pluginStore pluginstore.Store,
 	routeRegister routing.RouteRegister,
@@ -88,6 +94,8 @@ func ProvideService(
 	s.bundleRegistry.RegisterSupportItemCollector(settingsCollector(settings))
 	s.bundleRegistry.RegisterSupportItemCollector(dbCollector(sql))
 	s.bundleRegistry.RegisterSupportItemCollector(pluginInfoCollector(pluginStore, pluginSettings, s.log))
+	s.bundleRegistry.RegisterSupportItemCollector(dataSourceHealthCollector(dataSourceService, pluginStore, pluginContextProvider, pluginClient, s.log))
+	s.bundleRegistry.RegisterSupportItemCollector(recentErrorsCollector())
 
 	return s, nil
 }
@@ -0,0 +1,111 @@
+package setting
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"gopkg.in/ini.v1"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+)
+
+const defaultSecretCacheTTL = 5 * time.Minute
+
+// SecretStore resolves secret references from an external secret store.
+type SecretStore interface {
+	// GetSecret returns the secret for reference, which is the argument of the
+	// expander, for example `path/to/secret:key` in `$__vaultkv{path/to/secret:key}`.
+	GetSecret(ctx context.Context, reference string) (string, error)
+}
+
+// SecretStoreFactory creates a SecretStore from its `[secret_store.<name>]` config section.
+// It returns a nil SecretStore if the store isn't configured.
+type SecretStoreFactory func(section *ini.Section) (SecretStore, error)
+
+// AddSecretStore registers an expander named name that resolves `$__<name>{<reference>}`
+// using the SecretStore created by factory. Resolved secrets are cached for the duration set
+// by `cache_ttl` in the config section of the store and fetched again once expired.
+func AddSecretStore(name string, priority int64, factory SecretStoreFactory) {
+	AddExpander(name, priority, newSecretStoreExpander(name, factory))
+}
+
+type cachedSecret struct {
+	value   string
+	fetched time.Time
+}
+
+type secretStoreExpander struct {
+	name    string
+	factory SecretStoreFactory
+	log     log.Logger
+	now     func() time.Time
+
+	mu       sync.Mutex
+	store    SecretStore
+	cacheTTL time.Duration
+	cache    map[string]cachedSecret
+}
+
+func newSecretStoreExpander(name string, factory SecretStoreFactory) *secretStoreExpander {
+	return &secretStoreExpander{
+		name:    name,
+		factory: factory,
+		log:     log.New("settings.secret_store"),
+		now:     time.Now,
+		cache:   map[string]cachedSecret{},
+	}
+}
+
+func (e *secretStoreExpander) SetupExpander(file *ini.File) error {
+	section := file.Section("secret_store." + e.name)
+	store, err := e.factory(section)
+	if err != nil {
+		return err
+	}
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.store = store
+	e.cacheTTL = section.Key("cache_ttl").MustDuration(defaultSecretCacheTTL)
+	e.cache = map[string]cachedSecret{}
+	return nil
+}
+
+// Expand returns the cached secret for reference while it's fresh and fetches it from
+// the store otherwise. If the store can't be reached, a previously fetched value is used.
+// The lock is not held while the secret is fetched, so a slow store doesn't block the
+// expansion of cached secrets.
+func (e *secretStoreExpander) Expand(reference string) (string, error) {
+	e.mu.Lock()
+	store, cacheTTL, cache := e.store, e.cacheTTL, e.cache
+	cached, ok := cache[reference]
+	e.mu.Unlock()
+
+	if store == nil {
+		return "", fmt.Errorf("secret store %q is not configured, configure it in the [secret_store.%s] section", e.name, e.name)
+	}
+
+	if ok && e.now().Sub(cached.fetched) < cacheTTL {
+		return cached.value, nil
+	}
+
+	value, err := store.GetSecret(context.Background(), reference)
+	if err != nil {
+		if ok {
+			e.log.Warn("Failed to refresh secret, using previously fetched value", "store", e.name, "reference", reference, "error", err)
+			return cached.value, nil
+		}
+		return "", err
+	}
+
+	if cacheTTL > 0 {
+		// if the store was set up again while the secret was fetched, the value ends up
+		// in the cache of the previous store, which is no longer used
+		e.mu.Lock()
+		cache[reference] = cachedSecret{value: value, fetched: e.now()}
+		e.mu.Unlock()
+	}
+	return value, nil
+}
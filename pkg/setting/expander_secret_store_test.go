@@ -0,0 +1,191 @@
+package setting
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/ini.v1"
+)
+
+func TestVaultKVExpander(t *testing.T) {
+	var requests int32
+	password := "s3cr3t"
+	available := true
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&requests, 1)
+		if !available {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		if r.Header.Get("X-Vault-Token") != "root" {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+
+		switch r.URL.Path {
+		case "/v1/secret/data/grafana/smtp":
+			_ = json.NewEncoder(w).Encode(map[string]any{
+				"data": map[string]any{
+					"data":     map[string]any{"password": password, "port": 587},
+					"metadata": map[string]any{"version": 1},
+				},
+			})
+		case "/v1/kv/grafana/smtp":
+			_ = json.NewEncoder(w).Encode(map[string]any{
+				"data": map[string]any{"password": "v1-" + password},
+			})
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	t.Cleanup(server.Close)
+
+	setup := func(t *testing.T, cfg string) *secretStoreExpander {
+		t.Helper()
+		file, err := ini.Load([]byte(cfg))
+		require.NoError(t, err)
+		e := newSecretStoreExpander("vaultkv", newVaultKVStore)
+		require.NoError(t, e.SetupExpander(file))
+		return e
+	}
+
+	t.Run("reads secrets from kv version 2", func(t *testing.T) {
+		e := setup(t, "[secret_store.vaultkv]\nurl = "+server.URL+"\ntoken = root\n")
+
+		got, err := e.Expand("grafana/smtp:password")
+		require.NoError(t, err)
+		assert.Equal(t, "s3cr3t", got)
+
+		got, err = e.Expand("grafana/smtp:port")
+		require.NoError(t, err)
+		assert.Equal(t, "587", got)
+
+		_, err = e.Expand("grafana/smtp:missing")
+		require.Error(t, err)
+
+		_, err = e.Expand("grafana/unknown:password")
+		require.Error(t, err)
+
+		_, err = e.Expand("no-key")
+		require.Error(t, err)
+	})
+
+	t.Run("reads secrets from kv version 1", func(t *testing.T) {
+		e := setup(t, "[secret_store.vaultkv]\nurl = "+server.URL+"\ntoken = root\nmount = kv\nkv_version = 1\n")
+
+		got, err := e.Expand("grafana/smtp:password")
+		require.NoError(t, err)
+		assert.Equal(t, "v1-s3cr3t", got)
+	})
+
+	t.Run("caches secrets and refreshes them once expired", func(t *testing.T) {
+		e := setup(t, "[secret_store.vaultkv]\nurl = "+server.URL+"\ntoken = root\ncache_ttl = 1m\n")
+		now := time.Now()
+		e.now = func() time.Time { return now }
+
+		atomic.StoreInt32(&requests, 0)
+		for i := 0; i < 3; i++ {
+			got, err := e.Expand("grafana/smtp:password")
+			require.NoError(t, err)
+			assert.Equal(t, "s3cr3t", got)
+		}
+		assert.Equal(t, int32(1), atomic.LoadInt32(&requests))
+
+		password = "rotated"
+		t.Cleanup(func() { password = "s3cr3t" })
+		now = now.Add(2 * time.Minute)
+		got, err := e.Expand("grafana/smtp:password")
+		require.NoError(t, err)
+		assert.Equal(t, "rotated", got)
+		assert.Equal(t, int32(2), atomic.LoadInt32(&requests))
+
+		// the last known value is used while the store is unavailable
+		available = false
+		t.Cleanup(func() { available = true })
+		now = now.Add(2 * time.Minute)
+		got, err = e.Expand("grafana/smtp:password")
+		require.NoError(t, err)
+		assert.Equal(t, "rotated", got)
+	})
+
+	t.Run("fails when the store is not configured", func(t *testing.T) {
+		e := setup(t, "")
+		_, err := e.Expand("grafana/smtp:password")
+		require.Error(t, err)
+	})
+
+	t.Run("rejects unsupported kv versions", func(t *testing.T) {
+		file, err := ini.Load([]byte("[secret_store.vaultkv]\nurl = " + server.URL + "\nkv_version = 3\n"))
+		require.NoError(t, err)
+		require.Error(t, newSecretStoreExpander("vaultkv", newVaultKVStore).SetupExpander(file))
+	})
+}
+
+type blockingSecretStore struct {
+	blocked chan struct{}
+	release chan struct{}
+}
+
+func (s *blockingSecretStore) GetSecret(_ context.Context, reference string) (string, error) {
+	if reference == "slow" {
+		close(s.blocked)
+		<-s.release
+	}
+	return reference + "-value", nil
+}
+
+func TestSecretStoreExpanderDoesNotBlockWhileFetching(t *testing.T) {
+	store := &blockingSecretStore{blocked: make(chan struct{}), release: make(chan struct{})}
+	e := newSecretStoreExpander("test", func(*ini.Section) (SecretStore, error) { return store, nil })
+	require.NoError(t, e.SetupExpander(ini.Empty()))
+
+	got, err := e.Expand("cached")
+	require.NoError(t, err)
+	assert.Equal(t, "cached-value", got)
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		got, err := e.Expand("slow")
+		assert.NoError(t, err)
+		assert.Equal(t, "slow-value", got)
+	}()
+	<-store.blocked
+
+	// the cached secret is returned while the other one is being fetched
+	got, err = e.Expand("cached")
+	require.NoError(t, err)
+	assert.Equal(t, "cached-value", got)
+
+	close(store.release)
+	<-done
+}
+
+func TestExpandConfig_SecretStore(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewEncoder(w).Encode(map[string]any{
+			"data": map[string]any{"data": map[string]any{"password": "from-vault"}},
+		})
+	}))
+	t.Cleanup(server.Close)
+
+	t.Setenv("GF_TEST_VAULT_URL", server.URL)
+	file, err := ini.Load([]byte("[secret_store.vaultkv]\nurl = $__env{GF_TEST_VAULT_URL}\n\n[smtp]\npassword = $__vaultkv{grafana/smtp:password}\n"))
+	require.NoError(t, err)
+
+	require.NoError(t, expandConfig(file))
+	assert.Equal(t, "from-vault", file.Section("smtp").Key("password").String())
+
+	// values expanded after the config was loaded, like provisioning files, use the same store
+	got, err := ExpandVar("$__vaultkv{grafana/smtp:password}")
+	require.NoError(t, err)
+	assert.Equal(t, "from-vault", got)
+}
@@ -32,6 +32,11 @@ var expanders = []registeredExpander{
 		priority: -5,
 		expander: fileExpander{},
 	},
+	{
+		name:     "vaultkv",
+		priority: 0,
+		expander: newSecretStoreExpander("vaultkv", newVaultKVStore),
+	},
 }
 
 func AddExpander(name string, priority int64, e Expander) {
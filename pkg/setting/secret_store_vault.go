@@ -0,0 +1,116 @@
+package setting
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"gopkg.in/ini.v1"
+)
+
+// vaultKVStore reads secrets from the KV secrets engine of a HashiCorp Vault compatible HTTP API.
+type vaultKVStore struct {
+	client    *http.Client
+	url       string
+	token     string
+	namespace string
+	mount     string
+	kvVersion int
+}
+
+func newVaultKVStore(section *ini.Section) (SecretStore, error) {
+	addr := valueAsString(section, "url", "")
+	if addr == "" {
+		return nil, nil
+	}
+	if _, err := url.Parse(addr); err != nil {
+		return nil, fmt.Errorf("invalid vault url: %w", err)
+	}
+
+	kvVersion := section.Key("kv_version").MustInt(2)
+	if kvVersion != 1 && kvVersion != 2 {
+		return nil, fmt.Errorf("unsupported vault kv_version %d, expected 1 or 2", kvVersion)
+	}
+
+	return &vaultKVStore{
+		client:    &http.Client{Timeout: section.Key("timeout").MustDuration(10 * time.Second)},
+		url:       strings.TrimSuffix(addr, "/"),
+		token:     valueAsString(section, "token", ""),
+		namespace: valueAsString(section, "namespace", ""),
+		mount:     strings.Trim(valueAsString(section, "mount", "secret"), "/"),
+		kvVersion: kvVersion,
+	}, nil
+}
+
+// GetSecret resolves references of the form `<path>:<key>`, where path is relative to the mount.
+func (s *vaultKVStore) GetSecret(ctx context.Context, reference string) (string, error) {
+	idx := strings.LastIndex(reference, ":")
+	if idx <= 0 || idx == len(reference)-1 {
+		return "", fmt.Errorf("invalid vault reference %q, expected <path>:<key>", reference)
+	}
+	path, key := strings.Trim(reference[:idx], "/"), reference[idx+1:]
+
+	endpoint := fmt.Sprintf("%s/v1/%s/%s", s.url, s.mount, path)
+	if s.kvVersion == 2 {
+		endpoint = fmt.Sprintf("%s/v1/%s/data/%s", s.url, s.mount, path)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return "", err
+	}
+	if s.token != "" {
+		req.Header.Set("X-Vault-Token", s.token)
+	}
+	if s.namespace != "" {
+		req.Header.Set("X-Vault-Namespace", s.namespace)
+	}
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("failed to read secret %q from vault: %w", path, err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return "", fmt.Errorf("failed to read secret %q from vault: status %d: %s", path, resp.StatusCode, strings.TrimSpace(string(body)))
+	}
+
+	var payload struct {
+		Data json.RawMessage `json:"data"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&payload); err != nil {
+		return "", fmt.Errorf("failed to decode secret %q from vault: %w", path, err)
+	}
+
+	data := payload.Data
+	if s.kvVersion == 2 {
+		var versioned struct {
+			Data json.RawMessage `json:"data"`
+		}
+		if err := json.Unmarshal(data, &versioned); err != nil {
+			return "", fmt.Errorf("failed to decode secret %q from vault: %w", path, err)
+		}
+		data = versioned.Data
+	}
+
+	var values map[string]any
+	if err := json.Unmarshal(data, &values); err != nil {
+		return "", fmt.Errorf("failed to decode secret %q from vault: %w", path, err)
+	}
+
+	value, ok := values[key]
+	if !ok {
+		return "", fmt.Errorf("secret %q has no key %q", path, key)
+	}
+	if str, ok := value.(string); ok {
+		return str, nil
+	}
+	return fmt.Sprint(value), nil
+}
@@ -207,6 +207,9 @@ type Cfg struct {
 	RendererAuthToken              string
 	RendererConcurrentRequestLimit int
 	RendererRenderKeyLifeTime      time.Duration
+	RendererQueueEnabled           bool
+	RendererQueueMaxSize           int
+	RendererQueueTimeout           time.Duration
 
 	// Security
 	DisableInitAdminCreation          bool
@@ -524,6 +527,8 @@ type Cfg struct {
 
 	Search SearchSettings
 
+	Provisioning ProvisioningSettings
+
 	SecureSocksDSProxy SecureSocksDSProxySettings
 
 	// SAML Auth
@@ -1190,6 +1195,7 @@ func (cfg *Cfg) Load(args CommandLineArgs) error {
 
 	cfg.Storage = readStorageSettings(iniFile)
 	cfg.Search = readSearchSettings(iniFile)
+	cfg.Provisioning = readProvisioningSettings(iniFile)
 
 	cfg.SecureSocksDSProxy, err = readSecureSocksDSProxySettings(iniFile)
 	if err != nil {
@@ -1225,10 +1231,16 @@ func (cfg *Cfg) Load(args CommandLineArgs) error {
 	encryption := cacheServer.Key("encryption").MustBool(false)
 
 	cfg.RemoteCacheOptions = &RemoteCacheOptions{
-		Name:       dbName,
-		ConnStr:    connStr,
-		Prefix:     prefix,
-		Encryption: encryption,
+		Name:                     dbName,
+		ConnStr:                  connStr,
+		Prefix:                   prefix,
+		Encryption:               encryption,
+		LocalCacheEnabled:        cacheServer.Key("local_cache_enabled").MustBool(false),
+		LocalCacheMaxItems:       cacheServer.Key("local_cache_max_items").MustInt(10000),
+		LocalCacheTTL:            cacheServer.Key("local_cache_ttl").MustDuration(time.Minute),
+		Invalidation:             valueAsString(cacheServer, "invalidation", ""),
+		InvalidationPollInterval: cacheServer.Key("invalidation_poll_interval").MustDuration(5 * time.Second),
+		InvalidationRetention:    cacheServer.Key("invalidation_retention").MustDuration(10 * time.Minute),
 	}
 
 	geomapSection := iniFile.Section("geomap")
@@ -1273,6 +1285,16 @@ type RemoteCacheOptions struct {
 	ConnStr    string
 	Prefix     string
 	Encryption bool
+
+	// LocalCacheEnabled keeps recently used items in an in-process cache in front of the remote cache
+	LocalCacheEnabled  bool
+	LocalCacheMaxItems int
+	LocalCacheTTL      time.Duration
+	// Invalidation is how the instances tell each other to drop the items they changed
+	// from their local cache: "redis", "database" or "none". Empty picks one based on Name.
+	Invalidation             string
+	InvalidationPollInterval time.Duration
+	InvalidationRetention    time.Duration
 }
 
 func (cfg *Cfg) readSAMLConfig() {
@@ -1756,6 +1778,9 @@ func (cfg *Cfg) readRenderingSettings(iniFile *ini.File) error {
 
 	cfg.RendererConcurrentRequestLimit = renderSec.Key("concurrent_render_request_limit").MustInt(30)
 	cfg.RendererRenderKeyLifeTime = renderSec.Key("render_key_lifetime").MustDuration(5 * time.Minute)
+	cfg.RendererQueueEnabled = renderSec.Key("queue_enabled").MustBool(false)
+	cfg.RendererQueueMaxSize = renderSec.Key("queue_max_size").MustInt(100)
+	cfg.RendererQueueTimeout = renderSec.Key("queue_timeout").MustDuration(30 * time.Second)
 	cfg.ImagesDir = filepath.Join(cfg.DataPath, "png")
 	cfg.CSVsDir = filepath.Join(cfg.DataPath, "csv")
 
@@ -0,0 +1,23 @@
+package setting
+
+import (
+	"time"
+
+	"gopkg.in/ini.v1"
+)
+
+type ProvisioningSettings struct {
+	// Watch enables watching the provisioning directories for changes instead of polling them.
+	Watch bool
+	// WatchDebounce is the time changes are collected for before they are applied.
+	WatchDebounce time.Duration
+}
+
+func readProvisioningSettings(iniFile *ini.File) ProvisioningSettings {
+	s := ProvisioningSettings{}
+
+	provisioningSection := iniFile.Section("provisioning")
+	s.Watch = provisioningSection.Key("watch").MustBool(false)
+	s.WatchDebounce = provisioningSection.Key("watch_debounce").MustDuration(time.Second)
+	return s
+}
@@ -22,6 +22,7 @@ func TestSessionSettings(t *testing.T) {
 		err := cfg.Load(CommandLineArgs{
 			HomePath: homePath,
 			Config:   filepath.Join(homePath, "pkg/setting/testdata/session.ini"),
+			Args:     testPathArgs(t),
 		})
 		require.Nil(t, err)
 
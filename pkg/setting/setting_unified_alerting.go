@@ -21,6 +21,8 @@ const (
 	alertmanagerDefaultPushPullInterval   = cluster.DefaultPushPullInterval
 	alertmanagerDefaultConfigPollInterval = time.Minute
 	alertmanagerRedisDefaultMaxConns      = 5
+	evaluationDefaultHeartbeatInterval    = 10 * time.Second
+	evaluationDefaultHeartbeatTimeout     = time.Minute
 	// To start, the alertmanager needs at least one route defined.
 	// TODO: we should move this to Grafana settings and define this as the default.
 	alertmanagerDefaultConfiguration = `{
@@ -61,6 +63,16 @@ const (
 	// DefaultRuleEvaluationInterval indicates a default interval of for how long a rule should be evaluated to change state from Pending to Alerting
 	DefaultRuleEvaluationInterval = SchedulerBaseInterval * 6 // == 60 seconds
 	stateHistoryDefaultEnabled    = true
+	recordingRulesDefaultTimeout  = 10 * time.Second
+
+	notificationHistoryDefaultMaxPayloadSize = 64 * 1024
+)
+
+const (
+	// StateStoreInstances stores each alert instance in a row of the alert_instance table.
+	StateStoreInstances = "instances"
+	// StateStoreCompressed stores the alert instances of each rule in a single compressed row of the alert_rule_state table.
+	StateStoreCompressed = "compressed"
 )
 
 type UnifiedAlertingSettings struct {
@@ -80,6 +92,10 @@ type UnifiedAlertingSettings struct {
 	HARedisPassword                string
 	HARedisDB                      int
 	HARedisMaxConns                int
+	HAEvaluationSharding           bool // determines whether the evaluation of rule groups is sharded between the replicas of a HA cluster.
+	HAReplicaID                    string
+	HAHeartbeatInterval            time.Duration
+	HAHeartbeatTimeout             time.Duration
 	MaxAttempts                    int64
 	MinInterval                    time.Duration
 	EvaluationTimeout              time.Duration
@@ -96,8 +112,12 @@ type UnifiedAlertingSettings struct {
 	ReservedLabels                UnifiedAlertingReservedLabelSettings
 	StateHistory                  UnifiedAlertingStateHistorySettings
 	RemoteAlertmanager            RemoteAlertmanagerSettings
+	RecordingRules                RecordingRuleSettings
+	NotificationHistory           NotificationHistorySettings
 	// MaxStateSaveConcurrency controls the number of goroutines (per rule) that can save alert state in parallel.
 	MaxStateSaveConcurrency int
+	// StateStore is where the alert states are persisted, either StateStoreInstances or StateStoreCompressed.
+	StateStore string
 }
 
 // RemoteAlertmanagerSettings contains the configuration needed
@@ -109,6 +129,25 @@ type RemoteAlertmanagerSettings struct {
 	Password string
 }
 
+// RecordingRuleSettings contains the configuration of the Prometheus
+// remote write endpoint the results of recording rules are written to.
+type RecordingRuleSettings struct {
+	Enabled           bool
+	URL               string
+	BasicAuthUsername string
+	BasicAuthPassword string
+	TenantID          string
+	Timeout           time.Duration
+}
+
+// NotificationHistorySettings contains the configuration of the history
+// of the notifications sent by the Grafana Alertmanager.
+type NotificationHistorySettings struct {
+	Enabled bool
+	// MaxPayloadSize is the maximum number of bytes of the rendered payload stored for each notification.
+	MaxPayloadSize int
+}
+
 type UnifiedAlertingScreenshotSettings struct {
 	Capture                    bool
 	CaptureTimeout             time.Duration
@@ -262,6 +301,20 @@ func (cfg *Cfg) ReadUnifiedAlertingSettings(iniFile *ini.File) error {
 		}
 	}
 
+	uaCfg.HAEvaluationSharding = ua.Key("ha_evaluation_sharding").MustBool(false)
+	uaCfg.HAReplicaID = ua.Key("ha_replica_id").MustString("")
+	uaCfg.HAHeartbeatInterval, err = gtime.ParseDuration(valueAsString(ua, "ha_heartbeat_interval", evaluationDefaultHeartbeatInterval.String()))
+	if err != nil {
+		return err
+	}
+	uaCfg.HAHeartbeatTimeout, err = gtime.ParseDuration(valueAsString(ua, "ha_heartbeat_timeout", evaluationDefaultHeartbeatTimeout.String()))
+	if err != nil {
+		return err
+	}
+	if uaCfg.HAEvaluationSharding && uaCfg.HAHeartbeatTimeout <= uaCfg.HAHeartbeatInterval {
+		return errors.New("value of setting 'ha_heartbeat_timeout' should be greater than 'ha_heartbeat_interval'")
+	}
+
 	// TODO load from ini file
 	uaCfg.DefaultConfiguration = alertmanagerDefaultConfiguration
 
@@ -397,8 +450,37 @@ func (cfg *Cfg) ReadUnifiedAlertingSettings(iniFile *ini.File) error {
 	}
 	uaCfg.StateHistory = uaCfgStateHistory
 
+	recordingRules := iniFile.Section("unified_alerting.recording_rules")
+	// the keys missing in the section are inherited from [unified_alerting], which has its own enabled key
+	recordingRulesEnabled, _ := strconv.ParseBool(recordingRules.KeysHash()["enabled"])
+	uaCfgRecordingRules := RecordingRuleSettings{
+		Enabled:           recordingRulesEnabled,
+		URL:               recordingRules.Key("url").MustString(""),
+		BasicAuthUsername: recordingRules.Key("basic_auth_username").MustString(""),
+		BasicAuthPassword: recordingRules.Key("basic_auth_password").MustString(""),
+		TenantID:          recordingRules.Key("tenant_id").MustString(""),
+		Timeout:           recordingRules.Key("timeout").MustDuration(recordingRulesDefaultTimeout),
+	}
+	if uaCfgRecordingRules.Enabled && uaCfgRecordingRules.URL == "" {
+		return errors.New("setting 'url' in section 'unified_alerting.recording_rules' is required when recording rules are enabled")
+	}
+	uaCfg.RecordingRules = uaCfgRecordingRules
+
+	notificationHistory := iniFile.Section("unified_alerting.notification_history")
+	// the keys missing in the section are inherited from [unified_alerting], which has its own enabled key
+	notificationHistoryEnabled, _ := strconv.ParseBool(notificationHistory.KeysHash()["enabled"])
+	uaCfg.NotificationHistory = NotificationHistorySettings{
+		Enabled:        notificationHistoryEnabled,
+		MaxPayloadSize: notificationHistory.Key("max_payload_size").MustInt(notificationHistoryDefaultMaxPayloadSize),
+	}
+
 	uaCfg.MaxStateSaveConcurrency = ua.Key("max_state_save_concurrency").MustInt(1)
 
+	uaCfg.StateStore = valueAsString(ua, "state_store", StateStoreInstances)
+	if uaCfg.StateStore != StateStoreInstances && uaCfg.StateStore != StateStoreCompressed {
+		return fmt.Errorf("setting 'state_store' in section 'unified_alerting' is invalid, must be %q or %q", StateStoreInstances, StateStoreCompressed)
+	}
+
 	cfg.UnifiedAlerting = uaCfg
 	return nil
 }
@@ -12,7 +12,7 @@ import (
 
 func TestCfg_ReadUnifiedAlertingSettings(t *testing.T) {
 	cfg := NewCfg()
-	err := cfg.Load(CommandLineArgs{HomePath: "../../", Config: "../../conf/defaults.ini"})
+	err := cfg.Load(CommandLineArgs{HomePath: "../../", Config: "../../conf/defaults.ini", Args: testPathArgs(t)})
 	require.NoError(t, err)
 
 	// It sets the correct defaults.
@@ -299,3 +299,114 @@ func TestMinInterval(t *testing.T) {
 		})
 	}
 }
+
+func TestRecordingRulesSettings(t *testing.T) {
+	t.Run("should be disabled by default", func(t *testing.T) {
+		f := ini.Empty()
+		section, err := f.NewSection("unified_alerting")
+		require.NoError(t, err)
+		_, err = section.NewKey("enabled", "true")
+		require.NoError(t, err)
+
+		cfg := NewCfg()
+		cfg.IsFeatureToggleEnabled = func(key string) bool { return false }
+		require.NoError(t, cfg.ReadUnifiedAlertingSettings(f))
+		require.False(t, cfg.UnifiedAlerting.RecordingRules.Enabled)
+		require.Equal(t, 10*time.Second, cfg.UnifiedAlerting.RecordingRules.Timeout)
+	})
+
+	t.Run("should read the remote write endpoint", func(t *testing.T) {
+		f := ini.Empty()
+		section, err := f.NewSection("unified_alerting.recording_rules")
+		require.NoError(t, err)
+		_, err = section.NewKey("enabled", "true")
+		require.NoError(t, err)
+		_, err = section.NewKey("url", "http://localhost:9090/api/v1/write")
+		require.NoError(t, err)
+		_, err = section.NewKey("tenant_id", "tenant")
+		require.NoError(t, err)
+		_, err = section.NewKey("timeout", "30s")
+		require.NoError(t, err)
+
+		cfg := NewCfg()
+		cfg.IsFeatureToggleEnabled = func(key string) bool { return false }
+		require.NoError(t, cfg.ReadUnifiedAlertingSettings(f))
+		require.Equal(t, RecordingRuleSettings{
+			Enabled:  true,
+			URL:      "http://localhost:9090/api/v1/write",
+			TenantID: "tenant",
+			Timeout:  30 * time.Second,
+		}, cfg.UnifiedAlerting.RecordingRules)
+	})
+
+	t.Run("should fail if enabled without url", func(t *testing.T) {
+		f := ini.Empty()
+		section, err := f.NewSection("unified_alerting.recording_rules")
+		require.NoError(t, err)
+		_, err = section.NewKey("enabled", "true")
+		require.NoError(t, err)
+
+		cfg := NewCfg()
+		cfg.IsFeatureToggleEnabled = func(key string) bool { return false }
+		require.Error(t, cfg.ReadUnifiedAlertingSettings(f))
+	})
+}
+
+func TestHAEvaluationShardingSettings(t *testing.T) {
+	t.Run("should be disabled by default", func(t *testing.T) {
+		cfg := NewCfg()
+		cfg.IsFeatureToggleEnabled = func(key string) bool { return false }
+		require.NoError(t, cfg.ReadUnifiedAlertingSettings(ini.Empty()))
+		require.False(t, cfg.UnifiedAlerting.HAEvaluationSharding)
+		require.Equal(t, 10*time.Second, cfg.UnifiedAlerting.HAHeartbeatInterval)
+		require.Equal(t, time.Minute, cfg.UnifiedAlerting.HAHeartbeatTimeout)
+	})
+
+	t.Run("should fail if the heartbeat timeout is not greater than the interval", func(t *testing.T) {
+		f := ini.Empty()
+		section, err := f.NewSection("unified_alerting")
+		require.NoError(t, err)
+		_, err = section.NewKey("ha_evaluation_sharding", "true")
+		require.NoError(t, err)
+		_, err = section.NewKey("ha_heartbeat_interval", "1m")
+		require.NoError(t, err)
+
+		cfg := NewCfg()
+		cfg.IsFeatureToggleEnabled = func(key string) bool { return false }
+		require.Error(t, cfg.ReadUnifiedAlertingSettings(f))
+	})
+}
+
+func TestStateStoreSettings(t *testing.T) {
+	t.Run("should store alert instances by default", func(t *testing.T) {
+		cfg := NewCfg()
+		cfg.IsFeatureToggleEnabled = func(key string) bool { return false }
+		require.NoError(t, cfg.ReadUnifiedAlertingSettings(ini.Empty()))
+		require.Equal(t, StateStoreInstances, cfg.UnifiedAlerting.StateStore)
+	})
+
+	t.Run("should read the compressed state store", func(t *testing.T) {
+		f := ini.Empty()
+		section, err := f.NewSection("unified_alerting")
+		require.NoError(t, err)
+		_, err = section.NewKey("state_store", "compressed")
+		require.NoError(t, err)
+
+		cfg := NewCfg()
+		cfg.IsFeatureToggleEnabled = func(key string) bool { return false }
+		require.NoError(t, cfg.ReadUnifiedAlertingSettings(f))
+		require.Equal(t, StateStoreCompressed, cfg.UnifiedAlerting.StateStore)
+	})
+
+	t.Run("should fail if the state store is unknown", func(t *testing.T) {
+		f := ini.Empty()
+		section, err := f.NewSection("unified_alerting")
+		require.NoError(t, err)
+		_, err = section.NewKey("state_store", "redis")
+		require.NoError(t, err)
+
+		cfg := NewCfg()
+		cfg.IsFeatureToggleEnabled = func(key string) bool { return false }
+		require.Error(t, cfg.ReadUnifiedAlertingSettings(f))
+	})
+}